@@ -0,0 +1,284 @@
+package gomes
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// defaultRedriveIdleTimeout ends the redrives triggered through the admin API
+// when no dead letter is received for this duration.
+const defaultRedriveIdleTimeout = 5 * time.Second
+
+// adminTopology is the response of the admin topology endpoint.
+type adminTopology struct {
+	Endpoints []EndpointInfo `json:"endpoints"`
+	Channels  []string       `json:"channels"`
+	Handlers  []string       `json:"handlers"`
+}
+
+// adminRedriveRequest is the body of the admin redrive endpoint.
+type adminRedriveRequest struct {
+	Target               string   `json:"target"`
+	Routes               []string `json:"routes"`
+	ErrorContains        string   `json:"errorContains"`
+	MaxMessagesPerSecond int      `json:"maxMessagesPerSecond"`
+	IdleTimeout          string   `json:"idleTimeout"`
+}
+
+// AdminOption configures the admin API.
+type AdminOption func(options *adminOptions)
+
+// adminOptions holds the settings of the admin API.
+type adminOptions struct {
+	middlewares []func(http.Handler) http.Handler
+}
+
+// WithAdminMiddleware wraps the admin API with middlewares, e.g. the
+// authentication of the application. The first middleware is the outermost.
+//
+// Parameters:
+//   - middlewares: the HTTP middlewares
+//
+// Returns:
+//   - AdminOption: the admin option
+func WithAdminMiddleware(middlewares ...func(http.Handler) http.Handler) AdminOption {
+	return func(options *adminOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+// WithAdminBearerToken requires the requests to the admin API to carry the
+// token in the Authorization header ("Bearer <token>"), rejecting the other
+// requests with 401 Unauthorized.
+//
+// Parameters:
+//   - token: the expected bearer token
+//
+// Returns:
+//   - AdminOption: the admin option
+func WithAdminBearerToken(token string) AdminOption {
+	expected := []byte("Bearer " + token)
+	return WithAdminMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(given, expected) != 1 {
+				writeAdminError(w, http.StatusUnauthorized, errors.New("[admin] unauthorized"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// ServeAdmin starts the embedded admin HTTP server on addr, serving the
+// AdminHandler endpoints in background. The returned server may be stopped
+// with its Shutdown method.
+//
+// The admin API pauses consumers and redrives dead letters, so an address
+// without host, e.g. ":9090", listens on the loopback interface only. Expose
+// it explicitly, e.g. "0.0.0.0:9090", together with an authentication option
+// such as WithAdminBearerToken.
+//
+// Parameters:
+//   - addr: the TCP address to listen on, e.g. ":9090"
+//   - opts: the admin options, e.g. the authentication middleware
+//
+// Returns:
+//   - *http.Server: the running admin server
+//   - error: error if the address cannot be listened on
+func ServeAdmin(addr string, opts ...AdminOption) (*http.Server, error) {
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort("localhost", port)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("[admin] cannot listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           AdminHandler(opts...),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("[admin] server stopped with error", "reason", err.Error())
+		}
+	}()
+	slog.Info("[admin] server started", "addr", listener.Addr().String())
+	return server, nil
+}
+
+// AdminHandler returns the admin API, to be served by ServeAdmin or mounted
+// in the HTTP server of the application:
+//   - GET /endpoints: active endpoints, channels and handlers
+//   - POST /consumers/{name}/pause and /consumers/{name}/resume
+//   - GET /metrics: processing statistics of the consumers
+//   - GET /errors: last processing error of each consumer
+//   - POST /dead-letters/{channel}/redrive: redrives a dead letter channel
+//
+// The API has no authentication of its own: mount it behind the
+// authentication of the application or set it with WithAdminMiddleware.
+//
+// Parameters:
+//   - opts: the admin options, e.g. the authentication middleware
+//
+// Returns:
+//   - http.Handler: the admin API handler
+func AdminHandler(opts ...AdminOption) http.Handler {
+	options := adminOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /endpoints", adminListEndpoints)
+	mux.HandleFunc("POST /consumers/{name}/pause", adminPauseConsumer)
+	mux.HandleFunc("POST /consumers/{name}/resume", adminResumeConsumer)
+	mux.HandleFunc("GET /metrics", adminConsumerMetrics)
+	mux.HandleFunc("GET /errors", adminConsumerErrors)
+	mux.HandleFunc("POST /dead-letters/{channel}/redrive", adminRedriveDeadLetter)
+
+	var handler http.Handler = mux
+	for _, middleware := range slices.Backward(options.middlewares) {
+		handler = middleware(handler)
+	}
+	return handler
+}
+
+// adminListEndpoints lists the active endpoints, channels and handlers.
+func adminListEndpoints(w http.ResponseWriter, _ *http.Request) {
+	topology := adminTopology{
+		Endpoints: ActiveEndpoints(),
+		Channels:  slices.Sorted(maps.Keys(outboundChannelBuilders.GetAll())),
+		Handlers: append(
+			slices.Sorted(maps.Keys(actionHandlers.GetAll())),
+			slices.Sorted(maps.Keys(eventSubscribers.GetAll()))...,
+		),
+	}
+	topology.Channels = append(
+		topology.Channels,
+		slices.Sorted(maps.Keys(inboundChannelBuilders.GetAll()))...,
+	)
+
+	writeAdminJSON(w, http.StatusOK, topology)
+}
+
+// adminPauseConsumer pauses a consumer.
+func adminPauseConsumer(w http.ResponseWriter, r *http.Request) {
+	if err := PauseConsumer(r.PathValue("name")); err != nil {
+		writeAdminError(w, http.StatusNotFound, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]string{"status": "paused"})
+}
+
+// adminResumeConsumer resumes a consumer.
+func adminResumeConsumer(w http.ResponseWriter, r *http.Request) {
+	if err := ResumeConsumer(r.PathValue("name")); err != nil {
+		writeAdminError(w, http.StatusNotFound, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]string{"status": "resumed"})
+}
+
+// adminConsumerMetrics returns the processing statistics of the consumers.
+func adminConsumerMetrics(w http.ResponseWriter, _ *http.Request) {
+	writeAdminJSON(w, http.StatusOK, consumerStats())
+}
+
+// adminConsumerErrors returns the last processing error of each consumer.
+func adminConsumerErrors(w http.ResponseWriter, _ *http.Request) {
+	lastErrors := map[string]endpoint.ConsumerStats{}
+	for name, stats := range consumerStats() {
+		if stats.LastError != "" {
+			lastErrors[name] = endpoint.ConsumerStats{
+				Failed:      stats.Failed,
+				LastError:   stats.LastError,
+				LastErrorAt: stats.LastErrorAt,
+			}
+		}
+	}
+	writeAdminJSON(w, http.StatusOK, lastErrors)
+}
+
+// adminRedriveDeadLetter starts the redrive of a dead letter channel.
+func adminRedriveDeadLetter(w http.ResponseWriter, r *http.Request) {
+	request := adminRedriveRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("[admin] invalid body: %w", err))
+		return
+	}
+	if request.Target == "" {
+		writeAdminError(w, http.StatusBadRequest, errors.New("[admin] target is required"))
+		return
+	}
+	idleTimeout := defaultRedriveIdleTimeout
+	if request.IdleTimeout != "" {
+		var err error
+		if idleTimeout, err = time.ParseDuration(request.IdleTimeout); err != nil {
+			writeAdminError(w, http.StatusBadRequest, fmt.Errorf("[admin] invalid idle timeout: %w", err))
+			return
+		}
+	}
+
+	sourceChannel := r.PathValue("channel")
+	redriver, err := RedriveDeadLetter(sourceChannel, request.Target, endpoint.RedriveOptions{
+		Routes:               request.Routes,
+		ErrorContains:        request.ErrorContains,
+		MaxMessagesPerSecond: request.MaxMessagesPerSecond,
+		IdleTimeout:          idleTimeout,
+	})
+	if err != nil {
+		writeAdminError(w, http.StatusConflict, err)
+		return
+	}
+
+	go func() {
+		defer activeEndpoints.Remove(sourceChannel)
+		if err := redriver.Run(context.Background()); err != nil {
+			slog.Error("[admin] dead letter redrive failed",
+				"source", sourceChannel,
+				"reason", err.Error(),
+			)
+		}
+	}()
+	writeAdminJSON(w, http.StatusAccepted, map[string]string{"status": "started"})
+}
+
+// consumerStats returns the processing statistics of the active consumers.
+func consumerStats() map[string]endpoint.ConsumerStats {
+	stats := map[string]endpoint.ConsumerStats{}
+	for name, activeEndpoint := range activeEndpoints.GetAll() {
+		if consumer, ok := activeEndpoint.(*endpoint.EventDrivenConsumer); ok {
+			stats[name] = consumer.Stats()
+		}
+	}
+	return stats
+}
+
+// writeAdminJSON writes a JSON response.
+func writeAdminJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("[admin] failed to write response", "reason", err.Error())
+	}
+}
+
+// writeAdminError writes a JSON error response.
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	writeAdminJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,85 @@
+package gomes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes"
+)
+
+func TestAdminHandler(t *testing.T) {
+	handler := gomes.AdminHandler()
+
+	t.Run("should list the endpoints", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/endpoints", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", recorder.Code)
+		}
+		body := map[string]any{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("unexpected body: %v", err)
+		}
+		for _, key := range []string{"endpoints", "channels", "handlers"} {
+			if _, ok := body[key]; !ok {
+				t.Errorf("expected %s in response, got %s", key, recorder.Body.String())
+			}
+		}
+	})
+
+	t.Run("should return not found when pausing a missing consumer", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/consumers/admin.missing/pause", nil))
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("should reject a redrive without target", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/dead-letters/admin.dlq/redrive", http.NoBody)
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", recorder.Code)
+		}
+	})
+}
+
+func TestAdminHandler_BearerToken(t *testing.T) {
+	handler := gomes.AdminHandler(gomes.WithAdminBearerToken("secret"))
+
+	t.Run("should reject requests without the token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/endpoints", nil))
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("should serve requests with the token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/endpoints", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", recorder.Code)
+		}
+	})
+}
+
+func TestServeAdmin_ListensOnLoopbackByDefault(t *testing.T) {
+	server, err := gomes.ServeAdmin(":0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	host, _, _ := net.SplitHostPort(server.Addr)
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		t.Errorf("expected admin server on the loopback interface, got %s", server.Addr)
+	}
+}
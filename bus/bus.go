@@ -2,8 +2,21 @@ package bus
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// middlewares holds the bus-level middlewares applied to every bus created
+// after their registration.
+var (
+	middlewares   []Middleware
+	middlewaresMu sync.RWMutex
 )
 
 type Dispatcher interface {
@@ -23,3 +36,162 @@ type Dispatcher interface {
 		headers map[string]string,
 	) *message.MessageBuilder
 }
+
+// orphanReplyCounter is implemented by dispatchers counting the replies which
+// arrived after their request finished, e.g. by a reply timeout.
+type orphanReplyCounter interface {
+	OrphanReplies() int64
+}
+
+// orphanReplies returns the number of replies which arrived after their
+// request finished, or zero if the dispatcher does not count them.
+func orphanReplies(dispatcher Dispatcher) int64 {
+	if counter, ok := dispatcher.(orphanReplyCounter); ok {
+		return counter.OrphanReplies()
+	}
+	return 0
+}
+
+// Middleware wraps a dispatcher with cross-cutting behavior such as claims
+// injection, payload validation, logging or metrics. Implementations usually
+// embed the next dispatcher and override only the methods they need.
+type Middleware func(next Dispatcher) Dispatcher
+
+// Use registers bus-level middlewares applied to every CommandBus, QueryBus
+// and EventBus created afterwards. Middlewares registered first are the
+// outermost ones, so they run first on every Send/SendAsync/Publish.
+//
+// Parameters:
+//   - middleware: the middlewares to be registered
+func Use(middleware ...Middleware) {
+	middlewaresMu.Lock()
+	defer middlewaresMu.Unlock()
+	for _, m := range middleware {
+		if m != nil {
+			middlewares = append(middlewares, m)
+		}
+	}
+}
+
+// applyMiddlewares wraps the dispatcher with the registered middlewares.
+//
+// Parameters:
+//   - dispatcher: the dispatcher to be wrapped
+//
+// Returns:
+//   - Dispatcher: the wrapped dispatcher
+func applyMiddlewares(dispatcher Dispatcher) Dispatcher {
+	middlewaresMu.RLock()
+	defer middlewaresMu.RUnlock()
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		dispatcher = middlewares[i](dispatcher)
+	}
+	return dispatcher
+}
+
+// scopedHeaders propagates the continuity metadata of the message being
+// handled, available in the context, to the headers of an outgoing message.
+// The correlation id and tenant are inherited and the causation id is set to
+// the handled message id, unless they were explicitly provided.
+//
+// Parameters:
+//   - ctx: the context of the message being handled
+//   - headers: the outgoing message headers
+//
+// Returns:
+//   - map[string]string: the headers with continuity metadata
+func scopedHeaders(
+	ctx context.Context,
+	headers map[string]string,
+) map[string]string {
+	inbound, ok := message.HeaderFromContext(ctx)
+	if !ok {
+		return headers
+	}
+
+	scoped := make(map[string]string, len(headers)+3)
+	maps.Copy(scoped, headers)
+
+	inherited := map[string]string{
+		message.HeaderCorrelationId: inbound.Get(message.HeaderCorrelationId),
+		message.HeaderCausationId:   inbound.Get(message.HeaderMessageId),
+		message.HeaderTenantId:      inbound.Get(message.HeaderTenantId),
+	}
+	for key, value := range inherited {
+		if scoped[key] == "" && value != "" {
+			scoped[key] = value
+		}
+	}
+	return scoped
+}
+
+// sendWithTimeout sends a message synchronously, waiting for its reply at
+// most the timeout. The timeout travels in the replyTimeout header, so the
+// reply consumer releases the request reply channel as soon as it expires.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//   - dispatcher: the bus dispatcher
+//   - msg: the message to be sent
+//   - timeout: the maximum time to wait for the reply
+//
+// Returns:
+//   - any: the response from message processing
+//   - error: error if sending or processing fails, wrapping
+//     handler.ErrReplyTimeout when the timeout expires
+func sendWithTimeout(
+	ctx context.Context,
+	dispatcher Dispatcher,
+	msg *message.Message,
+	timeout time.Duration,
+) (any, error) {
+	if timeout <= 0 {
+		return dispatcher.SendMessage(ctx, msg)
+	}
+
+	msg.GetHeader().Set(message.HeaderReplyTimeout, timeout.String())
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := dispatcher.SendMessage(timeoutCtx, msg)
+	if err != nil && ctx.Err() == nil && timeoutCtx.Err() != nil &&
+		!errors.Is(err, handler.ErrReplyTimeout) {
+		return nil, fmt.Errorf(
+			"%w after %v, correlationId %s",
+			handler.ErrReplyTimeout,
+			timeout,
+			msg.GetHeader().Get(message.HeaderCorrelationId),
+		)
+	}
+	return result, err
+}
+
+// publishBatch publishes the messages through the dispatcher collecting
+// them in a batch, then writes the batch in a single call per publisher
+// channel.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - dispatcher: the dispatcher publishing the messages
+//   - msgs: the messages to be published
+//
+// Returns:
+//   - error: *message.BatchError with the failed messages by position
+func publishBatch(
+	ctx context.Context,
+	dispatcher Dispatcher,
+	msgs []*message.Message,
+) error {
+	batch := message.NewBatch()
+	failures := map[int]error{}
+	for i, msg := range msgs {
+		if err := dispatcher.PublishMessage(batch.Context(ctx, i), msg); err != nil {
+			failures[i] = err
+		}
+	}
+	maps.Copy(failures, batch.Flush(ctx))
+	if len(failures) > 0 {
+		return &message.BatchError{Errors: failures}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package bus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/bus"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+type headerMiddleware struct {
+	bus.Dispatcher
+	value string
+}
+
+func (m *headerMiddleware) SendMessage(
+	ctx context.Context,
+	msg *message.Message,
+) (any, error) {
+	if msg.GetHeader().Get(message.HeaderRoute) == "middleware.route" {
+		msg.GetHeader().Set("X-Middleware", msg.GetHeader().Get("X-Middleware")+m.value)
+	}
+	return m.Dispatcher.SendMessage(ctx, msg)
+}
+
+func (m *headerMiddleware) PublishMessage(
+	ctx context.Context,
+	msg *message.Message,
+) error {
+	if msg.GetHeader().Get(message.HeaderRoute) == "middleware.route" {
+		msg.GetHeader().Set("X-Middleware", msg.GetHeader().Get("X-Middleware")+m.value)
+	}
+	return m.Dispatcher.PublishMessage(ctx, msg)
+}
+
+func TestUse(t *testing.T) {
+	bus.Use(
+		func(next bus.Dispatcher) bus.Dispatcher {
+			return &headerMiddleware{Dispatcher: next, value: "a"}
+		},
+		nil,
+		func(next bus.Dispatcher) bus.Dispatcher {
+			return &headerMiddleware{Dispatcher: next, value: "b"}
+		},
+	)
+
+	t.Run("should wrap command bus in registration order", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockDispatcher{}
+		commandBus := bus.NewCommandBus(dispatcher)
+		_, err := commandBus.SendRaw(context.Background(), "middleware.route", "payload", nil)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got := dispatcher.lastMsg.GetHeader().Get("X-Middleware"); got != "ab" {
+			t.Errorf("expected middleware header 'ab', got '%s'", got)
+		}
+	})
+
+	t.Run("should wrap event bus", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockEventDispatcher{}
+		eventBus := bus.NewEventBus(dispatcher)
+		err := eventBus.PublishRaw(context.Background(), "middleware.route", "payload", nil)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got := dispatcher.lastMsg.GetHeader().Get("X-Middleware"); got != "ab" {
+			t.Errorf("expected middleware header 'ab', got '%s'", got)
+		}
+	})
+}
+
+func TestScopedHeaders(t *testing.T) {
+	inbound := message.NewHeader(map[string]string{
+		message.HeaderCorrelationId: "corr-1",
+		message.HeaderTenantId:      "tenant-1",
+	})
+	ctx := message.ContextWithHeader(context.Background(), inbound)
+
+	t.Run("should propagate continuity metadata", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockDispatcher{}
+		commandBus := bus.NewCommandBus(dispatcher)
+		_, err := commandBus.Send(ctx, mockAction{name: "scoped"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		header := dispatcher.lastMsg.GetHeader()
+		if header.Get(message.HeaderCorrelationId) != "corr-1" {
+			t.Errorf("expected correlation id 'corr-1', got '%s'", header.Get(message.HeaderCorrelationId))
+		}
+		if header.Get(message.HeaderCausationId) != inbound.Get(message.HeaderMessageId) {
+			t.Errorf("expected causation id to be the inbound message id, got '%s'", header.Get(message.HeaderCausationId))
+		}
+		if header.Get(message.HeaderTenantId) != "tenant-1" {
+			t.Errorf("expected tenant 'tenant-1', got '%s'", header.Get(message.HeaderTenantId))
+		}
+	})
+
+	t.Run("should keep explicit headers", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockEventDispatcher{}
+		eventBus := bus.NewEventBus(dispatcher)
+		err := eventBus.PublishRaw(ctx, "scoped", "payload", map[string]string{
+			message.HeaderCorrelationId: "explicit",
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got := dispatcher.lastMsg.GetHeader().Get(message.HeaderCorrelationId); got != "explicit" {
+			t.Errorf("expected correlation id 'explicit', got '%s'", got)
+		}
+	})
+}
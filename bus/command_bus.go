@@ -7,13 +7,19 @@
 //
 // The CommandBus implementation supports:
 // - Synchronous command execution with response handling
+// - Per-call reply timeout
 // - Raw command execution with custom payload and headers
 // - Asynchronous command execution for fire-and-forget scenarios
+// - Scheduled and delayed asynchronous command execution
 // - Automatic correlation ID generation
+// - Bus-level middlewares registered through Use
+// - Per-bus OpenTelemetry dispatch spans, configured through options
+// - Tenant stamping of the dispatched commands through WithTenantResolver
 package bus
 
 import (
 	"context"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
@@ -22,19 +28,24 @@ import (
 // CommandBus provides command execution capabilities for action processing.
 type CommandBus struct {
 	dispatcher Dispatcher
+	source     Dispatcher
+	delayed    *delayedPublisher
 }
 
 // NewCommandBus creates a new command bus instance with the specified dispatcher.
 //
 // Parameters:
 //   - dispatcher: the message dispatcher to use for command execution
+//   - opts: the bus options, e.g. WithTracing
 //
 // Returns:
 //   - *CommandBus: new command bus instance
-func NewCommandBus(dispatcher Dispatcher) *CommandBus {
+func NewCommandBus(dispatcher Dispatcher, opts ...Option) *CommandBus {
 	commandBus := &CommandBus{
-		dispatcher: dispatcher,
+		dispatcher: newBusDispatcher("command", dispatcher, opts),
+		source:     dispatcher,
 	}
+	commandBus.delayed = newDelayedPublisher(commandBus.dispatcher, opts)
 	return commandBus
 }
 
@@ -51,13 +62,38 @@ func (c *CommandBus) Send(
 	ctx context.Context,
 	action handler.Action,
 ) (any, error) {
-	builder := c.dispatcher.MessageBuilder(message.Command, action, nil)
+	builder := c.dispatcher.MessageBuilder(message.Command, action, scopedHeaders(ctx, nil))
 	msg := builder.
 		WithRoute(action.Name()).
 		Build()
 	return c.dispatcher.SendMessage(ctx, msg)
 }
 
+// SendWithTimeout executes a command action synchronously, waiting for its
+// result at most the timeout. The reply subscription of the request is
+// released when the timeout expires.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//   - action: the command action to be executed
+//   - timeout: the maximum time to wait for the result
+//
+// Returns:
+//   - any: the command result
+//   - error: error if command execution fails, wrapping
+//     handler.ErrReplyTimeout when the timeout expires
+func (c *CommandBus) SendWithTimeout(
+	ctx context.Context,
+	action handler.Action,
+	timeout time.Duration,
+) (any, error) {
+	builder := c.dispatcher.MessageBuilder(message.Command, action, scopedHeaders(ctx, nil))
+	msg := builder.
+		WithRoute(action.Name()).
+		Build()
+	return sendWithTimeout(ctx, c.dispatcher, msg, timeout)
+}
+
 // SendRaw executes a raw command with custom payload and headers synchronously.
 //
 // Parameters:
@@ -75,7 +111,7 @@ func (c *CommandBus) SendRaw(
 	payload any,
 	headers map[string]string,
 ) (any, error) {
-	builder := c.dispatcher.MessageBuilder(message.Command, payload, headers)
+	builder := c.dispatcher.MessageBuilder(message.Command, payload, scopedHeaders(ctx, headers))
 	msg := builder.
 		WithRoute(route).
 		Build()
@@ -94,7 +130,7 @@ func (c *CommandBus) SendAsync(
 	ctx context.Context,
 	action handler.Action,
 ) error {
-	builder := c.dispatcher.MessageBuilder(message.Command, action, nil)
+	builder := c.dispatcher.MessageBuilder(message.Command, action, scopedHeaders(ctx, nil))
 	msg := builder.
 		WithRoute(action.Name()).
 		Build()
@@ -120,10 +156,96 @@ func (c *CommandBus) SendRawAsync(
 	builder := c.dispatcher.MessageBuilder(
 		message.Command,
 		payload,
-		headers,
+		scopedHeaders(ctx, headers),
 	)
 	msg := builder.WithPayload(payload).
 		WithRoute(route).
 		Build()
 	return c.dispatcher.PublishMessage(ctx, msg)
 }
+
+// SendRawBatch executes many raw commands of a route asynchronously. The
+// messages go through the publishing pipeline one by one and are written to
+// their channels in a single broker call per channel.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - route: the route for the commands
+//   - payloads: the command payloads
+//   - headers: custom headers for every command
+//
+// Returns:
+//   - error: *message.BatchError with the failed commands by position
+func (c *CommandBus) SendRawBatch(
+	ctx context.Context,
+	route string,
+	payloads []any,
+	headers map[string]string,
+) error {
+	msgs := make([]*message.Message, len(payloads))
+	for i, payload := range payloads {
+		builder := c.dispatcher.MessageBuilder(
+			message.Command,
+			payload,
+			scopedHeaders(ctx, headers),
+		)
+		msgs[i] = builder.
+			WithRoute(route).
+			Build()
+	}
+	return publishBatch(ctx, c.dispatcher, msgs)
+}
+
+// SendAsyncAt executes a command action asynchronously at the given time.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - action: the command action to be executed
+//   - at: when the command is published
+//
+// Returns:
+//   - error: error if the command cannot be scheduled
+func (c *CommandBus) SendAsyncAt(
+	ctx context.Context,
+	action handler.Action,
+	at time.Time,
+) error {
+	builder := c.dispatcher.MessageBuilder(message.Command, action, scopedHeaders(ctx, nil))
+	msg := builder.
+		WithRoute(action.Name()).
+		Build()
+	return c.delayed.publishAt(ctx, at, msg)
+}
+
+// SendAsyncAfter executes a command action asynchronously after the delay.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - action: the command action to be executed
+//   - delay: how long to wait before the command is published
+//
+// Returns:
+//   - error: error if the command cannot be scheduled
+func (c *CommandBus) SendAsyncAfter(
+	ctx context.Context,
+	action handler.Action,
+	delay time.Duration,
+) error {
+	return c.SendAsyncAt(ctx, action, time.Now().Add(delay))
+}
+
+// Stop stops the scheduler of the bus: the pending delayed commands are no
+// longer published by this process, and new ones are rejected. Those
+// persisted in a ScheduleStore are published by the next process using it.
+func (c *CommandBus) Stop() {
+	c.delayed.stop()
+}
+
+// OrphanReplies returns the number of replies to the commands sent by the bus
+// which arrived after their request finished, e.g. by a reply timeout.
+//
+// Returns:
+//   - int64: number of orphan replies
+func (c *CommandBus) OrphanReplies() int64 {
+	return orphanReplies(c.source)
+}
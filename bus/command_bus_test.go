@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/bus"
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
 
 type mockDispatcher struct {
@@ -181,3 +183,75 @@ func TestCommandBus_SendRawAsync(t *testing.T) {
 		}
 	})
 }
+
+// blockingDispatcher waits for the context to be done on synchronous sends.
+type blockingDispatcher struct {
+	mockDispatcher
+}
+
+func (b *blockingDispatcher) SendMessage(ctx context.Context, msg *message.Message) (any, error) {
+	b.lastMsg = msg
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCommandBus_SendRawBatch(t *testing.T) {
+	t.Parallel()
+	channel := &batchChannel{}
+	cb := bus.NewCommandBus(&batchDispatcher{channel: channel})
+	headers := map[string]string{"origin": "import"}
+
+	err := cb.SendRawBatch(context.Background(), "import.order", []any{"a", "b", "c"}, headers)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(channel.batches) != 1 || len(channel.batches[0]) != 3 {
+		t.Fatalf("expected a single batch of 3 commands, got %v", channel.batches)
+	}
+	for i, msg := range channel.batches[0] {
+		if msg.GetHeader().Get(message.HeaderRoute) != "import.order" ||
+			msg.GetHeader().Get("origin") != "import" {
+			t.Errorf("unexpected headers of command %d: %v", i, msg.GetHeader())
+		}
+	}
+	if payload := channel.batches[0][2].GetPayload(); payload != "c" {
+		t.Errorf("expected payload c, got %v", payload)
+	}
+}
+
+func TestCommandBus_SendWithTimeout(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &blockingDispatcher{}
+		cb := bus.NewCommandBus(dispatcher, bus.WithTracing(false))
+
+		_, err := cb.SendWithTimeout(context.Background(), mockAction{name: "Slow"}, 10*time.Millisecond)
+		if !errors.Is(err, handler.ErrReplyTimeout) {
+			t.Fatalf("expected reply timeout error, got %v", err)
+		}
+		if dispatcher.lastMsg.GetHeader().Get(message.HeaderReplyTimeout) != "10ms" {
+			t.Errorf("expected replyTimeout header, got %q", dispatcher.lastMsg.GetHeader().Get(message.HeaderReplyTimeout))
+		}
+	})
+
+	t.Run("caller cancellation", func(t *testing.T) {
+		t.Parallel()
+		cb := bus.NewCommandBus(&blockingDispatcher{}, bus.WithTracing(false))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := cb.SendWithTimeout(ctx, mockAction{name: "Slow"}, time.Second)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled error, got %v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		cb := bus.NewCommandBus(&mockDispatcher{returnAny: "ok"}, bus.WithTracing(false))
+		result, err := cb.SendWithTimeout(context.Background(), mockAction{name: "Fast"}, time.Second)
+		if err != nil || result != "ok" {
+			t.Fatalf("expected ok result, got %v (%v)", result, err)
+		}
+	})
+}
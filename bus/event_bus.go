@@ -10,10 +10,13 @@
 // - Raw message publishing with custom headers
 // - Automatic correlation ID generation
 // - Asynchronous event distribution
+// - Scheduled and delayed event publishing
+// - Tenant stamping of the published events through WithTenantResolver
 package bus
 
 import (
 	"context"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
@@ -23,20 +26,23 @@ import (
 // throughout the system.
 type EventBus struct {
 	dispatcher Dispatcher
+	delayed    *delayedPublisher
 }
 
 // NewEventBus creates a new event bus instance with the specified dispatcher.
 //
 // Parameters:
 //   - dispatcher: the message dispatcher to use for publishing events
+//   - opts: the bus options, e.g. WithTracing
 //
 // Returns:
 //   - *EventBus: new event bus instance
-func NewEventBus(dispatcher Dispatcher) *EventBus {
+func NewEventBus(dispatcher Dispatcher, opts ...Option) *EventBus {
 
 	eventBus := &EventBus{
-		dispatcher: dispatcher,
+		dispatcher: newBusDispatcher("event", dispatcher, opts),
 	}
+	eventBus.delayed = newDelayedPublisher(eventBus.dispatcher, opts)
 	return eventBus
 }
 
@@ -49,13 +55,34 @@ func NewEventBus(dispatcher Dispatcher) *EventBus {
 // Returns:
 //   - error: error if publishing fails
 func (c *EventBus) Publish(ctx context.Context, action handler.Action) error {
-	builder := c.dispatcher.MessageBuilder(message.Event, action, nil)
+	builder := c.dispatcher.MessageBuilder(message.Event, action, scopedHeaders(ctx, nil))
 	msg := builder.
 		WithRoute(action.Name()).
 		Build()
 	return c.dispatcher.PublishMessage(ctx, msg)
 }
 
+// PublishBatch publishes many event actions at once. The messages go through
+// the publishing pipeline one by one and are written to their channels in a
+// single broker call per channel, e.g. a single Kafka writer call.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - actions: the actions to be published as events
+//
+// Returns:
+//   - error: *message.BatchError with the failed events by position
+func (c *EventBus) PublishBatch(ctx context.Context, actions []handler.Action) error {
+	msgs := make([]*message.Message, len(actions))
+	for i, action := range actions {
+		builder := c.dispatcher.MessageBuilder(message.Event, action, scopedHeaders(ctx, nil))
+		msgs[i] = builder.
+			WithRoute(action.Name()).
+			Build()
+	}
+	return publishBatch(ctx, c.dispatcher, msgs)
+}
+
 // PublishRaw publishes a raw event message with custom payload and headers.
 //
 // Parameters:
@@ -72,9 +99,53 @@ func (c *EventBus) PublishRaw(
 	payload any,
 	headers map[string]string,
 ) error {
-	builder := c.dispatcher.MessageBuilder(message.Event, payload, headers)
+	builder := c.dispatcher.MessageBuilder(message.Event, payload, scopedHeaders(ctx, headers))
 	msg := builder.
 		WithRoute(route).
 		Build()
 	return c.dispatcher.PublishMessage(ctx, msg)
 }
+
+// PublishAt publishes an event action at the given time.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - action: the action to be published as an event
+//   - at: when the event is published
+//
+// Returns:
+//   - error: error if the event cannot be scheduled
+func (c *EventBus) PublishAt(
+	ctx context.Context,
+	action handler.Action,
+	at time.Time,
+) error {
+	msg := c.dispatcher.MessageBuilder(message.Event, action, scopedHeaders(ctx, nil)).
+		WithRoute(action.Name()).
+		Build()
+	return c.delayed.publishAt(ctx, at, msg)
+}
+
+// PublishAfter publishes an event action after the delay.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - action: the action to be published as an event
+//   - delay: how long to wait before the event is published
+//
+// Returns:
+//   - error: error if the event cannot be scheduled
+func (c *EventBus) PublishAfter(
+	ctx context.Context,
+	action handler.Action,
+	delay time.Duration,
+) error {
+	return c.PublishAt(ctx, action, time.Now().Add(delay))
+}
+
+// Stop stops the scheduler of the bus: the pending delayed events are no
+// longer published by this process, and new ones are rejected. Those
+// persisted in a ScheduleStore are published by the next process using it.
+func (c *EventBus) Stop() {
+	c.delayed.stop()
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jeffersonbrasilino/gomes/bus"
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
 
 type mockEventDispatcher struct {
@@ -108,3 +109,80 @@ func TestEventBus_PublishRaw(t *testing.T) {
 		}
 	})
 }
+
+// batchChannel records the batches written to it.
+type batchChannel struct {
+	batches [][]*message.Message
+}
+
+func (c *batchChannel) Name() string { return "batch" }
+
+func (c *batchChannel) Send(ctx context.Context, msg *message.Message) error {
+	return c.SendBatch(ctx, []*message.Message{msg})
+}
+
+func (c *batchChannel) SendBatch(ctx context.Context, msgs []*message.Message) error {
+	c.batches = append(c.batches, msgs)
+	return nil
+}
+
+// batchDispatcher publishes the messages to the channel, as the outbound
+// channel adapter does, failing the messages of failRoute.
+type batchDispatcher struct {
+	mockEventDispatcher
+	channel   *batchChannel
+	failRoute string
+}
+
+func (d *batchDispatcher) PublishMessage(ctx context.Context, msg *message.Message) error {
+	if msg.GetHeader().Get(message.HeaderRoute) == d.failRoute {
+		return errors.New("route not found")
+	}
+	if message.CollectInBatch(ctx, d.channel, msg) {
+		return nil
+	}
+	return d.channel.Send(ctx, msg)
+}
+
+func TestEventBus_PublishBatch(t *testing.T) {
+	t.Parallel()
+	t.Run("should collect the events in a single batch", func(t *testing.T) {
+		t.Parallel()
+		channel := &batchChannel{}
+		eb := bus.NewEventBus(&batchDispatcher{channel: channel})
+		actions := []handler.Action{mockEAction{name: "first"}, mockEAction{name: "second"}}
+
+		if err := eb.PublishBatch(context.Background(), actions); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(channel.batches) != 1 || len(channel.batches[0]) != 2 {
+			t.Fatalf("expected a single batch of 2 events, got %v", channel.batches)
+		}
+		if route := channel.batches[0][1].GetHeader().Get(message.HeaderRoute); route != "second" {
+			t.Errorf("expected second event in order, got %s", route)
+		}
+	})
+
+	t.Run("should report the failed events by position", func(t *testing.T) {
+		t.Parallel()
+		channel := &batchChannel{}
+		eb := bus.NewEventBus(&batchDispatcher{channel: channel, failRoute: "second"})
+		actions := []handler.Action{
+			mockEAction{name: "first"},
+			mockEAction{name: "second"},
+			mockEAction{name: "third"},
+		}
+
+		err := eb.PublishBatch(context.Background(), actions)
+		var batchErr *message.BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("expected batch error, got %v", err)
+		}
+		if len(batchErr.Errors) != 1 || batchErr.Errors[1] == nil {
+			t.Errorf("expected event 1 to fail, got %v", batchErr.Errors)
+		}
+		if len(channel.batches) != 1 || len(channel.batches[0]) != 2 {
+			t.Errorf("expected the other events to be published, got %v", channel.batches)
+		}
+	})
+}
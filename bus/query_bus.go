@@ -10,10 +10,13 @@
 // - Raw query execution with custom payload and headers
 // - Asynchronous query execution for fire-and-forget scenarios
 // - Automatic correlation ID generation
+// - Typed query results through the generic Query and QueryRaw helpers
 package bus
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
@@ -22,19 +25,22 @@ import (
 // QueryBus provides query execution capabilities for data retrieval operations.
 type QueryBus struct {
 	dispatcher Dispatcher
+	source     Dispatcher
 }
 
 // NewQueryBus creates a new query bus instance with the specified dispatcher.
 //
 // Parameters:
 //   - dispatcher: the message dispatcher to use for query execution
+//   - opts: the bus options, e.g. WithTracing
 //
 // Returns:
 //   - *QueryBus: new query bus instance
-func NewQueryBus(dispatcher Dispatcher) *QueryBus {
+func NewQueryBus(dispatcher Dispatcher, opts ...Option) *QueryBus {
 
 	queryBus := &QueryBus{
-		dispatcher: dispatcher,
+		dispatcher: newBusDispatcher("query", dispatcher, opts),
+		source:     dispatcher,
 	}
 	return queryBus
 }
@@ -52,7 +58,7 @@ func (c *QueryBus) Send(
 	ctx context.Context,
 	action handler.Action,
 ) (any, error) {
-	builder := c.dispatcher.MessageBuilder(message.Query, action, nil)
+	builder := c.dispatcher.MessageBuilder(message.Query, action, scopedHeaders(ctx, nil))
 	msg := builder.
 		WithRoute(action.Name()).
 		Build()
@@ -77,7 +83,7 @@ func (c *QueryBus) SendRaw(
 	payload any,
 	headers map[string]string,
 ) (any, error) {
-	builder := c.dispatcher.MessageBuilder(message.Query, payload, headers)
+	builder := c.dispatcher.MessageBuilder(message.Query, payload, scopedHeaders(ctx, headers))
 	msg := builder.
 		WithRoute(route).
 		Build()
@@ -96,7 +102,7 @@ func (c *QueryBus) SendAsync(
 	ctx context.Context,
 	action handler.Action,
 ) error {
-	builder := c.dispatcher.MessageBuilder(message.Query, action, nil)
+	builder := c.dispatcher.MessageBuilder(message.Query, action, scopedHeaders(ctx, nil))
 	msg := builder.
 		WithRoute(action.Name()).
 		Build()
@@ -119,9 +125,112 @@ func (c *QueryBus) SendRawAsync(
 	payload any,
 	headers map[string]string,
 ) error {
-	builder := c.dispatcher.MessageBuilder(message.Query, payload, headers)
+	builder := c.dispatcher.MessageBuilder(message.Query, payload, scopedHeaders(ctx, headers))
 	msg := builder.
 		WithRoute(route).
 		Build()
 	return c.dispatcher.PublishMessage(ctx, msg)
 }
+
+// Query executes a query action synchronously and converts the reply into the
+// requested result type.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - queryBus: the query bus used to dispatch the query
+//   - action: the query action to be executed
+//
+// Returns:
+//   - TResult: the typed query result
+//   - error: error if query execution or result conversion fails
+func Query[TResult any](
+	ctx context.Context,
+	queryBus *QueryBus,
+	action handler.Action,
+) (TResult, error) {
+	reply, err := queryBus.Send(ctx, action)
+	if err != nil {
+		var result TResult
+		return result, err
+	}
+	return convertQueryResult[TResult](reply)
+}
+
+// QueryRaw executes a raw query synchronously and converts the reply into the
+// requested result type.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - queryBus: the query bus used to dispatch the query
+//   - route: the route for the query
+//   - payload: the query payload
+//   - headers: custom headers for the query
+//
+// Returns:
+//   - TResult: the typed query result
+//   - error: error if query execution or result conversion fails
+func QueryRaw[TResult any](
+	ctx context.Context,
+	queryBus *QueryBus,
+	route string,
+	payload any,
+	headers map[string]string,
+) (TResult, error) {
+	reply, err := queryBus.SendRaw(ctx, route, payload, headers)
+	if err != nil {
+		var result TResult
+		return result, err
+	}
+	return convertQueryResult[TResult](reply)
+}
+
+// convertQueryResult converts a query reply into TResult. Replies produced by
+// in-process handlers are asserted directly, while replies coming from
+// external channels ([]byte) or with a different shape are decoded as JSON.
+//
+// Parameters:
+//   - reply: the raw reply returned by the dispatcher
+//
+// Returns:
+//   - TResult: the converted result
+//   - error: error if the reply cannot be converted
+func convertQueryResult[TResult any](reply any) (TResult, error) {
+	var result TResult
+	if reply == nil {
+		return result, nil
+	}
+
+	if typedResult, ok := reply.(TResult); ok {
+		return typedResult, nil
+	}
+
+	data, ok := reply.([]byte)
+	if !ok {
+		var err error
+		data, err = json.Marshal(reply)
+		if err != nil {
+			return result, fmt.Errorf(
+				"[query-bus] cannot convert reply of type %T to %T: %w: %w",
+				reply, result, message.ErrTranslation, err,
+			)
+		}
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf(
+			"[query-bus] cannot convert reply of type %T to %T: %w: %w",
+			reply, result, message.ErrTranslation, err,
+		)
+	}
+
+	return result, nil
+}
+
+// OrphanReplies returns the number of replies to the queries sent by the bus
+// which arrived after their request finished, e.g. by a reply timeout.
+//
+// Returns:
+//   - int64: number of orphan replies
+func (c *QueryBus) OrphanReplies() int64 {
+	return orphanReplies(c.source)
+}
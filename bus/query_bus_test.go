@@ -180,3 +180,135 @@ func TestQueryBus_SendRawAsync(t *testing.T) {
 		}
 	})
 }
+
+type queryResult struct {
+	Name string `json:"name"`
+}
+
+func TestQuery(t *testing.T) {
+	t.Run("should return typed result", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnAny: &queryResult{Name: "ok"}}
+		qb := bus.NewQueryBus(dispatcher)
+
+		result, err := bus.Query[*queryResult](context.Background(), qb, mockqAction{name: "q"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Name != "ok" {
+			t.Errorf("expected name 'ok', got %v", result.Name)
+		}
+	})
+
+	t.Run("should decode json bytes", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnAny: []byte(`{"name":"json"}`)}
+		qb := bus.NewQueryBus(dispatcher)
+
+		result, err := bus.Query[queryResult](context.Background(), qb, mockqAction{name: "q"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Name != "json" {
+			t.Errorf("expected name 'json', got %v", result.Name)
+		}
+	})
+
+	t.Run("should convert different shapes", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnAny: map[string]any{"name": "map"}}
+		qb := bus.NewQueryBus(dispatcher)
+
+		result, err := bus.Query[queryResult](context.Background(), qb, mockqAction{name: "q"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Name != "map" {
+			t.Errorf("expected name 'map', got %v", result.Name)
+		}
+	})
+
+	t.Run("should return zero value on nil reply", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{}
+		qb := bus.NewQueryBus(dispatcher)
+
+		result, err := bus.Query[*queryResult](context.Background(), qb, mockqAction{name: "q"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("expected nil result, got %v", result)
+		}
+	})
+
+	t.Run("should return error when reply is not convertible", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnAny: "text"}
+		qb := bus.NewQueryBus(dispatcher)
+
+		_, err := bus.Query[queryResult](context.Background(), qb, mockqAction{name: "q"})
+		if err == nil {
+			t.Error("expected conversion error, got nil")
+		}
+	})
+
+	t.Run("should propagate dispatcher error", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnErr: errors.New("fail")}
+		qb := bus.NewQueryBus(dispatcher)
+
+		_, err := bus.Query[queryResult](context.Background(), qb, mockqAction{name: "q"})
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestQueryRaw(t *testing.T) {
+	t.Run("should return typed result", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnAny: []byte(`{"name":"raw"}`)}
+		qb := bus.NewQueryBus(dispatcher)
+
+		result, err := bus.QueryRaw[queryResult](
+			context.Background(), qb, "route", "data", map[string]string{"x": "y"},
+		)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Name != "raw" {
+			t.Errorf("expected name 'raw', got %v", result.Name)
+		}
+	})
+
+	t.Run("should propagate dispatcher error", func(t *testing.T) {
+		t.Parallel()
+		dispatcher := &mockQDispatcher{returnErr: errors.New("fail")}
+		qb := bus.NewQueryBus(dispatcher)
+
+		_, err := bus.QueryRaw[queryResult](context.Background(), qb, "route", nil, nil)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+// countingDispatcher is a dispatcher counting orphan replies.
+type countingDispatcher struct {
+	mockQDispatcher
+}
+
+func (c *countingDispatcher) OrphanReplies() int64 {
+	return 3
+}
+
+func TestQueryBus_OrphanReplies(t *testing.T) {
+	t.Parallel()
+	if replies := bus.NewQueryBus(&countingDispatcher{}).OrphanReplies(); replies != 3 {
+		t.Errorf("expected the orphan replies of the dispatcher, got %d", replies)
+	}
+	if replies := bus.NewQueryBus(&mockQDispatcher{}).OrphanReplies(); replies != 0 {
+		t.Errorf("expected no orphan replies, got %d", replies)
+	}
+}
@@ -0,0 +1,299 @@
+// Package bus provides message bus implementations for the message system.
+//
+// This package implements various message bus types that provide high-level
+// abstractions for sending and receiving messages. It supports command/query
+// separation (CQRS) patterns and event-driven messaging with different bus
+// types for different use cases.
+//
+// The recording buses implementation supports:
+// - CommandBus, QueryBus and EventBus doubles for unit tests
+// - Capture of the dispatched messages with their payload and headers
+// - Stubbed replies of synchronous commands and queries by route
+// - Assertions over the dispatched messages through AssertPublished
+package bus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// TestingT is the subset of testing.TB used by the recorder assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Matcher reports whether a recorded message matches an assertion.
+type Matcher func(msg *message.Message) bool
+
+// RecordedMessage is a message dispatched through a recording bus.
+type RecordedMessage struct {
+	// Message is the dispatched message, with its payload and headers.
+	Message *message.Message
+	// Async reports whether the message was published without waiting for
+	// a reply.
+	Async bool
+}
+
+// stubbedReply is the reply of a synchronous dispatch to a route.
+type stubbedReply struct {
+	result any
+	err    error
+}
+
+// Recorder is a dispatcher capturing every dispatched message instead of
+// delivering it, used by the recording buses.
+type Recorder struct {
+	mu       sync.Mutex
+	messages []RecordedMessage
+	replies  map[string]stubbedReply
+}
+
+// RecordingCommandBus is a CommandBus recording the dispatched commands.
+type RecordingCommandBus struct {
+	*CommandBus
+	*Recorder
+}
+
+// RecordingQueryBus is a QueryBus recording the dispatched queries.
+type RecordingQueryBus struct {
+	*QueryBus
+	*Recorder
+}
+
+// RecordingEventBus is an EventBus recording the published events.
+type RecordingEventBus struct {
+	*EventBus
+	*Recorder
+}
+
+// NewRecorder creates a new recording dispatcher.
+//
+// Returns:
+//   - *Recorder: the recorder instance
+func NewRecorder() *Recorder {
+	return &Recorder{replies: map[string]stubbedReply{}}
+}
+
+// NewRecordingCommandBus creates a command bus recording the dispatched
+// commands, to be injected in the services under test in place of the
+// command bus of the message system.
+//
+// Parameters:
+//   - opts: the bus options, e.g. WithTracing
+//
+// Returns:
+//   - *RecordingCommandBus: the recording command bus
+func NewRecordingCommandBus(opts ...Option) *RecordingCommandBus {
+	recorder := NewRecorder()
+	return &RecordingCommandBus{NewCommandBus(recorder, opts...), recorder}
+}
+
+// NewRecordingQueryBus creates a query bus recording the dispatched queries.
+//
+// Parameters:
+//   - opts: the bus options, e.g. WithTracing
+//
+// Returns:
+//   - *RecordingQueryBus: the recording query bus
+func NewRecordingQueryBus(opts ...Option) *RecordingQueryBus {
+	recorder := NewRecorder()
+	return &RecordingQueryBus{NewQueryBus(recorder, opts...), recorder}
+}
+
+// NewRecordingEventBus creates an event bus recording the published events.
+//
+// Parameters:
+//   - opts: the bus options, e.g. WithTracing
+//
+// Returns:
+//   - *RecordingEventBus: the recording event bus
+func NewRecordingEventBus(opts ...Option) *RecordingEventBus {
+	recorder := NewRecorder()
+	return &RecordingEventBus{NewEventBus(recorder, opts...), recorder}
+}
+
+// StubReply sets the reply of the synchronous dispatches to a route. Routes
+// without a stubbed reply are answered with a nil result.
+//
+// Parameters:
+//   - route: the command or query route
+//   - result: the reply result
+//   - err: the reply error
+func (r *Recorder) StubReply(route string, result any, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.replies[route] = stubbedReply{result: result, err: err}
+}
+
+// SendMessage records a synchronous dispatch and returns its stubbed reply.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the dispatched message
+//
+// Returns:
+//   - any: the stubbed result of the message route
+//   - error: the stubbed error of the message route
+func (r *Recorder) SendMessage(ctx context.Context, msg *message.Message) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, RecordedMessage{Message: msg})
+	reply := r.replies[msg.GetHeader().Get(message.HeaderRoute)]
+	return reply.result, reply.err
+}
+
+// PublishMessage records an asynchronous dispatch.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the dispatched message
+//
+// Returns:
+//   - error: always nil
+func (r *Recorder) PublishMessage(ctx context.Context, msg *message.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, RecordedMessage{Message: msg, Async: true})
+	return nil
+}
+
+// MessageBuilder creates the builder of the dispatched messages, generating
+// the correlation id like the message dispatcher does.
+//
+// Parameters:
+//   - messageType: the type of the message
+//   - payload: the message payload
+//   - headers: the message headers
+//
+// Returns:
+//   - *message.MessageBuilder: the message builder
+func (r *Recorder) MessageBuilder(
+	messageType message.MessageType,
+	payload any,
+	headers map[string]string,
+) *message.MessageBuilder {
+	builder, _ := message.NewMessageBuilderFromHeaders(headers)
+	builder.WithMessageType(messageType)
+	builder.WithPayload(payload)
+	if headers[message.HeaderCorrelationId] == "" {
+		builder.WithCorrelationId(uuid.New().String())
+	}
+	return builder
+}
+
+// Messages returns the messages dispatched to a route, in dispatch order.
+// An empty route returns every dispatched message.
+//
+// Parameters:
+//   - route: the command, query or event route
+//
+// Returns:
+//   - []RecordedMessage: the recorded messages
+func (r *Recorder) Messages(route string) []RecordedMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recorded := []RecordedMessage{}
+	for _, msg := range r.messages {
+		if route == "" || msg.Message.GetHeader().Get(message.HeaderRoute) == route {
+			recorded = append(recorded, msg)
+		}
+	}
+	return recorded
+}
+
+// Reset discards the recorded messages, keeping the stubbed replies.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = nil
+}
+
+// AssertPublished fails the test unless a message matching every matcher
+// was dispatched to the route.
+//
+// Parameters:
+//   - t: the test
+//   - route: the command, query or event route
+//   - matchers: the matchers the message must satisfy
+//
+// Returns:
+//   - bool: true if a matching message was dispatched
+func (r *Recorder) AssertPublished(t TestingT, route string, matchers ...Matcher) bool {
+	t.Helper()
+	recorded := r.Messages(route)
+	for _, msg := range recorded {
+		if matchesAll(msg.Message, matchers) {
+			return true
+		}
+	}
+	t.Errorf(
+		"[recording-bus] expected a matching message on route %q, %d recorded",
+		route,
+		len(recorded),
+	)
+	return false
+}
+
+// AssertNotPublished fails the test if a message matching every matcher was
+// dispatched to the route.
+//
+// Parameters:
+//   - t: the test
+//   - route: the command, query or event route
+//   - matchers: the matchers the message must satisfy
+//
+// Returns:
+//   - bool: true if no matching message was dispatched
+func (r *Recorder) AssertNotPublished(t TestingT, route string, matchers ...Matcher) bool {
+	t.Helper()
+	for _, msg := range r.Messages(route) {
+		if matchesAll(msg.Message, matchers) {
+			t.Errorf("[recording-bus] unexpected message on route %q", route)
+			return false
+		}
+	}
+	return true
+}
+
+// HasHeader matches messages with the header set to the value.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the expected header value
+//
+// Returns:
+//   - Matcher: the header matcher
+func HasHeader(key string, value string) Matcher {
+	return func(msg *message.Message) bool {
+		return msg.GetHeader().Get(key) == value
+	}
+}
+
+// PayloadMatches matches messages whose payload is a T satisfying the
+// predicate.
+//
+// Parameters:
+//   - predicate: the payload predicate
+//
+// Returns:
+//   - Matcher: the payload matcher
+func PayloadMatches[T any](predicate func(payload T) bool) Matcher {
+	return func(msg *message.Message) bool {
+		payload, ok := msg.GetPayload().(T)
+		return ok && predicate(payload)
+	}
+}
+
+// matchesAll reports whether the message satisfies every matcher.
+func matchesAll(msg *message.Message, matchers []Matcher) bool {
+	for _, matcher := range matchers {
+		if matcher != nil && !matcher(msg) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/bus"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+type userCreated struct {
+	Username string
+}
+
+func (e *userCreated) Name() string { return "userCreated" }
+
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecordingEventBus(t *testing.T) {
+	t.Parallel()
+	eventBus := bus.NewRecordingEventBus(bus.WithTracing(false))
+	err := eventBus.PublishRaw(
+		context.Background(),
+		"userCreated",
+		&userCreated{Username: "jane"},
+		map[string]string{message.HeaderTenantId: "acme"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byUsername := bus.PayloadMatches(func(e *userCreated) bool { return e.Username == "jane" })
+	if !eventBus.AssertPublished(t, "userCreated", byUsername, bus.HasHeader(message.HeaderTenantId, "acme")) {
+		return
+	}
+	eventBus.AssertNotPublished(t, "userDeleted")
+
+	recorded := eventBus.Messages("userCreated")
+	if len(recorded) != 1 || !recorded[0].Async {
+		t.Errorf("expected 1 async message, got %+v", recorded)
+	}
+
+	failing := &fakeT{}
+	eventBus.AssertPublished(failing, "userCreated", bus.HasHeader(message.HeaderTenantId, "other"))
+	eventBus.AssertNotPublished(failing, "userCreated")
+	if len(failing.errors) != 2 {
+		t.Errorf("expected 2 assertion failures, got %v", failing.errors)
+	}
+
+	eventBus.Reset()
+	if len(eventBus.Messages("")) != 0 {
+		t.Error("expected no recorded messages after reset")
+	}
+}
+
+func TestRecordingCommandBus_StubReply(t *testing.T) {
+	t.Parallel()
+	commandBus := bus.NewRecordingCommandBus(bus.WithTracing(false))
+	stubErr := errors.New("user exists")
+	commandBus.StubReply("createUser", nil, stubErr)
+
+	_, err := commandBus.Send(context.Background(), mockAction{name: "createUser"})
+	if !errors.Is(err, stubErr) {
+		t.Errorf("expected stubbed error, got %v", err)
+	}
+
+	result, err := commandBus.Send(context.Background(), mockAction{name: "renameUser"})
+	if err != nil || result != nil {
+		t.Errorf("expected nil reply, got %v, %v", result, err)
+	}
+	commandBus.AssertPublished(t, "createUser", bus.PayloadMatches(func(a mockAction) bool {
+		return a.name == "createUser"
+	}))
+	if recorded := commandBus.Messages("createUser"); recorded[0].Async {
+		t.Error("expected a synchronous dispatch")
+	}
+}
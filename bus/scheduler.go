@@ -0,0 +1,508 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/internal/jsonfile"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// ScheduledMessage is a message waiting to be published at its due time.
+type ScheduledMessage struct {
+	Id      string            `json:"id"`
+	DueAt   time.Time         `json:"dueAt"`
+	Headers map[string]string `json:"headers"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
+// ScheduleStore defines the contract for stores persisting scheduled
+// messages, so they survive process restarts.
+type ScheduleStore interface {
+	// Save records a scheduled message.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - scheduled: The scheduled message
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	Save(ctx context.Context, scheduled ScheduledMessage) error
+	// Delete removes a scheduled message once it is published.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - id: The scheduled message id
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	Delete(ctx context.Context, id string) error
+	// Pending returns every scheduled message not yet published.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//
+	// Returns:
+	//   - []ScheduledMessage: The pending scheduled messages
+	//   - error: Error if the store operation fails
+	Pending(ctx context.Context) ([]ScheduledMessage, error)
+}
+
+// WithScheduler sets the scheduler publishing the delayed messages of the bus,
+// e.g. one persisting them in a ScheduleStore. By default each bus keeps its
+// delayed messages only in memory.
+//
+// Parameters:
+//   - scheduler: the bus scheduler
+//
+// Returns:
+//   - Option: the bus option
+func WithScheduler(scheduler *Scheduler) Option {
+	return func(o *options) {
+		o.scheduler = scheduler
+	}
+}
+
+// WithNativeDelay publishes delayed messages immediately with the scheduledAt
+// header, leaving the delay to the broker, e.g. a RabbitMQ delayed-message
+// exchange. Only use it with channels whose broker supports delivery delay.
+//
+// Returns:
+//   - Option: the bus option
+func WithNativeDelay() Option {
+	return func(o *options) {
+		o.nativeDelay = true
+	}
+}
+
+// delayedPublisher publishes the messages of a bus at a future time.
+type delayedPublisher struct {
+	dispatcher  Dispatcher
+	scheduler   *Scheduler
+	nativeDelay bool
+}
+
+// newDelayedPublisher creates the delayed publisher of a bus, binding its
+// scheduler to the bus dispatcher.
+func newDelayedPublisher(dispatcher Dispatcher, opts []Option) *delayedPublisher {
+	config := newOptions(opts)
+	publisher := &delayedPublisher{dispatcher: dispatcher, nativeDelay: config.nativeDelay}
+	if config.nativeDelay {
+		return publisher
+	}
+
+	publisher.scheduler = config.scheduler
+	if publisher.scheduler == nil {
+		publisher.scheduler = NewScheduler(nil)
+	}
+	publisher.scheduler.bind(dispatcher)
+	return publisher
+}
+
+// publishAt publishes the message at the due time.
+func (p *delayedPublisher) publishAt(
+	ctx context.Context,
+	dueAt time.Time,
+	msg *message.Message,
+) error {
+	if p.nativeDelay {
+		msg.GetHeader().Set(message.HeaderScheduledAt, dueAt.UTC().Format(time.RFC3339Nano))
+		return p.dispatcher.PublishMessage(ctx, msg)
+	}
+	return p.scheduler.Schedule(ctx, dueAt, msg)
+}
+
+// stop stops the scheduler of the publisher, if any.
+func (p *delayedPublisher) stop() {
+	if p.scheduler != nil {
+		p.scheduler.Stop()
+	}
+}
+
+// Scheduler publishes messages at their due time, keeping an in-memory timer
+// per message and, optionally, persisting them in a ScheduleStore. Messages
+// failing to publish are retried with an exponential backoff, up to the
+// maximum attempts when set. A scheduler belongs to one bus.
+type Scheduler struct {
+	store       ScheduleStore
+	dispatcher  Dispatcher
+	deadLetter  message.PublisherChannel
+	mu          sync.Mutex
+	timers      map[string]*time.Timer
+	attempts    map[string]int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxAttempts int
+	stopped     bool
+}
+
+// NewScheduler creates a new scheduler.
+//
+// Parameters:
+//   - store: optional store persisting the scheduled messages, nil keeps them
+//     only in memory
+//
+// Returns:
+//   - *Scheduler: configured scheduler
+func NewScheduler(store ScheduleStore) *Scheduler {
+	return &Scheduler{
+		store:      store,
+		timers:     map[string]*time.Timer{},
+		attempts:   map[string]int{},
+		minBackoff: time.Second,
+		maxBackoff: time.Minute,
+	}
+}
+
+// WithRetryBackoff sets the delays between the publish attempts of a due
+// message. Each failure doubles the delay, from minDelay up to maxDelay.
+// Default: 1s to 1m.
+//
+// Parameters:
+//   - minDelay: the delay after the first failure
+//   - maxDelay: the maximum delay between attempts
+//
+// Returns:
+//   - *Scheduler: scheduler for method chaining
+func (s *Scheduler) WithRetryBackoff(minDelay, maxDelay time.Duration) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if minDelay > 0 {
+		s.minBackoff = minDelay
+	}
+	s.maxBackoff = max(maxDelay, s.minBackoff)
+	return s
+}
+
+// WithMaxAttempts limits the publish attempts of a due message. A message
+// failing every attempt is sent to the dead letter channel with the
+// deadLetterError header and removed from the scheduler; without a dead
+// letter channel it is dropped and logged. While the dead letter channel
+// fails too, the message keeps being retried. Default: 0, retrying until the
+// message is published.
+//
+// Parameters:
+//   - attempts: the maximum publish attempts, 0 for unlimited
+//   - deadLetter: optional channel receiving the messages failing every
+//     attempt
+//
+// Returns:
+//   - *Scheduler: scheduler for method chaining
+func (s *Scheduler) WithMaxAttempts(
+	attempts int,
+	deadLetter message.PublisherChannel,
+) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxAttempts = max(attempts, 0)
+	s.deadLetter = deadLetter
+	return s
+}
+
+// bind sets the dispatcher publishing the due messages and schedules the
+// messages persisted by previous processes.
+func (s *Scheduler) bind(dispatcher Dispatcher) {
+	s.mu.Lock()
+	s.dispatcher = dispatcher
+	s.mu.Unlock()
+
+	if s.store == nil {
+		return
+	}
+	pending, err := s.store.Pending(context.Background())
+	if err != nil {
+		slog.Error("[scheduler] failed to load scheduled messages", "reason", err.Error())
+		return
+	}
+	for _, scheduled := range pending {
+		builder, err := message.NewMessageBuilderFromHeaders(scheduled.Headers)
+		if err != nil {
+			slog.Error("[scheduler] invalid scheduled message",
+				"id", scheduled.Id,
+				"reason", err.Error(),
+			)
+			continue
+		}
+		msg := builder.WithPayload(scheduled.Payload).Build()
+		s.arm(scheduled.Id, scheduled.DueAt, msg)
+	}
+}
+
+// Schedule publishes the message at the due time. Messages already due are
+// published as soon as possible.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - dueAt: when the message is published
+//   - msg: the message to be published
+//
+// Returns:
+//   - error: error if the scheduler is stopped or the message cannot be
+//     persisted
+func (s *Scheduler) Schedule(
+	ctx context.Context,
+	dueAt time.Time,
+	msg *message.Message,
+) error {
+	s.mu.Lock()
+	stopped := s.stopped
+	s.mu.Unlock()
+	if stopped {
+		return fmt.Errorf("[scheduler] scheduler is stopped")
+	}
+
+	id := msg.GetHeader().Get(message.HeaderMessageId)
+	msg.GetHeader().Set(message.HeaderScheduledAt, dueAt.UTC().Format(time.RFC3339Nano))
+
+	if s.store != nil {
+		payload, err := json.Marshal(msg.GetPayload())
+		if err != nil {
+			return fmt.Errorf("[scheduler] failed to encode payload: %w", err)
+		}
+		err = s.store.Save(ctx, ScheduledMessage{
+			Id:      id,
+			DueAt:   dueAt,
+			Headers: msg.GetHeader().All(),
+			Payload: payload,
+		})
+		if err != nil {
+			return fmt.Errorf("[scheduler] failed to persist message %s: %w", id, err)
+		}
+	}
+
+	s.arm(id, dueAt, msg)
+	return nil
+}
+
+// Stop cancels every timer and rejects new messages. Persisted messages are
+// published by the next process using the store. gomes.Shutdown stops the
+// schedulers of the buses it created.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	for id, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, id)
+	}
+	clear(s.attempts)
+}
+
+// Pending returns how many messages are waiting for their due time.
+//
+// Returns:
+//   - int: the amount of scheduled messages
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+func (s *Scheduler) arm(id string, dueAt time.Time, msg *message.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	if timer, ok := s.timers[id]; ok {
+		timer.Stop()
+	}
+	s.timers[id] = time.AfterFunc(time.Until(dueAt), func() {
+		s.publish(id, msg)
+	})
+}
+
+func (s *Scheduler) publish(id string, msg *message.Message) {
+	s.mu.Lock()
+	delete(s.timers, id)
+	dispatcher := s.dispatcher
+	s.mu.Unlock()
+
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	err := dispatcher.PublishMessage(ctx, msg)
+	if err == nil {
+		s.complete(ctx, id)
+		return
+	}
+
+	attempts, delay := s.recordFailure(id)
+	if s.maxAttempts > 0 && attempts >= s.maxAttempts && s.giveUp(ctx, id, msg, err) {
+		s.complete(ctx, id)
+		return
+	}
+	slog.Error("[scheduler] failed to publish scheduled message, retrying",
+		"messageId", id,
+		"attempts", attempts,
+		"retryIn", delay.String(),
+		"reason", err.Error(),
+	)
+	s.arm(id, time.Now().Add(delay), msg)
+}
+
+// giveUp sends a message which failed every publish attempt to the dead
+// letter channel, or drops it when none is set. It reports whether the
+// message can be removed from the scheduler.
+func (s *Scheduler) giveUp(
+	ctx context.Context,
+	id string,
+	msg *message.Message,
+	reason error,
+) bool {
+	if s.deadLetter == nil {
+		slog.Error("[scheduler] dropped scheduled message after failed attempts",
+			"messageId", id,
+			"attempts", s.maxAttempts,
+			"reason", reason.Error(),
+		)
+		return true
+	}
+
+	deadLetterMessage := message.NewMessageBuilderFromMessage(msg).
+		WithChannelName(s.deadLetter.Name()).
+		WithContext(ctx).
+		WithCustomHeader(message.HeaderDeadLetterError, reason.Error()).
+		Build()
+	if err := s.deadLetter.Send(ctx, deadLetterMessage); err != nil {
+		slog.Error("[scheduler] failed to send scheduled message to dead letter",
+			"messageId", id,
+			"dlqChannelName", s.deadLetter.Name(),
+			"reason", err.Error(),
+		)
+		return false
+	}
+	slog.Warn("[scheduler] sent scheduled message to dead letter",
+		"messageId", id,
+		"attempts", s.maxAttempts,
+		"reason", reason.Error(),
+		"dlqChannelName", s.deadLetter.Name(),
+	)
+	return true
+}
+
+// complete removes a published or dead-lettered message from the scheduler
+// and its store.
+func (s *Scheduler) complete(ctx context.Context, id string) {
+	s.mu.Lock()
+	delete(s.attempts, id)
+	s.mu.Unlock()
+	if s.store != nil {
+		if err := s.store.Delete(ctx, id); err != nil {
+			slog.Error("[scheduler] failed to delete scheduled message",
+				"messageId", id,
+				"reason", err.Error(),
+			)
+		}
+	}
+}
+
+// recordFailure records a failed publish of the message, returning its
+// failed attempts and the delay before the next one. Each failure doubles
+// the delay up to the maximum backoff. Without maximum attempts, the count
+// stops growing once the delay reaches the maximum backoff.
+func (s *Scheduler) recordFailure(id string) (int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attempts := s.attempts[id] + 1
+	delay := s.minBackoff
+	for i := 1; i < attempts && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, s.maxBackoff)
+	if delay < s.maxBackoff || attempts <= s.maxAttempts {
+		s.attempts[id] = attempts
+	}
+	return attempts, delay
+}
+
+// fileScheduleStore is a ScheduleStore persisted in a JSON file.
+type fileScheduleStore struct {
+	path     string
+	mu       sync.Mutex
+	messages map[string]ScheduledMessage
+}
+
+// NewFileScheduleStore creates a schedule store persisted in a JSON file,
+// loading the messages scheduled by previous processes.
+//
+// Parameters:
+//   - path: the JSON file path, created on the first save
+//
+// Returns:
+//   - *fileScheduleStore: configured store instance
+//   - error: error if the existing file cannot be read
+func NewFileScheduleStore(path string) (*fileScheduleStore, error) {
+	store := &fileScheduleStore{path: path, messages: map[string]ScheduledMessage{}}
+
+	if err := jsonfile.Load(path, &store.messages); err != nil {
+		return nil, fmt.Errorf("[schedule-store] %w", err)
+	}
+	return store, nil
+}
+
+// Save records a scheduled message and persists the store.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - scheduled: the scheduled message
+//
+// Returns:
+//   - error: error if the file cannot be written
+func (s *fileScheduleStore) Save(ctx context.Context, scheduled ScheduledMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[scheduled.Id] = scheduled
+	return s.persist()
+}
+
+// Delete removes a scheduled message and persists the store.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - id: the scheduled message id
+//
+// Returns:
+//   - error: error if the file cannot be written
+func (s *fileScheduleStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.messages[id]; !ok {
+		return nil
+	}
+	delete(s.messages, id)
+	return s.persist()
+}
+
+// Pending returns every scheduled message of the store.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - []ScheduledMessage: the scheduled messages
+//   - error: always nil
+func (s *fileScheduleStore) Pending(ctx context.Context) ([]ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := make([]ScheduledMessage, 0, len(s.messages))
+	for _, scheduled := range s.messages {
+		pending = append(pending, scheduled)
+	}
+	return pending, nil
+}
+
+// persist atomically rewrites the store file. The caller holds the lock.
+func (s *fileScheduleStore) persist() error {
+	if err := jsonfile.Save(s.path, s.messages); err != nil {
+		return fmt.Errorf("[schedule-store] %w", err)
+	}
+	return nil
+}
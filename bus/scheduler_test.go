@@ -0,0 +1,218 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/bus"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// publishingDispatcher delivers the published messages to a channel.
+type publishingDispatcher struct {
+	mockDispatcher
+	published chan *message.Message
+}
+
+func (p *publishingDispatcher) PublishMessage(ctx context.Context, msg *message.Message) error {
+	p.published <- msg
+	return nil
+}
+
+func TestCommandBus_SendAsyncAfter(t *testing.T) {
+	t.Parallel()
+	dispatcher := &publishingDispatcher{published: make(chan *message.Message, 1)}
+	cb := bus.NewCommandBus(dispatcher, bus.WithTracing(false))
+
+	start := time.Now()
+	if err := cb.SendAsyncAfter(context.Background(), mockAction{name: "Delayed"}, 20*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case msg := <-dispatcher.published:
+		if time.Since(start) < 20*time.Millisecond {
+			t.Error("expected command to be published after the delay")
+		}
+		if msg.GetHeader().Get(message.HeaderScheduledAt) == "" {
+			t.Error("expected scheduledAt header")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected scheduled command to be published")
+	}
+}
+
+func TestEventBus_PublishAtWithNativeDelay(t *testing.T) {
+	t.Parallel()
+	dispatcher := &publishingDispatcher{published: make(chan *message.Message, 1)}
+	eb := bus.NewEventBus(dispatcher, bus.WithTracing(false), bus.WithNativeDelay())
+
+	dueAt := time.Now().Add(time.Hour)
+	if err := eb.PublishAt(context.Background(), mockAction{name: "Delayed"}, dueAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := <-dispatcher.published
+	if msg.GetHeader().Get(message.HeaderScheduledAt) != dueAt.UTC().Format(time.RFC3339Nano) {
+		t.Errorf("expected scheduledAt header, got %q", msg.GetHeader().Get(message.HeaderScheduledAt))
+	}
+}
+
+func TestScheduler_RestoresPersistedMessages(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	store, err := bus.NewFileScheduleStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stopped := bus.NewScheduler(store)
+	cb := bus.NewCommandBus(&publishingDispatcher{}, bus.WithTracing(false), bus.WithScheduler(stopped))
+	if err := cb.SendAsyncAfter(context.Background(), mockAction{name: "Persisted"}, 20*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stopped.Stop()
+
+	restartedStore, err := bus.NewFileScheduleStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error reloading store: %v", err)
+	}
+	dispatcher := &publishingDispatcher{published: make(chan *message.Message, 1)}
+	bus.NewCommandBus(dispatcher, bus.WithTracing(false), bus.WithScheduler(bus.NewScheduler(restartedStore)))
+
+	select {
+	case msg := <-dispatcher.published:
+		if msg.GetHeader().Get(message.HeaderRoute) != "Persisted" {
+			t.Errorf("expected persisted command, got route %q", msg.GetHeader().Get(message.HeaderRoute))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected persisted command to be published after restart")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		pending, _ := restartedStore.Pending(context.Background())
+		if len(pending) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected published message to be deleted, got %d pending", len(pending))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// flakyDispatcher fails the first publishes and then delivers the messages.
+type flakyDispatcher struct {
+	mockDispatcher
+	failures  atomic.Int32
+	published chan *message.Message
+}
+
+func (f *flakyDispatcher) PublishMessage(ctx context.Context, msg *message.Message) error {
+	if f.failures.Add(-1) >= 0 {
+		return errors.New("broker unavailable")
+	}
+	f.published <- msg
+	return nil
+}
+
+func TestScheduler_RetriesFailedPublish(t *testing.T) {
+	t.Parallel()
+	dispatcher := &flakyDispatcher{published: make(chan *message.Message, 1)}
+	dispatcher.failures.Store(2)
+	scheduler := bus.NewScheduler(nil).WithRetryBackoff(5*time.Millisecond, 10*time.Millisecond)
+	cb := bus.NewCommandBus(dispatcher, bus.WithTracing(false), bus.WithScheduler(scheduler))
+
+	if err := cb.SendAsyncAfter(context.Background(), mockAction{name: "Retried"}, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case msg := <-dispatcher.published:
+		if msg.GetHeader().Get(message.HeaderRoute) != "Retried" {
+			t.Errorf("expected retried command, got route %q", msg.GetHeader().Get(message.HeaderRoute))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected scheduled command to be published after the failures")
+	}
+	if scheduler.Pending() != 0 {
+		t.Errorf("expected no pending message, got %d", scheduler.Pending())
+	}
+}
+
+// deadLetterChannel records the messages sent to it.
+type deadLetterChannel struct {
+	sent chan *message.Message
+}
+
+func (d *deadLetterChannel) Send(ctx context.Context, msg *message.Message) error {
+	d.sent <- msg
+	return nil
+}
+
+func (d *deadLetterChannel) Name() string {
+	return "scheduler.dlq"
+}
+
+func TestScheduler_DeadLettersAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+	dispatcher := &flakyDispatcher{published: make(chan *message.Message, 1)}
+	dispatcher.failures.Store(100)
+	deadLetter := &deadLetterChannel{sent: make(chan *message.Message, 1)}
+	store, _ := bus.NewFileScheduleStore(filepath.Join(t.TempDir(), "schedule.json"))
+	scheduler := bus.NewScheduler(store).
+		WithRetryBackoff(time.Millisecond, 2*time.Millisecond).
+		WithMaxAttempts(3, deadLetter)
+	cb := bus.NewCommandBus(dispatcher, bus.WithTracing(false), bus.WithScheduler(scheduler))
+
+	if err := cb.SendAsyncAfter(context.Background(), mockAction{name: "Failing"}, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case msg := <-deadLetter.sent:
+		if msg.GetHeader().Get(message.HeaderDeadLetterError) == "" {
+			t.Error("expected deadLetterError header")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the message dead-lettered after the failed attempts")
+	}
+	if failures := 100 - dispatcher.failures.Load(); failures != 3 {
+		t.Errorf("expected 3 publish attempts, got %d", failures)
+	}
+	deadline := time.Now().Add(time.Second)
+	for pending, _ := store.Pending(context.Background()); len(pending) > 0; pending, _ = store.Pending(context.Background()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the dead-lettered message deleted, got %d pending", len(pending))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCommandBus_StopStopsTheScheduler(t *testing.T) {
+	t.Parallel()
+	dispatcher := &publishingDispatcher{published: make(chan *message.Message, 1)}
+	scheduler := bus.NewScheduler(nil)
+	cb := bus.NewCommandBus(dispatcher, bus.WithTracing(false), bus.WithScheduler(scheduler))
+	if err := cb.SendAsyncAfter(context.Background(), mockAction{name: "Delayed"}, 20*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cb.Stop()
+	if scheduler.Pending() != 0 {
+		t.Errorf("expected no pending message, got %d", scheduler.Pending())
+	}
+	if err := cb.SendAsyncAfter(context.Background(), mockAction{name: "Delayed"}, time.Millisecond); err == nil {
+		t.Error("expected stopped scheduler error")
+	}
+	select {
+	case <-dispatcher.published:
+		t.Error("expected no message published after stop")
+	case <-time.After(40 * time.Millisecond):
+	}
+}
@@ -0,0 +1,101 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// TenantResolver resolves the tenant of the messages dispatched with a
+// context, e.g. from the authenticated user. An empty tenant leaves the
+// message without tenant.
+type TenantResolver func(ctx context.Context) (string, error)
+
+// WithTenantResolver stamps the tenant resolved from the context on every
+// message dispatched by the bus. Messages already carrying a tenant, e.g.
+// inherited from the message being handled, keep it.
+//
+// Parameters:
+//   - resolver: the tenant resolver
+//
+// Returns:
+//   - Option: the bus option
+func WithTenantResolver(resolver TenantResolver) Option {
+	return func(o *options) {
+		o.tenantResolver = resolver
+	}
+}
+
+// NewTenantMiddleware creates a middleware stamping the tenant resolved from
+// the context on the dispatched messages, to be registered with Use so every
+// bus stamps the tenant.
+//
+// Parameters:
+//   - resolver: the tenant resolver
+//
+// Returns:
+//   - Middleware: the tenant middleware
+func NewTenantMiddleware(resolver TenantResolver) Middleware {
+	return func(next Dispatcher) Dispatcher {
+		return &tenantDispatcher{Dispatcher: next, resolver: resolver}
+	}
+}
+
+// tenantDispatcher stamps the resolved tenant on the dispatched messages.
+type tenantDispatcher struct {
+	Dispatcher
+	resolver TenantResolver
+}
+
+// SendMessage stamps the tenant and dispatches the message synchronously.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be sent
+//
+// Returns:
+//   - any: the response from message processing
+//   - error: error if the tenant cannot be resolved or sending fails
+func (t *tenantDispatcher) SendMessage(
+	ctx context.Context,
+	msg *message.Message,
+) (any, error) {
+	if err := t.stamp(ctx, msg); err != nil {
+		return nil, err
+	}
+	return t.Dispatcher.SendMessage(ctx, msg)
+}
+
+// PublishMessage stamps the tenant and dispatches the message asynchronously.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be published
+//
+// Returns:
+//   - error: error if the tenant cannot be resolved or publishing fails
+func (t *tenantDispatcher) PublishMessage(
+	ctx context.Context,
+	msg *message.Message,
+) error {
+	if err := t.stamp(ctx, msg); err != nil {
+		return err
+	}
+	return t.Dispatcher.PublishMessage(ctx, msg)
+}
+
+// stamp sets the tenant header of a message without tenant.
+func (t *tenantDispatcher) stamp(ctx context.Context, msg *message.Message) error {
+	if msg.GetHeader().Get(message.HeaderTenantId) != "" {
+		return nil
+	}
+	tenantId, err := t.resolver(ctx)
+	if err != nil {
+		return fmt.Errorf("[tenant] failed to resolve tenant: %w", err)
+	}
+	if tenantId == "" {
+		return nil
+	}
+	return msg.GetHeader().Set(message.HeaderTenantId, tenantId)
+}
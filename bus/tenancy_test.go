@@ -0,0 +1,54 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/bus"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+type tenantContextKey struct{}
+
+func tenantFromContext(ctx context.Context) (string, error) {
+	if tenantId, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenantId, nil
+	}
+	return "", errors.New("unauthenticated")
+}
+
+func TestWithTenantResolver(t *testing.T) {
+	t.Parallel()
+	ctx := context.WithValue(context.Background(), tenantContextKey{}, "acme")
+
+	t.Run("should stamp the resolved tenant", func(t *testing.T) {
+		t.Parallel()
+		commandBus := bus.NewRecordingCommandBus(bus.WithTenantResolver(tenantFromContext))
+		if err := commandBus.SendRawAsync(ctx, "create.user", "jane", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		commandBus.AssertPublished(t, "create.user", bus.HasHeader(message.HeaderTenantId, "acme"))
+	})
+
+	t.Run("should keep the tenant of the message", func(t *testing.T) {
+		t.Parallel()
+		eventBus := bus.NewRecordingEventBus(bus.WithTenantResolver(tenantFromContext))
+		headers := map[string]string{message.HeaderTenantId: "globex"}
+		if err := eventBus.PublishRaw(ctx, "user.created", "jane", headers); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		eventBus.AssertPublished(t, "user.created", bus.HasHeader(message.HeaderTenantId, "globex"))
+	})
+
+	t.Run("should fail when the tenant cannot be resolved", func(t *testing.T) {
+		t.Parallel()
+		commandBus := bus.NewRecordingCommandBus(bus.WithTenantResolver(tenantFromContext))
+		if _, err := commandBus.SendRaw(context.Background(), "create.user", "jane", nil); err == nil {
+			t.Error("expected error resolving the tenant")
+		}
+		if recorded := commandBus.Messages(""); len(recorded) != 0 {
+			t.Errorf("expected no dispatched message, got %d", len(recorded))
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/otel"
+)
+
+// Option configures a CommandBus, QueryBus or EventBus.
+type Option func(*options)
+
+// options holds the per-bus configuration.
+type options struct {
+	tracing         bool
+	traceAttributes []otel.OtelAttribute
+	scheduler       *Scheduler
+	nativeDelay     bool
+	tenantResolver  TenantResolver
+}
+
+// WithTracing enables or disables the bus-level producer span created for
+// every dispatch. Tracing is enabled by default; spans are only exported when
+// OpenTelemetry tracing is enabled for the message system.
+//
+// Parameters:
+//   - enabled: whether the bus creates dispatch spans
+//
+// Returns:
+//   - Option: the bus option
+func WithTracing(enabled bool) Option {
+	return func(o *options) {
+		o.tracing = enabled
+	}
+}
+
+// WithTraceAttributes adds attributes to every dispatch span of the bus.
+//
+// Parameters:
+//   - attributes: the span attributes
+//
+// Returns:
+//   - Option: the bus option
+func WithTraceAttributes(attributes ...otel.OtelAttribute) Option {
+	return func(o *options) {
+		o.traceAttributes = append(o.traceAttributes, attributes...)
+	}
+}
+
+// newBusDispatcher wraps the dispatcher with the registered middlewares, the
+// tenant stamping and, outermost, with the bus tracing.
+//
+// Parameters:
+//   - busType: the bus type, recorded in the dispatch spans
+//   - dispatcher: the dispatcher to be wrapped
+//   - opts: the bus options
+//
+// Returns:
+//   - Dispatcher: the wrapped dispatcher
+func newBusDispatcher(busType string, dispatcher Dispatcher, opts []Option) Dispatcher {
+	config := newOptions(opts)
+	dispatcher = applyMiddlewares(dispatcher)
+	if config.tenantResolver != nil {
+		dispatcher = NewTenantMiddleware(config.tenantResolver)(dispatcher)
+	}
+	if !config.tracing {
+		return dispatcher
+	}
+
+	return &tracingDispatcher{
+		Dispatcher: dispatcher,
+		trace:      otel.InitTrace(fmt.Sprintf("%s-bus", busType)),
+		attributes: append(
+			[]otel.OtelAttribute{otel.NewOtelAttr("messaging.gomes.bus", busType)},
+			config.traceAttributes...,
+		),
+	}
+}
+
+// newOptions applies the bus options over the defaults.
+func newOptions(opts []Option) *options {
+	config := &options{tracing: true}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(config)
+		}
+	}
+	return config
+}
+
+// tracingDispatcher starts a producer span with the Create operation for
+// every dispatch, whether or not the caller context is already traced, and
+// records the dispatch outcome. The message context carries the span, so the
+// publish spans of the channels are linked to it.
+type tracingDispatcher struct {
+	Dispatcher
+	trace      otel.OtelTrace
+	attributes []otel.OtelAttribute
+}
+
+// SendMessage dispatches a message synchronously inside a dispatch span.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be sent
+//
+// Returns:
+//   - any: the response from message processing
+//   - error: error if sending or processing fails
+func (t *tracingDispatcher) SendMessage(
+	ctx context.Context,
+	msg *message.Message,
+) (any, error) {
+	ctx, span := t.start(ctx, msg)
+	defer span.End()
+
+	result, err := t.Dispatcher.SendMessage(ctx, msg)
+	recordOutcome(span, err)
+	return result, err
+}
+
+// PublishMessage dispatches a message asynchronously inside a dispatch span.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be published
+//
+// Returns:
+//   - error: error if publishing fails
+func (t *tracingDispatcher) PublishMessage(
+	ctx context.Context,
+	msg *message.Message,
+) error {
+	ctx, span := t.start(ctx, msg)
+	defer span.End()
+
+	err := t.Dispatcher.PublishMessage(ctx, msg)
+	recordOutcome(span, err)
+	return err
+}
+
+func (t *tracingDispatcher) start(
+	ctx context.Context,
+	msg *message.Message,
+) (context.Context, otel.OtelSpan) {
+	ctx, span := t.trace.Start(
+		ctx,
+		fmt.Sprintf("create %s", msg.GetHeader().Get(message.HeaderRoute)),
+		otel.WithMessagingSystemType(otel.MessageSystemTypeInternal),
+		otel.WithSpanOperation(otel.SpanOperationCreate),
+		otel.WithSpanKind(otel.SpanKindProducer),
+		otel.WithAttributes(t.attributes...),
+		otel.WithMessage(msg),
+	)
+	msg.SetContext(ctx)
+	return ctx, span
+}
+
+func recordOutcome(span otel.OtelSpan, err error) {
+	if err != nil {
+		span.Error(err, err.Error())
+		return
+	}
+	span.Success("message dispatched")
+}
@@ -0,0 +1,145 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/bus"
+	gomesotel "github.com/jeffersonbrasilino/gomes/otel"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// recordedSpan is a minimal span recording its name and status.
+type recordedSpan struct {
+	embedded.Span
+	name        string
+	spanContext trace.SpanContext
+	mu          sync.Mutex
+	status      codes.Code
+	ended       bool
+}
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ended = true
+}
+func (s *recordedSpan) AddEvent(string, ...trace.EventOption) {}
+func (s *recordedSpan) AddLink(trace.Link)                    {}
+func (s *recordedSpan) IsRecording() bool                     { return true }
+func (s *recordedSpan) RecordError(error, ...trace.EventOption) {
+}
+func (s *recordedSpan) SpanContext() trace.SpanContext { return s.spanContext }
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+}
+func (s *recordedSpan) SetName(name string)                  { s.name = name }
+func (s *recordedSpan) SetAttributes(...attribute.KeyValue)  {}
+func (s *recordedSpan) TracerProvider() trace.TracerProvider { return recorder }
+
+// spanRecorder is a tracer provider keeping every started span.
+type spanRecorder struct {
+	embedded.TracerProvider
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+// recordingTracer starts the spans of a spanRecorder.
+type recordingTracer struct {
+	embedded.Tracer
+	recorder *spanRecorder
+}
+
+func (r *spanRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{recorder: r}
+}
+
+func (t *recordingTracer) Start(
+	ctx context.Context,
+	name string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	return t.recorder.start(ctx, name)
+}
+
+func (r *spanRecorder) start(
+	ctx context.Context,
+	name string,
+) (context.Context, trace.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := byte(len(r.spans) + 1)
+	span := &recordedSpan{
+		name: name,
+		spanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{id},
+			SpanID:  trace.SpanID{id},
+		}),
+	}
+	r.spans = append(r.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (r *spanRecorder) find(name string) *recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, span := range r.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+var recorder = &spanRecorder{}
+
+func TestBusTracing(t *testing.T) {
+	otel.SetTracerProvider(recorder)
+	gomesotel.EnableTrace()
+
+	t.Run("should create a span for asynchronous dispatches", func(t *testing.T) {
+		dispatcher := &mockDispatcher{}
+		commandBus := bus.NewCommandBus(dispatcher)
+		if err := commandBus.SendRawAsync(context.Background(), "traced.async", "payload", nil); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		span := recorder.find("create traced.async")
+		if span == nil {
+			t.Fatal("expected dispatch span to be created")
+		}
+		if !span.ended || span.status != codes.Ok {
+			t.Errorf("expected ended successful span, got ended=%v status=%v", span.ended, span.status)
+		}
+		if !trace.SpanContextFromContext(dispatcher.lastMsg.GetContext()).Equal(span.spanContext) {
+			t.Error("expected message context to carry the dispatch span")
+		}
+	})
+
+	t.Run("should record dispatch errors", func(t *testing.T) {
+		queryBus := bus.NewQueryBus(&mockDispatcher{returnErr: errors.New("failed")})
+		queryBus.SendRaw(context.Background(), "traced.error", "payload", nil)
+
+		span := recorder.find("create traced.error")
+		if span == nil || span.status != codes.Error {
+			t.Errorf("expected failed dispatch span, got %+v", span)
+		}
+	})
+
+	t.Run("should not create spans when tracing is disabled", func(t *testing.T) {
+		eventBus := bus.NewEventBus(&mockEventDispatcher{}, bus.WithTracing(false))
+		eventBus.PublishRaw(context.Background(), "untraced", "payload", nil)
+
+		if recorder.find("create untraced") != nil {
+			t.Error("expected no dispatch span")
+		}
+	})
+}
@@ -0,0 +1,355 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes"
+	"github.com/jeffersonbrasilino/gomes/channel/eventstore"
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/adapter/adaptertest"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+type orderPlacedHandler struct {
+	handled chan string
+}
+
+func (h *orderPlacedHandler) Handle(_ context.Context, evt *orderPlaced) (any, error) {
+	h.handled <- evt.Id
+	return nil, nil
+}
+
+func newContainer(conn *eventstore.Connection) container.Container[any, any] {
+	c := container.NewGenericContainer[any, any]()
+	_ = c.Set(adapter.ConnectionKey(conn.ReferenceName()), conn)
+	return c
+}
+
+func TestPublisherChannel_Send(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn := eventstore.NewConnection("events", eventstore.NewMemoryStore())
+	channel, err := eventstore.NewPublisherChannelAdapterBuilder("events", "orders").
+		Build(newContainer(conn))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("should append the message to its stream", func(t *testing.T) {
+		msg := newEvent("order-1")
+		msg.GetHeader().Set(eventstore.HeaderStreamId, "order-1")
+		msg.GetHeader().Set(eventstore.HeaderExpectedVersion, "0")
+		if err := channel.Send(ctx, msg); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		msgs, _ := conn.EventStore().ReadStream(ctx, "order-1", 1)
+		if len(msgs) != 1 {
+			t.Errorf("expected 1 event, got %d", len(msgs))
+		}
+	})
+
+	t.Run("should fail when the stream is at another version", func(t *testing.T) {
+		msg := newEvent("order-1")
+		msg.GetHeader().Set(eventstore.HeaderStreamId, "order-1")
+		msg.GetHeader().Set(eventstore.HeaderExpectedVersion, "0")
+		if err := channel.Send(ctx, msg); !errors.Is(err, eventstore.ErrWrongExpectedVersion) {
+			t.Errorf("expected ErrWrongExpectedVersion, got %v", err)
+		}
+	})
+
+	t.Run("should fail without stream id", func(t *testing.T) {
+		if err := channel.Send(ctx, newEvent("order-2")); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestConsumerChannel_Receive(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn := eventstore.NewConnection("events", eventstore.NewMemoryStore())
+	_, _ = conn.EventStore().AppendToStream(
+		ctx,
+		"order-1",
+		[]*message.Message{newEvent("order-1"), newEvent("order-1"), newEvent("order-1")},
+		eventstore.NoStream,
+	)
+
+	channel, err := eventstore.NewConsumerChannelAdapterBuilder("events", "order-1", "order-projector").
+		WithFromPosition(2).
+		WithPollInterval(10 * time.Millisecond).
+		Build(newContainer(conn))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer channel.Close()
+
+	receiveVersion := func() string {
+		t.Helper()
+		msg, err := channel.ReceiveMessage(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return msg.GetHeader().Get(eventstore.HeaderStreamVersion)
+	}
+
+	if version := receiveVersion(); version != "2" {
+		t.Errorf("expected version 2, got %s", version)
+	}
+	if version := receiveVersion(); version != "3" {
+		t.Errorf("expected version 3, got %s", version)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = conn.EventStore().AppendToStream(
+			ctx,
+			"order-1",
+			[]*message.Message{newEvent("order-1")},
+			3,
+		)
+	}()
+	if version := receiveVersion(); version != "4" {
+		t.Errorf("expected the appended version 4, got %s", version)
+	}
+}
+
+func TestConsumerChannel_ReceiveCategory(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn := eventstore.NewConnection("events", eventstore.NewMemoryStore())
+	for _, streamId := range []string{"order-1", "customer-1", "order-2"} {
+		_, _ = conn.EventStore().AppendToStream(
+			ctx,
+			streamId,
+			[]*message.Message{newEvent(streamId)},
+			eventstore.NoStream,
+		)
+	}
+
+	cases := []struct {
+		streamId string
+		expected []string
+	}{
+		{eventstore.AllStreams, []string{"order-1", "customer-1", "order-2"}},
+		{eventstore.CategoryStream("order"), []string{"order-1", "order-2"}},
+	}
+	for _, c := range cases {
+		channel, err := eventstore.NewConsumerChannelAdapterBuilder("events", c.streamId, "orders").
+			WithPollInterval(10 * time.Millisecond).
+			Build(newContainer(conn))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, expected := range c.expected {
+			msg, err := channel.ReceiveMessage(ctx)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if streamId := msg.GetHeader().Get(eventstore.HeaderStreamId); streamId != expected {
+				t.Errorf("%s: expected stream %s, got %s", c.streamId, expected, streamId)
+			}
+		}
+		channel.Close()
+	}
+}
+
+func TestConsumerChannel_ResumesFromCheckpoint(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn := eventstore.NewConnection("events", eventstore.NewMemoryStore())
+	_, _ = conn.EventStore().AppendToStream(
+		ctx,
+		"order-1",
+		[]*message.Message{newEvent("order-1"), newEvent("order-1"), newEvent("order-1")},
+		eventstore.NoStream,
+	)
+	checkpoints := endpoint.NewInMemoryCheckpointStore()
+	build := func() *adapter.InboundChannelAdapter {
+		t.Helper()
+		channel, err := eventstore.NewConsumerChannelAdapterBuilder("events", eventstore.AllStreams, "orders").
+			WithPollInterval(10 * time.Millisecond).
+			WithCheckpointStore(checkpoints).
+			Build(newContainer(conn))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return channel
+	}
+
+	channel := build()
+	for range 2 {
+		msg, err := channel.ReceiveMessage(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := channel.CommitMessage(msg); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	channel.Close()
+	if checkpoint, _ := checkpoints.LoadCheckpoint(ctx, "orders"); checkpoint != 2 {
+		t.Fatalf("expected checkpoint 2, got %d", checkpoint)
+	}
+
+	channel = build()
+	defer channel.Close()
+	msg, err := channel.ReceiveMessage(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if position := msg.GetHeader().Get(eventstore.HeaderPosition); position != "3" {
+		t.Errorf("expected to resume at position 3, got %s", position)
+	}
+}
+
+func TestConsumerChannel_Replay(t *testing.T) {
+	conn := eventstore.NewConnection("event-store", eventstore.NewMemoryStore())
+	consumerChannel := eventstore.NewConsumerChannelAdapterBuilder(
+		"event-store",
+		"order-9",
+		"order-9-replay",
+	).WithPollInterval(10 * time.Millisecond)
+	orderHandler := &orderPlacedHandler{handled: make(chan string, 2)}
+
+	for _, err := range []error{
+		gomes.AddChannelConnection(conn),
+		gomes.AddConsumerChannel(consumerChannel),
+		gomes.AddActionHandler(orderHandler),
+		gomes.Start(),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	t.Cleanup(gomes.Shutdown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	t.Cleanup(cancel)
+	_, err := conn.EventStore().AppendToStream(
+		ctx,
+		"order-9",
+		[]*message.Message{newEvent("order-9"), newEvent("order-10")},
+		eventstore.NoStream,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	consumer, err := gomes.EventDrivenConsumer("order-9-replay")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go consumer.Run(ctx)
+
+	for _, want := range []string{"order-9", "order-10"} {
+		select {
+		case id := <-orderHandler.handled:
+			if id != want {
+				t.Errorf("expected %s to be handled, got %s", want, id)
+			}
+		case <-ctx.Done():
+			t.Fatalf("expected %s to be handled", want)
+		}
+	}
+}
+
+func TestInboundChannelAdapter_Conformance(t *testing.T) {
+	adaptertest.RunInboundSuite(t, func(t *testing.T) *adaptertest.InboundFixture {
+		store := eventstore.NewMemoryStore()
+		outbound := eventstore.NewOutboundChannelAdapter(
+			eventstore.NewEventStore(store),
+			"inbound",
+			eventstore.NewMessageTranslator(),
+		)
+		return &adaptertest.InboundFixture{
+			Channel: eventstore.NewInboundChannelAdapter(
+				store,
+				"inbound",
+				1,
+				10*time.Millisecond,
+				eventstore.NewMessageTranslator(),
+			),
+			Produce: func(ctx context.Context, msg *message.Message) error {
+				msg.GetHeader().Set(eventstore.HeaderStreamId, "inbound")
+				return outbound.Send(ctx, msg)
+			},
+		}
+	})
+}
+
+type streamRecorderHandler struct {
+	streams []string
+}
+
+func (h *streamRecorderHandler) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	h.streams = append(h.streams, msg.GetHeader().Get(eventstore.HeaderStreamId))
+	return msg, nil
+}
+
+func TestProjectionRunner_CheckpointsGlobalPosition(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn := eventstore.NewConnection("events", eventstore.NewMemoryStore())
+	appendTo := func(streamIds ...string) {
+		t.Helper()
+		for _, streamId := range streamIds {
+			_, err := conn.EventStore().AppendToStream(
+				ctx,
+				streamId,
+				[]*message.Message{newEvent(streamId)},
+				eventstore.AnyVersion,
+			)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+	}
+	appendTo("order-1", "customer-1", "order-2")
+
+	channel, err := eventstore.NewConsumerChannelAdapterBuilder(
+		"events",
+		eventstore.CategoryStream("order"),
+		"order-summary",
+	).WithPollInterval(10 * time.Millisecond).Build(newContainer(conn))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer channel.Close()
+	recorder := &streamRecorderHandler{}
+	runner := endpoint.NewProjectionRunner(
+		"order-summary",
+		endpoint.NewGateway(recorder, "", ""),
+		channel,
+		endpoint.NewInMemoryCheckpointStore(),
+	).WithIdleTimeout(50 * time.Millisecond)
+
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if checkpoint, _ := runner.Checkpoint(ctx); checkpoint != 3 {
+		t.Errorf("expected the global position 3 as checkpoint, got %d", checkpoint)
+	}
+
+	appendTo("customer-2", "order-1")
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recorder.streams) != 3 || recorder.streams[2] != "order-1" {
+		t.Errorf("expected each order event projected once, got %v", recorder.streams)
+	}
+	if checkpoint, _ := runner.Checkpoint(ctx); checkpoint != 5 {
+		t.Errorf("expected the global position 5 as checkpoint, got %d", checkpoint)
+	}
+}
@@ -0,0 +1,88 @@
+package eventstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+)
+
+// Connection is a channel connection to an event store, shared by its
+// publisher and consumer channels.
+type Connection struct {
+	name       string
+	eventStore *EventStore
+}
+
+// NewConnection creates a new connection to an event store over a storage.
+//
+// Parameters:
+//   - name: the connection name identifier
+//   - store: the event storage, e.g. a MemoryStore or a SQLStore
+//
+// Returns:
+//   - *Connection: the connection instance
+func NewConnection(name string, store Store) *Connection {
+	return &Connection{name: name, eventStore: NewEventStore(store)}
+}
+
+// ReferenceName returns the connection name identifier.
+//
+// Returns:
+//   - string: the connection name
+func (c *Connection) ReferenceName() string {
+	return c.name
+}
+
+// Connect is a no-op, the storage is opened by the application.
+//
+// Returns:
+//   - error: always nil
+func (c *Connection) Connect() error {
+	return nil
+}
+
+// Disconnect is a no-op, the storage is closed by the application.
+//
+// Returns:
+//   - error: always nil
+func (c *Connection) Disconnect() error {
+	return nil
+}
+
+// EventStore returns the event store of the connection, used by the command
+// handlers to append and load the events of their aggregates.
+//
+// Returns:
+//   - *EventStore: the event store
+func (c *Connection) EventStore() *EventStore {
+	return c.eventStore
+}
+
+// getConnection returns the event store connection registered in the
+// container.
+func getConnection(
+	c container.Container[any, any],
+	connectionReferenceName string,
+) (*Connection, error) {
+	conn, err := container.Resolve[*Connection, any, any](
+		c,
+		adapter.ConnectionKey(connectionReferenceName),
+	)
+	if errors.Is(err, container.ErrItemNotFound) {
+		return nil, fmt.Errorf(
+			"no event store connection is registered as %s: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"connection %s is not a valid event store connection: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	return conn, nil
+}
@@ -0,0 +1,314 @@
+// Package eventstore provides event sourcing support for the message system.
+//
+// This package implements an append-only event stream abstraction, where
+// each aggregate has its own stream of events identified by the aggregate id
+// and versioned from 1. Events are appended with optimistic concurrency and
+// replayed through the regular handler pipeline by a catch-up consumer
+// channel.
+//
+// The EventStore implementation supports:
+// - Appending events to a stream with an expected version
+// - Reading a stream from a given version
+// - In-memory and SQL-backed stores
+// - Publisher channels appending the published events to their stream
+// - A global position ordering the events of all the streams
+// - Catch-up consumer channels over a stream, a category or all the streams
+// - Consumer positions persisted in a checkpoint store
+// - Seeking a global position, as the source of projection runners
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// Headers of the events read from or appended to an event store.
+const (
+	// HeaderStreamId holds the id of the stream of an event, which is the
+	// id of its aggregate.
+	HeaderStreamId = "streamId"
+	// HeaderStreamVersion holds the version of an event in its stream.
+	HeaderStreamVersion = "streamVersion"
+	// HeaderExpectedVersion holds the stream version expected by a message
+	// published to an event store channel.
+	HeaderExpectedVersion = "expectedVersion"
+	// HeaderPosition holds the global position of an event in the store.
+	HeaderPosition = "eventPosition"
+)
+
+// AllStreams is the stream id of the consumer channels consuming the events
+// of every stream, in global position order.
+const AllStreams = "$all"
+
+// categoryStreamPrefix prefixes the stream ids of the consumer channels
+// consuming a category of streams.
+const categoryStreamPrefix = "$ce-"
+
+// Expected versions with special meaning.
+const (
+	// AnyVersion appends to a stream regardless of its version.
+	AnyVersion int64 = -1
+	// NoStream appends to a stream only when it has no event yet.
+	NoStream int64 = 0
+)
+
+// ErrWrongExpectedVersion is returned when a stream was changed since the
+// expected version, e.g. by a concurrent command on the same aggregate.
+var ErrWrongExpectedVersion = errors.New("[event-store] wrong expected version")
+
+// Event is an event stored in a stream, in its wire format.
+type Event struct {
+	// StreamId is the id of the stream of the event.
+	StreamId string
+	// Version is the position of the event in its stream, starting at 1.
+	Version int64
+	// Position is the global position of the event across the streams of
+	// the store, starting at 1 and increasing in append order.
+	Position int64
+	// Headers are the message headers.
+	Headers map[string]string
+	// Payload is the JSON encoded message payload.
+	Payload []byte
+	// RecordedAt is when the event was appended.
+	RecordedAt time.Time
+}
+
+// Store defines the contract of the event storages, e.g. a SQL table.
+type Store interface {
+	// Append stores events at the end of a stream, when the stream is at the
+	// expected version.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - streamId: The id of the stream
+	//   - expectedVersion: The expected stream version, or AnyVersion
+	//   - events: The events to append, in order
+	//
+	// Returns:
+	//   - int64: The stream version after the append
+	//   - error: ErrWrongExpectedVersion if the stream is at another version
+	Append(
+		ctx context.Context,
+		streamId string,
+		expectedVersion int64,
+		events []*Event,
+	) (int64, error)
+	// Read returns the events of a stream from a version, in order.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - streamId: The id of the stream
+	//   - fromVersion: The version of the first event to return
+	//   - limit: The maximum number of events to return, zero for all
+	//
+	// Returns:
+	//   - []*Event: The events of the stream
+	//   - error: Error if the storage cannot be read
+	Read(
+		ctx context.Context,
+		streamId string,
+		fromVersion int64,
+		limit int,
+	) ([]*Event, error)
+	// ReadAll returns the events selected by a filter from a global
+	// position, in global position order.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - filter: The streams to read, all the streams when empty
+	//   - fromPosition: The global position of the first event to return
+	//   - limit: The maximum number of events to return, zero for all
+	//
+	// Returns:
+	//   - []*Event: The selected events
+	//   - error: Error if the storage cannot be read
+	ReadAll(
+		ctx context.Context,
+		filter StreamFilter,
+		fromPosition int64,
+		limit int,
+	) ([]*Event, error)
+}
+
+// StreamFilter selects the streams read by ReadAll. The zero value selects
+// all the streams.
+type StreamFilter struct {
+	// StreamId selects a single stream.
+	StreamId string
+	// Category selects the streams of a category, see CategoryOf.
+	Category string
+}
+
+// Matches reports whether an event belongs to the selected streams.
+//
+// Parameters:
+//   - event: the event
+//
+// Returns:
+//   - bool: true if the event is selected
+func (f StreamFilter) Matches(event *Event) bool {
+	if f.StreamId != "" && event.StreamId != f.StreamId {
+		return false
+	}
+	return f.Category == "" || CategoryOf(event.StreamId) == f.Category
+}
+
+// CategoryOf returns the category of a stream, the part of its id before the
+// first dash, e.g. "order" for "order-1", or the whole id without dash.
+//
+// Parameters:
+//   - streamId: the id of the stream
+//
+// Returns:
+//   - string: the category of the stream
+func CategoryOf(streamId string) string {
+	category, _, _ := strings.Cut(streamId, "-")
+	return category
+}
+
+// CategoryStream returns the stream id of the consumer channels consuming
+// the streams of a category, in global position order.
+//
+// Parameters:
+//   - category: the category, e.g. "order"
+//
+// Returns:
+//   - string: the stream id of the category
+func CategoryStream(category string) string {
+	return categoryStreamPrefix + category
+}
+
+// streamFilterOf returns the filter of the stream id of a consumer channel:
+// AllStreams, a CategoryStream or the id of a single stream.
+func streamFilterOf(streamId string) StreamFilter {
+	if streamId == AllStreams {
+		return StreamFilter{}
+	}
+	if category, ok := strings.CutPrefix(streamId, categoryStreamPrefix); ok {
+		return StreamFilter{Category: category}
+	}
+	return StreamFilter{StreamId: streamId}
+}
+
+// EventStore appends and reads the events of aggregates as messages.
+type EventStore struct {
+	store      Store
+	translator *MessageTranslator
+}
+
+// NewEventStore creates a new event store over a storage.
+//
+// Parameters:
+//   - store: the event storage
+//
+// Returns:
+//   - *EventStore: the event store instance
+func NewEventStore(store Store) *EventStore {
+	return &EventStore{store: store, translator: NewMessageTranslator()}
+}
+
+// AppendToStream appends events to the stream of an aggregate with
+// optimistic concurrency: the append fails when the stream is not at the
+// expected version, so the caller can reload the aggregate and retry.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - aggregateId: the id of the aggregate, which identifies its stream
+//   - events: the events to append, in order
+//   - expectedVersion: the expected stream version, NoStream for a new
+//     aggregate or AnyVersion to skip the check
+//
+// Returns:
+//   - int64: the stream version after the append
+//   - error: ErrWrongExpectedVersion if the stream is at another version
+func (s *EventStore) AppendToStream(
+	ctx context.Context,
+	aggregateId string,
+	events []*message.Message,
+	expectedVersion int64,
+) (int64, error) {
+	if aggregateId == "" {
+		return 0, fmt.Errorf("[event-store] aggregate id cannot be empty")
+	}
+	if expectedVersion < AnyVersion {
+		return 0, fmt.Errorf(
+			"[event-store] invalid expected version %d",
+			expectedVersion,
+		)
+	}
+
+	records := make([]*Event, len(events))
+	for i, msg := range events {
+		record, err := s.translator.FromMessage(msg)
+		if err != nil {
+			return 0, err
+		}
+		record.StreamId = aggregateId
+		records[i] = record
+	}
+	return s.store.Append(ctx, aggregateId, expectedVersion, records)
+}
+
+// ReadStream returns the events of an aggregate from a version, with their
+// stream id and version headers.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - aggregateId: the id of the aggregate
+//   - fromVersion: the version of the first event to return
+//
+// Returns:
+//   - []*message.Message: the events of the aggregate, in order
+//   - error: error if the stream cannot be read or translated
+func (s *EventStore) ReadStream(
+	ctx context.Context,
+	aggregateId string,
+	fromVersion int64,
+) ([]*message.Message, error) {
+	records, err := s.store.Read(ctx, aggregateId, fromVersion, 0)
+	if err != nil {
+		return nil, fmt.Errorf("[event-store] failed to read stream %s: %w", aggregateId, err)
+	}
+
+	msgs := make([]*message.Message, len(records))
+	for i, record := range records {
+		msg, err := s.translator.ToMessage(record)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = msg
+	}
+	return msgs, nil
+}
+
+// Store returns the storage of the event store.
+//
+// Returns:
+//   - Store: the event storage
+func (s *EventStore) Store() Store {
+	return s.store
+}
+
+// expectedVersionOf returns the expected version carried by a message,
+// AnyVersion when it has none.
+func expectedVersionOf(msg *message.Message) (int64, error) {
+	value := msg.GetHeader().Get(HeaderExpectedVersion)
+	if value == "" {
+		return AnyVersion, nil
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[event-store] invalid expected version %q: %w",
+			value,
+			err,
+		)
+	}
+	return version, nil
+}
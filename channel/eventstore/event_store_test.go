@@ -0,0 +1,104 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/channel/eventstore"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+type orderPlaced struct {
+	Id string `json:"id"`
+}
+
+func (e *orderPlaced) Name() string { return "order.placed" }
+
+func newEvent(id string) *message.Message {
+	return message.NewMessageBuilder().
+		WithMessageType(message.Event).
+		WithRoute("order.placed").
+		WithPayload(&orderPlaced{Id: id}).
+		Build()
+}
+
+func TestEventStore_AppendToStream(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should append events with optimistic concurrency", func(t *testing.T) {
+		store := eventstore.NewEventStore(eventstore.NewMemoryStore())
+
+		version, err := store.AppendToStream(
+			ctx,
+			"order-1",
+			[]*message.Message{newEvent("order-1"), newEvent("order-1")},
+			eventstore.NoStream,
+		)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if version != 2 {
+			t.Errorf("expected version 2, got %d", version)
+		}
+
+		_, err = store.AppendToStream(
+			ctx,
+			"order-1",
+			[]*message.Message{newEvent("order-1")},
+			eventstore.NoStream,
+		)
+		if !errors.Is(err, eventstore.ErrWrongExpectedVersion) {
+			t.Errorf("expected ErrWrongExpectedVersion, got %v", err)
+		}
+
+		version, err = store.AppendToStream(
+			ctx,
+			"order-1",
+			[]*message.Message{newEvent("order-1")},
+			eventstore.AnyVersion,
+		)
+		if err != nil || version != 3 {
+			t.Errorf("expected version 3, got %d, %v", version, err)
+		}
+	})
+
+	t.Run("should fail without aggregate id", func(t *testing.T) {
+		store := eventstore.NewEventStore(eventstore.NewMemoryStore())
+		_, err := store.AppendToStream(ctx, "", nil, eventstore.AnyVersion)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestEventStore_ReadStream(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := eventstore.NewEventStore(eventstore.NewMemoryStore())
+	_, _ = store.AppendToStream(
+		ctx,
+		"order-1",
+		[]*message.Message{newEvent("order-1"), newEvent("order-1"), newEvent("order-1")},
+		eventstore.NoStream,
+	)
+
+	msgs, err := store.ReadStream(ctx, "order-1", 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(msgs))
+	}
+	header := msgs[0].GetHeader()
+	if header.Get(eventstore.HeaderStreamId) != "order-1" {
+		t.Errorf("expected stream order-1, got %s", header.Get(eventstore.HeaderStreamId))
+	}
+	if header.Get(eventstore.HeaderStreamVersion) != "2" {
+		t.Errorf("expected version 2, got %s", header.Get(eventstore.HeaderStreamVersion))
+	}
+	if header.Get(message.HeaderRoute) != "order.placed" {
+		t.Errorf("expected route order.placed, got %s", header.Get(message.HeaderRoute))
+	}
+}
@@ -0,0 +1,443 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// readBatchSize is the maximum number of events read from the storage at
+// once by a catch-up consumer.
+const readBatchSize = 100
+
+// consumerChannelAdapterBuilder builds catch-up consumer channels over the
+// streams of the event store.
+type consumerChannelAdapterBuilder struct {
+	*adapter.InboundChannelAdapterBuilder[*Event]
+	connectionReferenceName string
+	consumerName            string
+	fromPosition            int64
+	pollInterval            time.Duration
+	checkpointStore         endpoint.CheckpointStore
+}
+
+// inboundChannelAdapter replays the events of the selected streams from a
+// global position and then follows the events appended to them, polling the
+// storage. With a checkpoint store, the position of the last processed event
+// is saved on every commit and the consumption resumes from it.
+type inboundChannelAdapter struct {
+	store             Store
+	streamId          string
+	filter            StreamFilter
+	pollInterval      time.Duration
+	messageTranslator adapter.InboundChannelMessageTranslator[*Event]
+	checkpointStore   endpoint.CheckpointStore
+	checkpointName    string
+	checkpointLoaded  bool
+	mu                sync.Mutex
+	nextPosition      int64
+	committedPosition int64
+	pending           []*Event
+	ctx               context.Context
+	cancelCtx         context.CancelFunc
+}
+
+// NewConsumerChannelAdapterBuilder creates a new event store catch-up
+// consumer channel adapter builder instance. The consumer replays the
+// streams through the regular handler pipeline, routed by the route header
+// of each event, and keeps following them. Events are delivered in global
+// position order, so the consumer should run with a single processor.
+//
+// Parameters:
+//   - connectionReferenceName: reference name for the event store connection
+//   - streamId: the streams to consume: the id of a single stream, i.e. the
+//     aggregate id, AllStreams or the CategoryStream of a category
+//   - consumerName: the consumer name identifier, naming its checkpoint
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: configured builder instance
+func NewConsumerChannelAdapterBuilder(
+	connectionReferenceName string,
+	streamId string,
+	consumerName string,
+) *consumerChannelAdapterBuilder {
+	return &consumerChannelAdapterBuilder{
+		InboundChannelAdapterBuilder: adapter.NewInboundChannelAdapterBuilder(
+			consumerName,
+			streamId,
+			NewMessageTranslator(),
+		),
+		connectionReferenceName: connectionReferenceName,
+		consumerName:            consumerName,
+		fromPosition:            1,
+		pollInterval:            500 * time.Millisecond,
+	}
+}
+
+// WithFromPosition sets the global position of the first event replayed
+// when the consumer has no checkpoint. Defaults to 1, the beginning of the
+// store.
+//
+// Parameters:
+//   - position: the global position of the first event
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for method chaining
+func (b *consumerChannelAdapterBuilder) WithFromPosition(
+	position int64,
+) *consumerChannelAdapterBuilder {
+	b.fromPosition = max(position, 1)
+	return b
+}
+
+// WithCheckpointStore sets the store keeping the position of the last event
+// processed by the consumer, keyed by the consumer name. The position is
+// saved on every commit and the consumption resumes after it on restart.
+// Without a store the position is kept in memory, and the streams are
+// replayed from WithFromPosition on every start.
+//
+// Parameters:
+//   - store: the checkpoint store
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for method chaining
+func (b *consumerChannelAdapterBuilder) WithCheckpointStore(
+	store endpoint.CheckpointStore,
+) *consumerChannelAdapterBuilder {
+	b.checkpointStore = store
+	return b
+}
+
+// WithPollInterval sets how often the storage is polled for new events once
+// the consumer caught up with the stream. Defaults to 500ms.
+//
+// Parameters:
+//   - interval: the polling interval
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for method chaining
+func (b *consumerChannelAdapterBuilder) WithPollInterval(
+	interval time.Duration,
+) *consumerChannelAdapterBuilder {
+	if interval > 0 {
+		b.pollInterval = interval
+	}
+	return b
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (b *consumerChannelAdapterBuilder) ConnectionReferenceName() string {
+	return b.connectionReferenceName
+}
+
+// Build constructs an event store inbound channel adapter from the
+// dependency container.
+//
+// Parameters:
+//   - container: dependency container containing required components
+//
+// Returns:
+//   - *adapter.InboundChannelAdapter: configured inbound channel adapter
+//   - error: error if connection not found or is invalid
+func (b *consumerChannelAdapterBuilder) Build(
+	container container.Container[any, any],
+) (*adapter.InboundChannelAdapter, error) {
+	conn, err := getConnection(container, b.connectionReferenceName)
+	if err != nil {
+		return nil, fmt.Errorf("[event-store-inbound-channel] %w", err)
+	}
+
+	inboundAdapter := NewInboundChannelAdapter(
+		conn.EventStore().Store(),
+		b.ReferenceName(),
+		b.fromPosition,
+		b.pollInterval,
+		b.MessageTranslator(),
+	)
+	if b.checkpointStore != nil {
+		inboundAdapter.withCheckpointStore(b.checkpointStore, b.consumerName)
+	}
+	return b.InboundChannelAdapterBuilder.BuildInboundAdapter(inboundAdapter)
+}
+
+// NewInboundChannelAdapter creates a new event store inbound channel adapter
+// instance.
+//
+// Parameters:
+//   - store: the event storage
+//   - streamId: the streams to consume: a stream id, AllStreams or a
+//     CategoryStream
+//   - fromPosition: the global position of the first event to deliver
+//   - pollInterval: how often the storage is polled for new events
+//   - messageTranslator: translator for converting events to internal messages
+//
+// Returns:
+//   - *inboundChannelAdapter: configured inbound channel adapter
+func NewInboundChannelAdapter(
+	store Store,
+	streamId string,
+	fromPosition int64,
+	pollInterval time.Duration,
+	messageTranslator adapter.InboundChannelMessageTranslator[*Event],
+) *inboundChannelAdapter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &inboundChannelAdapter{
+		store:             store,
+		streamId:          streamId,
+		filter:            streamFilterOf(streamId),
+		pollInterval:      pollInterval,
+		messageTranslator: messageTranslator,
+		nextPosition:      max(fromPosition, 1),
+		committedPosition: max(fromPosition, 1) - 1,
+		ctx:               ctx,
+		cancelCtx:         cancel,
+	}
+}
+
+// withCheckpointStore sets the store of the consumer checkpoint, loaded on
+// the first read and saved on every commit.
+func (a *inboundChannelAdapter) withCheckpointStore(
+	store endpoint.CheckpointStore,
+	name string,
+) {
+	a.checkpointStore = store
+	a.checkpointName = name
+}
+
+// Name returns the stream consumed by the inbound channel adapter.
+//
+// Returns:
+//   - string: the stream id
+func (a *inboundChannelAdapter) Name() string {
+	return a.streamId
+}
+
+// Receive returns the next event of the streams, blocking until one is
+// appended when the consumer caught up with the streams.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - *message.Message: the next event of the streams
+//   - error: error if the channel is closed, or the storage or the
+//     checkpoint cannot be read
+func (a *inboundChannelAdapter) Receive(ctx context.Context) (*message.Message, error) {
+	for {
+		event, err := a.next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			msg, err := a.messageTranslator.ToMessage(event)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", message.ErrTranslation, err)
+			}
+			return msg, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-a.ctx.Done():
+			return nil, a.ctx.Err()
+		case <-time.After(a.pollInterval):
+		}
+	}
+}
+
+// next returns the next pending event, reading a batch from the storage
+// when none is pending. It returns nil when the streams have no new event.
+func (a *inboundChannelAdapter) next(ctx context.Context) (*Event, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := a.loadCheckpoint(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(a.pending) == 0 {
+		events, err := a.store.ReadAll(ctx, a.filter, a.nextPosition, readBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[event-store-inbound-channel] failed to read stream %s: %w",
+				a.streamId,
+				err,
+			)
+		}
+		a.pending = events
+	}
+	if len(a.pending) == 0 {
+		return nil, nil
+	}
+
+	event := a.pending[0]
+	a.pending = a.pending[1:]
+	a.nextPosition = event.Position + 1
+	return event, nil
+}
+
+// loadCheckpoint resumes the consumption after the saved checkpoint, once.
+// The caller holds the lock.
+func (a *inboundChannelAdapter) loadCheckpoint(ctx context.Context) error {
+	if a.checkpointStore == nil || a.checkpointLoaded {
+		return nil
+	}
+	checkpoint, err := a.checkpointStore.LoadCheckpoint(ctx, a.checkpointName)
+	if err != nil {
+		return fmt.Errorf(
+			"[event-store-inbound-channel] failed to load checkpoint of %s: %w",
+			a.checkpointName,
+			err,
+		)
+	}
+	a.checkpointLoaded = true
+	if checkpoint > 0 {
+		a.nextPosition = checkpoint + 1
+		a.committedPosition = checkpoint
+		a.pending = nil
+	}
+	return nil
+}
+
+// Close stops the event consumption of the channel.
+//
+// Returns:
+//   - error: always nil
+func (a *inboundChannelAdapter) Close() error {
+	a.cancelCtx()
+	return nil
+}
+
+// CommitMessage marks the event as processed, saving its global position as
+// the consumer checkpoint when a checkpoint store is set.
+//
+// Parameters:
+//   - msg: the message to commit
+//
+// Returns:
+//   - error: error if the message was not read from the streams or the
+//     checkpoint cannot be saved
+func (a *inboundChannelAdapter) CommitMessage(msg *message.Message) error {
+	position, err := a.positionOf(msg)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if position <= a.committedPosition {
+		return nil
+	}
+	if a.checkpointStore != nil {
+		err := a.checkpointStore.SaveCheckpoint(
+			context.Background(),
+			a.checkpointName,
+			position,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"[event-store-inbound-channel] failed to save checkpoint of %s: %w",
+				a.checkpointName,
+				err,
+			)
+		}
+	}
+	a.committedPosition = position
+	return nil
+}
+
+// NackMessage settles the event as not processed. With requeue, the streams
+// are replayed again from the event, otherwise the event is skipped.
+//
+// Parameters:
+//   - msg: the message to settle
+//   - requeue: true to redeliver the event, false to skip it
+//
+// Returns:
+//   - error: error if the message was not read from the streams
+func (a *inboundChannelAdapter) NackMessage(msg *message.Message, requeue bool) error {
+	if !requeue {
+		return a.CommitMessage(msg)
+	}
+	position, err := a.positionOf(msg)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	if position < a.nextPosition {
+		a.nextPosition = position
+		a.pending = nil
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// SeekTo restarts the consumption at a global position, overriding the
+// saved checkpoint.
+//
+// Parameters:
+//   - position: the global position of the next event to deliver
+//
+// Returns:
+//   - error: always nil
+func (a *inboundChannelAdapter) SeekTo(position int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.checkpointLoaded = true
+	a.nextPosition = max(position, 1)
+	a.committedPosition = a.nextPosition - 1
+	a.pending = nil
+	return nil
+}
+
+// PositionOf returns the global position of an event read by the channel.
+//
+// Parameters:
+//   - msg: the received message
+//
+// Returns:
+//   - int64: the global position of the event
+//   - error: error if the message was not read from the streams
+func (a *inboundChannelAdapter) PositionOf(msg *message.Message) (int64, error) {
+	return a.positionOf(msg)
+}
+
+// Position returns the global position of the last event processed by the
+// consumer.
+//
+// Returns:
+//   - int64: the global position of the last committed event
+func (a *inboundChannelAdapter) Position() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.committedPosition
+}
+
+// positionOf returns the global position of a message read by the channel.
+func (a *inboundChannelAdapter) positionOf(msg *message.Message) (int64, error) {
+	if event, ok := msg.GetRawMessage().(*Event); ok {
+		return event.Position, nil
+	}
+	position, err := strconv.ParseInt(msg.GetHeader().Get(HeaderPosition), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[event-store-inbound-channel] message %s was not read from stream %s",
+			msg.GetHeader().Get(message.HeaderMessageId),
+			a.streamId,
+		)
+	}
+	return position, nil
+}
@@ -0,0 +1,158 @@
+package eventstore
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an event storage keeping the streams in memory, suited to
+// tests and single-process prototypes.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	streams map[string][]*Event
+	events  []*Event
+}
+
+// NewMemoryStore creates a new in-memory event storage.
+//
+// Returns:
+//   - *MemoryStore: the storage instance
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{streams: map[string][]*Event{}}
+}
+
+// Append stores events at the end of a stream, when the stream is at the
+// expected version.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - streamId: the id of the stream
+//   - expectedVersion: the expected stream version, or AnyVersion
+//   - events: the events to append, in order
+//
+// Returns:
+//   - int64: the stream version after the append
+//   - error: ErrWrongExpectedVersion if the stream is at another version
+func (s *MemoryStore) Append(
+	ctx context.Context,
+	streamId string,
+	expectedVersion int64,
+	events []*Event,
+) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := s.streams[streamId]
+	version := int64(len(stream))
+	if expectedVersion != AnyVersion && expectedVersion != version {
+		return version, wrongExpectedVersion(streamId, version, expectedVersion)
+	}
+
+	recordedAt := time.Now()
+	for _, event := range events {
+		version++
+		stored := *event
+		stored.StreamId = streamId
+		stored.Version = version
+		stored.Position = int64(len(s.events)) + 1
+		stored.Headers = maps.Clone(event.Headers)
+		stored.RecordedAt = recordedAt
+		stream = append(stream, &stored)
+		s.events = append(s.events, &stored)
+	}
+	s.streams[streamId] = stream
+	return version, nil
+}
+
+// Read returns the events of a stream from a version, in order.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - streamId: the id of the stream
+//   - fromVersion: the version of the first event to return
+//   - limit: the maximum number of events to return, zero for all
+//
+// Returns:
+//   - []*Event: the events of the stream
+//   - error: error if the context is done
+func (s *MemoryStore) Read(
+	ctx context.Context,
+	streamId string,
+	fromVersion int64,
+	limit int,
+) ([]*Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stream := s.streams[streamId]
+	start := max(fromVersion, 1) - 1
+	if start >= int64(len(stream)) {
+		return nil, nil
+	}
+	events := stream[start:]
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+
+	return copyEvents(events), nil
+}
+
+// ReadAll returns the events selected by a filter from a global position,
+// in global position order.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - filter: the streams to read, all the streams when empty
+//   - fromPosition: the global position of the first event to return
+//   - limit: the maximum number of events to return, zero for all
+//
+// Returns:
+//   - []*Event: the selected events
+//   - error: error if the context is done
+func (s *MemoryStore) ReadAll(
+	ctx context.Context,
+	filter StreamFilter,
+	fromPosition int64,
+	limit int,
+) ([]*Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []*Event
+	for _, event := range s.events[min(max(fromPosition, 1)-1, int64(len(s.events))):] {
+		if !filter.Matches(event) {
+			continue
+		}
+		events = append(events, event)
+		if limit > 0 && len(events) == limit {
+			break
+		}
+	}
+	return copyEvents(events), nil
+}
+
+// copyEvents returns copies of stored events, so the callers cannot change
+// the storage.
+func copyEvents(events []*Event) []*Event {
+	copies := make([]*Event, len(events))
+	for i, event := range events {
+		stored := *event
+		stored.Headers = maps.Clone(event.Headers)
+		copies[i] = &stored
+	}
+	return copies
+}
@@ -0,0 +1,83 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// streamHeaders holds the headers owned by the event store, never stored
+// with the events.
+var streamHeaders = []string{
+	HeaderStreamId,
+	HeaderStreamVersion,
+	HeaderExpectedVersion,
+	HeaderPosition,
+}
+
+// MessageTranslator translates messages to and from stored events, encoding
+// the payload as JSON like the broker channels do.
+type MessageTranslator struct{}
+
+// NewMessageTranslator creates a new message translator instance.
+//
+// Returns:
+//   - *MessageTranslator: new message translator instance
+func NewMessageTranslator() *MessageTranslator {
+	return &MessageTranslator{}
+}
+
+// FromMessage converts an internal message to an event, without its stream
+// headers, which are set by the storage.
+//
+// Parameters:
+//   - msg: the internal message to be converted
+//
+// Returns:
+//   - *Event: the event
+//   - error: error if payload serialization fails
+func (m *MessageTranslator) FromMessage(msg *message.Message) (*Event, error) {
+	headers := msg.GetHeader().All()
+	for _, key := range streamHeaders {
+		delete(headers, key)
+	}
+	payload, err := json.Marshal(msg.GetPayload())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[event-store-message-translator] payload converter error: %v",
+			err.Error(),
+		)
+	}
+	return &Event{Headers: headers, Payload: payload}, nil
+}
+
+// ToMessage converts an event to an internal message carrying its stream id,
+// version and global position headers.
+//
+// Parameters:
+//   - event: the event to be converted
+//
+// Returns:
+//   - *message.Message: the internal message
+//   - error: error if header conversion fails
+func (m *MessageTranslator) ToMessage(event *Event) (*message.Message, error) {
+	headers := make(map[string]string, len(event.Headers)+3)
+	maps.Copy(headers, event.Headers)
+	headers[HeaderStreamId] = event.StreamId
+	headers[HeaderStreamVersion] = strconv.FormatInt(event.Version, 10)
+	headers[HeaderPosition] = strconv.FormatInt(event.Position, 10)
+
+	messageBuilder, err := message.NewMessageBuilderFromHeaders(headers)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[event-store-message-translator] header converter error: %v",
+			err.Error(),
+		)
+	}
+	messageBuilder.WithPayload(event.Payload)
+	messageBuilder.WithRawMessage(event)
+	return messageBuilder.Build(), nil
+}
@@ -0,0 +1,153 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// publisherChannelAdapterBuilder builds publisher channels appending the
+// published events to their streams.
+type publisherChannelAdapterBuilder struct {
+	*adapter.OutboundChannelAdapterBuilder[*Event]
+	connectionReferenceName string
+}
+
+// outboundChannelAdapter appends each published message to the stream of
+// its streamId header.
+type outboundChannelAdapter struct {
+	eventStore        *EventStore
+	channelName       string
+	messageTranslator adapter.OutboundChannelMessageTranslator[*Event]
+}
+
+// NewPublisherChannelAdapterBuilder creates a new event store publisher
+// channel adapter builder instance. Messages published to the channel must
+// carry the streamId header, and may carry the expectedVersion header for
+// optimistic concurrency.
+//
+// Parameters:
+//   - connectionReferenceName: reference name for the event store connection
+//   - channelName: the publisher channel name
+//
+// Returns:
+//   - *publisherChannelAdapterBuilder: configured builder instance
+func NewPublisherChannelAdapterBuilder(
+	connectionReferenceName string,
+	channelName string,
+) *publisherChannelAdapterBuilder {
+	return &publisherChannelAdapterBuilder{
+		adapter.NewOutboundChannelAdapterBuilder(
+			channelName,
+			channelName,
+			NewMessageTranslator(),
+		),
+		connectionReferenceName,
+	}
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (b *publisherChannelAdapterBuilder) ConnectionReferenceName() string {
+	return b.connectionReferenceName
+}
+
+// Build constructs an event store outbound channel adapter from the
+// dependency container.
+//
+// Parameters:
+//   - container: dependency container containing required components
+//
+// Returns:
+//   - endpoint.OutboundChannelAdapter: configured publisher channel
+//   - error: error if connection not found or is invalid
+func (b *publisherChannelAdapterBuilder) Build(
+	container container.Container[any, any],
+) (endpoint.OutboundChannelAdapter, error) {
+	conn, err := getConnection(container, b.connectionReferenceName)
+	if err != nil {
+		return nil, fmt.Errorf("[event-store-outbound-channel] %w", err)
+	}
+
+	return b.OutboundChannelAdapterBuilder.BuildOutboundAdapter(
+		NewOutboundChannelAdapter(
+			conn.EventStore(),
+			b.ChannelName(),
+			b.MessageTranslator(),
+		),
+	)
+}
+
+// NewOutboundChannelAdapter creates a new event store outbound channel
+// adapter instance.
+//
+// Parameters:
+//   - eventStore: the event store to append to
+//   - channelName: the publisher channel name
+//   - messageTranslator: translator for converting internal messages to events
+//
+// Returns:
+//   - *outboundChannelAdapter: configured outbound channel adapter
+func NewOutboundChannelAdapter(
+	eventStore *EventStore,
+	channelName string,
+	messageTranslator adapter.OutboundChannelMessageTranslator[*Event],
+) *outboundChannelAdapter {
+	return &outboundChannelAdapter{
+		eventStore:        eventStore,
+		channelName:       channelName,
+		messageTranslator: messageTranslator,
+	}
+}
+
+// Name returns the name of the outbound channel adapter.
+//
+// Returns:
+//   - string: the channel name
+func (a *outboundChannelAdapter) Name() string {
+	return a.channelName
+}
+
+// Send appends a message to the stream of its streamId header, checking the
+// expectedVersion header when present.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be appended
+//
+// Returns:
+//   - error: ErrWrongExpectedVersion if the stream is at another version, or
+//     error if the message has no stream id or cannot be translated
+func (a *outboundChannelAdapter) Send(ctx context.Context, msg *message.Message) error {
+	streamId := msg.GetHeader().Get(HeaderStreamId)
+	if streamId == "" {
+		return fmt.Errorf(
+			"[event-store-outbound-channel] message %s has no %s header",
+			msg.GetHeader().Get(message.HeaderMessageId),
+			HeaderStreamId,
+		)
+	}
+	expectedVersion, err := expectedVersionOf(msg)
+	if err != nil {
+		return err
+	}
+
+	event, err := a.messageTranslator.FromMessage(msg)
+	if err != nil {
+		return err
+	}
+	_, err = a.eventStore.Store().Append(
+		ctx,
+		streamId,
+		expectedVersion,
+		[]*Event{event},
+	)
+	return err
+}
@@ -0,0 +1,394 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appendAttempts is the number of times an append is attempted when a
+// concurrent append took the same global positions.
+const appendAttempts = 5
+
+// SQLStore is an event storage keeping the streams in a SQL table, one row
+// per event keyed by stream id and version. The primary key guards the
+// optimistic concurrency across processes, and the unique global position,
+// assigned in sequence within the append transaction, orders the events of
+// all the streams without gaps.
+type SQLStore struct {
+	db          *sql.DB
+	tableName   string
+	placeholder func(position int) string
+}
+
+// SQLStoreOption is a functional option for configuring SQL event storages.
+type SQLStoreOption func(*SQLStore)
+
+// WithTableName sets the table of the events. Defaults to "events".
+//
+// Parameters:
+//   - tableName: the table name
+//
+// Returns:
+//   - SQLStoreOption: configured option function
+func WithTableName(tableName string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.tableName = tableName
+	}
+}
+
+// WithDollarPlaceholders uses numbered placeholders ($1, $2...) in the
+// queries, as required by PostgreSQL drivers. Defaults to "?".
+//
+// Returns:
+//   - SQLStoreOption: configured option function
+func WithDollarPlaceholders() SQLStoreOption {
+	return func(s *SQLStore) {
+		s.placeholder = func(position int) string {
+			return "$" + strconv.Itoa(position)
+		}
+	}
+}
+
+// NewSQLStore creates a new SQL event storage. The table is created by
+// CreateTable, or by the application migrations with the same columns.
+//
+// Parameters:
+//   - db: the database handle
+//   - opts: the storage options
+//
+// Returns:
+//   - *SQLStore: the storage instance
+func NewSQLStore(db *sql.DB, opts ...SQLStoreOption) *SQLStore {
+	store := &SQLStore{
+		db:          db,
+		tableName:   "events",
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// CreateTable creates the table of the events when it does not exist.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - error: error if the statement fails
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (
+			stream_id VARCHAR(255) NOT NULL,
+			version BIGINT NOT NULL,
+			position BIGINT NOT NULL UNIQUE,
+			category VARCHAR(255) NOT NULL,
+			headers TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			recorded_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (stream_id, version)
+		)`,
+		s.tableName,
+	))
+	if err != nil {
+		return fmt.Errorf("[event-store] failed to create table %s: %w", s.tableName, err)
+	}
+	return nil
+}
+
+// Append stores events at the end of a stream in a transaction, when the
+// stream is at the expected version. A concurrent append of the same
+// versions is rejected by the primary key and reported as a wrong expected
+// version, while an append conflicting only on the global positions is
+// attempted again.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - streamId: the id of the stream
+//   - expectedVersion: the expected stream version, or AnyVersion
+//   - events: the events to append, in order
+//
+// Returns:
+//   - int64: the stream version after the append
+//   - error: ErrWrongExpectedVersion if the stream is at another version
+func (s *SQLStore) Append(
+	ctx context.Context,
+	streamId string,
+	expectedVersion int64,
+	events []*Event,
+) (int64, error) {
+	var err error
+	for range appendAttempts {
+		var version int64
+		version, err = s.appendOnce(ctx, streamId, expectedVersion, events)
+		if err == nil || errors.Is(err, ErrWrongExpectedVersion) || ctx.Err() != nil {
+			return version, err
+		}
+	}
+	return 0, err
+}
+
+// appendOnce attempts an append in a transaction, assigning the global
+// positions after the last one stored.
+func (s *SQLStore) appendOnce(
+	ctx context.Context,
+	streamId string,
+	expectedVersion int64,
+	events []*Event,
+) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("[event-store] failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	version, err := s.streamVersion(ctx, tx, streamId)
+	if err != nil {
+		return 0, err
+	}
+	if expectedVersion != AnyVersion && expectedVersion != version {
+		return version, wrongExpectedVersion(streamId, version, expectedVersion)
+	}
+	position, err := s.lastPosition(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	insert := fmt.Sprintf(
+		"INSERT INTO %s (stream_id, version, position, category, headers, payload, recorded_at) VALUES (%s)",
+		s.tableName,
+		s.placeholders(1, 7),
+	)
+	recordedAt := time.Now().UTC()
+	for _, event := range events {
+		version++
+		position++
+		headers, err := json.Marshal(event.Headers)
+		if err != nil {
+			return 0, fmt.Errorf("[event-store] failed to encode headers: %w", err)
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			insert,
+			streamId,
+			version,
+			position,
+			CategoryOf(streamId),
+			string(headers),
+			string(event.Payload),
+			recordedAt,
+		)
+		if err != nil {
+			return 0, s.appendFailure(ctx, streamId, expectedVersion, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, s.appendFailure(ctx, streamId, expectedVersion, err)
+	}
+	return version, nil
+}
+
+// Read returns the events of a stream from a version, in order.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - streamId: the id of the stream
+//   - fromVersion: the version of the first event to return
+//   - limit: the maximum number of events to return, zero for all
+//
+// Returns:
+//   - []*Event: the events of the stream
+//   - error: error if the query fails
+func (s *SQLStore) Read(
+	ctx context.Context,
+	streamId string,
+	fromVersion int64,
+	limit int,
+) ([]*Event, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE stream_id = %s AND version >= %s ORDER BY version",
+		eventColumns,
+		s.tableName,
+		s.placeholder(1),
+		s.placeholder(2),
+	)
+	events, err := s.query(ctx, query, []any{streamId, fromVersion}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[event-store] failed to read stream %s: %w", streamId, err)
+	}
+	return events, nil
+}
+
+// ReadAll returns the events selected by a filter from a global position,
+// in global position order.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - filter: the streams to read, all the streams when empty
+//   - fromPosition: the global position of the first event to return
+//   - limit: the maximum number of events to return, zero for all
+//
+// Returns:
+//   - []*Event: the selected events
+//   - error: error if the query fails
+func (s *SQLStore) ReadAll(
+	ctx context.Context,
+	filter StreamFilter,
+	fromPosition int64,
+	limit int,
+) ([]*Event, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE position >= %s",
+		eventColumns,
+		s.tableName,
+		s.placeholder(1),
+	)
+	args := []any{fromPosition}
+	if filter.StreamId != "" {
+		args = append(args, filter.StreamId)
+		query += " AND stream_id = " + s.placeholder(len(args))
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		query += " AND category = " + s.placeholder(len(args))
+	}
+	events, err := s.query(ctx, query+" ORDER BY position", args, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[event-store] failed to read events: %w", err)
+	}
+	return events, nil
+}
+
+// eventColumns are the columns of the events scanned by query.
+const eventColumns = "stream_id, version, position, headers, payload, recorded_at"
+
+// query runs a query selecting the event columns, with an optional limit.
+func (s *SQLStore) query(
+	ctx context.Context,
+	query string,
+	args []any,
+	limit int,
+) ([]*Event, error) {
+	if limit > 0 {
+		args = append(args, limit)
+		query += " LIMIT " + s.placeholder(len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		var headers, payload string
+		event := &Event{}
+		err := rows.Scan(
+			&event.StreamId,
+			&event.Version,
+			&event.Position,
+			&headers,
+			&payload,
+			&event.RecordedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := json.Unmarshal([]byte(headers), &event.Headers); err != nil {
+			return nil, fmt.Errorf("failed to decode headers: %w", err)
+		}
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// streamVersion returns the version of the last event of a stream, zero
+// when it has no event.
+func (s *SQLStore) streamVersion(
+	ctx context.Context,
+	querier interface {
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	},
+	streamId string,
+) (int64, error) {
+	var version sql.NullInt64
+	err := querier.QueryRowContext(
+		ctx,
+		fmt.Sprintf(
+			"SELECT MAX(version) FROM %s WHERE stream_id = %s",
+			s.tableName,
+			s.placeholder(1),
+		),
+		streamId,
+	).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("[event-store] failed to read version of stream %s: %w", streamId, err)
+	}
+	return version.Int64, nil
+}
+
+// lastPosition returns the global position of the last stored event, zero
+// when the store has no event.
+func (s *SQLStore) lastPosition(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var position sql.NullInt64
+	err := tx.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT MAX(position) FROM %s", s.tableName),
+	).Scan(&position)
+	if err != nil {
+		return 0, fmt.Errorf("[event-store] failed to read the last position: %w", err)
+	}
+	return position.Int64, nil
+}
+
+// appendFailure reports a failed append as a wrong expected version when
+// the stream was changed meanwhile, since the drivers do not share an error
+// for primary key violations.
+func (s *SQLStore) appendFailure(
+	ctx context.Context,
+	streamId string,
+	expectedVersion int64,
+	err error,
+) error {
+	version, versionErr := s.streamVersion(ctx, s.db, streamId)
+	if versionErr == nil && expectedVersion != AnyVersion && version != expectedVersion {
+		return wrongExpectedVersion(streamId, version, expectedVersion)
+	}
+	return fmt.Errorf(
+		"[event-store] failed to append to stream %s: %w",
+		streamId,
+		errors.Join(err, versionErr),
+	)
+}
+
+// placeholders returns the placeholders of count arguments, separated by
+// commas, starting at the given position.
+func (s *SQLStore) placeholders(position int, count int) string {
+	placeholders := make([]string, count)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(position + i)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// wrongExpectedVersion returns the error of an append to a stream which is
+// not at the expected version.
+func wrongExpectedVersion(streamId string, version int64, expectedVersion int64) error {
+	return fmt.Errorf(
+		"%w: stream %s is at version %d, expected %d",
+		ErrWrongExpectedVersion,
+		streamId,
+		version,
+		expectedVersion,
+	)
+}
@@ -0,0 +1,137 @@
+package eventstore_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/channel/eventstore"
+	_ "modernc.org/sqlite"
+)
+
+func newSQLStore(t *testing.T) *eventstore.SQLStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	store := eventstore.NewSQLStore(db)
+	if err := store.CreateTable(context.Background()); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	return store
+}
+
+func storedEvents(count int) []*eventstore.Event {
+	events := make([]*eventstore.Event, count)
+	for i := range events {
+		events[i] = &eventstore.Event{
+			Headers: map[string]string{"route": "order.placed"},
+			Payload: []byte(`{"id":"order"}`),
+		}
+	}
+	return events
+}
+
+func TestSQLStore_Append(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newSQLStore(t)
+
+	version, err := store.Append(ctx, "order-1", eventstore.NoStream, storedEvents(2))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if version != 2 {
+		t.Errorf("expected version 2, got %d", version)
+	}
+
+	_, err = store.Append(ctx, "order-1", 1, storedEvents(1))
+	if !errors.Is(err, eventstore.ErrWrongExpectedVersion) {
+		t.Errorf("expected ErrWrongExpectedVersion, got %v", err)
+	}
+
+	version, err = store.Append(ctx, "order-1", eventstore.AnyVersion, storedEvents(1))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if version != 3 {
+		t.Errorf("expected version 3, got %d", version)
+	}
+}
+
+func TestSQLStore_Read(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newSQLStore(t)
+	_, _ = store.Append(ctx, "order-1", eventstore.NoStream, storedEvents(3))
+	_, _ = store.Append(ctx, "order-2", eventstore.NoStream, storedEvents(1))
+
+	events, err := store.Read(ctx, "order-1", 2, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	event := events[0]
+	if event.StreamId != "order-1" || event.Version != 2 || event.Position != 2 {
+		t.Errorf("expected order-1 version 2 at position 2, got %s version %d at position %d",
+			event.StreamId, event.Version, event.Position)
+	}
+	if event.Headers["route"] != "order.placed" || string(event.Payload) != `{"id":"order"}` {
+		t.Errorf("expected the stored headers and payload, got %v %s", event.Headers, event.Payload)
+	}
+
+	events, _ = store.Read(ctx, "order-1", 1, 1)
+	if len(events) != 1 {
+		t.Errorf("expected the limit to be applied, got %d events", len(events))
+	}
+}
+
+func TestSQLStore_ReadAll(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newSQLStore(t)
+	for _, streamId := range []string{"order-1", "customer-1", "order-2", "order-1"} {
+		_, err := store.Append(ctx, streamId, eventstore.AnyVersion, storedEvents(1))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	cases := []struct {
+		name      string
+		filter    eventstore.StreamFilter
+		from      int64
+		limit     int
+		positions []int64
+	}{
+		{"all the streams", eventstore.StreamFilter{}, 1, 0, []int64{1, 2, 3, 4}},
+		{"from a position", eventstore.StreamFilter{}, 3, 0, []int64{3, 4}},
+		{"with a limit", eventstore.StreamFilter{}, 1, 2, []int64{1, 2}},
+		{"a category", eventstore.StreamFilter{Category: "order"}, 1, 0, []int64{1, 3, 4}},
+		{"a stream", eventstore.StreamFilter{StreamId: "order-1"}, 2, 0, []int64{4}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			events, err := store.ReadAll(ctx, c.filter, c.from, c.limit)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(events) != len(c.positions) {
+				t.Fatalf("expected %d events, got %d", len(c.positions), len(events))
+			}
+			for i, event := range events {
+				if event.Position != c.positions[i] {
+					t.Errorf("expected position %d, got %d", c.positions[i], event.Position)
+				}
+			}
+		})
+	}
+}
@@ -9,11 +9,18 @@
 // - Connection pooling and reuse
 // - Error handling and connection lifecycle
 // - Configuration management for Kafka clients
+// - Consumer group membership and partition assignment introspection
+// - Topic provisioning through the admin API
+// - Replay of consumer groups from a point in time or an offset
 package kafka
 
 import (
 	"crypto/tls"
+	"errors"
+	"fmt"
 
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -119,3 +126,29 @@ func (c *connection) ReferenceName() string {
 func (c *connection) Disconnect() error {
 	return nil
 }
+
+// getConnection returns the Kafka connection registered in the container.
+func getConnection(
+	c container.Container[any, any],
+	connectionReferenceName string,
+) (*connection, error) {
+	conn, err := container.Resolve[*connection, any, any](
+		c,
+		adapter.ConnectionKey(connectionReferenceName),
+	)
+	if errors.Is(err, container.ErrItemNotFound) {
+		return nil, fmt.Errorf(
+			"no Kafka connection is registered as %s: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"connection %s is not a valid Kafka connection: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	return conn, nil
+}
@@ -0,0 +1,26 @@
+package kafka
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+)
+
+func TestGetConnection(t *testing.T) {
+	t.Parallel()
+	c := container.NewGenericContainer[any, any]()
+	c.Set(adapter.ConnectionKey("rabbitmq"), "not a kafka connection")
+
+	_, err := getConnection(c, "missing")
+	if !errors.Is(err, container.ErrItemNotFound) || !strings.Contains(err.Error(), "is registered") {
+		t.Errorf("expected a missing connection error, got %v", err)
+	}
+
+	_, err = getConnection(c, "rabbitmq")
+	if !errors.Is(err, container.ErrItemType) || !strings.Contains(err.Error(), "not a valid Kafka connection") {
+		t.Errorf("expected a wrong connection type error, got %v", err)
+	}
+}
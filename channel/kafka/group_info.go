@@ -0,0 +1,266 @@
+// Package kafka provides Kafka consumer group introspection and consumer lag
+// functionality.
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/otel"
+	"github.com/segmentio/kafka-go"
+)
+
+// GroupInfo holds the broker-side state of a Kafka consumer group.
+type GroupInfo struct {
+	GroupID string
+	State   string
+	Members []GroupMember
+}
+
+// GroupMember holds the membership data and partition assignments of a
+// single consumer group member.
+type GroupMember struct {
+	MemberID    string
+	ClientID    string
+	ClientHost  string
+	Assignments map[string][]int
+}
+
+// ConsumerGroupInfo describes the consumer group used by the given consumer,
+// returning its state, members and current partition assignments. It is
+// intended to help debugging rebalance issues and uneven load.
+//
+// The kafka-go reader does not expose the group generation, so the group
+// state reported by the broker is returned instead.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - consumerName: the consumer name used when building the consumer channel
+//
+// Returns:
+//   - *GroupInfo: the consumer group state and membership
+//   - error: error if the connection is not established or the describe
+//     request fails
+func (c *connection) ConsumerGroupInfo(
+	ctx context.Context,
+	consumerName string,
+) (*GroupInfo, error) {
+	return c.DescribeGroup(ctx, fmt.Sprintf("%s:%s", c.name, consumerName))
+}
+
+// DescribeGroup describes a Kafka consumer group by its group id through the
+// Kafka admin API.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - groupID: the consumer group id
+//
+// Returns:
+//   - *GroupInfo: the consumer group state and membership
+//   - error: error if the connection is not established or the describe
+//     request fails
+func (c *connection) DescribeGroup(
+	ctx context.Context,
+	groupID string,
+) (*GroupInfo, error) {
+	if c.transport == nil {
+		return nil, fmt.Errorf(
+			"[kafka-group-info] connection %s is not established",
+			c.name,
+		)
+	}
+
+	client := &kafka.Client{
+		Addr:      kafka.TCP(c.host...),
+		Transport: c.transport,
+	}
+	res, err := client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{
+		GroupIDs: []string{groupID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[kafka-group-info] group %s could not be described: %s",
+			groupID,
+			err.Error(),
+		)
+	}
+
+	if len(res.Groups) == 0 {
+		return nil, fmt.Errorf("[kafka-group-info] group %s not found", groupID)
+	}
+
+	group := res.Groups[0]
+	if group.Error != nil {
+		return nil, fmt.Errorf(
+			"[kafka-group-info] group %s could not be described: %s",
+			groupID,
+			group.Error.Error(),
+		)
+	}
+
+	info := &GroupInfo{
+		GroupID: group.GroupID,
+		State:   group.GroupState,
+		Members: make([]GroupMember, 0, len(group.Members)),
+	}
+	for _, member := range group.Members {
+		assignments := make(map[string][]int)
+		for _, topic := range member.MemberAssignments.Topics {
+			assignments[topic.Topic] = topic.Partitions
+		}
+		info.Members = append(info.Members, GroupMember{
+			MemberID:    member.MemberID,
+			ClientID:    member.ClientID,
+			ClientHost:  member.ClientHost,
+			Assignments: assignments,
+		})
+	}
+	return info, nil
+}
+
+// lagClient is the part of the Kafka client reading the lag of a consumer
+// group.
+type lagClient interface {
+	Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+	OffsetFetch(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error)
+}
+
+// Backlog returns the consumer lag of the adapter: the messages of its topics
+// not yet committed by its consumer group, or not yet read by the reader when
+// it is not part of a consumer group. A consumer of group topics reports the
+// lag summed over every topic.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - int64: the consumer lag
+//   - error: error if the topic offsets cannot be fetched
+func (a *inboundChannelAdapter) Backlog(ctx context.Context) (int64, error) {
+	config := a.consumer.Config()
+	if config.GroupID == "" {
+		return a.consumer.ReadLag(ctx)
+	}
+
+	transport := &kafka.Transport{}
+	if config.Dialer != nil {
+		transport.TLS = config.Dialer.TLS
+		transport.SASL = config.Dialer.SASLMechanism
+		transport.ClientID = config.Dialer.ClientID
+	}
+	client := &kafka.Client{
+		Addr:      kafka.TCP(config.Brokers...),
+		Transport: transport,
+	}
+	defer transport.CloseIdleConnections()
+
+	topics := config.GroupTopics
+	if len(topics) == 0 {
+		topics = []string{config.Topic}
+	}
+	return groupLag(ctx, client, config.GroupID, topics)
+}
+
+// groupLag returns the messages of the topics not yet committed by a consumer
+// group.
+func groupLag(
+	ctx context.Context,
+	client lagClient,
+	groupID string,
+	topics []string,
+) (int64, error) {
+	var lag int64
+	for _, topic := range topics {
+		messages, err := topicLag(ctx, client, groupID, topic)
+		if err != nil {
+			return 0, err
+		}
+		lag += messages
+	}
+	return lag, nil
+}
+
+// topicLag returns the messages of a topic not yet committed by a consumer
+// group.
+func topicLag(
+	ctx context.Context,
+	client lagClient,
+	groupID string,
+	topic string,
+) (int64, error) {
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{
+		Topics: []string{topic},
+	})
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[kafka-consumer-lag] topic %s could not be described: %s",
+			topic,
+			err.Error(),
+		)
+	}
+	if len(metadata.Topics) == 0 {
+		return 0, fmt.Errorf("[kafka-consumer-lag] topic %s not found", topic)
+	}
+
+	partitions := []int{}
+	offsetRequests := []kafka.OffsetRequest{}
+	for _, partition := range metadata.Topics[0].Partitions {
+		partitions = append(partitions, partition.ID)
+		offsetRequests = append(offsetRequests,
+			kafka.FirstOffsetOf(partition.ID),
+			kafka.LastOffsetOf(partition.ID),
+		)
+	}
+
+	offsets, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: offsetRequests},
+	})
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[kafka-consumer-lag] offsets of topic %s could not be listed: %s",
+			topic,
+			err.Error(),
+		)
+	}
+
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: groupID,
+		Topics:  map[string][]int{topic: partitions},
+	})
+	if err == nil {
+		err = committed.Error
+	}
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[kafka-consumer-lag] offsets of group %s could not be fetched: %s",
+			groupID,
+			err.Error(),
+		)
+	}
+
+	committedOffsets := map[int]int64{}
+	for _, partition := range committed.Topics[topic] {
+		committedOffsets[partition.Partition] = partition.CommittedOffset
+	}
+
+	var lag int64
+	for _, partition := range offsets.Topics[topic] {
+		offset, ok := committedOffsets[partition.Partition]
+		if !ok || offset < partition.FirstOffset {
+			offset = partition.FirstOffset
+		}
+		if partition.LastOffset > offset {
+			lag += partition.LastOffset - offset
+		}
+	}
+	return lag, nil
+}
+
+// MessagingSystem returns the messaging system of the adapter.
+//
+// Returns:
+//   - otel.MessageSystemType: the Kafka messaging system
+func (a *inboundChannelAdapter) MessagingSystem() otel.MessageSystemType {
+	return otel.MessageSystemTypeKafka
+}
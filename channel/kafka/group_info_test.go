@@ -0,0 +1,64 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeLagClient is a lag client over the last and committed offsets of the
+// single partition of each topic.
+type fakeLagClient struct {
+	last      map[string]int64
+	committed map[string]int64
+}
+
+func (f *fakeLagClient) Metadata(
+	ctx context.Context,
+	req *kafka.MetadataRequest,
+) (*kafka.MetadataResponse, error) {
+	topic := req.Topics[0]
+	return &kafka.MetadataResponse{Topics: []kafka.Topic{{
+		Name:       topic,
+		Partitions: []kafka.Partition{{Topic: topic, ID: 0}},
+	}}}, nil
+}
+
+func (f *fakeLagClient) ListOffsets(
+	ctx context.Context,
+	req *kafka.ListOffsetsRequest,
+) (*kafka.ListOffsetsResponse, error) {
+	res := &kafka.ListOffsetsResponse{Topics: map[string][]kafka.PartitionOffsets{}}
+	for topic := range req.Topics {
+		res.Topics[topic] = []kafka.PartitionOffsets{{LastOffset: f.last[topic]}}
+	}
+	return res, nil
+}
+
+func (f *fakeLagClient) OffsetFetch(
+	ctx context.Context,
+	req *kafka.OffsetFetchRequest,
+) (*kafka.OffsetFetchResponse, error) {
+	res := &kafka.OffsetFetchResponse{Topics: map[string][]kafka.OffsetFetchPartition{}}
+	for topic := range req.Topics {
+		res.Topics[topic] = []kafka.OffsetFetchPartition{{CommittedOffset: f.committed[topic]}}
+	}
+	return res, nil
+}
+
+func TestGroupLag_SumsEveryTopic(t *testing.T) {
+	t.Parallel()
+	client := &fakeLagClient{
+		last:      map[string]int64{"orders": 10, "payments": 7},
+		committed: map[string]int64{"orders": 4, "payments": 5},
+	}
+	lag, err := groupLag(context.Background(), client, "kafka:billing",
+		[]string{"orders", "payments"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lag != 8 {
+		t.Errorf("expected the lag of both topics, got %d", lag)
+	}
+}
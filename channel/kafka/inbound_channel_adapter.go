@@ -8,17 +8,27 @@
 // - Kafka consumer integration for message consumption
 // - Message translation between Kafka and internal formats
 // - Asynchronous message processing with context support
-// - Graceful shutdown and resource cleanup
+// - Batched commits of the highest contiguous processed offsets
+// - Partition-aware concurrency with per-partition order and commits
+// - Replies published before the offset commit of their requests
+// - Graceful and idempotent shutdown and resource cleanup
+// - Terminal consumption failures reported through Err
 package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 	"github.com/jeffersonbrasilino/gomes/otel"
 	"github.com/segmentio/kafka-go"
 )
@@ -30,12 +40,18 @@ type consumerChannelAdapterBuilder struct {
 	connectionReferenceName string
 	consumerName            string
 	kafkaConsumerConfig     *kafka.ReaderConfig
+	commitEvery             int
+	commitInterval          time.Duration
+	rebalanceListener       *RebalanceListener
+	topicSpec               *TopicSpec
+	partitionConcurrency    bool
+	atomicReply             bool
 }
 
 // inboundChannelAdapter implements the InboundChannelAdapter interface for Kafka,
 // providing message consumption capabilities through a Kafka consumer.
 type inboundChannelAdapter struct {
-	consumer          *kafka.Reader
+	consumer          messageReader
 	topic             string
 	messageTranslator adapter.InboundChannelMessageTranslator[*kafka.Message]
 	messageChannel    chan *message.Message
@@ -43,6 +59,12 @@ type inboundChannelAdapter struct {
 	ctx               context.Context
 	cancelCtx         context.CancelFunc
 	otelTrace         otel.OtelTrace
+	otelMetrics       otel.OtelMetrics
+	offsetCommitter   *offsetCommitter
+	done              chan struct{}
+	closeOnce         sync.Once
+	mu                sync.Mutex
+	err               error
 }
 
 // NewConsumerChannelAdapterBuilder creates a new Kafka consumer channel
@@ -69,12 +91,20 @@ func NewConsumerChannelAdapterBuilder(
 		connectionReferenceName,
 		consumerName,
 		&kafka.ReaderConfig{},
+		0,
+		0,
+		nil,
+		nil,
+		false,
+		false,
 	}
 	return builder
 }
 
 // WithGroupTopics sets the group topics for the Kafka consumer.
-// This allows the consumer to subscribe to multiple topics at once.
+// This allows the consumer to subscribe to multiple topics at once, the
+// channel topic is always consumed too. The topic of every received message
+// is recorded in the sourceTopic header.
 //
 // Parameters:
 //   - groupTopics: list of topics to subscribe to
@@ -209,8 +239,10 @@ func (b *consumerChannelAdapterBuilder) WithHeartbeatInterval(
 	return b
 }
 
-// WithCommitInterval sets the commit interval for the Kafka consumer.
-// This controls how often offsets are committed to Kafka.
+// WithCommitInterval batches the offset commits of the Kafka consumer,
+// committing the highest contiguous processed offset of each partition at
+// this interval instead of committing each acknowledged message. Messages
+// processed after the last commit are redelivered after a restart.
 //
 // Parameters:
 //   - commitInterval: interval between offset commits
@@ -220,7 +252,24 @@ func (b *consumerChannelAdapterBuilder) WithHeartbeatInterval(
 func (b *consumerChannelAdapterBuilder) WithCommitInterval(
 	commitInterval time.Duration,
 ) *consumerChannelAdapterBuilder {
-	b.kafkaConsumerConfig.CommitInterval = commitInterval
+	b.commitInterval = commitInterval
+	return b
+}
+
+// WithCommitEvery batches the offset commits of the Kafka consumer,
+// committing the highest contiguous processed offset of each partition every
+// n acknowledged messages instead of committing each one. It can be combined
+// with WithCommitInterval to bound how long offsets stay uncommitted.
+//
+// Parameters:
+//   - n: acknowledged messages between offset commits
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithCommitEvery(
+	n int,
+) *consumerChannelAdapterBuilder {
+	b.commitEvery = n
 	return b
 }
 
@@ -285,6 +334,131 @@ func (b *consumerChannelAdapterBuilder) WithRebalanceTimeout(
 	return b
 }
 
+// WithGroupBalancers sets the partition assignment strategies of the
+// consumer group, in priority order, e.g. kafka.RoundRobinGroupBalancer or
+// kafka.RackAffinityGroupBalancer. Defaults to range and round robin.
+//
+// The kafka-go client implements only the eager rebalance protocol, so
+// cooperative-sticky assignment and static membership are not available.
+//
+// Parameters:
+//   - balancers: the group balancers in priority order
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithGroupBalancers(
+	balancers ...kafka.GroupBalancer,
+) *consumerChannelAdapterBuilder {
+	b.kafkaConsumerConfig.GroupBalancers = balancers
+	return b
+}
+
+// WithTopicSpec declares the consumed topic, creating it on Start when
+// missing, so development environments bootstrap themselves.
+//
+// Parameters:
+//   - spec: the topic specification
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithTopicSpec(
+	spec TopicSpec,
+) *consumerChannelAdapterBuilder {
+	b.topicSpec = &spec
+	return b
+}
+
+// WithRebalanceListener sets a listener notified when partitions are
+// assigned to or revoked from the consumer. The consumer joins its group
+// through the group generations of the kafka-go client, so the revocation is
+// notified before the member rejoins the group: the listener can flush the
+// state of the revoked partitions before another member consumes them.
+//
+// The kafka-go client implements only the eager rebalance protocol, so every
+// partition is revoked on each rebalance; static membership and
+// cooperative-sticky assignment are not available.
+//
+// Parameters:
+//   - listener: the rebalance listener
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithRebalanceListener(
+	listener RebalanceListener,
+) *consumerChannelAdapterBuilder {
+	b.rebalanceListener = &listener
+	return b
+}
+
+// WithPartitionConcurrency consumes the assigned partitions concurrently
+// instead of through a single reader: one fetch loop runs per partition and
+// the messages of a partition are processed in order by the same consumer
+// processor, while different partitions are processed in parallel. Offsets
+// are committed per partition, so use it with WithAmountOfProcessors on the
+// consumer to scale up to the amount of assigned partitions.
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithPartitionConcurrency() *consumerChannelAdapterBuilder {
+	b.partitionConcurrency = true
+	b.WithOrderingKey(partitionOrderingKey)
+	return b
+}
+
+// WithDynamicReplyChannels publishes the replies addressed to topics with no
+// registered publisher channel, creating their publisher channels on the
+// connection of the consumer on first use. Only the topics matching one of
+// the allowed patterns are created.
+//
+// Parameters:
+//   - allowedPatterns: path.Match patterns of the reply topics allowed to be
+//     created, e.g. "replies.*"
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithDynamicReplyChannels(
+	allowedPatterns ...string,
+) *consumerChannelAdapterBuilder {
+	connectionReferenceName := b.connectionReferenceName
+	b.WithReplyChannelFactory(
+		func(
+			container container.Container[any, any],
+			topicName string,
+		) (message.PublisherChannel, error) {
+			publisher, err := NewPublisherChannelAdapterBuilder(
+				connectionReferenceName,
+				topicName,
+			).Build(container)
+			if err != nil {
+				return nil, err
+			}
+			return publisher.(message.PublisherChannel), nil
+		},
+		allowedPatterns...,
+	)
+	return b
+}
+
+// WithAtomicReply links the replies of the consumer to its offset commits,
+// preventing duplicated replies after crashes. Kafka commits offsets
+// cumulatively, so the consumer tracks its offsets: a message is committed
+// only after its reply is published. A failed reply is retried a few times;
+// when it keeps failing, the message is left uncommitted and the consumer
+// stops, so the message is redelivered once the consumer is restarted.
+// Replies carry an id derived from the id of
+// their request, so the replies duplicated by a crash between the reply and
+// the commit can be discarded by the requester, e.g. with WithDeduplication
+// on its reply consumer. Offsets are committed every processed message
+// unless WithCommitEvery or WithCommitInterval are set.
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder instance for chaining
+func (b *consumerChannelAdapterBuilder) WithAtomicReply() *consumerChannelAdapterBuilder {
+	b.InboundChannelAdapterBuilder.WithAtomicReply()
+	b.atomicReply = true
+	return b
+}
+
 // WithJoinGroupBackoff sets the join group backoff for the Kafka consumer.
 // This controls the initial backoff time for retrying group joins.
 //
@@ -406,6 +580,15 @@ func (b *consumerChannelAdapterBuilder) WithOffsetOutOfRangeError(
 	return b
 }
 
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (c *consumerChannelAdapterBuilder) ConnectionReferenceName() string {
+	return c.connectionReferenceName
+}
+
 // Build constructs a Kafka inbound channel adapter from the dependency container.
 //
 // Parameters:
@@ -417,33 +600,88 @@ func (b *consumerChannelAdapterBuilder) WithOffsetOutOfRangeError(
 func (c *consumerChannelAdapterBuilder) Build(
 	container container.Container[any, any],
 ) (*adapter.InboundChannelAdapter, error) {
-	con, err := container.Get(c.connectionReferenceName)
-
+	conn, err := getConnection(container, c.connectionReferenceName)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"[kafka-inbound-channel] connection %s does not exist",
-			c.connectionReferenceName,
-		)
+		return nil, fmt.Errorf("[kafka-inbound-channel] %w", err)
 	}
-
-	conn, ok := con.(*connection)
-	if !ok {
-		return nil, fmt.Errorf(
-			"[kafka-outbound-channel] connection %s is not a valid Kafka connection",
-			c.connectionReferenceName,
-		)
+	if err := provisionTopic(conn, c.ReferenceName(), c.topicSpec); err != nil {
+		return nil, err
 	}
 	c.kafkaConsumerConfig.Brokers = conn.getHost()
 	c.kafkaConsumerConfig.Topic = c.ReferenceName()
+	if len(c.kafkaConsumerConfig.GroupTopics) > 0 &&
+		!slices.Contains(c.kafkaConsumerConfig.GroupTopics, c.ReferenceName()) {
+		c.kafkaConsumerConfig.GroupTopics = append(
+			[]string{c.ReferenceName()},
+			c.kafkaConsumerConfig.GroupTopics...,
+		)
+	}
 	c.kafkaConsumerConfig.GroupID = fmt.Sprintf("%s:%s", c.connectionReferenceName, c.consumerName)
 	c.kafkaConsumerConfig.Dialer = conn.getDialer()
-
-	consumer := kafka.NewReader(*c.kafkaConsumerConfig)
-	adapter := NewInboundChannelAdapter(consumer, c.ReferenceName(), c.MessageTranslator())
-	return c.InboundChannelAdapterBuilder.BuildInboundAdapter(adapter), nil
+	trackOffsets := c.tracksOffsets()
+	var consumer messageReader
+	var committer *offsetCommitter
+	if !c.partitionConcurrency && !trackOffsets && c.rebalanceListener == nil {
+		consumer = kafka.NewReader(*c.kafkaConsumerConfig)
+	} else {
+		// the tracked offsets are discarded and the rebalance listener is
+		// notified on revocation, which only the group generations of the
+		// partitioned consumer expose
+		group, err := newPartitionedConsumer(*c.kafkaConsumerConfig)
+		if err != nil {
+			return nil, err
+		}
+		if trackOffsets {
+			committer = c.buildOffsetCommitter(group)
+		}
+		if c.rebalanceListener != nil {
+			group.addRebalanceListener(*c.rebalanceListener)
+		}
+		consumer = group
+	}
+	adapter := newInboundChannelAdapter(
+		consumer,
+		c.ReferenceName(),
+		c.MessageTranslator(),
+		committer,
+	)
+	inbound, err := c.InboundChannelAdapterBuilder.BuildInboundAdapter(adapter)
+	if err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("[kafka-inbound-channel] %w", err)
+	}
+	return inbound, nil
+}
+
+// tracksOffsets reports whether the consumer tracks its offsets. Kafka
+// commits offsets cumulatively, so unless every message is committed right
+// after its processing, the offsets are tracked and a message left
+// uncommitted, e.g. failed with handler.AckOnSuccess, is not committed past
+// by the following ones.
+func (c *consumerChannelAdapterBuilder) tracksOffsets() bool {
+	return c.commitEvery > 0 || c.commitInterval > 0 || c.atomicReply ||
+		c.AckMode() != handler.AckAuto
+}
+
+// buildOffsetCommitter creates the offset committer of the consumer,
+// discarding the offsets of the revoked partitions.
+func (c *consumerChannelAdapterBuilder) buildOffsetCommitter(
+	consumer *partitionedConsumer,
+) *offsetCommitter {
+	every := c.commitEvery
+	if every <= 0 && c.commitInterval <= 0 {
+		// uncommitted messages must hold back the commits of their partition
+		every = 1
+	}
+	committer := newOffsetCommitter(consumer, every, c.commitInterval)
+	consumer.addRebalanceListener(RebalanceListener{
+		OnPartitionsRevoked: func(_ context.Context, partitions map[string][]int) {
+			committer.Revoke(partitions)
+		},
+	})
+	return committer
 }
 
-
 // NewInboundChannelAdapter creates a new Kafka inbound channel adapter instance.
 //
 // Parameters:
@@ -457,6 +695,17 @@ func NewInboundChannelAdapter(
 	consumer *kafka.Reader,
 	topic string,
 	messageTranslator adapter.InboundChannelMessageTranslator[*kafka.Message],
+) *inboundChannelAdapter {
+	return newInboundChannelAdapter(consumer, topic, messageTranslator, nil)
+}
+
+// newInboundChannelAdapter creates a new Kafka inbound channel adapter
+// instance, batching its offset commits when an offset committer is given.
+func newInboundChannelAdapter(
+	consumer messageReader,
+	topic string,
+	messageTranslator adapter.InboundChannelMessageTranslator[*kafka.Message],
+	committer *offsetCommitter,
 ) *inboundChannelAdapter {
 	ctx, cancel := context.WithCancel(context.Background())
 	adp := &inboundChannelAdapter{
@@ -468,11 +717,27 @@ func NewInboundChannelAdapter(
 		ctx:               ctx,
 		cancelCtx:         cancel,
 		otelTrace:         otel.InitTrace("kafka-inbound-channel-adapter"),
+		otelMetrics:       otel.InitMetrics("kafka-inbound-channel-adapter"),
+		offsetCommitter:   committer,
+		done:              make(chan struct{}),
+	}
+	if committer != nil {
+		go committer.Run(ctx)
 	}
 	go adp.subscribeOnTopic()
 	return adp
 }
 
+// partitionOrderingKey returns the topic and partition a message was read
+// from, keeping the order of the messages of a partition.
+func partitionOrderingKey(msg *message.Message) string {
+	raw, ok := msg.GetRawMessage().(*kafka.Message)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", raw.Topic, raw.Partition)
+}
+
 // Name returns the topic name of the Kafka inbound channel adapter.
 //
 // Returns:
@@ -496,59 +761,121 @@ func (a *inboundChannelAdapter) Receive(ctx context.Context) (*message.Message,
 	case <-a.ctx.Done():
 		return nil, a.ctx.Err()
 	case msg := <-a.messageChannel:
+		topic := msg.GetHeader().Get(message.HeaderSourceTopic)
+		if topic == "" {
+			topic = a.topic
+		}
+		a.otelMetrics.AddConsumed(ctx, otel.MessageSystemTypeKafka, topic, msg)
 		return msg, nil
 	case err := <-a.errorChannel:
 		return nil, err
+	case <-a.done:
+		if err := a.Err(); err != nil {
+			return nil, err
+		}
+		return nil, context.Canceled
 	}
 }
 
+// Err returns the terminal failure which stopped the consumption of the
+// topic, e.g. the consumer closed by the broker client. Receive returns this
+// error once the consumption has stopped.
+//
+// Returns:
+//   - error: the terminal failure, nil while consuming or after Close
+func (a *inboundChannelAdapter) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
 // Close gracefully closes the Kafka inbound channel adapter and stops
-// message consumption.
+// message consumption, waiting for the subscription to end. Closing more
+// than once has no effect.
 //
 // Returns:
-//   - error: error if closing fails (typically nil)
+//   - error: error if closing the consumer fails
 func (a *inboundChannelAdapter) Close() error {
-	a.cancelCtx()
-	a.consumer.Close()
-	close(a.messageChannel)
-	close(a.errorChannel)
-	return nil
+	var err error
+	a.closeOnce.Do(func() {
+		a.cancelCtx()
+		if a.offsetCommitter != nil {
+			if flushErr := a.offsetCommitter.Flush(context.Background()); flushErr != nil {
+				slog.Error("[kafka-inbound-channel] failed to commit offsets on close",
+					"reason", flushErr.Error(),
+				)
+			}
+		}
+		err = a.consumer.Close()
+		<-a.done
+	})
+	return err
 }
 
 // subscribeOnTopic subscribes to the Kafka topic and processes incoming messages.
 // This method runs in a separate goroutine and continuously polls for messages,
 // translating them to the internal message format and sending them to the
-// message channel.
+// message channel. Every send is guarded by the adapter context, so closing
+// the adapter never blocks on, nor panics in, the subscription.
 func (a *inboundChannelAdapter) subscribeOnTopic() {
+	defer close(a.done)
+
 	for {
-		select {
-		case <-a.ctx.Done():
-			return
-		default:
-		}
 		msg, err := a.consumer.FetchMessage(a.ctx)
-
 		if err != nil {
-			if err == context.Canceled {
+			if a.ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, io.EOF) || errors.Is(err, kafka.ErrGroupClosed) {
+				a.fail(fmt.Errorf(
+					"[kafka-inbound-channel] consumer of topic %s closed: %w",
+					a.topic,
+					err,
+				))
 				return
 			}
-			a.errorChannel <- err
+			a.sendError(err)
+			continue
 		}
 
-		message, translateErr := a.messageTranslator.ToMessage(&msg)
-
+		translated, translateErr := a.messageTranslator.ToMessage(&msg)
 		if translateErr != nil {
-			a.errorChannel <- translateErr
+			a.sendError(fmt.Errorf("%w: %w", message.ErrTranslation, translateErr))
+			continue
+		}
+
+		// Untranslatable messages are never processed, so only the translated
+		// ones are tracked, otherwise they would block the partition commits.
+		if a.offsetCommitter != nil {
+			if err := a.offsetCommitter.Track(a.ctx, &msg); err != nil {
+				return
+			}
 		}
 
 		select {
 		case <-a.ctx.Done():
 			return
-		case a.messageChannel <- message:
+		case a.messageChannel <- translated:
 		}
 	}
 }
 
+// sendError reports a consumption error to the receivers, unless the adapter
+// is closed.
+func (a *inboundChannelAdapter) sendError(err error) {
+	select {
+	case <-a.ctx.Done():
+	case a.errorChannel <- err:
+	}
+}
+
+// fail records the terminal failure of the consumption.
+func (a *inboundChannelAdapter) fail(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.err = err
+}
+
 // CommitMessage commits the Kafka message offset to the broker, marking it as
 // consumed.
 //
@@ -559,7 +886,61 @@ func (a *inboundChannelAdapter) subscribeOnTopic() {
 //   - error: error if the message is not a Kafka message or commit fails
 func (a *inboundChannelAdapter) CommitMessage(msg *message.Message) error {
 	if segmentioMessage, ok := msg.GetRawMessage().(*kafka.Message); ok {
+		if a.offsetCommitter != nil {
+			return a.offsetCommitter.MarkProcessed(a.ctx, segmentioMessage)
+		}
 		return a.consumer.CommitMessages(a.ctx, *segmentioMessage)
 	}
 	return fmt.Errorf("[kafka-inbound-channel] failed to commit message")
 }
+
+// SourceIdOf returns the topic, partition and offset of a Kafka message,
+// which identify it on every delivery.
+//
+// Parameters:
+//   - msg: the internal message received by the channel
+//
+// Returns:
+//   - string: the identity of the message, as topic/partition/offset
+//   - error: error if the message is not a Kafka message
+func (a *inboundChannelAdapter) SourceIdOf(msg *message.Message) (string, error) {
+	raw, ok := msg.GetRawMessage().(*kafka.Message)
+	if !ok {
+		return "", fmt.Errorf("[kafka-inbound-channel] failed to identify message")
+	}
+	return fmt.Sprintf("%s/%d/%d", raw.Topic, raw.Partition, raw.Offset), nil
+}
+
+// NackMessage settles a message as not processed. Kafka cannot redeliver a
+// single message, so a requeued message is left uncommitted and delivered
+// again by the adapter, after the messages already fetched, and a discarded
+// message is committed. With batched commits, a requeued message holds back
+// the commit of the following offsets of its partition until it is
+// processed.
+//
+// Parameters:
+//   - msg: the internal message to settle
+//   - requeue: true to deliver the message again, false to discard it
+//
+// Returns:
+//   - error: error if the message is not a Kafka message or commit fails
+func (a *inboundChannelAdapter) NackMessage(msg *message.Message, requeue bool) error {
+	if !requeue {
+		return a.CommitMessage(msg)
+	}
+	raw, ok := msg.GetRawMessage().(*kafka.Message)
+	if !ok {
+		return fmt.Errorf("[kafka-inbound-channel] failed to nack message")
+	}
+	redelivered, err := a.messageTranslator.ToMessage(raw)
+	if err != nil {
+		return fmt.Errorf("[kafka-inbound-channel] failed to requeue message: %w", err)
+	}
+	go func() {
+		select {
+		case <-a.ctx.Done():
+		case a.messageChannel <- redelivered:
+		}
+	}()
+	return nil
+}
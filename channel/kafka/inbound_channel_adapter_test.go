@@ -0,0 +1,152 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeReader delivers the given messages and records the committed offsets.
+type fakeReader struct {
+	messages  chan kafka.Message
+	mu        sync.Mutex
+	committed []int64
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case msg := <-r.messages:
+		return msg, nil
+	}
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.committed = append(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Config() kafka.ReaderConfig             { return kafka.ReaderConfig{} }
+func (r *fakeReader) ReadLag(context.Context) (int64, error) { return 0, nil }
+func (r *fakeReader) Close() error                           { return nil }
+func (r *fakeReader) committedOffsets() []int64              { r.mu.Lock(); defer r.mu.Unlock(); return r.committed }
+
+// failingTranslator fails to translate the messages without value.
+type failingTranslator struct{}
+
+func (failingTranslator) ToMessage(data *kafka.Message) (*message.Message, error) {
+	if len(data.Value) == 0 {
+		return nil, errors.New("empty message")
+	}
+	return message.NewMessageBuilder().
+		WithPayload(data.Value).
+		WithRawMessage(data).
+		Build(), nil
+}
+
+func TestInboundChannelAdapter_TranslationFailureDoesNotBlockCommits(t *testing.T) {
+	t.Parallel()
+	reader := &fakeReader{messages: make(chan kafka.Message, 2)}
+	reader.messages <- kafka.Message{Topic: "orders", Partition: 0, Offset: 10}
+	reader.messages <- kafka.Message{Topic: "orders", Partition: 0, Offset: 11, Value: []byte(`{}`)}
+
+	adp := newInboundChannelAdapter(
+		reader,
+		"orders",
+		failingTranslator{},
+		newOffsetCommitter(reader, 1, 0),
+	)
+	defer adp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := adp.Receive(ctx); !errors.Is(err, message.ErrTranslation) {
+		t.Fatalf("expected translation error, got %v", err)
+	}
+	msg, err := adp.Receive(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := adp.CommitMessage(msg); err != nil {
+		t.Fatalf("unexpected commit error: %v", err)
+	}
+
+	if got := reader.committedOffsets(); len(got) != 1 || got[0] != 11 {
+		t.Errorf("expected offset 11 committed, got %v", got)
+	}
+}
+
+func TestInboundChannelAdapter_RedeliversRequeuedMessages(t *testing.T) {
+	t.Parallel()
+	reader := &fakeReader{messages: make(chan kafka.Message, 2)}
+	reader.messages <- kafka.Message{Topic: "orders", Partition: 0, Offset: 10, Value: []byte(`{}`)}
+	reader.messages <- kafka.Message{Topic: "orders", Partition: 0, Offset: 11, Value: []byte(`{}`)}
+
+	adp := newInboundChannelAdapter(
+		reader,
+		"orders",
+		failingTranslator{},
+		newOffsetCommitter(reader, 1, 0),
+	)
+	defer adp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	first, err := adp.Receive(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := adp.NackMessage(first, true); err != nil {
+		t.Fatalf("unexpected nack error: %v", err)
+	}
+
+	for range 2 {
+		msg, err := adp.Receive(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := adp.CommitMessage(msg); err != nil {
+			t.Fatalf("unexpected commit error: %v", err)
+		}
+	}
+
+	got := reader.committedOffsets()
+	if len(got) == 0 || got[len(got)-1] != 11 {
+		t.Errorf("expected offset 11 committed after the redelivery, got %v", got)
+	}
+}
+
+func TestConsumerChannelAdapterBuilder_TracksOffsets(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		configure func(b *consumerChannelAdapterBuilder)
+		expected  bool
+	}{
+		{"auto ack commits every message", func(b *consumerChannelAdapterBuilder) {}, false},
+		{"ack on success", func(b *consumerChannelAdapterBuilder) { b.WithAckMode(handler.AckOnSuccess) }, true},
+		{"manual ack", func(b *consumerChannelAdapterBuilder) { b.WithAckMode(handler.AckManual) }, true},
+		{"batched commits", func(b *consumerChannelAdapterBuilder) { b.WithCommitEvery(10) }, true},
+		{"atomic reply", func(b *consumerChannelAdapterBuilder) { b.WithAtomicReply() }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewConsumerChannelAdapterBuilder("kafka", "orders", "orders-consumer")
+			tt.configure(builder)
+			if got := builder.tracksOffsets(); got != tt.expected {
+				t.Errorf("expected tracksOffsets %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
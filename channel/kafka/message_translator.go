@@ -7,6 +7,7 @@
 // The MessageTranslator implementation supports:
 // - Message translation between internal and Kafka formats
 // - JSON serialization and deserialization
+// - gzip/zstd payload compression by the contentEncoding header
 // - Header mapping and conversion
 // - Error handling for translation failures
 package kafka
@@ -50,10 +51,12 @@ func (m *MessageTranslator) FromMessage(msg *message.Message) (
 	error,
 ) {
 	headersMap := msg.GetHeader()
-
-	contextPropagator := otel.GetTraceContextPropagatorByContext(msg.GetContext())
-	if contextPropagator != nil {
-		maps.Copy(headersMap, contextPropagator)
+	if otel.IsTraceEnabled() {
+		headersMap = message.Header(msg.GetHeader().All())
+		maps.Copy(
+			headersMap,
+			otel.GetTraceContextPropagatorByContext(msg.GetContext()),
+		)
 	}
 
 	kafkaHeaders := []kafka.Header{}
@@ -72,6 +75,17 @@ func (m *MessageTranslator) FromMessage(msg *message.Message) (
 		)
 	}
 
+	payload, err = message.CompressPayload(
+		headersMap.Get(message.HeaderContentEncoding),
+		payload,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[kafka-message-translator] payload converter error: %v",
+			err.Error(),
+		)
+	}
+
 	return &kafka.Message{
 		Key:     []byte(headersMap.Get(message.HeaderCorrelationId)),
 		Value:   payload,
@@ -98,6 +112,21 @@ func (m *MessageTranslator) ToMessage(data *kafka.Message) (
 		headers[h.Key] = string(h.Value)
 	}
 
+	payload, err := message.DecompressPayload(
+		headers[message.HeaderContentEncoding],
+		data.Value,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[kafka-message-translator] payload converter error: %v",
+			err.Error(),
+		)
+	}
+	delete(headers, message.HeaderContentEncoding)
+	if data.Topic != "" {
+		headers[message.HeaderSourceTopic] = data.Topic
+	}
+
 	messageBuilder, err := message.NewMessageBuilderFromHeaders(headers)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -106,16 +135,13 @@ func (m *MessageTranslator) ToMessage(data *kafka.Message) (
 		)
 	}
 
-	traceParenValue, exists := headers["Traceparent"]
-	if exists && traceParenValue != "" {
-		ctx := otel.GetTraceContextPropagatorByTraceParent(
-			context.Background(),
-			traceParenValue,
+	if otel.IsTraceEnabled() {
+		messageBuilder.WithContext(
+			otel.GetTraceContextPropagatorByHeaders(context.Background(), headers),
 		)
-		messageBuilder.WithContext(ctx)
 	}
 
-	messageBuilder.WithPayload(data.Value)
+	messageBuilder.WithPayload(payload)
 	messageBuilder.WithRawMessage(data)
 	msg := messageBuilder.Build()
 	return msg, nil
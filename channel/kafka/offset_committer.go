@@ -0,0 +1,273 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// defaultMaxUncommittedOffsets is the default amount of offsets received and
+// not yet committable a partition may hold before the consumer stops
+// fetching.
+const defaultMaxUncommittedOffsets = 10000
+
+// messageCommitter commits the offsets of Kafka messages.
+type messageCommitter interface {
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// topicPartition identifies a partition of a topic.
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+// partitionOffsets tracks the offsets of a partition received and processed
+// since its last commit. The received offsets are kept in ascending order.
+type partitionOffsets struct {
+	received    []int64
+	processed   map[int64]bool
+	committable int64
+	committed   int64
+}
+
+// offsetCommitter batches the offset commits of a consumer, committing the
+// highest contiguous processed offset of each partition every amount of
+// processed messages and/or every interval, instead of committing each
+// message. Offsets after a message still being processed, or requeued, are
+// not committed, so they are redelivered after a restart or rebalance.
+//
+// A partition whose offsets go back, e.g. redelivered by the group after a
+// rebalance, restarts its tracking from the redelivered offset, and the
+// state of revoked partitions is discarded. Each partition holds at most
+// maxUncommitted offsets not yet committable: beyond it, Track waits for the
+// oldest ones to be processed.
+type offsetCommitter struct {
+	committer      messageCommitter
+	every          int
+	interval       time.Duration
+	maxUncommitted int
+	mu             sync.Mutex
+	partitions     map[topicPartition]*partitionOffsets
+	pending        int
+	released       chan struct{}
+}
+
+// newOffsetCommitter creates a new offset committer.
+//
+// Parameters:
+//   - committer: the consumer committing the offsets
+//   - every: processed messages triggering a commit, zero disables it
+//   - interval: interval between commits, zero disables it
+//
+// Returns:
+//   - *offsetCommitter: configured offset committer
+func newOffsetCommitter(
+	committer messageCommitter,
+	every int,
+	interval time.Duration,
+) *offsetCommitter {
+	return &offsetCommitter{
+		committer:      committer,
+		every:          every,
+		interval:       interval,
+		maxUncommitted: defaultMaxUncommittedOffsets,
+		partitions:     map[topicPartition]*partitionOffsets{},
+		released:       make(chan struct{}),
+	}
+}
+
+// Track registers a message received from the broker, in partition order.
+// An offset not after the last tracked offset of its partition is a
+// redelivery, restarting the tracking of the partition. When the partition
+// holds the maximum amount of uncommitted offsets, it waits for the oldest
+// ones to be processed.
+//
+// Parameters:
+//   - ctx: context stopping the wait
+//   - msg: the received Kafka message
+//
+// Returns:
+//   - error: the context error if it is done while waiting
+func (c *offsetCommitter) Track(ctx context.Context, msg *kafka.Message) error {
+	for {
+		c.mu.Lock()
+		offsets := c.partitionOffsets(msg)
+		if last := len(offsets.received) - 1; last >= 0 && msg.Offset <= offsets.received[last] {
+			offsets.rewind(msg.Offset)
+		}
+		if len(offsets.received) < c.maxUncommitted {
+			offsets.received = append(offsets.received, msg.Offset)
+			c.mu.Unlock()
+			return nil
+		}
+		released := c.released
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// MarkProcessed registers a processed message, committing the offsets when
+// the amount of processed messages is reached. Messages no longer tracked,
+// e.g. of a revoked partition, are ignored.
+//
+// Parameters:
+//   - ctx: context of the commit
+//   - msg: the processed Kafka message
+//
+// Returns:
+//   - error: error if the commit fails
+func (c *offsetCommitter) MarkProcessed(ctx context.Context, msg *kafka.Message) error {
+	c.mu.Lock()
+	offsets, ok := c.partitions[topicPartition{msg.Topic, msg.Partition}]
+	if !ok {
+		c.mu.Unlock()
+		return nil
+	}
+	if _, tracked := slices.BinarySearch(offsets.received, msg.Offset); !tracked {
+		c.mu.Unlock()
+		return nil
+	}
+	offsets.processed[msg.Offset] = true
+	advanced := false
+	for len(offsets.received) > 0 && offsets.processed[offsets.received[0]] {
+		offsets.committable = offsets.received[0]
+		delete(offsets.processed, offsets.received[0])
+		offsets.received = offsets.received[1:]
+		advanced = true
+	}
+	if advanced {
+		c.release()
+	}
+	c.pending++
+	flush := c.every > 0 && c.pending >= c.every
+	c.mu.Unlock()
+
+	if flush {
+		return c.Flush(ctx)
+	}
+	return nil
+}
+
+// Revoke discards the offsets tracked for partitions no longer assigned to
+// the consumer, so they are neither committed nor holding back the consumer.
+//
+// Parameters:
+//   - partitions: the revoked partitions, by topic
+func (c *offsetCommitter) Revoke(partitions map[string][]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic, ids := range partitions {
+		for _, id := range ids {
+			delete(c.partitions, topicPartition{topic, id})
+		}
+	}
+	c.release()
+}
+
+// Flush commits the highest contiguous processed offset of each partition.
+//
+// Parameters:
+//   - ctx: context of the commit
+//
+// Returns:
+//   - error: error if the commit fails
+func (c *offsetCommitter) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	var commits []kafka.Message
+	for partition, offsets := range c.partitions {
+		if offsets.committable > offsets.committed {
+			commits = append(commits, kafka.Message{
+				Topic:     partition.topic,
+				Partition: partition.partition,
+				Offset:    offsets.committable,
+			})
+		}
+	}
+	c.pending = 0
+	c.mu.Unlock()
+
+	if len(commits) == 0 {
+		return nil
+	}
+	if err := c.committer.CommitMessages(ctx, commits...); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, commit := range commits {
+		offsets, ok := c.partitions[topicPartition{commit.Topic, commit.Partition}]
+		if ok && commit.Offset > offsets.committed {
+			offsets.committed = commit.Offset
+		}
+	}
+	return nil
+}
+
+// Run commits the offsets at the configured interval until the context is
+// done. It returns immediately when no interval is configured.
+//
+// Parameters:
+//   - ctx: context stopping the commits
+func (c *offsetCommitter) Run(ctx context.Context) {
+	if c.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := c.Flush(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("[kafka-inbound-channel] failed to commit offsets",
+				"reason", err.Error(),
+			)
+		}
+	}
+}
+
+// partitionOffsets returns the offsets tracked for the partition of a
+// message. The caller holds the lock.
+func (c *offsetCommitter) partitionOffsets(msg *kafka.Message) *partitionOffsets {
+	partition := topicPartition{msg.Topic, msg.Partition}
+	offsets, ok := c.partitions[partition]
+	if !ok {
+		offsets = &partitionOffsets{
+			processed:   map[int64]bool{},
+			committable: -1,
+			committed:   -1,
+		}
+		c.partitions[partition] = offsets
+	}
+	return offsets
+}
+
+// release wakes up the Track calls waiting for uncommitted offsets to be
+// released. The caller holds the lock.
+func (c *offsetCommitter) release() {
+	close(c.released)
+	c.released = make(chan struct{})
+}
+
+// rewind restarts the tracking of the partition from a redelivered offset,
+// discarding the offsets received since it. The offsets before it stay
+// committable.
+func (p *partitionOffsets) rewind(offset int64) {
+	p.received = nil
+	clear(p.processed)
+	p.committable = min(p.committable, offset-1)
+	p.committed = min(p.committed, offset-1)
+}
@@ -0,0 +1,134 @@
+package kafka
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func trackedMessage(partition int, offset int64) *kafka.Message {
+	return &kafka.Message{Topic: "orders", Partition: partition, Offset: offset}
+}
+
+func TestOffsetCommitter_CommitsContiguousOffsets(t *testing.T) {
+	t.Parallel()
+	reader := &fakeReader{}
+	committer := newOffsetCommitter(reader, 0, 0)
+	ctx := context.Background()
+	for offset := int64(0); offset < 3; offset++ {
+		committer.Track(ctx, trackedMessage(0, offset))
+	}
+
+	committer.MarkProcessed(ctx, trackedMessage(0, 0))
+	committer.MarkProcessed(ctx, trackedMessage(0, 2))
+	committer.Flush(ctx)
+	if got := reader.committedOffsets(); !slices.Equal(got, []int64{0}) {
+		t.Fatalf("expected the gap to hold back offset 2, got %v", got)
+	}
+
+	committer.MarkProcessed(ctx, trackedMessage(0, 1))
+	committer.Flush(ctx)
+	if got := reader.committedOffsets(); !slices.Equal(got, []int64{0, 2}) {
+		t.Errorf("expected offset 2 committed once the gap is processed, got %v", got)
+	}
+}
+
+func TestOffsetCommitter_RestartsOnRedelivery(t *testing.T) {
+	t.Parallel()
+	reader := &fakeReader{}
+	committer := newOffsetCommitter(reader, 0, 0)
+	ctx := context.Background()
+	committer.Track(ctx, trackedMessage(0, 10))
+	committer.Track(ctx, trackedMessage(0, 11))
+
+	// the group redelivers the partition from its committed offset
+	committer.Track(ctx, trackedMessage(0, 10))
+	committer.Track(ctx, trackedMessage(0, 11))
+	committer.MarkProcessed(ctx, trackedMessage(0, 10))
+	committer.MarkProcessed(ctx, trackedMessage(0, 11))
+	committer.Flush(ctx)
+
+	if got := reader.committedOffsets(); !slices.Equal(got, []int64{11}) {
+		t.Errorf("expected redelivered offsets not to block the partition, got %v", got)
+	}
+}
+
+func TestOffsetCommitter_HoldsRequeuedOffsetUntilProcessed(t *testing.T) {
+	t.Parallel()
+	reader := &fakeReader{}
+	committer := newOffsetCommitter(reader, 1, 0)
+	ctx := context.Background()
+	committer.Track(ctx, trackedMessage(0, 0))
+	committer.Track(ctx, trackedMessage(0, 1))
+
+	// offset 0 is nacked and left unprocessed
+	committer.MarkProcessed(ctx, trackedMessage(0, 1))
+	if got := reader.committedOffsets(); len(got) != 0 {
+		t.Fatalf("expected the nacked offset to hold back the commits, got %v", got)
+	}
+
+	committer.MarkProcessed(ctx, trackedMessage(0, 0))
+	if got := reader.committedOffsets(); !slices.Equal(got, []int64{1}) {
+		t.Errorf("expected offset 1 committed once the nacked offset is processed, got %v", got)
+	}
+}
+
+func TestOffsetCommitter_DiscardsRevokedPartitions(t *testing.T) {
+	t.Parallel()
+	reader := &fakeReader{}
+	committer := newOffsetCommitter(reader, 0, 0)
+	ctx := context.Background()
+	committer.Track(ctx, trackedMessage(0, 0))
+	committer.Track(ctx, trackedMessage(1, 0))
+	committer.MarkProcessed(ctx, trackedMessage(1, 0))
+
+	committer.Revoke(map[string][]int{"orders": {1}})
+	committer.MarkProcessed(ctx, trackedMessage(0, 0))
+	committer.MarkProcessed(ctx, trackedMessage(1, 0))
+	committer.Flush(ctx)
+
+	if got := reader.committedOffsets(); !slices.Equal(got, []int64{0}) {
+		t.Errorf("expected only the assigned partition committed, got %v", got)
+	}
+	if len(committer.partitions) != 1 {
+		t.Errorf("expected the revoked partition state discarded, got %d partitions", len(committer.partitions))
+	}
+}
+
+func TestOffsetCommitter_CapsUncommittedOffsets(t *testing.T) {
+	t.Parallel()
+	committer := newOffsetCommitter(&fakeReader{}, 0, 0)
+	committer.maxUncommitted = 2
+	ctx := context.Background()
+	committer.Track(ctx, trackedMessage(0, 0))
+	committer.Track(ctx, trackedMessage(0, 1))
+
+	tracked := make(chan error, 1)
+	go func() {
+		tracked <- committer.Track(ctx, trackedMessage(0, 2))
+	}()
+	select {
+	case <-tracked:
+		t.Fatal("expected Track to wait while the partition is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	committer.MarkProcessed(ctx, trackedMessage(0, 0))
+	select {
+	case err := <-tracked:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Track to resume once an offset is processed")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := committer.Track(cancelled, trackedMessage(0, 3)); err == nil {
+		t.Error("expected the context error while the partition is full")
+	}
+}
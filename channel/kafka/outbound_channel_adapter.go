@@ -13,6 +13,7 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jeffersonbrasilino/gomes/container"
@@ -33,6 +34,7 @@ type publisherChannelAdapterBuilder struct {
 	batchBytes              int64
 	async                   bool
 	requiredAcks            int
+	topicSpec               *TopicSpec
 }
 
 // outboundChannelAdapter implements the PublisherChannel interface for Kafka,
@@ -42,6 +44,7 @@ type outboundChannelAdapter struct {
 	topicName         string
 	messageTranslator adapter.OutboundChannelMessageTranslator[*kafka.Message]
 	otelTrace         otel.OtelTrace
+	otelMetrics       otel.OtelMetrics
 }
 
 // NewPublisherChannelAdapterBuilder creates a new Kafka publisher channel
@@ -69,6 +72,7 @@ func NewPublisherChannelAdapterBuilder(
 		1048576,
 		true,
 		0,
+		nil,
 	}
 	return builder
 }
@@ -93,6 +97,7 @@ func NewOutboundChannelAdapter(
 		topicName:         topicName,
 		messageTranslator: messageTranslator,
 		otelTrace:         otel.InitTrace("kafka-outbound-channel-adapter"),
+		otelMetrics:       otel.InitMetrics("kafka-outbound-channel-adapter"),
 	}
 }
 
@@ -171,6 +176,30 @@ func (b *publisherChannelAdapterBuilder) WithRequiredAcks(
 	return b
 }
 
+// WithTopicSpec declares the published topic, creating it on Start when
+// missing, so development environments bootstrap themselves.
+//
+// Parameters:
+//   - spec: the topic specification
+//
+// Returns:
+//   - *publisherChannelAdapterBuilder: builder instance for chaining
+func (b *publisherChannelAdapterBuilder) WithTopicSpec(
+	spec TopicSpec,
+) *publisherChannelAdapterBuilder {
+	b.topicSpec = &spec
+	return b
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (b *publisherChannelAdapterBuilder) ConnectionReferenceName() string {
+	return b.connectionReferenceName
+}
+
 // Build constructs a Kafka outbound channel adapter from the dependency
 // container. It retrieves the connection, creates a Kafka writer with the
 // configured settings, and returns a wrapped outbound adapter.
@@ -184,21 +213,13 @@ func (b *publisherChannelAdapterBuilder) WithRequiredAcks(
 func (b *publisherChannelAdapterBuilder) Build(
 	container container.Container[any, any],
 ) (endpoint.OutboundChannelAdapter, error) {
-	con, err := container.Get(b.connectionReferenceName)
-
+	conn, err := getConnection(container, b.connectionReferenceName)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"[kafka-outbound-channel] connection %s does not exist",
-			b.connectionReferenceName,
-		)
+		return nil, fmt.Errorf("[kafka-outbound-channel] %w", err)
 	}
 
-	conn, ok := con.(*connection)
-	if !ok {
-		return nil, fmt.Errorf(
-			"[kafka-outbound-channel] connection %s is not a valid Kafka connection",
-			b.connectionReferenceName,
-		)
+	if err := provisionTopic(conn, b.ChannelName(), b.topicSpec); err != nil {
+		return nil, err
 	}
 
 	producer := &kafka.Writer{
@@ -241,7 +262,7 @@ func (a *outboundChannelAdapter) Name() string {
 //   - error: error if sending fails or context is cancelled
 func (a *outboundChannelAdapter) Send(ctx context.Context, msg *message.Message) error {
 
-	_, span := a.otelTrace.Start(
+	ctx, span := a.otelTrace.Start(
 		ctx,
 		"",
 		otel.WithMessagingSystemType(otel.MessageSystemTypeKafka),
@@ -250,6 +271,10 @@ func (a *outboundChannelAdapter) Send(ctx context.Context, msg *message.Message)
 		otel.WithMessage(msg),
 	)
 	defer span.End()
+	if otel.IsTraceEnabled() {
+		// the translator propagates the producer span through the headers
+		msg.SetContext(ctx)
+	}
 
 	select {
 	case <-ctx.Done():
@@ -269,6 +294,7 @@ func (a *outboundChannelAdapter) Send(ctx context.Context, msg *message.Message)
 	}
 
 	err := a.producer.WriteMessages(ctx, *msgToSend)
+	a.otelMetrics.AddPublished(ctx, otel.MessageSystemTypeKafka, a.topicName, msg, err)
 
 	select {
 	case <-ctx.Done():
@@ -289,6 +315,66 @@ func (a *outboundChannelAdapter) Send(ctx context.Context, msg *message.Message)
 	return err
 }
 
+// SendBatch publishes the messages to the Kafka topic in a single writer
+// call. Messages which cannot be translated or written are reported by a
+// *message.BatchError indexed by their position.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msgs: the messages to be published
+//
+// Returns:
+//   - error: *message.BatchError if any message fails
+func (a *outboundChannelAdapter) SendBatch(
+	ctx context.Context,
+	msgs []*message.Message,
+) error {
+	ctx, span := a.otelTrace.Start(
+		ctx,
+		fmt.Sprintf("Send batch of %d messages", len(msgs)),
+		otel.WithMessagingSystemType(otel.MessageSystemTypeKafka),
+		otel.WithSpanOperation(otel.SpanOperationSend),
+		otel.WithSpanKind(otel.SpanKindProducer),
+	)
+	defer span.End()
+
+	failures := map[int]error{}
+	positions := make([]int, 0, len(msgs))
+	records := make([]kafka.Message, 0, len(msgs))
+	for i, msg := range msgs {
+		record, err := a.messageTranslator.FromMessage(msg)
+		if err != nil {
+			failures[i] = err
+			continue
+		}
+		positions = append(positions, i)
+		records = append(records, *record)
+	}
+
+	if len(records) > 0 {
+		err := a.producer.WriteMessages(ctx, records...)
+		var writeErrors kafka.WriteErrors
+		for i, position := range positions {
+			recordErr := err
+			if errors.As(err, &writeErrors) {
+				recordErr = writeErrors[i]
+			}
+			a.otelMetrics.AddPublished(ctx, otel.MessageSystemTypeKafka, a.topicName, msgs[position], recordErr)
+			if recordErr != nil {
+				failures[position] = recordErr
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		err := &message.BatchError{Errors: failures}
+		span.Error(err, err.Error())
+		return err
+	}
+	span.Success("message batch sent to kafka topic successfully")
+	return nil
+}
+
 // Close closes the Kafka producer and releases associated resources.
 //
 // Returns:
@@ -0,0 +1,329 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// messageReader fetches Kafka messages and commits their offsets.
+type messageReader interface {
+	messageCommitter
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	Config() kafka.ReaderConfig
+	ReadLag(ctx context.Context) (int64, error)
+	Close() error
+}
+
+// fetchResult is a message or error fetched from a partition.
+type fetchResult struct {
+	msg kafka.Message
+	err error
+}
+
+// groupGeneration is a generation of a consumer group, running the
+// functions of its members until it ends.
+type groupGeneration interface {
+	Start(fn func(ctx context.Context))
+	CommitOffsets(offsets map[string]map[int]int64) error
+}
+
+// partitionedConsumer consumes the partitions assigned to a consumer group
+// member concurrently, running one fetch loop per partition, so a slow
+// partition does not hold back the others. The messages of a partition are
+// fetched in order and their offsets are committed per partition through the
+// current group generation.
+//
+// Its rebalance listeners are notified of the partitions assigned when a
+// generation starts and of their revocation when it ends, before the member
+// rejoins the group, so no other member consumes them yet.
+type partitionedConsumer struct {
+	group      *kafka.ConsumerGroup
+	config     kafka.ReaderConfig
+	fetched    chan fetchResult
+	ctx        context.Context
+	cancelCtx  context.CancelFunc
+	startOnce  sync.Once
+	listeners  []RebalanceListener
+	consume    func(ctx context.Context, topic string, assignment kafka.PartitionAssignment)
+	mu         sync.Mutex
+	generation groupGeneration
+	assigned   map[topicPartition]bool
+}
+
+// newPartitionedConsumer creates a partitioned consumer joining the consumer
+// group of the reader configuration. The partitions are consumed from the
+// first fetch.
+//
+// Parameters:
+//   - config: the reader configuration of the consumer group and partitions
+//
+// Returns:
+//   - *partitionedConsumer: the partitioned consumer
+//   - error: error if the consumer group configuration is invalid
+func newPartitionedConsumer(config kafka.ReaderConfig) (*partitionedConsumer, error) {
+	topics := config.GroupTopics
+	if len(topics) == 0 {
+		topics = []string{config.Topic}
+	}
+	group, err := kafka.NewConsumerGroup(kafka.ConsumerGroupConfig{
+		ID:                     config.GroupID,
+		Brokers:                config.Brokers,
+		Dialer:                 config.Dialer,
+		Topics:                 topics,
+		GroupBalancers:         config.GroupBalancers,
+		HeartbeatInterval:      config.HeartbeatInterval,
+		PartitionWatchInterval: config.PartitionWatchInterval,
+		WatchPartitionChanges:  config.WatchPartitionChanges,
+		SessionTimeout:         config.SessionTimeout,
+		RebalanceTimeout:       config.RebalanceTimeout,
+		JoinGroupBackoff:       config.JoinGroupBackoff,
+		RetentionTime:          config.RetentionTime,
+		StartOffset:            config.StartOffset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[kafka-inbound-channel] failed to create consumer group %s: %w",
+			config.GroupID,
+			err,
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumer := &partitionedConsumer{
+		group:     group,
+		config:    config,
+		fetched:   make(chan fetchResult),
+		ctx:       ctx,
+		cancelCtx: cancel,
+	}
+	consumer.consume = consumer.consumePartition
+	return consumer, nil
+}
+
+// addRebalanceListener adds a listener notified of the partitions assigned
+// to and revoked from the member. It must be added before the first fetch.
+//
+// Parameters:
+//   - listener: the rebalance listener
+func (c *partitionedConsumer) addRebalanceListener(listener RebalanceListener) {
+	c.listeners = append(c.listeners, listener)
+}
+
+// FetchMessage returns the next message fetched from any assigned partition.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//
+// Returns:
+//   - kafka.Message: the fetched message
+//   - error: error if fetching fails or the context is done
+func (c *partitionedConsumer) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	c.startOnce.Do(func() {
+		go c.run()
+	})
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case <-c.ctx.Done():
+		return kafka.Message{}, c.ctx.Err()
+	case result := <-c.fetched:
+		return result.msg, result.err
+	}
+}
+
+// CommitMessages commits the offsets of the messages through the current
+// group generation. Messages of partitions no longer assigned to the member
+// are not committed, being redelivered to their new owner.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//   - msgs: the messages whose offsets are committed
+//
+// Returns:
+//   - error: error if no partitions are assigned or the commit fails
+func (c *partitionedConsumer) CommitMessages(
+	ctx context.Context,
+	msgs ...kafka.Message,
+) error {
+	c.mu.Lock()
+	generation, assigned := c.generation, c.assigned
+	c.mu.Unlock()
+	if generation == nil {
+		return fmt.Errorf("[kafka-inbound-channel] no partitions assigned to commit")
+	}
+
+	offsets := map[string]map[int]int64{}
+	for _, msg := range msgs {
+		if !assigned[topicPartition{msg.Topic, msg.Partition}] {
+			continue
+		}
+		if offsets[msg.Topic] == nil {
+			offsets[msg.Topic] = map[int]int64{}
+		}
+		if next := msg.Offset + 1; next > offsets[msg.Topic][msg.Partition] {
+			offsets[msg.Topic][msg.Partition] = next
+		}
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+	return generation.CommitOffsets(offsets)
+}
+
+// Config returns the reader configuration of the consumer group.
+//
+// Returns:
+//   - kafka.ReaderConfig: the reader configuration
+func (c *partitionedConsumer) Config() kafka.ReaderConfig {
+	return c.config
+}
+
+// ReadLag is not available for consumer groups, whose lag is read from the
+// committed offsets of the group.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//
+// Returns:
+//   - int64: always zero
+//   - error: always an error
+func (c *partitionedConsumer) ReadLag(ctx context.Context) (int64, error) {
+	return 0, fmt.Errorf(
+		"[kafka-inbound-channel] read lag is not available for consumer group %s",
+		c.config.GroupID,
+	)
+}
+
+// Close stops the partition fetch loops and leaves the consumer group.
+//
+// Returns:
+//   - error: error if leaving the group fails
+func (c *partitionedConsumer) Close() error {
+	c.cancelCtx()
+	return c.group.Close()
+}
+
+// run consumes the partitions of every generation of the consumer group,
+// until the consumer is closed.
+func (c *partitionedConsumer) run() {
+	for {
+		generation, err := c.group.Next(c.ctx)
+		if err != nil {
+			if c.ctx.Err() != nil || errors.Is(err, kafka.ErrGroupClosed) {
+				return
+			}
+			slog.Error("[kafka-inbound-channel] failed to join consumer group",
+				"groupId", c.config.GroupID,
+				"reason", err.Error(),
+			)
+			continue
+		}
+
+		c.startGeneration(generation, generation.Assignments)
+	}
+}
+
+// startGeneration notifies the assigned partitions and starts their fetch
+// loops. The revocation is notified when the generation ends, once the fetch
+// loops stopped: the group waits for it before rejoining, so the partitions
+// are not consumed by another member yet.
+func (c *partitionedConsumer) startGeneration(
+	generation groupGeneration,
+	assignments map[string][]kafka.PartitionAssignment,
+) {
+	assigned := map[topicPartition]bool{}
+	partitions := map[string][]int{}
+	for topic, topicAssignments := range assignments {
+		for _, assignment := range topicAssignments {
+			assigned[topicPartition{topic, assignment.ID}] = true
+			partitions[topic] = append(partitions[topic], assignment.ID)
+		}
+	}
+	c.mu.Lock()
+	c.generation, c.assigned = generation, assigned
+	c.mu.Unlock()
+
+	for _, listener := range c.listeners {
+		if listener.OnPartitionsAssigned != nil {
+			listener.OnPartitionsAssigned(c.ctx, partitions)
+		}
+	}
+
+	var fetching sync.WaitGroup
+	for topic, topicAssignments := range assignments {
+		for _, assignment := range topicAssignments {
+			fetching.Add(1)
+			generation.Start(func(ctx context.Context) {
+				defer fetching.Done()
+				c.consume(ctx, topic, assignment)
+			})
+		}
+	}
+	generation.Start(func(ctx context.Context) {
+		<-ctx.Done()
+		fetching.Wait()
+		c.mu.Lock()
+		if c.generation == generation {
+			c.generation, c.assigned = nil, nil
+		}
+		c.mu.Unlock()
+		for _, listener := range c.listeners {
+			if listener.OnPartitionsRevoked != nil {
+				listener.OnPartitionsRevoked(context.Background(), partitions)
+			}
+		}
+	})
+}
+
+// consumePartition fetches the messages of a partition in order from its
+// assigned offset, until the generation ends.
+func (c *partitionedConsumer) consumePartition(
+	ctx context.Context,
+	topic string,
+	assignment kafka.PartitionAssignment,
+) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:               c.config.Brokers,
+		Topic:                 topic,
+		Partition:             assignment.ID,
+		Dialer:                c.config.Dialer,
+		QueueCapacity:         c.config.QueueCapacity,
+		MinBytes:              c.config.MinBytes,
+		MaxBytes:              c.config.MaxBytes,
+		MaxWait:               c.config.MaxWait,
+		ReadBatchTimeout:      c.config.ReadBatchTimeout,
+		ReadBackoffMin:        c.config.ReadBackoffMin,
+		ReadBackoffMax:        c.config.ReadBackoffMax,
+		IsolationLevel:        c.config.IsolationLevel,
+		MaxAttempts:           c.config.MaxAttempts,
+		OffsetOutOfRangeError: c.config.OffsetOutOfRangeError,
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(assignment.Offset); err != nil {
+		slog.Error("[kafka-inbound-channel] failed to seek partition",
+			"topic", topic,
+			"partition", assignment.ID,
+			"offset", assignment.Offset,
+			"reason", err.Error(),
+		)
+		return
+	}
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case c.fetched <- fetchResult{msg: msg, err: err}:
+		}
+	}
+}
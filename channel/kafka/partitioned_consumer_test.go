@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeGeneration runs the functions of a group generation until it is
+// ended.
+type fakeGeneration struct {
+	ctx     context.Context
+	running sync.WaitGroup
+}
+
+func (g *fakeGeneration) Start(fn func(ctx context.Context)) {
+	g.running.Add(1)
+	go func() {
+		defer g.running.Done()
+		fn(g.ctx)
+	}()
+}
+
+func (g *fakeGeneration) CommitOffsets(map[string]map[int]int64) error {
+	return nil
+}
+
+// rebalanceEvents records the rebalance notifications and partition fetches
+// of a consumer, in order.
+type rebalanceEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *rebalanceEvents) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *rebalanceEvents) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+func TestPartitionedConsumer_NotifiesRebalancesAroundTheGeneration(t *testing.T) {
+	t.Parallel()
+	events := &rebalanceEvents{}
+	fetching := make(chan struct{}, 2)
+	consumer := &partitionedConsumer{ctx: context.Background()}
+	consumer.consume = func(ctx context.Context, topic string, assignment kafka.PartitionAssignment) {
+		fetching <- struct{}{}
+		<-ctx.Done()
+		events.add(fmt.Sprintf("stopped %s/%d", topic, assignment.ID))
+	}
+	consumer.addRebalanceListener(RebalanceListener{
+		OnPartitionsAssigned: func(_ context.Context, partitions map[string][]int) {
+			slices.Sort(partitions["orders"])
+			events.add(fmt.Sprintf("assigned %v", partitions["orders"]))
+		},
+		OnPartitionsRevoked: func(_ context.Context, partitions map[string][]int) {
+			slices.Sort(partitions["orders"])
+			events.add(fmt.Sprintf("revoked %v", partitions["orders"]))
+		},
+	})
+
+	ctx, end := context.WithCancel(context.Background())
+	generation := &fakeGeneration{ctx: ctx}
+	consumer.startGeneration(generation, map[string][]kafka.PartitionAssignment{
+		"orders": {{ID: 0}, {ID: 1}},
+	})
+	<-fetching
+	<-fetching
+	if got := events.list(); !slices.Equal(got, []string{"assigned [0 1]"}) {
+		t.Fatalf("expected the assignment notified before fetching, got %v", got)
+	}
+
+	end()
+	generation.running.Wait()
+	got := events.list()
+	if len(got) != 4 || got[3] != "revoked [0 1]" {
+		t.Fatalf("expected the revocation notified after the fetch loops stopped, got %v", got)
+	}
+	if err := consumer.CommitMessages(context.Background(), *trackedMessage(0, 1)); err == nil {
+		t.Error("expected no commit once the partitions are revoked")
+	}
+}
+
+func TestPartitionedConsumer_CommitsOnlyAssignedPartitions(t *testing.T) {
+	t.Parallel()
+	committed := map[string]map[int]int64{}
+	consumer := &partitionedConsumer{
+		ctx:        context.Background(),
+		generation: commitRecorder(committed),
+		assigned:   map[topicPartition]bool{{"orders", 0}: true},
+	}
+
+	err := consumer.CommitMessages(
+		context.Background(),
+		*trackedMessage(0, 4),
+		*trackedMessage(1, 7),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(committed["orders"]) != 1 || committed["orders"][0] != 5 {
+		t.Errorf("expected only the next offset of the assigned partition committed, got %v", committed)
+	}
+}
+
+// commitRecorder is a group generation recording its committed offsets.
+type commitRecorder map[string]map[int]int64
+
+func (r commitRecorder) Start(func(ctx context.Context)) {}
+
+func (r commitRecorder) CommitOffsets(offsets map[string]map[int]int64) error {
+	for topic, partitions := range offsets {
+		r[topic] = partitions
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package kafka
+
+import "context"
+
+// RebalanceListener is notified when partitions are assigned to or revoked
+// from a consumer, keyed by topic. Both callbacks are optional.
+//
+// The callbacks run within the group generations of the consumer: the
+// assignment is notified when a generation starts, before its partitions are
+// fetched, and the revocation when it ends, after its partitions stop being
+// fetched and before the member rejoins the group, so the revoked partitions
+// are not consumed by another member yet.
+type RebalanceListener struct {
+	OnPartitionsAssigned func(ctx context.Context, partitions map[string][]int)
+	OnPartitionsRevoked  func(ctx context.Context, partitions map[string][]int)
+}
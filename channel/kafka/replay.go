@@ -0,0 +1,256 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// replayTimeout bounds the repositioning of a consumer group.
+const replayTimeout = 30 * time.Second
+
+// ReplayPosition is the position a consumer group is moved to, on every
+// partition of its topics, to reprocess historical messages.
+type ReplayPosition struct {
+	at     time.Time
+	offset int64
+}
+
+// ReplayFromTime replays the messages published from the given time: each
+// partition is moved to its first message with a timestamp equal to or
+// later than it, or to its end when there is none.
+//
+// Parameters:
+//   - at: the time of the first message to replay
+//
+// Returns:
+//   - ReplayPosition: the replay position
+func ReplayFromTime(at time.Time) ReplayPosition {
+	return ReplayPosition{at: at}
+}
+
+// ReplayFromOffset replays the messages from the given offset on every
+// partition, clamped to the offsets available in each partition. Use
+// kafka.FirstOffset to replay from the beginning of the partitions, or
+// kafka.LastOffset to skip to their end.
+//
+// Parameters:
+//   - offset: the offset of the first message to replay
+//
+// Returns:
+//   - ReplayPosition: the replay position
+func ReplayFromOffset(offset int64) ReplayPosition {
+	return ReplayPosition{offset: offset}
+}
+
+// replayClient is the part of the Kafka client repositioning a consumer
+// group.
+type replayClient interface {
+	Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+	DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error)
+	OffsetCommit(ctx context.Context, req *kafka.OffsetCommitRequest) (*kafka.OffsetCommitResponse, error)
+}
+
+// ReplayFrom moves the consumer group of a consumer to a position on every
+// partition of a topic, so the consumer reprocesses the messages from there
+// through its handlers once it is started again. It is a one-shot operation
+// run by an operator, e.g. from a maintenance command, while every instance
+// of the consumer is stopped: the broker only accepts the offsets of a group
+// without active members, so the replay is rejected otherwise.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - consumerName: the consumer name used when building the consumer channel
+//   - topic: the topic to replay
+//   - position: the replay position
+//
+// Returns:
+//   - error: error if the connection is not established, the group has
+//     active members or the offsets cannot be committed
+func (c *connection) ReplayFrom(
+	ctx context.Context,
+	consumerName string,
+	topic string,
+	position ReplayPosition,
+) error {
+	if c.transport == nil {
+		return fmt.Errorf(
+			"[kafka-replay] connection %s is not established",
+			c.name,
+		)
+	}
+	ctx, cancel := context.WithTimeout(ctx, replayTimeout)
+	defer cancel()
+	return replayGroup(
+		ctx,
+		&kafka.Client{Addr: kafka.TCP(c.host...), Transport: c.transport},
+		fmt.Sprintf("%s:%s", c.name, consumerName),
+		topic,
+		position,
+	)
+}
+
+// replayGroup commits the offsets of a position for every partition of a
+// topic on behalf of a consumer group without active members.
+func replayGroup(
+	ctx context.Context,
+	client replayClient,
+	groupID string,
+	topic string,
+	position ReplayPosition,
+) error {
+	groups, err := client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{
+		GroupIDs: []string{groupID},
+	})
+	if err == nil && len(groups.Groups) > 0 && groups.Groups[0].Error != nil {
+		err = groups.Groups[0].Error
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"[kafka-replay] group %s could not be described: %s",
+			groupID,
+			err.Error(),
+		)
+	}
+	if len(groups.Groups) > 0 && len(groups.Groups[0].Members) > 0 {
+		return fmt.Errorf(
+			"[kafka-replay] group %s has %d active members, stop its consumers before the replay",
+			groupID,
+			len(groups.Groups[0].Members),
+		)
+	}
+
+	offsets, err := replayOffsets(ctx, client, topic, position)
+	if err != nil {
+		return err
+	}
+	commits := map[string][]kafka.OffsetCommit{}
+	for partition, offset := range offsets {
+		commits[topic] = append(commits[topic], kafka.OffsetCommit{
+			Partition: partition,
+			Offset:    offset,
+		})
+	}
+
+	res, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      groupID,
+		GenerationID: -1,
+		Topics:       commits,
+	})
+	if err == nil {
+		errs := []error{}
+		for _, partitions := range res.Topics {
+			for _, partition := range partitions {
+				errs = append(errs, partition.Error)
+			}
+		}
+		err = errors.Join(errs...)
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"[kafka-replay] offsets of group %s could not be committed: %s",
+			groupID,
+			err.Error(),
+		)
+	}
+	return nil
+}
+
+// replayOffsets resolves the offset of a position on every partition of a
+// topic.
+func replayOffsets(
+	ctx context.Context,
+	client replayClient,
+	topic string,
+	position ReplayPosition,
+) (map[int]int64, error) {
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{
+		Topics: []string{topic},
+	})
+	if err == nil && (len(metadata.Topics) == 0 || metadata.Topics[0].Error != nil) {
+		err = fmt.Errorf("topic not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[kafka-replay] topic %s could not be described: %s",
+			topic,
+			err.Error(),
+		)
+	}
+
+	boundRequests := []kafka.OffsetRequest{}
+	timeRequests := []kafka.OffsetRequest{}
+	for _, partition := range metadata.Topics[0].Partitions {
+		boundRequests = append(boundRequests,
+			kafka.FirstOffsetOf(partition.ID),
+			kafka.LastOffsetOf(partition.ID),
+		)
+		timeRequests = append(timeRequests, kafka.TimeOffsetOf(partition.ID, position.at))
+	}
+
+	bounds, err := listOffsets(ctx, client, topic, boundRequests)
+	if err != nil {
+		return nil, err
+	}
+	offsets := map[int]int64{}
+	for _, partition := range bounds {
+		offsets[partition.Partition] = min(
+			max(position.offset, partition.FirstOffset),
+			partition.LastOffset,
+		)
+		if position.offset == kafka.LastOffset {
+			offsets[partition.Partition] = partition.LastOffset
+		}
+	}
+	if position.at.IsZero() {
+		return offsets, nil
+	}
+
+	// partitions without messages after the time are moved to their end
+	byTime, err := listOffsets(ctx, client, topic, timeRequests)
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range bounds {
+		offsets[partition.Partition] = partition.LastOffset
+	}
+	for _, partition := range byTime {
+		for offset := range partition.Offsets {
+			if offset >= 0 {
+				offsets[partition.Partition] = offset
+			}
+		}
+	}
+	return offsets, nil
+}
+
+// listOffsets lists the offsets of the partitions of a topic.
+func listOffsets(
+	ctx context.Context,
+	client replayClient,
+	topic string,
+	requests []kafka.OffsetRequest,
+) ([]kafka.PartitionOffsets, error) {
+	res, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests},
+	})
+	if err == nil {
+		errs := []error{}
+		for _, partition := range res.Topics[topic] {
+			errs = append(errs, partition.Error)
+		}
+		err = errors.Join(errs...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[kafka-replay] offsets of topic %s could not be listed: %s",
+			topic,
+			err.Error(),
+		)
+	}
+	return res.Topics[topic], nil
+}
@@ -0,0 +1,179 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakePartition holds the offsets of a partition of the fake replay client.
+type fakePartition struct {
+	first  int64
+	last   int64
+	byTime int64
+}
+
+// fakeReplayClient is a replay client over in-memory partitions of the
+// "orders" topic.
+type fakeReplayClient struct {
+	partitions map[int]fakePartition
+	members    int
+	commits    map[int]int64
+}
+
+func (f *fakeReplayClient) Metadata(
+	ctx context.Context,
+	req *kafka.MetadataRequest,
+) (*kafka.MetadataResponse, error) {
+	topic := kafka.Topic{Name: "orders"}
+	for id := range f.partitions {
+		topic.Partitions = append(topic.Partitions, kafka.Partition{Topic: "orders", ID: id})
+	}
+	return &kafka.MetadataResponse{Topics: []kafka.Topic{topic}}, nil
+}
+
+func (f *fakeReplayClient) ListOffsets(
+	ctx context.Context,
+	req *kafka.ListOffsetsRequest,
+) (*kafka.ListOffsetsResponse, error) {
+	offsets := map[int]*kafka.PartitionOffsets{}
+	for _, request := range req.Topics["orders"] {
+		partition := f.partitions[request.Partition]
+		result, ok := offsets[request.Partition]
+		if !ok {
+			result = &kafka.PartitionOffsets{
+				Partition: request.Partition,
+				Offsets:   map[int64]time.Time{},
+			}
+			offsets[request.Partition] = result
+		}
+		switch request.Timestamp {
+		case kafka.FirstOffset:
+			result.FirstOffset = partition.first
+		case kafka.LastOffset:
+			result.LastOffset = partition.last
+		default:
+			result.Offsets[partition.byTime] = time.UnixMilli(request.Timestamp)
+		}
+	}
+	res := &kafka.ListOffsetsResponse{Topics: map[string][]kafka.PartitionOffsets{}}
+	for _, result := range offsets {
+		res.Topics["orders"] = append(res.Topics["orders"], *result)
+	}
+	return res, nil
+}
+
+func (f *fakeReplayClient) DescribeGroups(
+	ctx context.Context,
+	req *kafka.DescribeGroupsRequest,
+) (*kafka.DescribeGroupsResponse, error) {
+	group := kafka.DescribeGroupsResponseGroup{GroupID: req.GroupIDs[0]}
+	for range f.members {
+		group.Members = append(group.Members, kafka.DescribeGroupsResponseMember{})
+	}
+	return &kafka.DescribeGroupsResponse{
+		Groups: []kafka.DescribeGroupsResponseGroup{group},
+	}, nil
+}
+
+func (f *fakeReplayClient) OffsetCommit(
+	ctx context.Context,
+	req *kafka.OffsetCommitRequest,
+) (*kafka.OffsetCommitResponse, error) {
+	f.commits = map[int]int64{}
+	for _, commit := range req.Topics["orders"] {
+		f.commits[commit.Partition] = commit.Offset
+	}
+	return &kafka.OffsetCommitResponse{}, nil
+}
+
+func newFakeReplayClient() *fakeReplayClient {
+	return &fakeReplayClient{partitions: map[int]fakePartition{
+		0: {first: 5, last: 10, byTime: 8},
+		1: {first: 0, last: 3, byTime: -1},
+	}}
+}
+
+func TestReplayOffsets_ClampsOffsets(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		offset   int64
+		expected map[int]int64
+	}{
+		{"within the partitions", 7, map[int]int64{0: 7, 1: 3}},
+		{"first offset", kafka.FirstOffset, map[int]int64{0: 5, 1: 0}},
+		{"last offset", kafka.LastOffset, map[int]int64{0: 10, 1: 3}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			offsets, err := replayOffsets(
+				context.Background(),
+				newFakeReplayClient(),
+				"orders",
+				ReplayFromOffset(c.offset),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for partition, expected := range c.expected {
+				if offsets[partition] != expected {
+					t.Errorf("expected offset %d on partition %d, got %d",
+						expected, partition, offsets[partition])
+				}
+			}
+		})
+	}
+}
+
+func TestReplayOffsets_FromTimeFallsBackToTheEnd(t *testing.T) {
+	t.Parallel()
+	offsets, err := replayOffsets(
+		context.Background(),
+		newFakeReplayClient(),
+		"orders",
+		ReplayFromTime(time.Now().Add(-time.Hour)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if offsets[0] != 8 {
+		t.Errorf("expected the first offset after the time, got %d", offsets[0])
+	}
+	if offsets[1] != 3 {
+		t.Errorf("expected the end of a partition without later messages, got %d", offsets[1])
+	}
+}
+
+func TestReplayGroup(t *testing.T) {
+	t.Parallel()
+	t.Run("commits the offsets of a group without members", func(t *testing.T) {
+		t.Parallel()
+		client := newFakeReplayClient()
+		err := replayGroup(context.Background(), client, "kafka:billing", "orders",
+			ReplayFromOffset(kafka.FirstOffset))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.commits[0] != 5 || client.commits[1] != 0 {
+			t.Errorf("expected the first offsets committed, got %v", client.commits)
+		}
+	})
+
+	t.Run("rejects a group with active members", func(t *testing.T) {
+		t.Parallel()
+		client := newFakeReplayClient()
+		client.members = 1
+		err := replayGroup(context.Background(), client, "kafka:billing", "orders",
+			ReplayFromOffset(kafka.FirstOffset))
+		if err == nil {
+			t.Fatal("expected error for a group with active members")
+		}
+		if client.commits != nil {
+			t.Errorf("expected no offsets committed, got %v", client.commits)
+		}
+	})
+}
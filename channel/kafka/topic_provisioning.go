@@ -0,0 +1,96 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// provisioningTimeout bounds the creation of a topic.
+const provisioningTimeout = 30 * time.Second
+
+// TopicSpec declares a topic created when missing on Start. Zero values use
+// the broker defaults.
+type TopicSpec struct {
+	// Partitions is the number of partitions of the topic.
+	Partitions int
+	// ReplicationFactor is the number of replicas of each partition.
+	ReplicationFactor int
+	// Retention is how long the messages of the topic are kept.
+	Retention time.Duration
+}
+
+// ProvisionTopic creates a topic through the Kafka admin API, doing nothing
+// when it already exists.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - topic: the topic name
+//   - spec: the topic specification
+//
+// Returns:
+//   - error: error if the connection is not established or the topic cannot
+//     be created
+func (c *connection) ProvisionTopic(
+	ctx context.Context,
+	topic string,
+	spec TopicSpec,
+) error {
+	if c.transport == nil {
+		return fmt.Errorf(
+			"[kafka-topic-provisioning] connection %s is not established",
+			c.name,
+		)
+	}
+
+	config := kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     -1,
+		ReplicationFactor: -1,
+	}
+	if spec.Partitions > 0 {
+		config.NumPartitions = spec.Partitions
+	}
+	if spec.ReplicationFactor > 0 {
+		config.ReplicationFactor = spec.ReplicationFactor
+	}
+	if spec.Retention > 0 {
+		config.ConfigEntries = append(config.ConfigEntries, kafka.ConfigEntry{
+			ConfigName:  "retention.ms",
+			ConfigValue: strconv.FormatInt(spec.Retention.Milliseconds(), 10),
+		})
+	}
+
+	client := &kafka.Client{
+		Addr:      kafka.TCP(c.host...),
+		Transport: c.transport,
+	}
+	res, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{config},
+	})
+	if err == nil {
+		err = res.Errors[topic]
+	}
+	if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return fmt.Errorf(
+			"[kafka-topic-provisioning] topic %s could not be created: %s",
+			topic,
+			err.Error(),
+		)
+	}
+	return nil
+}
+
+// provisionTopic creates the topic of a channel when a spec is declared.
+func provisionTopic(conn *connection, topic string, spec *TopicSpec) error {
+	if spec == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), provisioningTimeout)
+	defer cancel()
+	return conn.ProvisionTopic(ctx, topic, *spec)
+}
@@ -8,11 +8,15 @@
 //   - Multiple exchange types (Direct, Fanout, Topic, Headers)
 //   - Channel lifecycle management
 //   - Message translation between internal and AMQP formats
+//   - Queue depth and consumer count introspection
 package rabbitmq
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -66,6 +70,22 @@ func (c *connection) GetConnection() *amqp.Connection {
 	return c.conn
 }
 
+// Consumer opens a new AMQP channel to consume messages through.
+//
+// Returns:
+//   - *amqp.Channel: the consumer channel
+//   - error: error if the connection is not established or the channel
+//     cannot be opened
+func (c *connection) Consumer() (*amqp.Channel, error) {
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil, fmt.Errorf(
+			"[RabbitMQ-connection] connection %s is not established",
+			c.name,
+		)
+	}
+	return c.conn.Channel()
+}
+
 // Disconnect closes the RabbitMQ connection and releases associated resources.
 //
 // Returns:
@@ -81,3 +101,29 @@ func (c *connection) Disconnect() error {
 func (c *connection) ReferenceName() string {
 	return c.name
 }
+
+// getConnection returns the RabbitMQ connection registered in the container.
+func getConnection(
+	c container.Container[any, any],
+	connectionReferenceName string,
+) (*connection, error) {
+	conn, err := container.Resolve[*connection, any, any](
+		c,
+		adapter.ConnectionKey(connectionReferenceName),
+	)
+	if errors.Is(err, container.ErrItemNotFound) {
+		return nil, fmt.Errorf(
+			"no RabbitMQ connection is registered as %s: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"connection %s is not a valid RabbitMQ connection: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	return conn, nil
+}
@@ -4,7 +4,10 @@ package rabbitmq
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sync"
 
+	"github.com/google/uuid"
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/adapter"
@@ -12,6 +15,10 @@ import (
 	"github.com/rabbitmq/amqp091-go"
 )
 
+// defaultPrefetchCount is the number of unacknowledged messages delivered to
+// a consumer by default.
+const defaultPrefetchCount = 10
+
 // consumerChannelAdapterBuilder provides a builder pattern for creating
 // RabbitMQ inbound channel adapters with connection and queue configuration.
 type consumerChannelAdapterBuilder struct {
@@ -22,6 +29,15 @@ type consumerChannelAdapterBuilder struct {
 	noLocal                 bool
 	noWait                  bool
 	args                    amqp091.Table
+	bindings                []binding
+	prefetchCount           int
+}
+
+// binding is an exchange bound to the consumed queue.
+type binding struct {
+	exchange     string
+	routingKey   string
+	exchangeType exchangeType
 }
 
 // inboundChannelAdapter implements the InboundChannelAdapter interface for
@@ -34,11 +50,18 @@ type inboundChannelAdapter struct {
 	messageChannel    chan *message.Message
 	errorChannel      chan error
 	otelTrace         otel.OtelTrace
+	otelMetrics       otel.OtelMetrics
 	noLocal           bool
 	exclusive         bool
 	noWait            bool
 	args              amqp091.Table
-	stopTrigger       chan bool
+	consumerTag       string
+	ctx               context.Context
+	cancelCtx         context.CancelFunc
+	done              chan struct{}
+	closeOnce         sync.Once
+	mu                sync.Mutex
+	err               error
 }
 
 // NewConsumerChannelAdapterBuilder creates a new RabbitMQ consumer channel
@@ -68,6 +91,8 @@ func NewConsumerChannelAdapterBuilder(
 		false, // no-local
 		false, // no-wait
 		nil,   // arguments
+		nil,   // bindings
+		defaultPrefetchCount,
 	}
 	return builder
 }
@@ -126,6 +151,91 @@ func (c *consumerChannelAdapterBuilder) WithArguments(args amqp091.Table) *consu
 	return c
 }
 
+// WithBinding binds the consumed queue to an exchange with a routing key.
+// When bindings are set, the queue, the exchanges and the bindings are
+// declared on build, so topic routing consumption needs no previous setup.
+// Can be called multiple times to bind the queue to several routing keys or
+// exchanges.
+//
+// Parameters:
+//   - exchange: the exchange name
+//   - routingKey: the binding routing key, e.g. "order.*" for topic exchanges
+//   - exchangeType: the exchange type (ExchangeDirect, ExchangeFanout,
+//     ExchangeTopic, ExchangeHeaders)
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder for method chaining
+func (c *consumerChannelAdapterBuilder) WithBinding(
+	exchange string,
+	routingKey string,
+	exchangeType exchangeType,
+) *consumerChannelAdapterBuilder {
+	c.bindings = append(c.bindings, binding{exchange, routingKey, exchangeType})
+	return c
+}
+
+// WithPrefetchCount sets how many unacknowledged messages the broker
+// delivers to the consumer at once (basic.qos). Without a limit the broker
+// pushes the whole queue to the consumer, which holds the messages in memory
+// and keeps them from other consumers. Defaults to 10; zero removes the
+// limit.
+//
+// Parameters:
+//   - count: the maximum number of unacknowledged messages
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder for method chaining
+func (c *consumerChannelAdapterBuilder) WithPrefetchCount(
+	count int,
+) *consumerChannelAdapterBuilder {
+	c.prefetchCount = max(count, 0)
+	return c
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (c *consumerChannelAdapterBuilder) ConnectionReferenceName() string {
+	return c.connectionReferenceName
+}
+
+// WithDynamicReplyChannels publishes the replies addressed to queues with no
+// registered publisher channel, e.g. the private reply queue of a requester,
+// creating their publisher channels on the connection of the consumer on
+// first use. Only the queues matching one of the allowed patterns are
+// created.
+//
+// Parameters:
+//   - allowedPatterns: path.Match patterns of the reply queues allowed to be
+//     created, e.g. "replies.*"
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: builder for method chaining
+func (c *consumerChannelAdapterBuilder) WithDynamicReplyChannels(
+	allowedPatterns ...string,
+) *consumerChannelAdapterBuilder {
+	connectionReferenceName := c.connectionReferenceName
+	c.WithReplyChannelFactory(
+		func(
+			container container.Container[any, any],
+			queueName string,
+		) (message.PublisherChannel, error) {
+			publisher, err := NewPublisherChannelAdapterBuilder(
+				connectionReferenceName,
+				queueName,
+			).Build(container)
+			if err != nil {
+				return nil, err
+			}
+			return publisher.(message.PublisherChannel), nil
+		},
+		allowedPatterns...,
+	)
+	return c
+}
+
 // Build constructs a RabbitMQ inbound channel adapter from the dependency
 // container by retrieving the connection and creating a consumer channel.
 //
@@ -139,16 +249,12 @@ func (c *consumerChannelAdapterBuilder) WithArguments(args amqp091.Table) *consu
 func (c *consumerChannelAdapterBuilder) Build(
 	container container.Container[any, any],
 ) (*adapter.InboundChannelAdapter, error) {
-	con, err := container.Get(c.connectionReferenceName)
-
+	conn, err := getConnection(container, c.connectionReferenceName)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"[RabbitMQ-inbound-channel] connection %s does not exist",
-			c.connectionReferenceName,
-		)
+		return nil, fmt.Errorf("[RabbitMQ-inbound-channel] %w", err)
 	}
 
-	consumer, err := con.(*connection).GetConnection().Channel()
+	consumer, err := conn.Consumer()
 	if err != nil {
 		return nil, fmt.Errorf(
 			"[RabbitMQ-inbound-channel] consumer %s could not be created: %s",
@@ -156,6 +262,18 @@ func (c *consumerChannelAdapterBuilder) Build(
 			err.Error(),
 		)
 	}
+	if err := consumer.Qos(c.prefetchCount, 0, false); err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf(
+			"[RabbitMQ-inbound-channel] failed to set the prefetch count of %s: %w",
+			c.ReferenceName(),
+			err,
+		)
+	}
+	if err := c.declareBindings(consumer); err != nil {
+		consumer.Close()
+		return nil, err
+	}
 	adapter := NewInboundChannelAdapter(
 		consumer,
 		c.ReferenceName(),
@@ -165,18 +283,90 @@ func (c *consumerChannelAdapterBuilder) Build(
 		c.noWait,
 		c.args,
 	)
-	return c.InboundChannelAdapterBuilder.BuildInboundAdapter(adapter), nil
+	inbound, err := c.InboundChannelAdapterBuilder.BuildInboundAdapter(adapter)
+	if err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("[RabbitMQ-inbound-channel] %w", err)
+	}
+	return inbound, nil
+}
+
+// declareBindings declares the consumed queue, the exchanges and the bindings
+// of the channel, doing nothing when no binding is set.
+func (c *consumerChannelAdapterBuilder) declareBindings(
+	consumer *amqp091.Channel,
+) error {
+	if len(c.bindings) == 0 {
+		return nil
+	}
+
+	_, err := consumer.QueueDeclare(
+		c.ReferenceName(),
+		true,
+		false,
+		c.exclusive,
+		c.noWait,
+		c.args,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"[RabbitMQ-inbound-channel] failed to declare queue %s: %w",
+			c.ReferenceName(),
+			err,
+		)
+	}
+
+	for _, binding := range c.bindings {
+		err := consumer.ExchangeDeclare(
+			binding.exchange,
+			binding.exchangeType.Type(),
+			true,
+			false,
+			false,
+			c.noWait,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"[RabbitMQ-inbound-channel] failed to declare exchange %s: %w",
+				binding.exchange,
+				err,
+			)
+		}
+		err = consumer.QueueBind(
+			c.ReferenceName(),
+			binding.routingKey,
+			binding.exchange,
+			c.noWait,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"[RabbitMQ-inbound-channel] failed to bind queue %s to exchange %s: %w",
+				c.ReferenceName(),
+				binding.exchange,
+				err,
+			)
+		}
+	}
+	return nil
 }
 
 // NewInboundChannelAdapter creates a new RabbitMQ inbound channel adapter
 // instance with OpenTelemetry tracing support. It automatically starts a
-// goroutine to subscribe to the queue and process incoming messages.
+// goroutine to subscribe to the queue and process incoming messages, under a
+// unique consumer tag derived from the queue name.
 //
 // Parameters:
 //   - consumer: the RabbitMQ channel for receiving messages
 //   - queue: the RabbitMQ queue name to consume from
 //   - messageTranslator: translator for converting RabbitMQ messages to
 //     internal format
+//   - noLocal: whether messages published on the same connection are not
+//     delivered
+//   - exclusive: whether the queue is consumed exclusively
+//   - noWait: whether the server confirmation of the consume is not awaited
+//   - args: optional consume arguments
 //
 // Returns:
 //   - *inboundChannelAdapter: configured inbound channel adapter with active
@@ -190,6 +380,7 @@ func NewInboundChannelAdapter(
 	noWait bool,
 	args amqp091.Table,
 ) *inboundChannelAdapter {
+	ctx, cancel := context.WithCancel(context.Background())
 	adp := &inboundChannelAdapter{
 		consumer:          consumer,
 		queue:             queue,
@@ -197,7 +388,15 @@ func NewInboundChannelAdapter(
 		messageChannel:    make(chan *message.Message),
 		errorChannel:      make(chan error),
 		otelTrace:         otel.InitTrace("rabbitMQ-inbound-channel-adapter"),
-		stopTrigger:       make(chan bool),
+		otelMetrics:       otel.InitMetrics("rabbitMQ-inbound-channel-adapter"),
+		noLocal:           noLocal,
+		exclusive:         exclusive,
+		noWait:            noWait,
+		args:              args,
+		consumerTag:       fmt.Sprintf("%s-%s", queue, uuid.NewString()),
+		ctx:               ctx,
+		cancelCtx:         cancel,
+		done:              make(chan struct{}),
 	}
 	go adp.subscribeOnQueue()
 	return adp
@@ -211,6 +410,15 @@ func (a *inboundChannelAdapter) Name() string {
 	return a.queue
 }
 
+// ConsumerTag returns the tag identifying the consumer of the adapter on the
+// broker.
+//
+// Returns:
+//   - string: the consumer tag
+func (a *inboundChannelAdapter) ConsumerTag() string {
+	return a.consumerTag
+}
+
 // Receive receives a message from the RabbitMQ queue using a non-blocking
 // select pattern that respects context cancellation.
 //
@@ -228,67 +436,148 @@ func (a *inboundChannelAdapter) Receive(
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
+	case <-a.ctx.Done():
+		return nil, a.ctx.Err()
 	case msg := <-a.messageChannel:
+		a.otelMetrics.AddConsumed(ctx, otel.MessageSystemTypeRabbitMQ, a.queue, msg)
 		return msg, nil
 	case err := <-a.errorChannel:
 		return nil, err
+	case <-a.done:
+		if err := a.Err(); err != nil {
+			return nil, err
+		}
+		return nil, context.Canceled
 	}
 }
 
-// Close gracefully closes the RabbitMQ inbound channel adapter and stops
-// message consumption.
+// Err returns the terminal failure which stopped the consumption of the
+// queue, e.g. the consumer cancelled by the broker when its queue is
+// deleted. Receive returns this error once the consumption has stopped.
+//
+// Returns:
+//   - error: the terminal failure, nil while consuming or after Close
+func (a *inboundChannelAdapter) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// Close gracefully closes the RabbitMQ inbound channel adapter: the consumer
+// is cancelled on the broker, so no new message is delivered, and the channel
+// is closed once the subscription ends, requeueing the unacknowledged
+// messages. Closing more than once has no effect.
 //
 // Returns:
-//   - error: error if closing fails (typically nil)
+//   - error: error if closing the channel fails
 func (a *inboundChannelAdapter) Close() error {
-	close(a.stopTrigger)
-	a.consumer.Close()
-	return nil
+	var err error
+	a.closeOnce.Do(func() {
+		a.cancelCtx()
+		if cancelErr := a.consumer.Cancel(a.consumerTag, false); cancelErr != nil {
+			slog.Warn("[rabbitmq-inbound-channel] failed to cancel consumer",
+				"consumerTag", a.consumerTag,
+				"reason", cancelErr.Error(),
+			)
+		}
+		<-a.done
+		err = a.consumer.Close()
+	})
+	return err
 }
 
 // subscribeOnQueue subscribes to the RabbitMQ queue and processes incoming
 // messages continuously. This method runs in a separate goroutine and handles
-// message translation and error propagation.
+// message translation and error propagation, reporting the cancellation of
+// the consumer by the broker, e.g. when its queue is deleted.
 func (a *inboundChannelAdapter) subscribeOnQueue() {
-	defer func() {
-		close(a.messageChannel)
-		close(a.errorChannel)
-	}()
+	defer close(a.done)
 
+	cancellations := a.consumer.NotifyCancel(make(chan string, 1))
 	rabbitmqMessages, err := a.consumer.Consume(
 		a.queue,
-		"",    // consumer tag (server generates if empty)
+		a.consumerTag,
 		false, // auto-ack (we handle ack manually)
 		a.exclusive,
 		a.noLocal,
 		a.noWait,
 		a.args,
 	)
-
 	if err != nil {
-		a.errorChannel <- fmt.Errorf("failed to start consuming: %w", err)
+		a.fail(fmt.Errorf(
+			"[rabbitmq-inbound-channel] failed to start consuming queue %s: %w",
+			a.queue,
+			err,
+		))
 		return
 	}
 
-	for msg := range rabbitmqMessages {
-		message, translateErr := a.messageTranslator.ToMessage(msg)
-		if translateErr != nil {
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case tag := <-cancellations:
+			a.fail(fmt.Errorf(
+				"[rabbitmq-inbound-channel] consumer %s cancelled by the broker",
+				tag,
+			))
+			return
+		case msg, ok := <-rabbitmqMessages:
+			if !ok {
+				if a.ctx.Err() != nil {
+					return
+				}
+				a.fail(fmt.Errorf(
+					"[rabbitmq-inbound-channel] deliveries of queue %s closed",
+					a.queue,
+				))
+				return
+			}
+			translated, translateErr := a.messageTranslator.ToMessage(msg)
+			if translateErr != nil {
+				a.discard(msg)
+				a.sendError(fmt.Errorf("%w: %w", message.ErrTranslation, translateErr))
+				continue
+			}
+
 			select {
-			case a.errorChannel <- translateErr:
-			case <-a.stopTrigger:
+			case <-a.ctx.Done():
 				return
+			case a.messageChannel <- translated:
 			}
-			continue
 		}
+	}
+}
 
-		select {
-		case <-a.stopTrigger:
-			return
-		case a.messageChannel <- message:
-		}
+// discard rejects a delivery which cannot be translated, without requeue,
+// so it is dead-lettered when the queue has a dead letter exchange instead
+// of holding a prefetch slot until the channel is closed.
+func (a *inboundChannelAdapter) discard(delivery amqp091.Delivery) {
+	if err := delivery.Nack(false, false); err != nil {
+		slog.Warn("[rabbitmq-inbound-channel] failed to discard message",
+			"queue", a.queue,
+			"deliveryTag", delivery.DeliveryTag,
+			"reason", err.Error(),
+		)
 	}
 }
 
+// sendError reports a consumption error to the receivers, unless the adapter
+// is closed.
+func (a *inboundChannelAdapter) sendError(err error) {
+	select {
+	case <-a.ctx.Done():
+	case a.errorChannel <- err:
+	}
+}
+
+// fail records the terminal failure of the consumption.
+func (a *inboundChannelAdapter) fail(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.err = err
+}
+
 // CommitMessage acknowledges a message to RabbitMQ, confirming successful
 // processing. This removes the message from the queue.
 //
@@ -307,3 +596,25 @@ func (a *inboundChannelAdapter) CommitMessage(
 		"[rabbitmq-inbound-channel] failed to commit message",
 	)
 }
+
+// NackMessage negatively acknowledges a message to RabbitMQ, requeueing it
+// for redelivery or discarding it, in which case it is dead-lettered when the
+// queue has a dead letter exchange.
+//
+// Parameters:
+//   - msg: the message to settle
+//   - requeue: true to requeue the message, false to discard it
+//
+// Returns:
+//   - error: error if the settlement fails or message type is invalid
+func (a *inboundChannelAdapter) NackMessage(
+	msg *message.Message,
+	requeue bool,
+) error {
+	if externalMessage, ok := msg.GetRawMessage().(amqp091.Delivery); ok {
+		return externalMessage.Nack(false, requeue)
+	}
+	return fmt.Errorf(
+		"[rabbitmq-inbound-channel] failed to nack message",
+	)
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"maps"
+	"strconv"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/otel"
@@ -14,14 +17,31 @@ import (
 
 // MessageTranslator provides message translation capabilities between internal
 // message formats and RabbitMQ-specific AMQP formats.
-type MessageTranslator struct{}
+type MessageTranslator struct {
+	headerCodec message.HeaderCodec
+}
 
 // NewMessageTranslator creates a new message translator instance.
 //
 // Returns:
 //   - *MessageTranslator: new message translator instance
 func NewMessageTranslator() *MessageTranslator {
-	return &MessageTranslator{}
+	return &MessageTranslator{headerCodec: message.DefaultHeaderCodec()}
+}
+
+// WithHeaderCodec sets the codec used to convert typed AMQP header values
+// (integers, booleans, timestamps and arrays) into message headers.
+//
+// Parameters:
+//   - codec: the header codec
+//
+// Returns:
+//   - *MessageTranslator: translator instance for method chaining
+func (m *MessageTranslator) WithHeaderCodec(
+	codec message.HeaderCodec,
+) *MessageTranslator {
+	m.headerCodec = codec
+	return m
 }
 
 // FromMessage translates an internal message to RabbitMQ AMQP Publishing
@@ -40,11 +60,12 @@ func (m *MessageTranslator) FromMessage(
 ) (*amqp.Publishing, error) {
 
 	headersMap := msg.GetHeader()
-	contextPropagator := otel.GetTraceContextPropagatorByContext(
-		msg.GetContext(),
-	)
-	if contextPropagator != nil {
-		maps.Copy(headersMap, contextPropagator)
+	if otel.IsTraceEnabled() {
+		headersMap = message.Header(msg.GetHeader().All())
+		maps.Copy(
+			headersMap,
+			otel.GetTraceContextPropagatorByContext(msg.GetContext()),
+		)
 	}
 
 	pld, err := json.Marshal(msg.GetPayload())
@@ -55,28 +76,47 @@ func (m *MessageTranslator) FromMessage(
 		)
 	}
 
+	contentEncoding := headersMap.Get(message.HeaderContentEncoding)
+	pld, err = message.CompressPayload(contentEncoding, pld)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[rabbitMQ-message-translator] converter error: %v",
+			err.Error(),
+		)
+	}
+
 	headers := amqp.Table{}
 	for k, v := range headersMap {
 		headers[k] = v
 	}
+	delay, delayed := deliveryDelay(headersMap)
+	if delayed {
+		headers[delayHeader] = delay
+	}
 
 	return &amqp.Publishing{
-		ContentType: "application/json",
-		Headers:     headers,
-		Body:        pld,
+		ContentType:     "application/json",
+		ContentEncoding: contentEncoding,
+		Headers:         headers,
+		Expiration:      expiration(msg, delay),
+		Priority:        uint8(min(max(msg.Priority(), 0), 255)),
+		Body:            pld,
 	}, nil
 }
 
 // ToMessage translates a RabbitMQ AMQP Delivery message to internal message
-// format. It extracts headers, reconstructs OpenTelemetry trace context if
+// format. It extracts headers, encoding typed AMQP header values with the
+// header codec, reconstructs OpenTelemetry trace context if
 // present, and builds the internal message with the raw AMQP delivery.
+// Headers whose type the codec does not support, e.g. nested tables or
+// decimals set by the broker or other producers, are skipped.
 //
 // Parameters:
 //   - msg: the AMQP delivery message to translate
 //
 // Returns:
 //   - *message.Message: internal message with extracted headers and payload
-//   - error: error if header conversion or message building fails
+//   - error: error if payload conversion or message building fails
 func (m *MessageTranslator) ToMessage(
 	msg amqp.Delivery,
 ) (*message.Message, error) {
@@ -84,9 +124,32 @@ func (m *MessageTranslator) ToMessage(
 	for k, h := range msg.Headers {
 		if strVal, ok := h.(string); ok {
 			headers[k] = strVal
+			continue
+		}
+
+		value, err := m.headerCodec.Encode(h)
+		if err != nil {
+			slog.Debug("[rabbitMQ-message-translator] skipped unsupported header",
+				"header", k,
+				"type", fmt.Sprintf("%T", h),
+			)
+			continue
 		}
+		headers[k] = value
 	}
 
+	payload, err := message.DecompressPayload(
+		headers[message.HeaderContentEncoding],
+		msg.Body,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[rabbitMQ-message-translator] converter error: %v",
+			err.Error(),
+		)
+	}
+	delete(headers, message.HeaderContentEncoding)
+
 	messageBuilder, err := message.NewMessageBuilderFromHeaders(headers)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -95,18 +158,51 @@ func (m *MessageTranslator) ToMessage(
 		)
 	}
 
-	traceParenValue, exists := headers["Traceparent"]
-	if exists && traceParenValue != "" {
-		ctx := otel.GetTraceContextPropagatorByTraceParent(
-			context.Background(),
-			traceParenValue,
+	if otel.IsTraceEnabled() {
+		messageBuilder.WithContext(
+			otel.GetTraceContextPropagatorByHeaders(context.Background(), headers),
 		)
-		messageBuilder.WithContext(ctx)
 	}
 
-	messageBuilder.WithPayload(msg.Body)
+	messageBuilder.WithPayload(payload)
 	messageBuilder.WithRawMessage(msg)
 	buildedMessage := messageBuilder.Build()
 
 	return buildedMessage, nil
 }
+
+// delayHeader is the header holding the delivery delay, in milliseconds, of
+// messages published to a delayed-message exchange.
+const delayHeader = "x-delay"
+
+// deliveryDelay returns the delay until the scheduledAt header of a message.
+func deliveryDelay(headers message.Header) (int64, bool) {
+	scheduledAt := headers.Get(message.HeaderScheduledAt)
+	if scheduledAt == "" {
+		return 0, false
+	}
+	dueAt, err := time.Parse(time.RFC3339Nano, scheduledAt)
+	if err != nil {
+		return 0, false
+	}
+	delay := time.Until(dueAt).Milliseconds()
+	if delay <= 0 {
+		return 0, false
+	}
+	return delay, true
+}
+
+// expiration returns the broker-native time to live, in milliseconds, of a
+// message with the expiresAt header. The delivery delay is discounted, since
+// the broker counts the time to live from when the message reaches a queue.
+func expiration(msg *message.Message, delay int64) string {
+	expiresAt, ok := msg.ExpiresAt()
+	if !ok {
+		return ""
+	}
+	ttl := time.Until(expiresAt).Milliseconds() - delay
+	if ttl < 0 {
+		ttl = 0
+	}
+	return strconv.FormatInt(ttl, 10)
+}
@@ -4,6 +4,7 @@ package rabbitmq
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
@@ -25,6 +26,7 @@ const (
 	ExchangeDirect
 	ExchangeTopic
 	ExchangeHeaders
+	ExchangeDelayed
 )
 
 type exchangeType int8
@@ -45,6 +47,7 @@ type publisherChannelAdapterBuilder struct {
 	exclusive               bool
 	noWait                  bool
 	args                    amqp.Table
+	maxPriority             uint8
 }
 
 // outboundChannelAdapter implements the OutboundChannelAdapter interface for
@@ -57,6 +60,7 @@ type outboundChannelAdapter struct {
 	exchangeRoutingKeys string
 	channelType         producerChannelType
 	otelTrace           otel.OtelTrace
+	otelMetrics         otel.OtelMetrics
 }
 
 // NewPublisherChannelAdapterBuilder creates a new RabbitMQ publishing channel
@@ -93,6 +97,7 @@ func NewPublisherChannelAdapterBuilder(
 		false, // exclusive
 		false, // no-wait
 		nil,   // arguments
+		0,     // max priority
 	}
 	return builder
 }
@@ -123,6 +128,7 @@ func NewOutboundChannelAdapter(
 		exchangeRoutingKeys: exchangeRoutingKeys,
 		channelType:         channelType,
 		otelTrace:           otel.InitTrace("rabbitmq-outbound-channel-adapter"),
+		otelMetrics:         otel.InitMetrics("rabbitmq-outbound-channel-adapter"),
 	}
 }
 
@@ -138,6 +144,8 @@ func (t exchangeType) Type() string {
 		return amqp.ExchangeHeaders
 	case ExchangeTopic:
 		return amqp.ExchangeTopic
+	case ExchangeDelayed:
+		return "x-delayed-message"
 	default:
 		return amqp.ExchangeDirect
 	}
@@ -163,7 +171,9 @@ func (b *publisherChannelAdapterBuilder) WithExchangeRoutingKeys(
 //
 // Parameters:
 //   - value: exchange type (ExchangeDirect, ExchangeFanout, ExchangeTopic,
-//     ExchangeHeaders)
+//     ExchangeHeaders, ExchangeDelayed). ExchangeDelayed requires the
+//     delayed-message plugin and routes as a direct exchange unless the
+//     x-delayed-type argument is set
 //
 // Returns:
 //   - *publisherChannelAdapterBuilder: builder for method chaining
@@ -261,6 +271,31 @@ func (b *publisherChannelAdapterBuilder) WithArguments(args amqp.Table) *publish
 	return b
 }
 
+// WithMaxPriority declares the queue as a priority queue, through the
+// x-max-priority argument, so the broker delivers messages with a higher
+// priority header first. Only queue channels are declared with it.
+//
+// Parameters:
+//   - value: the maximum message priority, recommended up to 10
+//
+// Returns:
+//   - *publisherChannelAdapterBuilder: builder for method chaining
+func (b *publisherChannelAdapterBuilder) WithMaxPriority(
+	value uint8,
+) *publisherChannelAdapterBuilder {
+	b.maxPriority = value
+	return b
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (b *publisherChannelAdapterBuilder) ConnectionReferenceName() string {
+	return b.connectionReferenceName
+}
+
 // Build constructs a RabbitMQ outbound channel adapter from the dependency
 // container by retrieving the connection and creating a producer channel.
 //
@@ -273,16 +308,12 @@ func (b *publisherChannelAdapterBuilder) WithArguments(args amqp.Table) *publish
 func (b *publisherChannelAdapterBuilder) Build(
 	container container.Container[any, any],
 ) (endpoint.OutboundChannelAdapter, error) {
-	con, err := container.Get(b.connectionReferenceName)
-
+	conn, err := getConnection(container, b.connectionReferenceName)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"[RabbitMQ-outbound-channel] connection %s does not exist",
-			b.connectionReferenceName,
-		)
+		return nil, fmt.Errorf("[RabbitMQ-outbound-channel] %w", err)
 	}
 
-	producer, err := con.(*connection).GetConnection().Channel()
+	producer, err := conn.GetConnection().Channel()
 	if err != nil {
 		return nil, fmt.Errorf(
 			"[RabbitMQ-outbound-channel] failed to create producer channel: %w",
@@ -291,6 +322,11 @@ func (b *publisherChannelAdapterBuilder) Build(
 	}
 
 	if b.channelType == ProducerExchange {
+		args := b.args
+		if b.exchangeType == ExchangeDelayed && args["x-delayed-type"] == nil {
+			args = amqp.Table{"x-delayed-type": amqp.ExchangeDirect}
+			maps.Copy(args, b.args)
+		}
 		err = producer.ExchangeDeclare(
 			b.ChannelName(),
 			b.exchangeType.Type(),
@@ -298,16 +334,21 @@ func (b *publisherChannelAdapterBuilder) Build(
 			b.deleteUnused,
 			b.exclusive,
 			b.noWait,
-			b.args,
+			args,
 		)
 	} else {
+		args := b.args
+		if b.maxPriority > 0 && args["x-max-priority"] == nil {
+			args = amqp.Table{"x-max-priority": int(b.maxPriority)}
+			maps.Copy(args, b.args)
+		}
 		_, err = producer.QueueDeclare(
 			b.ChannelName(),
 			b.durable,
 			b.deleteUnused,
 			b.exclusive,
 			b.noWait,
-			b.args,
+			args,
 		)
 	}
 
@@ -353,7 +394,7 @@ func (a *outboundChannelAdapter) Send(
 	ctx context.Context,
 	msg *message.Message,
 ) error {
-	_, span := a.otelTrace.Start(
+	ctx, span := a.otelTrace.Start(
 		ctx,
 		"",
 		otel.WithMessagingSystemType(otel.MessageSystemTypeRabbitMQ),
@@ -362,6 +403,10 @@ func (a *outboundChannelAdapter) Send(
 		otel.WithMessage(msg),
 	)
 	defer span.End()
+	if otel.IsTraceEnabled() {
+		// the translator propagates the producer span through the headers
+		msg.SetContext(ctx)
+	}
 
 	select {
 	case <-ctx.Done():
@@ -391,9 +436,38 @@ func (a *outboundChannelAdapter) Send(
 		false, // immediate
 		*msgToSend,
 	)
+	a.otelMetrics.AddPublished(ctx, otel.MessageSystemTypeRabbitMQ, a.channelName, msg, err)
 	return err
 }
 
+// SendBatch publishes the messages through the producer channel in order,
+// without stopping on failures. Failed messages are reported by a
+// *message.BatchError indexed by their position. AMQP has no batch publish:
+// each message is a separate publish on the same channel, so the batch saves
+// no broker round trip over sending the messages one by one.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msgs: the messages to be published
+//
+// Returns:
+//   - error: *message.BatchError if any message fails
+func (a *outboundChannelAdapter) SendBatch(
+	ctx context.Context,
+	msgs []*message.Message,
+) error {
+	failures := map[int]error{}
+	for i, msg := range msgs {
+		if err := a.Send(ctx, msg); err != nil {
+			failures[i] = err
+		}
+	}
+	if len(failures) > 0 {
+		return &message.BatchError{Errors: failures}
+	}
+	return nil
+}
+
 // Close gracefully closes the RabbitMQ producer channel and releases
 // associated resources.
 //
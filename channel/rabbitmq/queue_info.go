@@ -0,0 +1,102 @@
+// Package rabbitmq provides RabbitMQ queue introspection functionality.
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/otel"
+)
+
+// QueueStats holds the broker-side state of a RabbitMQ queue.
+type QueueStats struct {
+	Name      string
+	Messages  int
+	Consumers int
+}
+
+// QueueInfo returns the current depth and consumer count of the given queue
+// using the shared RabbitMQ connection.
+//
+// Parameters:
+//   - queue: the RabbitMQ queue name to inspect
+//
+// Returns:
+//   - *QueueStats: the queue depth and consumer count
+//   - error: error if no connection is available or the queue does not exist
+func QueueInfo(queue string) (*QueueStats, error) {
+	if conInstance == nil {
+		return nil, fmt.Errorf(
+			"[RabbitMQ-queue-info] connection has not been created",
+		)
+	}
+	return conInstance.QueueInfo(queue)
+}
+
+// QueueInfo returns the current depth and consumer count of the given queue.
+// The inspection uses a passive declaration on a dedicated channel, so it
+// never creates the queue nor affects channels used by the adapters.
+//
+// Parameters:
+//   - queue: the RabbitMQ queue name to inspect
+//
+// Returns:
+//   - *QueueStats: the queue depth and consumer count
+//   - error: error if the connection is not established or the queue does not
+//     exist
+func (c *connection) QueueInfo(queue string) (*QueueStats, error) {
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil, fmt.Errorf(
+			"[RabbitMQ-queue-info] connection %s is not established",
+			c.name,
+		)
+	}
+
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[RabbitMQ-queue-info] channel could not be created: %s",
+			err.Error(),
+		)
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(queue, false, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[RabbitMQ-queue-info] queue %s could not be inspected: %s",
+			queue,
+			err.Error(),
+		)
+	}
+
+	return &QueueStats{
+		Name:      q.Name,
+		Messages:  q.Messages,
+		Consumers: q.Consumers,
+	}, nil
+}
+
+// Backlog returns the depth of the queue consumed by the adapter.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - int64: the messages ready in the queue
+//   - error: error if the queue cannot be inspected
+func (a *inboundChannelAdapter) Backlog(ctx context.Context) (int64, error) {
+	stats, err := QueueInfo(a.queue)
+	if err != nil {
+		return 0, err
+	}
+	return int64(stats.Messages), nil
+}
+
+// MessagingSystem returns the messaging system of the adapter.
+//
+// Returns:
+//   - otel.MessageSystemType: the RabbitMQ messaging system
+func (a *inboundChannelAdapter) MessagingSystem() otel.MessageSystemType {
+	return otel.MessageSystemTypeRabbitMQ
+}
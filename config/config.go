@@ -0,0 +1,330 @@
+// Package config provides the declarative configuration of the message system
+// topology, loaded from YAML files.
+//
+// The Config implementation supports:
+// - Connections, publisher channels and consumer channels declared in YAML
+// - Environment variable interpolation with ${VAR} and ${VAR:-default}
+// - Retries, dead letter channels and acknowledgment modes per consumer
+// - Consumer tuning (processors, processing timeout, stop on error)
+// - Validation of the declared topology before it is materialized
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported connection drivers.
+const (
+	DriverKafka    = "kafka"
+	DriverRabbitMQ = "rabbitmq"
+)
+
+// Supported acknowledgment modes.
+const (
+	AckModeAuto      = "auto"
+	AckModeOnSuccess = "on-success"
+	AckModeManual    = "manual"
+)
+
+// Supported consumer error policies.
+const (
+	ErrorPolicyStop           = "stop"
+	ErrorPolicyContinue       = "continue"
+	ErrorPolicyDLQAndContinue = "dlq-and-continue"
+	ErrorPolicyPauseAndAlert  = "pause-and-alert"
+)
+
+// envPattern matches the ${VAR} and ${VAR:-default} placeholders.
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Config declares the topology of the message system.
+type Config struct {
+	Connections []ConnectionConfig `yaml:"connections"`
+	Publishers  []PublisherConfig  `yaml:"publishers"`
+	Consumers   []ConsumerConfig   `yaml:"consumers"`
+}
+
+// ConnectionConfig declares a broker connection.
+type ConnectionConfig struct {
+	// Name is the reference name of the connection.
+	Name string `yaml:"name"`
+	// Driver is the broker of the connection, kafka or rabbitmq.
+	Driver string `yaml:"driver"`
+	// Hosts are the broker addresses. RabbitMQ connections use the first one.
+	Hosts []string `yaml:"hosts"`
+}
+
+// PublisherConfig declares a publisher channel.
+type PublisherConfig struct {
+	// Connection is the reference name of the connection of the channel.
+	Connection string `yaml:"connection"`
+	// Channel is the topic, queue or exchange the messages are published to.
+	Channel string `yaml:"channel"`
+	// ReplyChannel is the channel the replies are received from.
+	ReplyChannel string `yaml:"replyChannel"`
+	// Exchange publishes to a RabbitMQ exchange of the given type (direct,
+	// fanout, topic or headers) instead of a queue.
+	Exchange string `yaml:"exchange"`
+	// RoutingKey is the routing key of the RabbitMQ exchange.
+	RoutingKey string `yaml:"routingKey"`
+}
+
+// ConsumerConfig declares a consumer channel and its consumer tuning.
+type ConsumerConfig struct {
+	// Name is the reference name of the consumer, also the Kafka consumer group.
+	Name string `yaml:"name"`
+	// Connection is the reference name of the connection of the channel.
+	Connection string `yaml:"connection"`
+	// Channel is the topic or queue the messages are consumed from.
+	Channel string `yaml:"channel"`
+	// DeadLetterChannel is the publisher channel receiving the failed messages.
+	DeadLetterChannel string `yaml:"deadLetterChannel"`
+	// RetryAttempts are the delays, in milliseconds, of the processing retries.
+	RetryAttempts []int `yaml:"retryAttempts"`
+	// AckMode is the acknowledgment mode: auto, on-success or manual.
+	AckMode string `yaml:"ackMode"`
+	// Processors is the amount of concurrent processors of the consumer.
+	Processors int `yaml:"processors"`
+	// ProcessingTimeout is the message processing timeout, e.g. "5s".
+	ProcessingTimeout time.Duration `yaml:"processingTimeout"`
+	// ErrorPolicy is the consumer error policy: stop, continue,
+	// dlq-and-continue or pause-and-alert.
+	ErrorPolicy string `yaml:"errorPolicy"`
+	// StopOnError stops the consumer when a message fails.
+	//
+	// Deprecated: use ErrorPolicy, which takes precedence when set.
+	StopOnError *bool `yaml:"stopOnError"`
+	// CommitEvery batches the Kafka offset commits every amount of messages.
+	CommitEvery int `yaml:"commitEvery"`
+	// CommitInterval batches the Kafka offset commits every interval.
+	CommitInterval time.Duration `yaml:"commitInterval"`
+}
+
+// Load reads, interpolates and validates the configuration file at path.
+//
+// Parameters:
+//   - path: the path of the YAML configuration file
+//
+// Returns:
+//   - *Config: the validated configuration
+//   - error: error if the file cannot be read, parsed or is invalid
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[config] cannot read %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse interpolates the environment variables of a YAML configuration and
+// decodes and validates it.
+//
+// Parameters:
+//   - data: the YAML configuration
+//
+// Returns:
+//   - *Config: the validated configuration
+//   - error: error if a variable is not set, or the configuration cannot be
+//     parsed or is invalid
+func Parse(data []byte) (*Config, error) {
+	interpolated, err := interpolate(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal([]byte(interpolated), config); err != nil {
+		return nil, fmt.Errorf("[config] cannot parse configuration: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate checks the declared topology, reporting every invalid entry.
+//
+// Returns:
+//   - error: the joined validation errors, nil if the configuration is valid
+func (c *Config) Validate() error {
+	var errs []error
+	drivers := map[string]string{}
+	for i, connection := range c.Connections {
+		switch {
+		case connection.Name == "":
+			errs = append(errs, fmt.Errorf("[config] connections[%d]: name is required", i))
+			continue
+		case drivers[connection.Name] != "":
+			errs = append(errs, fmt.Errorf("[config] connection %s is declared twice", connection.Name))
+			continue
+		}
+		if connection.Driver != DriverKafka && connection.Driver != DriverRabbitMQ {
+			errs = append(errs, fmt.Errorf(
+				"[config] connection %s: unsupported driver %q",
+				connection.Name,
+				connection.Driver,
+			))
+		}
+		if len(connection.Hosts) == 0 {
+			errs = append(errs, fmt.Errorf("[config] connection %s: hosts are required", connection.Name))
+		}
+		drivers[connection.Name] = connection.Driver
+	}
+
+	publishers := map[string]bool{}
+	for i, publisher := range c.Publishers {
+		if publisher.Channel == "" {
+			errs = append(errs, fmt.Errorf("[config] publishers[%d]: channel is required", i))
+			continue
+		}
+		if publishers[publisher.Channel] {
+			errs = append(errs, fmt.Errorf("[config] publisher %s is declared twice", publisher.Channel))
+		}
+		publishers[publisher.Channel] = true
+		driver, ok := drivers[publisher.Connection]
+		if !ok {
+			errs = append(errs, fmt.Errorf(
+				"[config] publisher %s: unknown connection %q",
+				publisher.Channel,
+				publisher.Connection,
+			))
+		}
+		if publisher.Exchange != "" {
+			if ok && driver != DriverRabbitMQ {
+				errs = append(errs, fmt.Errorf(
+					"[config] publisher %s: exchange is supported by rabbitmq connections only",
+					publisher.Channel,
+				))
+			}
+			if _, valid := exchangeTypes[publisher.Exchange]; !valid {
+				errs = append(errs, fmt.Errorf(
+					"[config] publisher %s: unsupported exchange type %q",
+					publisher.Channel,
+					publisher.Exchange,
+				))
+			}
+		}
+	}
+
+	consumers := map[string]bool{}
+	for i, consumer := range c.Consumers {
+		if consumer.Name == "" || consumer.Channel == "" {
+			errs = append(errs, fmt.Errorf("[config] consumers[%d]: name and channel are required", i))
+			continue
+		}
+		if consumers[consumer.Name] {
+			errs = append(errs, fmt.Errorf("[config] consumer %s is declared twice", consumer.Name))
+		}
+		consumers[consumer.Name] = true
+		driver, ok := drivers[consumer.Connection]
+		if !ok {
+			errs = append(errs, fmt.Errorf(
+				"[config] consumer %s: unknown connection %q",
+				consumer.Name,
+				consumer.Connection,
+			))
+		}
+		switch consumer.AckMode {
+		case "", AckModeAuto, AckModeOnSuccess, AckModeManual:
+		default:
+			errs = append(errs, fmt.Errorf(
+				"[config] consumer %s: unsupported ack mode %q",
+				consumer.Name,
+				consumer.AckMode,
+			))
+		}
+		switch consumer.ErrorPolicy {
+		case "", ErrorPolicyStop, ErrorPolicyContinue,
+			ErrorPolicyDLQAndContinue, ErrorPolicyPauseAndAlert:
+		default:
+			errs = append(errs, fmt.Errorf(
+				"[config] consumer %s: unsupported error policy %q",
+				consumer.Name,
+				consumer.ErrorPolicy,
+			))
+		}
+		for _, delay := range consumer.RetryAttempts {
+			if delay < 0 {
+				errs = append(errs, fmt.Errorf(
+					"[config] consumer %s: retry attempts cannot be negative",
+					consumer.Name,
+				))
+				break
+			}
+		}
+		if consumer.Processors < 0 || consumer.ProcessingTimeout < 0 {
+			errs = append(errs, fmt.Errorf(
+				"[config] consumer %s: processors and processing timeout cannot be negative",
+				consumer.Name,
+			))
+		}
+		if consumer.CommitEvery < 0 || consumer.CommitInterval < 0 {
+			errs = append(errs, fmt.Errorf(
+				"[config] consumer %s: commit every and commit interval cannot be negative",
+				consumer.Name,
+			))
+		}
+		if ok && driver != DriverKafka && (consumer.CommitEvery > 0 || consumer.CommitInterval > 0) {
+			errs = append(errs, fmt.Errorf(
+				"[config] consumer %s: batched commits are supported by kafka connections only",
+				consumer.Name,
+			))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Driver returns the driver of a declared connection.
+//
+// Parameters:
+//   - connectionName: the reference name of the connection
+//
+// Returns:
+//   - string: the driver, empty if the connection is not declared
+func (c *Config) Driver(connectionName string) string {
+	for _, connection := range c.Connections {
+		if connection.Name == connectionName {
+			return connection.Driver
+		}
+	}
+	return ""
+}
+
+// exchangeTypes are the supported RabbitMQ exchange types.
+var exchangeTypes = map[string]struct{}{
+	"direct":  {},
+	"fanout":  {},
+	"topic":   {},
+	"headers": {},
+}
+
+// interpolate replaces the ${VAR} and ${VAR:-default} placeholders with the
+// environment variables.
+func interpolate(data string) (string, error) {
+	var missing []error
+	result := envPattern.ReplaceAllStringFunc(data, func(placeholder string) string {
+		groups := envPattern.FindStringSubmatch(placeholder)
+		if value, ok := os.LookupEnv(groups[1]); ok {
+			return value
+		}
+		if groups[2] != "" {
+			return groups[3]
+		}
+		missing = append(missing, fmt.Errorf(
+			"[config] environment variable %s is not set",
+			groups[1],
+		))
+		return placeholder
+	})
+	if err := errors.Join(missing...); err != nil {
+		return "", err
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/config"
+)
+
+const topology = `
+connections:
+  - name: kafka
+    driver: kafka
+    hosts: ["${GOMES_TEST_KAFKA_HOST:-localhost:9092}"]
+publishers:
+  - connection: kafka
+    channel: orders.dlq
+consumers:
+  - name: orders-consumer
+    connection: kafka
+    channel: orders
+    deadLetterChannel: orders.dlq
+    retryAttempts: [100, 200]
+    ackMode: on-success
+    processors: 4
+    processingTimeout: 5s
+`
+
+func TestLoad(t *testing.T) {
+	t.Run("should load and interpolate the configuration", func(t *testing.T) {
+		t.Setenv("GOMES_TEST_KAFKA_HOST", "broker:9092")
+		path := filepath.Join(t.TempDir(), "gomes.yaml")
+		if err := os.WriteFile(path, []byte(topology), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := config.Load(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Connections[0].Hosts[0] != "broker:9092" {
+			t.Errorf("expected interpolated host, got %s", cfg.Connections[0].Hosts[0])
+		}
+		consumer := cfg.Consumers[0]
+		if consumer.ProcessingTimeout != 5*time.Second || consumer.Processors != 4 {
+			t.Errorf("unexpected consumer tuning: %+v", consumer)
+		}
+		if len(consumer.RetryAttempts) != 2 || consumer.AckMode != config.AckModeOnSuccess {
+			t.Errorf("unexpected consumer channel: %+v", consumer)
+		}
+	})
+
+	t.Run("should use the default of unset variables", func(t *testing.T) {
+		cfg, err := config.Parse([]byte(topology))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Connections[0].Hosts[0] != "localhost:9092" {
+			t.Errorf("expected default host, got %s", cfg.Connections[0].Hosts[0])
+		}
+	})
+
+	t.Run("should fail when a variable without default is not set", func(t *testing.T) {
+		_, err := config.Parse([]byte(`connections: [{name: "${GOMES_TEST_UNSET}"}]`))
+		if err == nil || !strings.Contains(err.Error(), "GOMES_TEST_UNSET") {
+			t.Errorf("expected unset variable error, got %v", err)
+		}
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Connections: []config.ConnectionConfig{
+			{Name: "rabbit", Driver: config.DriverRabbitMQ, Hosts: []string{"amqp://localhost"}},
+			{Name: "nats", Driver: "nats", Hosts: []string{"localhost"}},
+		},
+		Consumers: []config.ConsumerConfig{
+			{Name: "orders", Connection: "missing", Channel: "orders"},
+			{Name: "payments", Connection: "rabbit", Channel: "payments", AckMode: "never", CommitEvery: 10, ErrorPolicy: "ignore"},
+		},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, expected := range []string{
+		`unsupported driver "nats"`,
+		`consumer orders: unknown connection "missing"`,
+		`unsupported ack mode "never"`,
+		`unsupported error policy "ignore"`,
+		"batched commits are supported by kafka connections only",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in %v", expected, err)
+		}
+	}
+}
@@ -1,19 +1,29 @@
 // Package container provides a generic container implementation for managing
 // key-value pairs with thread-safe operations. It supports setting, getting,
 // replacing, and removing items, with error handling for duplicate keys and
-// missing items.
+// missing items. Components of different kinds sharing a container are kept
+// apart by namespaced keys, see NamespacedKey. Items are started and stopped
+// through lifecycle hooks, the items being stopped in reverse registration
+// order and before the items they depend on.
 package container
 
 import (
+	"context"
 	"fmt"
 	"maps"
+	"slices"
 	"sync"
 )
 
 type (
 	genericContainer[K comparable, T any] struct {
-		mu        sync.RWMutex
-		container map[K]T
+		mu         sync.RWMutex
+		container  map[K]T
+		order      []K
+		startHooks map[K][]Hook
+		stopHooks  map[K][]Hook
+		dependents map[K][]K
+		started    map[K]bool
 	}
 	// Container defines the interface for a generic key-value container.
 	// It provides methods to manage items with error handling.
@@ -24,19 +34,37 @@ type (
 		Has(key K) bool
 		// Replace updates an existing item. Returns an error if the key does not exist.
 		Replace(key K, item T) error
+		// SetOrReplace adds an item, replacing the existing item of the key if any.
+		SetOrReplace(key K, item T)
 		// Get retrieves an item by key. Returns an error if the key is not found.
 		Get(key K) (T, error)
 		// GetAll returns a copy of all items in the container.
 		GetAll() map[K]T
-		// Remove deletes an item by key. Returns an error if the key is not found.
+		// Remove deletes an item by key, with its lifecycle hooks. Returns an error if the key is not found.
 		Remove(key K) error
+		// OnStart adds a hook run when the item is started. Returns an error if the key is not found.
+		OnStart(key K, hook Hook) error
+		// OnStop adds a hook run when the item is stopped. Returns an error if the key is not found.
+		OnStop(key K, hook Hook) error
+		// DependsOn records that an item depends on other items, so it is stopped before them.
+		DependsOn(key K, dependencies ...K) error
+		// Start runs the start hooks of the items not started yet, in registration order.
+		Start(ctx context.Context) error
+		// Stop runs the stop hooks of every item, dependents first, in reverse registration order.
+		Stop(ctx context.Context) error
 	}
 )
 
 // NewGenericContainer creates a new instance of a generic container.
 // It initializes an empty map for storing key-value pairs.
 func NewGenericContainer[K comparable, T any]() *genericContainer[K, T] {
-	return &genericContainer[K, T]{container: make(map[K]T)}
+	return &genericContainer[K, T]{
+		container:  make(map[K]T),
+		startHooks: make(map[K][]Hook),
+		stopHooks:  make(map[K][]Hook),
+		dependents: make(map[K][]K),
+		started:    make(map[K]bool),
+	}
 }
 
 func (c *genericContainer[K, T]) Set(key K, item T) error {
@@ -47,6 +75,7 @@ func (c *genericContainer[K, T]) Set(key K, item T) error {
 		return fmt.Errorf("%v already exists", key)
 	}
 	c.container[key] = item
+	c.order = append(c.order, key)
 	return nil
 }
 
@@ -68,6 +97,15 @@ func (c *genericContainer[K, T]) Replace(key K, item T) error {
 	return nil
 }
 
+func (c *genericContainer[K, T]) SetOrReplace(key K, item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.container[key]; !found {
+		c.order = append(c.order, key)
+	}
+	c.container[key] = item
+}
+
 func (c *genericContainer[K, T]) Get(key K) (T, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -94,5 +132,17 @@ func (c *genericContainer[K, T]) Remove(key K) error {
 	}
 
 	delete(c.container, key)
+	c.order = slices.DeleteFunc(c.order, func(k K) bool { return k == key })
+	delete(c.startHooks, key)
+	delete(c.stopHooks, key)
+	delete(c.dependents, key)
+	delete(c.started, key)
 	return nil
 }
+
+// NamespacedKey returns the key of a component in the namespace of its kind,
+// e.g. "connection:kafka", so components of different kinds sharing a name do
+// not collide in the same container.
+func NamespacedKey(namespace string, name string) string {
+	return namespace + ":" + name
+}
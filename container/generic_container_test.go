@@ -75,6 +75,39 @@ func TestGenericContainer_Replace(t *testing.T) {
 	})
 }
 
+func TestGenericContainer_SetOrReplace(t *testing.T) {
+	t.Parallel()
+	c := container.NewGenericContainer[string, int]()
+	key := "foo"
+
+	t.Run("should set item if key does not exist", func(t *testing.T) {
+		c.SetOrReplace(key, 42)
+		if val, _ := c.Get(key); val != 42 {
+			t.Errorf("expected value to be set")
+		}
+	})
+
+	t.Run("should replace item if key exists", func(t *testing.T) {
+		c.SetOrReplace(key, 99)
+		if val, _ := c.Get(key); val != 99 {
+			t.Errorf("expected value to be replaced")
+		}
+	})
+}
+
+func TestNamespacedKey(t *testing.T) {
+	t.Parallel()
+	c := container.NewGenericContainer[string, string]()
+	_ = c.Set("createUser", "channel")
+	err := c.Set(container.NamespacedKey("consumer", "createUser"), "consumer")
+	if err != nil {
+		t.Errorf("expected no collision across namespaces, got %v", err)
+	}
+	if key := container.NamespacedKey("consumer", "createUser"); key != "consumer:createUser" {
+		t.Errorf("expected namespaced key consumer:createUser, got %s", key)
+	}
+}
+
 func TestGenericContainer_Get(t *testing.T) {
 	t.Parallel()
 	c := container.NewGenericContainer[string, int]()
@@ -0,0 +1,155 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type (
+	// Hook is a lifecycle hook of a container item, e.g. warming up a
+	// connection pool on start or closing it on stop.
+	Hook func(ctx context.Context) error
+	// Starter is implemented by items started with the container, in
+	// addition to the hooks added with OnStart.
+	Starter interface {
+		OnStart(ctx context.Context) error
+	}
+	// Stopper is implemented by items stopped with the container, in
+	// addition to the hooks added with OnStop.
+	Stopper interface {
+		OnStop(ctx context.Context) error
+	}
+)
+
+func (c *genericContainer[K, T]) OnStart(key K, hook Hook) error {
+	return c.addHook(c.startHooks, key, hook)
+}
+
+func (c *genericContainer[K, T]) OnStop(key K, hook Hook) error {
+	return c.addHook(c.stopHooks, key, hook)
+}
+
+func (c *genericContainer[K, T]) addHook(hooks map[K][]Hook, key K, hook Hook) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.container[key]; !found {
+		return fmt.Errorf("cannot find item %v", key)
+	}
+	if hook == nil {
+		return fmt.Errorf("hook of item %v cannot be nil", key)
+	}
+	hooks[key] = append(hooks[key], hook)
+	return nil
+}
+
+func (c *genericContainer[K, T]) DependsOn(key K, dependencies ...K) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range append([]K{key}, dependencies...) {
+		if _, found := c.container[k]; !found {
+			return fmt.Errorf("cannot find item %v", k)
+		}
+	}
+	for _, dependency := range dependencies {
+		if !slices.Contains(c.dependents[dependency], key) {
+			c.dependents[dependency] = append(c.dependents[dependency], key)
+		}
+	}
+	return nil
+}
+
+// Start runs the start hooks of the items not started yet, in registration
+// order, so items registered later can rely on the items registered before
+// them. It stops at the first failing hook.
+func (c *genericContainer[K, T]) Start(ctx context.Context) error {
+	c.mu.RLock()
+	keys := slices.DeleteFunc(slices.Clone(c.order), func(key K) bool {
+		return c.started[key]
+	})
+	c.mu.RUnlock()
+
+	for _, key := range keys {
+		item, hooks, found := c.lifecycle(key, c.startHooks)
+		if !found {
+			continue
+		}
+		if starter, ok := any(item).(Starter); ok {
+			hooks = append([]Hook{starter.OnStart}, hooks...)
+		}
+		for _, hook := range hooks {
+			if err := hook(ctx); err != nil {
+				return fmt.Errorf("failed to start %v: %w", key, err)
+			}
+		}
+		c.mu.Lock()
+		c.started[key] = true
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop runs the stop hooks of every item in reverse registration order, an
+// item being stopped only after the items depending on it. Failing hooks do
+// not interrupt the disposal, their errors are joined.
+func (c *genericContainer[K, T]) Stop(ctx context.Context) error {
+	c.mu.RLock()
+	keys := c.stopOrder()
+	c.mu.RUnlock()
+
+	var errs []error
+	for _, key := range keys {
+		item, hooks, found := c.lifecycle(key, c.stopHooks)
+		if !found {
+			continue
+		}
+		if stopper, ok := any(item).(Stopper); ok {
+			hooks = append(hooks, stopper.OnStop)
+		}
+		for _, hook := range hooks {
+			if err := hook(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("failed to stop %v: %w", key, err))
+			}
+		}
+		c.mu.Lock()
+		delete(c.started, key)
+		c.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// lifecycle returns an item with a copy of its hooks.
+func (c *genericContainer[K, T]) lifecycle(
+	key K,
+	hooks map[K][]Hook,
+) (T, []Hook, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, found := c.container[key]
+	return item, slices.Clone(hooks[key]), found
+}
+
+// stopOrder returns the keys in reverse registration order, each key preceded
+// by the keys of its dependents. The caller holds the lock.
+func (c *genericContainer[K, T]) stopOrder() []K {
+	visited := make(map[K]bool, len(c.order))
+	order := make([]K, 0, len(c.order))
+	var visit func(key K)
+	visit = func(key K) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, dependent := range c.dependents[key] {
+			if _, found := c.container[dependent]; found {
+				visit(dependent)
+			}
+		}
+		order = append(order, key)
+	}
+	for _, key := range slices.Backward(c.order) {
+		visit(key)
+	}
+	return order
+}
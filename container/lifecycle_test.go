@@ -0,0 +1,139 @@
+package container_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+)
+
+type lifecycleItem struct {
+	name   string
+	events *[]string
+}
+
+func (i *lifecycleItem) OnStart(ctx context.Context) error {
+	*i.events = append(*i.events, "start:"+i.name)
+	return nil
+}
+
+func (i *lifecycleItem) OnStop(ctx context.Context) error {
+	*i.events = append(*i.events, "stop:"+i.name)
+	return nil
+}
+
+func TestGenericContainer_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should start items in registration order once", func(t *testing.T) {
+		var events []string
+		c := container.NewGenericContainer[string, any]()
+		_ = c.Set("connection", &lifecycleItem{"connection", &events})
+		_ = c.Set("cache", "not a starter")
+		_ = c.OnStart("cache", func(ctx context.Context) error {
+			events = append(events, "start:cache")
+			return nil
+		})
+
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := []string{"start:connection", "start:cache"}
+		if !slices.Equal(events, want) {
+			t.Errorf("expected %v, got %v", want, events)
+		}
+	})
+
+	t.Run("should fail when a start hook fails", func(t *testing.T) {
+		c := container.NewGenericContainer[string, any]()
+		_ = c.Set("pool", "pool")
+		_ = c.OnStart("pool", func(ctx context.Context) error {
+			return errors.New("warm-up failed")
+		})
+		if err := c.Start(context.Background()); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("should fail to add hooks of missing items", func(t *testing.T) {
+		c := container.NewGenericContainer[string, any]()
+		hook := func(ctx context.Context) error { return nil }
+		if err := c.OnStart("missing", hook); err == nil {
+			t.Error("expected error, got nil")
+		}
+		if err := c.OnStop("missing", hook); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestGenericContainer_Stop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should stop items in reverse registration order", func(t *testing.T) {
+		var events []string
+		c := container.NewGenericContainer[string, any]()
+		for _, name := range []string{"connection", "producer", "consumer"} {
+			_ = c.Set(name, &lifecycleItem{name, &events})
+		}
+
+		if err := c.Stop(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := []string{"stop:consumer", "stop:producer", "stop:connection"}
+		if !slices.Equal(events, want) {
+			t.Errorf("expected %v, got %v", want, events)
+		}
+	})
+
+	t.Run("should stop dependents before their dependencies", func(t *testing.T) {
+		var events []string
+		c := container.NewGenericContainer[string, any]()
+		for _, name := range []string{"repository", "pool"} {
+			_ = c.Set(name, &lifecycleItem{name, &events})
+		}
+		if err := c.DependsOn("repository", "pool"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		_ = c.Stop(context.Background())
+		want := []string{"stop:repository", "stop:pool"}
+		if !slices.Equal(events, want) {
+			t.Errorf("expected %v, got %v", want, events)
+		}
+	})
+
+	t.Run("should stop every item when a stop hook fails", func(t *testing.T) {
+		var events []string
+		c := container.NewGenericContainer[string, any]()
+		_ = c.Set("connection", &lifecycleItem{"connection", &events})
+		_ = c.Set("producer", "producer")
+		_ = c.OnStop("producer", func(ctx context.Context) error {
+			return errors.New("close failed")
+		})
+
+		if err := c.Stop(context.Background()); err == nil {
+			t.Error("expected error, got nil")
+		}
+		if !slices.Equal(events, []string{"stop:connection"}) {
+			t.Errorf("expected the connection stopped, got %v", events)
+		}
+	})
+
+	t.Run("should not stop removed items", func(t *testing.T) {
+		var events []string
+		c := container.NewGenericContainer[string, any]()
+		_ = c.Set("consumer", &lifecycleItem{"consumer", &events})
+		_ = c.Remove("consumer")
+
+		_ = c.Stop(context.Background())
+		if len(events) > 0 {
+			t.Errorf("expected no item stopped, got %v", events)
+		}
+	})
+}
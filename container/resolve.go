@@ -0,0 +1,65 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Errors wrapped by the resolution helpers, so callers can handle them with
+// errors.Is.
+var (
+	// ErrItemNotFound is wrapped when no item is registered for a key.
+	ErrItemNotFound = errors.New("item not found")
+	// ErrItemType is wrapped when the item of a key is not of the resolved
+	// type.
+	ErrItemType = errors.New("item of unexpected type")
+)
+
+// Resolve returns the item of a key as a T, e.g. the connection of a channel
+// adapter, describing in the error whether the item is missing or of another
+// type.
+//
+// Parameters:
+//   - c: the container holding the item
+//   - key: the key of the item
+//
+// Returns:
+//   - T: the item
+//   - error: error wrapping ErrItemNotFound or ErrItemType
+func Resolve[T any, K comparable, V any](c Container[K, V], key K) (T, error) {
+	var resolved T
+	item, err := c.Get(key)
+	if err != nil {
+		return resolved, fmt.Errorf("%w: %w", ErrItemNotFound, err)
+	}
+	resolved, ok := any(item).(T)
+	if !ok {
+		return resolved, fmt.Errorf(
+			"%w: %v is %T, not %v",
+			ErrItemType,
+			key,
+			item,
+			reflect.TypeFor[T](),
+		)
+	}
+	return resolved, nil
+}
+
+// MustResolve returns the item of a key as a T, panicking when the item is
+// missing or of another type. It suits wiring code, where a missing item is
+// a programming error.
+//
+// Parameters:
+//   - c: the container holding the item
+//   - key: the key of the item
+//
+// Returns:
+//   - T: the item
+func MustResolve[T any, K comparable, V any](c Container[K, V], key K) T {
+	resolved, err := Resolve[T](c, key)
+	if err != nil {
+		panic(err)
+	}
+	return resolved
+}
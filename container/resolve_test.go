@@ -0,0 +1,68 @@
+package container_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+)
+
+type resolveConnection struct{ name string }
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	c := container.NewGenericContainer[any, any]()
+	_ = c.Set("connection:kafka", &resolveConnection{"kafka"})
+	_ = c.Set("dependency:cache", "cache")
+
+	t.Run("should resolve an item of the given type", func(t *testing.T) {
+		conn, err := container.Resolve[*resolveConnection, any, any](c, "connection:kafka")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if conn.name != "kafka" {
+			t.Errorf("expected kafka, got %s", conn.name)
+		}
+	})
+
+	t.Run("should fail when the item is not found", func(t *testing.T) {
+		_, err := container.Resolve[*resolveConnection, any, any](c, "connection:missing")
+		if !errors.Is(err, container.ErrItemNotFound) {
+			t.Errorf("expected ErrItemNotFound, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "cannot find item connection:missing") {
+			t.Errorf("expected the error of the container wrapped, got %v", err)
+		}
+	})
+
+	t.Run("should fail when the item is of another type", func(t *testing.T) {
+		_, err := container.Resolve[*resolveConnection, any, any](c, "dependency:cache")
+		if !errors.Is(err, container.ErrItemType) {
+			t.Errorf("expected ErrItemType, got %v", err)
+		}
+	})
+}
+
+func TestMustResolve(t *testing.T) {
+	t.Parallel()
+
+	c := container.NewGenericContainer[string, any]()
+	_ = c.Set("cache", "cache")
+
+	t.Run("should resolve an item of the given type", func(t *testing.T) {
+		if got := container.MustResolve[string](c, "cache"); got != "cache" {
+			t.Errorf("expected cache, got %s", got)
+		}
+	})
+
+	t.Run("should panic when the item is of another type", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic, got none")
+			}
+		}()
+		container.MustResolve[int](c, "cache")
+	})
+}
@@ -0,0 +1,67 @@
+package gomes
+
+import (
+	"context"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// HeadersFromContext returns a copy of the headers of the message handled
+// with the context, e.g. inside an action handler or event subscriber. It is
+// safe with multiple processors, unlike the handler header accessors.
+//
+// Parameters:
+//   - ctx: the handler context
+//
+// Returns:
+//   - message.Header: the message headers, empty outside a handler
+func HeadersFromContext(ctx context.Context) message.Header {
+	header, ok := message.HeaderFromContext(ctx)
+	if !ok {
+		return message.Header{}
+	}
+	return message.Header(header.All())
+}
+
+// MessageIdFromContext returns the id of the message handled with the
+// context.
+//
+// Parameters:
+//   - ctx: the handler context
+//
+// Returns:
+//   - string: the message id, empty outside a handler
+func MessageIdFromContext(ctx context.Context) string {
+	header, _ := message.HeaderFromContext(ctx)
+	return header.Get(message.HeaderMessageId)
+}
+
+// CorrelationIdFromContext returns the correlation id of the message handled
+// with the context.
+//
+// Parameters:
+//   - ctx: the handler context
+//
+// Returns:
+//   - string: the correlation id, empty outside a handler
+func CorrelationIdFromContext(ctx context.Context) string {
+	header, _ := message.HeaderFromContext(ctx)
+	return header.Get(message.HeaderCorrelationId)
+}
+
+// RawMessageFromContext returns the raw message received from the external
+// source of the message handled with the context, e.g. the kafka.Message or
+// amqp.Delivery.
+//
+// Parameters:
+//   - ctx: the handler context
+//
+// Returns:
+//   - any: the raw message, nil outside a handler or for internal messages
+func RawMessageFromContext(ctx context.Context) any {
+	msg, ok := message.MessageFromContext(ctx)
+	if !ok || msg == nil {
+		return nil
+	}
+	return msg.GetRawMessage()
+}
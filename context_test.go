@@ -0,0 +1,53 @@
+package gomes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestHeadersFromContext(t *testing.T) {
+	t.Run("should return the metadata of the handled message", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().
+			WithMessageId("msg-1").
+			WithCorrelationId("corr-1").
+			WithRawMessage("raw-message").
+			Build()
+		ctx := message.ContextWithMessage(context.Background(), msg)
+
+		headers := gomes.HeadersFromContext(ctx)
+		if headers.Get(message.HeaderMessageId) != "msg-1" {
+			t.Errorf("expected message id msg-1, got %s", headers.Get(message.HeaderMessageId))
+		}
+		headers[message.HeaderMessageId] = "changed"
+		if msg.GetHeader().Get(message.HeaderMessageId) != "msg-1" {
+			t.Error("expected a copy of the message headers")
+		}
+		if id := gomes.MessageIdFromContext(ctx); id != "msg-1" {
+			t.Errorf("expected message id msg-1, got %s", id)
+		}
+		if id := gomes.CorrelationIdFromContext(ctx); id != "corr-1" {
+			t.Errorf("expected correlation id corr-1, got %s", id)
+		}
+		if raw := gomes.RawMessageFromContext(ctx); raw != "raw-message" {
+			t.Errorf("expected raw message, got %v", raw)
+		}
+	})
+
+	t.Run("should return empty metadata outside a handler", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		if headers := gomes.HeadersFromContext(ctx); headers == nil || len(headers) != 0 {
+			t.Errorf("expected empty headers, got %v", headers)
+		}
+		if gomes.MessageIdFromContext(ctx) != "" || gomes.CorrelationIdFromContext(ctx) != "" {
+			t.Error("expected empty ids")
+		}
+		if gomes.RawMessageFromContext(ctx) != nil {
+			t.Error("expected no raw message")
+		}
+	})
+}
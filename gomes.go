@@ -8,16 +8,30 @@
 //
 // The gomes implementation supports:
 // - Command and Query bus management
+// - Scatter-gather queries over multiple channels
 // - Event-driven consumer processing
+// - Running every registered consumer with one call
 // - Channel connection management
 // - Action handler registration
+// - Global consumer interceptors
+// - In-process event subscribers
 // - Default endpoint configuration
 // - System lifecycle management
+// - OpenTelemetry tracing and metrics
+// - Quiescing for safe deploys
+// - Runtime pause/resume of consumers
 package gomes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/bus"
 	"github.com/jeffersonbrasilino/gomes/container"
@@ -25,6 +39,7 @@ import (
 	"github.com/jeffersonbrasilino/gomes/message/adapter"
 	"github.com/jeffersonbrasilino/gomes/message/endpoint"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
+	"github.com/jeffersonbrasilino/gomes/message/router"
 	"github.com/jeffersonbrasilino/gomes/otel"
 )
 
@@ -32,6 +47,21 @@ import (
 const (
 	defaultCommandChannelName = "default.channel.command"
 	defaultQueryChannelName   = "default.channel.query"
+	defaultEventChannelName   = "default.channel.event"
+)
+
+// Sentinel errors wrapped by the errors of the message system, re-exported from
+// the message package so callers can handle them with errors.Is.
+var (
+	ErrChannelNotFound       = message.ErrChannelNotFound
+	ErrDuplicateRegistration = message.ErrDuplicateRegistration
+	ErrNotStarted            = message.ErrNotStarted
+	ErrHandlerNotFound       = message.ErrHandlerNotFound
+	ErrTranslation           = message.ErrTranslation
+	ErrTimeout               = message.ErrTimeout
+	ErrInvalidTenant         = message.ErrInvalidTenant
+	ErrPanic                 = message.ErrPanic
+	ErrGuardViolation        = message.ErrGuardViolation
 )
 
 // Global containers for managing message system components.
@@ -54,8 +84,74 @@ var (
 		string,
 		BuildableComponent[message.PublisherChannel],
 	]()
+	eventSubscribers = container.NewGenericContainer[
+		string,
+		*handler.EventSubscribersActivatorBuilder,
+	]()
+	globalBeforeInterceptors []message.MessageHandler
+	globalAfterInterceptors  []message.MessageHandler
+	globalInterceptorsMu     sync.RWMutex
+	quiesced                 atomic.Bool
+	tenantRouting            message.TenantChannelStrategy
 )
 
+// AddGlobalBeforeInterceptor registers interceptors executed before message
+// processing on every consumer, ahead of the interceptors configured on each
+// consumer channel. It must be called before the consumers are created.
+//
+// Parameters:
+//   - interceptors: the message handlers to register
+//
+// Returns:
+//   - error: error if any interceptor is nil
+func AddGlobalBeforeInterceptor(interceptors ...message.MessageHandler) error {
+	if slices.Contains(interceptors, nil) {
+		return fmt.Errorf("interceptor cannot be nil")
+	}
+
+	globalInterceptorsMu.Lock()
+	defer globalInterceptorsMu.Unlock()
+	globalBeforeInterceptors = append(globalBeforeInterceptors, interceptors...)
+	return nil
+}
+
+// AddGlobalAfterInterceptor registers interceptors executed after message
+// processing on every consumer, behind the interceptors configured on each
+// consumer channel. It must be called before the consumers are created.
+//
+// Parameters:
+//   - interceptors: the message handlers to register
+//
+// Returns:
+//   - error: error if any interceptor is nil
+func AddGlobalAfterInterceptor(interceptors ...message.MessageHandler) error {
+	if slices.Contains(interceptors, nil) {
+		return fmt.Errorf("interceptor cannot be nil")
+	}
+
+	globalInterceptorsMu.Lock()
+	defer globalInterceptorsMu.Unlock()
+	globalAfterInterceptors = append(globalAfterInterceptors, interceptors...)
+	return nil
+}
+
+// globalInterceptors returns a copy of the registered global before or after
+// interceptors.
+func globalInterceptors(before bool) []message.MessageHandler {
+	globalInterceptorsMu.RLock()
+	defer globalInterceptorsMu.RUnlock()
+	if before {
+		return slices.Clone(globalBeforeInterceptors)
+	}
+	return slices.Clone(globalAfterInterceptors)
+}
+
+// flusher is implemented by components which buffer outgoing messages, such as
+// publishers or outboxes, and can flush them on demand.
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
 // BuildableComponent defines the contract for components that can be built
 // from a dependency container.
 type BuildableComponent[T any] interface {
@@ -71,14 +167,18 @@ type BuildableComponent[T any] interface {
 //   - publisher: the publisher channel builder to register
 //
 // Returns:
-//   - error: error if a channel with the same reference name already exists
+//   - error: error if a channel or handler with the same reference name
+//     already exists
 func AddPublisherChannel(
 	publisher BuildableComponent[endpoint.OutboundChannelAdapter],
 ) error {
-	if outboundChannelBuilders.Has(publisher.ReferenceName()) {
+	if outboundChannelBuilders.Has(publisher.ReferenceName()) ||
+		hasActionHandler(publisher.ReferenceName()) ||
+		eventSubscribers.Has(publisher.ReferenceName()) {
 		return fmt.Errorf(
-			"[publisher-channel] channel %s already exists",
+			"[publisher-channel] channel %s %w",
 			publisher.ReferenceName(),
+			message.ErrDuplicateRegistration,
 		)
 	}
 	outboundChannelBuilders.Set(publisher.ReferenceName(), publisher)
@@ -105,14 +205,140 @@ func buildOutboundChannels(
 				err,
 			)
 		}
-		container.Set(v.ReferenceName(), outboundChannel)
+		if err := setComponent(container, v.ReferenceName(), outboundChannel); err != nil {
+			return fmt.Errorf(
+				"[publisher-channel] channel %s %w",
+				v.ReferenceName(),
+				message.ErrDuplicateRegistration,
+			)
+		}
+	}
+	if err := attachWireTaps(container); err != nil {
+		return err
+	}
+	return attachPublishFallbacks(container)
+}
+
+// wireTapAttacher is implemented by publisher channels configured with a
+// wire tap.
+type wireTapAttacher interface {
+	WireTapChannelName() string
+	AttachWireTap(channel message.PublisherChannel)
+}
+
+// attachWireTaps resolves the wire tap channels of the built publisher
+// channels.
+//
+// Parameters:
+//   - container: the dependency container with the built publisher channels
+//
+// Returns:
+//   - error: error if a wire tap channel is not a registered publisher channel
+func attachWireTaps(container container.Container[any, any]) error {
+	for name := range outboundChannelBuilders.GetAll() {
+		anyChannel, err := container.Get(name)
+		if err != nil {
+			continue
+		}
+		tapped, ok := anyChannel.(wireTapAttacher)
+		if !ok || tapped.WireTapChannelName() == "" {
+			continue
+		}
+		publisher, err := resolvePublisherChannel(
+			container,
+			"wire tap",
+			name,
+			tapped.WireTapChannelName(),
+		)
+		if err != nil {
+			return err
+		}
+		tapped.AttachWireTap(publisher)
+	}
+	return nil
+}
+
+// publishFallbackAttacher is implemented by publisher channels sending the
+// messages they fail to publish to a fallback channel.
+type publishFallbackAttacher interface {
+	PublishFallbackChannelName() string
+	AttachPublishFallback(channel message.PublisherChannel)
+}
+
+// attachPublishFallbacks resolves the publish fallback channels of the built
+// publisher channels.
+//
+// Parameters:
+//   - container: the dependency container with the built publisher channels
+//
+// Returns:
+//   - error: error if a fallback channel is not a registered publisher channel
+func attachPublishFallbacks(container container.Container[any, any]) error {
+	for name := range outboundChannelBuilders.GetAll() {
+		anyChannel, err := container.Get(name)
+		if err != nil {
+			continue
+		}
+		channel, ok := anyChannel.(publishFallbackAttacher)
+		if !ok || channel.PublishFallbackChannelName() == "" {
+			continue
+		}
+		publisher, err := resolvePublisherChannel(
+			container,
+			"publish fallback",
+			name,
+			channel.PublishFallbackChannelName(),
+		)
+		if err != nil {
+			return err
+		}
+		channel.AttachPublishFallback(publisher)
 	}
 	return nil
 }
 
-// registerDefaultEndpoints registers the default command and query endpoints
-// with the message system. These endpoints are used when no specific channel
-// is specified for command or query operations.
+// resolvePublisherChannel returns a built publisher channel referenced by
+// another publisher channel.
+//
+// Parameters:
+//   - container: the dependency container with the built publisher channels
+//   - kind: the kind of reference, e.g. wire tap
+//   - name: the referencing channel name
+//   - channelName: the referenced channel name
+//
+// Returns:
+//   - message.PublisherChannel: the referenced publisher channel
+//   - error: error if the channel is not a registered publisher channel
+func resolvePublisherChannel(
+	container container.Container[any, any],
+	kind string,
+	name string,
+	channelName string,
+) (message.PublisherChannel, error) {
+	anyChannel, err := container.Get(channelName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[publisher-channel] %s of %s: %w: %s",
+			kind,
+			name,
+			message.ErrChannelNotFound,
+			channelName,
+		)
+	}
+	publisher, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[publisher-channel] %s channel %s is not a publisher channel",
+			kind,
+			channelName,
+		)
+	}
+	return publisher, nil
+}
+
+// registerDefaultEndpoints registers the default command, query and event
+// endpoints with the message system. These endpoints are used when no specific
+// channel is specified for command, query or event operations.
 //
 // Parameters:
 //   - container: the dependency container to register endpoints with
@@ -166,6 +392,28 @@ func registerDefaultEndpoints(
 		)
 	}
 
+	eventDispatcher, err := endpoint.NewMessageDispatcherBuilder(
+		defaultEventChannelName,
+		"",
+	).Build(container)
+	if err != nil {
+		return fmt.Errorf(
+			"[message-dispatcher] failed to build event dispatcher: %w",
+			err,
+		)
+	}
+
+	err = activeEndpoints.Set(
+		defaultEventChannelName,
+		bus.NewEventBus(eventDispatcher),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"[message-dispatcher] failed to register event bus: %w",
+			err,
+		)
+	}
+
 	return nil
 }
 
@@ -181,8 +429,9 @@ func registerDefaultEndpoints(
 func AddChannelConnection(con adapter.ChannelConnection) error {
 	if channelConnections.Has(con.ReferenceName()) {
 		return fmt.Errorf(
-			"[channel-module] connection %s already exists",
+			"[channel-module] connection %s %w",
 			con.ReferenceName(),
+			message.ErrDuplicateRegistration,
 		)
 	}
 	channelConnections.Set(con.ReferenceName(), con)
@@ -210,7 +459,7 @@ func buildChannelConnections(
 				err,
 			)
 		}
-		container.Set(v.ReferenceName(), v)
+		setComponent(container, adapter.ConnectionKey(v.ReferenceName()), v)
 	}
 	return nil
 }
@@ -229,8 +478,9 @@ func AddConsumerChannel(
 ) error {
 	if inboundChannelBuilders.Has(inboundChannel.ReferenceName()) {
 		return fmt.Errorf(
-			"[consumer-channel] consumer for channel %s already exists",
+			"[consumer-channel] consumer for channel %s %w",
 			inboundChannel.ReferenceName(),
+			message.ErrDuplicateRegistration,
 		)
 	}
 	inboundChannelBuilders.Set(inboundChannel.ReferenceName(), inboundChannel)
@@ -254,209 +504,664 @@ func buildInboundChannels(
 		if err != nil {
 			return fmt.Errorf("[consumer-channel] %s", err)
 		}
-		container.Set(inboundChannel.ReferenceName(), inboundChannel)
+		err = setComponent(
+			container,
+			endpoint.ConsumerChannelKey(inboundChannel.ReferenceName()),
+			inboundChannel,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"[consumer-channel] consumer %s %w",
+				inboundChannel.ReferenceName(),
+				message.ErrDuplicateRegistration,
+			)
+		}
 	}
 	return nil
 }
 
+// ActionHandlerOption configures the registration of an action handler.
+type ActionHandlerOption func(options *actionHandlerOptions)
+
+// actionHandlerOptions holds the registration settings of an action handler.
+type actionHandlerOptions struct {
+	route   string
+	channel string
+	version string
+}
+
+// WithRoute sets the route handled by the action handler, instead of the
+// route derived from the action name, e.g. to register handlers of the same
+// action type under different routes.
+//
+// Parameters:
+//   - route: the route handled
+//
+// Returns:
+//   - ActionHandlerOption: the registration option
+func WithRoute(route string) ActionHandlerOption {
+	return func(options *actionHandlerOptions) {
+		options.route = route
+	}
+}
+
+// WithChannel sets the name of the channel the action handler listens on,
+// so messages addressed to the channel by the channelName header reach the
+// handler besides the messages of its route.
+//
+// Parameters:
+//   - channelName: the channel name of the handler
+//
+// Returns:
+//   - ActionHandlerOption: the registration option
+func WithChannel(channelName string) ActionHandlerOption {
+	return func(options *actionHandlerOptions) {
+		options.channel = channelName
+	}
+}
+
+// WithVersion restricts the action handler to the messages of a version of
+// its route, e.g. during a schema migration. Messages of versions without a
+// handler of their own are routed to the handler of the route.
+//
+// Parameters:
+//   - version: the message version handled
+//
+// Returns:
+//   - ActionHandlerOption: the registration option
+func WithVersion(version string) ActionHandlerOption {
+	return func(options *actionHandlerOptions) {
+		options.version = version
+	}
+}
+
 // AddActionHandler registers an action handler with the message system.
 // Action handlers process commands, queries, or events based on the action
-// type. Each action type can have only one handler registered.
+// type. Each action type can have only one handler registered. The action type
+// may be either a value or a pointer type.
 //
 // Parameters:
 //   - handlerAction: the action handler to register (must not be nil)
 //
 // Returns:
-//   - error: error if handler is nil or a handler for the same action already
-//     exists
+//   - error: error if handler is nil, the action name cannot be resolved or a
+//     handler for the same action already exists
 func AddActionHandler[T handler.Action, U any](
 	handlerAction handler.ActionHandler[T, U],
 ) error {
-	if handlerAction == nil {
-		return fmt.Errorf("handler cannot be nil")
-	}
-
-	action := *new(T)
-	if outboundChannelBuilders.Has(action.Name()) {
-		return fmt.Errorf(
-			"handler for %s already exists",
-			action.Name(),
-		)
-	}
-
-	actionHandlers.Set(
-		action.Name(),
-		handler.NewActionHandleActivatorBuilder(
-			action.Name(),
-			handlerAction,
-		),
-	)
-	return nil
+	return AddActionHandlerWithOptions(handlerAction)
 }
 
-// buildActionHandlers builds all registered action handlers and adds them to
-// the message system container. This function processes all registered handlers
-// and is called during system initialization.
+// AddActionHandlerWithOptions registers an action handler with the message
+// system, with an explicit route, channel or version. Without options it
+// behaves like AddActionHandler.
 //
 // Parameters:
-//   - container: the dependency container to add built handlers to
+//   - handlerAction: the action handler to register (must not be nil)
+//   - opts: the registration options, e.g. WithRoute
 //
 // Returns:
-//   - error: error if building any handler fails
-func buildActionHandlers(
-	container container.Container[any, any],
+//   - error: error if handler is nil, the route cannot be resolved or a
+//     handler for the same route, version or channel already exists
+func AddActionHandlerWithOptions[T handler.Action, U any](
+	handlerAction handler.ActionHandler[T, U],
+	opts ...ActionHandlerOption,
 ) error {
-	for _, v := range actionHandlers.GetAll() {
-		actionHandler, err := v.Build(container)
-		if err != nil {
-			return fmt.Errorf(
-				"[action-handler] failed to build handler: %w",
-				err,
-			)
-		}
-		err = container.Set(actionHandler.Name(), actionHandler)
-		if err != nil {
-			return fmt.Errorf(
-				"[action-handler] failed to register handler: %w",
-				err,
-			)
+	if handlerAction == nil {
+		return fmt.Errorf("handler cannot be nil")
+	}
+
+	options := newActionHandlerOptions(opts)
+	actionName := options.route
+	if actionName == "" {
+		var err error
+		if actionName, err = handler.ActionName[T](); err != nil {
+			return err
 		}
 	}
-	return nil
+	return registerActionHandler(actionName, options, activatorBuilderOf(handlerAction))
 }
 
-// Start initializes the message system by building all registered components
-// and registering default endpoints. This function must be called after
-// registering all channels, connections, and handlers, and before using any
-// bus or consumer functionality.
+// AddActionHandlerFactory registers an action handler created when the
+// message system starts, by a factory resolving its dependencies from the
+// gomes container, e.g. publisher channels or the dependencies registered
+// with AddDependency. Factories run after the channels are built.
 //
-// The initialization process follows this order:
-// 1. Register default command and query endpoints
-// 2. Build action handlers
-// 3. Build channel connections
-// 4. Build outbound channels
-// 5. Build inbound channels
+// Parameters:
+//   - factory: creates the action handler (must not be nil)
+//   - opts: the registration options, e.g. WithRoute
 //
 // Returns:
-//   - error: error if any component fails to build or initialize
-func Start() error {
-	buildFunctions := []func(container container.Container[any, any]) error{
-		registerDefaultEndpoints,
-		buildActionHandlers,
-		buildChannelConnections,
-		buildOutboundChannels,
-		buildInboundChannels,
+//   - error: error if factory is nil, the route cannot be resolved or a
+//     handler for the same route, version or channel already exists
+func AddActionHandlerFactory[T handler.Action, U any](
+	factory func(c container.Container[any, any]) (handler.ActionHandler[T, U], error),
+	opts ...ActionHandlerOption,
+) error {
+	if factory == nil {
+		return fmt.Errorf("handler factory cannot be nil")
 	}
 
-	for _, buildFunc := range buildFunctions {
-		err := buildFunc(gomesContainer)
-		if err != nil {
+	options := newActionHandlerOptions(opts)
+	actionName := options.route
+	if actionName == "" {
+		var err error
+		if actionName, err = handler.ActionName[T](); err != nil {
 			return err
 		}
 	}
-
-	return nil
+	return registerActionHandler(
+		actionName,
+		options,
+		func(channelName string) BuildableComponent[message.PublisherChannel] {
+			return handler.NewActionHandleActivatorBuilderFromFactory(channelName, factory)
+		},
+	)
 }
 
-// CommandBus returns the default command bus instance. The default command bus
-// uses an internal channel and does not require external messaging infrastructure.
-// This is useful for local command processing without message brokers.
+// AddDependency registers a dependency in the gomes container, so handler
+// factories can resolve it by name when the message system starts.
+//
+// Parameters:
+//   - name: the dependency name
+//   - dependency: the dependency (must not be nil)
 //
 // Returns:
-//   - *bus.CommandBus: the default command bus (never nil, but may panic if
-//     system is not initialized)
-func CommandBus() (*bus.CommandBus, error) {
-	cb, err := CommandBusByChannel(defaultCommandChannelName)
-	if err != nil {
-		// This should not happen if Start() was called correctly
-		return nil, fmt.Errorf(
-			"[gomes] failed to get default command bus: %v",
-			err,
-		)
+//   - error: error if the name is empty, the dependency is nil or the name is
+//     already registered
+func AddDependency(name string, dependency any) error {
+	if name == "" {
+		return fmt.Errorf("dependency name cannot be empty")
 	}
-	return cb, nil
+	if dependency == nil {
+		return fmt.Errorf("dependency cannot be nil")
+	}
+	if gomesContainer.Has(name) {
+		return fmt.Errorf("dependency %s %w", name, message.ErrDuplicateRegistration)
+	}
+	return gomesContainer.Set(name, dependency)
 }
 
-// QueryBus returns the default query bus instance. The default query bus uses
-// an internal channel and does not require external messaging infrastructure.
-// This is useful for local query processing without message brokers.
+// HandleCommand registers a function handling the commands of a route, so
+// commands need neither a Name method nor a handler type. The command
+// payload is converted to T like the actions of AddActionHandler.
+//
+// Parameters:
+//   - route: the command route
+//   - handle: the function handling the commands (must not be nil)
+//   - opts: the registration options, e.g. WithVersion; the route argument
+//     takes precedence over WithRoute
 //
 // Returns:
-//   - *bus.QueryBus: the default query bus (never nil, but may panic if system
-//     is not initialized)
-func QueryBus() (*bus.QueryBus, error) {
-	qb, err := QueryBusByChannel(defaultQueryChannelName)
-	if err != nil {
-		// This should not happen if Start() was called correctly
-		return nil, fmt.Errorf(
-			"[gomes] failed to get default query bus: %v",
-			err,
-		)
+//   - error: error if the route is empty, handle is nil or a handler for the
+//     same route, version or channel already exists
+func HandleCommand[T any, U any](
+	route string,
+	handle func(ctx context.Context, cmd T) (U, error),
+	opts ...ActionHandlerOption,
+) error {
+	if handle == nil {
+		return fmt.Errorf("handler cannot be nil")
 	}
-	return qb, nil
+	if route == "" {
+		return fmt.Errorf("route cannot be empty")
+	}
+	return registerActionHandler(
+		route,
+		newActionHandlerOptions(opts),
+		activatorBuilderOf[T, U](handler.ActionHandlerFunc[T, U](handle)),
+	)
 }
 
-// CommandBusByChannel returns or creates a command bus for the specified
-// channel. If a bus already exists for the channel, it is returned. Otherwise,
-// a new bus is created and registered. The channel must have a corresponding
-// publisher channel registered.
+// HandleQuery registers a function handling the queries of a route, as
+// HandleCommand does for commands.
 //
 // Parameters:
-//   - channelName: the name of the channel for the command bus
+//   - route: the query route
+//   - handle: the function handling the queries (must not be nil)
+//   - opts: the registration options, e.g. WithVersion; the route argument
+//     takes precedence over WithRoute
 //
 // Returns:
-//   - *bus.CommandBus: the command bus for the specified channel
-//   - error: error if channel does not exist or is not a command channel
-func CommandBusByChannel(
-	channelName string,
-) (*bus.CommandBus, error) {
-	dispatcher, err := activeEndpoints.Get(channelName)
-	if err != nil {
-		dispatcher, err := endpoint.NewMessageDispatcherBuilder(
-			channelName,
-			channelName,
-		).Build(gomesContainer)
-		if err != nil {
-			return nil, err
-		}
-
-		commandBus := bus.NewCommandBus(dispatcher)
-		activeEndpoints.Set(channelName, commandBus)
-		return commandBus, nil
-	}
-
-	commandDispatcher, ok := dispatcher.(*bus.CommandBus)
-	if !ok {
-		return nil, fmt.Errorf("channel %s is not command channel", channelName)
-	}
-	return commandDispatcher, nil
+//   - error: error if the route is empty, handle is nil or a handler for the
+//     same route, version or channel already exists
+func HandleQuery[T any, U any](
+	route string,
+	handle func(ctx context.Context, query T) (U, error),
+	opts ...ActionHandlerOption,
+) error {
+	return HandleCommand(route, handle, opts...)
 }
 
-// QueryBusByChannel returns or creates a query bus for the specified channel.
-// If a bus already exists for the channel, it is returned. Otherwise, a new
-// bus is created and registered. The channel must have a corresponding
-// publisher channel registered.
+// HandleEvent registers a function subscribing to the events of a route, as
+// SubscribeEvent does for subscriber types.
 //
 // Parameters:
-//   - channelName: the name of the channel for the query bus
+//   - route: the event route
+//   - handle: the function handling the events (must not be nil)
+//
+// Returns:
+//   - error: error if the route is empty, handle is nil or an action handler
+//     is already registered for the route
+func HandleEvent[T any](
+	route string,
+	handle func(ctx context.Context, event T) error,
+) error {
+	if handle == nil {
+		return fmt.Errorf("subscriber cannot be nil")
+	}
+	if route == "" {
+		return fmt.Errorf("route cannot be empty")
+	}
+	return subscribeEvent(route, handler.EventSubscriberFunc[T](handle))
+}
+
+// newActionHandlerOptions applies the registration options of an action
+// handler.
+func newActionHandlerOptions(opts []ActionHandlerOption) *actionHandlerOptions {
+	options := &actionHandlerOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	return options
+}
+
+// registerActionHandler registers the activator builder of an action route,
+// created for the handler channel, checking the route, version and channel
+// are not taken.
+func registerActionHandler(
+	actionName string,
+	options *actionHandlerOptions,
+	newBuilder func(channelName string) BuildableComponent[message.PublisherChannel],
+) error {
+	if outboundChannelBuilders.Has(actionName) ||
+		eventSubscribers.Has(actionName) {
+		return fmt.Errorf(
+			"handler for %s %w",
+			actionName,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	handlerName := actionName
+	if options.version != "" {
+		handlerName = router.VersionedRoute(actionName, options.version)
+	}
+	channelName := handlerName
+	if options.channel != "" {
+		channelName = options.channel
+	}
+
+	for _, name := range []string{handlerName, channelName} {
+		if hasActionHandler(name) || outboundChannelBuilders.Has(name) ||
+			eventSubscribers.Has(name) {
+			return fmt.Errorf(
+				"handler for %s %w",
+				name,
+				message.ErrDuplicateRegistration,
+			)
+		}
+	}
+
+	actionHandlers.Set(handlerName, newBuilder(channelName))
+	return nil
+}
+
+// hasActionHandler reports whether an action handler is registered for a
+// route, version or channel name.
+func hasActionHandler(name string) bool {
+	return actionHandlers.Has(name) || slices.ContainsFunc(
+		slices.Collect(maps.Values(actionHandlers.GetAll())),
+		func(builder BuildableComponent[message.PublisherChannel]) bool {
+			return builder.ReferenceName() == name
+		},
+	)
+}
+
+// activatorBuilderOf returns the builder constructor of a handler activator.
+func activatorBuilderOf[T any, U any](
+	handlerAction handler.ActionHandler[T, U],
+) func(channelName string) BuildableComponent[message.PublisherChannel] {
+	return func(channelName string) BuildableComponent[message.PublisherChannel] {
+		return handler.NewActionHandleActivatorBuilder(channelName, handlerAction)
+	}
+}
+
+// SubscribeEvent registers an in-process subscriber for events of type T.
+// Every subscriber registered for the same event receives each published
+// event, without requiring any message broker. Events published through the
+// default EventBus, or consumed from an inbound channel with the same route,
+// are delivered to all subscribers.
+//
+// Parameters:
+//   - subscriber: the event subscriber to register (must not be nil)
+//
+// Returns:
+//   - error: error if subscriber is nil, the event name cannot be resolved or
+//     an action handler is already registered for the same event name
+func SubscribeEvent[T handler.Action](
+	subscriber handler.EventSubscriber[T],
+) error {
+	if subscriber == nil {
+		return fmt.Errorf("subscriber cannot be nil")
+	}
+
+	eventName, err := handler.ActionName[T]()
+	if err != nil {
+		return err
+	}
+	return subscribeEvent(eventName, subscriber)
+}
+
+// subscribeEvent adds a subscriber to the subscribers activator of an event.
+func subscribeEvent[T any](
+	eventName string,
+	subscriber handler.EventSubscriber[T],
+) error {
+	if hasActionHandler(eventName) || outboundChannelBuilders.Has(eventName) {
+		return fmt.Errorf(
+			"handler for %s %w",
+			eventName,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	subscribersBuilder, err := eventSubscribers.Get(eventName)
+	if err != nil {
+		subscribersBuilder = handler.NewEventSubscribersActivatorBuilder(
+			eventName,
+		)
+		eventSubscribers.Set(eventName, subscribersBuilder)
+	}
+
+	subscribersBuilder.AddSubscriber(handler.NewEventSubscriberHandler(subscriber))
+	return nil
+}
+
+// buildEventSubscribers builds the subscribers activators of every event with
+// registered subscribers and adds them to the message system container.
+//
+// Parameters:
+//   - container: the dependency container to add built activators to
+//
+// Returns:
+//   - error: error if building or registering any activator fails
+func buildEventSubscribers(
+	container container.Container[any, any],
+) error {
+	for _, v := range eventSubscribers.GetAll() {
+		subscribersChannel, err := v.Build(container)
+		if err != nil {
+			return fmt.Errorf(
+				"[event-subscriber] failed to build subscribers: %w",
+				err,
+			)
+		}
+		err = setComponent(container, subscribersChannel.Name(), subscribersChannel)
+		if err != nil {
+			return fmt.Errorf(
+				"[event-subscriber] failed to register subscribers: %w",
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// buildActionHandlers builds all registered action handlers and adds them to
+// the message system container. This function processes all registered handlers
+// and is called during system initialization.
+//
+// Parameters:
+//   - container: the dependency container to add built handlers to
+//
+// Returns:
+//   - error: error if building any handler fails
+func buildActionHandlers(
+	container container.Container[any, any],
+) error {
+	for name, v := range actionHandlers.GetAll() {
+		actionHandler, err := v.Build(container)
+		if err != nil {
+			return fmt.Errorf(
+				"[action-handler] failed to build handler: %w",
+				err,
+			)
+		}
+		err = setComponent(container, name, actionHandler)
+		if err == nil && actionHandler.Name() != name {
+			// the same handler is also addressed by its channel name
+			err = container.Set(actionHandler.Name(), actionHandler)
+		}
+		if err != nil {
+			return fmt.Errorf(
+				"[action-handler] failed to register handler: %w",
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// setComponent registers a component in the message system container with
+// the stop hook tearing it down on Shutdown, e.g. closing a channel or
+// disconnecting a connection.
+//
+// Parameters:
+//   - c: the dependency container
+//   - key: the key of the component
+//   - component: the component to register
+//
+// Returns:
+//   - error: error if the key is already registered
+func setComponent(c container.Container[any, any], key string, component any) error {
+	if err := c.Set(key, component); err != nil {
+		return err
+	}
+	if hook := disposalHook(key, component); hook != nil {
+		return c.OnStop(key, hook)
+	}
+	return nil
+}
+
+// disposalHook returns the hook tearing down a component of the message
+// system, or nil when the component holds no resources.
+func disposalHook(key string, component any) container.Hook {
+	switch c := component.(type) {
+	case message.ConsumerChannel:
+		return func(ctx context.Context) error {
+			slog.Info("[message-system] close consumer channel", "name", c.Name())
+			return c.Close()
+		}
+	case message.SubscriberChannel:
+		return func(ctx context.Context) error {
+			slog.Info("[message-system] unsubscribe channel", "name", c.Name())
+			return c.Unsubscribe()
+		}
+	case endpoint.OutboundChannelAdapter:
+		return func(ctx context.Context) error {
+			slog.Info("[message-system] close outbound channel", "name", key)
+			return c.Close()
+		}
+	case adapter.ChannelConnection:
+		return func(ctx context.Context) error {
+			slog.Info("[message-system] disconnect channel connection", "name", key)
+			return c.Disconnect()
+		}
+	}
+	return nil
+}
+
+// Start initializes the message system by building all registered components
+// and registering default endpoints. This function must be called after
+// registering all channels, connections, and handlers, and before using any
+// bus or consumer functionality.
+//
+// The initialization process follows this order:
+// 1. Register default command, query and event endpoints
+// 2. Build channel connections
+// 3. Build outbound channels
+// 4. Build action handlers, so handler factories resolve the channels
+// 5. Build event subscribers
+// 6. Build inbound channels
+//
+// Returns:
+//   - error: error if any component fails to build or initialize
+func Start() error {
+	buildFunctions := []func(container container.Container[any, any]) error{
+		registerDefaultEndpoints,
+		buildChannelConnections,
+		buildOutboundChannels,
+		buildActionHandlers,
+		buildEventSubscribers,
+		buildInboundChannels,
+	}
+
+	for _, buildFunc := range buildFunctions {
+		err := buildFunc(gomesContainer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := gomesContainer.Start(context.Background()); err != nil {
+		return fmt.Errorf("[message-system] %w", err)
+	}
+	return nil
+}
+
+// CommandBus returns the default command bus instance. The default command bus
+// uses an internal channel and does not require external messaging infrastructure.
+// This is useful for local command processing without message brokers.
+//
+// Returns:
+//   - *bus.CommandBus: the default command bus (never nil, but may panic if
+//     system is not initialized)
+func CommandBus() (*bus.CommandBus, error) {
+	if !activeEndpoints.Has(defaultCommandChannelName) {
+		return nil, fmt.Errorf("[gomes] default command bus: %w", message.ErrNotStarted)
+	}
+	cb, err := CommandBusByChannel(defaultCommandChannelName)
+	if err != nil {
+		// This should not happen if Start() was called correctly
+		return nil, fmt.Errorf(
+			"[gomes] failed to get default command bus: %w",
+			err,
+		)
+	}
+	return cb, nil
+}
+
+// QueryBus returns the default query bus instance. The default query bus uses
+// an internal channel and does not require external messaging infrastructure.
+// This is useful for local query processing without message brokers.
+//
+// Returns:
+//   - *bus.QueryBus: the default query bus (never nil, but may panic if system
+//     is not initialized)
+func QueryBus() (*bus.QueryBus, error) {
+	if !activeEndpoints.Has(defaultQueryChannelName) {
+		return nil, fmt.Errorf("[gomes] default query bus: %w", message.ErrNotStarted)
+	}
+	qb, err := QueryBusByChannel(defaultQueryChannelName)
+	if err != nil {
+		// This should not happen if Start() was called correctly
+		return nil, fmt.Errorf(
+			"[gomes] failed to get default query bus: %w",
+			err,
+		)
+	}
+	return qb, nil
+}
+
+// EventBus returns the default event bus instance. The default event bus uses
+// an internal channel and delivers events to the in-process subscribers
+// registered through SubscribeEvent.
+//
+// Returns:
+//   - *bus.EventBus: the default event bus
+//   - error: error if the system is not initialized
+func EventBus() (*bus.EventBus, error) {
+	if !activeEndpoints.Has(defaultEventChannelName) {
+		return nil, fmt.Errorf("[gomes] default event bus: %w", message.ErrNotStarted)
+	}
+	eb, err := EventBusByChannel(defaultEventChannelName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[gomes] failed to get default event bus: %w",
+			err,
+		)
+	}
+	return eb, nil
+}
+
+// CommandBusByChannel returns or creates a command bus for the specified
+// channel. If a bus already exists for the channel, it is returned. Otherwise,
+// a new bus is created and registered. The channel must have a corresponding
+// publisher channel registered.
+//
+// Parameters:
+//   - channelName: the name of the channel for the command bus
+//   - opts: the bus options, applied only when the bus is created
+//
+// Returns:
+//   - *bus.CommandBus: the command bus for the specified channel
+//   - error: error if channel does not exist or is not a command channel
+func CommandBusByChannel(
+	channelName string,
+	opts ...bus.Option,
+) (*bus.CommandBus, error) {
+	dispatcher, err := activeEndpoints.Get(channelName)
+	if err != nil {
+		dispatcher, err := endpoint.NewMessageDispatcherBuilder(
+			channelName,
+			channelName,
+		).WithTenantRouting(tenantRouting).Build(gomesContainer)
+		if err != nil {
+			return nil, err
+		}
+
+		commandBus := bus.NewCommandBus(dispatcher, opts...)
+		activeEndpoints.Set(channelName, commandBus)
+		return commandBus, nil
+	}
+
+	commandDispatcher, ok := dispatcher.(*bus.CommandBus)
+	if !ok {
+		return nil, fmt.Errorf("channel %s is not command channel", channelName)
+	}
+	return commandDispatcher, nil
+}
+
+// QueryBusByChannel returns or creates a query bus for the specified channel.
+// If a bus already exists for the channel, it is returned. Otherwise, a new
+// bus is created and registered. The channel must have a corresponding
+// publisher channel registered.
+//
+// Parameters:
+//   - channelName: the name of the channel for the query bus
+//   - opts: the bus options, applied only when the bus is created
 //
 // Returns:
 //   - *bus.QueryBus: the query bus for the specified channel
 //   - error: error if channel does not exist or is not a query channel
 func QueryBusByChannel(
 	channelName string,
+	opts ...bus.Option,
 ) (*bus.QueryBus, error) {
 	dispatcher, err := activeEndpoints.Get(channelName)
 	if err != nil {
 		dispatcher, err := endpoint.NewMessageDispatcherBuilder(
 			channelName,
 			channelName,
-		).Build(gomesContainer)
+		).WithTenantRouting(tenantRouting).Build(gomesContainer)
 		if err != nil {
 			return nil, err
 		}
 
-		queryBus := bus.NewQueryBus(dispatcher)
+		queryBus := bus.NewQueryBus(dispatcher, opts...)
 		activeEndpoints.Set(channelName, queryBus)
 		return queryBus, nil
 	}
@@ -468,6 +1173,42 @@ func QueryBusByChannel(
 	return queryDispatcher, nil
 }
 
+// ScatterGatherQueryBus returns or creates a query bus publishing each query
+// to all the channels of the scatter-gather, and replying with their gathered
+// replies.
+//
+// Parameters:
+//   - scatterGather: the scatter-gather builder
+//   - opts: the bus options, applied only when the bus is created
+//
+// Returns:
+//   - *bus.QueryBus: the scatter-gather query bus
+//   - error: error if the scatter-gather cannot be built or its reference name
+//     is in use by another endpoint
+func ScatterGatherQueryBus(
+	scatterGather BuildableComponent[*endpoint.ScatterGather],
+	opts ...bus.Option,
+) (*bus.QueryBus, error) {
+	referenceName := scatterGather.ReferenceName()
+	activeEndpoint, err := activeEndpoints.Get(referenceName)
+	if err != nil {
+		dispatcher, err := scatterGather.Build(gomesContainer)
+		if err != nil {
+			return nil, err
+		}
+
+		queryBus := bus.NewQueryBus(dispatcher, opts...)
+		activeEndpoints.Set(referenceName, queryBus)
+		return queryBus, nil
+	}
+
+	queryBus, ok := activeEndpoint.(*bus.QueryBus)
+	if !ok {
+		return nil, fmt.Errorf("endpoint %s is not query bus", referenceName)
+	}
+	return queryBus, nil
+}
+
 // EventBusByChannel returns or creates an event bus for the specified channel.
 // If a bus already exists for the channel, it is returned. Otherwise, a new
 // bus is created and registered. The channel must have a corresponding
@@ -475,33 +1216,88 @@ func QueryBusByChannel(
 //
 // Parameters:
 //   - channelName: the name of the channel for the event bus
+//   - opts: the bus options, applied only when the bus is created
 //
 // Returns:
 //   - *bus.EventBus: the event bus for the specified channel
 //   - error: error if channel does not exist or is not an event channel
 func EventBusByChannel(
 	channelName string,
+	opts ...bus.Option,
 ) (*bus.EventBus, error) {
 	dispatcher, err := activeEndpoints.Get(channelName)
 	if err != nil {
 		dispatcher, err := endpoint.NewMessageDispatcherBuilder(
 			channelName,
 			channelName,
-		).Build(gomesContainer)
+		).WithTenantRouting(tenantRouting).Build(gomesContainer)
 		if err != nil {
 			return nil, err
 		}
 
-		eventBus := bus.NewEventBus(dispatcher)
+		eventBus := bus.NewEventBus(dispatcher, opts...)
 		activeEndpoints.Set(channelName, eventBus)
 		return eventBus, nil
 	}
 
-	eventDispatcher, ok := dispatcher.(*bus.EventBus)
+	eventDispatcher, ok := dispatcher.(*bus.EventBus)
+	if !ok {
+		return nil, fmt.Errorf("channel %s is not publish event channel", channelName)
+	}
+	return eventDispatcher, nil
+}
+
+// correlationStoreProvider is implemented by publisher channels keeping the
+// correlations of their requests in a correlation store.
+type correlationStoreProvider interface {
+	CorrelationStore() (handler.CorrelationStore, time.Duration)
+}
+
+// MatchReply matches a reply received outside the lifetime of its request,
+// e.g. by the reply consumer after a restart, to the pending correlation kept
+// by the correlation store of the publisher channel the request was sent
+// through. The request is given by the causation id of the reply, and its
+// matched correlation is removed from the store.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - channelName: the publisher channel the request was sent through
+//   - reply: the reply message
+//
+// Returns:
+//   - handler.PendingCorrelation: the correlation of the request
+//   - bool: false if the channel has no correlation store or the request has
+//     no pending correlation
+//   - error: error if the channel is not registered or the store fails
+func MatchReply(
+	ctx context.Context,
+	channelName string,
+	reply *message.Message,
+) (handler.PendingCorrelation, bool, error) {
+	anyChannel, err := gomesContainer.Get(channelName)
+	if err != nil {
+		return handler.PendingCorrelation{}, false, fmt.Errorf(
+			"[gomes] publisher %w: %s",
+			message.ErrChannelNotFound,
+			channelName,
+		)
+	}
+
+	provider, ok := anyChannel.(correlationStoreProvider)
 	if !ok {
-		return nil, fmt.Errorf("channel %s is not publish event channel", channelName)
+		return handler.PendingCorrelation{}, false, nil
 	}
-	return eventDispatcher, nil
+	store, _ := provider.CorrelationStore()
+	requestId := reply.GetHeader().Get(message.HeaderCausationId)
+	if store == nil || requestId == "" {
+		return handler.PendingCorrelation{}, false, nil
+	}
+
+	correlation, found, err := store.Find(ctx, requestId)
+	if err != nil || !found {
+		return correlation, false, err
+	}
+	return correlation, true, store.Delete(ctx, requestId)
 }
 
 // EventDrivenConsumer creates and returns an event-driven consumer for the
@@ -522,13 +1318,16 @@ func EventDrivenConsumer(
 	consumerActive, err := activeEndpoints.Get(consumerName)
 	if err == nil && consumerActive != nil {
 		return nil, fmt.Errorf(
-			"consumer for %s already exists",
+			"consumer for %s %w",
 			consumerName,
+			message.ErrDuplicateRegistration,
 		)
 	}
 
 	consumer, err := endpoint.
 		NewEventDrivenConsumerBuilder(consumerName).
+		WithBeforeInterceptors(globalInterceptors(true)...).
+		WithAfterInterceptors(globalInterceptors(false)...).
 		Build(gomesContainer)
 
 	if err != nil {
@@ -540,10 +1339,556 @@ func EventDrivenConsumer(
 	return consumer, nil
 }
 
+// RunConsumersOptions configures the consumers started by RunAllConsumers
+// and StartConsumersWithOptions.
+type RunConsumersOptions struct {
+	// Consumers limits the consumers to run. Empty runs every registered
+	// consumer channel which has no active endpoint yet.
+	Consumers []string
+	// AmountOfProcessors sets the concurrent processors of each consumer.
+	AmountOfProcessors int
+	// ProcessingTimeoutMilliseconds sets the message processing timeout of
+	// each consumer.
+	ProcessingTimeoutMilliseconds int
+	// ContinueOnError keeps each consumer running when a message fails,
+	// instead of stopping it.
+	ContinueOnError bool
+	// StopAllOnError stops every consumer when one of them stops with an
+	// error.
+	StopAllOnError bool
+	// OnError is called when a consumer stops with an error.
+	OnError func(consumerName string, err error)
+}
+
+// ConsumersHandle controls the consumers started by StartConsumers.
+type ConsumersHandle struct {
+	consumers map[string]*endpoint.EventDrivenConsumer
+	cancel    context.CancelFunc
+	done      chan struct{}
+	mu        sync.Mutex
+	errs      []error
+}
+
+// StartConsumers creates an event-driven consumer for every registered
+// consumer channel which has no active endpoint yet, with the settings
+// declared by LoadConfig, and runs each one in its own goroutine. It returns
+// right away with a handle to wait for or stop the consumers.
+//
+// Parameters:
+//   - ctx: context controlling the lifetime of every consumer
+//
+// Returns:
+//   - *ConsumersHandle: the handle of the running consumers
+//   - error: error if a consumer cannot be built
+func StartConsumers(ctx context.Context) (*ConsumersHandle, error) {
+	return StartConsumersWithOptions(ctx, RunConsumersOptions{})
+}
+
+// StartConsumersWithOptions is StartConsumers applying the shared options to
+// the consumers. Options left unset keep the settings declared by LoadConfig.
+//
+// Parameters:
+//   - ctx: context controlling the lifetime of every consumer
+//   - opts: the options shared by the consumers
+//
+// Returns:
+//   - *ConsumersHandle: the handle of the running consumers
+//   - error: error if a consumer cannot be built
+func StartConsumersWithOptions(
+	ctx context.Context,
+	opts RunConsumersOptions,
+) (*ConsumersHandle, error) {
+	names := opts.Consumers
+	if len(names) == 0 {
+		for name := range inboundChannelBuilders.GetAll() {
+			if !activeEndpoints.Has(name) {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+	}
+
+	consumers := make(map[string]*endpoint.EventDrivenConsumer, len(names))
+	for _, name := range names {
+		consumer, err := EventDrivenConsumer(name)
+		if err != nil {
+			for built, builtConsumer := range consumers {
+				builtConsumer.Close()
+				activeEndpoints.Remove(built)
+			}
+			return nil, fmt.Errorf("[message-system] consumer %s: %w", name, err)
+		}
+		consumer.
+			WithAmountOfProcessors(opts.AmountOfProcessors).
+			WithMessageProcessingTimeout(opts.ProcessingTimeoutMilliseconds)
+		if opts.ContinueOnError {
+			consumer.WithErrorPolicy(endpoint.ErrorPolicyContinue)
+		}
+		consumers[name] = consumer
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	handle := &ConsumersHandle{
+		consumers: consumers,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for name, consumer := range consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := consumer.Run(runCtx)
+			if err == nil || errors.Is(err, context.Canceled) {
+				return
+			}
+
+			slog.Error("[message-system] consumer stopped with error",
+				"name", name,
+				"error", err,
+			)
+			if opts.OnError != nil {
+				opts.OnError(name, err)
+			}
+			handle.mu.Lock()
+			handle.errs = append(handle.errs, fmt.Errorf("consumer %s: %w", name, err))
+			handle.mu.Unlock()
+			if opts.StopAllOnError {
+				cancel()
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(handle.done)
+	}()
+	return handle, nil
+}
+
+// Consumers returns the names of the started consumers.
+//
+// Returns:
+//   - []string: the consumer names, sorted
+func (h *ConsumersHandle) Consumers() []string {
+	names := make([]string, 0, len(h.consumers))
+	for name := range h.consumers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Done returns a channel closed when every consumer stopped.
+//
+// Returns:
+//   - <-chan struct{}: the channel closed when the consumers stopped
+func (h *ConsumersHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until every consumer stopped, either because the context was
+// cancelled, because of Stop, because of their error policy or, with
+// StopAllOnError, because another consumer failed.
+//
+// Returns:
+//   - error: the joined errors of the consumers which stopped with an error
+func (h *ConsumersHandle) Wait() error {
+	<-h.done
+	return h.Err()
+}
+
+// Err returns the joined errors of the consumers which stopped with an error
+// so far.
+//
+// Returns:
+//   - error: the consumer errors, nil if none failed
+func (h *ConsumersHandle) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := errors.Join(h.errs...); err != nil {
+		return fmt.Errorf("[message-system] consumers failed: %w", err)
+	}
+	return nil
+}
+
+// Stop stops every consumer and waits until they shut down.
+//
+// Returns:
+//   - error: the joined errors of the consumers which stopped with an error
+func (h *ConsumersHandle) Stop() error {
+	h.cancel()
+	return h.Wait()
+}
+
+// RunAllConsumers starts the consumers like StartConsumersWithOptions and
+// blocks until every consumer stopped.
+//
+// Parameters:
+//   - ctx: context controlling the lifetime of every consumer
+//   - opts: the options shared by the consumers
+//
+// Returns:
+//   - error: the joined errors of the consumers which failed to start or
+//     stopped with an error
+func RunAllConsumers(ctx context.Context, opts RunConsumersOptions) error {
+	handle, err := StartConsumersWithOptions(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return handle.Wait()
+}
+
+// BackfillCoordinator creates a backfill coordinator for the consumer channel,
+// used to replay historical messages across multiple workers while preserving
+// per-key order.
+//
+// Parameters:
+//   - consumerName: the consumer channel reference name
+//
+// Returns:
+//   - *endpoint.BackfillCoordinator: the backfill coordinator
+//   - error: error if the consumer already exists or cannot be built
+func BackfillCoordinator(
+	consumerName string,
+) (*endpoint.BackfillCoordinator, error) {
+	consumerActive, err := activeEndpoints.Get(consumerName)
+	if err == nil && consumerActive != nil {
+		return nil, fmt.Errorf(
+			"consumer for %s %w",
+			consumerName,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	coordinator, err := endpoint.
+		NewBackfillCoordinatorBuilder(consumerName).
+		WithBeforeInterceptors(globalInterceptors(true)...).
+		WithAfterInterceptors(globalInterceptors(false)...).
+		Build(gomesContainer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	activeEndpoints.Set(consumerName, coordinator)
+
+	return coordinator, nil
+}
+
+// ProjectionRunner creates a runner which keeps a read model up to date from
+// the events of a seekable consumer channel, e.g. an event store consumer
+// channel. The runner replays the events after the checkpoint of the
+// projection and saves the position of every processed event.
+//
+// Parameters:
+//   - projectionName: the name of the projection, identifying its checkpoint
+//   - consumerName: the consumer channel reference name
+//   - checkpointStore: the store of the projection checkpoint
+//
+// Returns:
+//   - *endpoint.ProjectionRunner: the projection runner
+//   - error: error if the consumer already exists or cannot be built
+func ProjectionRunner(
+	projectionName string,
+	consumerName string,
+	checkpointStore endpoint.CheckpointStore,
+) (*endpoint.ProjectionRunner, error) {
+	consumerActive, err := activeEndpoints.Get(consumerName)
+	if err == nil && consumerActive != nil {
+		return nil, fmt.Errorf(
+			"consumer for %s %w",
+			consumerName,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	runner, err := endpoint.
+		NewProjectionRunnerBuilder(projectionName, consumerName, checkpointStore).
+		WithBeforeInterceptors(globalInterceptors(true)...).
+		WithAfterInterceptors(globalInterceptors(false)...).
+		Build(gomesContainer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	activeEndpoints.Set(consumerName, runner)
+
+	return runner, nil
+}
+
+// RedriveDeadLetter creates a runner which consumes the dead letter consumer
+// channel, filters the failed messages, strips their failure metadata and
+// republishes them to the target publisher channel. The target is required:
+// the dead letter messages record the reference name of the failed consumer,
+// which is not a publisher channel.
+//
+// Parameters:
+//   - sourceChannel: the dead letter consumer channel reference name
+//   - targetChannel: the publisher channel to republish to
+//   - opts: the redrive options (filters, rate limit and idle timeout)
+//
+// Returns:
+//   - *endpoint.DeadLetterRedriver: the redrive runner
+//   - error: error if the source channel is not available or the target
+//     channel is missing
+func RedriveDeadLetter(
+	sourceChannel string,
+	targetChannel string,
+	opts endpoint.RedriveOptions,
+) (*endpoint.DeadLetterRedriver, error) {
+	consumerActive, err := activeEndpoints.Get(sourceChannel)
+	if err == nil && consumerActive != nil {
+		return nil, fmt.Errorf(
+			"consumer for %s %w",
+			sourceChannel,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	anyChannel, err := gomesContainer.Get(endpoint.ConsumerChannelKey(sourceChannel))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[dead-letter-redriver] consumer %w: %s",
+			message.ErrChannelNotFound,
+			sourceChannel,
+		)
+	}
+
+	source, ok := anyChannel.(endpoint.InboundChannelAdapter)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[dead-letter-redriver] channel %s is not a consumer channel",
+			sourceChannel,
+		)
+	}
+
+	if targetChannel == "" {
+		return nil, endpoint.ErrRedriveTargetRequired
+	}
+	if _, err := gomesContainer.Get(targetChannel); err != nil {
+		return nil, fmt.Errorf(
+			"[dead-letter-redriver] target %w: %s",
+			message.ErrChannelNotFound,
+			targetChannel,
+		)
+	}
+
+	redriver := endpoint.NewDeadLetterRedriver(
+		source,
+		gomesContainer,
+		targetChannel,
+		opts,
+	)
+	// the registration is the guard of concurrent redrives of the source: the
+	// container rejects it when another endpoint took the source since the
+	// check above.
+	if err := activeEndpoints.Set(sourceChannel, redriver); err != nil {
+		return nil, fmt.Errorf(
+			"consumer for %s %w",
+			sourceChannel,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	return redriver, nil
+}
+
+// Bridge creates a runner which consumes the source consumer channel and
+// republishes its messages to the target publisher channel, optionally
+// transforming and rate limiting them. It is useful for topic renames, broker
+// migrations and dead letter channel drains.
+//
+// Parameters:
+//   - sourceConsumerChannel: the consumer channel reference name
+//   - targetPublisherChannel: the publisher channel reference name
+//   - opts: the bridge options (transformer, rate limit and idle timeout)
+//
+// Returns:
+//   - *endpoint.Bridge: the bridge runner
+//   - error: error if the source or target channel is not available
+func Bridge(
+	sourceConsumerChannel string,
+	targetPublisherChannel string,
+	opts endpoint.BridgeOptions,
+) (*endpoint.Bridge, error) {
+	consumerActive, err := activeEndpoints.Get(sourceConsumerChannel)
+	if err == nil && consumerActive != nil {
+		return nil, fmt.Errorf(
+			"consumer for %s %w",
+			sourceConsumerChannel,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	anyChannel, err := gomesContainer.Get(endpoint.ConsumerChannelKey(sourceConsumerChannel))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[bridge] consumer %w: %s",
+			message.ErrChannelNotFound,
+			sourceConsumerChannel,
+		)
+	}
+
+	source, ok := anyChannel.(endpoint.InboundChannelAdapter)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[bridge] channel %s is not a consumer channel",
+			sourceConsumerChannel,
+		)
+	}
+
+	anyTarget, err := gomesContainer.Get(targetPublisherChannel)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[bridge] target %w: %s",
+			message.ErrChannelNotFound,
+			targetPublisherChannel,
+		)
+	}
+
+	target, ok := anyTarget.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[bridge] channel %s is not a publisher channel",
+			targetPublisherChannel,
+		)
+	}
+
+	bridge := endpoint.NewBridge(source, target, opts)
+	if err := activeEndpoints.Set(sourceConsumerChannel, bridge); err != nil {
+		return nil, fmt.Errorf(
+			"consumer for %s %w",
+			sourceConsumerChannel,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	return bridge, nil
+}
+
+// Quiesce prepares the message system for process replacement during rolling
+// deploys. It stops every consumer from accepting new inbound messages, waits
+// for in-flight messages and their replies to finish and flushes buffered
+// publishers. Unlike Shutdown, connections remain open so the process can
+// still publish until it exits.
+//
+// Parameters:
+//   - ctx: context bounding how long to wait for in-flight work
+//
+// Returns:
+//   - error: error if draining or flushing did not complete
+func Quiesce(ctx context.Context) error {
+	slog.Info("[message-system] quiescing...")
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for k, v := range activeEndpoints.GetAll() {
+		consumer, ok := v.(*endpoint.EventDrivenConsumer)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, consumer *endpoint.EventDrivenConsumer) {
+			defer wg.Done()
+			slog.Info("[message-system] drain consumer", "name", name)
+			if err := consumer.Drain(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("consumer %s: %w", name, err))
+				mu.Unlock()
+			}
+		}(k, consumer)
+	}
+	wg.Wait()
+
+	for k, v := range gomesContainer.GetAll() {
+		if f, ok := v.(flusher); ok {
+			slog.Info("[message-system] flush channel", "name", k)
+			if err := f.Flush(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("channel %v: %w", k, err))
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("[message-system] quiesce failed: %w", err)
+	}
+
+	quiesced.Store(true)
+	slog.Info("[message-system] quiesce completed, ready for replacement")
+	return nil
+}
+
+// PauseConsumer stops the given consumer from fetching messages, without
+// dropping its connection, e.g. during deployments or incident mitigation.
+//
+// Parameters:
+//   - consumerName: the name of the running consumer
+//
+// Returns:
+//   - error: error if the consumer does not exist
+func PauseConsumer(consumerName string) error {
+	consumer, err := activeConsumer(consumerName)
+	if err != nil {
+		return err
+	}
+	consumer.Pause()
+	return nil
+}
+
+// ResumeConsumer restarts fetching messages on a consumer paused by
+// PauseConsumer.
+//
+// Parameters:
+//   - consumerName: the name of the paused consumer
+//
+// Returns:
+//   - error: error if the consumer does not exist
+func ResumeConsumer(consumerName string) error {
+	consumer, err := activeConsumer(consumerName)
+	if err != nil {
+		return err
+	}
+	consumer.Resume()
+	return nil
+}
+
+// activeConsumer returns the event-driven consumer created for the given
+// name.
+func activeConsumer(consumerName string) (*endpoint.EventDrivenConsumer, error) {
+	activeEndpoint, err := activeEndpoints.Get(consumerName)
+	if err != nil {
+		return nil, fmt.Errorf("consumer %s not found", consumerName)
+	}
+	consumer, ok := activeEndpoint.(*endpoint.EventDrivenConsumer)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an event-driven consumer", consumerName)
+	}
+	return consumer, nil
+}
+
+// IsQuiesced reports whether Quiesce completed successfully, meaning the
+// process can be safely replaced.
+//
+// Returns:
+//   - bool: true if the message system is quiesced
+func IsQuiesced() bool {
+	return quiesced.Load()
+}
+
 // Shutdown gracefully shuts down the message system by stopping all active
 // consumers and closing all channels. This function should be called during
 // application shutdown to ensure proper cleanup of resources. All consumers
-// are stopped first, followed by closing of all channels.
+// are stopped first, then the schedulers of the command and event buses, so
+// no delayed message is published afterwards, then the stop hooks of the
+// container run: consumer channels are closed before the publisher channels
+// and the connections, and the dependencies implementing container.Stopper
+// are stopped last.
 func Shutdown() {
 	slog.Info("[message-system] shutting down...")
 	for k, v := range activeEndpoints.GetAll() {
@@ -552,48 +1897,104 @@ func Shutdown() {
 			inboundChannel.Stop()
 		}
 	}
-
-	for k, v := range gomesContainer.GetAll() {
-		switch c := v.(type) {
-		case message.ConsumerChannel:
-			slog.Info("[message-system] close consumer channel", "name", c.Name())
-			c.Close()
-		case message.SubscriberChannel:
-			slog.Info("[message-system] unsubscribe channel", "name", c.Name())
-			c.Unsubscribe()
-		case endpoint.OutboundChannelAdapter:
-			slog.Info("[message-system] close outbound channel", "name", k)
-			c.Close()
-		case adapter.ChannelConnection:
-			slog.Info("[message-system] disconnect channel connection", "name", k)
-			c.Disconnect()
+	for _, runtime := range runtimeConsumers.GetAll() {
+		runtime.cancel()
+	}
+	// the schedulers are stopped before the publisher channels of their
+	// delayed messages are closed
+	for _, v := range activeEndpoints.GetAll() {
+		switch activeBus := v.(type) {
+		case *bus.CommandBus:
+			activeBus.Stop()
+		case *bus.EventBus:
+			activeBus.Stop()
 		}
 	}
+
+	// consumers are stopped before the producers and the connections, in
+	// reverse registration order
+	if err := gomesContainer.Stop(context.Background()); err != nil {
+		slog.Error("[message-system] failed to stop components", "reason", err.Error())
+	}
 	slog.Info("[message-system] shutdown completed")
 }
 
+// EndpointInfo describes an active endpoint of the message system.
+type EndpointInfo struct {
+	// Name is the reference name of the endpoint.
+	Name string `json:"name"`
+	// Type describes the kind of the endpoint, e.g. "[inbound] Event-Driven".
+	Type string `json:"type"`
+	// Channel is the channel the endpoint consumes from or publishes to.
+	Channel string `json:"channel"`
+	// Status is the status of the endpoint, e.g. "running" or "paused".
+	Status string `json:"status"`
+	// Config holds the configuration of the endpoint, if available.
+	Config any `json:"config,omitempty"`
+}
+
+// ActiveEndpoints returns the currently active endpoints of the message
+// system, sorted by name, so applications can expose them through their own
+// logging or HTTP endpoints.
+//
+// Returns:
+//   - []EndpointInfo: the active endpoints
+func ActiveEndpoints() []EndpointInfo {
+	endpoints := activeEndpoints.GetAll()
+	infos := make([]EndpointInfo, 0, len(endpoints))
+	for _, name := range slices.Sorted(maps.Keys(endpoints)) {
+		info := EndpointInfo{
+			Name:    name,
+			Type:    endpointType(endpoints[name]),
+			Channel: name,
+			Status:  "active",
+		}
+		if consumer, ok := endpoints[name].(*endpoint.EventDrivenConsumer); ok {
+			settings := consumer.Settings()
+			info.Channel = settings.Channel
+			info.Status = consumer.Status()
+			info.Config = settings
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 // ShowActiveEndpoints displays all currently active endpoints in the message
 // system. This function is useful for debugging and monitoring purposes,
 // showing all registered endpoints and their types.
 func ShowActiveEndpoints() {
 	fmt.Println("\n---[Message System] Active Endpoints ---")
-	fmt.Printf("%-30s | %-10s\n", "Endpoint Name", "Type")
-	fmt.Println("-------------------------------------------")
-	for name, ep := range activeEndpoints.GetAll() {
-		endpointType := "undefined"
-		switch ep.(type) {
-		case *endpoint.EventDrivenConsumer:
-			endpointType = "[inbound] Event-Driven"
-		case *bus.CommandBus:
-			endpointType = "[outbound] Command-Bus"
-		case *bus.QueryBus:
-			endpointType = "[outbound] Query-Bus"
-		case *bus.EventBus:
-			endpointType = "[outbound] Event-Bus"
-		}
-		fmt.Printf("%-30s | %-10s\n", name, endpointType)
+	fmt.Printf("%-30s | %-22s | %-10s\n", "Endpoint Name", "Type", "Status")
+	fmt.Println("------------------------------------------------------------------")
+	for _, info := range ActiveEndpoints() {
+		fmt.Printf("%-30s | %-22s | %-10s\n", info.Name, info.Type, info.Status)
+	}
+	fmt.Println("------------------------------------------------------------------")
+}
+
+// endpointType returns the type description of an active endpoint.
+func endpointType(activeEndpoint any) string {
+	switch activeEndpoint.(type) {
+	case *endpoint.EventDrivenConsumer:
+		return "[inbound] Event-Driven"
+	case *endpoint.BackfillCoordinator:
+		return "[inbound] Backfill"
+	case *endpoint.DeadLetterRedriver:
+		return "[inbound] DLQ-Redrive"
+	case *endpoint.ProjectionRunner:
+		return "[inbound] Projection"
+	case *endpoint.Bridge:
+		return "[inbound] Bridge"
+	case *bus.CommandBus:
+		return "[outbound] Command-Bus"
+	case *bus.QueryBus:
+		return "[outbound] Query-Bus"
+	case *bus.EventBus:
+		return "[outbound] Event-Bus"
+	default:
+		return "undefined"
 	}
-	fmt.Println("-------------------------------------------")
 }
 
 // EnableOtelTrace enables OpenTelemetry distributed tracing for the message
@@ -603,3 +2004,32 @@ func ShowActiveEndpoints() {
 func EnableOtelTrace() {
 	otel.EnableTrace()
 }
+
+// EnableOtelMetrics enables the OpenTelemetry messaging metrics: the
+// messaging.process.duration histogram of the gateways and the
+// messaging.client.consumed/published counters of the channel adapters. It
+// requires that an OpenTelemetry MeterProvider has been configured globally.
+func EnableOtelMetrics() {
+	otel.EnableMetrics()
+}
+
+// EnableTenantRouting publishes the messages of each tenant, given by their
+// tenantId header, to the channel of the tenant, e.g. "orders.tenant-a" for
+// the buses of the "orders" channel with message.TenantChannelSuffix("."). The
+// channels of the tenants must be registered as publisher channels. It
+// applies to the buses created afterwards by CommandBusByChannel,
+// QueryBusByChannel and EventBusByChannel, so it must be called before them.
+//
+// Parameters:
+//   - strategy: the tenant channel strategy
+func EnableTenantRouting(strategy message.TenantChannelStrategy) {
+	tenantRouting = strategy
+}
+
+// EnableMessageHistory enables the EIP Message History: consumers, routers,
+// handlers and outbound channels append themselves, with a timestamp, to the
+// history header of the messages they process, so multi-hop flows and dead
+// letters can be traced back. The history is read with message.GetHistory.
+func EnableMessageHistory() {
+	message.EnableHistory()
+}
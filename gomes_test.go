@@ -1,11 +1,16 @@
 package gomes_test
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/jeffersonbrasilino/gomes"
 	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/channel"
 	"github.com/jeffersonbrasilino/gomes/message/endpoint"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
@@ -45,6 +50,19 @@ func TestAddPublisherChannel_Duplicate(t *testing.T) {
 	}
 }
 
+func TestAddPublisherChannel_HandlerCollision(t *testing.T) {
+	createUser := func(ctx context.Context, command createUser) (string, error) {
+		return "", nil
+	}
+	if err := gomes.HandleCommand("collision.create.user", createUser); err != nil {
+		t.Fatalf("unexpected error registering handler: %v", err)
+	}
+	err := gomes.AddPublisherChannel(&fakeOutboundBuilder{name: "collision.create.user"})
+	if !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate registration error, got %v", err)
+	}
+}
+
 func TestAddConsumerChannel_Duplicate(t *testing.T) {
 	b := &fakeInboundBuilder{name: "in.chan.dup"}
 	if err := gomes.AddConsumerChannel(b); err != nil {
@@ -73,6 +91,13 @@ func TestAddActionHandler_Nil(t *testing.T) {
 	}
 }
 
+func TestSubscribeEvent_Nil(t *testing.T) {
+	err := gomes.SubscribeEvent[handler.Action](nil)
+	if err == nil {
+		t.Fatal("expected error when subscribing nil subscriber, got nil")
+	}
+}
+
 func TestEnableOtelTraceAndShowShutdown(t *testing.T) {
 	// Call EnableOtelTrace, ShowActiveEndpoints and Shutdown to ensure they run without panic.
 	gomes.EnableOtelTrace()
@@ -94,6 +119,10 @@ func TestStartAndBuses(t *testing.T) {
 		if _, err := gomes.QueryBus(); err != nil {
 			t.Fatalf("QueryBus should be available after Start: %v", err)
 		}
+
+		if _, err := gomes.EventBus(); err != nil {
+			t.Fatalf("EventBus should be available after Start: %v", err)
+		}
 	})
 }
 
@@ -137,8 +166,392 @@ func TestEventDrivenConsumer_AlreadyExists(t *testing.T) {
 			t.Fatalf("unexpected creating command bus: %v", err)
 		}
 
-		if _, err := gomes.EventDrivenConsumer(name); err == nil {
-			t.Fatal("expected error when creating consumer for existing endpoint, got nil")
+		_, err := gomes.EventDrivenConsumer(name)
+		if !errors.Is(err, gomes.ErrDuplicateRegistration) {
+			t.Fatalf("expected ErrDuplicateRegistration when creating consumer for existing endpoint, got %v", err)
 		}
 	})
 }
+
+func TestBackfillCoordinator_ChannelNotFound(t *testing.T) {
+	_, err := gomes.BackfillCoordinator("backfill.missing")
+	if !errors.Is(err, gomes.ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound when creating backfill for missing channel, got %v", err)
+	}
+}
+
+func TestProjectionRunner_ChannelNotFound(t *testing.T) {
+	_, err := gomes.ProjectionRunner(
+		"orders",
+		"projection.missing",
+		endpoint.NewInMemoryCheckpointStore(),
+	)
+	if !errors.Is(err, gomes.ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound when creating projection for missing channel, got %v", err)
+	}
+}
+
+func TestAddGlobalInterceptor_Nil(t *testing.T) {
+	if err := gomes.AddGlobalBeforeInterceptor(nil); err == nil {
+		t.Fatal("expected error when registering nil before interceptor, got nil")
+	}
+	if err := gomes.AddGlobalAfterInterceptor(nil); err == nil {
+		t.Fatal("expected error when registering nil after interceptor, got nil")
+	}
+}
+
+func TestQuiesce(t *testing.T) {
+	if err := gomes.Quiesce(context.Background()); err != nil {
+		t.Fatalf("Quiesce should not return error, got: %v", err)
+	}
+	if !gomes.IsQuiesced() {
+		t.Fatal("expected message system to be quiesced")
+	}
+}
+
+func TestRedriveDeadLetter_ChannelNotFound(t *testing.T) {
+	_, err := gomes.RedriveDeadLetter("dlq.missing", "", endpoint.RedriveOptions{})
+	if err == nil {
+		t.Fatal("expected error when redriving missing channel, got nil")
+	}
+}
+
+func TestMatchReply_ChannelNotFound(t *testing.T) {
+	_, _, err := gomes.MatchReply(context.Background(), "replies.missing", message.NewMessageBuilder().Build())
+	if err == nil {
+		t.Fatal("expected error when matching a reply of a missing channel, got nil")
+	}
+}
+
+func TestBridge_ChannelNotFound(t *testing.T) {
+	_, err := gomes.Bridge("orders.legacy", "orders", endpoint.BridgeOptions{})
+	if err == nil {
+		t.Fatal("expected error when bridging missing channel, got nil")
+	}
+}
+
+type pointerRegistrationAction struct{ prefix string }
+
+func (a *pointerRegistrationAction) Name() string { return a.prefix + "pointer.registration" }
+
+type valueRegistrationAction struct{}
+
+func (a valueRegistrationAction) Name() string { return "value.registration" }
+
+type valueFactoryAction struct{}
+
+func (a valueFactoryAction) Name() string { return "factory.registration" }
+
+type registrationHandler[T handler.Action] struct{}
+
+func (h *registrationHandler[T]) Handle(_ context.Context, _ T) (any, error) {
+	return nil, nil
+}
+
+func TestAddActionHandler_PointerAndValueActions(t *testing.T) {
+	err := gomes.AddActionHandler(&registrationHandler[*pointerRegistrationAction]{})
+	if err != nil {
+		t.Fatalf("unexpected error registering pointer action: %v", err)
+	}
+
+	err = gomes.AddActionHandler(&registrationHandler[valueRegistrationAction]{})
+	if err != nil {
+		t.Fatalf("unexpected error registering value action: %v", err)
+	}
+
+	err = gomes.AddActionHandler(&registrationHandler[handler.Action]{})
+	if err == nil {
+		t.Fatal("expected error registering interface action type, got nil")
+	}
+}
+
+func TestAddActionHandlerWithOptions(t *testing.T) {
+	err := gomes.AddActionHandlerWithOptions(
+		&registrationHandler[*pointerRegistrationAction]{},
+		gomes.WithRoute("options.first"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error registering first route: %v", err)
+	}
+
+	err = gomes.AddActionHandlerWithOptions(
+		&registrationHandler[*pointerRegistrationAction]{},
+		gomes.WithRoute("options.second"),
+		gomes.WithChannel("options.second.channel"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error registering the same action on another route: %v", err)
+	}
+
+	err = gomes.AddActionHandlerWithOptions(
+		&registrationHandler[*pointerRegistrationAction]{},
+		gomes.WithRoute("options.first"),
+		gomes.WithVersion("2.0"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error registering a version of the route: %v", err)
+	}
+
+	err = gomes.AddActionHandlerWithOptions(
+		&registrationHandler[*pointerRegistrationAction]{},
+		gomes.WithRoute("options.first"),
+	)
+	if !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate registration error, got %v", err)
+	}
+
+	err = gomes.AddActionHandlerWithOptions(
+		&registrationHandler[*pointerRegistrationAction]{},
+		gomes.WithRoute("options.third"),
+		gomes.WithChannel("options.second.channel"),
+	)
+	if !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate channel error, got %v", err)
+	}
+}
+
+type createUser struct {
+	Email string `json:"email"`
+}
+
+func TestHandleCommand(t *testing.T) {
+	createUserFunc := func(ctx context.Context, cmd createUser) (string, error) {
+		return cmd.Email, nil
+	}
+	if err := gomes.HandleCommand("func.create.user", createUserFunc); err != nil {
+		t.Fatalf("unexpected error registering function handler: %v", err)
+	}
+	if err := gomes.HandleQuery("func.create.user", createUserFunc); !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate registration error, got %v", err)
+	}
+	if err := gomes.HandleCommand("", createUserFunc); err == nil {
+		t.Error("expected error registering an empty route")
+	}
+	if err := gomes.HandleCommand[createUser, string]("func.nil", nil); err == nil {
+		t.Error("expected error registering a nil function")
+	}
+
+	userCreated := func(ctx context.Context, event createUser) error { return nil }
+	if err := gomes.HandleEvent("func.user.created", userCreated); err != nil {
+		t.Fatalf("unexpected error subscribing function: %v", err)
+	}
+	if err := gomes.HandleEvent("func.create.user", userCreated); !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate registration error, got %v", err)
+	}
+}
+
+func TestAddActionHandlerFactory(t *testing.T) {
+	factory := func(c container.Container[any, any]) (handler.ActionHandler[valueFactoryAction, any], error) {
+		return &registrationHandler[valueFactoryAction]{}, nil
+	}
+	if err := gomes.AddActionHandlerFactory(factory); err != nil {
+		t.Fatalf("unexpected error registering handler factory: %v", err)
+	}
+	if err := gomes.AddActionHandlerFactory(factory); !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate registration error, got %v", err)
+	}
+	if err := gomes.AddActionHandlerFactory[valueFactoryAction, any](nil); err == nil {
+		t.Error("expected error registering a nil factory")
+	}
+}
+
+func TestAddDependency(t *testing.T) {
+	if err := gomes.AddDependency("dependency.repository", struct{}{}); err != nil {
+		t.Fatalf("unexpected error registering dependency: %v", err)
+	}
+	if err := gomes.AddDependency("dependency.repository", struct{}{}); !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Errorf("expected duplicate registration error, got %v", err)
+	}
+	if err := gomes.AddDependency("", struct{}{}); err == nil {
+		t.Error("expected error registering an unnamed dependency")
+	}
+	if err := gomes.AddDependency("dependency.nil", nil); err == nil {
+		t.Error("expected error registering a nil dependency")
+	}
+}
+
+func TestScatterGatherQueryBus(t *testing.T) {
+	builder := endpoint.NewScatterGatherBuilder("sg.query.bus", "sg.a", "sg.b")
+	queryBus, err := gomes.ScatterGatherQueryBus(builder)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := gomes.ScatterGatherQueryBus(builder)
+	if err != nil || again != queryBus {
+		t.Errorf("expected the registered query bus, got %v (%v)", again, err)
+	}
+
+	if _, err := gomes.ScatterGatherQueryBus(endpoint.NewScatterGatherBuilder("sg.empty")); err == nil {
+		t.Error("expected error for scatter-gather without channels")
+	}
+}
+
+func TestRunAllConsumers_ConsumerNotFound(t *testing.T) {
+	err := gomes.RunAllConsumers(context.Background(), gomes.RunConsumersOptions{
+		Consumers: []string{"run.all.missing"},
+	})
+	if err == nil {
+		t.Fatal("expected error when running a missing consumer, got nil")
+	}
+}
+
+func TestStartConsumers_ConsumerNotFound(t *testing.T) {
+	handle, err := gomes.StartConsumersWithOptions(context.Background(), gomes.RunConsumersOptions{
+		Consumers: []string{"start.consumers.missing"},
+	})
+	if err == nil || handle != nil {
+		t.Fatalf("expected error when starting a missing consumer, got %v", err)
+	}
+}
+
+// closingConsumerChannel is a consumer channel recording whether it was
+// closed.
+type closingConsumerChannel struct {
+	closed bool
+}
+
+func (c *closingConsumerChannel) Name() string { return "closing" }
+func (c *closingConsumerChannel) Receive(ctx context.Context) (*message.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+func (c *closingConsumerChannel) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestStartConsumers_ClosesBuiltConsumersOnError(t *testing.T) {
+	if _, err := gomes.CommandBus(); errors.Is(err, gomes.ErrNotStarted) {
+		if err := gomes.Start(); err != nil {
+			t.Fatalf("Start should not return error, got: %v", err)
+		}
+	}
+
+	channel := &closingConsumerChannel{}
+	err := gomes.AddDependency(
+		endpoint.ConsumerChannelKey("start.partial.built"),
+		adapter.NewInboundChannelAdapter(channel, "start.partial.built", "", nil, nil, nil, false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = gomes.StartConsumersWithOptions(context.Background(), gomes.RunConsumersOptions{
+		Consumers: []string{"start.partial.built", "start.partial.missing"},
+	})
+	if err == nil {
+		t.Fatal("expected error when starting a missing consumer, got nil")
+	}
+	if !channel.closed {
+		t.Error("expected the consumer built before the error to be closed")
+	}
+	if _, err := gomes.EventDrivenConsumer("start.partial.built"); err != nil {
+		t.Errorf("expected the built consumer to be released, got %v", err)
+	}
+}
+
+func TestPauseConsumer_ConsumerNotFound(t *testing.T) {
+	if err := gomes.PauseConsumer("pause.missing"); err == nil {
+		t.Fatal("expected error when pausing a missing consumer, got nil")
+	}
+	if err := gomes.ResumeConsumer("pause.missing"); err == nil {
+		t.Fatal("expected error when resuming a missing consumer, got nil")
+	}
+}
+
+// validatedInboundBuilder is a consumer channel builder referencing a
+// connection and a dead letter channel.
+type validatedInboundBuilder struct {
+	fakeInboundBuilder
+	connection string
+	deadLetter string
+}
+
+func (f *validatedInboundBuilder) ConnectionReferenceName() string { return f.connection }
+func (f *validatedInboundBuilder) DeadLetterChannelName() string   { return f.deadLetter }
+func (f *validatedInboundBuilder) UnroutableChannelName() string   { return "" }
+func (f *validatedInboundBuilder) QuarantineChannelName() string   { return "" }
+func (f *validatedInboundBuilder) ResponseChannelName() string     { return "validate.missing.response" }
+func (f *validatedInboundBuilder) DeadLetterRoutes() map[string]string {
+	return map[string]string{"createUser": "validate.missing.user.dlq"}
+}
+
+func TestValidate(t *testing.T) {
+	err := gomes.AddConsumerChannel(&validatedInboundBuilder{
+		fakeInboundBuilder: fakeInboundBuilder{name: "validate.in"},
+		connection:         "validate.missing.connection",
+		deadLetter:         "validate.missing.dlq",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := gomes.Validate()
+	if report.Valid() {
+		t.Fatal("expected validation issues")
+	}
+	for _, expected := range []string{
+		"[consumer-channel] validate.in: connection validate.missing.connection is not registered",
+		"[consumer-channel] validate.in: dead letter channel validate.missing.dlq has no publisher channel registered",
+		"[consumer-channel] validate.in: dead letter channel validate.missing.user.dlq of route createUser has no publisher channel registered",
+		"[consumer-channel] validate.in: response channel validate.missing.response has no publisher channel registered",
+	} {
+		if !strings.Contains(report.Err().Error(), expected) {
+			t.Errorf("expected issue %q, got %v", expected, report.Err())
+		}
+	}
+}
+
+// runtimeInboundBuilder builds a consumer channel over an in-memory channel.
+type runtimeInboundBuilder struct{ name string }
+
+func (f *runtimeInboundBuilder) Build(c container.Container[any, any]) (*adapter.InboundChannelAdapter, error) {
+	return adapter.NewInboundChannelAdapter(
+		channel.NewPointToPointChannel(f.name), f.name, "", nil, nil, nil, false,
+	), nil
+}
+
+func (f *runtimeInboundBuilder) ReferenceName() string { return f.name }
+
+func TestConsumerChannelRuntime(t *testing.T) {
+	if _, err := gomes.CommandBus(); errors.Is(err, gomes.ErrNotStarted) {
+		if err := gomes.Start(); err != nil {
+			t.Fatalf("Start should not return error, got: %v", err)
+		}
+	}
+
+	name := "runtime.consumer"
+	if err := gomes.AddConsumerChannelRuntime(&runtimeInboundBuilder{name: name}); err != nil {
+		t.Fatalf("unexpected error adding consumer channel: %v", err)
+	}
+	err := gomes.AddConsumerChannelRuntime(&runtimeInboundBuilder{name: name})
+	if !errors.Is(err, gomes.ErrDuplicateRegistration) {
+		t.Fatalf("expected ErrDuplicateRegistration, got %v", err)
+	}
+
+	if err := gomes.RemoveConsumerChannel(name); err != nil {
+		t.Fatalf("unexpected error removing consumer channel: %v", err)
+	}
+	err = gomes.RemoveConsumerChannel(name)
+	if !errors.Is(err, gomes.ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+}
+
+func TestActiveEndpoints(t *testing.T) {
+	if _, err := gomes.CommandBus(); errors.Is(err, gomes.ErrNotStarted) {
+		if err := gomes.Start(); err != nil {
+			t.Fatalf("Start should not return error, got: %v", err)
+		}
+	}
+
+	for _, info := range gomes.ActiveEndpoints() {
+		if info.Name == "default.channel.command" {
+			if info.Type != "[outbound] Command-Bus" || info.Status != "active" {
+				t.Errorf("unexpected endpoint info: %+v", info)
+			}
+			return
+		}
+	}
+	t.Error("expected the default command bus in the active endpoints")
+}
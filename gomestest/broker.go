@@ -0,0 +1,221 @@
+// Package gomestest provides an in-memory broker for integration tests of the
+// message system, replacing Kafka or RabbitMQ without Docker.
+//
+// The Broker implementation supports:
+// - Topics consumed as competing consumer queues
+// - Redelivery of messages negatively acknowledged with requeue
+// - Dead letter channels through the regular publisher channels
+// - Recording of the messages sent by publisher channels
+// - Awaiting the settlement of a message by its message id
+package gomestest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// ErrBrokerClosed is returned by consumers of a disconnected broker.
+var ErrBrokerClosed = errors.New("[gomestest-broker] broker closed")
+
+// Record is a message stored in the in-memory broker, in its wire format.
+type Record struct {
+	// Topic is the topic the record was published to.
+	Topic string
+	// Headers are the message headers.
+	Headers map[string]string
+	// Payload is the JSON encoded message payload.
+	Payload []byte
+	// Deliveries is the number of times the record was delivered.
+	Deliveries int
+}
+
+// Broker is an in-memory message broker. Each topic is a queue shared by its
+// consumers; records are removed when committed, and requeued when
+// negatively acknowledged with requeue.
+type Broker struct {
+	mu        sync.Mutex
+	notify    chan struct{}
+	closed    bool
+	queues    map[string][]*Record
+	sent      map[string][]*Record
+	settled   map[string]bool
+	delivered map[string]int
+}
+
+// NewBroker creates a new in-memory broker.
+//
+// Returns:
+//   - *Broker: the broker instance
+func NewBroker() *Broker {
+	return &Broker{
+		notify:    make(chan struct{}),
+		queues:    map[string][]*Record{},
+		sent:      map[string][]*Record{},
+		settled:   map[string]bool{},
+		delivered: map[string]int{},
+	}
+}
+
+// Publish stores a record at the end of the topic queue.
+//
+// Parameters:
+//   - topic: the topic name
+//   - headers: the message headers
+//   - payload: the JSON encoded message payload
+func (b *Broker) Publish(topic string, headers map[string]string, payload []byte) {
+	b.publish(topic, headers, payload, false)
+}
+
+// Sent returns the records published to a topic by publisher channels, in
+// publishing order.
+//
+// Parameters:
+//   - topic: the topic name
+//
+// Returns:
+//   - []*Record: the published records
+func (b *Broker) Sent(topic string) []*Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*Record{}, b.sent[topic]...)
+}
+
+// Pending returns the number of records waiting to be consumed in a topic.
+//
+// Parameters:
+//   - topic: the topic name
+//
+// Returns:
+//   - int: the number of pending records
+func (b *Broker) Pending(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queues[topic])
+}
+
+// Deliveries returns how many times the message with the given id was
+// delivered to consumers.
+//
+// Parameters:
+//   - messageId: the message id
+//
+// Returns:
+//   - int: the number of deliveries
+func (b *Broker) Deliveries(messageId string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.delivered[messageId]
+}
+
+// AwaitSettled blocks until the message with the given id is committed, or
+// negatively acknowledged without requeue, by a consumer.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - messageId: the message id
+//
+// Returns:
+//   - error: error if the context is done before the message is settled
+func (b *Broker) AwaitSettled(ctx context.Context, messageId string) error {
+	for {
+		b.mu.Lock()
+		settled, notify := b.settled[messageId], b.notify
+		b.mu.Unlock()
+		if settled {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"[gomestest-broker] message %s not processed: %w",
+				messageId,
+				ctx.Err(),
+			)
+		case <-notify:
+		}
+	}
+}
+
+// Close disconnects the broker, failing its pending consumers.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.broadcast()
+}
+
+// publish stores a record, recording it as sent when published by a
+// publisher channel.
+func (b *Broker) publish(
+	topic string,
+	headers map[string]string,
+	payload []byte,
+	sent bool,
+) {
+	record := &Record{
+		Topic:   topic,
+		Headers: maps.Clone(headers),
+		Payload: payload,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queues[topic] = append(b.queues[topic], record)
+	if sent {
+		b.sent[topic] = append(b.sent[topic], record)
+	}
+	b.broadcast()
+}
+
+// receive blocks until a record is available in the topic and delivers it.
+func (b *Broker) receive(ctx context.Context, topic string) (*Record, error) {
+	for {
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			return nil, ErrBrokerClosed
+		}
+		if queue := b.queues[topic]; len(queue) > 0 {
+			record := queue[0]
+			b.queues[topic] = queue[1:]
+			record.Deliveries++
+			b.delivered[record.Headers[message.HeaderMessageId]]++
+			b.mu.Unlock()
+			return record, nil
+		}
+		notify := b.notify
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// settle marks a delivered record as settled, requeueing it at the front of
+// its topic when requested.
+func (b *Broker) settle(record *Record, requeue bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if requeue {
+		b.queues[record.Topic] = append([]*Record{record}, b.queues[record.Topic]...)
+	} else {
+		b.settled[record.Headers[message.HeaderMessageId]] = true
+	}
+	b.broadcast()
+}
+
+// broadcast wakes up the goroutines waiting for a broker change. It must be
+// called with the lock held.
+func (b *Broker) broadcast() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
@@ -0,0 +1,454 @@
+package gomestest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// Connection is a channel connection to an in-memory broker.
+type Connection struct {
+	name   string
+	broker *Broker
+}
+
+// NewConnection creates a new connection to an in-memory broker.
+//
+// Parameters:
+//   - name: the connection name identifier
+//   - broker: the in-memory broker
+//
+// Returns:
+//   - *Connection: the connection instance
+func NewConnection(name string, broker *Broker) *Connection {
+	return &Connection{name: name, broker: broker}
+}
+
+// ReferenceName returns the connection name identifier.
+//
+// Returns:
+//   - string: the connection name
+func (c *Connection) ReferenceName() string {
+	return c.name
+}
+
+// Connect is a no-op, the in-memory broker is always available.
+//
+// Returns:
+//   - error: always nil
+func (c *Connection) Connect() error {
+	return nil
+}
+
+// Disconnect closes the in-memory broker, failing its pending consumers.
+//
+// Returns:
+//   - error: always nil
+func (c *Connection) Disconnect() error {
+	c.broker.Close()
+	return nil
+}
+
+// Broker returns the in-memory broker of the connection.
+//
+// Returns:
+//   - *Broker: the broker instance
+func (c *Connection) Broker() *Broker {
+	return c.broker
+}
+
+// MessageTranslator translates messages to and from the broker records,
+// encoding the payload as JSON like the broker channels do.
+type MessageTranslator struct{}
+
+// NewMessageTranslator creates a new message translator instance.
+//
+// Returns:
+//   - *MessageTranslator: new message translator instance
+func NewMessageTranslator() *MessageTranslator {
+	return &MessageTranslator{}
+}
+
+// FromMessage converts an internal message to a broker record.
+//
+// Parameters:
+//   - msg: the internal message to be converted
+//
+// Returns:
+//   - *Record: the broker record
+//   - error: error if payload serialization fails
+func (m *MessageTranslator) FromMessage(msg *message.Message) (*Record, error) {
+	headers := msg.GetHeader().All()
+	payload, err := json.Marshal(msg.GetPayload())
+	if err == nil {
+		payload, err = message.CompressPayload(
+			headers[message.HeaderContentEncoding],
+			payload,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[gomestest-message-translator] payload converter error: %v",
+			err.Error(),
+		)
+	}
+	return &Record{Headers: headers, Payload: payload}, nil
+}
+
+// ToMessage converts a broker record to an internal message.
+//
+// Parameters:
+//   - record: the broker record to be converted
+//
+// Returns:
+//   - *message.Message: the internal message
+//   - error: error if header or payload conversion fails
+func (m *MessageTranslator) ToMessage(record *Record) (*message.Message, error) {
+	headers := map[string]string{}
+	for key, value := range record.Headers {
+		if key != message.HeaderContentEncoding {
+			headers[key] = value
+		}
+	}
+	if record.Topic != "" {
+		headers[message.HeaderSourceTopic] = record.Topic
+	}
+	payload, err := message.DecompressPayload(
+		record.Headers[message.HeaderContentEncoding],
+		record.Payload,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[gomestest-message-translator] payload converter error: %v",
+			err.Error(),
+		)
+	}
+
+	messageBuilder, err := message.NewMessageBuilderFromHeaders(headers)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[gomestest-message-translator] header converter error: %v",
+			err.Error(),
+		)
+	}
+	messageBuilder.WithPayload(payload)
+	messageBuilder.WithRawMessage(record)
+	return messageBuilder.Build(), nil
+}
+
+// publisherChannelAdapterBuilder builds publisher channels over a topic of
+// the in-memory broker.
+type publisherChannelAdapterBuilder struct {
+	*adapter.OutboundChannelAdapterBuilder[*Record]
+	connectionReferenceName string
+}
+
+// outboundChannelAdapter publishes messages to a topic of the in-memory
+// broker.
+type outboundChannelAdapter struct {
+	broker            *Broker
+	topicName         string
+	messageTranslator adapter.OutboundChannelMessageTranslator[*Record]
+}
+
+// NewPublisherChannelAdapterBuilder creates a new in-memory publisher channel
+// adapter builder instance.
+//
+// Parameters:
+//   - connectionReferenceName: reference name for the in-memory connection
+//   - topicName: the topic to publish messages to
+//
+// Returns:
+//   - *publisherChannelAdapterBuilder: configured builder instance
+func NewPublisherChannelAdapterBuilder(
+	connectionReferenceName string,
+	topicName string,
+) *publisherChannelAdapterBuilder {
+	return &publisherChannelAdapterBuilder{
+		adapter.NewOutboundChannelAdapterBuilder(
+			topicName,
+			topicName,
+			NewMessageTranslator(),
+		),
+		connectionReferenceName,
+	}
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (b *publisherChannelAdapterBuilder) ConnectionReferenceName() string {
+	return b.connectionReferenceName
+}
+
+// Build constructs an in-memory outbound channel adapter from the dependency
+// container.
+//
+// Parameters:
+//   - container: dependency container containing required components
+//
+// Returns:
+//   - endpoint.OutboundChannelAdapter: configured publisher channel
+//   - error: error if connection not found or is invalid
+func (b *publisherChannelAdapterBuilder) Build(
+	container container.Container[any, any],
+) (endpoint.OutboundChannelAdapter, error) {
+	conn, err := getConnection(container, b.connectionReferenceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.OutboundChannelAdapterBuilder.BuildOutboundAdapter(
+		NewOutboundChannelAdapter(conn.broker, b.ChannelName(), b.MessageTranslator()),
+	)
+}
+
+// NewOutboundChannelAdapter creates a new in-memory outbound channel adapter
+// instance.
+//
+// Parameters:
+//   - broker: the in-memory broker
+//   - topicName: the topic to publish messages to
+//   - messageTranslator: translator for converting internal messages to records
+//
+// Returns:
+//   - *outboundChannelAdapter: configured outbound channel adapter
+func NewOutboundChannelAdapter(
+	broker *Broker,
+	topicName string,
+	messageTranslator adapter.OutboundChannelMessageTranslator[*Record],
+) *outboundChannelAdapter {
+	return &outboundChannelAdapter{
+		broker:            broker,
+		topicName:         topicName,
+		messageTranslator: messageTranslator,
+	}
+}
+
+// Name returns the topic name of the outbound channel adapter.
+//
+// Returns:
+//   - string: the topic name
+func (a *outboundChannelAdapter) Name() string {
+	return a.topicName
+}
+
+// Send publishes a message to the broker topic.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be published
+//
+// Returns:
+//   - error: error if the message cannot be translated or the context is done
+func (a *outboundChannelAdapter) Send(ctx context.Context, msg *message.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	record, err := a.messageTranslator.FromMessage(msg)
+	if err != nil {
+		return err
+	}
+	a.broker.publish(a.topicName, record.Headers, record.Payload, true)
+	return nil
+}
+
+// consumerChannelAdapterBuilder builds consumer channels over a topic of the
+// in-memory broker.
+type consumerChannelAdapterBuilder struct {
+	*adapter.InboundChannelAdapterBuilder[*Record]
+	connectionReferenceName string
+}
+
+// inboundChannelAdapter consumes messages from a topic of the in-memory
+// broker.
+type inboundChannelAdapter struct {
+	broker            *Broker
+	topicName         string
+	messageTranslator adapter.InboundChannelMessageTranslator[*Record]
+	ctx               context.Context
+	cancelCtx         context.CancelFunc
+}
+
+// NewConsumerChannelAdapterBuilder creates a new in-memory consumer channel
+// adapter builder instance.
+//
+// Parameters:
+//   - connectionReferenceName: reference name for the in-memory connection
+//   - topicName: the topic to consume messages from
+//   - consumerName: the consumer name identifier
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: configured builder instance
+func NewConsumerChannelAdapterBuilder(
+	connectionReferenceName string,
+	topicName string,
+	consumerName string,
+) *consumerChannelAdapterBuilder {
+	return &consumerChannelAdapterBuilder{
+		adapter.NewInboundChannelAdapterBuilder(
+			consumerName,
+			topicName,
+			NewMessageTranslator(),
+		),
+		connectionReferenceName,
+	}
+}
+
+// ConnectionReferenceName returns the reference name of the connection used
+// by the channel.
+//
+// Returns:
+//   - string: the connection reference name
+func (b *consumerChannelAdapterBuilder) ConnectionReferenceName() string {
+	return b.connectionReferenceName
+}
+
+// Build constructs an in-memory inbound channel adapter from the dependency
+// container.
+//
+// Parameters:
+//   - container: dependency container containing required components
+//
+// Returns:
+//   - *adapter.InboundChannelAdapter: configured inbound channel adapter
+//   - error: error if connection not found or is invalid
+func (b *consumerChannelAdapterBuilder) Build(
+	container container.Container[any, any],
+) (*adapter.InboundChannelAdapter, error) {
+	conn, err := getConnection(container, b.connectionReferenceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.InboundChannelAdapterBuilder.BuildInboundAdapter(
+		NewInboundChannelAdapter(conn.broker, b.ReferenceName(), b.MessageTranslator()),
+	)
+}
+
+// NewInboundChannelAdapter creates a new in-memory inbound channel adapter
+// instance.
+//
+// Parameters:
+//   - broker: the in-memory broker
+//   - topicName: the topic to consume messages from
+//   - messageTranslator: translator for converting records to internal messages
+//
+// Returns:
+//   - *inboundChannelAdapter: configured inbound channel adapter
+func NewInboundChannelAdapter(
+	broker *Broker,
+	topicName string,
+	messageTranslator adapter.InboundChannelMessageTranslator[*Record],
+) *inboundChannelAdapter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &inboundChannelAdapter{
+		broker:            broker,
+		topicName:         topicName,
+		messageTranslator: messageTranslator,
+		ctx:               ctx,
+		cancelCtx:         cancel,
+	}
+}
+
+// Name returns the topic name of the inbound channel adapter.
+//
+// Returns:
+//   - string: the topic name
+func (a *inboundChannelAdapter) Name() string {
+	return a.topicName
+}
+
+// Receive blocks until a message is available in the broker topic.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - *message.Message: the received message
+//   - error: error if the channel is closed or the message cannot be translated
+func (a *inboundChannelAdapter) Receive(ctx context.Context) (*message.Message, error) {
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(a.ctx, cancel)
+	defer stop()
+
+	record, err := a.broker.receive(receiveCtx, a.topicName)
+	if err != nil {
+		if a.ctx.Err() != nil {
+			return nil, a.ctx.Err()
+		}
+		return nil, err
+	}
+
+	msg, err := a.messageTranslator.ToMessage(record)
+	if err != nil {
+		a.broker.settle(record, false)
+		return nil, fmt.Errorf("%w: %w", message.ErrTranslation, err)
+	}
+	return msg, nil
+}
+
+// Close stops the message consumption of the channel.
+//
+// Returns:
+//   - error: always nil
+func (a *inboundChannelAdapter) Close() error {
+	a.cancelCtx()
+	return nil
+}
+
+// CommitMessage removes the message from the broker, marking it as processed.
+//
+// Parameters:
+//   - msg: the message to commit
+//
+// Returns:
+//   - error: error if the message was not received from the broker
+func (a *inboundChannelAdapter) CommitMessage(msg *message.Message) error {
+	return a.NackMessage(msg, false)
+}
+
+// NackMessage settles the message as not processed, redelivering it when
+// requeue is true and discarding it otherwise.
+//
+// Parameters:
+//   - msg: the message to settle
+//   - requeue: true to redeliver the message, false to discard it
+//
+// Returns:
+//   - error: error if the message was not received from the broker
+func (a *inboundChannelAdapter) NackMessage(msg *message.Message, requeue bool) error {
+	record, ok := msg.GetRawMessage().(*Record)
+	if !ok {
+		return fmt.Errorf("[gomestest-inbound-channel] failed to settle message")
+	}
+	a.broker.settle(record, requeue)
+	return nil
+}
+
+// getConnection returns the in-memory connection registered in the container.
+func getConnection(
+	c container.Container[any, any],
+	connectionReferenceName string,
+) (*Connection, error) {
+	conn, err := container.Resolve[*Connection, any, any](
+		c,
+		adapter.ConnectionKey(connectionReferenceName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[gomestest-channel] connection %s is not a valid in-memory connection: %w",
+			connectionReferenceName,
+			err,
+		)
+	}
+	return conn, nil
+}
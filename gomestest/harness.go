@@ -0,0 +1,156 @@
+package gomestest
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// defaultAwaitTimeout is how long AwaitProcessed waits for a message by
+// default.
+const defaultAwaitTimeout = 5 * time.Second
+
+// Harness wires an in-memory broker in the message system and provides the
+// helpers to drive and inspect it from integration tests.
+type Harness struct {
+	connection   *Connection
+	translator   *MessageTranslator
+	awaitTimeout time.Duration
+}
+
+// NewHarness creates a new test harness over a new in-memory broker. The
+// connection returned by Connection must be registered in the message system
+// with gomes.AddChannelConnection.
+//
+// Parameters:
+//   - connectionName: the reference name of the in-memory connection
+//
+// Returns:
+//   - *Harness: the harness instance
+func NewHarness(connectionName string) *Harness {
+	return &Harness{
+		connection:   NewConnection(connectionName, NewBroker()),
+		translator:   NewMessageTranslator(),
+		awaitTimeout: defaultAwaitTimeout,
+	}
+}
+
+// WithAwaitTimeout sets how long AwaitProcessed waits for a message.
+//
+// Parameters:
+//   - timeout: the await timeout
+//
+// Returns:
+//   - *Harness: harness instance for chaining
+func (h *Harness) WithAwaitTimeout(timeout time.Duration) *Harness {
+	h.awaitTimeout = timeout
+	return h
+}
+
+// Connection returns the in-memory connection of the harness.
+//
+// Returns:
+//   - *Connection: the connection instance
+func (h *Harness) Connection() *Connection {
+	return h.connection
+}
+
+// Broker returns the in-memory broker of the harness.
+//
+// Returns:
+//   - *Broker: the broker instance
+func (h *Harness) Broker() *Broker {
+	return h.connection.broker
+}
+
+// PublisherChannel creates a publisher channel builder over a topic of the
+// harness broker.
+//
+// Parameters:
+//   - topicName: the topic to publish messages to
+//
+// Returns:
+//   - *publisherChannelAdapterBuilder: configured builder instance
+func (h *Harness) PublisherChannel(topicName string) *publisherChannelAdapterBuilder {
+	return NewPublisherChannelAdapterBuilder(h.connection.name, topicName)
+}
+
+// ConsumerChannel creates a consumer channel builder over a topic of the
+// harness broker.
+//
+// Parameters:
+//   - topicName: the topic to consume messages from
+//   - consumerName: the consumer name identifier
+//
+// Returns:
+//   - *consumerChannelAdapterBuilder: configured builder instance
+func (h *Harness) ConsumerChannel(
+	topicName string,
+	consumerName string,
+) *consumerChannelAdapterBuilder {
+	return NewConsumerChannelAdapterBuilder(h.connection.name, topicName, consumerName)
+}
+
+// PublishRaw publishes a raw payload to a topic, as an external producer
+// would. A message id is generated when the headers have none.
+//
+// Parameters:
+//   - topicName: the topic name
+//   - payload: the JSON encoded message payload
+//   - headers: the message headers, e.g. route and messageType
+//
+// Returns:
+//   - string: the message id of the published message
+func (h *Harness) PublishRaw(
+	topicName string,
+	payload []byte,
+	headers map[string]string,
+) string {
+	recordHeaders := map[string]string{}
+	for key, value := range headers {
+		recordHeaders[key] = value
+	}
+	if recordHeaders[message.HeaderMessageId] == "" {
+		recordHeaders[message.HeaderMessageId] = uuid.NewString()
+	}
+	h.connection.broker.Publish(topicName, recordHeaders, payload)
+	return recordHeaders[message.HeaderMessageId]
+}
+
+// AwaitProcessed blocks until the message with the given id is settled by a
+// consumer, either processed or sent to its dead letter channel, up to the
+// harness await timeout.
+//
+// Parameters:
+//   - messageId: the message id
+//
+// Returns:
+//   - error: error if the message is not settled in time
+func (h *Harness) AwaitProcessed(messageId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.awaitTimeout)
+	defer cancel()
+	return h.connection.broker.AwaitSettled(ctx, messageId)
+}
+
+// SentMessages returns the messages published to a topic by publisher
+// channels, in publishing order, with their payload JSON encoded.
+//
+// Parameters:
+//   - topicName: the topic name
+//
+// Returns:
+//   - []*message.Message: the published messages
+func (h *Harness) SentMessages(topicName string) []*message.Message {
+	records := h.connection.broker.Sent(topicName)
+	messages := make([]*message.Message, 0, len(records))
+	for _, record := range records {
+		msg, err := h.translator.ToMessage(record)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
@@ -0,0 +1,140 @@
+package gomestest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes"
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/gomestest"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+)
+
+type createOrder struct {
+	Id     string `json:"id"`
+	Reject bool   `json:"reject"`
+}
+
+func (c *createOrder) Name() string { return "create.order" }
+
+type createOrderHandler struct {
+	handled chan string
+}
+
+func (h *createOrderHandler) Handle(_ context.Context, cmd *createOrder) (any, error) {
+	if cmd.Reject {
+		return nil, errors.New("order rejected")
+	}
+	h.handled <- cmd.Id
+	return nil, nil
+}
+
+func TestHarness(t *testing.T) {
+	harness := gomestest.NewHarness("memory").WithAwaitTimeout(2 * time.Second)
+	consumerChannel := harness.ConsumerChannel("orders", "orders-consumer")
+	consumerChannel.WithDeadLetterChannelName("orders.dlq")
+	orderHandler := &createOrderHandler{handled: make(chan string, 1)}
+
+	for _, err := range []error{
+		gomes.AddChannelConnection(harness.Connection()),
+		gomes.AddConsumerChannel(consumerChannel),
+		gomes.AddPublisherChannel(harness.PublisherChannel("orders.dlq")),
+		gomes.AddActionHandler(orderHandler),
+		gomes.Start(),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	t.Cleanup(gomes.Shutdown)
+
+	consumer, err := gomes.EventDrivenConsumer("orders-consumer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go consumer.Run(ctx)
+
+	headers := map[string]string{
+		message.HeaderRoute:       "create.order",
+		message.HeaderMessageType: "Command",
+	}
+
+	t.Run("should process a raw message", func(t *testing.T) {
+		messageId := harness.PublishRaw("orders", []byte(`{"id":"order-1"}`), headers)
+		if err := harness.AwaitProcessed(messageId); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id := <-orderHandler.handled; id != "order-1" {
+			t.Errorf("expected order-1 to be handled, got %s", id)
+		}
+		if len(harness.SentMessages("orders.dlq")) != 0 {
+			t.Error("expected no dead letter")
+		}
+	})
+
+	t.Run("should send a failed message to the dead letter channel", func(t *testing.T) {
+		messageId := harness.PublishRaw("orders", []byte(`{"id":"order-2","reject":true}`), headers)
+		if err := harness.AwaitProcessed(messageId); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		deadLetters := harness.SentMessages("orders.dlq")
+		if len(deadLetters) != 1 {
+			t.Fatalf("expected 1 dead letter, got %d", len(deadLetters))
+		}
+		if reason := deadLetters[0].GetHeader().Get(message.HeaderDeadLetterError); reason != "order rejected" {
+			t.Errorf("unexpected dead letter error: %s", reason)
+		}
+	})
+
+	t.Run("should fail when the message is not processed in time", func(t *testing.T) {
+		err := harness.WithAwaitTimeout(10 * time.Millisecond).AwaitProcessed("unknown")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
+
+func TestInboundChannelAdapter_Redelivery(t *testing.T) {
+	t.Parallel()
+	harness := gomestest.NewHarness("memory")
+	container := container.NewGenericContainer[any, any]()
+	container.Set(adapter.ConnectionKey("memory"), harness.Connection())
+	channel, err := harness.ConsumerChannel("payments", "payments-consumer").Build(container)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer channel.Close()
+
+	messageId := harness.PublishRaw("payments", []byte(`{}`), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	msg, err := channel.ReceiveMessage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := channel.NackMessage(msg, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	redelivered, err := channel.ReceiveMessage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := redelivered.GetHeader().Get(message.HeaderMessageId); got != messageId {
+		t.Errorf("expected message %s to be redelivered, got %s", messageId, got)
+	}
+	if deliveries := harness.Broker().Deliveries(messageId); deliveries != 2 {
+		t.Errorf("expected 2 deliveries, got %d", deliveries)
+	}
+	if err := channel.CommitMessage(redelivered); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if harness.Broker().Pending("payments") != 0 {
+		t.Error("expected no pending message")
+	}
+}
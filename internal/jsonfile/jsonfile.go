@@ -0,0 +1,178 @@
+// Package jsonfile provides the persistence of the file backed stores, which
+// keep their whole state in a single JSON file, or append their changes to a
+// log of JSON lines. Writes are synced to the disk before returning.
+package jsonfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Load decodes the JSON file into the value. A missing or empty file leaves
+// the value unchanged.
+//
+// Parameters:
+//   - path: the JSON file path
+//   - value: pointer receiving the decoded content
+//
+// Returns:
+//   - error: error if the file cannot be read or is invalid
+func Load(path string, value any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, value); err != nil {
+			return fmt.Errorf("invalid file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Save atomically rewrites the JSON file with the value, see WriteFile.
+//
+// Parameters:
+//   - path: the JSON file path, created when missing
+//   - value: the content to be encoded
+//
+// Returns:
+//   - error: error if the value cannot be encoded or the file written
+func Save(path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return WriteFile(path, data)
+}
+
+// WriteFile atomically rewrites the file with the data, writing and syncing
+// a temporary file in the same directory, renaming it over the file and
+// syncing the directory, so the file holds either the previous or the new
+// content after a crash.
+//
+// Parameters:
+//   - path: the file path, created when missing
+//   - data: the new content
+//
+// Returns:
+//   - error: error if the file cannot be written
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return syncDir(path)
+}
+
+// AppendLine appends the value as a JSON line to the file and syncs it, so
+// the line survives a crash once AppendLine returns.
+//
+// Parameters:
+//   - path: the file path, created when missing
+//   - value: the content of the line
+//
+// Returns:
+//   - error: error if the value cannot be encoded or the file written
+func AppendLine(path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	_, statErr := os.Stat(path)
+	created := errors.Is(statErr, os.ErrNotExist)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if created {
+		return syncDir(path)
+	}
+	return nil
+}
+
+// LoadLines decodes the JSON lines of the file, calling decode for each of
+// them in order. A missing file has no line, and an incomplete last line,
+// left by a crash while appending, is ignored.
+//
+// Parameters:
+//   - path: the file path
+//   - decode: function decoding a line
+//
+// Returns:
+//   - error: error if the file cannot be read, a line is invalid or decode
+//     fails
+func LoadLines(path string, decode func(line json.RawMessage) error) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var line json.RawMessage
+		err := decoder.Decode(&line)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid file %s: %w", path, err)
+		}
+		if err := decode(line); err != nil {
+			return fmt.Errorf("invalid file %s: %w", path, err)
+		}
+	}
+}
+
+// syncDir syncs the directory of a file, persisting its creation or rename.
+func syncDir(path string) error {
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("failed to sync the directory of %s: %w", path, err)
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("failed to sync the directory of %s: %w", path, err)
+	}
+	return nil
+}
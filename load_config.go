@@ -0,0 +1,185 @@
+package gomes
+
+import (
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/channel/kafka"
+	"github.com/jeffersonbrasilino/gomes/channel/rabbitmq"
+	"github.com/jeffersonbrasilino/gomes/config"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// LoadConfig loads the topology declared in a YAML configuration file,
+// registering its connections, publisher channels and consumer channels
+// through the existing builders. Environment variables are interpolated with
+// ${VAR} and ${VAR:-default}. It must be called before Start.
+//
+// Parameters:
+//   - path: the path of the YAML configuration file
+//
+// Returns:
+//   - error: error if the configuration is invalid or a component cannot be
+//     registered
+func LoadConfig(path string) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	return ApplyConfig(cfg)
+}
+
+// ApplyConfig registers the topology of a loaded configuration.
+//
+// Parameters:
+//   - cfg: the configuration to register
+//
+// Returns:
+//   - error: error if the configuration is invalid or a component cannot be
+//     registered
+func ApplyConfig(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	for _, connection := range cfg.Connections {
+		var err error
+		switch connection.Driver {
+		case config.DriverKafka:
+			err = AddChannelConnection(
+				kafka.NewConnection(connection.Name, connection.Hosts),
+			)
+		case config.DriverRabbitMQ:
+			err = AddChannelConnection(
+				rabbitmq.NewConnection(connection.Name, connection.Hosts[0]),
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("[gomes] [config] %w", err)
+		}
+	}
+
+	for _, publisher := range cfg.Publishers {
+		if err := addConfiguredPublisher(cfg, publisher); err != nil {
+			return fmt.Errorf("[gomes] [config] %w", err)
+		}
+	}
+
+	for _, consumer := range cfg.Consumers {
+		if err := addConfiguredConsumer(cfg, consumer); err != nil {
+			return fmt.Errorf("[gomes] [config] %w", err)
+		}
+	}
+	return nil
+}
+
+// addConfiguredPublisher registers a publisher channel declared in the
+// configuration.
+func addConfiguredPublisher(
+	cfg *config.Config,
+	publisher config.PublisherConfig,
+) error {
+	switch cfg.Driver(publisher.Connection) {
+	case config.DriverKafka:
+		builder := kafka.NewPublisherChannelAdapterBuilder(
+			publisher.Connection,
+			publisher.Channel,
+		)
+		if publisher.ReplyChannel != "" {
+			builder.WithReplyChannelName(publisher.ReplyChannel)
+		}
+		return AddPublisherChannel(builder)
+	default:
+		builder := rabbitmq.NewPublisherChannelAdapterBuilder(
+			publisher.Connection,
+			publisher.Channel,
+		)
+		if publisher.ReplyChannel != "" {
+			builder.WithReplyChannelName(publisher.ReplyChannel)
+		}
+		if publisher.Exchange != "" {
+			builder.WithChannelType(rabbitmq.ProducerExchange).
+				WithExchangeRoutingKeys(publisher.RoutingKey)
+			switch publisher.Exchange {
+			case "fanout":
+				builder.WithExchangeType(rabbitmq.ExchangeFanout)
+			case "topic":
+				builder.WithExchangeType(rabbitmq.ExchangeTopic)
+			case "headers":
+				builder.WithExchangeType(rabbitmq.ExchangeHeaders)
+			default:
+				builder.WithExchangeType(rabbitmq.ExchangeDirect)
+			}
+		}
+		return AddPublisherChannel(builder)
+	}
+}
+
+// addConfiguredConsumer registers a consumer channel declared in the
+// configuration, with the tuning of its consumers.
+func addConfiguredConsumer(
+	cfg *config.Config,
+	consumer config.ConsumerConfig,
+) error {
+	switch cfg.Driver(consumer.Connection) {
+	case config.DriverKafka:
+		builder := kafka.NewConsumerChannelAdapterBuilder(
+			consumer.Connection,
+			consumer.Channel,
+			consumer.Name,
+		).WithCommitEvery(consumer.CommitEvery).
+			WithCommitInterval(consumer.CommitInterval)
+		configureConsumerChannel(builder.InboundChannelAdapterBuilder, consumer)
+		return AddConsumerChannel(builder)
+	default:
+		builder := rabbitmq.NewConsumerChannelAdapterBuilder(
+			consumer.Connection,
+			consumer.Channel,
+			consumer.Name,
+		)
+		configureConsumerChannel(builder.InboundChannelAdapterBuilder, consumer)
+		return AddConsumerChannel(builder)
+	}
+}
+
+// configureConsumerChannel applies the settings of a configured consumer to
+// its channel builder.
+func configureConsumerChannel[T any](
+	builder *adapter.InboundChannelAdapterBuilder[T],
+	consumer config.ConsumerConfig,
+) {
+	builder.WithDeadLetterChannelName(consumer.DeadLetterChannel)
+	builder.WithRetryTimes(consumer.RetryAttempts...)
+	builder.WithAckMode(configuredAckModes[consumer.AckMode])
+	builder.WithAmountOfProcessors(consumer.Processors)
+	builder.WithProcessingTimeout(consumer.ProcessingTimeout)
+	builder.WithErrorPolicy(configuredErrorPolicy(consumer))
+}
+
+// configuredErrorPolicy returns the error policy of a configured consumer,
+// falling back to the deprecated stopOnError setting.
+func configuredErrorPolicy(consumer config.ConsumerConfig) endpoint.ErrorPolicy {
+	if consumer.ErrorPolicy != "" {
+		return configuredErrorPolicies[consumer.ErrorPolicy]
+	}
+	if consumer.StopOnError != nil && !*consumer.StopOnError {
+		return endpoint.ErrorPolicyContinue
+	}
+	return endpoint.ErrorPolicyStop
+}
+
+// configuredErrorPolicies maps the configured consumer error policies.
+var configuredErrorPolicies = map[string]endpoint.ErrorPolicy{
+	config.ErrorPolicyStop:           endpoint.ErrorPolicyStop,
+	config.ErrorPolicyContinue:       endpoint.ErrorPolicyContinue,
+	config.ErrorPolicyDLQAndContinue: endpoint.ErrorPolicyDLQAndContinue,
+	config.ErrorPolicyPauseAndAlert:  endpoint.ErrorPolicyPauseAndAlert,
+}
+
+// configuredAckModes maps the configured acknowledgment modes.
+var configuredAckModes = map[string]handler.AckMode{
+	config.AckModeAuto:      handler.AckAuto,
+	config.AckModeOnSuccess: handler.AckOnSuccess,
+	config.AckModeManual:    handler.AckManual,
+}
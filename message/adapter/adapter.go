@@ -1,6 +1,24 @@
 package adapter
 
-import "github.com/jeffersonbrasilino/gomes/message"
+import (
+	"context"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/otel"
+)
+
+// ConnectionKey returns the key a channel connection is registered with in
+// the gomes container, apart from the channels and handlers.
+//
+// Parameters:
+//   - referenceName: the reference name of the connection
+//
+// Returns:
+//   - string: the namespaced key of the connection
+func ConnectionKey(referenceName string) string {
+	return container.NamespacedKey("connection", referenceName)
+}
 
 // ChannelConnection defines the contract for managing channel connections
 // with connect and disconnect capabilities.
@@ -14,6 +32,76 @@ type ClosableChannel interface {
 	Close() error
 }
 
+// BacklogChannel defines the contract for consumer channels able to report
+// how many messages wait to be consumed, e.g. the Kafka consumer lag or the
+// RabbitMQ queue depth.
+type BacklogChannel interface {
+	// Backlog returns the messages waiting to be consumed.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//
+	// Returns:
+	//   - int64: The amount of messages waiting to be consumed
+	//   - error: Error if the broker cannot be inspected
+	Backlog(ctx context.Context) (int64, error)
+	// MessagingSystem returns the messaging system of the channel.
+	//
+	// Returns:
+	//   - otel.MessageSystemType: The messaging system
+	MessagingSystem() otel.MessageSystemType
+}
+
+// FailingChannel defines the contract for consumer channels reporting the
+// terminal failure which stopped their consumption, e.g. a consumer cancelled
+// by the broker, as opposed to transient receive errors.
+type FailingChannel interface {
+	// Err returns the terminal failure of the channel.
+	//
+	// Returns:
+	//   - error: The terminal failure, nil while consuming or after Close
+	Err() error
+}
+
+// SeekableChannel defines the contract for consumer channels able to restart
+// the consumption at a position of their source, e.g. the version of an event
+// stream, as required by the projection runners.
+type SeekableChannel interface {
+	// SeekTo restarts the consumption at a position, the next message received
+	// being the first one at or after it.
+	//
+	// Parameters:
+	//   - position: The position of the next message to receive
+	//
+	// Returns:
+	//   - error: Error if the channel cannot be repositioned
+	SeekTo(position int64) error
+	// PositionOf returns the position of a message received by the channel.
+	//
+	// Parameters:
+	//   - msg: The received message
+	//
+	// Returns:
+	//   - int64: The position of the message in the source
+	//   - error: Error if the message was not received by the channel
+	PositionOf(msg *message.Message) (int64, error)
+}
+
+// IdentifiableChannel defines the contract for consumer channels able to
+// identify a message by its place in their source, e.g. the topic, partition
+// and offset of a Kafka message, the same on every delivery of the message.
+type IdentifiableChannel interface {
+	// SourceIdOf returns the identity of a message received by the channel.
+	//
+	// Parameters:
+	//   - msg: The received message
+	//
+	// Returns:
+	//   - string: The identity of the message in the source
+	//   - error: Error if the message was not received by the channel
+	SourceIdOf(msg *message.Message) (string, error)
+}
+
 // InboundChannelMessageTranslator defines the contract for translating external messages
 // to the internal format.
 //
@@ -0,0 +1,351 @@
+// Package adaptertest provides conformance test suites for custom channel
+// adapters, so new broker integrations can verify they satisfy the
+// expectations of the message system.
+//
+// The suites implementation supports:
+// - Header and payload translation round trips
+// - Receive cancellation through the context
+// - Commit and negative acknowledgment with redelivery
+// - Close releasing the pending receivers
+// - Reply metadata propagation
+package adaptertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// defaultTimeout bounds every broker operation of the suites.
+const defaultTimeout = 5 * time.Second
+
+// InboundFixture is a consumer channel under test with the means to produce
+// messages to the broker it consumes from.
+type InboundFixture struct {
+	// Channel is the consumer channel under test.
+	Channel message.ConsumerChannel
+	// Produce delivers a message to the broker consumed by Channel, as an
+	// external producer would.
+	Produce func(ctx context.Context, msg *message.Message) error
+	// Timeout bounds every broker operation, defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// OutboundFixture is a publisher channel under test with the means to read
+// back the messages it published.
+type OutboundFixture struct {
+	// Channel is the publisher channel under test.
+	Channel message.PublisherChannel
+	// Consume reads the next message published by Channel from the broker.
+	Consume func(ctx context.Context) (*message.Message, error)
+	// Timeout bounds every broker operation, defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// samplePayload is the payload of the messages used by the suites.
+type samplePayload struct {
+	Id    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+// RunInboundSuite runs the conformance suite of consumer channels. The
+// factory is called for every test with a fresh fixture; channels are closed
+// by the suite.
+//
+// Parameters:
+//   - t: the test
+//   - factory: creates the fixture of a test
+func RunInboundSuite(t *testing.T, factory func(t *testing.T) *InboundFixture) {
+	t.Run("should have a name", func(t *testing.T) {
+		fixture := newInboundFixture(t, factory)
+		if fixture.Channel.Name() == "" {
+			t.Error("expected channel name")
+		}
+	})
+
+	t.Run("should translate headers and payload", func(t *testing.T) {
+		fixture := newInboundFixture(t, factory)
+		sent := newSampleMessage("translate")
+		ctx := fixture.context(t)
+		if err := fixture.Produce(ctx, sent); err != nil {
+			t.Fatalf("produce failed: %v", err)
+		}
+
+		received, err := fixture.Channel.Receive(ctx)
+		if err != nil {
+			t.Fatalf("receive failed: %v", err)
+		}
+		assertSameMessage(t, sent, received)
+	})
+
+	t.Run("should stop receiving when the context is done", func(t *testing.T) {
+		fixture := newInboundFixture(t, factory)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		result := make(chan error, 1)
+		go func() {
+			_, err := fixture.Channel.Receive(ctx)
+			result <- err
+		}()
+		select {
+		case err := <-result:
+			if err == nil {
+				t.Error("expected error receiving without messages")
+			}
+		case <-time.After(fixture.Timeout):
+			t.Error("receive did not return when the context was done")
+		}
+	})
+
+	t.Run("should commit received messages", func(t *testing.T) {
+		fixture := newInboundFixture(t, factory)
+		ackChannel, ok := fixture.Channel.(handler.ChannelMessageAcknowledgment)
+		if !ok {
+			t.Skip("channel does not acknowledge messages")
+		}
+		ctx := fixture.context(t)
+		if err := fixture.Produce(ctx, newSampleMessage("commit")); err != nil {
+			t.Fatalf("produce failed: %v", err)
+		}
+		received, err := fixture.Channel.Receive(ctx)
+		if err != nil {
+			t.Fatalf("receive failed: %v", err)
+		}
+		if err := ackChannel.CommitMessage(received); err != nil {
+			t.Errorf("commit failed: %v", err)
+		}
+	})
+
+	t.Run("should redeliver messages negatively acknowledged with requeue", func(t *testing.T) {
+		fixture := newInboundFixture(t, factory)
+		nackChannel, ok := fixture.Channel.(handler.ChannelMessageNegativeAcknowledgment)
+		if !ok {
+			t.Skip("channel does not negatively acknowledge messages")
+		}
+		ctx := fixture.context(t)
+		sent := newSampleMessage("redeliver")
+		if err := fixture.Produce(ctx, sent); err != nil {
+			t.Fatalf("produce failed: %v", err)
+		}
+		received, err := fixture.Channel.Receive(ctx)
+		if err != nil {
+			t.Fatalf("receive failed: %v", err)
+		}
+		if err := nackChannel.NackMessage(received, true); err != nil {
+			t.Fatalf("nack failed: %v", err)
+		}
+
+		redelivered, err := fixture.Channel.Receive(ctx)
+		if err != nil {
+			t.Fatalf("receive of the redelivery failed: %v", err)
+		}
+		assertSameMessage(t, sent, redelivered)
+	})
+
+	t.Run("should release pending receivers on close", func(t *testing.T) {
+		fixture := factory(t)
+		result := make(chan error, 1)
+		go func() {
+			_, err := fixture.Channel.Receive(context.Background())
+			result <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+		if err := fixture.Channel.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		select {
+		case err := <-result:
+			if err == nil {
+				t.Error("expected error receiving from a closed channel")
+			}
+		case <-time.After(timeoutOf(fixture.Timeout)):
+			t.Error("receive did not return when the channel was closed")
+		}
+	})
+}
+
+// RunOutboundSuite runs the conformance suite of publisher channels. The
+// factory is called for every test with a fresh fixture.
+//
+// Parameters:
+//   - t: the test
+//   - factory: creates the fixture of a test
+func RunOutboundSuite(t *testing.T, factory func(t *testing.T) *OutboundFixture) {
+	t.Run("should have a name", func(t *testing.T) {
+		fixture := newOutboundFixture(t, factory)
+		if fixture.Channel.Name() == "" {
+			t.Error("expected channel name")
+		}
+	})
+
+	t.Run("should translate headers and payload", func(t *testing.T) {
+		fixture := newOutboundFixture(t, factory)
+		sent := newSampleMessage("publish")
+		ctx := fixture.context(t)
+		if err := fixture.Channel.Send(ctx, sent); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+
+		published, err := fixture.Consume(ctx)
+		if err != nil {
+			t.Fatalf("consume failed: %v", err)
+		}
+		assertSameMessage(t, sent, published)
+	})
+
+	t.Run("should propagate the reply metadata", func(t *testing.T) {
+		fixture := newOutboundFixture(t, factory)
+		sent := message.NewMessageBuilderFromMessage(newSampleMessage("reply")).
+			WithReplyTo("replies").
+			Build()
+		ctx := fixture.context(t)
+		if err := fixture.Channel.Send(ctx, sent); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+
+		published, err := fixture.Consume(ctx)
+		if err != nil {
+			t.Fatalf("consume failed: %v", err)
+		}
+		for _, key := range []string{message.HeaderReplyTo, message.HeaderCorrelationId} {
+			if published.GetHeader().Get(key) != sent.GetHeader().Get(key) {
+				t.Errorf("expected %s header %q, got %q",
+					key, sent.GetHeader().Get(key), published.GetHeader().Get(key))
+			}
+		}
+	})
+
+	t.Run("should preserve the publishing order", func(t *testing.T) {
+		fixture := newOutboundFixture(t, factory)
+		ctx := fixture.context(t)
+		ids := []string{"first", "second", "third"}
+		for _, id := range ids {
+			if err := fixture.Channel.Send(ctx, newSampleMessage(id)); err != nil {
+				t.Fatalf("send failed: %v", err)
+			}
+		}
+		for _, id := range ids {
+			published, err := fixture.Consume(ctx)
+			if err != nil {
+				t.Fatalf("consume failed: %v", err)
+			}
+			if got := published.GetHeader().Get(message.HeaderMessageId); got != id {
+				t.Errorf("expected message %s, got %s", id, got)
+			}
+		}
+	})
+}
+
+// newInboundFixture creates an inbound fixture closing its channel when the
+// test ends.
+func newInboundFixture(
+	t *testing.T,
+	factory func(t *testing.T) *InboundFixture,
+) *InboundFixture {
+	t.Helper()
+	fixture := factory(t)
+	fixture.Timeout = timeoutOf(fixture.Timeout)
+	t.Cleanup(func() { fixture.Channel.Close() })
+	return fixture
+}
+
+// newOutboundFixture creates an outbound fixture.
+func newOutboundFixture(
+	t *testing.T,
+	factory func(t *testing.T) *OutboundFixture,
+) *OutboundFixture {
+	t.Helper()
+	fixture := factory(t)
+	fixture.Timeout = timeoutOf(fixture.Timeout)
+	return fixture
+}
+
+// context returns a context bounded by the fixture timeout.
+func (f *InboundFixture) context(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), f.Timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// context returns a context bounded by the fixture timeout.
+func (f *OutboundFixture) context(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), f.Timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// timeoutOf returns the timeout, or the default timeout when not set.
+func timeoutOf(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return defaultTimeout
+	}
+	return timeout
+}
+
+// newSampleMessage creates a command with the metadata checked by the suites.
+func newSampleMessage(id string) *message.Message {
+	return message.NewMessageBuilder().
+		WithMessageType(message.Command).
+		WithMessageId(id).
+		WithCorrelationId("correlation-"+id).
+		WithRoute("adaptertest.sample").
+		WithChannelName("adaptertest").
+		WithCustomHeader(message.HeaderTenantId, "adaptertest").
+		WithPayload(&samplePayload{Id: id, Total: 42}).
+		Build()
+}
+
+// assertSameMessage checks that the received message carries the metadata
+// and payload of the sent one.
+func assertSameMessage(t *testing.T, sent, received *message.Message) {
+	t.Helper()
+	if received == nil {
+		t.Fatal("expected message, got nil")
+	}
+	for _, key := range []string{
+		message.HeaderMessageId,
+		message.HeaderCorrelationId,
+		message.HeaderRoute,
+		message.HeaderMessageType,
+		message.HeaderTenantId,
+	} {
+		if received.GetHeader().Get(key) != sent.GetHeader().Get(key) {
+			t.Errorf("expected %s header %q, got %q",
+				key, sent.GetHeader().Get(key), received.GetHeader().Get(key))
+		}
+	}
+
+	expected, err := encodePayload(sent.GetPayload())
+	if err != nil {
+		t.Fatalf("cannot encode sent payload: %v", err)
+	}
+	actual, err := encodePayload(received.GetPayload())
+	if err != nil {
+		t.Fatalf("cannot encode received payload: %v", err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("expected payload %s, got %s", expected, actual)
+	}
+}
+
+// encodePayload returns the compact JSON encoding of a payload; payloads
+// received as bytes are expected to be JSON already.
+func encodePayload(payload any) ([]byte, error) {
+	raw, ok := payload.([]byte)
+	if !ok {
+		return json.Marshal(payload)
+	}
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, raw); err != nil {
+		return nil, fmt.Errorf("payload is not JSON: %w", err)
+	}
+	return compacted.Bytes(), nil
+}
@@ -0,0 +1,41 @@
+package adaptertest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/gomestest"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter/adaptertest"
+)
+
+func TestRunInboundSuite(t *testing.T) {
+	adaptertest.RunInboundSuite(t, func(t *testing.T) *adaptertest.InboundFixture {
+		broker := gomestest.NewBroker()
+		translator := gomestest.NewMessageTranslator()
+		return &adaptertest.InboundFixture{
+			Channel: gomestest.NewInboundChannelAdapter(broker, "inbound", translator),
+			Produce: func(_ context.Context, msg *message.Message) error {
+				record, err := translator.FromMessage(msg)
+				if err != nil {
+					return err
+				}
+				broker.Publish("inbound", record.Headers, record.Payload)
+				return nil
+			},
+		}
+	})
+}
+
+func TestRunOutboundSuite(t *testing.T) {
+	adaptertest.RunOutboundSuite(t, func(t *testing.T) *adaptertest.OutboundFixture {
+		broker := gomestest.NewBroker()
+		translator := gomestest.NewMessageTranslator()
+		consumer := gomestest.NewInboundChannelAdapter(broker, "outbound", translator)
+		t.Cleanup(func() { consumer.Close() })
+		return &adaptertest.OutboundFixture{
+			Channel: gomestest.NewOutboundChannelAdapter(broker, "outbound", translator),
+			Consume: consumer.Receive,
+		}
+	})
+}
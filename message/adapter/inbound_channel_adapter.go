@@ -14,9 +14,15 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"maps"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
+	"github.com/jeffersonbrasilino/gomes/message/router"
+	"github.com/jeffersonbrasilino/gomes/otel"
 )
 
 // InboundChannelAdapterBuilder provides a fluent interface for configuring
@@ -29,10 +35,44 @@ type InboundChannelAdapterBuilder[TMessageType any] struct {
 	messageTranslator     InboundChannelMessageTranslator[TMessageType]
 	referenceName         string
 	deadLetterChannelName string
+	deadLetterRoutes      map[string]string
+	unroutableChannelName string
+	quarantineChannelName string
+	quarantineMaxFailures int
+	poisonMessageKey      handler.PoisonMessageKeyExtractor
 	beforeProcessors      []message.MessageHandler
 	afterProcessors       []message.MessageHandler
 	retryTimeAttempts     []int
+	retryPolicy           handler.RetryPolicy
+	circuitBreaker        *handler.CircuitBreakerConfig
+	ackMode               handler.AckMode
+	deduplicationStore    handler.DeduplicationStore
+	deduplicationTTL      time.Duration
+	claimCheckStore       handler.BlobStore
+	keyProvider           handler.KeyProvider
+	jsonEncoder           *message.JSONEncoder
+	backlogInterval       time.Duration
+	logBacklog            bool
 	sendReplyUsingReplyTo bool
+	responseChannelName   string
+	wireTapChannelName    string
+	wireTapSampling       float64
+	filter                router.FilterFunc
+	discardChannelName    string
+	messageRouter         router.ContainerRouter
+	transformers          []handler.Transformer
+	topicBeforeProcessors []message.MessageHandler
+	topicAfterProcessors  []message.MessageHandler
+	upcasters             []handler.Upcaster
+	tenantValidation      message.MessageHandler
+	guards                message.MessageHandler
+	orderingKey           func(*message.Message) string
+	amountOfProcessors    int
+	processingTimeout     time.Duration
+	errorPolicy           endpoint.ErrorPolicy
+	replyChannelFactory   handler.ReplyChannelFactory
+	allowedReplyChannels  []string
+	atomicReply           bool
 }
 
 // InboundChannelAdapter handles the reception, processing, and forwarding of messages
@@ -41,10 +81,36 @@ type InboundChannelAdapter struct {
 	inboundAdapter        message.ConsumerChannel
 	referenceName         string
 	deadLetterChannelName string
+	deadLetterRoutes      map[string]string
+	unroutableChannelName string
+	quarantineChannelName string
+	quarantineMaxFailures int
+	poisonMessageKey      handler.PoisonMessageKeyExtractor
 	beforeProcessors      []message.MessageHandler
 	afterProcessors       []message.MessageHandler
 	retryTimeAttempts     []int
+	retryPolicy           handler.RetryPolicy
+	circuitBreaker        *handler.CircuitBreaker
+	ackMode               handler.AckMode
+	deduplicationStore    handler.DeduplicationStore
+	deduplicationTTL      time.Duration
+	backlogInterval       time.Duration
+	logBacklog            bool
+	otelMetrics           otel.OtelMetrics
 	sendReplyUsingReplyTo bool
+	responseChannelName   string
+	wireTapChannelName    string
+	wireTapSampling       float64
+	filter                router.FilterFunc
+	discardChannelName    string
+	messageRouter         router.ContainerRouter
+	orderingKey           func(*message.Message) string
+	amountOfProcessors    int
+	processingTimeout     time.Duration
+	errorPolicy           endpoint.ErrorPolicy
+	replyChannelFactory   handler.ReplyChannelFactory
+	allowedReplyChannels  []string
+	atomicReply           bool
 }
 
 // NewInboundChannelAdapterBuilder creates a new builder instance for configuring
@@ -68,6 +134,7 @@ func NewInboundChannelAdapterBuilder[T any](
 		referenceName:     referenceName,
 		beforeProcessors:  []message.MessageHandler{},
 		afterProcessors:   []message.MessageHandler{},
+		wireTapSampling:   100,
 	}
 }
 
@@ -81,6 +148,83 @@ func (b *InboundChannelAdapterBuilder[TMessageType]) WithDeadLetterChannelName(
 	b.deadLetterChannelName = value
 }
 
+// WithDeadLetterForRoute sends the failed messages of a route to their own
+// dead letter channel instead of the channel set by WithDeadLetterChannelName,
+// so the failures of unrelated actions sharing the consumer channel are kept
+// apart. Routes without a mapping use the dead letter channel of the consumer.
+//
+// Parameters:
+//   - route: The route (action or event name) of the failed messages
+//   - channelName: The dead letter channel name of the route
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithDeadLetterForRoute(
+	route string,
+	channelName string,
+) {
+	if b.deadLetterRoutes == nil {
+		b.deadLetterRoutes = map[string]string{}
+	}
+	b.deadLetterRoutes[route] = channelName
+}
+
+// WithUnroutableChannelName enables strict routing: messages whose route has
+// no registered handler are treated as errors and sent to the given channel.
+//
+// Parameters:
+//   - value: The unroutable channel name to set
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithUnroutableChannelName(
+	value string,
+) {
+	b.unroutableChannelName = value
+}
+
+// WithPoisonMessageQuarantine enables poison message detection: messages
+// failing maxFailures consecutive times across redeliveries are sent to the
+// quarantine channel instead of being processed again. With a dead letter
+// channel, only the failures the dead letter channel could not handle are
+// counted. maxFailures must be positive, otherwise the build fails.
+//
+// Parameters:
+//   - channelName: The quarantine channel name
+//   - maxFailures: Consecutive failures after which a message is quarantined
+//   - keyExtractor: Optional message identification, defaults to the message id
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithPoisonMessageQuarantine(
+	channelName string,
+	maxFailures int,
+	keyExtractor ...handler.PoisonMessageKeyExtractor,
+) {
+	b.quarantineChannelName = channelName
+	b.quarantineMaxFailures = maxFailures
+	if len(keyExtractor) > 0 {
+		b.poisonMessageKey = keyExtractor[0]
+	}
+}
+
+// WithCircuitBreaker processes the received messages through a circuit
+// breaker: once the failure rate exceeds the threshold, the circuit opens and
+// consumption is paused, or messages are sent to the parking channel, for the
+// cool-down period before trial messages are processed.
+//
+// Parameters:
+//   - config: The circuit breaker configuration
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithCircuitBreaker(
+	config handler.CircuitBreakerConfig,
+) {
+	b.circuitBreaker = &config
+}
+
+// WithAckMode sets when the received messages are acknowledged. With
+// handler.AckOnSuccess, failed messages are not committed, so the broker
+// redelivers them instead of dropping them when no dead letter channel is
+// configured.
+//
+// Parameters:
+//   - mode: The acknowledgment mode, handler.AckAuto by default
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithAckMode(
+	mode handler.AckMode,
+) {
+	b.ackMode = mode
+}
+
 // WithBeforeInterceptors sets the before processing interceptors for the adapter builder.
 //
 // Parameters:
@@ -101,12 +245,238 @@ func (b *InboundChannelAdapterBuilder[TMessageType]) WithAfterInterceptors(
 	b.afterProcessors = processors
 }
 
+// WithTopicBeforeInterceptors sets before processing interceptors applied
+// only to the messages received from a topic, for channels consuming
+// multiple topics. They run after the channel before interceptors.
+//
+// Parameters:
+//   - topic: The source topic of the intercepted messages
+//   - processors: Variable number of message handlers to execute before processing
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithTopicBeforeInterceptors(
+	topic string,
+	processors ...message.MessageHandler,
+) {
+	for _, processor := range processors {
+		b.topicBeforeProcessors = append(
+			b.topicBeforeProcessors,
+			handler.NewSourceTopicInterceptor(topic, processor),
+		)
+	}
+}
+
+// WithTopicAfterInterceptors sets after processing interceptors applied only
+// to the messages received from a topic, for channels consuming multiple
+// topics. They run after the channel after interceptors.
+//
+// Parameters:
+//   - topic: The source topic of the intercepted messages
+//   - processors: Variable number of message handlers to execute after processing
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithTopicAfterInterceptors(
+	topic string,
+	processors ...message.MessageHandler,
+) {
+	for _, processor := range processors {
+		b.topicAfterProcessors = append(
+			b.topicAfterProcessors,
+			handler.NewSourceTopicInterceptor(topic, processor),
+		)
+	}
+}
+
+// WithWireTap asynchronously copies every received message, before it is
+// processed, to the given publisher channel for audit or inspection.
+//
+// Parameters:
+//   - channelName: The wire tap channel name
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithWireTap(
+	channelName string,
+) {
+	b.wireTapChannelName = channelName
+}
+
+// WithWireTapSampling sets the percentage of the received messages copied to
+// the wire tap channel.
+//
+// Parameters:
+//   - percentage: The sampled percentage, from 0 to 100, 100 by default
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithWireTapSampling(
+	percentage float64,
+) {
+	b.wireTapSampling = percentage
+}
+
+// WithTransformer registers a transformer converting the received messages
+// of a route after their translation and before they are dispatched to the
+// handlers, e.g. normalizing legacy payload shapes to the canonical schema.
+// Transformers run in registration order, after the before interceptors.
+//
+// Parameters:
+//   - route: The route of the transformed messages, empty for every message
+//   - transform: The transformation function
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithTransformer(
+	route string,
+	transform handler.TransformFunc,
+) {
+	b.transformers = append(b.transformers, handler.Transformer{
+		Route:     route,
+		Transform: transform,
+	})
+}
+
+// WithFilter drops the received messages failing the filter before they
+// reach the action handlers, e.g. events older than a given age or from
+// certain origins. The filter runs after the before interceptors, so it sees
+// the decoded payload. Dropped messages are acknowledged and, when a discard
+// channel is given, sent to it.
+//
+// Parameters:
+//   - filter: The predicate returning true for the messages to process
+//   - discardChannelName: Optional publisher channel receiving the dropped
+//     messages
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithFilter(
+	filter router.FilterFunc,
+	discardChannelName ...string,
+) {
+	b.filter = filter
+	if len(discardChannelName) > 0 {
+		b.discardChannelName = discardChannelName[0]
+	}
+}
+
+// WithRouter routes the received messages through the router, e.g. a
+// dynamic router, to its destination channels instead of dispatching them to
+// the action handlers. The router runs after the before interceptors and the
+// filter, and the messages are acknowledged once routed.
+//
+// Parameters:
+//   - messageRouter: The router of the received messages
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithRouter(
+	messageRouter router.ContainerRouter,
+) {
+	b.messageRouter = messageRouter
+}
+
+// WithTenantValidation rejects the received messages without tenant or whose
+// tenant fails the validator, before any other interceptor. Rejected messages
+// fail with an error wrapping message.ErrInvalidTenant, so they are sent to
+// the dead letter channel when configured.
+//
+// Parameters:
+//   - validate: The tenant validator, e.g. handler.AllowedTenants, or nil to
+//     only require a tenant
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithTenantValidation(
+	validate handler.TenantValidator,
+) {
+	b.tenantValidation = handler.NewTenantValidationInterceptor(validate)
+}
+
+// WithGuards rejects the received messages violating the guards (maximum
+// payload size, required headers and allowed routes) before any other
+// interceptor, so they never reach the action handlers. Rejected messages
+// fail with a permanent error wrapping a message.GuardError, so they are not
+// retried and are sent to the dead letter channel, when configured, with the
+// violated guard in the dlqGuardFailure header.
+//
+// Parameters:
+//   - guards: The guards of the channel
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithGuards(
+	guards handler.MessageGuards,
+) {
+	b.guards = handler.NewMessageGuardInterceptor(guards)
+}
+
 // WithSendReplyUsingReplyTo enables reply-to functionality for the adapter builder.
 func (b *InboundChannelAdapterBuilder[TMessageType]) WithSendReplyUsingReplyTo() {
 	b.sendReplyUsingReplyTo = true
 
 }
 
+// WithResponseChannelName publishes the results of the handlers to the given
+// channel when the received messages have no reply-to header, e.g. to report
+// the outcome of asynchronous commands. Messages with a reply-to header are
+// still answered on their own reply channel.
+//
+// Parameters:
+//   - channelName: The name of the response publisher channel
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithResponseChannelName(
+	channelName string,
+) {
+	b.responseChannelName = channelName
+}
+
+// WithReplyChannelFactory creates, on first use, the publisher channels of
+// reply-to destinations with no registered publisher channel, e.g. the
+// private reply queue of a requester. Only destinations matching one of the
+// allowed patterns are created.
+//
+// Parameters:
+//   - factory: creates the publisher channel of a reply destination
+//   - allowedPatterns: path.Match patterns of the destinations allowed to be
+//     created, e.g. "replies.*"
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithReplyChannelFactory(
+	factory handler.ReplyChannelFactory,
+	allowedPatterns ...string,
+) {
+	b.replyChannelFactory = factory
+	b.allowedReplyChannels = allowedPatterns
+}
+
+// WithAtomicReply publishes the replies of the handlers before acknowledging
+// the received messages: a message whose reply fails to be published is
+// requeued instead of acknowledged, and the replies carry an id derived from
+// the id of their request, so the replies duplicated by a redelivery can be
+// discarded by the requester.
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithAtomicReply() {
+	b.atomicReply = true
+}
+
+// WithOrderingKey keeps the processing order of the received messages sharing
+// an ordering key, e.g. the broker partition they were read from: the
+// consumers of the channel hash the messages by their key to a fixed
+// processor. An ordering key set on the consumer takes precedence.
+//
+// Parameters:
+//   - key: extracts the ordering key of a message
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithOrderingKey(
+	key func(*message.Message) string,
+) {
+	b.orderingKey = key
+}
+
+// WithAmountOfProcessors sets the amount of concurrent processors of the
+// consumers of the channel. An amount set on the consumer takes precedence.
+//
+// Parameters:
+//   - amount: The amount of concurrent processors
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithAmountOfProcessors(
+	amount int,
+) {
+	b.amountOfProcessors = amount
+}
+
+// WithProcessingTimeout sets the message processing timeout of the consumers
+// of the channel. A timeout set on the consumer takes precedence.
+//
+// Parameters:
+//   - timeout: The message processing timeout
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithProcessingTimeout(
+	timeout time.Duration,
+) {
+	b.processingTimeout = timeout
+}
+
+// WithErrorPolicy sets how the consumers of the channel react to receive and
+// processing errors, ErrorPolicyStop by default. A policy set on the consumer
+// takes precedence.
+//
+// Parameters:
+//   - policy: The error policy of the consumers
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithErrorPolicy(
+	policy endpoint.ErrorPolicy,
+) {
+	b.errorPolicy = policy
+}
+
 // ReferenceName returns the current reference(ChannelName) name of the builder.
 //
 // Returns:
@@ -115,6 +485,74 @@ func (b *InboundChannelAdapterBuilder[TMessageType]) ReferenceName() string {
 	return b.channelName
 }
 
+// DeadLetterChannelName returns the dead letter channel name of the builder.
+//
+// Returns:
+//   - string: The dead letter channel name
+func (b *InboundChannelAdapterBuilder[TMessageType]) DeadLetterChannelName() string {
+	return b.deadLetterChannelName
+}
+
+// DeadLetterRoutes returns the dead letter channel names by route of the
+// builder.
+//
+// Returns:
+//   - map[string]string: The dead letter channel names by route
+func (b *InboundChannelAdapterBuilder[TMessageType]) DeadLetterRoutes() map[string]string {
+	return b.deadLetterRoutes
+}
+
+// AckMode returns when the messages received by the built adapter are
+// acknowledged.
+//
+// Returns:
+//   - handler.AckMode: The acknowledgment mode
+func (b *InboundChannelAdapterBuilder[TMessageType]) AckMode() handler.AckMode {
+	return b.ackMode
+}
+
+// UnroutableChannelName returns the unroutable channel name of the builder.
+//
+// Returns:
+//   - string: The unroutable channel name
+func (b *InboundChannelAdapterBuilder[TMessageType]) UnroutableChannelName() string {
+	return b.unroutableChannelName
+}
+
+// WireTapChannelName returns the wire tap channel name of the builder.
+//
+// Returns:
+//   - string: The wire tap channel name, empty when disabled
+func (b *InboundChannelAdapterBuilder[TMessageType]) WireTapChannelName() string {
+	return b.wireTapChannelName
+}
+
+// ResponseChannelName returns the response channel name of the builder.
+//
+// Returns:
+//   - string: The response channel name, empty when disabled
+func (b *InboundChannelAdapterBuilder[TMessageType]) ResponseChannelName() string {
+	return b.responseChannelName
+}
+
+// DiscardChannelName returns the channel receiving the messages dropped by
+// the filter.
+//
+// Returns:
+//   - string: The discard channel name
+func (b *InboundChannelAdapterBuilder[TMessageType]) DiscardChannelName() string {
+	return b.discardChannelName
+}
+
+// QuarantineChannelName returns the poison message quarantine channel name of
+// the builder.
+//
+// Returns:
+//   - string: The quarantine channel name
+func (b *InboundChannelAdapterBuilder[TMessageType]) QuarantineChannelName() string {
+	return b.quarantineChannelName
+}
+
 // WithRetryTimes Sets the time and number of retry attempts.
 //
 // Parameters:
@@ -125,6 +563,92 @@ func (b *InboundChannelAdapterBuilder[TMessageType]) WithRetryTimes(
 	b.retryTimeAttempts = hitTimesMillisecond
 }
 
+// WithRetryPolicy sets the retry policy for failed message processing
+// attempts. It takes precedence over the delays set by WithRetryTimes.
+//
+// Parameters:
+//   - policy: the retry policy deciding retries and delays
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithRetryPolicy(
+	policy handler.RetryPolicy,
+) {
+	b.retryPolicy = policy
+}
+
+// WithDeduplication enables the idempotent receiver, skipping messages whose
+// id was already processed.
+//
+// Parameters:
+//   - store: the store tracking the processed message ids
+//   - ttl: how long a processed message id is kept
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithDeduplication(
+	store handler.DeduplicationStore,
+	ttl time.Duration,
+) {
+	b.deduplicationStore = store
+	b.deduplicationTTL = ttl
+}
+
+// WithClaimCheck enables the rehydration of claim-checked payloads from the
+// blob store, before any other interceptor runs.
+//
+// Parameters:
+//   - store: the blob store keeping the payloads
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithClaimCheck(
+	store handler.BlobStore,
+) {
+	b.claimCheckStore = store
+}
+
+// WithEncryption enables the decryption of encrypted payloads, after the
+// claim-checked payloads are rehydrated and before any other interceptor runs.
+//
+// Parameters:
+//   - keyProvider: the key provider unwrapping the data keys
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithEncryption(
+	keyProvider handler.KeyProvider,
+) {
+	b.keyProvider = keyProvider
+}
+
+// WithJSONEncoder sets the encoder the handlers decode the received payloads
+// with, after every other before interceptor runs.
+//
+// Parameters:
+//   - encoder: the JSON encoder of the channel
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithJSONEncoder(
+	encoder *message.JSONEncoder,
+) {
+	b.jsonEncoder = encoder
+}
+
+// WithUpcasters converts the payloads received through the channel to the
+// latest version of their route, so handlers always receive the latest
+// schema while producers migrate.
+//
+// Parameters:
+//   - upcasters: the upcasters of each route and version
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithUpcasters(
+	upcasters ...handler.Upcaster,
+) {
+	b.upcasters = append(b.upcasters, upcasters...)
+}
+
+// WithBacklogMonitor periodically collects how many messages wait to be
+// consumed from the channel, e.g. the Kafka consumer lag or the RabbitMQ
+// queue depth, while the consumer runs. The backlog is published as the
+// messaging.consumer.backlog gauge when metrics are enabled.
+//
+// Parameters:
+//   - interval: how often the backlog is collected
+//   - logBacklog: also log the collected backlog
+func (b *InboundChannelAdapterBuilder[TMessageType]) WithBacklogMonitor(
+	interval time.Duration,
+	logBacklog bool,
+) {
+	b.backlogInterval = interval
+	b.logBacklog = logBacklog
+}
+
 // MessageTranslator returns the configured message translator.
 //
 // Returns:
@@ -143,18 +667,110 @@ func (
 //
 // Returns:
 //   - *InboundChannelAdapter: Configured inbound channel adapter
+//   - error: error if the circuit breaker configuration is invalid
 func (b *InboundChannelAdapterBuilder[TMessageType]) BuildInboundAdapter(
 	inboundAdapter message.ConsumerChannel,
-) *InboundChannelAdapter {
-	return NewInboundChannelAdapter(
+) (*InboundChannelAdapter, error) {
+	beforeProcessors := append(
+		b.beforeProcessors[:len(b.beforeProcessors):len(b.beforeProcessors)],
+		b.topicBeforeProcessors...,
+	)
+	afterProcessors := append(
+		b.afterProcessors[:len(b.afterProcessors):len(b.afterProcessors)],
+		b.topicAfterProcessors...,
+	)
+	if b.keyProvider != nil {
+		beforeProcessors = append(
+			[]message.MessageHandler{handler.NewDecryptInterceptor(b.keyProvider)},
+			beforeProcessors...,
+		)
+	}
+	if b.claimCheckStore != nil {
+		beforeProcessors = append(
+			[]message.MessageHandler{handler.NewClaimCheckInInterceptor(b.claimCheckStore)},
+			beforeProcessors...,
+		)
+	}
+	if b.tenantValidation != nil {
+		beforeProcessors = append(
+			[]message.MessageHandler{b.tenantValidation},
+			beforeProcessors...,
+		)
+	}
+	if b.guards != nil {
+		beforeProcessors = append(
+			[]message.MessageHandler{b.guards},
+			beforeProcessors...,
+		)
+	}
+	if b.jsonEncoder != nil {
+		beforeProcessors = append(
+			beforeProcessors[:len(beforeProcessors):len(beforeProcessors)],
+			handler.NewJSONDecodeInterceptor(b.jsonEncoder),
+		)
+	}
+
+	if len(b.upcasters) > 0 {
+		beforeProcessors = append(
+			beforeProcessors[:len(beforeProcessors):len(beforeProcessors)],
+			handler.NewUpcastInterceptor(b.upcasters...),
+		)
+	}
+
+	if len(b.transformers) > 0 {
+		beforeProcessors = append(
+			beforeProcessors[:len(beforeProcessors):len(beforeProcessors)],
+			handler.NewMessageTransformer(b.transformers...),
+		)
+	}
+
+	adapter := NewInboundChannelAdapter(
 		inboundAdapter,
 		b.referenceName,
 		b.deadLetterChannelName,
-		b.beforeProcessors,
-		b.afterProcessors,
+		beforeProcessors,
+		afterProcessors,
 		b.retryTimeAttempts,
 		b.sendReplyUsingReplyTo,
 	)
+	adapter.deadLetterRoutes = maps.Clone(b.deadLetterRoutes)
+	adapter.retryPolicy = b.retryPolicy
+	adapter.deduplicationStore = b.deduplicationStore
+	adapter.deduplicationTTL = b.deduplicationTTL
+	adapter.unroutableChannelName = b.unroutableChannelName
+	adapter.quarantineChannelName = b.quarantineChannelName
+	adapter.quarantineMaxFailures = b.quarantineMaxFailures
+	adapter.poisonMessageKey = b.poisonMessageKey
+	adapter.backlogInterval = b.backlogInterval
+	adapter.logBacklog = b.logBacklog
+	adapter.ackMode = b.ackMode
+	adapter.wireTapChannelName = b.wireTapChannelName
+	adapter.wireTapSampling = b.wireTapSampling
+	adapter.filter = b.filter
+	adapter.discardChannelName = b.discardChannelName
+	adapter.messageRouter = b.messageRouter
+	adapter.responseChannelName = b.responseChannelName
+	adapter.orderingKey = b.orderingKey
+	adapter.amountOfProcessors = b.amountOfProcessors
+	adapter.processingTimeout = b.processingTimeout
+	adapter.errorPolicy = b.errorPolicy
+	adapter.replyChannelFactory = b.replyChannelFactory
+	adapter.allowedReplyChannels = b.allowedReplyChannels
+	adapter.atomicReply = b.atomicReply
+	if b.quarantineChannelName != "" && b.quarantineMaxFailures <= 0 {
+		return nil, fmt.Errorf(
+			"[inbound-channel] quarantine max failures must be greater than zero, got %d",
+			b.quarantineMaxFailures,
+		)
+	}
+	if b.circuitBreaker != nil {
+		breaker, err := handler.NewCircuitBreaker(*b.circuitBreaker)
+		if err != nil {
+			return nil, err
+		}
+		adapter.circuitBreaker = breaker
+	}
+	return adapter, nil
 }
 
 // NewInboundChannelAdapter creates a new inbound channel adapter instance.
@@ -186,6 +802,7 @@ func NewInboundChannelAdapter(
 		afterProcessors:       afterProcessors,
 		retryTimeAttempts:     retryTimeAttempts,
 		sendReplyUsingReplyTo: sendReplyUsingReplyTo,
+		otelMetrics:           otel.InitMetrics("inbound-channel-adapter"),
 	}
 }
 
@@ -205,6 +822,115 @@ func (i *InboundChannelAdapter) DeadLetterChannelName() string {
 	return i.deadLetterChannelName
 }
 
+// DeadLetterRoutes returns the configured dead letter channel names by route.
+//
+// Returns:
+//   - map[string]string: The dead letter channel names by route, nil when
+//     not configured
+func (i *InboundChannelAdapter) DeadLetterRoutes() map[string]string {
+	return i.deadLetterRoutes
+}
+
+// UnroutableChannelName returns the configured unroutable channel name.
+//
+// Returns:
+//   - string: The unroutable channel name, empty when strict routing is disabled
+func (i *InboundChannelAdapter) UnroutableChannelName() string {
+	return i.unroutableChannelName
+}
+
+// PoisonMessageQuarantine returns the configured poison message quarantine.
+//
+// Returns:
+//   - string: The quarantine channel name, empty when disabled
+//   - int: Consecutive failures after which a message is quarantined
+//   - handler.PoisonMessageKeyExtractor: The message identification, or nil
+//     for the default
+func (i *InboundChannelAdapter) PoisonMessageQuarantine() (
+	string,
+	int,
+	handler.PoisonMessageKeyExtractor,
+) {
+	return i.quarantineChannelName, i.quarantineMaxFailures, i.poisonMessageKey
+}
+
+// WireTap returns the configured wire tap.
+//
+// Returns:
+//   - string: The wire tap channel name, empty when disabled
+//   - float64: The sampled percentage of the messages
+func (i *InboundChannelAdapter) WireTap() (string, float64) {
+	return i.wireTapChannelName, i.wireTapSampling
+}
+
+// Filter returns the configured message filter.
+//
+// Returns:
+//   - router.FilterFunc: The message filter, nil when disabled
+//   - string: The discard channel name, empty to drop messages silently
+func (i *InboundChannelAdapter) Filter() (router.FilterFunc, string) {
+	return i.filter, i.discardChannelName
+}
+
+// Router returns the router of the received messages.
+//
+// Returns:
+//   - router.ContainerRouter: The router, nil when the messages are
+//     dispatched to the action handlers
+func (i *InboundChannelAdapter) Router() router.ContainerRouter {
+	return i.messageRouter
+}
+
+// OrderingKey returns the ordering key of the received messages.
+//
+// Returns:
+//   - func(*message.Message) string: The ordering key, nil when not set
+func (i *InboundChannelAdapter) OrderingKey() func(*message.Message) string {
+	return i.orderingKey
+}
+
+// AmountOfProcessors returns the amount of concurrent processors of the
+// consumers of the channel.
+//
+// Returns:
+//   - int: The amount of processors, zero when not set
+func (i *InboundChannelAdapter) AmountOfProcessors() int {
+	return i.amountOfProcessors
+}
+
+// ProcessingTimeout returns the message processing timeout of the consumers
+// of the channel.
+//
+// Returns:
+//   - time.Duration: The processing timeout, zero when not set
+func (i *InboundChannelAdapter) ProcessingTimeout() time.Duration {
+	return i.processingTimeout
+}
+
+// ErrorPolicy returns the error policy of the consumers of the channel.
+//
+// Returns:
+//   - endpoint.ErrorPolicy: The error policy
+func (i *InboundChannelAdapter) ErrorPolicy() endpoint.ErrorPolicy {
+	return i.errorPolicy
+}
+
+// AckMode returns when the received messages are acknowledged.
+//
+// Returns:
+//   - handler.AckMode: The acknowledgment mode
+func (i *InboundChannelAdapter) AckMode() handler.AckMode {
+	return i.ackMode
+}
+
+// CircuitBreaker returns the configured circuit breaker.
+//
+// Returns:
+//   - *handler.CircuitBreaker: The circuit breaker, nil when disabled
+func (i *InboundChannelAdapter) CircuitBreaker() *handler.CircuitBreaker {
+	return i.circuitBreaker
+}
+
 // BeforeProcessors returns the configured pre-processing handlers.
 //
 // Returns:
@@ -229,6 +955,26 @@ func (i *InboundChannelAdapter) RetryAttempts() []int {
 	return i.retryTimeAttempts
 }
 
+// RetryPolicy returns the configured retry policy.
+//
+// Returns:
+//   - handler.RetryPolicy: The retry policy, or nil if none is configured
+func (i *InboundChannelAdapter) RetryPolicy() handler.RetryPolicy {
+	return i.retryPolicy
+}
+
+// Deduplication returns the configured deduplication store and ttl.
+//
+// Returns:
+//   - handler.DeduplicationStore: The store, or nil if deduplication is disabled
+//   - time.Duration: How long a processed message id is kept
+func (i *InboundChannelAdapter) Deduplication() (
+	handler.DeduplicationStore,
+	time.Duration,
+) {
+	return i.deduplicationStore, i.deduplicationTTL
+}
+
 // SendReplyUsingReplyTo returns whether reply-to functionality is enabled.
 //
 // Returns:
@@ -237,6 +983,33 @@ func (i *InboundChannelAdapter) SendReplyUsingReplyTo() bool {
 	return i.sendReplyUsingReplyTo
 }
 
+// ReplyChannelFactory returns the factory of the reply channels of unknown
+// reply-to destinations.
+//
+// Returns:
+//   - handler.ReplyChannelFactory: The reply channel factory, nil when not set
+//   - []string: The patterns of the destinations allowed to be created
+func (i *InboundChannelAdapter) ReplyChannelFactory() (handler.ReplyChannelFactory, []string) {
+	return i.replyChannelFactory, i.allowedReplyChannels
+}
+
+// AtomicReply returns whether the replies are published before the received
+// messages are acknowledged.
+//
+// Returns:
+//   - bool: true when the replies are atomic
+func (i *InboundChannelAdapter) AtomicReply() bool {
+	return i.atomicReply
+}
+
+// ResponseChannelName returns the configured response channel name.
+//
+// Returns:
+//   - string: The response channel name, empty when disabled
+func (i *InboundChannelAdapter) ResponseChannelName() string {
+	return i.responseChannelName
+}
+
 // ReceiveMessage receives a message from the channel, respecting context cancellation.
 //
 // Parameters:
@@ -256,6 +1029,145 @@ func (i *InboundChannelAdapter) ReceiveMessage(ctx context.Context) (*message.Me
 	return i.inboundAdapter.Receive(ctx)
 }
 
+// Err returns the terminal failure which stopped the consumption of the
+// channel, when the channel reports it.
+//
+// Returns:
+//   - error: The terminal failure, nil while consuming or when not reported
+func (i *InboundChannelAdapter) Err() error {
+	if failingChannel, ok := i.inboundAdapter.(FailingChannel); ok {
+		return failingChannel.Err()
+	}
+	return nil
+}
+
+// Backlog returns how many messages wait to be consumed from the channel.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//
+// Returns:
+//   - int64: The amount of messages waiting to be consumed
+//   - error: Error if the channel cannot report its backlog or the broker
+//     cannot be inspected
+func (i *InboundChannelAdapter) Backlog(ctx context.Context) (int64, error) {
+	backlogChannel, ok := i.inboundAdapter.(BacklogChannel)
+	if !ok {
+		return 0, fmt.Errorf(
+			"[inbound-channel] channel %s does not report its backlog",
+			i.referenceName,
+		)
+	}
+	return backlogChannel.Backlog(ctx)
+}
+
+// MonitorBacklog collects the channel backlog at the configured interval until
+// the context is done. It returns immediately when the backlog monitor is not
+// configured or the channel cannot report its backlog.
+//
+// Parameters:
+//   - ctx: Context stopping the monitor
+func (i *InboundChannelAdapter) MonitorBacklog(ctx context.Context) {
+	backlogChannel, ok := i.inboundAdapter.(BacklogChannel)
+	if !ok || i.backlogInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(i.backlogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		backlog, err := backlogChannel.Backlog(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("[inbound-channel] failed to collect backlog",
+					"channel", i.referenceName,
+					"reason", err.Error(),
+				)
+			}
+			continue
+		}
+
+		i.otelMetrics.RecordBacklog(
+			ctx,
+			backlogChannel.MessagingSystem(),
+			i.referenceName,
+			backlog,
+		)
+		if i.logBacklog {
+			slog.Info("[inbound-channel] backlog collected",
+				"channel", i.referenceName,
+				"backlog", backlog,
+			)
+		}
+	}
+}
+
+// SeekTo restarts the consumption of the channel at a position of its source.
+//
+// Parameters:
+//   - position: The position of the next message to receive
+//
+// Returns:
+//   - error: Error if the channel cannot be repositioned
+func (i *InboundChannelAdapter) SeekTo(position int64) error {
+	seekableChannel, ok := i.inboundAdapter.(SeekableChannel)
+	if !ok {
+		return fmt.Errorf(
+			"[inbound-channel] channel %s cannot seek",
+			i.referenceName,
+		)
+	}
+	return seekableChannel.SeekTo(position)
+}
+
+// PositionOf returns the position of a received message in the source of the
+// channel.
+//
+// Parameters:
+//   - msg: The received message
+//
+// Returns:
+//   - int64: The position of the message
+//   - error: Error if the channel cannot seek or the message was not received
+//     by it
+func (i *InboundChannelAdapter) PositionOf(msg *message.Message) (int64, error) {
+	seekableChannel, ok := i.inboundAdapter.(SeekableChannel)
+	if !ok {
+		return 0, fmt.Errorf(
+			"[inbound-channel] channel %s cannot seek",
+			i.referenceName,
+		)
+	}
+	return seekableChannel.PositionOf(msg)
+}
+
+// SourceIdOf returns the identity of a received message in the source of the
+// channel, the same on every delivery of the message.
+//
+// Parameters:
+//   - msg: The received message
+//
+// Returns:
+//   - string: The identity of the message in the source
+//   - error: Error if the channel cannot identify its messages or the message
+//     was not received by it
+func (i *InboundChannelAdapter) SourceIdOf(msg *message.Message) (string, error) {
+	identifiableChannel, ok := i.inboundAdapter.(IdentifiableChannel)
+	if !ok {
+		return "", fmt.Errorf(
+			"[inbound-channel] channel %s cannot identify its messages",
+			i.referenceName,
+		)
+	}
+	return identifiableChannel.SourceIdOf(msg)
+}
+
 // Close closes the inbound channel adapter, releasing associated resources.
 //
 // Returns:
@@ -279,3 +1191,23 @@ func (i *InboundChannelAdapter) CommitMessage(msg *message.Message) error {
 
 	return ackChannel.CommitMessage(msg)
 }
+
+// NackMessage settles a message as not processed. Channels unable to settle a
+// message negatively leave it uncommitted for redelivery when requeue is
+// true, and commit it otherwise.
+//
+// Parameters:
+//   - msg: The message to settle
+//   - requeue: true to redeliver the message, false to discard it
+//
+// Returns:
+//   - error: Error if the settlement fails
+func (i *InboundChannelAdapter) NackMessage(msg *message.Message, requeue bool) error {
+	if nackChannel, ok := i.inboundAdapter.(handler.ChannelMessageNegativeAcknowledgment); ok {
+		return nackChannel.NackMessage(msg, requeue)
+	}
+	if requeue {
+		return nil
+	}
+	return i.CommitMessage(msg)
+}
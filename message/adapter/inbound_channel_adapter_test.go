@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+	"github.com/jeffersonbrasilino/gomes/otel"
 )
 
 // mockConsumerChannel implements message.ConsumerChannel for tests.
@@ -53,6 +57,21 @@ func (m *mockTranslator) ToMessage(msg string) (*message.Message, error) {
 		Build(), nil
 }
 
+// buildInboundAdapter builds the adapter of a builder, failing the test on
+// error.
+func buildInboundAdapter(
+	t *testing.T,
+	builder *adapter.InboundChannelAdapterBuilder[string],
+	channel message.ConsumerChannel,
+) *adapter.InboundChannelAdapter {
+	t.Helper()
+	built, err := builder.BuildInboundAdapter(channel)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return built
+}
+
 func TestNewInboundChannelAdapterBuilder(t *testing.T) {
 	t.Parallel()
 	translator := &mockTranslator{}
@@ -70,7 +89,7 @@ func TestInboundChannelAdapterBuilder_WithDeadLetterChannelName(t *testing.T) {
 	translator := &mockTranslator{}
 	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
 	builder.WithDeadLetterChannelName("dlc")
-	b := builder.BuildInboundAdapter(&mockConsumerChannel{})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
 	if b.DeadLetterChannelName() != "dlc" {
 		t.Errorf("Expected DeadLetterChannelName 'dlc', got '%s'", b.DeadLetterChannelName())
 	}
@@ -81,29 +100,91 @@ func TestInboundChannelAdapterBuilder_WithBeforeInterceptors(t *testing.T) {
 	translator := &mockTranslator{}
 	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
 	builder.WithBeforeInterceptors(&mockMessageHandler{})
-	b := builder.BuildInboundAdapter(&mockConsumerChannel{})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
 	if len(b.BeforeProcessors()) != 1 {
 		t.Error("BeforeProcessors not assigned correctly")
 	}
 }
 
+func TestInboundChannelAdapterBuilder_WithTransformer(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	builder.WithBeforeInterceptors(&mockMessageHandler{})
+	builder.WithTransformer("order.created", func(msg *message.Message) (*message.Message, error) {
+		return message.NewMessageBuilderFromMessage(msg).WithPayload("canonical").Build(), nil
+	})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	processors := b.BeforeProcessors()
+	if len(processors) != 2 {
+		t.Fatalf("Expected 2 before processors, got %d", len(processors))
+	}
+
+	msg := message.NewMessageBuilder().WithRoute("order.created").WithPayload("legacy").Build()
+	result, err := processors[1].Handle(context.Background(), msg)
+	if err != nil || result.GetPayload() != "canonical" {
+		t.Errorf("Expected transformed message after the interceptors, got %v, %v", result, err)
+	}
+}
+
+func TestInboundChannelAdapterBuilder_WithUpcasters(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	builder.WithUpcasters(handler.Upcaster{
+		Route:       "order.created",
+		FromVersion: "1.0",
+		ToVersion:   "2.0",
+		Upcast: func(ctx context.Context, payload any) (any, error) {
+			return "v2", nil
+		},
+	})
+	processors := buildInboundAdapter(t, builder, &mockConsumerChannel{}).BeforeProcessors()
+	if len(processors) != 1 {
+		t.Fatalf("Expected 1 before processor, got %d", len(processors))
+	}
+
+	msg := message.NewMessageBuilder().WithRoute("order.created").WithPayload("v1").Build()
+	result, err := processors[0].Handle(context.Background(), msg)
+	if err != nil || result.GetPayload() != "v2" {
+		t.Errorf("Expected upcast message, got %v, %v", result, err)
+	}
+}
+
 func TestInboundChannelAdapterBuilder_WithAfterInterceptors(t *testing.T) {
 	t.Parallel()
 	translator := &mockTranslator{}
 	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
 	builder.WithAfterInterceptors(&mockMessageHandler{})
-	b := builder.BuildInboundAdapter(&mockConsumerChannel{})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
 	if len(b.AfterProcessors()) != 1 {
 		t.Error("AfterProcessors not assigned correctly")
 	}
 }
 
+func TestInboundChannelAdapterBuilder_WithTopicInterceptors(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	builder.WithBeforeInterceptors(&mockMessageHandler{})
+	builder.WithTopicBeforeInterceptors("orders", &mockMessageHandler{})
+	builder.WithAfterInterceptors(&mockMessageHandler{})
+	builder.WithTopicAfterInterceptors("orders", &mockMessageHandler{}, &mockMessageHandler{})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	if len(b.BeforeProcessors()) != 2 {
+		t.Errorf("Expected 2 before processors, got %d", len(b.BeforeProcessors()))
+	}
+	if len(b.AfterProcessors()) != 3 {
+		t.Errorf("Expected 3 after processors, got %d", len(b.AfterProcessors()))
+	}
+}
+
 func TestInboundChannelAdapterBuilder_WithSendReplyUsingReplyTo(t *testing.T) {
 	t.Parallel()
 	translator := &mockTranslator{}
 	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
 	builder.WithSendReplyUsingReplyTo()
-	b := builder.BuildInboundAdapter(&mockConsumerChannel{})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
 	if b.SendReplyUsingReplyTo() != true {
 		t.Error("SendReplyUsingReplyTo not set correctly")
 	}
@@ -114,23 +195,164 @@ func TestInboundChannelAdapterBuilder_WithRetryTimes(t *testing.T) {
 	translator := &mockTranslator{}
 	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
 	builder.WithRetryTimes(1_000)
-	b := builder.BuildInboundAdapter(&mockConsumerChannel{})
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
 	if b.RetryAttempts()[0] != 1_000 {
 		t.Error("RetryTimes not set correctly")
 	}
 }
 
+func TestInboundChannelAdapterBuilder_WithRetryPolicy(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	policy := handler.NewFixedRetryPolicy(1_000)
+	builder.WithRetryPolicy(policy)
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	if b.RetryPolicy() != policy {
+		t.Error("RetryPolicy not set correctly")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_WithDeduplication(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	store := handler.NewInMemoryDeduplicationStore(10)
+	builder.WithDeduplication(store, time.Minute)
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	gotStore, gotTTL := b.Deduplication()
+	if gotStore != store || gotTTL != time.Minute {
+		t.Error("Deduplication not set correctly")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_WithUnroutableChannelName(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	builder.WithUnroutableChannelName("unroutable")
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	if b.UnroutableChannelName() != "unroutable" {
+		t.Error("UnroutableChannelName not set correctly")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_WithPoisonMessageQuarantine(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	builder.WithPoisonMessageQuarantine("quarantine", 5)
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	channelName, maxFailures, keyExtractor := b.PoisonMessageQuarantine()
+	if channelName != "quarantine" || maxFailures != 5 || keyExtractor != nil {
+		t.Error("PoisonMessageQuarantine not set correctly")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_InvalidPoisonMessageQuarantine(t *testing.T) {
+	t.Parallel()
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", &mockTranslator{})
+	builder.WithPoisonMessageQuarantine("quarantine", 0)
+	if _, err := builder.BuildInboundAdapter(&mockConsumerChannel{}); err == nil {
+		t.Error("expected error for zero max failures")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_WithAckMode(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	if buildInboundAdapter(t, builder, &mockConsumerChannel{}).AckMode() != handler.AckAuto {
+		t.Error("expected AckAuto by default")
+	}
+	builder.WithAckMode(handler.AckOnSuccess)
+	b := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	if b.AckMode() != handler.AckOnSuccess {
+		t.Error("AckMode not set correctly")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_WithOrderingKey(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	if buildInboundAdapter(t, builder, &mockConsumerChannel{}).OrderingKey() != nil {
+		t.Error("expected no ordering key by default")
+	}
+	builder.WithOrderingKey(func(msg *message.Message) string {
+		return msg.GetHeader().Get(message.HeaderRoute)
+	})
+	key := buildInboundAdapter(t, builder, &mockConsumerChannel{}).OrderingKey()
+	msg := message.NewMessageBuilder().WithRoute("orders").Build()
+	if key == nil || key(msg) != "orders" {
+		t.Error("OrderingKey not set correctly")
+	}
+}
+
+func TestInboundChannelAdapterBuilder_ProcessingOptions(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	builder.WithAmountOfProcessors(4)
+	builder.WithProcessingTimeout(5 * time.Second)
+	builder.WithErrorPolicy(endpoint.ErrorPolicyDLQAndContinue)
+
+	built := buildInboundAdapter(t, builder, &mockConsumerChannel{})
+	if built.AmountOfProcessors() != 4 {
+		t.Errorf("expected 4 processors, got %d", built.AmountOfProcessors())
+	}
+	if built.ProcessingTimeout() != 5*time.Second {
+		t.Errorf("expected 5s processing timeout, got %v", built.ProcessingTimeout())
+	}
+	if built.ErrorPolicy() != endpoint.ErrorPolicyDLQAndContinue {
+		t.Errorf("expected dlq-and-continue policy, got %v", built.ErrorPolicy())
+	}
+}
+
+// failingConsumerChannel is a consumer channel whose consumption stopped
+// with a terminal failure.
+type failingConsumerChannel struct {
+	*mockConsumerChannel
+}
+
+func (f *failingConsumerChannel) Err() error {
+	return f.err
+}
+
+func TestInboundChannelAdapter_Err(t *testing.T) {
+	t.Parallel()
+	translator := &mockTranslator{}
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
+	if err := buildInboundAdapter(t, builder, &mockConsumerChannel{}).Err(); err != nil {
+		t.Errorf("expected no error for channels not reporting failures, got %v", err)
+	}
+	terminalErr := errors.New("consumer closed")
+	failing := &failingConsumerChannel{&mockConsumerChannel{err: terminalErr}}
+	if err := buildInboundAdapter(t, builder, failing).Err(); !errors.Is(err, terminalErr) {
+		t.Errorf("expected terminal failure, got %v", err)
+	}
+}
+
 func TestInboundChannelAdapterBuilder_BuildInboundAdapter(t *testing.T) {
 	t.Parallel()
 	translator := &mockTranslator{}
 	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", translator)
 	mockChan := &mockConsumerChannel{}
-	adapterInstance := builder.BuildInboundAdapter(mockChan)
+	adapterInstance := buildInboundAdapter(t, builder, mockChan)
 	if adapterInstance.ReferenceName() != "ref" {
 		t.Errorf("Expected ReferenceName 'ref', got '%s'", adapterInstance.ReferenceName())
 	}
 }
 
+func TestInboundChannelAdapterBuilder_InvalidCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", &mockTranslator{})
+	builder.WithCircuitBreaker(handler.CircuitBreakerConfig{FailureRateThreshold: 2})
+	if _, err := builder.BuildInboundAdapter(&mockConsumerChannel{}); err == nil {
+		t.Error("expected error for an invalid failure rate threshold")
+	}
+}
+
 func TestInboundChannelAdapterBuilder_ReferenceName(t *testing.T) {
 	t.Parallel()
 	translator := &mockTranslator{}
@@ -272,3 +494,60 @@ func TestClose(t *testing.T) {
 		}
 	})
 }
+
+// mockBacklogChannel implements adapter.BacklogChannel for tests.
+type mockBacklogChannel struct {
+	*mockConsumerChannel
+	collected chan int64
+}
+
+func (m *mockBacklogChannel) Backlog(ctx context.Context) (int64, error) {
+	m.collected <- 42
+	return 42, nil
+}
+
+func (m *mockBacklogChannel) MessagingSystem() otel.MessageSystemType {
+	return otel.MessageSystemTypeKafka
+}
+
+func TestInboundChannelAdapter_Backlog(t *testing.T) {
+	t.Run("channel without backlog", func(t *testing.T) {
+		t.Parallel()
+		adp := buildInboundAdapter(
+			t,
+			adapter.NewInboundChannelAdapterBuilder("ref", "chan", &mockTranslator{}),
+			&mockConsumerChannel{},
+		)
+		if _, err := adp.Backlog(context.Background()); err == nil {
+			t.Error("expected error for a channel without backlog")
+		}
+	})
+
+	t.Run("monitor collects at the interval", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockBacklogChannel{&mockConsumerChannel{}, make(chan int64, 10)}
+		builder := adapter.NewInboundChannelAdapterBuilder("ref", "chan", &mockTranslator{})
+		builder.WithBacklogMonitor(time.Millisecond, true)
+		adp := buildInboundAdapter(t, builder, channel)
+
+		backlog, err := adp.Backlog(context.Background())
+		if err != nil || backlog != 42 {
+			t.Fatalf("expected backlog 42, got %d, %v", backlog, err)
+		}
+		<-channel.collected
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adp.MonitorBacklog(ctx)
+			close(done)
+		}()
+		select {
+		case <-channel.collected:
+		case <-time.After(time.Second):
+			t.Fatal("expected the backlog to be collected")
+		}
+		cancel()
+		<-done
+	})
+}
@@ -14,8 +14,14 @@ package adapter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
 
 // OutboundChannelAdapterBuilder provides a fluent interface for configuring
@@ -28,6 +34,31 @@ type OutboundChannelAdapterBuilder[TMessageType any] struct {
 	channelName       string
 	replyChannelName  string
 	messageTranslator OutboundChannelMessageTranslator[TMessageType]
+	beforeProcessors  []message.MessageHandler
+	afterProcessors   []message.MessageHandler
+	downcast          message.MessageHandler
+	jsonEncoder       *message.JSONEncoder
+	encryption        message.MessageHandler
+	claimCheck        message.MessageHandler
+	compression       *compressionSettings
+	replyCorrelator   *handler.ReplyCorrelator
+	correlationStore  handler.CorrelationStore
+	correlationTTL    time.Duration
+	lateReplyHandler  func(msg *message.Message)
+	wireTapChannel    string
+	wireTapSampling   float64
+	publishRetry      handler.RetryPolicy
+	publishFallback   string
+	buffer            MessageBuffer
+	flushInterval     time.Duration
+	maxFlushAttempts  int
+}
+
+// compressionSettings holds the compression configured for a channel, whose
+// encoding is validated when the channel is built.
+type compressionSettings struct {
+	encoding string
+	minSize  int
 }
 
 // OutboundChannelAdapter handles the sending of messages to external systems
@@ -35,6 +66,19 @@ type OutboundChannelAdapterBuilder[TMessageType any] struct {
 type OutboundChannelAdapter struct {
 	outboundAdapter  message.PublisherChannel
 	replyChannelName string
+	sendInterceptors []message.MessageHandler
+	afterProcessors  []message.MessageHandler
+	replyCorrelator  *handler.ReplyCorrelator
+	correlationStore handler.CorrelationStore
+	correlationTTL   time.Duration
+	lateReplyHandler func(msg *message.Message)
+	wireTapChannel   string
+	wireTapSampling  float64
+	wireTap          message.MessageHandler
+	publishRetry     handler.RetryPolicy
+	fallbackChannel  string
+	fallback         message.PublisherChannel
+	storeAndForward  *storeAndForward
 }
 
 // NewOutboundChannelAdapterBuilder creates a new builder instance for configuring
@@ -56,6 +100,7 @@ func NewOutboundChannelAdapterBuilder[T any](
 		referenceName:     referenceName,
 		channelName:       channelName,
 		messageTranslator: messageTranslator,
+		wireTapSampling:   100,
 	}
 }
 
@@ -81,6 +126,254 @@ func (b *OutboundChannelAdapterBuilder[TMessageType]) WithReplyChannelName(
 	return b
 }
 
+// WithBeforeInterceptors sets the interceptors executed over every message
+// before it is published, e.g. to stamp tenant headers or validate the
+// payload. They run before the encoding, encryption, claim check and
+// compression of the channel, so they see the message as sent by the
+// application. An interceptor returning an error aborts the publication and
+// one returning a nil message drops it.
+//
+// Parameters:
+//   - processors: Variable number of message handlers to execute before publishing
+//
+// Returns:
+//   - *OutboundChannelAdapterBuilder[TMessageType]: builder instance for method chaining
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithBeforeInterceptors(
+	processors ...message.MessageHandler,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.beforeProcessors = processors
+	return b
+}
+
+// WithAfterInterceptors sets the interceptors executed over every message
+// published by the channel, e.g. to audit the sent messages. They receive
+// the message as published and, since it is already sent, their failures are
+// only logged.
+//
+// Parameters:
+//   - processors: Variable number of message handlers to execute after publishing
+//
+// Returns:
+//   - *OutboundChannelAdapterBuilder[TMessageType]: builder instance for method chaining
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithAfterInterceptors(
+	processors ...message.MessageHandler,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.afterProcessors = processors
+	return b
+}
+
+// WithEncryption enables the envelope encryption of the payloads sent through
+// the channel, e.g. for events carrying personal data.
+//
+// Parameters:
+//   - keyProvider: The key provider wrapping the data keys
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithEncryption(
+	keyProvider handler.KeyProvider,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.encryption = handler.NewEncryptInterceptor(keyProvider)
+	return b
+}
+
+// WithClaimCheck enables the claim check for large payloads: payloads whose
+// JSON encoding is bigger than the threshold are kept in the blob store and
+// sent as a reference header.
+//
+// Parameters:
+//   - store: The blob store keeping the payloads
+//   - threshold: The encoded payload size, in bytes, above which it is stored
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithClaimCheck(
+	store handler.BlobStore,
+	threshold int,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.claimCheck = handler.NewClaimCheckOutInterceptor(store, threshold)
+	return b
+}
+
+// WithDowncasters converts the payloads sent through the channel to the
+// version expected by its consumers, e.g. a legacy channel during a migration
+// window.
+//
+// Parameters:
+//   - targetVersion: The version expected by the channel consumers
+//   - downcasters: The downcasters of each route and version
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithDowncasters(
+	targetVersion string,
+	downcasters ...handler.Downcaster,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.downcast = handler.NewDowncastInterceptor(targetVersion, downcasters...)
+	return b
+}
+
+// WithJSONEncoder sets the encoder of the message payloads sent through the
+// channel, e.g. to match the JSON contract of an interop partner.
+//
+// Parameters:
+//   - encoder: The JSON encoder of the channel
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithJSONEncoder(
+	encoder *message.JSONEncoder,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.jsonEncoder = encoder
+	return b
+}
+
+// WithCompression enables the compression of the serialized payloads sent
+// through the channel. Consumers decompress them transparently by the
+// contentEncoding header.
+//
+// Parameters:
+//   - encoding: The content encoding, message.ContentEncodingGzip or
+//     message.ContentEncodingZstd
+//   - minSize: The serialized payload size, in bytes, below which the payload
+//     is sent uncompressed
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithCompression(
+	encoding string,
+	minSize int,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.compression = &compressionSettings{encoding: encoding, minSize: minSize}
+	return b
+}
+
+// WithReplyCorrelator makes the requests sent through the channel await their
+// correlated reply, received from a reply channel shared by many instances,
+// instead of the publish acknowledgement. The consumer of the reply channel
+// must use the same correlator as its first before interceptor.
+//
+// Parameters:
+//   - correlator: The reply correlator of this instance
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithReplyCorrelator(
+	correlator *handler.ReplyCorrelator,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.replyCorrelator = correlator
+	return b
+}
+
+// WithCorrelationStore keeps the correlation of each request sent through the
+// channel by the buses in the given store while it waits for its reply, keyed
+// by the request message id. With a durable store, the replies received after
+// a restart can still be matched to their request, see gomes.MatchReply.
+//
+// Parameters:
+//   - store: The pending correlation store
+//   - ttl: How long a correlation is kept, zero means it never expires
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithCorrelationStore(
+	store handler.CorrelationStore,
+	ttl time.Duration,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.correlationStore = store
+	b.correlationTTL = ttl
+	return b
+}
+
+// WithLateReplyHandler sets the handler called by the buses with the replies
+// to requests sent through the channel which arrive after their request
+// finished, e.g. by a reply timeout. Late replies are always logged and
+// counted, see bus.CommandBus.OrphanReplies.
+//
+// Parameters:
+//   - lateReplyHandler: The late reply handler
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithLateReplyHandler(
+	lateReplyHandler func(msg *message.Message),
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.lateReplyHandler = lateReplyHandler
+	return b
+}
+
+// WithWireTap asynchronously copies every message sent through the channel,
+// before its encoding, to the given publisher channel for audit or
+// inspection.
+//
+// Parameters:
+//   - channelName: The wire tap channel name
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithWireTap(
+	channelName string,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.wireTapChannel = channelName
+	return b
+}
+
+// WithWireTapSampling sets the percentage of the sent messages copied to the
+// wire tap channel.
+//
+// Parameters:
+//   - percentage: The sampled percentage, from 0 to 100, 100 by default
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithWireTapSampling(
+	percentage float64,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.wireTapSampling = percentage
+	return b
+}
+
+// WithPublishRetry retries the failed publications of the channel by the
+// given policy, so transient broker errors do not reach the callers. When
+// every attempt fails, the message is sent to the fallback channel, if given,
+// e.g. a local buffer forwarded once the broker is back.
+//
+// Parameters:
+//   - policy: The retry policy of the publications
+//   - fallbackChannelName: The optional fallback publisher channel name
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithPublishRetry(
+	policy handler.RetryPolicy,
+	fallbackChannelName ...string,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.publishRetry = policy
+	if len(fallbackChannelName) > 0 {
+		b.publishFallback = fallbackChannelName[0]
+	}
+	return b
+}
+
+// WithStoreAndForward keeps the messages the channel fails to publish in a
+// local durable buffer, e.g. during broker outages, instead of failing the
+// callers. A background flusher publishes the buffered messages every
+// interval once the broker recovers; while messages are buffered, new ones
+// are buffered too, preserving the publishing order of the channel. Requests
+// awaiting a reply are never buffered. Messages are buffered as intercepted
+// by the send interceptors, so they are not intercepted again on the flush.
+//
+// Parameters:
+//   - buffer: The durable buffer of the channel, e.g. NewFileMessageBuffer
+//   - flushInterval: The interval between flush attempts, 1 second by default
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithStoreAndForward(
+	buffer MessageBuffer,
+	flushInterval time.Duration,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.buffer = buffer
+	b.flushInterval = flushInterval
+	return b
+}
+
+// WithStoreAndForwardMaxAttempts sets how many flushes a buffered message may
+// fail before it is discarded, 5 by default. A discarded message is sent to
+// the publish fallback channel, when configured by WithPublishRetry, or
+// dropped. Messages are only discarded once a later buffered message is
+// published, so broker outages never discard messages.
+//
+// Parameters:
+//   - maxAttempts: The failed flushes after which a message is discarded
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WithStoreAndForwardMaxAttempts(
+	maxAttempts int,
+) *OutboundChannelAdapterBuilder[TMessageType] {
+	b.maxFlushAttempts = maxAttempts
+	return b
+}
+
+// PublishFallbackChannelName returns the publish fallback channel name of the
+// builder.
+//
+// Returns:
+//   - string: The fallback channel name, empty when disabled
+func (b *OutboundChannelAdapterBuilder[TMessageType]) PublishFallbackChannelName() string {
+	return b.publishFallback
+}
+
+// WireTapChannelName returns the wire tap channel name of the builder.
+//
+// Returns:
+//   - string: The wire tap channel name, empty when disabled
+func (b *OutboundChannelAdapterBuilder[TMessageType]) WireTapChannelName() string {
+	return b.wireTapChannel
+}
+
 // ReferenceName returns the current reference name of the builder.
 //
 // Returns:
@@ -134,6 +427,65 @@ func (b *OutboundChannelAdapterBuilder[TMessageType]) BuildOutboundAdapter(
 ) (*OutboundChannelAdapter, error) {
 
 	outboundHandler := NewOutboundChannelAdapter(outboundAdapter, b.replyChannelName)
+	outboundHandler.replyCorrelator = b.replyCorrelator
+	outboundHandler.correlationStore = b.correlationStore
+	outboundHandler.correlationTTL = b.correlationTTL
+	outboundHandler.lateReplyHandler = b.lateReplyHandler
+	outboundHandler.wireTapChannel = b.wireTapChannel
+	outboundHandler.wireTapSampling = b.wireTapSampling
+	outboundHandler.publishRetry = b.publishRetry
+	outboundHandler.fallbackChannel = b.publishFallback
+	outboundHandler.sendInterceptors = slices.Clone(b.beforeProcessors)
+	outboundHandler.afterProcessors = slices.Clone(b.afterProcessors)
+	if b.buffer != nil {
+		storeAndForward, err := newStoreAndForward(
+			outboundHandler,
+			b.buffer,
+			b.flushInterval,
+			b.maxFlushAttempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		outboundHandler.storeAndForward = storeAndForward
+	}
+	if b.downcast != nil {
+		outboundHandler.sendInterceptors = append(
+			outboundHandler.sendInterceptors,
+			b.downcast,
+		)
+	}
+	if b.jsonEncoder != nil {
+		outboundHandler.sendInterceptors = append(
+			outboundHandler.sendInterceptors,
+			handler.NewJSONEncodeInterceptor(b.jsonEncoder),
+		)
+	}
+	if b.encryption != nil {
+		outboundHandler.sendInterceptors = append(
+			outboundHandler.sendInterceptors,
+			b.encryption,
+		)
+	}
+	if b.claimCheck != nil {
+		outboundHandler.sendInterceptors = append(
+			outboundHandler.sendInterceptors,
+			b.claimCheck,
+		)
+	}
+	if b.compression != nil {
+		compression, err := handler.NewCompressionInterceptor(
+			b.compression.encoding,
+			b.compression.minSize,
+		)
+		if err != nil {
+			return nil, err
+		}
+		outboundHandler.sendInterceptors = append(
+			outboundHandler.sendInterceptors,
+			compression,
+		)
+	}
 	return outboundHandler, nil
 }
 
@@ -169,12 +521,35 @@ func (o *OutboundChannelAdapter) Send(
 	ctx context.Context,
 	msg *message.Message,
 ) error {
+	message.RecordHistory(msg, message.HistoryComponentOutboundChannel, o.Name())
 
 	if o.replyChannelName != "" {
 		msg.GetHeader().Set(message.HeaderReplyTo, o.replyChannelName)
 	}
-	err := o.outboundAdapter.Send(ctx, msg)
-	if msg.GetInternalReplyChannel() != nil {
+
+	var replies <-chan *message.Message
+	var release func()
+	awaitReply := o.replyCorrelator != nil &&
+		msg.GetInternalReplyChannel() != nil &&
+		message.ReplyExpected(ctx)
+	if awaitReply {
+		msg.GetHeader().Set(message.HeaderReplyInstanceId, o.replyCorrelator.InstanceId())
+		replies, release = o.replyCorrelator.Register(
+			msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	}
+
+	if o.wireTap != nil {
+		o.wireTap.Handle(ctx, msg)
+	}
+
+	err := o.publish(ctx, msg)
+	if awaitReply && err == nil {
+		go o.awaitCorrelatedReply(ctx, msg, replies, release)
+	} else if msg.GetInternalReplyChannel() != nil {
+		if release != nil {
+			release()
+		}
 		go o.publishOnInternalChannel(ctx, msg, err)
 	}
 
@@ -185,6 +560,281 @@ func (o *OutboundChannelAdapter) Send(
 	return nil
 }
 
+// publish intercepts the message and sends it through the publisher channel,
+// retrying the failures by the publish retry policy. When every attempt
+// fails, the intercepted message is kept in the store and forward buffer, or
+// the original message is sent to the fallback channel, if configured. When
+// the context publishes a batch, the message is only collected: it goes
+// through the same steps when the batch is flushed, which reports its
+// outcome.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - msg: The message to be published
+//
+// Returns:
+//   - error: The publication error, nil if buffered, sent to the fallback
+//     channel or collected in a batch
+func (o *OutboundChannelAdapter) publish(
+	ctx context.Context,
+	msg *message.Message,
+) error {
+	if message.CollectInBatch(ctx, outboundBatchChannel{o}, msg) {
+		return nil
+	}
+	msgToSend, err := o.intercept(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if msgToSend == nil {
+		return nil
+	}
+	return o.deliver(ctx, msg, msgToSend, func() error {
+		return o.sendWithRetry(ctx, msgToSend)
+	})
+}
+
+// deliver publishes an intercepted message by the send function, keeping it
+// in the store and forward buffer or sending the original message to the
+// fallback channel when the send fails.
+func (o *OutboundChannelAdapter) deliver(
+	ctx context.Context,
+	msg *message.Message,
+	msgToSend *message.Message,
+	send func() error,
+) error {
+	if o.storeAndForward != nil && msg.GetInternalReplyChannel() == nil {
+		return o.storeAndForward.publish(ctx, msgToSend, send)
+	}
+	if err := send(); err != nil {
+		return o.sendToFallback(ctx, msg, err)
+	}
+	return nil
+}
+
+// outboundBatchChannel collects the messages of an outbound channel adapter
+// in a batch, writing them through the publisher channel in a single call on
+// flush. The failed messages are retried, buffered or sent to the fallback
+// channel as the messages published one by one.
+type outboundBatchChannel struct {
+	adapter *OutboundChannelAdapter
+}
+
+// Send publishes a single message as a batch.
+func (c outboundBatchChannel) Send(ctx context.Context, msg *message.Message) error {
+	return c.SendBatch(ctx, []*message.Message{msg})
+}
+
+// SendBatch intercepts the messages, writes them through the publisher
+// channel and handles the failures of each message.
+func (c outboundBatchChannel) SendBatch(ctx context.Context, msgs []*message.Message) error {
+	o := c.adapter
+	failures := map[int]error{}
+	positions := make([]int, 0, len(msgs))
+	intercepted := make([]*message.Message, 0, len(msgs))
+	for i, msg := range msgs {
+		msgToSend, err := o.intercept(ctx, msg)
+		if err != nil {
+			failures[i] = err
+			continue
+		}
+		if msgToSend != nil {
+			positions = append(positions, i)
+			intercepted = append(intercepted, msgToSend)
+		}
+	}
+
+	var writeFailures map[int]error
+	written := o.storeAndForward == nil || !o.storeAndForward.buffering()
+	if written {
+		writeFailures = message.PublishBatch(ctx, o.outboundAdapter, intercepted)
+	}
+	for i, msgToSend := range intercepted {
+		send := func() error {
+			return o.sendWithRetry(ctx, msgToSend)
+		}
+		if written {
+			writeErr, failed := writeFailures[i]
+			if !failed {
+				o.afterPublish(ctx, msgToSend)
+				continue
+			}
+			send = func() error {
+				return o.retrySend(ctx, msgToSend, writeErr)
+			}
+		}
+		if err := o.deliver(ctx, msgs[positions[i]], msgToSend, send); err != nil {
+			failures[positions[i]] = err
+		}
+	}
+
+	if len(failures) > 0 {
+		return &message.BatchError{Errors: failures}
+	}
+	return nil
+}
+
+// Name returns the name of the outbound channel adapter.
+func (c outboundBatchChannel) Name() string {
+	return c.adapter.Name()
+}
+
+// intercept runs the send interceptors of the channel over the message,
+// returning a nil message when an interceptor drops it.
+func (o *OutboundChannelAdapter) intercept(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	var err error
+	for _, interceptor := range o.sendInterceptors {
+		if msg, err = interceptor.Handle(ctx, msg); err != nil {
+			return nil, err
+		}
+		if msg == nil {
+			return nil, nil
+		}
+	}
+	return msg, nil
+}
+
+// afterPublish runs the after interceptors of the channel over a published
+// message, logging their failures.
+func (o *OutboundChannelAdapter) afterPublish(
+	ctx context.Context,
+	msg *message.Message,
+) {
+	for _, interceptor := range o.afterProcessors {
+		result, err := interceptor.Handle(ctx, msg)
+		if err != nil {
+			slog.Error("[outbound-channel-adapter] after interceptor failed",
+				"channel", o.Name(),
+				"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+				"reason", err.Error(),
+			)
+			return
+		}
+		if result == nil {
+			return
+		}
+		msg = result
+	}
+}
+
+// sendWithRetry sends an intercepted message through the publisher channel,
+// retrying the failures by the publish retry policy, and runs the after
+// interceptors once it is published.
+func (o *OutboundChannelAdapter) sendWithRetry(
+	ctx context.Context,
+	msg *message.Message,
+) error {
+	return o.retrySend(ctx, msg, o.outboundAdapter.Send(ctx, msg))
+}
+
+// retrySend retries a failed send of an intercepted message by the publish
+// retry policy, and runs the after interceptors once it is published.
+func (o *OutboundChannelAdapter) retrySend(
+	ctx context.Context,
+	msg *message.Message,
+	err error,
+) error {
+	for attempt := 1; err != nil && o.publishRetry != nil; attempt++ {
+		delay, retry := o.publishRetry.NextDelay(attempt, err)
+		if !retry {
+			break
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = o.outboundAdapter.Send(ctx, msg)
+	}
+	if err == nil {
+		o.afterPublish(ctx, msg)
+	}
+	return err
+}
+
+// sendToFallback sends a message not published to the fallback channel,
+// returning the publication error when no fallback channel is attached.
+func (o *OutboundChannelAdapter) sendToFallback(
+	ctx context.Context,
+	msg *message.Message,
+	publishErr error,
+) error {
+	if o.fallback == nil {
+		return publishErr
+	}
+	if err := o.fallback.Send(context.WithoutCancel(ctx), msg); err != nil {
+		return fmt.Errorf(
+			"[outbound-channel-adapter] failed to send message to fallback channel %s: %w",
+			o.fallback.Name(),
+			errors.Join(publishErr, err),
+		)
+	}
+	slog.Warn("[outbound-channel-adapter] message sent to fallback channel",
+		"channel", o.Name(),
+		"fallbackChannel", o.fallback.Name(),
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"reason", publishErr.Error(),
+	)
+	return nil
+}
+
+// PublishFallbackChannelName returns the configured publish fallback channel
+// name.
+//
+// Returns:
+//   - string: The fallback channel name, empty when disabled
+func (o *OutboundChannelAdapter) PublishFallbackChannelName() string {
+	return o.fallbackChannel
+}
+
+// AttachPublishFallback sets the channel receiving the messages whose
+// publication failed, resolved from the configured fallback channel name.
+//
+// Parameters:
+//   - channel: The fallback publisher channel
+func (o *OutboundChannelAdapter) AttachPublishFallback(channel message.PublisherChannel) {
+	o.fallback = channel
+}
+
+// CorrelationStore returns the configured pending correlation store.
+//
+// Returns:
+//   - handler.CorrelationStore: The correlation store, nil when disabled
+//   - time.Duration: How long a correlation is kept
+func (o *OutboundChannelAdapter) CorrelationStore() (handler.CorrelationStore, time.Duration) {
+	return o.correlationStore, o.correlationTTL
+}
+
+// LateReplyHandler returns the configured late reply handler.
+//
+// Returns:
+//   - func(msg *message.Message): The late reply handler, nil when not set
+func (o *OutboundChannelAdapter) LateReplyHandler() func(msg *message.Message) {
+	return o.lateReplyHandler
+}
+
+// WireTapChannelName returns the configured wire tap channel name.
+//
+// Returns:
+//   - string: The wire tap channel name, empty when disabled
+func (o *OutboundChannelAdapter) WireTapChannelName() string {
+	return o.wireTapChannel
+}
+
+// AttachWireTap sets the channel receiving the copies of the sent messages,
+// resolved from the configured wire tap channel name.
+//
+// Parameters:
+//   - channel: The wire tap publisher channel
+func (o *OutboundChannelAdapter) AttachWireTap(channel message.PublisherChannel) {
+	o.wireTap = handler.NewWireTap(channel, o.wireTapSampling)
+}
+
 // Name returns the name of outbound channel adapter.
 //
 // Returns:
@@ -219,11 +869,36 @@ func (o *OutboundChannelAdapter) publishOnInternalChannel(
 	msg.GetInternalReplyChannel().Send(ctx, resultMessage)
 }
 
+// awaitCorrelatedReply publishes the correlated reply of a request to its
+// internal reply channel, stopping to await it when the request is done.
+//
+// Parameters:
+//   - ctx: Context of the request
+//   - msg: The request message
+//   - replies: Receives the correlated reply
+//   - release: Stops awaiting the reply
+func (o *OutboundChannelAdapter) awaitCorrelatedReply(
+	ctx context.Context,
+	msg *message.Message,
+	replies <-chan *message.Message,
+	release func(),
+) {
+	defer release()
+	select {
+	case reply := <-replies:
+		msg.GetInternalReplyChannel().Send(ctx, reply)
+	case <-ctx.Done():
+	}
+}
+
 // Close closes the outbound channel adapter, releasing associated resources.
 //
 // Returns:
 //   - error: Error if closing the channel fails
 func (o *OutboundChannelAdapter) Close() error {
+	if o.storeAndForward != nil {
+		o.storeAndForward.stop()
+	}
 
 	closableChannel, ok := o.outboundAdapter.(ClosableChannel)
 	if !ok {
@@ -2,12 +2,15 @@ package adapter_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/adapter"
 	"github.com/jeffersonbrasilino/gomes/message/channel"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
 
 // mockPublisherChannel implements message.PublisherChannel for tests.
@@ -109,6 +112,15 @@ func TestOutboundChannelAdapterBuilder_BuildOutboundAdapter(t *testing.T) {
 	}
 }
 
+func TestOutboundChannelAdapterBuilder_BuildOutboundAdapterUnsupportedCompression(t *testing.T) {
+	t.Parallel()
+	builder := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{})
+	builder.WithCompression("br", 0)
+	if _, err := builder.BuildOutboundAdapter(&mockPublisherChannel{}); err == nil {
+		t.Error("Expected error for unsupported content encoding")
+	}
+}
+
 func TestOutboundChannelAdapter_Name(t *testing.T) {
 	t.Parallel()
 	translator := &mockOutboundTranslator{}
@@ -212,3 +224,349 @@ func TestOutboundChannelAdapter_Close(t *testing.T) {
 		}
 	})
 }
+
+func TestOutboundChannelAdapter_SendWithClaimCheck(t *testing.T) {
+	t.Parallel()
+	store := handler.NewFileBlobStore(t.TempDir())
+	pub := &mockPublisherChannel{}
+	outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithClaimCheck(store, 8).
+		BuildOutboundAdapter(pub)
+
+	msg := message.NewMessageBuilder().WithPayload("a payload bigger than the threshold").Build()
+	if err := outbound.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	key := pub.sentMsg.GetHeader().Get(message.HeaderClaimCheck)
+	if key == "" || pub.sentMsg.GetPayload() != nil {
+		t.Fatalf("Expected claim-checked message, got %v", pub.sentMsg.GetPayload())
+	}
+	if _, err := store.Get(context.Background(), key); err != nil {
+		t.Errorf("Expected payload in the blob store, got %v", err)
+	}
+}
+
+func TestOutboundChannelAdapter_SendWithJSONEncoder(t *testing.T) {
+	t.Parallel()
+	pub := &mockPublisherChannel{}
+	outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithJSONEncoder(message.NewJSONEncoder().WithFieldNaming(message.FieldNamingSnakeCase)).
+		BuildOutboundAdapter(pub)
+
+	payload := struct{ OrderId string }{OrderId: "1"}
+	msg := message.NewMessageBuilder().WithPayload(payload).Build()
+	if err := outbound.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	encoded, ok := pub.sentMsg.GetPayload().(json.RawMessage)
+	if !ok || string(encoded) != `{"order_id":"1"}` {
+		t.Errorf("Expected snake_case encoded payload, got %v", pub.sentMsg.GetPayload())
+	}
+	if msg.GetPayload() != payload {
+		t.Error("Expected original message payload to be kept")
+	}
+}
+
+// outboundInterceptorFunc adapts a function to message.MessageHandler.
+type outboundInterceptorFunc func(ctx context.Context, msg *message.Message) (*message.Message, error)
+
+func (f outboundInterceptorFunc) Handle(ctx context.Context, msg *message.Message) (*message.Message, error) {
+	return f(ctx, msg)
+}
+
+func TestOutboundChannelAdapter_SendWithInterceptors(t *testing.T) {
+	t.Parallel()
+	stampTenant := outboundInterceptorFunc(func(ctx context.Context, msg *message.Message) (*message.Message, error) {
+		if msg.GetPayload() == "drop" {
+			return nil, nil
+		}
+		if msg.GetPayload() == "invalid" {
+			return nil, errors.New("invalid payload")
+		}
+		return message.NewMessageBuilderFromMessage(msg).
+			WithCustomHeader(message.HeaderTenantId, "tenant-1").
+			Build(), nil
+	})
+	audited := []string{}
+	audit := outboundInterceptorFunc(func(ctx context.Context, msg *message.Message) (*message.Message, error) {
+		audited = append(audited, msg.GetHeader().Get(message.HeaderTenantId))
+		return msg, nil
+	})
+
+	t.Run("should intercept the published message", func(t *testing.T) {
+		pub := &mockPublisherChannel{}
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithBeforeInterceptors(stampTenant).
+			WithAfterInterceptors(audit).
+			BuildOutboundAdapter(pub)
+
+		if err := outbound.Send(context.Background(), message.NewMessageBuilder().WithPayload("ok").Build()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if pub.sentMsg.GetHeader().Get(message.HeaderTenantId) != "tenant-1" {
+			t.Errorf("Expected tenant header stamped, got %v", pub.sentMsg.GetHeader())
+		}
+		if len(audited) != 1 || audited[0] != "tenant-1" {
+			t.Errorf("Expected published message audited, got %v", audited)
+		}
+	})
+
+	t.Run("should not publish dropped or rejected messages", func(t *testing.T) {
+		pub := &mockPublisherChannel{}
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithBeforeInterceptors(stampTenant).
+			BuildOutboundAdapter(pub)
+
+		if err := outbound.Send(context.Background(), message.NewMessageBuilder().WithPayload("drop").Build()); err != nil {
+			t.Errorf("Expected dropped message without error, got %v", err)
+		}
+		if err := outbound.Send(context.Background(), message.NewMessageBuilder().WithPayload("invalid").Build()); err == nil {
+			t.Error("Expected interceptor error, got nil")
+		}
+		if pub.sentMsg != nil {
+			t.Errorf("Expected no published message, got %v", pub.sentMsg)
+		}
+	})
+
+	t.Run("should not fail the publication when an after interceptor fails", func(t *testing.T) {
+		pub := &mockPublisherChannel{}
+		failing := outboundInterceptorFunc(func(ctx context.Context, msg *message.Message) (*message.Message, error) {
+			return nil, errors.New("audit failed")
+		})
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithAfterInterceptors(failing).
+			BuildOutboundAdapter(pub)
+
+		if err := outbound.Send(context.Background(), message.NewMessageBuilder().WithPayload("ok").Build()); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestOutboundChannelAdapter_SendWithWireTap(t *testing.T) {
+	t.Parallel()
+	pub := &mockPublisherChannel{}
+	audit := channel.NewPointToPointChannel("audit")
+	outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithWireTap("audit").
+		BuildOutboundAdapter(pub)
+	if outbound.WireTapChannelName() != "audit" {
+		t.Fatalf("Expected wire tap channel audit, got %s", outbound.WireTapChannelName())
+	}
+	outbound.AttachWireTap(audit)
+
+	msg := message.NewMessageBuilder().WithMessageId("sent").Build()
+	if err := outbound.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tapped, err := audit.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Expected wire tap copy, got %v", err)
+	}
+	if tapped.GetHeader().Get(message.HeaderMessageId) != "sent" || pub.sentMsg != msg {
+		t.Error("Expected the message sent and copied to the wire tap")
+	}
+}
+
+// flakyPublisherChannel fails the first sends.
+type flakyPublisherChannel struct {
+	failures int
+	sends    int
+}
+
+func (f *flakyPublisherChannel) Send(ctx context.Context, msg *message.Message) error {
+	f.sends++
+	if f.sends <= f.failures {
+		return errors.New("broker unavailable")
+	}
+	return nil
+}
+
+func (f *flakyPublisherChannel) Name() string {
+	return "flaky"
+}
+
+func TestOutboundChannelAdapter_SendWithPublishRetry(t *testing.T) {
+	t.Parallel()
+	t.Run("should retry transient failures", func(t *testing.T) {
+		t.Parallel()
+		pub := &flakyPublisherChannel{failures: 2}
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithPublishRetry(handler.NewFixedRetryPolicy(1, 1)).
+			BuildOutboundAdapter(pub)
+
+		if err := outbound.Send(context.Background(), message.NewMessageBuilder().Build()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if pub.sends != 3 {
+			t.Errorf("Expected 3 sends, got %d", pub.sends)
+		}
+	})
+
+	t.Run("should send to the fallback channel when retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+		pub := &flakyPublisherChannel{failures: 10}
+		fallback := &mockPublisherChannel{}
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithPublishRetry(handler.NewFixedRetryPolicy(1), "buffer").
+			BuildOutboundAdapter(pub)
+		if outbound.PublishFallbackChannelName() != "buffer" {
+			t.Fatalf("Expected fallback channel buffer, got %s", outbound.PublishFallbackChannelName())
+		}
+		outbound.AttachPublishFallback(fallback)
+
+		msg := message.NewMessageBuilder().WithMessageId("buffered").Build()
+		if err := outbound.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if pub.sends != 2 || fallback.sentMsg != msg {
+			t.Errorf("Expected 2 sends and the message buffered, got %d sends", pub.sends)
+		}
+	})
+
+	t.Run("should return the error without fallback channel", func(t *testing.T) {
+		t.Parallel()
+		pub := &flakyPublisherChannel{failures: 10}
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithPublishRetry(handler.NewFixedRetryPolicy(1)).
+			BuildOutboundAdapter(pub)
+
+		if err := outbound.Send(context.Background(), message.NewMessageBuilder().Build()); err == nil {
+			t.Error("Expected publication error")
+		}
+	})
+}
+
+func TestOutboundChannelAdapter_SendInBatch(t *testing.T) {
+	t.Parallel()
+	pub := &mockPublisherChannel{}
+	outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		BuildOutboundAdapter(pub)
+	batch := message.NewBatch()
+	msg := message.NewMessageBuilder().WithMessageId("batched").Build()
+
+	if err := outbound.Send(batch.Context(context.Background(), 0), msg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pub.sentMsg != nil {
+		t.Fatal("Expected message to be collected instead of sent")
+	}
+	if failures := batch.Flush(context.Background()); len(failures) != 0 {
+		t.Fatalf("Expected no failure, got %v", failures)
+	}
+	if pub.sentMsg != msg {
+		t.Error("Expected message to be sent on flush")
+	}
+}
+
+// flakyBatchChannel fails every message of its batches.
+type flakyBatchChannel struct {
+	flakyPublisherChannel
+	batches int
+}
+
+func (f *flakyBatchChannel) SendBatch(ctx context.Context, msgs []*message.Message) error {
+	f.batches++
+	failures := map[int]error{}
+	for i := range msgs {
+		failures[i] = errors.New("broker unavailable")
+	}
+	return &message.BatchError{Errors: failures}
+}
+
+func TestOutboundChannelAdapter_SendInBatchWithPublishRetry(t *testing.T) {
+	t.Parallel()
+	t.Run("should retry the failed messages and run the after interceptors", func(t *testing.T) {
+		t.Parallel()
+		pub := &flakyBatchChannel{}
+		published := 0
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithPublishRetry(handler.NewFixedRetryPolicy(1)).
+			WithAfterInterceptors(outboundInterceptorFunc(
+				func(ctx context.Context, msg *message.Message) (*message.Message, error) {
+					published++
+					return msg, nil
+				},
+			)).
+			BuildOutboundAdapter(pub)
+		batch := message.NewBatch()
+		for i := range 2 {
+			outbound.Send(batch.Context(context.Background(), i), message.NewMessageBuilder().Build())
+		}
+
+		if failures := batch.Flush(context.Background()); len(failures) != 0 {
+			t.Fatalf("Expected no failure, got %v", failures)
+		}
+		if pub.batches != 1 || pub.sends != 2 || published != 2 {
+			t.Errorf("Expected 1 batch, 2 retries and 2 published messages, got %d, %d and %d",
+				pub.batches, pub.sends, published)
+		}
+	})
+
+	t.Run("should send to the fallback channel when retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+		pub := &flakyBatchChannel{flakyPublisherChannel: flakyPublisherChannel{failures: 10}}
+		fallback := &mockPublisherChannel{}
+		outbound, _ := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+			WithPublishRetry(handler.NewFixedRetryPolicy(1), "buffer").
+			BuildOutboundAdapter(pub)
+		outbound.AttachPublishFallback(fallback)
+		batch := message.NewBatch()
+		msg := message.NewMessageBuilder().WithMessageId("buffered").Build()
+		outbound.Send(batch.Context(context.Background(), 0), msg)
+
+		if failures := batch.Flush(context.Background()); len(failures) != 0 {
+			t.Fatalf("Expected no failure, got %v", failures)
+		}
+		if fallback.sentMsg != msg {
+			t.Error("Expected the message sent to the fallback channel")
+		}
+	})
+}
+
+func TestOutboundChannelAdapter_SendWithReplyCorrelator(t *testing.T) {
+	t.Parallel()
+	correlator := handler.NewReplyCorrelator("instance-a")
+	pubChan := &mockPublisherChannel{}
+	adapterInstance, _ := adapter.NewOutboundChannelAdapterBuilder(
+		"ref", "chan", &mockOutboundTranslator{},
+	).
+		WithReplyChannelName("shared-replies").
+		WithReplyCorrelator(correlator).
+		BuildOutboundAdapter(pubChan)
+
+	internalChannel := channel.NewPointToPointChannel("internalChan")
+	msg := message.NewMessageBuilder().
+		WithMessageType(message.Command).
+		WithCorrelationId("corr-1").
+		WithPayload("payload").
+		WithInternalReplyChannel(internalChannel).
+		Build()
+
+	ctx := message.ContextWithReplyExpected(context.Background())
+	if err := adapterInstance.Send(ctx, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pubChan.sentMsg.GetHeader().Get(message.HeaderReplyInstanceId) != "instance-a" {
+		t.Fatal("expected the request to carry the instance id")
+	}
+
+	reply := message.NewMessageBuilder().
+		WithMessageType(message.Document).
+		WithCorrelationId("corr-1").
+		WithCustomHeader(message.HeaderCausationId, msg.GetHeader().Get(message.HeaderMessageId)).
+		WithCustomHeader(message.HeaderReplyInstanceId, "instance-a").
+		WithPayload("reply").
+		Build()
+	correlator.Handle(context.Background(), reply)
+
+	received, err := internalChannel.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.GetPayload() != "reply" {
+		t.Errorf("expected the correlated reply, got %v", received.GetPayload())
+	}
+}
@@ -0,0 +1,489 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/internal/jsonfile"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// defaultFlushInterval is the default interval between the flushes of a
+// store and forward buffer.
+const defaultFlushInterval = time.Second
+
+// defaultMaxFlushAttempts is the default amount of flushes a buffered message
+// fails before it is discarded, once a later message is published.
+const defaultMaxFlushAttempts = 5
+
+// BufferedMessage is an outbound message kept until its broker recovers. The
+// message is kept as intercepted by the send interceptors of the channel,
+// ready to be published.
+type BufferedMessage struct {
+	Id      string            `json:"id"`
+	Headers map[string]string `json:"headers"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
+// MessageBuffer defines the contract for the local durable buffers keeping
+// the outbound messages of a channel during broker outages, in order.
+type MessageBuffer interface {
+	// Append adds a message to the end of the buffer.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - buffered: The buffered message
+	//
+	// Returns:
+	//   - error: Error if the buffer operation fails
+	Append(ctx context.Context, buffered BufferedMessage) error
+	// Delete removes a message once it is published.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - id: The buffered message id
+	//
+	// Returns:
+	//   - error: Error if the buffer operation fails
+	Delete(ctx context.Context, id string) error
+	// Pending returns the buffered messages in append order.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//
+	// Returns:
+	//   - []BufferedMessage: The buffered messages
+	//   - error: Error if the buffer operation fails
+	Pending(ctx context.Context) ([]BufferedMessage, error)
+}
+
+// storeAndForward buffers the messages an outbound channel adapter fails to
+// publish and flushes them in background.
+type storeAndForward struct {
+	adapter     *OutboundChannelAdapter
+	buffer      MessageBuffer
+	interval    time.Duration
+	maxAttempts int
+	failures    map[string]int // used by the flusher only
+	mu          sync.RWMutex
+	backlog     bool
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// newStoreAndForward creates the store and forward of an adapter, starting
+// its flusher. Messages buffered by previous processes are flushed first.
+func newStoreAndForward(
+	adapter *OutboundChannelAdapter,
+	buffer MessageBuffer,
+	interval time.Duration,
+	maxAttempts int,
+) (*storeAndForward, error) {
+	pending, err := buffer.Pending(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("[store-and-forward] failed to load buffered messages: %w", err)
+	}
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxFlushAttempts
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &storeAndForward{
+		adapter:     adapter,
+		buffer:      buffer,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		failures:    map[string]int{},
+		backlog:     len(pending) > 0,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s, nil
+}
+
+// publish sends the intercepted message, buffering it when the send fails or
+// when older messages are still buffered.
+func (s *storeAndForward) publish(
+	ctx context.Context,
+	msg *message.Message,
+	send func() error,
+) error {
+	s.mu.RLock()
+	if !s.backlog {
+		err := send()
+		s.mu.RUnlock()
+		if err == nil {
+			return nil
+		}
+		slog.Warn("[store-and-forward] buffering message after publish failure",
+			"channel", s.adapter.Name(),
+			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"reason", err.Error(),
+		)
+	} else {
+		s.mu.RUnlock()
+	}
+
+	payload, err := json.Marshal(msg.GetPayload())
+	if err != nil {
+		return fmt.Errorf("[store-and-forward] failed to encode payload: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := msg.GetHeader().Get(message.HeaderMessageId)
+	err = s.buffer.Append(ctx, BufferedMessage{
+		Id:      id,
+		Headers: msg.GetHeader().All(),
+		Payload: payload,
+	})
+	if err != nil {
+		return fmt.Errorf("[store-and-forward] failed to buffer message %s: %w", id, err)
+	}
+	s.backlog = true
+	return nil
+}
+
+// buffering reports whether older messages are buffered, so new messages are
+// buffered too.
+func (s *storeAndForward) buffering() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.backlog
+}
+
+// run flushes the buffer every interval until stopped.
+func (s *storeAndForward) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+// flush publishes the buffered messages in order, stopping at the first
+// failure so the order is preserved. A message failing maxAttempts flushes is
+// skipped once a later message is published, which shows the broker is
+// available, and discarded: sent to the publish fallback channel when
+// attached, dropped otherwise. So a message the broker always rejects does
+// not block the channel.
+//
+// The lock is held only to load the buffered messages, not while they are
+// sent, so publishers buffer their messages meanwhile instead of waiting
+// for the broker. Those messages are flushed in a new pass, and the backlog
+// is cleared only once a pass finds the buffer empty.
+func (s *storeAndForward) flush(ctx context.Context) {
+	for {
+		s.mu.Lock()
+		if !s.backlog {
+			s.mu.Unlock()
+			return
+		}
+		pending, err := s.buffer.Pending(ctx)
+		if err != nil {
+			s.mu.Unlock()
+			slog.Error("[store-and-forward] failed to load buffered messages",
+				"channel", s.adapter.Name(),
+				"reason", err.Error(),
+			)
+			return
+		}
+		if len(pending) == 0 {
+			s.backlog = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		if !s.flushPending(ctx, pending) {
+			return
+		}
+	}
+}
+
+// flushPending publishes buffered messages in order, reporting whether all
+// of them were removed from the buffer.
+func (s *storeAndForward) flushPending(
+	ctx context.Context,
+	pending []BufferedMessage,
+) bool {
+	var stuck *BufferedMessage
+	var stuckErr error
+	for _, buffered := range pending {
+		if err := s.forward(ctx, buffered); err != nil {
+			slog.Warn("[store-and-forward] failed to flush buffered message",
+				"channel", s.adapter.Name(),
+				"messageId", buffered.Id,
+				"reason", err.Error(),
+			)
+			if stuck != nil {
+				return false
+			}
+			s.failures[buffered.Id]++
+			if s.failures[buffered.Id] < s.maxAttempts {
+				return false
+			}
+			stuck, stuckErr = &buffered, err
+			continue
+		}
+		if !s.remove(ctx, buffered.Id) {
+			return false
+		}
+		if stuck != nil {
+			if !s.discard(ctx, *stuck, stuckErr) {
+				return false
+			}
+			stuck = nil
+		}
+	}
+	return stuck == nil
+}
+
+// remove deletes a published message from the buffer.
+func (s *storeAndForward) remove(ctx context.Context, id string) bool {
+	delete(s.failures, id)
+	if err := s.buffer.Delete(ctx, id); err != nil {
+		slog.Error("[store-and-forward] failed to delete buffered message",
+			"channel", s.adapter.Name(),
+			"messageId", id,
+			"reason", err.Error(),
+		)
+		return false
+	}
+	return true
+}
+
+// discard removes a message which failed every flush attempt from the
+// buffer, sending it to the publish fallback channel when attached.
+func (s *storeAndForward) discard(
+	ctx context.Context,
+	buffered BufferedMessage,
+	reason error,
+) bool {
+	if s.adapter.fallback != nil {
+		msg, err := buffered.message(ctx)
+		if err == nil {
+			err = s.adapter.sendToFallback(ctx, msg, reason)
+		}
+		if err != nil {
+			slog.Error("[store-and-forward] failed to discard buffered message",
+				"channel", s.adapter.Name(),
+				"messageId", buffered.Id,
+				"reason", err.Error(),
+			)
+			return false
+		}
+	} else {
+		slog.Error("[store-and-forward] dropped buffered message after failed flushes",
+			"channel", s.adapter.Name(),
+			"messageId", buffered.Id,
+			"attempts", s.failures[buffered.Id],
+			"reason", reason.Error(),
+		)
+	}
+	return s.remove(ctx, buffered.Id)
+}
+
+// forward publishes a buffered message through the publisher channel. The
+// message was intercepted before being buffered, so it is not intercepted
+// again.
+func (s *storeAndForward) forward(ctx context.Context, buffered BufferedMessage) error {
+	msg, err := buffered.message(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.adapter.outboundAdapter.Send(ctx, msg); err != nil {
+		return err
+	}
+	s.adapter.afterPublish(ctx, msg)
+	return nil
+}
+
+// message rebuilds the buffered message.
+func (b BufferedMessage) message(ctx context.Context) (*message.Message, error) {
+	builder, err := message.NewMessageBuilderFromHeaders(b.Headers)
+	if err != nil {
+		return nil, err
+	}
+	return builder.WithPayload(b.Payload).WithContext(ctx).Build(), nil
+}
+
+// stop stops the flusher. Buffered messages are flushed by the next process
+// using the buffer.
+func (s *storeAndForward) stop() {
+	s.cancel()
+	<-s.done
+}
+
+// compactMinRecords is the number of records of a buffer file below which
+// the file is not compacted.
+const compactMinRecords = 1000
+
+// fileMessageBuffer is a MessageBuffer persisted in an append-only log of
+// JSON lines: each append and delete writes and syncs a single record, and
+// the log is compacted once most of its records are deleted messages.
+type fileMessageBuffer struct {
+	path     string
+	mu       sync.Mutex
+	messages []BufferedMessage
+	records  int
+}
+
+// bufferRecord is a line of the buffer file, recording either an appended
+// message or the id of a deleted one.
+type bufferRecord struct {
+	Append *BufferedMessage `json:"append,omitempty"`
+	Delete string           `json:"delete,omitempty"`
+}
+
+// NewFileMessageBuffer creates a message buffer persisted in a file, loading
+// the messages buffered by previous processes and compacting the file. Each
+// channel must use its own file.
+//
+// Parameters:
+//   - path: the file path, created on the first append
+//
+// Returns:
+//   - *fileMessageBuffer: configured buffer instance
+//   - error: error if the existing file cannot be read or compacted
+func NewFileMessageBuffer(path string) (*fileMessageBuffer, error) {
+	buffer := &fileMessageBuffer{path: path}
+	if err := buffer.load(); err != nil {
+		return nil, fmt.Errorf("[message-buffer] %w", err)
+	}
+	if buffer.records > 0 {
+		if err := buffer.compact(); err != nil {
+			return nil, err
+		}
+	}
+	return buffer, nil
+}
+
+// Append adds a message to the end of the buffer, appending it to the file.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - buffered: the buffered message
+//
+// Returns:
+//   - error: error if the file cannot be written
+func (b *fileMessageBuffer) Append(ctx context.Context, buffered BufferedMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.write(bufferRecord{Append: &buffered}); err != nil {
+		return err
+	}
+	b.messages = append(b.messages, buffered)
+	return nil
+}
+
+// Delete removes a message, appending its deletion to the file.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - id: the buffered message id
+//
+// Returns:
+//   - error: error if the file cannot be written
+func (b *fileMessageBuffer) Delete(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	index := slices.IndexFunc(b.messages, func(buffered BufferedMessage) bool {
+		return buffered.Id == id
+	})
+	if index < 0 {
+		return nil
+	}
+	if err := b.write(bufferRecord{Delete: id}); err != nil {
+		return err
+	}
+	b.messages = slices.Delete(b.messages, index, index+1)
+	if b.records >= compactMinRecords && b.records > 2*len(b.messages) {
+		return b.compact()
+	}
+	return nil
+}
+
+// Pending returns the buffered messages in append order.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - []BufferedMessage: the buffered messages
+//   - error: always nil
+func (b *fileMessageBuffer) Pending(ctx context.Context) ([]BufferedMessage, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.messages), nil
+}
+
+// load replays the records of the buffer file. A file written as a single
+// JSON array of messages by previous versions is loaded as their appends.
+func (b *fileMessageBuffer) load() error {
+	return jsonfile.LoadLines(b.path, func(line json.RawMessage) error {
+		if len(line) > 0 && line[0] == '[' {
+			var messages []BufferedMessage
+			if err := json.Unmarshal(line, &messages); err != nil {
+				return err
+			}
+			b.messages = append(b.messages, messages...)
+			b.records += len(messages) + 1
+			return nil
+		}
+		var record bufferRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		b.records++
+		if record.Append != nil {
+			b.messages = append(b.messages, *record.Append)
+			return nil
+		}
+		b.messages = slices.DeleteFunc(b.messages, func(buffered BufferedMessage) bool {
+			return buffered.Id == record.Delete
+		})
+		return nil
+	})
+}
+
+// write appends a record to the buffer file. The caller holds the lock.
+func (b *fileMessageBuffer) write(record bufferRecord) error {
+	if err := jsonfile.AppendLine(b.path, record); err != nil {
+		return fmt.Errorf("[message-buffer] %w", err)
+	}
+	b.records++
+	return nil
+}
+
+// compact atomically rewrites the buffer file with the records of the
+// buffered messages only. The caller holds the lock.
+func (b *fileMessageBuffer) compact() error {
+	var data []byte
+	for _, buffered := range b.messages {
+		line, err := json.Marshal(bufferRecord{Append: &buffered})
+		if err != nil {
+			return fmt.Errorf("[message-buffer] failed to encode message %s: %w", buffered.Id, err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := jsonfile.WriteFile(b.path, data); err != nil {
+		return fmt.Errorf("[message-buffer] %w", err)
+	}
+	b.records = len(b.messages)
+	return nil
+}
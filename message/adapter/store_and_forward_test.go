@@ -0,0 +1,307 @@
+package adapter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+)
+
+// outageChannel fails every send while the broker is down.
+type outageChannel struct {
+	mu       sync.Mutex
+	down     bool
+	rejected string
+	sent     []string
+}
+
+func (o *outageChannel) Send(ctx context.Context, msg *message.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.down {
+		return errors.New("broker unavailable")
+	}
+	if id := msg.GetHeader().Get(message.HeaderMessageId); id == o.rejected {
+		return errors.New("message rejected")
+	}
+	o.sent = append(o.sent, msg.GetHeader().Get(message.HeaderMessageId))
+	return nil
+}
+
+func (o *outageChannel) Name() string {
+	return "outage"
+}
+
+func (o *outageChannel) setDown(down bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.down = down
+}
+
+func (o *outageChannel) sentIds() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return slices.Clone(o.sent)
+}
+
+func TestOutboundChannelAdapter_StoreAndForward(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "orders.buffer.json")
+	buffer, err := adapter.NewFileMessageBuffer(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pub := &outageChannel{down: true}
+	outbound, err := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithStoreAndForward(buffer, 10*time.Millisecond).
+		BuildOutboundAdapter(pub)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer outbound.Close()
+
+	for _, id := range []string{"first", "second"} {
+		msg := message.NewMessageBuilder().WithMessageId(id).WithPayload(map[string]string{"id": id}).Build()
+		if err := outbound.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Expected message buffered, got %v", err)
+		}
+	}
+	reloaded, err := adapter.NewFileMessageBuffer(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pending, _ := reloaded.Pending(context.Background()); len(pending) != 2 {
+		t.Fatalf("Expected 2 messages persisted, got %d", len(pending))
+	}
+
+	pub.setDown(false)
+	if err := outbound.Send(context.Background(), message.NewMessageBuilder().WithMessageId("third").Build()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(pub.sentIds()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sent := pub.sentIds(); !slices.Equal(sent, []string{"first", "second", "third"}) {
+		t.Errorf("Expected messages flushed in order, got %v", sent)
+	}
+	if pending, _ := buffer.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected empty buffer, got %d messages", len(pending))
+	}
+}
+
+// countingInterceptor counts the intercepted messages.
+type countingInterceptor struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (c *countingInterceptor) Handle(ctx context.Context, msg *message.Message) (*message.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+	return msg, nil
+}
+
+func (c *countingInterceptor) intercepted() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+// awaitSent waits until the channel has sent the amount of messages.
+func awaitSent(pub *outageChannel, amount int) []string {
+	deadline := time.Now().Add(time.Second)
+	for len(pub.sentIds()) < amount && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return pub.sentIds()
+}
+
+func TestOutboundChannelAdapter_StoreAndForwardInterceptsOnce(t *testing.T) {
+	t.Parallel()
+	buffer, err := adapter.NewFileMessageBuffer(filepath.Join(t.TempDir(), "buffer.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	interceptor := &countingInterceptor{}
+	pub := &outageChannel{down: true}
+	outbound, err := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithBeforeInterceptors(interceptor).
+		WithStoreAndForward(buffer, 10*time.Millisecond).
+		BuildOutboundAdapter(pub)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer outbound.Close()
+
+	msg := message.NewMessageBuilder().WithMessageId("first").WithPayload(map[string]string{"id": "first"}).Build()
+	if err := outbound.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Expected message buffered, got %v", err)
+	}
+	pub.setDown(false)
+	if sent := awaitSent(pub, 1); !slices.Equal(sent, []string{"first"}) {
+		t.Fatalf("Expected buffered message flushed, got %v", sent)
+	}
+	if got := interceptor.intercepted(); got != 1 {
+		t.Errorf("Expected message intercepted once, got %d", got)
+	}
+}
+
+func TestOutboundChannelAdapter_StoreAndForwardDiscardsRejectedMessage(t *testing.T) {
+	t.Parallel()
+	buffer, err := adapter.NewFileMessageBuffer(filepath.Join(t.TempDir(), "buffer.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pub := &outageChannel{down: true, rejected: "poison"}
+	fallback := &outageChannel{}
+	outbound, err := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithPublishRetry(nil, "fallback").
+		WithStoreAndForward(buffer, 10*time.Millisecond).
+		WithStoreAndForwardMaxAttempts(2).
+		BuildOutboundAdapter(pub)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	outbound.AttachPublishFallback(fallback)
+	defer outbound.Close()
+
+	for _, id := range []string{"poison", "second"} {
+		msg := message.NewMessageBuilder().WithMessageId(id).WithPayload(map[string]string{"id": id}).Build()
+		if err := outbound.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Expected message buffered, got %v", err)
+		}
+	}
+	pub.setDown(false)
+	if sent := awaitSent(pub, 1); !slices.Equal(sent, []string{"second"}) {
+		t.Fatalf("Expected message after the rejected one flushed, got %v", sent)
+	}
+	if sent := awaitSent(fallback, 1); !slices.Equal(sent, []string{"poison"}) {
+		t.Errorf("Expected rejected message sent to the fallback channel, got %v", sent)
+	}
+	if err := outbound.Send(context.Background(), message.NewMessageBuilder().WithMessageId("third").Build()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent := awaitSent(pub, 2); !slices.Equal(sent, []string{"second", "third"}) {
+		t.Errorf("Expected new messages published once the buffer is flushed, got %v", sent)
+	}
+}
+
+// gatedChannel holds every send until its gate is opened.
+type gatedChannel struct {
+	*outageChannel
+	gate chan struct{}
+}
+
+func (g *gatedChannel) Send(ctx context.Context, msg *message.Message) error {
+	select {
+	case <-g.gate:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return g.outageChannel.Send(ctx, msg)
+}
+
+func TestOutboundChannelAdapter_StoreAndForwardBuffersWhileFlushing(t *testing.T) {
+	t.Parallel()
+	buffer, err := adapter.NewFileMessageBuffer(filepath.Join(t.TempDir(), "buffer.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pub := &gatedChannel{outageChannel: &outageChannel{}, gate: make(chan struct{})}
+	buffer.Append(context.Background(), adapter.BufferedMessage{
+		Id:      "first",
+		Headers: message.NewMessageBuilder().WithMessageId("first").Build().GetHeader().All(),
+		Payload: []byte(`{"id":"first"}`),
+	})
+	outbound, err := adapter.NewOutboundChannelAdapterBuilder("ref", "chan", &mockOutboundTranslator{}).
+		WithStoreAndForward(buffer, 10*time.Millisecond).
+		BuildOutboundAdapter(pub)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer outbound.Close()
+
+	// the flusher is blocked sending the first message
+	time.Sleep(30 * time.Millisecond)
+	sent := make(chan error, 1)
+	go func() {
+		sent <- outbound.Send(context.Background(), message.NewMessageBuilder().WithMessageId("second").Build())
+	}()
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Fatalf("Expected message buffered, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the publish not to wait for the flush")
+	}
+
+	close(pub.gate)
+	if sent := awaitSent(pub.outageChannel, 2); !slices.Equal(sent, []string{"first", "second"}) {
+		t.Errorf("Expected messages flushed in order, got %v", sent)
+	}
+}
+
+func TestFileMessageBuffer_ReplaysTheLog(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "buffer.json")
+	buffer, err := adapter.NewFileMessageBuffer(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, id := range []string{"first", "second", "third"} {
+		if err := buffer.Append(ctx, adapter.BufferedMessage{Id: id}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := buffer.Delete(ctx, "second"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// a crash while appending leaves an incomplete last record
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	file.WriteString(`{"append":{"id":"fou`)
+	file.Close()
+
+	reloaded, err := adapter.NewFileMessageBuffer(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pending, _ := reloaded.Pending(ctx)
+	ids := []string{}
+	for _, buffered := range pending {
+		ids = append(ids, buffered.Id)
+	}
+	if !slices.Equal(ids, []string{"first", "third"}) {
+		t.Errorf("Expected the remaining messages in order, got %v", ids)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := bytes.Count(data, []byte("\n")); lines != 2 {
+		t.Errorf("Expected the log compacted to 2 records, got %d", lines)
+	}
+}
+
+func TestFileMessageBuffer_LoadsTheArrayFormat(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "buffer.json")
+	os.WriteFile(path, []byte(`[{"id":"first"},{"id":"second"}]`), 0o600)
+
+	buffer, err := adapter.NewFileMessageBuffer(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pending, _ := buffer.Pending(context.Background()); len(pending) != 2 {
+		t.Errorf("Expected 2 messages loaded, got %d", len(pending))
+	}
+}
@@ -0,0 +1,203 @@
+// Package message provides the batch publishing of the message system.
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// BatchPublisherChannel is implemented by publisher channels writing many
+// messages in a single broker call, e.g. a single Kafka writer call.
+type BatchPublisherChannel interface {
+	PublisherChannel
+	// SendBatch publishes the messages in order. Partial failures are
+	// reported by a *BatchError indexed by the position of the messages.
+	SendBatch(ctx context.Context, messages []*Message) error
+}
+
+// BatchError reports the messages of a batch which failed to be published,
+// by their position in the batch.
+type BatchError struct {
+	Errors map[int]error
+}
+
+// Error returns the amount of failed messages and the first failure.
+func (e *BatchError) Error() string {
+	indexes := slices.Sorted(maps.Keys(e.Errors))
+	if len(indexes) == 0 {
+		return "[batch] no message failed"
+	}
+	return fmt.Sprintf(
+		"[batch] %d messages failed, message %d: %v",
+		len(indexes),
+		indexes[0],
+		e.Errors[indexes[0]],
+	)
+}
+
+// Unwrap returns the errors of the failed messages.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// batchContextKey is the context key holding the position of a message in
+// the batch being published.
+type batchContextKey struct{}
+
+// batchPosition is the position of a message in a batch.
+type batchPosition struct {
+	batch *Batch
+	index int
+}
+
+// batchEntry is a message collected in a batch.
+type batchEntry struct {
+	index   int
+	message *Message
+}
+
+// Batch collects the messages published with its contexts at the publisher
+// channels, instead of sending them one by one, so they are written by
+// channel in a single broker call on Flush.
+type Batch struct {
+	mu       sync.Mutex
+	channels []PublisherChannel
+	entries  map[PublisherChannel][]batchEntry
+}
+
+// NewBatch creates an empty batch.
+//
+// Returns:
+//   - *Batch: the batch
+func NewBatch() *Batch {
+	return &Batch{entries: map[PublisherChannel][]batchEntry{}}
+}
+
+// Context returns a copy of the context publishing the message at the given
+// position of the batch.
+//
+// Parameters:
+//   - ctx: the parent context
+//   - index: the position of the message in the batch
+//
+// Returns:
+//   - context.Context: the context collecting the message in the batch
+func (b *Batch) Context(ctx context.Context, index int) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, batchContextKey{}, batchPosition{b, index})
+}
+
+// CollectInBatch collects a message about to be sent through a publisher
+// channel in the batch of the context, if any.
+//
+// Parameters:
+//   - ctx: the context of the send
+//   - channel: the publisher channel writing the message
+//   - msg: the message
+//
+// Returns:
+//   - bool: true if the message was collected, false if the context has no
+//     batch and the message must be sent
+func CollectInBatch(ctx context.Context, channel PublisherChannel, msg *Message) bool {
+	if ctx == nil {
+		return false
+	}
+	position, ok := ctx.Value(batchContextKey{}).(batchPosition)
+	if !ok {
+		return false
+	}
+
+	position.batch.mu.Lock()
+	defer position.batch.mu.Unlock()
+	if _, ok := position.batch.entries[channel]; !ok {
+		position.batch.channels = append(position.batch.channels, channel)
+	}
+	position.batch.entries[channel] = append(
+		position.batch.entries[channel],
+		batchEntry{position.index, msg},
+	)
+	return true
+}
+
+// Flush writes the collected messages, in a single call per channel when the
+// channel is a BatchPublisherChannel, and empties the batch.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - map[int]error: the errors of the failed messages by batch position
+func (b *Batch) Flush(ctx context.Context) map[int]error {
+	b.mu.Lock()
+	channels, entries := b.channels, b.entries
+	b.channels, b.entries = nil, map[PublisherChannel][]batchEntry{}
+	b.mu.Unlock()
+
+	failures := map[int]error{}
+	for _, channel := range channels {
+		collected := entries[channel]
+		messages := make([]*Message, len(collected))
+		for i, entry := range collected {
+			messages[i] = entry.message
+		}
+		for i, err := range PublishBatch(ctx, channel, messages) {
+			failures[collected[i].index] = err
+		}
+	}
+	return failures
+}
+
+// PublishBatch writes the messages through a publisher channel, in a single
+// call when the channel is a BatchPublisherChannel, one by one otherwise.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - channel: the publisher channel writing the messages
+//   - messages: the messages, in order
+//
+// Returns:
+//   - map[int]error: the errors of the failed messages by position
+func PublishBatch(
+	ctx context.Context,
+	channel PublisherChannel,
+	messages []*Message,
+) map[int]error {
+	failures := map[int]error{}
+	batchChannel, ok := channel.(BatchPublisherChannel)
+	if !ok {
+		for i, msg := range messages {
+			if err := channel.Send(ctx, msg); err != nil {
+				failures[i] = err
+			}
+		}
+		return failures
+	}
+
+	err := batchChannel.SendBatch(ctx, messages)
+	if err == nil {
+		return failures
+	}
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		for i := range messages {
+			failures[i] = err
+		}
+		return failures
+	}
+	for i, err := range batchErr.Errors {
+		if i >= 0 && i < len(messages) {
+			failures[i] = err
+		}
+	}
+	return failures
+}
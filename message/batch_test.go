@@ -0,0 +1,120 @@
+package message_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+type batchPublisherChannel struct {
+	batches [][]*message.Message
+	sent    []*message.Message
+	err     error
+}
+
+func (c *batchPublisherChannel) Name() string { return "batch" }
+
+func (c *batchPublisherChannel) Send(ctx context.Context, msg *message.Message) error {
+	c.sent = append(c.sent, msg)
+	return c.err
+}
+
+func (c *batchPublisherChannel) SendBatch(ctx context.Context, msgs []*message.Message) error {
+	c.batches = append(c.batches, msgs)
+	return c.err
+}
+
+type singlePublisherChannel struct {
+	sent []*message.Message
+}
+
+func (c *singlePublisherChannel) Name() string { return "single" }
+
+func (c *singlePublisherChannel) Send(ctx context.Context, msg *message.Message) error {
+	c.sent = append(c.sent, msg)
+	if len(c.sent) == 2 {
+		return errors.New("send failed")
+	}
+	return nil
+}
+
+func TestCollectInBatch(t *testing.T) {
+	t.Parallel()
+	t.Run("should not collect without a batch in the context", func(t *testing.T) {
+		channel := &batchPublisherChannel{}
+		msg := message.NewMessageBuilder().Build()
+		if message.CollectInBatch(context.Background(), channel, msg) {
+			t.Error("expected message not to be collected")
+		}
+	})
+
+	t.Run("should collect messages by channel", func(t *testing.T) {
+		batch := message.NewBatch()
+		batchChannel := &batchPublisherChannel{}
+		singleChannel := &singlePublisherChannel{}
+		for i := range 3 {
+			msg := message.NewMessageBuilder().Build()
+			if !message.CollectInBatch(batch.Context(context.Background(), i), batchChannel, msg) {
+				t.Fatal("expected message to be collected")
+			}
+			message.CollectInBatch(batch.Context(context.Background(), i), singleChannel, msg)
+		}
+
+		failures := batch.Flush(context.Background())
+		if len(batchChannel.batches) != 1 || len(batchChannel.batches[0]) != 3 {
+			t.Errorf("expected a single batch of 3 messages, got %v", batchChannel.batches)
+		}
+		if len(singleChannel.sent) != 3 {
+			t.Errorf("expected 3 messages sent one by one, got %d", len(singleChannel.sent))
+		}
+		if len(failures) != 1 || failures[1] == nil {
+			t.Errorf("expected the second message to fail, got %v", failures)
+		}
+		if failures := batch.Flush(context.Background()); len(failures) != 0 {
+			t.Errorf("expected an empty batch after flush, got %v", failures)
+		}
+	})
+
+	t.Run("should report a batch failure for every message", func(t *testing.T) {
+		batch := message.NewBatch()
+		channel := &batchPublisherChannel{err: errors.New("broker unavailable")}
+		for _, i := range []int{4, 7} {
+			message.CollectInBatch(batch.Context(context.Background(), i), channel, message.NewMessageBuilder().Build())
+		}
+
+		failures := batch.Flush(context.Background())
+		if len(failures) != 2 || failures[4] == nil || failures[7] == nil {
+			t.Errorf("expected messages 4 and 7 to fail, got %v", failures)
+		}
+	})
+
+	t.Run("should map partial failures to the batch positions", func(t *testing.T) {
+		batch := message.NewBatch()
+		partialErr := errors.New("record too large")
+		channel := &batchPublisherChannel{
+			err: &message.BatchError{Errors: map[int]error{1: partialErr}},
+		}
+		for _, i := range []int{4, 7} {
+			message.CollectInBatch(batch.Context(context.Background(), i), channel, message.NewMessageBuilder().Build())
+		}
+
+		failures := batch.Flush(context.Background())
+		if len(failures) != 1 || !errors.Is(failures[7], partialErr) {
+			t.Errorf("expected message 7 to fail, got %v", failures)
+		}
+	})
+}
+
+func TestBatchError(t *testing.T) {
+	t.Parallel()
+	cause := errors.New("record too large")
+	err := &message.BatchError{Errors: map[int]error{3: errors.New("other"), 1: cause}}
+	if !errors.Is(err, cause) {
+		t.Error("expected batch error to wrap the message errors")
+	}
+	if err.Error() != "[batch] 2 messages failed, message 1: record too large" {
+		t.Errorf("unexpected error message: %s", err.Error())
+	}
+}
@@ -0,0 +1,132 @@
+// Package message provides payload compression for the message system.
+//
+// The contentEncoding header names the compression of a serialized payload.
+// Channel message translators compress the payload of outbound messages
+// carrying the header and transparently decompress inbound ones.
+//
+// The compression implementation supports:
+// - gzip content encoding
+// - zstd content encoding
+// - Identity (no) content encoding
+package message
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content encoding constants.
+const (
+	ContentEncodingIdentity = ""
+	ContentEncodingGzip     = "gzip"
+	ContentEncodingZstd     = "zstd"
+)
+
+// zstdEncoder and zstdDecoder are shared by every message: their EncodeAll
+// and DecodeAll are safe for concurrent use, and creating them allocates
+// buffers and starts goroutines.
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+// ValidateContentEncoding checks whether a content encoding is supported, so
+// it can be validated when a channel is configured rather than per message.
+//
+// Parameters:
+//   - encoding: the content encoding
+//
+// Returns:
+//   - error: error if the encoding is not supported
+func ValidateContentEncoding(encoding string) error {
+	switch encoding {
+	case ContentEncodingIdentity, ContentEncodingGzip, ContentEncodingZstd:
+		return nil
+	default:
+		return fmt.Errorf("[compression] unsupported content encoding %q", encoding)
+	}
+}
+
+// CompressPayload compresses a serialized payload with the given content
+// encoding.
+//
+// Parameters:
+//   - encoding: the content encoding
+//   - data: the serialized payload
+//
+// Returns:
+//   - []byte: the compressed payload
+//   - error: error if the encoding is not supported or compression fails
+func CompressPayload(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case ContentEncodingIdentity:
+		return data, nil
+	case ContentEncodingGzip:
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write(data); err != nil {
+			return nil, fmt.Errorf("[compression] gzip failed: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("[compression] gzip failed: %w", err)
+		}
+		return buffer.Bytes(), nil
+	case ContentEncodingZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return nil, fmt.Errorf("[compression] zstd failed: %w", err)
+		}
+		return encoder.EncodeAll(data, nil), nil
+	default:
+		return nil, ValidateContentEncoding(encoding)
+	}
+}
+
+// DecompressPayload decompresses a payload compressed with the given content
+// encoding.
+//
+// Parameters:
+//   - encoding: the content encoding
+//   - data: the compressed payload
+//
+// Returns:
+//   - []byte: the serialized payload
+//   - error: error if the encoding is not supported or the data is invalid
+func DecompressPayload(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case ContentEncodingIdentity:
+		return data, nil
+	case ContentEncodingGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("[compression] gunzip failed: %w", err)
+		}
+		defer reader.Close()
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("[compression] gunzip failed: %w", err)
+		}
+		return decompressed, nil
+	case ContentEncodingZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("[compression] zstd failed: %w", err)
+		}
+		decompressed, err := decoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("[compression] zstd failed: %w", err)
+		}
+		return decompressed, nil
+	default:
+		return nil, ValidateContentEncoding(encoding)
+	}
+}
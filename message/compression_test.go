@@ -0,0 +1,54 @@
+package message_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestCompressPayload(t *testing.T) {
+	t.Parallel()
+	data := bytes.Repeat([]byte(`{"orderId":"1"}`), 100)
+
+	for _, encoding := range []string{
+		message.ContentEncodingIdentity,
+		message.ContentEncodingGzip,
+		message.ContentEncodingZstd,
+	} {
+		compressed, err := message.CompressPayload(encoding, data)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", encoding, err)
+		}
+		if encoding != message.ContentEncodingIdentity && len(compressed) >= len(data) {
+			t.Errorf("%q: expected compressed payload, got %d bytes", encoding, len(compressed))
+		}
+		decompressed, err := message.DecompressPayload(encoding, compressed)
+		if err != nil || !bytes.Equal(decompressed, data) {
+			t.Errorf("%q: expected original payload, got %d bytes (%v)", encoding, len(decompressed), err)
+		}
+	}
+
+	if _, err := message.CompressPayload("br", data); err == nil {
+		t.Error("expected error for unsupported encoding")
+	}
+	if _, err := message.DecompressPayload(message.ContentEncodingGzip, data); err == nil {
+		t.Error("expected error for invalid gzip data")
+	}
+}
+
+func TestValidateContentEncoding(t *testing.T) {
+	t.Parallel()
+	for _, encoding := range []string{
+		message.ContentEncodingIdentity,
+		message.ContentEncodingGzip,
+		message.ContentEncodingZstd,
+	} {
+		if err := message.ValidateContentEncoding(encoding); err != nil {
+			t.Errorf("%q: unexpected error: %v", encoding, err)
+		}
+	}
+	if err := message.ValidateContentEncoding("br"); err == nil {
+		t.Error("expected error for unsupported encoding")
+	}
+}
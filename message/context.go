@@ -0,0 +1,173 @@
+// Package message provides context utilities for the message system.
+package message
+
+import "context"
+
+// headerContextKey is the context key holding the header of the message being
+// handled.
+type headerContextKey struct{}
+
+// ContextWithHeader returns a copy of the context carrying the header of the
+// message being handled, so messages sent while handling it can inherit its
+// continuity metadata (correlation, causation and tenant).
+//
+// Parameters:
+//   - ctx: the parent context
+//   - header: the header of the message being handled
+//
+// Returns:
+//   - context.Context: the context carrying the header
+func ContextWithHeader(ctx context.Context, header Header) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, headerContextKey{}, header)
+}
+
+// HeaderFromContext returns the header of the message being handled, if any.
+//
+// Parameters:
+//   - ctx: the context of the message being handled
+//
+// Returns:
+//   - Header: the message header
+//   - bool: true if the context carries a message header
+func HeaderFromContext(ctx context.Context) (Header, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	header, ok := ctx.Value(headerContextKey{}).(Header)
+	return header, ok
+}
+
+// messageContextKey is the context key holding the message being handled.
+type messageContextKey struct{}
+
+// ContextWithMessage returns a copy of the context carrying the message being
+// handled and its header, so handlers processed concurrently read the
+// metadata of their own message instead of state shared by the handler.
+//
+// Parameters:
+//   - ctx: the parent context
+//   - msg: the message being handled
+//
+// Returns:
+//   - context.Context: the context carrying the message
+func ContextWithMessage(ctx context.Context, msg *Message) context.Context {
+	ctx = ContextWithHeader(ctx, msg.GetHeader())
+	return context.WithValue(ctx, messageContextKey{}, msg)
+}
+
+// MessageFromContext returns the message being handled, if any.
+//
+// Parameters:
+//   - ctx: the context of the message being handled
+//
+// Returns:
+//   - *Message: the message being handled
+//   - bool: true if the context carries a message
+func MessageFromContext(ctx context.Context) (*Message, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	msg, ok := ctx.Value(messageContextKey{}).(*Message)
+	return msg, ok
+}
+
+// replyExpectedContextKey is the context key marking requests whose caller
+// awaits a reply.
+type replyExpectedContextKey struct{}
+
+// ContextWithReplyExpected returns a copy of the context marking that the
+// caller awaits the reply of the message sent with it, e.g. a command sent
+// synchronously, as opposed to a message published asynchronously.
+//
+// Parameters:
+//   - ctx: the parent context
+//
+// Returns:
+//   - context.Context: the marked context
+func ContextWithReplyExpected(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, replyExpectedContextKey{}, true)
+}
+
+// ReplyExpected reports whether the caller awaits the reply of the message
+// sent with the context.
+//
+// Parameters:
+//   - ctx: the context of the sent message
+//
+// Returns:
+//   - bool: true if the caller awaits the reply
+func ReplyExpected(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	expected, _ := ctx.Value(replyExpectedContextKey{}).(bool)
+	return expected
+}
+
+// Acknowledger settles the message being handled on its broker, replacing the
+// acknowledgment made by the consumer after processing.
+type Acknowledger interface {
+	// Ack marks the message as processed, preventing its redelivery.
+	Ack() error
+	// Nack marks the message as not processed, requeueing it for redelivery
+	// when requeue is true.
+	Nack(requeue bool) error
+	// Reject discards the message without processing it.
+	Reject() error
+}
+
+// DeferrableAcknowledger is implemented by acknowledgers whose message can be
+// settled after its handling returns, e.g. by a handler holding the message
+// until it is released downstream.
+type DeferrableAcknowledger interface {
+	Acknowledger
+	// Defer keeps the message unsettled when its handling returns, leaving its
+	// settlement to a later Ack, Nack or Reject.
+	Defer()
+}
+
+// acknowledgerContextKey is the context key holding the acknowledger of the
+// message being handled.
+type acknowledgerContextKey struct{}
+
+// ContextWithAcknowledger returns a copy of the context carrying the
+// acknowledger of the message being handled.
+//
+// Parameters:
+//   - ctx: the parent context
+//   - acknowledger: the acknowledger of the message being handled
+//
+// Returns:
+//   - context.Context: the context carrying the acknowledger
+func ContextWithAcknowledger(
+	ctx context.Context,
+	acknowledger Acknowledger,
+) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, acknowledgerContextKey{}, acknowledger)
+}
+
+// AcknowledgerFromContext returns the acknowledger of the message being
+// handled, if any.
+//
+// Parameters:
+//   - ctx: the context of the message being handled
+//
+// Returns:
+//   - Acknowledger: the message acknowledger
+//   - bool: true if the context carries an acknowledger
+func AcknowledgerFromContext(ctx context.Context) (Acknowledger, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	acknowledger, ok := ctx.Value(acknowledgerContextKey{}).(Acknowledger)
+	return acknowledger, ok
+}
@@ -0,0 +1,57 @@
+package message_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestHeaderFromContext(t *testing.T) {
+	t.Run("should return header stored in context", func(t *testing.T) {
+		t.Parallel()
+		header := message.NewHeader(map[string]string{
+			message.HeaderCorrelationId: "corr-1",
+		})
+		ctx := message.ContextWithHeader(context.Background(), header)
+
+		got, ok := message.HeaderFromContext(ctx)
+		if !ok {
+			t.Fatal("expected header in context")
+		}
+		if got.Get(message.HeaderCorrelationId) != "corr-1" {
+			t.Errorf("expected correlation id 'corr-1', got '%s'", got.Get(message.HeaderCorrelationId))
+		}
+	})
+
+	t.Run("should report missing header", func(t *testing.T) {
+		t.Parallel()
+		if _, ok := message.HeaderFromContext(context.Background()); ok {
+			t.Error("expected no header in context")
+		}
+	})
+}
+
+func TestMessageFromContext(t *testing.T) {
+	t.Run("should return message and header stored in context", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().WithCorrelationId("corr-1").Build()
+		ctx := message.ContextWithMessage(context.Background(), msg)
+
+		got, ok := message.MessageFromContext(ctx)
+		if !ok || got != msg {
+			t.Fatalf("expected message in context, got %v", got)
+		}
+		header, ok := message.HeaderFromContext(ctx)
+		if !ok || header.Get(message.HeaderCorrelationId) != "corr-1" {
+			t.Errorf("expected header of the message in context, got %v", header)
+		}
+	})
+
+	t.Run("should report missing message", func(t *testing.T) {
+		t.Parallel()
+		if _, ok := message.MessageFromContext(context.Background()); ok {
+			t.Error("expected no message in context")
+		}
+	})
+}
@@ -0,0 +1,395 @@
+// Package endpoint implements the backfill coordinator for large-scale
+// reprocessing of historical messages.
+//
+// The BackfillCoordinator implementation supports:
+// - Parallel replay across N workers selected by key hash
+// - Per-key ordering preservation (same key always handled by the same worker)
+// - Throttling to limit the impact of the replay on live traffic
+// - Replay completion detection through an idle timeout
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// BackfillCoordinatorBuilder is responsible for building BackfillCoordinator
+// instances. referenceName identifies the input channel to be replayed.
+type BackfillCoordinatorBuilder struct {
+	referenceName      string
+	beforeInterceptors []message.MessageHandler
+	afterInterceptors  []message.MessageHandler
+}
+
+// BackfillCoordinator replays the messages of an input channel across multiple
+// workers. Messages sharing the same key are always dispatched to the same
+// worker, so their relative order is preserved.
+type BackfillCoordinator struct {
+	referenceName                 string
+	gateway                       *Gateway
+	inboundChannelAdapter         InboundChannelAdapter
+	amountOfWorkers               int
+	keyExtractor                  func(msg *message.Message) string
+	maxMessagesPerSecond          int
+	idleTimeout                   time.Duration
+	processingTimeoutMilliseconds int
+}
+
+// NewBackfillCoordinatorBuilder creates a new BackfillCoordinatorBuilder
+// instance.
+//
+// Parameters:
+//   - referenceName: reference name of the input channel
+//
+// Returns:
+//   - *BackfillCoordinatorBuilder: pointer to BackfillCoordinatorBuilder
+func NewBackfillCoordinatorBuilder(
+	referenceName string,
+) *BackfillCoordinatorBuilder {
+	return &BackfillCoordinatorBuilder{
+		referenceName: referenceName,
+	}
+}
+
+// NewBackfillCoordinator creates a new BackfillCoordinator instance. By default
+// the correlation id is used as the ordering key.
+//
+// Parameters:
+//   - referenceName: reference name of the input channel
+//   - gateway: pointer to the associated Gateway
+//   - inboundChannelAdapter: input channel adapter
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator
+func NewBackfillCoordinator(
+	referenceName string,
+	gateway *Gateway,
+	inboundChannelAdapter InboundChannelAdapter,
+) *BackfillCoordinator {
+	return &BackfillCoordinator{
+		referenceName:                 referenceName,
+		gateway:                       gateway,
+		inboundChannelAdapter:         inboundChannelAdapter,
+		amountOfWorkers:               1,
+		processingTimeoutMilliseconds: 100000,
+		keyExtractor: func(msg *message.Message) string {
+			return msg.GetHeader().Get(message.HeaderCorrelationId)
+		},
+	}
+}
+
+// WithBeforeInterceptors adds interceptors executed before the interceptors of
+// the consumer channel.
+//
+// Parameters:
+//   - interceptors: message handlers to execute before processing
+//
+// Returns:
+//   - *BackfillCoordinatorBuilder: builder instance for method chaining
+func (b *BackfillCoordinatorBuilder) WithBeforeInterceptors(
+	interceptors ...message.MessageHandler,
+) *BackfillCoordinatorBuilder {
+	b.beforeInterceptors = append(b.beforeInterceptors, interceptors...)
+	return b
+}
+
+// WithAfterInterceptors adds interceptors executed after the interceptors of
+// the consumer channel.
+//
+// Parameters:
+//   - interceptors: message handlers to execute after processing
+//
+// Returns:
+//   - *BackfillCoordinatorBuilder: builder instance for method chaining
+func (b *BackfillCoordinatorBuilder) WithAfterInterceptors(
+	interceptors ...message.MessageHandler,
+) *BackfillCoordinatorBuilder {
+	b.afterInterceptors = append(b.afterInterceptors, interceptors...)
+	return b
+}
+
+// Build constructs a BackfillCoordinator from the dependency container.
+//
+// Parameters:
+//   - container: dependency container
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator
+//   - error: error if any occurs
+func (b *BackfillCoordinatorBuilder) Build(
+	container container.Container[any, any],
+) (*BackfillCoordinator, error) {
+	anyChannel, err := container.Get(ConsumerChannelKey(b.referenceName))
+	if err != nil {
+		return nil,
+			fmt.Errorf(
+				"[backfill-coordinator] consumer %w: %s",
+				message.ErrChannelNotFound,
+				b.referenceName,
+			)
+	}
+
+	inboundChannel, ok := anyChannel.(InboundChannelAdapter)
+	if !ok {
+		return nil,
+			fmt.Errorf(
+				"[backfill-coordinator] consumer channel %s is not a consumer channel.",
+				b.referenceName,
+			)
+	}
+
+	gateway, err := buildInboundGateway(
+		container,
+		inboundChannel,
+		b.beforeInterceptors,
+		b.afterInterceptors,
+		handler.AckAuto,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBackfillCoordinator(b.referenceName, gateway, inboundChannel), nil
+}
+
+// WithAmountOfWorkers sets the number of workers sharing the replay.
+//
+// default value: 1
+//
+// Parameters:
+//   - value: number of workers
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator for method chaining
+func (c *BackfillCoordinator) WithAmountOfWorkers(value int) *BackfillCoordinator {
+	if value > 1 {
+		c.amountOfWorkers = value
+	}
+	return c
+}
+
+// WithKeyExtractor sets the function which extracts the ordering key of a
+// message. Messages with the same key are processed in order by the same
+// worker.
+//
+// Parameters:
+//   - extractor: function returning the ordering key of a message
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator for method chaining
+func (c *BackfillCoordinator) WithKeyExtractor(
+	extractor func(msg *message.Message) string,
+) *BackfillCoordinator {
+	if extractor != nil {
+		c.keyExtractor = extractor
+	}
+	return c
+}
+
+// WithMaxMessagesPerSecond throttles the replay so it does not starve live
+// traffic sharing the same handlers and downstream resources.
+//
+// default value: 0 (unlimited)
+//
+// Parameters:
+//   - value: maximum number of messages dispatched per second
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator for method chaining
+func (c *BackfillCoordinator) WithMaxMessagesPerSecond(
+	value int,
+) *BackfillCoordinator {
+	if value > 0 {
+		c.maxMessagesPerSecond = value
+	}
+	return c
+}
+
+// WithIdleTimeout sets how long the coordinator waits for a new message before
+// considering the replay completed.
+//
+// default value: 0 (runs until the context is cancelled)
+//
+// Parameters:
+//   - value: idle duration
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator for method chaining
+func (c *BackfillCoordinator) WithIdleTimeout(
+	value time.Duration,
+) *BackfillCoordinator {
+	if value > 0 {
+		c.idleTimeout = value
+	}
+	return c
+}
+
+// WithMessageProcessingTimeout sets the message processing timeout in
+// milliseconds.
+//
+// Parameters:
+//   - milliseconds: timeout in milliseconds
+//
+// Returns:
+//   - *BackfillCoordinator: pointer to BackfillCoordinator for method chaining
+func (c *BackfillCoordinator) WithMessageProcessingTimeout(
+	milliseconds int,
+) *BackfillCoordinator {
+	if milliseconds > 0 {
+		c.processingTimeoutMilliseconds = milliseconds
+	}
+	return c
+}
+
+// Run replays the messages of the input channel until the idle timeout is
+// reached, the context is cancelled or a message fails. Pending messages
+// already dispatched to the workers are processed before returning.
+//
+// Parameters:
+//   - ctx: context for cancellation and timeout control
+//
+// Returns:
+//   - error: the first receive or processing error, or the context error
+func (c *BackfillCoordinator) Run(ctx context.Context) error {
+	slog.Info(
+		"[backfill-coordinator] started.",
+		"consumerName", c.referenceName,
+		"workers", c.amountOfWorkers,
+	)
+
+	runCtx, cancelRunCtx := context.WithCancelCause(ctx)
+	defer cancelRunCtx(nil)
+	defer c.inboundChannelAdapter.Close()
+
+	queues := make([]chan *message.Message, c.amountOfWorkers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan *message.Message, 1)
+		wg.Add(1)
+		go func(workerId int, queue chan *message.Message) {
+			defer wg.Done()
+			for msg := range queue {
+				if runCtx.Err() != nil {
+					continue
+				}
+				if err := c.process(runCtx, msg, workerId); err != nil {
+					cancelRunCtx(err)
+				}
+			}
+		}(i, queues[i])
+	}
+
+	var throttle <-chan time.Time
+	if c.maxMessagesPerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(c.maxMessagesPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	c.dispatch(runCtx, cancelRunCtx, queues, throttle)
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	slog.Info(
+		"[backfill-coordinator] finished.",
+		"consumerName", c.referenceName,
+	)
+
+	return context.Cause(runCtx)
+}
+
+// dispatch receives messages from the input channel and dispatches each of
+// them to the worker owning its key.
+func (c *BackfillCoordinator) dispatch(
+	ctx context.Context,
+	cancel context.CancelCauseFunc,
+	queues []chan *message.Message,
+	throttle <-chan time.Time,
+) {
+	for {
+		receiveCtx, cancelReceive := ctx, context.CancelFunc(func() {})
+		if c.idleTimeout > 0 {
+			receiveCtx, cancelReceive = context.WithTimeout(ctx, c.idleTimeout)
+		}
+		msg, err := c.inboundChannelAdapter.ReceiveMessage(receiveCtx)
+		idle := receiveCtx.Err() != nil
+		cancelReceive()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if idle {
+			slog.Info(
+				"[backfill-coordinator] no more messages to replay.",
+				"consumerName", c.referenceName,
+			)
+			return
+		}
+
+		if err != nil {
+			cancel(err)
+			return
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-throttle:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case queues[c.workerFor(msg, len(queues))] <- msg:
+		}
+	}
+}
+
+// workerFor returns the index of the worker owning the message key.
+func (c *BackfillCoordinator) workerFor(msg *message.Message, workers int) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(c.keyExtractor(msg)))
+	return int(hash.Sum32() % uint32(workers))
+}
+
+// process sends the message to the gateway and waits for its completion.
+func (c *BackfillCoordinator) process(
+	ctx context.Context,
+	msg *message.Message,
+	workerId int,
+) error {
+	opCtx, cancel := context.WithTimeout(
+		ctx,
+		time.Duration(c.processingTimeoutMilliseconds)*time.Millisecond,
+	)
+	defer cancel()
+
+	_, err := c.gateway.Execute(opCtx, msg)
+	if err != nil {
+		slog.Error("[backfill-coordinator] processing message error.",
+			"consumer.name", c.referenceName,
+			"consumer.workerId", workerId,
+			"consumer.messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"consumer.error", err.Error(),
+		)
+	}
+	return err
+}
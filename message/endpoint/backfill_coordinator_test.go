@@ -0,0 +1,163 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// historyInboundAdapter replays a fixed list of messages and then blocks
+// until the context is done.
+type historyInboundAdapter struct {
+	fakeInboundAdapter
+	messages chan *message.Message
+}
+
+func newHistoryInboundAdapter(msgs ...*message.Message) *historyInboundAdapter {
+	messages := make(chan *message.Message, len(msgs))
+	for _, msg := range msgs {
+		messages <- msg
+	}
+	return &historyInboundAdapter{messages: messages}
+}
+
+func (h *historyInboundAdapter) ReceiveMessage(
+	ctx context.Context,
+) (*message.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case msg := <-h.messages:
+		return msg, nil
+	}
+}
+
+type orderRecorderHandler struct {
+	mu    sync.Mutex
+	order map[string][]string
+}
+
+func (o *orderRecorderHandler) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetPayload() == "fail" {
+		return nil, errors.New("processing failed")
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	key := msg.GetHeader().Get(message.HeaderCorrelationId)
+	o.order[key] = append(o.order[key], msg.GetPayload().(string))
+	return msg, nil
+}
+
+func buildHistoryMessage(key string, payload string) *message.Message {
+	return message.NewMessageBuilder().
+		WithMessageType(message.Event).
+		WithCorrelationId(key).
+		WithPayload(payload).
+		Build()
+}
+
+func TestBackfillCoordinatorBuilder_Build(t *testing.T) {
+	t.Run("fails when consumer channel is not found", func(t *testing.T) {
+		t.Parallel()
+		got, err := endpoint.NewBackfillCoordinatorBuilder("ref").
+			Build(container.NewGenericContainer[any, any]())
+		if got != nil {
+			t.Errorf("Expected nil, got: %v", got)
+		}
+		if !errors.Is(err, message.ErrChannelNotFound) {
+			t.Errorf("Expected not found error, got: %v", err)
+		}
+	})
+
+	t.Run("fails when channel is not a consumer channel", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set(endpoint.ConsumerChannelKey("ref"), "invalid adapter")
+		_, err := endpoint.NewBackfillCoordinatorBuilder("ref").Build(cont)
+		if err == nil || err.Error() != "[backfill-coordinator] consumer channel ref is not a consumer channel." {
+			t.Errorf("Expected invalid channel error, got: %v", err)
+		}
+	})
+}
+
+func TestBackfillCoordinator_Run(t *testing.T) {
+	t.Run("preserves per key order across workers", func(t *testing.T) {
+		t.Parallel()
+		msgs := []*message.Message{}
+		for i := 0; i < 10; i++ {
+			for _, key := range []string{"a", "b", "c"} {
+				msgs = append(msgs, buildHistoryMessage(key, fmt.Sprintf("%d", i)))
+			}
+		}
+		recorder := &orderRecorderHandler{order: map[string][]string{}}
+		coordinator := endpoint.NewBackfillCoordinator(
+			"history",
+			endpoint.NewGateway(recorder, "", ""),
+			newHistoryInboundAdapter(msgs...),
+		).WithAmountOfWorkers(3).WithIdleTimeout(100 * time.Millisecond)
+
+		if err := coordinator.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+
+		for _, key := range []string{"a", "b", "c"} {
+			got := recorder.order[key]
+			if len(got) != 10 {
+				t.Fatalf("Expected 10 messages for key %s, got %d", key, len(got))
+			}
+			for i, payload := range got {
+				if payload != fmt.Sprintf("%d", i) {
+					t.Errorf("Expected ordered payloads for key %s, got %v", key, got)
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("throttles dispatching", func(t *testing.T) {
+		t.Parallel()
+		recorder := &orderRecorderHandler{order: map[string][]string{}}
+		coordinator := endpoint.NewBackfillCoordinator(
+			"history",
+			endpoint.NewGateway(recorder, "", ""),
+			newHistoryInboundAdapter(
+				buildHistoryMessage("a", "1"),
+				buildHistoryMessage("a", "2"),
+				buildHistoryMessage("a", "3"),
+			),
+		).WithMaxMessagesPerSecond(20).WithIdleTimeout(50 * time.Millisecond)
+
+		start := time.Now()
+		if err := coordinator.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+			t.Errorf("Expected throttled replay, took %v", elapsed)
+		}
+	})
+
+	t.Run("stops on processing error", func(t *testing.T) {
+		t.Parallel()
+		recorder := &orderRecorderHandler{order: map[string][]string{}}
+		coordinator := endpoint.NewBackfillCoordinator(
+			"history",
+			endpoint.NewGateway(recorder, "", ""),
+			newHistoryInboundAdapter(buildHistoryMessage("a", "fail")),
+		)
+
+		err := coordinator.Run(context.Background())
+		if err == nil || err.Error() != "processing failed" {
+			t.Errorf("Expected processing error, got: %v", err)
+		}
+	})
+}
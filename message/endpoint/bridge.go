@@ -0,0 +1,251 @@
+// Package endpoint implements the bridge, used to drain a consumer channel
+// into a publisher channel.
+//
+// The Bridge implementation supports:
+//   - Consumption of any consumer channel
+//   - Optional transformation and filtering of the forwarded messages
+//   - Republishing to a publisher channel with rate limiting
+//   - Acknowledgment of the source message only after it is republished
+//   - Idempotency keys derived from the source messages (at-least-once, not
+//     exactly-once: duplicates are published and discarded downstream)
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// BridgeTransformer transforms a message before it is forwarded. Returning a
+// nil message skips the message, which is still acknowledged on the source.
+type BridgeTransformer func(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error)
+
+// BridgeOptions configures a bridge.
+type BridgeOptions struct {
+	// Transformer transforms or filters the messages before forwarding them.
+	// Nil forwards the messages unchanged.
+	Transformer BridgeTransformer
+	// MaxMessagesPerSecond limits the forwarding rate. Zero means unlimited.
+	MaxMessagesPerSecond int
+	// IdleTimeout ends the bridge when no message is received for this
+	// duration. Zero means it runs until the context is cancelled.
+	IdleTimeout time.Duration
+	// IdempotencyKeys uses as message id of each forwarded message an
+	// idempotency key derived from the identity of its source message in the
+	// source channel, the same on every delivery, so the duplicates forwarded
+	// by a crash between the publish and the source commit are discarded by
+	// the target consumers with deduplication enabled. It does not make the
+	// forwarding exactly-once: no transaction is used, so the duplicates are
+	// still published. The source channel must identify its messages, e.g.
+	// Kafka topics; messages it cannot identify are not forwarded.
+	IdempotencyKeys bool
+}
+
+// bridgeIdempotencyKeyNamespace is the namespace of the idempotency keys of
+// the forwarded messages derived from their source messages.
+var bridgeIdempotencyKeyNamespace = uuid.MustParse("7c2f4e81-93a6-4d0b-b5e2-6a1d8f3c9e57")
+
+// Bridge consumes a consumer channel and republishes its messages to a
+// publisher channel, e.g. to rename a topic, migrate between brokers or drain
+// a dead letter channel.
+type Bridge struct {
+	sourceChannel    InboundChannelAdapter
+	targetChannel    message.PublisherChannel
+	options          BridgeOptions
+	rateLimiter      *tokenBucket
+	forwardedCounter atomic.Int64
+	skippedCounter   atomic.Int64
+}
+
+// NewBridge creates a new bridge.
+//
+// Parameters:
+//   - sourceChannel: the consumer channel to drain
+//   - targetChannel: the publisher channel to republish to
+//   - options: the bridge options
+//
+// Returns:
+//   - *Bridge: configured bridge
+func NewBridge(
+	sourceChannel InboundChannelAdapter,
+	targetChannel message.PublisherChannel,
+	options BridgeOptions,
+) *Bridge {
+	bridge := &Bridge{
+		sourceChannel: sourceChannel,
+		targetChannel: targetChannel,
+		options:       options,
+	}
+	if options.MaxMessagesPerSecond > 0 {
+		bridge.rateLimiter = newTokenBucket(float64(options.MaxMessagesPerSecond), 1)
+	}
+	return bridge
+}
+
+// Forwarded returns the number of messages republished so far.
+//
+// Returns:
+//   - int64: number of republished messages
+func (b *Bridge) Forwarded() int64 {
+	return b.forwardedCounter.Load()
+}
+
+// Skipped returns the number of messages skipped by the transformer so far.
+//
+// Returns:
+//   - int64: number of skipped messages
+func (b *Bridge) Skipped() int64 {
+	return b.skippedCounter.Load()
+}
+
+// Run consumes the source channel and republishes its messages until the
+// idle timeout is reached or the context is cancelled. A message is only
+// acknowledged on the source after it is republished, so a failed message is
+// redelivered when the bridge runs again.
+//
+// Parameters:
+//   - ctx: context for cancellation and timeout control
+//
+// Returns:
+//   - error: error if receiving, transforming or republishing fails
+func (b *Bridge) Run(ctx context.Context) error {
+	slog.Info("[bridge] started.",
+		"source", b.sourceChannel.ReferenceName(),
+		"target", b.targetChannel.Name(),
+	)
+	defer b.sourceChannel.Close()
+
+	for {
+		receiveCtx, cancelReceive := ctx, context.CancelFunc(func() {})
+		if b.options.IdleTimeout > 0 {
+			receiveCtx, cancelReceive = context.WithTimeout(ctx, b.options.IdleTimeout)
+		}
+		msg, err := b.sourceChannel.ReceiveMessage(receiveCtx)
+		idle := receiveCtx.Err() != nil
+		cancelReceive()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if idle {
+			slog.Info("[bridge] finished.",
+				"source", b.sourceChannel.ReferenceName(),
+				"forwarded", b.Forwarded(),
+				"skipped", b.Skipped(),
+			)
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("[bridge] receive error: %w", err)
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		if b.rateLimiter != nil {
+			if err := b.rateLimiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err := b.forward(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// BridgeIdempotencyKey returns the idempotency key of a forwarded message,
+// used as its message id, the same for every delivery of its source message.
+//
+// Parameters:
+//   - sourceId: the identity of the source message in the source channel
+//
+// Returns:
+//   - string: the idempotency key of the forwarded message
+func BridgeIdempotencyKey(sourceId string) string {
+	return uuid.NewSHA1(bridgeIdempotencyKeyNamespace, []byte(sourceId)).String()
+}
+
+// forward transforms a message and republishes it to the target channel,
+// acknowledging it on the source afterwards.
+func (b *Bridge) forward(ctx context.Context, msg *message.Message) error {
+	forwarded := msg
+	if b.options.Transformer != nil {
+		var err error
+		forwarded, err = b.options.Transformer(ctx, msg)
+		if err != nil {
+			return fmt.Errorf("[bridge] failed to transform message: %w", err)
+		}
+	}
+
+	if forwarded != nil {
+		builder := message.NewMessageBuilderFromMessage(forwarded).
+			WithChannelName(b.targetChannel.Name()).
+			WithRawMessage(nil).
+			WithContext(ctx)
+		if b.options.IdempotencyKeys {
+			sourceId, err := b.sourceIdOf(msg)
+			if err != nil {
+				return err
+			}
+			builder.WithMessageId(BridgeIdempotencyKey(sourceId))
+		}
+		out := builder.Build()
+		if err := b.targetChannel.Send(ctx, out); err != nil {
+			return fmt.Errorf(
+				"[bridge] failed to republish message to %s: %w",
+				b.targetChannel.Name(),
+				err,
+			)
+		}
+	}
+
+	if ackChannel, ok := b.sourceChannel.(handler.ChannelMessageAcknowledgment); ok {
+		if err := ackChannel.CommitMessage(msg); err != nil {
+			return fmt.Errorf("[bridge] %w", err)
+		}
+	}
+
+	if forwarded == nil {
+		b.skippedCounter.Add(1)
+		return nil
+	}
+	b.forwardedCounter.Add(1)
+	return nil
+}
+
+// sourceIdOf returns the identity of a source message in the source channel.
+// The message id is not used instead: consumer channels generate a new one on
+// every delivery of messages received without it.
+func (b *Bridge) sourceIdOf(msg *message.Message) (string, error) {
+	channel, ok := b.sourceChannel.(identifiableChannelProvider)
+	if !ok {
+		return "", fmt.Errorf(
+			"[bridge] channel %s cannot identify its messages to derive their idempotency keys",
+			b.sourceChannel.ReferenceName(),
+		)
+	}
+	sourceId, err := channel.SourceIdOf(msg)
+	if err != nil {
+		return "", fmt.Errorf("[bridge] cannot derive the idempotency key: %w", err)
+	}
+	if sourceId == "" {
+		return "", fmt.Errorf(
+			"[bridge] message %s has no source id to derive its idempotency key",
+			msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	}
+	return sourceId, nil
+}
@@ -0,0 +1,185 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+type failingPublisher struct{}
+
+func (f *failingPublisher) Name() string { return "failing" }
+
+func (f *failingPublisher) Send(_ context.Context, _ *message.Message) error {
+	return errors.New("broker unavailable")
+}
+
+// identifiableInboundAdapter identifies its messages by their payload, as a
+// broker identifies them by their offset.
+type identifiableInboundAdapter struct {
+	*historyInboundAdapter
+}
+
+func (i *identifiableInboundAdapter) SourceIdOf(msg *message.Message) (string, error) {
+	return string(msg.GetPayload().([]byte)), nil
+}
+
+func buildBridgeMessage(route string) *message.Message {
+	return message.NewMessageBuilder().
+		WithMessageType(message.Event).
+		WithRoute(route).
+		WithCorrelationId("corr-" + route).
+		WithChannelName("orders.legacy").
+		WithPayload([]byte(`{"id":"` + route + `"}`)).
+		Build()
+}
+
+func TestBridge_Run(t *testing.T) {
+	t.Run("forwards the messages to the target channel", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		bridge := endpoint.NewBridge(
+			newHistoryInboundAdapter(
+				buildBridgeMessage("orderCreated"),
+				buildBridgeMessage("orderPaid"),
+			),
+			target,
+			endpoint.BridgeOptions{
+				MaxMessagesPerSecond: 1000,
+				IdleTimeout:          50 * time.Millisecond,
+			},
+		)
+
+		if err := bridge.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if bridge.Forwarded() != 2 || len(target.sent) != 2 {
+			t.Fatalf("Expected 2 forwarded messages, got %d", bridge.Forwarded())
+		}
+
+		header := target.sent[0].GetHeader()
+		if header.Get(message.HeaderRoute) != "orderCreated" {
+			t.Errorf("Expected route 'orderCreated', got '%s'", header.Get(message.HeaderRoute))
+		}
+		if header.Get(message.HeaderCorrelationId) != "corr-orderCreated" {
+			t.Errorf("Expected original correlation id, got '%s'", header.Get(message.HeaderCorrelationId))
+		}
+		if header.Get(message.HeaderChannelName) != "recording" {
+			t.Errorf("Expected channel name 'recording', got '%s'", header.Get(message.HeaderChannelName))
+		}
+	})
+
+	t.Run("transforms and skips messages", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		bridge := endpoint.NewBridge(
+			newHistoryInboundAdapter(
+				buildBridgeMessage("orderCreated"),
+				buildBridgeMessage("orderPaid"),
+			),
+			target,
+			endpoint.BridgeOptions{
+				Transformer: func(
+					_ context.Context,
+					msg *message.Message,
+				) (*message.Message, error) {
+					if msg.GetHeader().Get(message.HeaderRoute) == "orderPaid" {
+						return nil, nil
+					}
+					return message.NewMessageBuilderFromMessage(msg).
+						WithRoute("order.created").
+						Build(), nil
+				},
+				IdleTimeout: 50 * time.Millisecond,
+			},
+		)
+
+		if err := bridge.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if bridge.Forwarded() != 1 || bridge.Skipped() != 1 {
+			t.Fatalf(
+				"Expected 1 forwarded and 1 skipped message, got %d and %d",
+				bridge.Forwarded(),
+				bridge.Skipped(),
+			)
+		}
+		if route := target.sent[0].GetHeader().Get(message.HeaderRoute); route != "order.created" {
+			t.Errorf("Expected transformed route 'order.created', got '%s'", route)
+		}
+	})
+
+	t.Run("derives the idempotency keys of the forwarded messages from the source messages", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		// the same source message delivered twice, e.g. after a crash
+		// between the publish and the commit
+		for range 2 {
+			bridge := endpoint.NewBridge(
+				&identifiableInboundAdapter{
+					newHistoryInboundAdapter(buildBridgeMessage("orderCreated")),
+				},
+				target,
+				endpoint.BridgeOptions{
+					IdempotencyKeys: true,
+					IdleTimeout:     50 * time.Millisecond,
+				},
+			)
+			if err := bridge.Run(context.Background()); err != nil {
+				t.Fatalf("Expected nil error, got: %v", err)
+			}
+		}
+
+		if len(target.sent) != 2 {
+			t.Fatalf("Expected 2 forwarded messages, got %d", len(target.sent))
+		}
+		first := target.sent[0].GetHeader().Get(message.HeaderMessageId)
+		second := target.sent[1].GetHeader().Get(message.HeaderMessageId)
+		if first != second {
+			t.Errorf("Expected the same message id, got %s and %s", first, second)
+		}
+		if want := endpoint.BridgeIdempotencyKey(`{"id":"orderCreated"}`); first != want {
+			t.Errorf("Expected message id %s, got %s", want, first)
+		}
+	})
+
+	t.Run("does not forward messages without source id when deriving idempotency keys", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		bridge := endpoint.NewBridge(
+			newHistoryInboundAdapter(buildBridgeMessage("orderCreated")),
+			target,
+			endpoint.BridgeOptions{
+				IdempotencyKeys: true,
+				IdleTimeout:     50 * time.Millisecond,
+			},
+		)
+
+		if err := bridge.Run(context.Background()); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		if len(target.sent) != 0 {
+			t.Errorf("Expected no forwarded message, got %d", len(target.sent))
+		}
+	})
+
+	t.Run("stops when the target channel fails", func(t *testing.T) {
+		t.Parallel()
+		bridge := endpoint.NewBridge(
+			newHistoryInboundAdapter(buildBridgeMessage("orderCreated")),
+			&failingPublisher{},
+			endpoint.BridgeOptions{IdleTimeout: 50 * time.Millisecond},
+		)
+
+		if err := bridge.Run(context.Background()); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		if bridge.Forwarded() != 0 {
+			t.Errorf("Expected no forwarded message, got %d", bridge.Forwarded())
+		}
+	})
+}
@@ -0,0 +1,302 @@
+// Package endpoint implements the dead letter redriver, used to reprocess
+// messages shipped to a dead letter channel.
+//
+// The DeadLetterRedriver implementation supports:
+// - Consumption of a dead letter channel
+// - Filtering by route and error
+// - Restoration of the original payload and headers
+// - Republishing to a given channel with rate limiting
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// ErrRedriveTargetRequired is returned when a dead letter redrive has no
+// target publisher channel.
+var ErrRedriveTargetRequired = errors.New(
+	"[dead-letter-redriver] target publisher channel is required",
+)
+
+// RedriveOptions configures a dead letter redrive.
+type RedriveOptions struct {
+	// Routes restricts the redrive to messages of these routes. Empty means all.
+	Routes []string
+	// ErrorContains restricts the redrive to messages whose failure reason
+	// contains this text. Empty means all.
+	ErrorContains string
+	// MaxMessagesPerSecond limits the republishing rate. Zero means unlimited.
+	MaxMessagesPerSecond int
+	// IdleTimeout ends the redrive when no message is received for this
+	// duration. Zero means it runs until the context is cancelled.
+	IdleTimeout time.Duration
+}
+
+// DeadLetterRedriver consumes a dead letter channel and republishes the
+// original messages, stripped of their failure metadata.
+type DeadLetterRedriver struct {
+	sourceChannel   InboundChannelAdapter
+	container       container.Container[any, any]
+	targetChannel   string
+	options         RedriveOptions
+	redrivenCounter atomic.Int64
+}
+
+// deadLetterEnvelope mirrors the payload published by the dead letter handler.
+// The original payload is kept as raw JSON, so the translators republish it
+// as is instead of encoding it again.
+type deadLetterEnvelope struct {
+	ReasonError string
+	Payload     json.RawMessage
+	Headers     map[string]string
+}
+
+// publisherChannel defines the contract for channels the redriver can
+// republish to, either internal channels or outbound channel adapters.
+type publisherChannel interface {
+	Send(ctx context.Context, msg *message.Message) error
+}
+
+// redriveStrippedHeaders holds the headers removed before republishing.
+var redriveStrippedHeaders = []string{
+	message.HeaderDeadLetterOriginalChannel,
+	message.HeaderDeadLetterError,
+	message.HeaderDeadLetterHandler,
+	message.HeaderDeadLetterAttempts,
+	message.HeaderDeadLetterFailedAt,
+	message.HeaderDeadLetterStackTrace,
+	message.HeaderDeadLetterGuardFailure,
+	message.HeaderRetryAttempts,
+	message.HeaderChannelName,
+}
+
+// NewDeadLetterRedriver creates a new dead letter redriver.
+//
+// Parameters:
+//   - sourceChannel: the dead letter consumer channel
+//   - container: dependency container used to resolve the target channels
+//   - targetChannel: publisher channel to republish to. It is required: the
+//     original channel recorded in the dead letter message is the reference
+//     name of the failed consumer, which is not a publisher channel
+//   - options: the redrive options
+//
+// Returns:
+//   - *DeadLetterRedriver: configured redriver
+func NewDeadLetterRedriver(
+	sourceChannel InboundChannelAdapter,
+	container container.Container[any, any],
+	targetChannel string,
+	options RedriveOptions,
+) *DeadLetterRedriver {
+	return &DeadLetterRedriver{
+		sourceChannel: sourceChannel,
+		container:     container,
+		targetChannel: targetChannel,
+		options:       options,
+	}
+}
+
+// Redriven returns the number of messages republished so far.
+//
+// Returns:
+//   - int64: number of republished messages
+func (r *DeadLetterRedriver) Redriven() int64 {
+	return r.redrivenCounter.Load()
+}
+
+// Run consumes the dead letter channel and republishes the matching messages
+// until the idle timeout is reached or the context is cancelled. Messages not
+// matching the filters are skipped without being acknowledged.
+//
+// Parameters:
+//   - ctx: context for cancellation and timeout control
+//
+// Returns:
+//   - error: error if the target channel is missing, or if receiving,
+//     decoding or republishing fails
+func (r *DeadLetterRedriver) Run(ctx context.Context) error {
+	if r.targetChannel == "" {
+		r.sourceChannel.Close()
+		return ErrRedriveTargetRequired
+	}
+	slog.Info("[dead-letter-redriver] started.",
+		"source", r.sourceChannel.ReferenceName(),
+		"target", r.targetChannel,
+	)
+	defer r.sourceChannel.Close()
+
+	var throttle <-chan time.Time
+	if r.options.MaxMessagesPerSecond > 0 {
+		ticker := time.NewTicker(
+			time.Second / time.Duration(r.options.MaxMessagesPerSecond),
+		)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	for {
+		receiveCtx, cancelReceive := ctx, context.CancelFunc(func() {})
+		if r.options.IdleTimeout > 0 {
+			receiveCtx, cancelReceive = context.WithTimeout(ctx, r.options.IdleTimeout)
+		}
+		msg, err := r.sourceChannel.ReceiveMessage(receiveCtx)
+		idle := receiveCtx.Err() != nil
+		cancelReceive()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if idle {
+			slog.Info("[dead-letter-redriver] finished.",
+				"source", r.sourceChannel.ReferenceName(),
+				"redriven", r.Redriven(),
+			)
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("[dead-letter-redriver] receive error: %w", err)
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle:
+			}
+		}
+
+		if err := r.redrive(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// redrive restores the original message and republishes it when it matches
+// the configured filters.
+func (r *DeadLetterRedriver) redrive(
+	ctx context.Context,
+	dlqMessage *message.Message,
+) error {
+	envelope, err := r.decodeEnvelope(dlqMessage)
+	if err != nil {
+		return err
+	}
+
+	if !r.matches(envelope) {
+		return nil
+	}
+
+	targetName := r.targetChannel
+	target, err := r.container.Get(targetName)
+	if err != nil {
+		return fmt.Errorf(
+			"[dead-letter-redriver] target %w: %s",
+			message.ErrChannelNotFound,
+			targetName,
+		)
+	}
+
+	publisher, ok := target.(publisherChannel)
+	if !ok {
+		return fmt.Errorf(
+			"[dead-letter-redriver] target channel %s is not a publisher channel",
+			targetName,
+		)
+	}
+
+	headers := make(map[string]string, len(envelope.Headers))
+	for key, value := range envelope.Headers {
+		if !slices.Contains(redriveStrippedHeaders, key) {
+			headers[key] = value
+		}
+	}
+
+	builder, err := message.NewMessageBuilderFromHeaders(headers)
+	if err != nil {
+		return fmt.Errorf("[dead-letter-redriver] %w", err)
+	}
+	msg := builder.
+		WithChannelName(targetName).
+		WithPayload(envelope.Payload).
+		WithContext(ctx).
+		Build()
+
+	if err := publisher.Send(ctx, msg); err != nil {
+		return fmt.Errorf(
+			"[dead-letter-redriver] failed to republish message to %s: %w",
+			targetName,
+			err,
+		)
+	}
+
+	if ackChannel, ok := r.sourceChannel.(handler.ChannelMessageAcknowledgment); ok {
+		if err := ackChannel.CommitMessage(dlqMessage); err != nil {
+			return fmt.Errorf("[dead-letter-redriver] %w", err)
+		}
+	}
+
+	r.redrivenCounter.Add(1)
+	return nil
+}
+
+// decodeEnvelope decodes the dead letter payload, either received from an
+// external broker ([]byte) or from an internal channel.
+func (r *DeadLetterRedriver) decodeEnvelope(
+	msg *message.Message,
+) (*deadLetterEnvelope, error) {
+	data, ok := msg.GetPayload().([]byte)
+	if !ok {
+		var err error
+		data, err = json.Marshal(msg.GetPayload())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[dead-letter-redriver] cannot decode dead letter message: %w: %w",
+				message.ErrTranslation,
+				err,
+			)
+		}
+	}
+
+	envelope := &deadLetterEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, fmt.Errorf(
+			"[dead-letter-redriver] cannot decode dead letter message: %w: %w",
+			message.ErrTranslation,
+			err,
+		)
+	}
+	return envelope, nil
+}
+
+// matches reports whether the dead letter message matches the filters.
+func (r *DeadLetterRedriver) matches(envelope *deadLetterEnvelope) bool {
+	if len(r.options.Routes) > 0 &&
+		!slices.Contains(r.options.Routes, envelope.Headers[message.HeaderRoute]) {
+		return false
+	}
+
+	if r.options.ErrorContains != "" &&
+		!strings.Contains(envelope.ReasonError, r.options.ErrorContains) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+package endpoint_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/channel/kafka"
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+type recordingPublisher struct {
+	mu   sync.Mutex
+	sent []*message.Message
+}
+
+func (r *recordingPublisher) Name() string { return "recording" }
+
+func (r *recordingPublisher) Send(_ context.Context, msg *message.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+func buildDeadLetterMessage(t *testing.T, route string, reason string) *message.Message {
+	payload, err := json.Marshal(map[string]any{
+		"ReasonError": reason,
+		"Payload":     map[string]any{"id": route},
+		"Headers": map[string]string{
+			message.HeaderRoute:         route,
+			message.HeaderCorrelationId: "corr-" + route,
+			message.HeaderRetryAttempts: "2",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message.NewMessageBuilder().
+		WithMessageType(message.Document).
+		WithPayload(payload).
+		WithCustomHeader(message.HeaderDeadLetterOriginalChannel, "orders-consumer").
+		WithCustomHeader(message.HeaderDeadLetterError, reason).
+		Build()
+}
+
+func TestDeadLetterRedriver_Run(t *testing.T) {
+	t.Run("republishes to target channel without failure headers", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("orders", target)
+
+		redriver := endpoint.NewDeadLetterRedriver(
+			newHistoryInboundAdapter(buildDeadLetterMessage(t, "createOrder", "timeout")),
+			cont,
+			"orders",
+			endpoint.RedriveOptions{IdleTimeout: 50 * time.Millisecond},
+		)
+
+		if err := redriver.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if redriver.Redriven() != 1 || len(target.sent) != 1 {
+			t.Fatalf("Expected 1 redriven message, got %d", redriver.Redriven())
+		}
+
+		header := target.sent[0].GetHeader()
+		if header.Get(message.HeaderRoute) != "createOrder" {
+			t.Errorf("Expected route 'createOrder', got '%s'", header.Get(message.HeaderRoute))
+		}
+		if header.Get(message.HeaderCorrelationId) != "corr-createOrder" {
+			t.Errorf("Expected original correlation id, got '%s'", header.Get(message.HeaderCorrelationId))
+		}
+		if header.Get(message.HeaderRetryAttempts) != "" {
+			t.Error("Expected retry attempts header to be stripped")
+		}
+		if string(target.sent[0].GetPayload().(json.RawMessage)) != `{"id":"createOrder"}` {
+			t.Errorf("Expected original payload, got %s", target.sent[0].GetPayload())
+		}
+	})
+
+	t.Run("republishes the original payload through a broker translator", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("orders", target)
+
+		redriver := endpoint.NewDeadLetterRedriver(
+			newHistoryInboundAdapter(buildDeadLetterMessage(t, "createOrder", "timeout")),
+			cont,
+			"orders",
+			endpoint.RedriveOptions{IdleTimeout: 50 * time.Millisecond},
+		)
+		if err := redriver.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(target.sent) != 1 {
+			t.Fatalf("Expected 1 redriven message, got %d", len(target.sent))
+		}
+
+		record, err := kafka.NewMessageTranslator().FromMessage(target.sent[0])
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if string(record.Value) != `{"id":"createOrder"}` {
+			t.Errorf("Expected original JSON on the wire, got %s", record.Value)
+		}
+		received, err := kafka.NewMessageTranslator().ToMessage(record)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if string(received.GetPayload().([]byte)) != `{"id":"createOrder"}` {
+			t.Errorf("Expected original payload after the round trip, got %s", received.GetPayload())
+		}
+	})
+
+	t.Run("filters by route and error", func(t *testing.T) {
+		t.Parallel()
+		target := &recordingPublisher{}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("retry.target", target)
+
+		redriver := endpoint.NewDeadLetterRedriver(
+			newHistoryInboundAdapter(
+				buildDeadLetterMessage(t, "createOrder", "timeout"),
+				buildDeadLetterMessage(t, "createOrder", "validation"),
+				buildDeadLetterMessage(t, "cancelOrder", "timeout"),
+			),
+			cont,
+			"retry.target",
+			endpoint.RedriveOptions{
+				Routes:               []string{"createOrder"},
+				ErrorContains:        "time",
+				MaxMessagesPerSecond: 1000,
+				IdleTimeout:          50 * time.Millisecond,
+			},
+		)
+
+		if err := redriver.Run(context.Background()); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(target.sent) != 1 {
+			t.Errorf("Expected 1 redriven message, got %d", len(target.sent))
+		}
+	})
+
+	t.Run("fails without target channel", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("orders-consumer", &recordingPublisher{})
+		redriver := endpoint.NewDeadLetterRedriver(
+			newHistoryInboundAdapter(buildDeadLetterMessage(t, "createOrder", "timeout")),
+			cont,
+			"",
+			endpoint.RedriveOptions{IdleTimeout: 50 * time.Millisecond},
+		)
+
+		if err := redriver.Run(context.Background()); !errors.Is(err, endpoint.ErrRedriveTargetRequired) {
+			t.Errorf("Expected ErrRedriveTargetRequired, got %v", err)
+		}
+		if redriver.Redriven() != 0 {
+			t.Errorf("Expected no redriven message, got %d", redriver.Redriven())
+		}
+	})
+
+	t.Run("fails when target channel is not found", func(t *testing.T) {
+		t.Parallel()
+		redriver := endpoint.NewDeadLetterRedriver(
+			newHistoryInboundAdapter(buildDeadLetterMessage(t, "createOrder", "timeout")),
+			container.NewGenericContainer[any, any](),
+			"missing",
+			endpoint.RedriveOptions{IdleTimeout: 50 * time.Millisecond},
+		)
+
+		if err := redriver.Run(context.Background()); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
@@ -2,10 +2,26 @@ package endpoint
 
 import (
 	"context"
+	"time"
 
+	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+	"github.com/jeffersonbrasilino/gomes/message/router"
 )
 
+// ConsumerChannelKey returns the key a consumer channel is registered with in
+// the gomes container, apart from the publisher channels and handlers.
+//
+// Parameters:
+//   - referenceName: the reference name of the consumer channel
+//
+// Returns:
+//   - string: the namespaced key of the consumer channel
+func ConsumerChannelKey(referenceName string) string {
+	return container.NamespacedKey("consumer", referenceName)
+}
+
 // InboundChannelAdapter defines the contract for inbound channel adapters that
 // receive messages from external sources.
 type InboundChannelAdapter interface {
@@ -19,6 +35,135 @@ type InboundChannelAdapter interface {
 	SendReplyUsingReplyTo() bool
 }
 
+// backlogMonitorProvider is implemented by inbound channel adapters able to
+// collect their backlog while the consumer runs.
+type backlogMonitorProvider interface {
+	MonitorBacklog(ctx context.Context)
+}
+
+// failingChannelProvider is implemented by inbound channel adapters
+// reporting the terminal failure which stopped their consumption.
+type failingChannelProvider interface {
+	Err() error
+}
+
+// retryPolicyProvider is implemented by inbound channel adapters configured
+// with a retry policy.
+type retryPolicyProvider interface {
+	RetryPolicy() handler.RetryPolicy
+}
+
+// deduplicationProvider is implemented by inbound channel adapters configured
+// with message deduplication.
+type deduplicationProvider interface {
+	Deduplication() (handler.DeduplicationStore, time.Duration)
+}
+
+// unroutableChannelProvider is implemented by inbound channel adapters
+// configured with strict routing.
+type unroutableChannelProvider interface {
+	UnroutableChannelName() string
+}
+
+// deadLetterRoutesProvider is implemented by inbound channel adapters
+// sending the failed messages of some routes to their own dead letter channels.
+type deadLetterRoutesProvider interface {
+	DeadLetterRoutes() map[string]string
+}
+
+// poisonMessageQuarantineProvider is implemented by inbound channel adapters
+// configured with poison message quarantine.
+type poisonMessageQuarantineProvider interface {
+	PoisonMessageQuarantine() (string, int, handler.PoisonMessageKeyExtractor)
+}
+
+// ackModeProvider is implemented by inbound channel adapters configured with
+// an acknowledgment mode.
+type ackModeProvider interface {
+	AckMode() handler.AckMode
+}
+
+// wireTapProvider is implemented by inbound channel adapters configured with
+// a wire tap.
+type wireTapProvider interface {
+	WireTap() (string, float64)
+}
+
+// filterProvider is implemented by inbound channel adapters configured with
+// a message filter.
+type filterProvider interface {
+	Filter() (router.FilterFunc, string)
+}
+
+// routerProvider is implemented by inbound channel adapters routing their
+// messages through a router.
+type routerProvider interface {
+	Router() router.ContainerRouter
+}
+
+// responseChannelProvider is implemented by inbound channel adapters
+// configured with a response channel.
+type responseChannelProvider interface {
+	ResponseChannelName() string
+}
+
+// replyChannelFactoryProvider is implemented by inbound channel adapters
+// creating the reply channels of unknown reply-to destinations.
+type replyChannelFactoryProvider interface {
+	ReplyChannelFactory() (handler.ReplyChannelFactory, []string)
+}
+
+// atomicReplyProvider is implemented by inbound channel adapters linking the
+// reply publishing to the acknowledgment of the requests.
+type atomicReplyProvider interface {
+	AtomicReply() bool
+}
+
+// orderingKeyProvider is implemented by inbound channel adapters keeping the
+// order of the messages sharing an ordering key.
+type orderingKeyProvider interface {
+	OrderingKey() func(*message.Message) string
+}
+
+// processingOptionsProvider is implemented by inbound channel adapters
+// configured with the processing options of their consumers.
+type processingOptionsProvider interface {
+	AmountOfProcessors() int
+	ProcessingTimeout() time.Duration
+	ErrorPolicy() ErrorPolicy
+}
+
+// circuitBreakerProvider is implemented by inbound channel adapters
+// configured with a circuit breaker.
+type circuitBreakerProvider interface {
+	CircuitBreaker() *handler.CircuitBreaker
+}
+
+// seekableChannelProvider is implemented by inbound channel adapters able to
+// restart the consumption at a position of their source.
+type seekableChannelProvider interface {
+	SeekTo(position int64) error
+	PositionOf(msg *message.Message) (int64, error)
+}
+
+// identifiableChannelProvider is implemented by inbound channel adapters able
+// to identify a message by its place in their source.
+type identifiableChannelProvider interface {
+	SourceIdOf(msg *message.Message) (string, error)
+}
+
+// correlationStoreProvider is implemented by publisher channels keeping the
+// correlations of their requests in a correlation store.
+type correlationStoreProvider interface {
+	CorrelationStore() (handler.CorrelationStore, time.Duration)
+}
+
+// lateReplyHandlerProvider is implemented by publisher channels handling the
+// replies to their requests which arrive after the request finished.
+type lateReplyHandlerProvider interface {
+	LateReplyHandler() func(msg *message.Message)
+}
+
 type OutboundChannelAdapter interface {
 	Send(ctx context.Context, message *message.Message) error
 	Close() error
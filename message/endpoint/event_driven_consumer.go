@@ -10,15 +10,31 @@
 // - Asynchronous message consumption with multiple concurrent processors
 // - Integration with inbound channel adapters and gateways
 // - Configurable processing timeouts and error handling
+// - Per-route and per-message processing timeout overrides
 // - Graceful shutdown and resource cleanup
+// - Draining of in-flight messages for safe deploys
+// - Runtime pause/resume of message fetching
+// - Ordered processing of the messages sharing an ordering key
+// - Priority queues processing higher priority messages first
+// - Token bucket rate limiting of message dispatching
+// - Fetching held while the circuit breaker is open
+// - Stop on terminal failures of the consumer channel
+// - Processors kept alive when the processing of a message panics
+// - Processing statistics and last error snapshots
+// - Consumer entries in the Message History
 // - Dead letter channel support for failed messages
+// - Error policies for receive and processing errors
+// - Terminal error of a run exposed through Done and Err
 package endpoint
 
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jeffersonbrasilino/gomes/container"
@@ -30,7 +46,10 @@ import (
 // EventDrivenConsumerBuilder is responsible for building EventDrivenConsumer instances.
 // referenceName identifies the input channel to be consumed.
 type EventDrivenConsumerBuilder struct {
-	referenceName string
+	referenceName      string
+	beforeInterceptors []message.MessageHandler
+	afterInterceptors  []message.MessageHandler
+	ackMode            handler.AckMode
 }
 
 // EventDrivenConsumer represents an event-driven-consumer.
@@ -39,19 +58,89 @@ type EventDrivenConsumerBuilder struct {
 type EventDrivenConsumer struct {
 	referenceName                 string
 	processingTimeoutMilliseconds int
+	routeTimeouts                 map[string]time.Duration
 	gateway                       *Gateway
 	inboundChannelAdapter         InboundChannelAdapter
 	amountOfProcessors            int
-	processingQueue               chan *message.Message
+	processingQueues              []chan *message.Message
+	orderingKey                   func(*message.Message) string
+	priorityExtractor             func(*message.Message) int
+	priorityQueue                 *priorityQueue
 	processorsWaitGroup           sync.WaitGroup
-	stopOnError                   bool
+	errorPolicy                   ErrorPolicy
+	errorAlert                    func(consumerName string, err error)
 	otelTrace                     otel.OtelTrace
 	stopTrigger                   chan error
 	runCancelCtxFunc              func(err error)
+	receiveCancelFunc             context.CancelFunc
+	done                          chan struct{}
+	terminated                    chan struct{}
+	terminalErr                   error
+	resumed                       chan struct{}
+	rateLimiter                   *tokenBucket
+	circuitBreaker                *handler.CircuitBreaker
+	processedCounter              atomic.Int64
+	failedCounter                 atomic.Int64
+	lastError                     string
+	lastErrorAt                   time.Time
 	once                          sync.Once
 	mu                            sync.Mutex
 }
 
+// ErrorPolicy defines how a consumer reacts to the errors receiving messages
+// from its channel and to the errors processing them.
+type ErrorPolicy int
+
+// Error policies.
+const (
+	// ErrorPolicyStop stops the consumer on receive and processing errors.
+	ErrorPolicyStop ErrorPolicy = iota
+	// ErrorPolicyContinue logs receive and processing errors and keeps
+	// consuming; failed messages are settled by the acknowledgment mode.
+	ErrorPolicyContinue
+	// ErrorPolicyDLQAndContinue keeps consuming after receive errors and
+	// after processing errors whose message was sent to the dead letter
+	// channel, stopping when a failed message could not be dead lettered.
+	ErrorPolicyDLQAndContinue
+	// ErrorPolicyPauseAndAlert pauses the fetching after a processing error,
+	// until Resume is called, and alerts both receive and processing errors.
+	// Receive errors do not pause the consumer.
+	ErrorPolicyPauseAndAlert
+)
+
+// String returns the string representation of an ErrorPolicy.
+//
+// Returns:
+//   - string: the policy name
+func (p ErrorPolicy) String() string {
+	switch p {
+	case ErrorPolicyContinue:
+		return "continue"
+	case ErrorPolicyDLQAndContinue:
+		return "dlq-and-continue"
+	case ErrorPolicyPauseAndAlert:
+		return "pause-and-alert"
+	default:
+		return "stop"
+	}
+}
+
+// ConsumerStats is a snapshot of the processing statistics of a consumer.
+type ConsumerStats struct {
+	// Processed is the amount of messages processed successfully.
+	Processed int64 `json:"processed"`
+	// Failed is the amount of messages whose processing failed.
+	Failed int64 `json:"failed"`
+	// Unroutable is the amount of messages sent to the unroutable channel.
+	Unroutable int64 `json:"unroutable"`
+	// Paused reports whether the message fetching is paused.
+	Paused bool `json:"paused"`
+	// LastError is the last processing error, empty if none.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorAt is when the last processing error happened.
+	LastErrorAt time.Time `json:"lastErrorAt,omitzero"`
+}
+
 // NewEventDrivenConsumerBuilder creates a new EventDrivenConsumerBuilder instance.
 //
 // Parameters:
@@ -85,12 +174,60 @@ func NewEventDrivenConsumer(
 		gateway:                       gateway,
 		inboundChannelAdapter:         inboundChannelAdapter,
 		amountOfProcessors:            1,
-		stopOnError:                   true,
+		errorPolicy:                   ErrorPolicyStop,
 		otelTrace:                     otel.InitTrace("event-driven-consumer"),
 	}
 	return consumer
 }
 
+// WithBeforeInterceptors adds interceptors executed before the interceptors of
+// the consumer channel, e.g. interceptors shared by every consumer.
+//
+// Parameters:
+//   - interceptors: message handlers to execute before processing
+//
+// Returns:
+//   - *EventDrivenConsumerBuilder: builder instance for method chaining
+func (b *EventDrivenConsumerBuilder) WithBeforeInterceptors(
+	interceptors ...message.MessageHandler,
+) *EventDrivenConsumerBuilder {
+	b.beforeInterceptors = append(b.beforeInterceptors, interceptors...)
+	return b
+}
+
+// WithAfterInterceptors adds interceptors executed after the interceptors of
+// the consumer channel, e.g. interceptors shared by every consumer.
+//
+// Parameters:
+//   - interceptors: message handlers to execute after processing
+//
+// Returns:
+//   - *EventDrivenConsumerBuilder: builder instance for method chaining
+func (b *EventDrivenConsumerBuilder) WithAfterInterceptors(
+	interceptors ...message.MessageHandler,
+) *EventDrivenConsumerBuilder {
+	b.afterInterceptors = append(b.afterInterceptors, interceptors...)
+	return b
+}
+
+// WithAckMode sets when the messages are acknowledged on the consumer
+// channel: after every processing (AckAuto, the default), after successful
+// processing only (AckOnSuccess), or by the handlers through the Acknowledger
+// carried by the message context (AckManual). Modes other than AckAuto
+// override the mode of the consumer channel.
+//
+// Parameters:
+//   - mode: the acknowledgment mode
+//
+// Returns:
+//   - *EventDrivenConsumerBuilder: builder instance for method chaining
+func (b *EventDrivenConsumerBuilder) WithAckMode(
+	mode handler.AckMode,
+) *EventDrivenConsumerBuilder {
+	b.ackMode = mode
+	return b
+}
+
 // Build constructs an EventDrivenConsumer from the dependency container.
 //
 // Parameters:
@@ -103,11 +240,12 @@ func (b *EventDrivenConsumerBuilder) Build(
 	container container.Container[any, any],
 ) (*EventDrivenConsumer, error) {
 
-	anyChannel, err := container.Get(b.referenceName)
+	anyChannel, err := container.Get(ConsumerChannelKey(b.referenceName))
 	if err != nil {
 		return nil,
 			fmt.Errorf(
-				"[event-driven-consumer] consumer channel %s not found.",
+				"[event-driven-consumer] consumer %w: %s",
+				message.ErrChannelNotFound,
 				b.referenceName,
 			)
 	}
@@ -121,12 +259,92 @@ func (b *EventDrivenConsumerBuilder) Build(
 			)
 	}
 
+	gateway, err := buildInboundGateway(
+		container,
+		inboundChannel,
+		b.beforeInterceptors,
+		b.afterInterceptors,
+		b.ackMode,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := NewEventDrivenConsumer(
+		b.referenceName,
+		gateway,
+		inboundChannel,
+	)
+
+	if optionsChannel, ok := inboundChannel.(processingOptionsProvider); ok {
+		consumer.
+			WithAmountOfProcessors(optionsChannel.AmountOfProcessors()).
+			WithMessageProcessingTimeout(
+				int(optionsChannel.ProcessingTimeout().Milliseconds()),
+			).
+			WithErrorPolicy(optionsChannel.ErrorPolicy())
+	}
+
+	if keyChannel, ok := inboundChannel.(orderingKeyProvider); ok &&
+		keyChannel.OrderingKey() != nil {
+		consumer.orderingKey = keyChannel.OrderingKey()
+	}
+
+	// without a parking channel, messages are not fetched while the circuit
+	// is open
+	if breakerChannel, ok := inboundChannel.(circuitBreakerProvider); ok &&
+		breakerChannel.CircuitBreaker() != nil &&
+		breakerChannel.CircuitBreaker().ParkingChannel() == "" {
+		consumer.circuitBreaker = breakerChannel.CircuitBreaker()
+	}
+
+	return consumer, nil
+}
+
+// buildInboundGateway builds the gateway which processes the messages received
+// by an inbound channel adapter, applying its dead letter, unroutable,
+// interceptors, retry, circuit breaker, quarantine, deduplication,
+// acknowledgment and reply-to settings. The given before interceptors run
+// ahead of the channel ones and the after interceptors run behind them.
+//
+// Parameters:
+//   - container: dependency container
+//   - inboundChannel: the inbound channel adapter
+//   - beforeInterceptors: additional interceptors executed before processing
+//   - afterInterceptors: additional interceptors executed after processing
+//   - ackMode: when the messages are acknowledged, handler.AckAuto applies
+//     the mode of the channel
+//
+// Returns:
+//   - *Gateway: the configured gateway
+//   - error: error if any occurs
+func buildInboundGateway(
+	container container.Container[any, any],
+	inboundChannel InboundChannelAdapter,
+	beforeInterceptors []message.MessageHandler,
+	afterInterceptors []message.MessageHandler,
+	ackMode handler.AckMode,
+) (*Gateway, error) {
 	gatewayBuilder := NewGatewayBuilder(inboundChannel.ReferenceName(), "")
 
+	if len(beforeInterceptors) > 0 {
+		gatewayBuilder.WithBeforeInterceptors(beforeInterceptors...)
+	}
+
 	if inboundChannel.DeadLetterChannelName() != "" {
 		gatewayBuilder.WithDeadLetterChannel(inboundChannel.DeadLetterChannelName())
 	}
 
+	if routesChannel, ok := inboundChannel.(deadLetterRoutesProvider); ok &&
+		len(routesChannel.DeadLetterRoutes()) > 0 {
+		gatewayBuilder.WithDeadLetterRoutes(routesChannel.DeadLetterRoutes())
+	}
+
+	if strictChannel, ok := inboundChannel.(unroutableChannelProvider); ok &&
+		strictChannel.UnroutableChannelName() != "" {
+		gatewayBuilder.WithUnroutableChannel(strictChannel.UnroutableChannelName())
+	}
+
 	if len(inboundChannel.BeforeProcessors()) > 0 {
 		gatewayBuilder.WithBeforeInterceptors(inboundChannel.BeforeProcessors()...)
 	}
@@ -135,30 +353,83 @@ func (b *EventDrivenConsumerBuilder) Build(
 		gatewayBuilder.WithAfterInterceptors(inboundChannel.AfterProcessors()...)
 	}
 
+	if len(afterInterceptors) > 0 {
+		gatewayBuilder.WithAfterInterceptors(afterInterceptors...)
+	}
+
 	if len(inboundChannel.RetryAttempts()) > 0 {
 		gatewayBuilder.WithRetry(inboundChannel.RetryAttempts())
 	}
 
+	if policyChannel, ok := inboundChannel.(retryPolicyProvider); ok &&
+		policyChannel.RetryPolicy() != nil {
+		gatewayBuilder.WithRetryPolicy(policyChannel.RetryPolicy())
+	}
+
+	if breakerChannel, ok := inboundChannel.(circuitBreakerProvider); ok &&
+		breakerChannel.CircuitBreaker() != nil {
+		gatewayBuilder.WithCircuitBreaker(breakerChannel.CircuitBreaker())
+	}
+
+	if poisonChannel, ok := inboundChannel.(poisonMessageQuarantineProvider); ok {
+		channelName, maxFailures, keyExtractor := poisonChannel.PoisonMessageQuarantine()
+		if channelName != "" {
+			gatewayBuilder.WithPoisonMessageQuarantine(channelName, maxFailures, keyExtractor)
+		}
+	}
+
+	if dedupChannel, ok := inboundChannel.(deduplicationProvider); ok {
+		if store, ttl := dedupChannel.Deduplication(); store != nil {
+			gatewayBuilder.WithDeduplication(store, ttl)
+		}
+	}
+
 	if ackChannel, ok := inboundChannel.(handler.ChannelMessageAcknowledgment); ok {
-		gatewayBuilder.WithAcknowledge(ackChannel)
+		if modeChannel, ok := inboundChannel.(ackModeProvider); ok &&
+			ackMode == handler.AckAuto {
+			ackMode = modeChannel.AckMode()
+		}
+		gatewayBuilder.WithAcknowledge(ackChannel).WithAckMode(ackMode)
 	}
 
 	if inboundChannel.SendReplyUsingReplyTo() == true {
 		gatewayBuilder.WithSendReplyUsingReplyTo()
 	}
 
-	gateway, err := gatewayBuilder.Build(container)
-	if err != nil {
-		return nil, err
+	if factoryChannel, ok := inboundChannel.(replyChannelFactoryProvider); ok {
+		if factory, allowedPatterns := factoryChannel.ReplyChannelFactory(); factory != nil {
+			gatewayBuilder.WithReplyChannelFactory(factory, allowedPatterns...)
+		}
 	}
 
-	consumer := NewEventDrivenConsumer(
-		b.referenceName,
-		gateway,
-		inboundChannel,
-	)
+	if atomicChannel, ok := inboundChannel.(atomicReplyProvider); ok &&
+		atomicChannel.AtomicReply() {
+		gatewayBuilder.WithAtomicReply()
+	}
 
-	return consumer, nil
+	if responseChannel, ok := inboundChannel.(responseChannelProvider); ok &&
+		responseChannel.ResponseChannelName() != "" {
+		gatewayBuilder.WithResponseChannelName(responseChannel.ResponseChannelName())
+	}
+
+	if filterChannel, ok := inboundChannel.(filterProvider); ok {
+		if filter, discardChannelName := filterChannel.Filter(); filter != nil {
+			gatewayBuilder.WithFilter(filter, discardChannelName)
+		}
+	}
+
+	if routerChannel, ok := inboundChannel.(routerProvider); ok &&
+		routerChannel.Router() != nil {
+		gatewayBuilder.WithRouter(routerChannel.Router())
+	}
+
+	if tapChannel, ok := inboundChannel.(wireTapProvider); ok {
+		if channelName, sampling := tapChannel.WireTap(); channelName != "" {
+			gatewayBuilder.WithWireTap(channelName, sampling)
+		}
+	}
+
+	return gatewayBuilder.Build(container)
 }
 
 // WithMessageProcessingTimeout sets the message processing timeout in milliseconds.
@@ -177,13 +448,37 @@ func (b *EventDrivenConsumer) WithMessageProcessingTimeout(
 	return b
 }
 
+// WithRouteTimeout sets the processing timeout of the messages of a route,
+// overriding the timeout set by WithMessageProcessingTimeout. The
+// processingTimeout header of a message, as a duration string, overrides both.
+//
+// Parameters:
+//   - route: the message route
+//   - timeout: processing timeout of the route messages
+//
+// Returns:
+//   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
+func (b *EventDrivenConsumer) WithRouteTimeout(
+	route string,
+	timeout time.Duration,
+) *EventDrivenConsumer {
+	if timeout > 0 {
+		if b.routeTimeouts == nil {
+			b.routeTimeouts = map[string]time.Duration{}
+		}
+		b.routeTimeouts[route] = timeout
+	}
+	return b
+}
+
 // WithAmountOfProcessors sets the number of concurrent processors.
 //
 // default value: 1
 //
 // Warning: If the order of message processing is crucial (such as data streaming),
 // it is not recommended to configure this setting, as we do not guarantee the
-// processing order in parallel goroutines.
+// processing order in parallel goroutines. Use WithOrderingKey to keep the
+// order of related messages.
 //
 // Parameters:
 //   - value: number of processors
@@ -197,70 +492,370 @@ func (b *EventDrivenConsumer) WithAmountOfProcessors(value int) *EventDrivenCons
 	return b
 }
 
+// WithOrderingKey keeps the processing order of related messages while
+// running multiple processors: messages are hashed by their key to a fixed
+// processor, so messages sharing a key (e.g. an aggregate id) are processed
+// sequentially while different keys are processed in parallel.
+//
+// Parameters:
+//   - key: extracts the ordering key of a message, nil disables ordering
+//
+// Returns:
+//   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
+func (b *EventDrivenConsumer) WithOrderingKey(
+	key func(*message.Message) string,
+) *EventDrivenConsumer {
+	b.orderingKey = key
+	return b
+}
+
+// WithPriorityExtractor places the received messages into priority queues,
+// buffering up to ten messages per processor, so the processors take the
+// messages of higher priority first. Priorities are clamped between 0 and
+// MaxConsumerPriority. Priorities are ignored when messages are ordered by
+// key with WithOrderingKey.
+//
+// Parameters:
+//   - extract: extracts the priority of a message, e.g.
+//     (*message.Message).Priority, nil disables the priority queues
+//
+// Returns:
+//   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
+func (b *EventDrivenConsumer) WithPriorityExtractor(
+	extract func(*message.Message) int,
+) *EventDrivenConsumer {
+	b.priorityExtractor = extract
+	return b
+}
+
+// WithRateLimit limits how many messages per second are dispatched to the
+// processors, allowing bursts up to burst messages, so a hot channel cannot
+// overload downstream dependencies. Values lower than or equal to zero
+// disable the limit.
+//
+// Parameters:
+//   - msgsPerSecond: messages dispatched per second
+//   - burst: messages dispatched at once after an idle period
+//
+// Returns:
+//   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
+func (b *EventDrivenConsumer) WithRateLimit(
+	msgsPerSecond float64,
+	burst int,
+) *EventDrivenConsumer {
+	b.rateLimiter = nil
+	if msgsPerSecond > 0 {
+		b.rateLimiter = newTokenBucket(msgsPerSecond, burst)
+	}
+	return b
+}
+
 // WithStopOnError sets the stop run when error occured.
 //
 // default value: true
 //
+// Deprecated: use WithErrorPolicy; true maps to ErrorPolicyStop and false to
+// ErrorPolicyContinue.
+//
 // Parameters:
 //   - value: flag(bool)
 //
 // Returns:
 //   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
 func (b *EventDrivenConsumer) WithStopOnError(value bool) *EventDrivenConsumer {
-	b.stopOnError = value
+	if value {
+		return b.WithErrorPolicy(ErrorPolicyStop)
+	}
+	return b.WithErrorPolicy(ErrorPolicyContinue)
+}
+
+// WithErrorPolicy sets how the consumer reacts to receive and processing
+// errors.
+//
+// default value: ErrorPolicyStop
+//
+// Parameters:
+//   - policy: the error policy
+//
+// Returns:
+//   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
+func (b *EventDrivenConsumer) WithErrorPolicy(policy ErrorPolicy) *EventDrivenConsumer {
+	b.errorPolicy = policy
 	return b
 }
 
-// Run starts processing messages received from the input channel.
+// WithErrorAlert sets the function notified of the errors alerted by
+// ErrorPolicyPauseAndAlert, e.g. to page the team owning the consumer. The
+// errors are always logged.
+//
+// Parameters:
+//   - alert: receives the consumer name and the alerted error
+//
+// Returns:
+//   - *EventDrivenConsumer: pointer to EventDrivenConsumer for method chaining
+func (b *EventDrivenConsumer) WithErrorAlert(
+	alert func(consumerName string, err error),
+) *EventDrivenConsumer {
+	b.errorAlert = alert
+	return b
+}
+
+// Run starts processing messages received from the input channel, blocking
+// until the consumer stops. The terminal error is also available through Err
+// once Done is closed.
 //
 // Parameters:
 //   - ctx: context for cancellation and timeout control
 //
 // Returns:
-//   - error: error if any occurs
+//   - error: the error stopping the consumer, e.g. a receive or processing
+//     error under ErrorPolicyStop or a terminal failure of the channel; the
+//     context error when ctx is done; nil when stopped through Stop or Drain
 func (e *EventDrivenConsumer) Run(ctx context.Context) error {
+	err := e.run(ctx)
+	e.terminate(err)
+	return err
+}
+
+// Done returns a channel closed when Run returns, after the consumer shut
+// down.
+//
+// Returns:
+//   - <-chan struct{}: the channel closed on termination
+func (e *EventDrivenConsumer) Done() <-chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.terminatedChannel()
+}
+
+// Err returns the terminal error of the consumer, nil while it is running or
+// when it stopped cleanly.
+//
+// Returns:
+//   - error: the error returned by Run
+func (e *EventDrivenConsumer) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.terminalErr
+}
+
+// terminate records the terminal error and closes the Done channel.
+func (e *EventDrivenConsumer) terminate(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.terminalErr = err
+	terminated := e.terminatedChannel()
+	select {
+	case <-terminated:
+	default:
+		close(terminated)
+	}
+}
+
+// terminatedChannel returns the Done channel, creating it on first use. The
+// caller must hold the consumer lock.
+func (e *EventDrivenConsumer) terminatedChannel() chan struct{} {
+	if e.terminated == nil {
+		e.terminated = make(chan struct{})
+	}
+	return e.terminated
+}
+
+// terminalError returns the error ending a run: the cause of the parent
+// context or of the stop, or nil when stopped through Stop or Drain.
+func terminalError(ctx context.Context, runCtx context.Context) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	if runCtx.Err() == nil {
+		return nil
+	}
+	if cause := context.Cause(runCtx); cause != context.Canceled {
+		return cause
+	}
+	return nil
+}
+
+// run receives messages and dispatches them to the processors until the
+// consumer stops.
+func (e *EventDrivenConsumer) run(ctx context.Context) error {
 	slog.Info(
 		"[event-driven-consumer] started.",
 		"consumerName", e.referenceName,
 	)
 
 	runCtx, cancelRunCtx := context.WithCancelCause(ctx)
+	receiveCtx, cancelReceiveCtx := context.WithCancel(runCtx)
+	defer cancelRunCtx(nil)
+	defer cancelReceiveCtx()
+
+	e.mu.Lock()
+	e.receiveCancelFunc = cancelReceiveCtx
+	e.done = make(chan struct{})
+	defer close(e.done)
+	e.mu.Unlock()
+
 	defer e.shutdown()
 	e.runCancelCtxFunc = cancelRunCtx
 
-	e.processingQueue = make(chan *message.Message, e.amountOfProcessors)
+	e.processingQueues = e.makeProcessingQueues()
 	e.stopTrigger = make(chan error)
 	e.startProcessorsNodes(runCtx)
 
+	if monitor, ok := e.inboundChannelAdapter.(backlogMonitorProvider); ok {
+		go monitor.MonitorBacklog(receiveCtx)
+	}
+
 	for {
-		select {
-		case <-runCtx.Done():
-			return context.Cause(runCtx)
-		default:
+		if receiveCtx.Err() != nil {
+			return terminalError(ctx, runCtx)
 		}
 
-		msg, err := e.inboundChannelAdapter.ReceiveMessage(runCtx)
+		if resumed := e.pausedUntil(); resumed != nil {
+			select {
+			case <-receiveCtx.Done():
+				return terminalError(ctx, runCtx)
+			case <-resumed:
+			}
+			continue
+		}
+
+		if e.circuitBreaker != nil &&
+			e.circuitBreaker.WaitUntilClosed(receiveCtx) != nil {
+			continue
+		}
+
+		msg, err := e.inboundChannelAdapter.ReceiveMessage(receiveCtx)
 		if err != nil {
+			if receiveCtx.Err() != nil {
+				if runCtx.Err() == nil {
+					slog.Info(
+						"[event-driven-consumer] stopped receiving messages, draining.",
+						"consumerName", e.referenceName,
+					)
+				}
+				return terminalError(ctx, runCtx)
+			}
+			if failingChannel, ok := e.inboundChannelAdapter.(failingChannelProvider); ok &&
+				failingChannel.Err() != nil {
+				slog.Error("[event-driven-consumer] consumer channel failed",
+					"consumer.name", e.referenceName,
+					"error", failingChannel.Err(),
+				)
+				e.stop(failingChannel.Err())
+				return failingChannel.Err()
+			}
 			if err != context.Canceled {
 				slog.Error("[event-driven-consumer] message receive error",
 					"consumer.name", e.referenceName,
 					"error", err,
 				)
 			}
-			if e.stopOnError {
+			switch e.errorPolicy {
+			case ErrorPolicyStop:
 				e.stop(err)
 				return err
+			case ErrorPolicyPauseAndAlert:
+				e.alert(err)
 			}
+			continue
+		}
+
+		if e.rateLimiter != nil && msg != nil {
+			// a cancelled wait still dispatches the message already received
+			e.rateLimiter.Wait(receiveCtx)
+		}
+
+		if e.priorityQueue != nil {
+			if msg == nil {
+				continue
+			}
+			select {
+			case <-e.stopTrigger:
+				return terminalError(ctx, runCtx)
+			case e.priorityQueue.slots <- struct{}{}:
+				e.priorityQueue.push(msg, e.priorityExtractor(msg))
+			}
+			continue
 		}
 
 		select {
-		case err := <-e.stopTrigger:
-			return err
-		case e.processingQueue <- msg:
+		case <-e.stopTrigger:
+			return terminalError(ctx, runCtx)
+		case e.dispatchQueue(msg) <- msg:
 		}
 	}
 }
 
+// Drain stops receiving new messages and waits until the messages already
+// received are processed and the consumer has shut down. Unlike Stop, the
+// in-flight messages are not cancelled.
+//
+// Parameters:
+//   - ctx: context bounding how long to wait for in-flight messages
+//
+// Returns:
+//   - error: the context error if the consumer did not drain in time
+func (e *EventDrivenConsumer) Drain(ctx context.Context) error {
+	e.mu.Lock()
+	cancelReceive := e.receiveCancelFunc
+	done := e.done
+	e.mu.Unlock()
+
+	if cancelReceive == nil {
+		return nil
+	}
+
+	cancelReceive()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pause stops fetching messages from the inbound channel until Resume is
+// called. The connection and the consumer group membership are kept, and the
+// messages already fetched are still processed.
+func (e *EventDrivenConsumer) Pause() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.resumed != nil {
+		return
+	}
+	e.resumed = make(chan struct{})
+	slog.Info("[event-driven-consumer] paused.", "consumerName", e.referenceName)
+}
+
+// Resume restarts fetching messages from the inbound channel after Pause.
+func (e *EventDrivenConsumer) Resume() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.resumed == nil {
+		return
+	}
+	close(e.resumed)
+	e.resumed = nil
+	slog.Info("[event-driven-consumer] resumed.", "consumerName", e.referenceName)
+}
+
+// IsPaused reports whether the consumer is paused.
+//
+// Returns:
+//   - bool: true if fetching messages is paused
+func (e *EventDrivenConsumer) IsPaused() bool {
+	return e.pausedUntil() != nil
+}
+
+// pausedUntil returns the channel closed when the consumer resumes, or nil if
+// it is not paused.
+func (e *EventDrivenConsumer) pausedUntil() chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.resumed
+}
+
 // sendToGateway sends the message to the gateway for processing.
 //
 // Parameters:
@@ -277,14 +872,22 @@ func (e *EventDrivenConsumer) sendToGateway(
 	default:
 	}
 
-	opCtx, cancel := context.WithTimeout(
-		ctx,
-		time.Duration(e.processingTimeoutMilliseconds)*time.Millisecond,
-	)
-	defer cancel()
-
 	header := msg.GetHeader()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicErr := message.NewPanicError(recovered, debug.Stack())
+			e.recordError(panicErr)
+			slog.Error("[event-driven-consumer] message processing panicked.",
+				"consumer.name", e.referenceName,
+				"consumer.nodeId", nodeId,
+				"consumer.messageId", header.Get(message.HeaderMessageId),
+				"consumer.error", panicErr.Error(),
+				"consumer.stack", panicErr.Stack,
+			)
+		}
+	}()
 
+	opCtx := ctx
 	var span otel.OtelSpan
 	if msg.GetContext() != nil {
 		opCtx, span = e.otelTrace.Start(
@@ -298,15 +901,20 @@ func (e *EventDrivenConsumer) sendToGateway(
 		defer span.End()
 	}
 
+	opCtx, cancel := context.WithTimeout(opCtx, e.processingTimeout(msg))
+	defer cancel()
+
 	slog.Info("[event-driven-consumer] message processing started.",
 		"consumer.name", e.referenceName,
 		"consumer.nodeId", nodeId,
 		"consumer.messageId", header.Get(message.HeaderMessageId),
 	)
+	message.RecordHistory(msg, message.HistoryComponentConsumer, e.referenceName)
 	_, err := e.gateway.Execute(opCtx, msg)
 	spanStatus := otel.SpanStatusOK
 	if err != nil {
 		spanStatus = otel.SpanStatusError
+		e.recordError(err)
 		slog.Error("[event-driven-consumer] processing message error.",
 			"consumer.name", e.referenceName,
 			"consumer.nodeId", nodeId,
@@ -318,12 +926,15 @@ func (e *EventDrivenConsumer) sendToGateway(
 			span.Error(err, "[event-driven-consumer] processing message error.")
 		}
 
-		if e.stopOnError {
+		if e.stopOnProcessingError(err) {
 			e.stop(err)
 			return
 		}
 	}
 
+	if err == nil {
+		e.processedCounter.Add(1)
+	}
 	if span != nil {
 		span.SetStatus(spanStatus, "[event-driven-consumer] message processed completed.")
 	}
@@ -335,11 +946,159 @@ func (e *EventDrivenConsumer) sendToGateway(
 	)
 }
 
+// Stats returns a snapshot of the processing statistics of the consumer.
+//
+// Returns:
+//   - ConsumerStats: the processing statistics
+func (e *EventDrivenConsumer) Stats() ConsumerStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return ConsumerStats{
+		Processed:   e.processedCounter.Load(),
+		Failed:      e.failedCounter.Load(),
+		Unroutable:  e.gateway.Unroutable(),
+		Paused:      e.resumed != nil,
+		LastError:   e.lastError,
+		LastErrorAt: e.lastErrorAt,
+	}
+}
+
+// Consumer statuses reported by Status.
+const (
+	ConsumerStatusIdle    = "idle"
+	ConsumerStatusRunning = "running"
+	ConsumerStatusPaused  = "paused"
+	ConsumerStatusStopped = "stopped"
+)
+
+// Status returns the status of the consumer: idle before Run, running,
+// paused or stopped after Run returns.
+//
+// Returns:
+//   - string: the consumer status
+func (e *EventDrivenConsumer) Status() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done == nil {
+		return ConsumerStatusIdle
+	}
+	select {
+	case <-e.done:
+		return ConsumerStatusStopped
+	default:
+	}
+	if e.resumed != nil {
+		return ConsumerStatusPaused
+	}
+	return ConsumerStatusRunning
+}
+
+// ConsumerSettings describes the configuration of a consumer.
+type ConsumerSettings struct {
+	// Channel is the reference name of the inbound channel.
+	Channel string `json:"channel"`
+	// AmountOfProcessors is the amount of concurrent processors.
+	AmountOfProcessors int `json:"amountOfProcessors"`
+	// ProcessingTimeout is the message processing timeout.
+	ProcessingTimeout time.Duration `json:"processingTimeout"`
+	// StopOnError reports whether the consumer stops when a message fails.
+	StopOnError bool `json:"stopOnError"`
+	// ErrorPolicy is the name of the error policy of the consumer.
+	ErrorPolicy string `json:"errorPolicy"`
+}
+
+// Settings returns the configuration of the consumer.
+//
+// Returns:
+//   - ConsumerSettings: the consumer configuration
+func (e *EventDrivenConsumer) Settings() ConsumerSettings {
+	return ConsumerSettings{
+		Channel:            e.inboundChannelAdapter.ReferenceName(),
+		AmountOfProcessors: e.amountOfProcessors,
+		ProcessingTimeout: time.Duration(e.processingTimeoutMilliseconds) *
+			time.Millisecond,
+		StopOnError: e.errorPolicy == ErrorPolicyStop,
+		ErrorPolicy: e.errorPolicy.String(),
+	}
+}
+
+// recordError records a processing error in the statistics.
+// stopOnProcessingError applies the error policy to a processing error,
+// reporting whether the consumer must stop. A request whose atomic reply was
+// not published is left unacknowledged, so the consumer stops whatever its
+// policy for the broker to redeliver it.
+func (e *EventDrivenConsumer) stopOnProcessingError(err error) bool {
+	if handler.IsReplyNotPublished(err) {
+		e.alert(err)
+		return true
+	}
+	switch e.errorPolicy {
+	case ErrorPolicyStop:
+		return true
+	case ErrorPolicyDLQAndContinue:
+		return !handler.IsDeadLettered(err)
+	case ErrorPolicyPauseAndAlert:
+		e.Pause()
+		e.alert(err)
+	}
+	return false
+}
+
+// alert logs an error alerted by the error policy and notifies the error
+// alert function.
+func (e *EventDrivenConsumer) alert(err error) {
+	slog.Error("[event-driven-consumer] error alert.",
+		"consumer.name", e.referenceName,
+		"consumer.errorPolicy", e.errorPolicy.String(),
+		"error", err,
+	)
+	if e.errorAlert != nil {
+		e.errorAlert(e.referenceName, err)
+	}
+}
+
+func (e *EventDrivenConsumer) recordError(err error) {
+	e.failedCounter.Add(1)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastError = err.Error()
+	e.lastErrorAt = time.Now()
+}
+
+// processingTimeout returns the processing timeout of a message: its
+// processingTimeout header, its route timeout or the consumer timeout.
+func (e *EventDrivenConsumer) processingTimeout(msg *message.Message) time.Duration {
+	if value := msg.GetHeader().Get(message.HeaderProcessingTimeout); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err == nil && timeout > 0 {
+			return timeout
+		}
+		slog.Warn("[event-driven-consumer] invalid processing timeout header, ignored.",
+			"consumer.name", e.referenceName,
+			"consumer.messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"header", value,
+		)
+	}
+	if timeout, ok := e.routeTimeouts[msg.GetHeader().Get(message.HeaderRoute)]; ok {
+		return timeout
+	}
+	return time.Duration(e.processingTimeoutMilliseconds) * time.Millisecond
+}
+
 // Stop requests the consumer to stop by canceling the internal context.
 func (e *EventDrivenConsumer) Stop() {
 	e.stop(nil)
 }
 
+// Close releases the input channel of a consumer which was built but never
+// run. A running consumer closes its input channel when it stops.
+//
+// Returns:
+//   - error: error if the input channel cannot be closed
+func (e *EventDrivenConsumer) Close() error {
+	return e.inboundChannelAdapter.Close()
+}
+
 func (e *EventDrivenConsumer) stop(err error) {
 	e.once.Do(func() {
 		if e.runCancelCtxFunc != nil {
@@ -352,31 +1111,76 @@ func (e *EventDrivenConsumer) stop(err error) {
 	})
 }
 
-// shutdown ends processing, closes the input channel and waits for processors to finish.
+// shutdown ends processing, waits for processors to finish and closes the
+// input channel.
 func (e *EventDrivenConsumer) shutdown() {
 
 	slog.Info("[event-driven-consumer] shutting down.",
 		"consumerName", e.referenceName,
 	)
 
-	e.inboundChannelAdapter.Close()
-	close(e.processingQueue)
+	for _, queue := range e.processingQueues {
+		close(queue)
+	}
+	if e.priorityQueue != nil {
+		e.priorityQueue.close()
+	}
 	e.processorsWaitGroup.Wait()
+	e.inboundChannelAdapter.Close()
 	e.once.Do(func() {
 		close(e.stopTrigger)
 	})
 }
 
+// makeProcessingQueues creates the queues feeding the processors: a queue
+// shared by every processor, a queue per processor when messages are ordered
+// by key, or the priority queue when messages are prioritized.
+func (e *EventDrivenConsumer) makeProcessingQueues() []chan *message.Message {
+	e.priorityQueue = nil
+	if e.orderingKey == nil && e.priorityExtractor != nil {
+		e.priorityQueue = newPriorityQueue(
+			e.amountOfProcessors * (MaxConsumerPriority + 1),
+		)
+		return nil
+	}
+	if e.orderingKey == nil {
+		return []chan *message.Message{
+			make(chan *message.Message, e.amountOfProcessors),
+		}
+	}
+	queues := make([]chan *message.Message, e.amountOfProcessors)
+	for i := range queues {
+		queues[i] = make(chan *message.Message, 1)
+	}
+	return queues
+}
+
+// dispatchQueue returns the queue of the processor handling the message.
+func (e *EventDrivenConsumer) dispatchQueue(msg *message.Message) chan *message.Message {
+	if len(e.processingQueues) == 1 || msg == nil {
+		return e.processingQueues[0]
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(e.orderingKey(msg)))
+	return e.processingQueues[hash.Sum32()%uint32(len(e.processingQueues))]
+}
+
 // startProcessorsNodes starts concurrent processors to consume messages from the queue.
 func (e *EventDrivenConsumer) startProcessorsNodes(ctx context.Context) {
 	for i := 0; i < e.amountOfProcessors; i++ {
 		e.processorsWaitGroup.Add(1)
 		go func(workerId int) {
 			defer e.processorsWaitGroup.Done()
-			for msg := range e.processingQueue {
+			if e.priorityQueue != nil {
+				for range e.priorityQueue.items {
+					e.sendToGateway(ctx, e.priorityQueue.pop(), workerId)
+				}
+			} else {
+				for msg := range e.processingQueues[workerId%len(e.processingQueues)] {
 
-				if msg != nil {
-					e.sendToGateway(ctx, msg, workerId)
+					if msg != nil {
+						e.sendToGateway(ctx, msg, workerId)
+					}
 				}
 			}
 
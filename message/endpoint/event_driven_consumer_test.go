@@ -2,7 +2,10 @@ package endpoint_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/channel"
 	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
 
 // fakeInboundAdapter is a lightweight test double for InboundChannelAdapter.
@@ -87,6 +91,21 @@ func (d *dummyEventDrivenGatewayHandler) Handle(
 	return msg, nil
 }
 
+// optionsInboundAdapter is an inbound adapter configured with the
+// processing options of its consumers.
+type optionsInboundAdapter struct {
+	*fakeInboundAdapter
+	processors  int
+	timeout     time.Duration
+	errorPolicy endpoint.ErrorPolicy
+}
+
+func (o *optionsInboundAdapter) AmountOfProcessors() int          { return o.processors }
+func (o *optionsInboundAdapter) ProcessingTimeout() time.Duration { return o.timeout }
+func (o *optionsInboundAdapter) ErrorPolicy() endpoint.ErrorPolicy {
+	return o.errorPolicy
+}
+
 func TestNewEventDrivenConsumerBuilder_Build(t *testing.T) {
 	t.Run("builds EventDrivenConsumer successfully", func(t *testing.T) {
 		t.Parallel()
@@ -94,7 +113,7 @@ func TestNewEventDrivenConsumerBuilder_Build(t *testing.T) {
 		cont.Set("dlq", channel.NewPointToPointChannel("dlq"))
 
 		in := &fakeInboundAdapter{nil, "dlq"}
-		cont.Set("ref", in)
+		cont.Set(endpoint.ConsumerChannelKey("ref"), in)
 		got, err := endpoint.NewEventDrivenConsumerBuilder("ref").
 			Build(cont)
 
@@ -106,6 +125,46 @@ func TestNewEventDrivenConsumerBuilder_Build(t *testing.T) {
 		}
 	})
 
+	t.Run("builds EventDrivenConsumer with additional interceptors", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set(endpoint.ConsumerChannelKey("ref"), &fakeInboundAdapter{nil, ""})
+		got, err := endpoint.NewEventDrivenConsumerBuilder("ref").
+			WithBeforeInterceptors(&dummyEventDrivenGatewayHandler{nil}).
+			WithAfterInterceptors(&dummyEventDrivenGatewayHandler{nil}).
+			Build(cont)
+
+		if err != nil {
+			t.Errorf("Expected success, got error: %v", err)
+		}
+		if got == nil {
+			t.Error("Expected EventDrivenConsumer instance, got nil")
+		}
+	})
+
+	t.Run("applies the processing options of the channel", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set(endpoint.ConsumerChannelKey("ref"), &optionsInboundAdapter{
+			&fakeInboundAdapter{nil, ""},
+			4,
+			5 * time.Second,
+			endpoint.ErrorPolicyContinue,
+		})
+		got, err := endpoint.NewEventDrivenConsumerBuilder("ref").
+			Build(cont)
+		if err != nil {
+			t.Fatalf("Expected success, got error: %v", err)
+		}
+
+		settings := got.Settings()
+		if settings.AmountOfProcessors != 4 ||
+			settings.ProcessingTimeout != 5*time.Second ||
+			settings.ErrorPolicy != "continue" {
+			t.Errorf("expected the channel processing options, got %+v", settings)
+		}
+	})
+
 	t.Run("Fails to build when gateway not found", func(t *testing.T) {
 		t.Parallel()
 		cont := container.NewGenericContainer[any, any]()
@@ -114,8 +173,8 @@ func TestNewEventDrivenConsumerBuilder_Build(t *testing.T) {
 		if got != nil {
 			t.Errorf("Expected nil, got: %v", got)
 		}
-		if err == nil || err.Error() != "[event-driven-consumer] consumer channel ref not found." {
-			t.Errorf("Expected error '[event-driven-consumer] consumer channel ref not found.', got: %v", err)
+		if !errors.Is(err, message.ErrChannelNotFound) {
+			t.Errorf("Expected ErrChannelNotFound, got: %v", err)
 		}
 	})
 
@@ -123,7 +182,7 @@ func TestNewEventDrivenConsumerBuilder_Build(t *testing.T) {
 		t.Parallel()
 		cont := container.NewGenericContainer[any, any]()
 		in := "invalid adapter"
-		cont.Set("ref", in)
+		cont.Set(endpoint.ConsumerChannelKey("ref"), in)
 		got, err := endpoint.NewEventDrivenConsumerBuilder("ref").
 			Build(cont)
 		fmt.Println(got, err)
@@ -140,7 +199,8 @@ func TestNewEventDrivenConsumerBuilder_Build(t *testing.T) {
 		cont := container.NewGenericContainer[any, any]()
 
 		in := &fakeInboundAdapter{nil, "dlq"}
-		cont.Set("ref", in)
+		cont.Set(endpoint.ConsumerChannelKey("ref"), in)
+		cont.Set("dlq", "not a publisher channel")
 		got, err := endpoint.NewEventDrivenConsumerBuilder("ref").
 			Build(cont)
 
@@ -277,6 +337,611 @@ func TestEventDrivenConsumer_Run(t *testing.T) {
 	})
 }
 
+// failingInboundAdapter is an inbound adapter whose consumption stopped with
+// a terminal failure.
+type failingInboundAdapter struct {
+	*fakeInboundAdapter
+	err error
+}
+
+func (f *failingInboundAdapter) ReceiveMessage(ctx context.Context) (*message.Message, error) {
+	return nil, f.err
+}
+
+func (f *failingInboundAdapter) Err() error {
+	return f.err
+}
+
+func TestEventDrivenConsumer_RunStopsOnTerminalFailure(t *testing.T) {
+	t.Parallel()
+	terminalErr := errors.New("consumer cancelled by the broker")
+	in := &failingInboundAdapter{&fakeInboundAdapter{}, terminalErr}
+	gw := endpoint.NewGateway(&dummyEventDrivenGatewayHandler{}, "", "")
+	consumer := endpoint.NewEventDrivenConsumer("ref", gw, in).WithStopOnError(false)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- consumer.Run(context.Background())
+	}()
+
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, terminalErr) {
+			t.Errorf("expected terminal failure, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("consumer did not stop on the terminal failure")
+	}
+}
+
+func TestEventDrivenConsumer_DoneAndErr(t *testing.T) {
+	t.Run("exposes the terminal error", func(t *testing.T) {
+		t.Parallel()
+		terminalErr := errors.New("consumer cancelled by the broker")
+		in := &failingInboundAdapter{&fakeInboundAdapter{}, terminalErr}
+		gw := endpoint.NewGateway(&dummyEventDrivenGatewayHandler{}, "", "")
+		consumer := endpoint.NewEventDrivenConsumer("ref", gw, in)
+		done := consumer.Done()
+
+		go consumer.Run(context.Background())
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected Done to be closed when the consumer stops")
+		}
+		if err := consumer.Err(); !errors.Is(err, terminalErr) {
+			t.Errorf("expected terminal failure, got %v", err)
+		}
+	})
+
+	t.Run("reports no error when stopped", func(t *testing.T) {
+		t.Parallel()
+		in := &fakeInboundAdapter{ch: channel.NewPointToPointChannel("in")}
+		gw := endpoint.NewGateway(&dummyEventDrivenGatewayHandler{}, "", "")
+		consumer := endpoint.NewEventDrivenConsumer("ref", gw, in)
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- consumer.Run(context.Background())
+		}()
+		time.Sleep(50 * time.Millisecond)
+		select {
+		case <-consumer.Done():
+			t.Fatal("expected Done to stay open while running")
+		default:
+		}
+		consumer.Stop()
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("consumer did not stop")
+		}
+		<-consumer.Done()
+		if err := consumer.Err(); err != nil {
+			t.Errorf("expected no terminal error, got %v", err)
+		}
+	})
+}
+
+func TestEventDrivenConsumer_Drain(t *testing.T) {
+	t.Run("finishes in-flight message before returning", func(t *testing.T) {
+		t.Parallel()
+		inChannel := channel.NewPointToPointChannel("in")
+		outChannel := make(chan any, 1)
+		in := &fakeInboundAdapter{ch: inChannel}
+
+		gw := endpoint.NewGateway(&dummyEventDrivenGatewayHandler{response: outChannel}, "", "")
+		consumer := endpoint.NewEventDrivenConsumer("ref", gw, in)
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- consumer.Run(context.Background())
+		}()
+
+		msg := message.NewMessageBuilder().
+			WithChannelName("in").
+			WithMessageType(message.Command).
+			WithPayload("payload").
+			WithContext(context.Background()).
+			Build()
+		inChannel.Send(context.Background(), msg)
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := consumer.Drain(ctx); err != nil {
+			t.Fatalf("expected drain to complete, got: %v", err)
+		}
+
+		select {
+		case res := <-outChannel:
+			if _, ok := res.(*message.Message); !ok {
+				t.Errorf("expected in-flight message to be processed, got: %v", res)
+			}
+		default:
+			t.Error("expected in-flight message to be processed before drain returned")
+		}
+
+		if err := <-errChan; err != nil {
+			t.Errorf("expected Run to return nil after drain, got: %v", err)
+		}
+	})
+
+	t.Run("returns immediately when not running", func(t *testing.T) {
+		t.Parallel()
+		consumer := endpoint.NewEventDrivenConsumer("ref", nil, nil)
+		if err := consumer.Drain(context.Background()); err != nil {
+			t.Errorf("expected nil, got: %v", err)
+		}
+	})
+}
+
+func TestEventDrivenConsumer_PauseResume(t *testing.T) {
+	t.Parallel()
+	inChannel := channel.NewPointToPointChannel("in")
+	outChannel := make(chan any, 1)
+	in := &fakeInboundAdapter{ch: inChannel}
+
+	gw := endpoint.NewGateway(&dummyEventDrivenGatewayHandler{response: outChannel}, "", "")
+	consumer := endpoint.NewEventDrivenConsumer("ref", gw, in)
+	consumer.Pause()
+	if !consumer.IsPaused() {
+		t.Fatal("expected consumer to be paused")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Run(ctx)
+
+	msg := message.NewMessageBuilder().
+		WithChannelName("in").
+		WithMessageType(message.Command).
+		WithPayload("payload").
+		WithContext(context.Background()).
+		Build()
+	sent := make(chan struct{})
+	go func() {
+		inChannel.Send(context.Background(), msg)
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("expected no message to be fetched while paused")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	consumer.Resume()
+	if consumer.IsPaused() {
+		t.Fatal("expected consumer to be resumed")
+	}
+	select {
+	case res := <-outChannel:
+		if _, ok := res.(*message.Message); !ok {
+			t.Errorf("expected message to be processed, got: %v", res)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected message to be processed after resume")
+	}
+}
+
+// countingHandler counts the processed messages.
+// failingGatewayHandler fails the processing of every message.
+type failingGatewayHandler struct {
+	err error
+}
+
+func (f *failingGatewayHandler) Handle(
+	_ context.Context,
+	_ *message.Message,
+) (*message.Message, error) {
+	return nil, f.err
+}
+
+func TestEventDrivenConsumer_WithErrorPolicy(t *testing.T) {
+	processingErr := errors.New("processing failed")
+	cases := []struct {
+		name       string
+		policy     endpoint.ErrorPolicy
+		deadLetter bool
+		expectStop bool
+	}{
+		{"stop", endpoint.ErrorPolicyStop, false, true},
+		{"continue", endpoint.ErrorPolicyContinue, false, false},
+		{"dlq-and-continue without dead letter", endpoint.ErrorPolicyDLQAndContinue, false, true},
+		{"dlq-and-continue with dead letter", endpoint.ErrorPolicyDLQAndContinue, true, false},
+		{"pause-and-alert", endpoint.ErrorPolicyPauseAndAlert, false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			inChannel := channel.NewPointToPointChannel("in")
+			var gatewayHandler message.MessageHandler = &failingGatewayHandler{err: processingErr}
+			if tc.deadLetter {
+				gatewayHandler = handler.NewDeadLetter(&recordingPublisher{}, gatewayHandler)
+			}
+			alerts := make(chan error, 1)
+			consumer := endpoint.NewEventDrivenConsumer(
+				"ref",
+				endpoint.NewGateway(gatewayHandler, "", ""),
+				&fakeInboundAdapter{ch: inChannel},
+			).
+				WithErrorPolicy(tc.policy).
+				WithErrorAlert(func(_ string, err error) { alerts <- err })
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			result := make(chan error, 1)
+			go func() {
+				result <- consumer.Run(ctx)
+			}()
+			inChannel.Send(ctx, message.NewMessageBuilder().
+				WithMessageType(message.Command).
+				WithPayload("payload").
+				WithContext(ctx).
+				Build())
+
+			select {
+			case err := <-result:
+				if !tc.expectStop {
+					t.Fatalf("expected the consumer to keep running, stopped with %v", err)
+				}
+				if !errors.Is(err, processingErr) {
+					t.Errorf("expected the processing error, got %v", err)
+				}
+				return
+			case <-time.After(300 * time.Millisecond):
+				if tc.expectStop {
+					t.Fatal("expected the consumer to stop")
+				}
+			}
+			if failed := consumer.Stats().Failed; failed != 1 {
+				t.Errorf("expected 1 failed message, got %d", failed)
+			}
+			if tc.policy != endpoint.ErrorPolicyPauseAndAlert {
+				return
+			}
+			select {
+			case err := <-alerts:
+				if !errors.Is(err, processingErr) {
+					t.Errorf("expected the processing error alerted, got %v", err)
+				}
+			default:
+				t.Error("expected the processing error to be alerted")
+			}
+			if !consumer.Stats().Paused {
+				t.Error("expected the consumer to be paused")
+			}
+		})
+	}
+}
+
+type failingReplyPublisher struct{}
+
+func (f *failingReplyPublisher) Name() string { return "replies" }
+
+func (f *failingReplyPublisher) Send(context.Context, *message.Message) error {
+	return errors.New("broker unavailable")
+}
+
+func TestEventDrivenConsumer_StopsWhenAtomicReplyIsNotPublished(t *testing.T) {
+	t.Parallel()
+	replyContainer := container.NewGenericContainer[any, any]()
+	replyContainer.Set("replies", &failingReplyPublisher{})
+	inChannel := channel.NewPointToPointChannel("in")
+	consumer := endpoint.NewEventDrivenConsumer(
+		"ref",
+		endpoint.NewGateway(
+			handler.NewSendReplyToHandler(&dummyGatewayHandler{}, replyContainer).WithAtomicReply(),
+			"",
+			"",
+		),
+		&fakeInboundAdapter{ch: inChannel},
+	).WithErrorPolicy(endpoint.ErrorPolicyContinue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := make(chan error, 1)
+	go func() {
+		result <- consumer.Run(ctx)
+	}()
+	inChannel.Send(ctx, message.NewMessageBuilder().
+		WithMessageType(message.Command).
+		WithReplyTo("replies").
+		WithPayload("payload").
+		WithContext(ctx).
+		Build())
+
+	select {
+	case err := <-result:
+		if !handler.IsReplyNotPublished(err) {
+			t.Errorf("expected the reply not published error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the consumer to stop")
+	}
+}
+
+type countingHandler struct {
+	processed chan time.Time
+}
+
+func (c *countingHandler) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	c.processed <- time.Now()
+	return msg, nil
+}
+
+func TestEventDrivenConsumer_WithRateLimit(t *testing.T) {
+	t.Parallel()
+	inChannel := channel.NewPointToPointChannel("in")
+	in := &fakeInboundAdapter{ch: inChannel}
+	handler := &countingHandler{processed: make(chan time.Time, 4)}
+
+	gw := endpoint.NewGateway(handler, "", "")
+	consumer := endpoint.NewEventDrivenConsumer("ref", gw, in).
+		WithAmountOfProcessors(4).
+		WithRateLimit(20, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Run(ctx)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		inChannel.Send(context.Background(), message.NewMessageBuilder().
+			WithMessageType(message.Command).
+			WithPayload("payload").
+			WithContext(context.Background()).
+			Build())
+	}
+
+	var last time.Time
+	for i := 0; i < 4; i++ {
+		select {
+		case last = <-handler.processed:
+		case <-time.After(3 * time.Second):
+			t.Fatal("expected every message to be processed")
+		}
+	}
+	// 2 messages of burst, then 2 messages at 20 per second
+	if elapsed := last.Sub(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected the dispatching to be rate limited, took %v", elapsed)
+	}
+}
+
+// orderRecordingHandler records the processing order of the messages per key.
+type orderRecordingHandler struct {
+	mu        sync.Mutex
+	order     map[string][]string
+	inFlight  map[string]bool
+	overlaps  int
+	processed chan struct{}
+}
+
+func (o *orderRecordingHandler) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	key := msg.GetHeader().Get("key")
+	o.mu.Lock()
+	if o.inFlight[key] {
+		o.overlaps++
+	}
+	o.inFlight[key] = true
+	o.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	o.mu.Lock()
+	o.inFlight[key] = false
+	o.order[key] = append(o.order[key], msg.GetPayload().(string))
+	o.mu.Unlock()
+	o.processed <- struct{}{}
+	return msg, nil
+}
+
+func TestEventDrivenConsumer_WithOrderingKey(t *testing.T) {
+	t.Parallel()
+	inChannel := channel.NewPointToPointChannel("in")
+	in := &fakeInboundAdapter{ch: inChannel}
+	handler := &orderRecordingHandler{
+		order:     map[string][]string{},
+		inFlight:  map[string]bool{},
+		processed: make(chan struct{}, 20),
+	}
+
+	gw := endpoint.NewGateway(handler, "", "")
+	consumer := endpoint.NewEventDrivenConsumer("ref", gw, in).
+		WithAmountOfProcessors(4).
+		WithOrderingKey(func(msg *message.Message) string {
+			return msg.GetHeader().Get("key")
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Run(ctx)
+
+	keys := []string{"a", "b"}
+	for i := 0; i < 10; i++ {
+		for _, key := range keys {
+			inChannel.Send(context.Background(), message.NewMessageBuilder().
+				WithMessageType(message.Command).
+				WithPayload(fmt.Sprintf("%d", i)).
+				WithCustomHeader("key", key).
+				WithContext(context.Background()).
+				Build())
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		select {
+		case <-handler.processed:
+		case <-time.After(3 * time.Second):
+			t.Fatal("expected every message to be processed")
+		}
+	}
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if handler.overlaps != 0 {
+		t.Errorf("expected no concurrent processing of a key, got %d", handler.overlaps)
+	}
+	for _, key := range keys {
+		for i, payload := range handler.order[key] {
+			if payload != fmt.Sprintf("%d", i) {
+				t.Fatalf("expected key %s processed in order, got %v", key, handler.order[key])
+			}
+		}
+	}
+}
+
+// priorityRecordingHandler records the payloads in processing order, holding
+// the first message until released.
+type priorityRecordingHandler struct {
+	mu        sync.Mutex
+	order     []string
+	started   chan struct{}
+	release   chan struct{}
+	processed chan struct{}
+}
+
+func (p *priorityRecordingHandler) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetPayload() == "first" {
+		close(p.started)
+		<-p.release
+	}
+	p.mu.Lock()
+	p.order = append(p.order, msg.GetPayload().(string))
+	p.mu.Unlock()
+	p.processed <- struct{}{}
+	return msg, nil
+}
+
+func TestEventDrivenConsumer_WithPriorityExtractor(t *testing.T) {
+	t.Parallel()
+	inChannel := channel.NewPointToPointChannel("in")
+	in := &fakeInboundAdapter{ch: inChannel}
+	handler := &priorityRecordingHandler{
+		started:   make(chan struct{}),
+		release:   make(chan struct{}),
+		processed: make(chan struct{}, 10),
+	}
+
+	gw := endpoint.NewGateway(handler, "", "")
+	consumer := endpoint.NewEventDrivenConsumer("ref", gw, in).
+		WithPriorityExtractor((*message.Message).Priority)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Run(ctx)
+
+	send := func(payload string, priority int) {
+		inChannel.Send(context.Background(), message.NewMessageBuilder().
+			WithMessageType(message.Command).
+			WithPayload(payload).
+			WithPriority(priority).
+			WithContext(context.Background()).
+			Build())
+	}
+	send("first", 0)
+	<-handler.started
+	send("low", 1)
+	send("urgent", 20)
+	send("high", 5)
+	time.Sleep(100 * time.Millisecond)
+	close(handler.release)
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-handler.processed:
+		case <-time.After(3 * time.Second):
+			t.Fatal("expected every message to be processed")
+		}
+	}
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	expected := []string{"first", "urgent", "high", "low"}
+	if !slices.Equal(handler.order, expected) {
+		t.Errorf("expected processing order %v, got %v", expected, handler.order)
+	}
+}
+
+// deadlineRecordingHandler records the time left until the processing
+// deadline of each message.
+type deadlineRecordingHandler struct {
+	remaining chan time.Duration
+}
+
+func (d *deadlineRecordingHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	deadline, _ := ctx.Deadline()
+	d.remaining <- time.Until(deadline)
+	return msg, nil
+}
+
+func TestEventDrivenConsumer_WithRouteTimeout(t *testing.T) {
+	t.Parallel()
+	inChannel := channel.NewPointToPointChannel("in")
+	in := &fakeInboundAdapter{ch: inChannel}
+	handler := &deadlineRecordingHandler{remaining: make(chan time.Duration, 1)}
+
+	gw := endpoint.NewGateway(handler, "", "")
+	consumer := endpoint.NewEventDrivenConsumer("ref", gw, in).
+		WithMessageProcessingTimeout(60000).
+		WithRouteTimeout("slowCommand", 10*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Run(ctx)
+
+	cases := []struct {
+		name     string
+		route    string
+		header   string
+		expected time.Duration
+	}{
+		{"consumer timeout", "command", "", time.Minute},
+		{"route timeout", "slowCommand", "", 10 * time.Second},
+		{"header timeout", "slowCommand", "2s", 2 * time.Second},
+		{"invalid header timeout", "slowCommand", "invalid", 10 * time.Second},
+	}
+	for _, c := range cases {
+		builder := message.NewMessageBuilder().
+			WithMessageType(message.Command).
+			WithRoute(c.route).
+			WithPayload("payload").
+			WithContext(context.Background())
+		if c.header != "" {
+			builder.WithCustomHeader(message.HeaderProcessingTimeout, c.header)
+		}
+		inChannel.Send(context.Background(), builder.Build())
+
+		select {
+		case remaining := <-handler.remaining:
+			if remaining > c.expected || remaining < c.expected-time.Second {
+				t.Errorf("%s: expected deadline in %v, got %v", c.name, c.expected, remaining)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s: expected message to be processed", c.name)
+		}
+	}
+}
+
 func TestEventDrivenConsumer_ConfigFunctions(t *testing.T) {
 	configFunctions := []struct {
 		name           string
@@ -300,6 +965,12 @@ func TestEventDrivenConsumer_ConfigFunctions(t *testing.T) {
 				return c.WithStopOnError(true)
 			},
 		},
+		{
+			"WithErrorPolicy",
+			func(c *endpoint.EventDrivenConsumer) *endpoint.EventDrivenConsumer {
+				return c.WithErrorPolicy(endpoint.ErrorPolicyContinue)
+			},
+		},
 	}
 
 	for _, cf := range configFunctions {
@@ -8,21 +8,37 @@
 // The Gateway implementation supports:
 // - Message processing with before/after interceptors
 // - Dead letter channel integration for failed messages
+// - Strict routing with an unroutable channel for unknown routes
+// - Poison message quarantine
+// - Circuit breaker pausing the processing while dependencies fail
+// - Duplicated message skipping (idempotent receiver)
+// - Message filtering with an optional discard channel
+// - Expired message (time to live) discarding or dead lettering
 // - Reply channel support for request-response patterns
+// - Reply timeouts with orphan (late) reply detection
+// - Pending request correlations kept in a pluggable store
 // - Asynchronous message processing with context support
-// - Configurable routing through recipient list routers
+// - Configurable routing through recipient list routers, or a given router
+// - Wire tap copying the executed messages to an audit channel
+// - Per-tenant request channels through a tenant channel strategy
+// - Panics of the processing pipeline returned as errors
 package endpoint
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
-	"github.com/jeffersonbrasilino/gomes/message/channel"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
 	"github.com/jeffersonbrasilino/gomes/message/router"
+	"github.com/jeffersonbrasilino/gomes/otel"
 )
 
 // GatewayReferenceName generates a standardized reference name for gateways.
@@ -44,10 +60,34 @@ type gatewayBuilder struct {
 	beforeInterceptors       []message.MessageHandler
 	afterInterceptors        []message.MessageHandler
 	deadLetterChannel        string
+	deadLetterRoutes         map[string]string
+	unroutableChannel        string
+	quarantineChannel        string
+	quarantineMaxFailures    int
+	poisonMessageKey         handler.PoisonMessageKeyExtractor
 	replyChannelName         string
 	acknowledgeChannel       handler.ChannelMessageAcknowledgment
+	ackMode                  handler.AckMode
 	retryHitTimeMilliseconds []int
+	retryPolicy              handler.RetryPolicy
+	circuitBreaker           *handler.CircuitBreaker
+	deduplicationStore       handler.DeduplicationStore
+	deduplicationTTL         time.Duration
 	sendReplyUsingReplyTo    bool
+	responseChannelName      string
+	replyChannelFactory      handler.ReplyChannelFactory
+	allowedReplyChannels     []string
+	atomicReply              bool
+	replyTimeout             time.Duration
+	lateReplyHandler         LateReplyHandler
+	correlationStore         handler.CorrelationStore
+	correlationTTL           time.Duration
+	wireTapChannel           string
+	wireTapSampling          float64
+	filter                   router.FilterFunc
+	discardChannel           string
+	messageRouter            router.ContainerRouter
+	tenantRouting            message.TenantChannelStrategy
 }
 
 // Gateway represents a message processing gateway that handles message routing,
@@ -56,6 +96,18 @@ type Gateway struct {
 	messageProcessor   message.MessageHandler
 	replyChannelName   string
 	requestChannelName string
+	lateReplyHandler   LateReplyHandler
+	correlationStore   handler.CorrelationStore
+	correlationTTL     time.Duration
+	orphanReplies      atomic.Int64
+	unroutable         unroutableCounter
+	otelMetrics        otel.OtelMetrics
+	tenantRouting      message.TenantChannelStrategy
+}
+
+// unroutableCounter counts the messages sent to the unroutable channel.
+type unroutableCounter interface {
+	Unroutable() int64
 }
 
 // NewGatewayBuilder creates a new gateway builder instance.
@@ -124,6 +176,57 @@ func (b *gatewayBuilder) WithDeadLetterChannel(channelName string) *gatewayBuild
 	return b
 }
 
+// WithDeadLetterRoutes sets the dead letter channels of the failed messages
+// of some routes, used instead of the dead letter channel set by
+// WithDeadLetterChannel.
+//
+// Parameters:
+//   - routes: the dead letter channel names by route
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithDeadLetterRoutes(routes map[string]string) *gatewayBuilder {
+	b.deadLetterRoutes = routes
+	return b
+}
+
+// WithUnroutableChannel enables strict routing, sending messages whose route
+// has no registered handler to the given channel.
+//
+// Parameters:
+//   - channelName: name of the unroutable channel
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithUnroutableChannel(channelName string) *gatewayBuilder {
+	b.unroutableChannel = channelName
+	return b
+}
+
+// WithPoisonMessageQuarantine enables poison message detection, sending
+// messages failing maxFailures consecutive times to the quarantine channel.
+// The quarantine wraps the dead letter channel, so with a dead letter
+// channel it only counts the failures the dead letter channel could not
+// handle, e.g. messages which cannot be dead-lettered.
+//
+// Parameters:
+//   - channelName: name of the quarantine channel
+//   - maxFailures: consecutive failures after which a message is quarantined
+//   - keyExtractor: message identification, nil for the message id
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithPoisonMessageQuarantine(
+	channelName string,
+	maxFailures int,
+	keyExtractor handler.PoisonMessageKeyExtractor,
+) *gatewayBuilder {
+	b.quarantineChannel = channelName
+	b.quarantineMaxFailures = maxFailures
+	b.poisonMessageKey = keyExtractor
+	return b
+}
+
 // WithReplyChannel sets the reply channel for request-response patterns.
 //
 // Parameters:
@@ -150,6 +253,19 @@ func (b *gatewayBuilder) WithAcknowledge(
 	return b
 }
 
+// WithAckMode sets when the messages are acknowledged by the acknowledgment
+// handler.
+//
+// Parameters:
+//   - mode: the acknowledgment mode, handler.AckAuto by default
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithAckMode(mode handler.AckMode) *gatewayBuilder {
+	b.ackMode = mode
+	return b
+}
+
 // WithRetry configures retry intervals for failed message processing attempts.
 //
 // Parameters:
@@ -164,6 +280,160 @@ func (b *gatewayBuilder) WithRetry(
 	return b
 }
 
+// WithRetryPolicy configures the retry policy for failed message processing
+// attempts. It takes precedence over the retry intervals set by WithRetry.
+//
+// Parameters:
+//   - policy: the retry policy deciding retries and delays
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithRetryPolicy(
+	policy handler.RetryPolicy,
+) *gatewayBuilder {
+	b.retryPolicy = policy
+	return b
+}
+
+// WithCircuitBreaker processes the messages through the circuit breaker,
+// after their retries are exhausted. While the circuit is open, messages are
+// sent to its parking channel, when configured, or held until it half-opens.
+//
+// Parameters:
+//   - breaker: the circuit breaker tracking the processing failures
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithCircuitBreaker(
+	breaker *handler.CircuitBreaker,
+) *gatewayBuilder {
+	b.circuitBreaker = breaker
+	return b
+}
+
+// WithDeduplication enables the idempotent receiver, skipping messages whose
+// id was already processed.
+//
+// Parameters:
+//   - store: the store tracking the processed message ids
+//   - ttl: how long a processed message id is kept
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithDeduplication(
+	store handler.DeduplicationStore,
+	ttl time.Duration,
+) *gatewayBuilder {
+	b.deduplicationStore = store
+	b.deduplicationTTL = ttl
+	return b
+}
+
+// WithReplyTimeout sets how long a request waits for its reply. Zero means
+// the wait is bound only by the request context. The replyTimeout header of a
+// message overrides it for that request.
+//
+// Parameters:
+//   - timeout: the reply timeout
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithReplyTimeout(timeout time.Duration) *gatewayBuilder {
+	b.replyTimeout = timeout
+	return b
+}
+
+// WithLateReplyHandler sets the handler called with the replies arriving
+// after their request finished. By default late replies are only logged and
+// counted.
+//
+// Parameters:
+//   - lateReplyHandler: the late reply handler
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithLateReplyHandler(
+	lateReplyHandler LateReplyHandler,
+) *gatewayBuilder {
+	b.lateReplyHandler = lateReplyHandler
+	return b
+}
+
+// WithCorrelationStore keeps the correlation of each request in the given
+// store while it waits for its reply. Correlations of requests finished by a
+// reply timeout or cancellation are kept until the ttl elapses, so their late
+// replies (even after a restart, with a durable store) can still be matched.
+//
+// Parameters:
+//   - store: the pending correlation store
+//   - ttl: how long a correlation is kept, zero means it never expires
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithCorrelationStore(
+	store handler.CorrelationStore,
+	ttl time.Duration,
+) *gatewayBuilder {
+	b.correlationStore = store
+	b.correlationTTL = ttl
+	return b
+}
+
+// WithWireTap asynchronously copies every executed message, before it is
+// processed, to the given publisher channel. The channel is resolved at its
+// first use when it is not registered yet.
+//
+// Parameters:
+//   - channelName: the wire tap channel name
+//   - samplingPercentage: the percentage of messages copied, from 0 to 100
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithWireTap(
+	channelName string,
+	samplingPercentage float64,
+) *gatewayBuilder {
+	b.wireTapChannel = channelName
+	b.wireTapSampling = samplingPercentage
+	return b
+}
+
+// WithRouter routes the messages through the router instead of the recipient
+// list router of the action handlers, so they are sent to the destination
+// channels of the router without waiting for a reply.
+//
+// Parameters:
+//   - messageRouter: the router resolving the destination channels
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithRouter(
+	messageRouter router.ContainerRouter,
+) *gatewayBuilder {
+	b.messageRouter = messageRouter
+	return b
+}
+
+// WithFilter drops the messages failing the filter after the before
+// interceptors, so they never reach the action handlers. Dropped messages are
+// sent to the discard channel, when given.
+//
+// Parameters:
+//   - filter: the predicate returning true for the messages to process
+//   - discardChannelName: name of the discard channel, empty to drop messages
+//     silently
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithFilter(
+	filter router.FilterFunc,
+	discardChannelName string,
+) *gatewayBuilder {
+	b.filter = filter
+	b.discardChannel = discardChannelName
+	return b
+}
+
 // WithSendReplyUsingReplyTo enables reply-to functionality for the gateway builder.
 //
 // Returns:
@@ -173,8 +443,73 @@ func (b *gatewayBuilder) WithSendReplyUsingReplyTo() *gatewayBuilder {
 	return b
 }
 
+// WithResponseChannelName sends the replies of messages without a reply-to
+// header to the given channel, enabling the reply sending even when the
+// reply-to functionality is not enabled.
+//
+// Parameters:
+//   - channelName: the name of the response publisher channel
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithResponseChannelName(channelName string) *gatewayBuilder {
+	b.responseChannelName = channelName
+	return b
+}
+
+// WithReplyChannelFactory creates the publisher channels of reply-to
+// destinations with no registered publisher channel, when they match one of
+// the allowed patterns.
+//
+// Parameters:
+//   - factory: creates the publisher channel of a reply destination
+//   - allowedPatterns: path.Match patterns of the destinations allowed to be
+//     created
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithReplyChannelFactory(
+	factory handler.ReplyChannelFactory,
+	allowedPatterns ...string,
+) *gatewayBuilder {
+	b.replyChannelFactory = factory
+	b.allowedReplyChannels = allowedPatterns
+	return b
+}
+
+// WithAtomicReply publishes the replies before acknowledging the requests,
+// leaving unacknowledged the requests whose reply keeps failing to be
+// published, and derives the
+// reply ids from the request ids so duplicated replies can be discarded.
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithAtomicReply() *gatewayBuilder {
+	b.atomicReply = true
+	return b
+}
+
+// WithTenantRouting sends the messages of each tenant to the request channel
+// of the tenant given by the strategy, e.g. "orders.tenant-a" for the
+// "orders" channel. Messages without tenant keep the request channel, and
+// gateways without request channel are not affected.
+//
+// Parameters:
+//   - strategy: the tenant channel strategy
+//
+// Returns:
+//   - *gatewayBuilder: builder instance for method chaining
+func (b *gatewayBuilder) WithTenantRouting(
+	strategy message.TenantChannelStrategy,
+) *gatewayBuilder {
+	b.tenantRouting = strategy
+	return b
+}
+
 // Build constructs a Gateway from the dependency container with configured
-// interceptors, dead letter channel, and reply channel.
+// interceptors, dead letter channel, and reply channel. Expired messages are
+// dropped before the interceptors, or sent to the dead letter channel when
+// configured.
 //
 // Parameters:
 //   - container: dependency container containing required components
@@ -186,19 +521,67 @@ func (b *gatewayBuilder) Build(
 	container container.Container[any, any],
 ) (*Gateway, error) {
 
-	messageRouter := router.NewRouter()
+	// expired messages go to the dead letter channel of their route, as the
+	// failed ones
+	var deadLetterChannel message.PublisherChannel
+	if b.deadLetterChannel != "" {
+		publisherChannel, err := resolvePublisherChannel(container, b.deadLetterChannel)
+		if err != nil {
+			return nil, fmt.Errorf("[gateway-builder] [dead-letter] %w", err)
+		}
+		deadLetterChannel = publisherChannel
+	}
+	deadLetterRouteChannels := map[string]message.PublisherChannel{}
+	for route, channelName := range b.deadLetterRoutes {
+		publisherChannel, err := resolvePublisherChannel(container, channelName)
+		if err != nil {
+			return nil, fmt.Errorf("[gateway-builder] [dead-letter] %w", err)
+		}
+		deadLetterRouteChannels[route] = publisherChannel
+	}
+
+	messageRouter := router.NewRouter().
+		AddHandler(
+			handler.NewExpirationHandler(deadLetterChannel).
+				WithRouteChannels(deadLetterRouteChannels),
+		)
 	if b.beforeInterceptors != nil {
 		for _, beforeInterceptors := range b.beforeInterceptors {
 			messageRouter.AddHandler(handler.NewContextHandler(beforeInterceptors))
 		}
 	}
 
-	messageRouter.AddHandler(
-		handler.NewContextHandler(router.NewRecipientListRouter(container)),
-	)
-	messageRouter.AddHandler(
-		handler.NewContextHandler(handler.NewReplyConsumerHandler(container)),
-	)
+	if b.filter != nil {
+		var discardChannel message.PublisherChannel
+		if b.discardChannel != "" {
+			publisherChannel, err := resolvePublisherChannel(container, b.discardChannel)
+			if err != nil {
+				return nil, fmt.Errorf("[gateway-builder] [filter] %w", err)
+			}
+			discardChannel = publisherChannel
+		}
+		messageRouter.AddHandler(
+			handler.NewContextHandler(
+				handler.NewMessageFilterHandler(b.filter, discardChannel),
+			),
+		)
+	}
+
+	if b.messageRouter != nil {
+		messageRouter.AddHandler(
+			handler.NewContextHandler(b.messageRouter.Bind(container)),
+		)
+	} else {
+		messageRouter.AddHandler(
+			handler.NewContextHandler(router.NewRecipientListRouter(container)),
+		)
+		messageRouter.AddHandler(
+			handler.NewContextHandler(
+				handler.NewReplyConsumerHandler(container).
+					WithReplyTimeout(b.replyTimeout),
+			),
+		)
+	}
 
 	if b.afterInterceptors != nil {
 		for _, afterInterceptors := range b.afterInterceptors {
@@ -206,43 +589,124 @@ func (b *gatewayBuilder) Build(
 		}
 	}
 
-	if b.retryHitTimeMilliseconds != nil {
+	var unroutable unroutableCounter
+	if b.unroutableChannel != "" {
+		publisherChannel, err := resolvePublisherChannel(container, b.unroutableChannel)
+		if err != nil {
+			return nil, fmt.Errorf("[gateway-builder] [unroutable] %w", err)
+		}
+		unroutableHandler := handler.NewUnroutableHandler(publisherChannel, messageRouter)
+		unroutable = unroutableHandler
+		messageRouter = router.NewRouter().AddHandler(unroutableHandler)
+	}
+
+	if b.retryPolicy != nil {
 		messageRouter = router.NewRouter().
 			AddHandler(
-				handler.NewRetryHandler(b.retryHitTimeMilliseconds, messageRouter),
+				handler.NewRetryHandlerWithPolicy(b.retryPolicy, messageRouter),
 			)
-	}
-
-	if b.sendReplyUsingReplyTo == true {
+	} else if b.retryHitTimeMilliseconds != nil {
 		messageRouter = router.NewRouter().
 			AddHandler(
-				handler.NewContextHandler(
-					handler.NewSendReplyToHandler(messageRouter, container),
-				),
+				handler.NewRetryHandler(b.retryHitTimeMilliseconds, messageRouter),
 			)
 	}
 
-	if b.deadLetterChannel != "" {
-		deadLetterChannel, err := container.Get(b.deadLetterChannel)
-		if err != nil {
-			return nil, fmt.Errorf("[gateway-builder] [dead-letter] %s", err)
+	if b.circuitBreaker != nil {
+		var parkingChannel message.PublisherChannel
+		if channelName := b.circuitBreaker.ParkingChannel(); channelName != "" {
+			publisherChannel, err := resolvePublisherChannel(container, channelName)
+			if err != nil {
+				return nil, fmt.Errorf("[gateway-builder] [circuit-breaker] %w", err)
+			}
+			parkingChannel = publisherChannel
+		}
+		messageRouter = router.NewRouter().AddHandler(
+			handler.NewCircuitBreakerHandler(
+				b.circuitBreaker,
+				messageRouter,
+				parkingChannel,
+			),
+		)
+	}
+
+	if b.sendReplyUsingReplyTo == true || b.responseChannelName != "" {
+		replyHandler := handler.NewSendReplyToHandler(messageRouter, container).
+			WithResponseChannelName(b.responseChannelName).
+			WithReplyChannelFactory(
+				b.replyChannelFactory,
+				b.allowedReplyChannels...,
+			)
+		if b.atomicReply {
+			replyHandler.WithAtomicReply()
 		}
+		messageRouter = router.NewRouter().
+			AddHandler(handler.NewContextHandler(replyHandler))
+	}
+
+	if deadLetterChannel != nil || len(deadLetterRouteChannels) > 0 {
 		messageRouter = router.NewRouter().
 			AddHandler(
 				handler.NewDeadLetter(
-					deadLetterChannel.(message.PublisherChannel),
+					deadLetterChannel,
 					messageRouter,
-				),
+				).WithSourceChannel(b.referenceName).
+					WithRouteChannels(deadLetterRouteChannels),
 			)
 	}
 
+	if b.quarantineChannel != "" {
+		publisherChannel, err := resolvePublisherChannel(container, b.quarantineChannel)
+		if err != nil {
+			return nil, fmt.Errorf("[gateway-builder] [quarantine] %w", err)
+		}
+		poisonMessageHandler, err := handler.NewPoisonMessageHandler(
+			publisherChannel,
+			b.quarantineMaxFailures,
+			messageRouter,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("[gateway-builder] [quarantine] %w", err)
+		}
+		messageRouter = router.NewRouter().AddHandler(
+			poisonMessageHandler.WithKeyExtractor(b.poisonMessageKey),
+		)
+	}
+
+	if b.deduplicationStore != nil {
+		messageRouter = router.NewRouter().AddHandler(
+			handler.NewDeduplicationHandler(
+				b.deduplicationStore,
+				b.deduplicationTTL,
+				messageRouter,
+			),
+		)
+	}
+
+	if b.wireTapChannel != "" {
+		wireTapChannel, err := resolvePublisherChannel(container, b.wireTapChannel)
+		if err != nil {
+			return nil, fmt.Errorf("[gateway-builder] [wire-tap] %w", err)
+		}
+		messageRouter = router.NewRouter().
+			AddHandler(handler.NewWireTap(wireTapChannel, b.wireTapSampling)).
+			AddHandler(messageRouter)
+	}
+
 	if b.acknowledgeChannel != nil {
 		messageRouter = router.NewRouter().AddHandler(
-			handler.NewAcknowledgeHandler(b.acknowledgeChannel, messageRouter),
+			handler.NewAcknowledgeHandler(b.acknowledgeChannel, messageRouter).
+				WithAckMode(b.ackMode),
 		)
 	}
 
-	return NewGateway(messageRouter, b.replyChannelName, b.requestChannelName), nil
+	gateway := NewGateway(messageRouter, b.replyChannelName, b.requestChannelName)
+	gateway.lateReplyHandler = b.lateReplyHandler
+	gateway.correlationStore = b.correlationStore
+	gateway.correlationTTL = b.correlationTTL
+	gateway.tenantRouting = b.tenantRouting
+	gateway.unroutable = unroutable
+	return gateway, nil
 }
 
 // NewGateway creates a new gateway instance.
@@ -263,6 +727,7 @@ func NewGateway(
 		messageProcessor:   messageProcessor,
 		replyChannelName:   replyChannelName,
 		requestChannelName: requestChannelName,
+		otelMetrics:        otel.InitMetrics("gateway"),
 	}
 }
 
@@ -283,21 +748,41 @@ func (g *Gateway) Execute(
 	opCtx, cancel := context.WithCancel(parentContext)
 	defer cancel()
 
-	responseChannel := make(chan any)
+	startedAt := time.Now()
+	var err error
+	defer func() {
+		g.otelMetrics.RecordProcessDuration(
+			parentContext,
+			otel.MessageSystemTypeInternal,
+			msg,
+			time.Since(startedAt),
+			err,
+		)
+	}()
+
+	// buffered, so the request goroutine never blocks on a caller which
+	// already gave up waiting
+	responseChannel := make(chan any, 1)
 	go g.executeAsync(opCtx, responseChannel, msg)
 
 	select {
 	case result := <-responseChannel:
 		switch v := result.(type) {
 		case *message.Message:
+			if v == nil {
+				return nil, nil
+			}
 			return v.GetPayload(), nil
 		case error:
+			err = v
 			return nil, v
 		default:
-			return nil, fmt.Errorf("invalid response type")
+			err = fmt.Errorf("invalid response type")
+			return nil, err
 		}
 	case <-opCtx.Done():
-		return nil, opCtx.Err()
+		err = opCtx.Err()
+		return nil, err
 	}
 }
 
@@ -314,46 +799,224 @@ func (g *Gateway) executeAsync(
 	msg *message.Message,
 ) {
 	defer close(responseChannel)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicErr := message.NewPanicError(recovered, debug.Stack())
+			slog.Error("[gateway] message processing panicked",
+				"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+				"reason", panicErr.Error(),
+				"stack", panicErr.Stack,
+			)
+			responseChannel <- panicErr
+		}
+	}()
 
 	select {
 	case <-ctx.Done():
 		responseChannel <- ctx.Err()
+		return
 	default:
 	}
 
 	messageToProcess := message.NewMessageBuilderFromMessage(msg)
-	messageToProcess.WithChannelName(g.requestChannelName)
+	messageToProcess.WithChannelName(g.requestChannel(msg))
 	messageToProcess.WithContext(ctx)
 	if g.replyChannelName != "" {
 		messageToProcess.WithReplyTo(g.replyChannelName)
 	}
 
-	internalReplyChannel := g.makeInternalChannel()
+	internalReplyChannel := g.makeInternalChannel(msg.GetHeader().Get(message.HeaderMessageId))
 	defer internalReplyChannel.Close()
 
 	messageToProcess.WithInternalReplyChannel(internalReplyChannel)
 
-	resultMessage, err := g.messageProcessor.Handle(ctx, messageToProcess.Build())
+	request := messageToProcess.Build()
+	g.saveCorrelation(ctx, request)
+
+	resultMessage, err := g.messageProcessor.Handle(ctx, request)
+	g.finishCorrelation(request, err)
 	if err != nil {
 		responseChannel <- err
 		return
 	}
 
-	select {
-	case <-ctx.Done():
-		responseChannel <- ctx.Err()
-		return
-	case responseChannel <- resultMessage:
+	responseChannel <- resultMessage
+}
+
+// requestChannel returns the channel the message is sent to, the channel of
+// its tenant when tenant routing is enabled.
+func (g *Gateway) requestChannel(msg *message.Message) string {
+	tenantId := msg.GetHeader().Get(message.HeaderTenantId)
+	if g.tenantRouting == nil || g.requestChannelName == "" || tenantId == "" {
+		return g.requestChannelName
+	}
+	if channelName := g.tenantRouting(g.requestChannelName, tenantId); channelName != "" {
+		return channelName
+	}
+	return g.requestChannelName
+}
+
+// OrphanReplies returns the number of replies which arrived after their
+// request finished.
+//
+// Returns:
+//   - int64: number of orphan replies
+func (g *Gateway) OrphanReplies() int64 {
+	return g.orphanReplies.Load()
+}
+
+// Unroutable returns the number of messages sent to the unroutable channel,
+// zero when no unroutable channel is configured.
+//
+// Returns:
+//   - int64: number of unroutable messages
+func (g *Gateway) Unroutable() int64 {
+	if g.unroutable == nil {
+		return 0
 	}
+	return g.unroutable.Unroutable()
 }
 
-// makeInternalChannel creates an internal point-to-point channel for handling
-// reply messages during processing.
+// makeInternalChannel creates an internal reply channel for handling reply
+// messages during processing.
+//
+// Parameters:
+//   - requestId: the message id of the request awaiting the replies
 //
 // Returns:
-//   - *channel.PointToPointChannel: internal channel for reply handling
-func (g *Gateway) makeInternalChannel() *channel.PointToPointChannel {
-	internalChannelName := uuid.New().String()
-	chn := channel.NewPointToPointChannel(internalChannelName)
-	return chn
+//   - *internalReplyChannel: internal channel for reply handling
+func (g *Gateway) makeInternalChannel(requestId string) *internalReplyChannel {
+	return newInternalReplyChannel(uuid.New().String(), func(reply *message.Message) {
+		g.handleLateReply(requestId, reply)
+	})
+}
+
+// MatchReply matches a reply to the pending correlation of its request, given
+// by the causation id of the reply, removing it from the correlation store.
+// It allows replies received outside the request lifetime, e.g. by the reply
+// consumer after a restart, to be related to their request.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - reply: the reply message
+//
+// Returns:
+//   - handler.PendingCorrelation: the correlation of the request
+//   - bool: false if there is no correlation store or pending correlation
+//   - error: error if the store operation fails
+func (g *Gateway) MatchReply(
+	ctx context.Context,
+	reply *message.Message,
+) (handler.PendingCorrelation, bool, error) {
+	return g.matchCorrelation(ctx, reply.GetHeader().Get(message.HeaderCausationId))
+}
+
+// matchCorrelation finds and removes the pending correlation of a request.
+func (g *Gateway) matchCorrelation(
+	ctx context.Context,
+	requestId string,
+) (handler.PendingCorrelation, bool, error) {
+	if g.correlationStore == nil || requestId == "" {
+		return handler.PendingCorrelation{}, false, nil
+	}
+
+	correlation, found, err := g.correlationStore.Find(ctx, requestId)
+	if err != nil || !found {
+		return correlation, false, err
+	}
+
+	return correlation, true, g.correlationStore.Delete(ctx, requestId)
+}
+
+// saveCorrelation records the correlation of a request in the correlation
+// store, if any.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the request message
+func (g *Gateway) saveCorrelation(ctx context.Context, msg *message.Message) {
+	if g.correlationStore == nil {
+		return
+	}
+
+	now := time.Now()
+	correlation := handler.PendingCorrelation{
+		CorrelationId:  msg.GetHeader().Get(message.HeaderCorrelationId),
+		MessageId:      msg.GetHeader().Get(message.HeaderMessageId),
+		Route:          msg.GetHeader().Get(message.HeaderRoute),
+		RequestChannel: g.requestChannelName,
+		ReplyChannel:   g.replyChannelName,
+		CreatedAt:      now,
+	}
+	if correlation.MessageId == "" {
+		return
+	}
+	if g.correlationTTL > 0 {
+		correlation.ExpiresAt = now.Add(g.correlationTTL)
+	}
+
+	if err := g.correlationStore.Save(ctx, correlation); err != nil {
+		slog.Error("[gateway] failed to save request correlation",
+			"messageId", correlation.MessageId,
+			"reason", err.Error(),
+		)
+	}
+}
+
+// finishCorrelation removes the correlation of a finished request. It is kept
+// when the request finished without its reply, so a late reply can be matched.
+//
+// Parameters:
+//   - msg: the request message
+//   - err: the request error, if any
+func (g *Gateway) finishCorrelation(msg *message.Message, err error) {
+	if g.correlationStore == nil {
+		return
+	}
+
+	if errors.Is(err, handler.ErrReplyTimeout) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	messageId := msg.GetHeader().Get(message.HeaderMessageId)
+	if errDelete := g.correlationStore.Delete(context.Background(), messageId); errDelete != nil {
+		slog.Error("[gateway] failed to delete request correlation",
+			"messageId", messageId,
+			"reason", errDelete.Error(),
+		)
+	}
+}
+
+// handleLateReply records a reply which arrived after its request finished
+// and delegates it to the late reply handler, if any.
+//
+// Parameters:
+//   - requestId: the message id of the finished request
+//   - msg: the late reply message
+func (g *Gateway) handleLateReply(requestId string, msg *message.Message) {
+	total := g.orphanReplies.Add(1)
+	attributes := []any{
+		"requestChannel", g.requestChannelName,
+		"correlationId", msg.GetHeader().Get(message.HeaderCorrelationId),
+		"orphanRepliesTotal", total,
+	}
+
+	correlation, found, err := g.matchCorrelation(context.Background(), requestId)
+	if err != nil {
+		attributes = append(attributes, "correlationStoreError", err.Error())
+	}
+	if found {
+		attributes = append(attributes,
+			"requestMessageId", correlation.MessageId,
+			"requestRoute", correlation.Route,
+			"requestAge", time.Since(correlation.CreatedAt).String(),
+		)
+	}
+	slog.Warn("[gateway] orphan reply received after request finished", attributes...)
+
+	if g.lateReplyHandler != nil {
+		g.lateReplyHandler(msg)
+	}
 }
@@ -2,13 +2,18 @@ package endpoint_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/channel"
 	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+	"github.com/jeffersonbrasilino/gomes/message/router"
 )
 
 type dummyGatewayHandler struct{}
@@ -106,16 +111,231 @@ func TestMessageBuilder_WithDeadLetterChannel(t *testing.T) {
 		})
 	})
 
-	t.Run("should return error if dead letter channel does not exist", func(t *testing.T) {
+	t.Run("should resolve a dead letter channel registered after build", func(t *testing.T) {
 		container := container.NewGenericContainer[any, any]()
+		gateway, err := endpoint.NewGatewayBuilder("ref", "").
+			WithDeadLetterChannel("lateDeadLetterChannel").
+			Build(container)
+		if err != nil {
+			t.Fatalf("Build should return nil error, got: %v", err)
+		}
+
+		dlq := &recordingPublisher{}
+		container.Set("lateDeadLetterChannel", dlq)
+		msg := message.NewMessageBuilder().WithRoute("unknown.route").Build()
+		gateway.Execute(context.Background(), msg)
+		if len(dlq.sent) != 1 {
+			t.Errorf("Expected 1 dead letter message, got %d", len(dlq.sent))
+		}
+	})
+
+	t.Run("should return error if dead letter channel is not a publisher channel", func(t *testing.T) {
+		container := container.NewGenericContainer[any, any]()
+		container.Set("invalidChannel", "not a channel")
 		_, err := endpoint.NewGatewayBuilder("ref", "channel").
-			WithDeadLetterChannel("nonExistentChannel").
+			WithDeadLetterChannel("invalidChannel").
 			Build(container)
 		if err == nil {
-			t.Error("Build should return an error if dead letter channel does not exist")
+			t.Error("Build should return an error if dead letter channel is not a publisher channel")
+		}
+	})
+}
+func TestMessageBuilder_WithUnroutableChannel(t *testing.T) {
+	t.Parallel()
+	t.Run("should send unknown routes to unroutable channel", func(t *testing.T) {
+		container := container.NewGenericContainer[any, any]()
+		unroutable := &recordingPublisher{}
+		dlq := &recordingPublisher{}
+		container.Set("unroutable", unroutable)
+		container.Set("deadLetterChannel", dlq)
+		gateway, err := endpoint.NewGatewayBuilder("ref", "").
+			WithUnroutableChannel("unroutable").
+			WithDeadLetterChannel("deadLetterChannel").
+			WithRetry([]int{1}).
+			Build(container)
+		if err != nil {
+			t.Fatalf("Build should return nil error, got: %v", err)
+		}
+
+		msg := message.NewMessageBuilder().WithRoute("unknown.route").Build()
+		_, err = gateway.Execute(context.Background(), msg)
+		if !errors.Is(err, router.ErrUnroutable) {
+			t.Errorf("Expected unroutable error, got: %v", err)
+		}
+		if len(unroutable.sent) != 1 {
+			t.Errorf("Expected 1 unroutable message, got %d", len(unroutable.sent))
+		}
+		if gateway.Unroutable() != 1 {
+			t.Errorf("Expected 1 unroutable message counted, got %d", gateway.Unroutable())
+		}
+		if len(dlq.sent) != 0 {
+			t.Errorf("Expected no dead letter message, got %d", len(dlq.sent))
+		}
+	})
+
+	t.Run("should return ErrChannelNotFound if unroutable channel is never registered", func(t *testing.T) {
+		container := container.NewGenericContainer[any, any]()
+		gateway, err := endpoint.NewGatewayBuilder("ref", "").
+			WithUnroutableChannel("nonExistentChannel").
+			Build(container)
+		if err != nil {
+			t.Fatalf("Build should return nil error, got: %v", err)
+		}
+
+		msg := message.NewMessageBuilder().WithRoute("unknown.route").Build()
+		_, err = gateway.Execute(context.Background(), msg)
+		if !errors.Is(err, message.ErrChannelNotFound) {
+			t.Errorf("Expected ErrChannelNotFound, got: %v", err)
 		}
 	})
 }
+
+func TestMessageBuilder_WithPoisonMessageQuarantine(t *testing.T) {
+	t.Parallel()
+	t.Run("should quarantine messages the dead letter channel cannot take", func(t *testing.T) {
+		container := container.NewGenericContainer[any, any]()
+		quarantine := &recordingPublisher{}
+		container.Set("quarantine", quarantine)
+		container.Set("deadLetterChannel", &failingPublisher{})
+		gateway, err := endpoint.NewGatewayBuilder("ref", "").
+			WithDeadLetterChannel("deadLetterChannel").
+			WithPoisonMessageQuarantine("quarantine", 2, nil).
+			Build(container)
+		if err != nil {
+			t.Fatalf("Build should return nil error, got: %v", err)
+		}
+
+		msg := message.NewMessageBuilder().WithMessageId("poison").WithRoute("unknown.route").Build()
+		if _, err := gateway.Execute(context.Background(), msg); err == nil {
+			t.Fatal("Expected the dead letter failure on the first delivery")
+		}
+		if _, err := gateway.Execute(context.Background(), msg); err != nil {
+			t.Fatalf("Expected the message quarantined, got: %v", err)
+		}
+		if len(quarantine.sent) != 1 {
+			t.Errorf("Expected 1 quarantined message, got %d", len(quarantine.sent))
+		}
+	})
+
+	t.Run("should reject non positive max failures", func(t *testing.T) {
+		container := container.NewGenericContainer[any, any]()
+		container.Set("quarantine", &recordingPublisher{})
+		_, err := endpoint.NewGatewayBuilder("ref", "").
+			WithPoisonMessageQuarantine("quarantine", 0, nil).
+			Build(container)
+		if err == nil {
+			t.Error("Build should return an error for zero max failures")
+		}
+	})
+}
+
+func TestGateway_ExpiredMessage(t *testing.T) {
+	t.Parallel()
+	container := container.NewGenericContainer[any, any]()
+	dlq := &recordingPublisher{}
+	container.Set("deadLetterChannel", dlq)
+	gateway, err := endpoint.NewGatewayBuilder("ref", "").
+		WithDeadLetterChannel("deadLetterChannel").
+		Build(container)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().
+		WithRoute("unknown.route").
+		WithTTL(-time.Second).
+		Build()
+	result, err := gateway.Execute(context.Background(), msg)
+	if err != nil || result != nil {
+		t.Errorf("Expected expired message to skip processing, got %v, %v", result, err)
+	}
+	if len(dlq.sent) != 1 || dlq.sent[0].GetHeader().Get(message.HeaderExpired) != "true" {
+		t.Errorf("Expected expired message in the dead letter channel, got %d", len(dlq.sent))
+	}
+}
+
+func TestGateway_ExpiredMessageOfRoute(t *testing.T) {
+	t.Parallel()
+	container := container.NewGenericContainer[any, any]()
+	routeDlq := &recordingPublisher{}
+	container.Set("orders.dlq", routeDlq)
+	gateway, err := endpoint.NewGatewayBuilder("ref", "").
+		WithDeadLetterRoutes(map[string]string{"order.created": "orders.dlq"}).
+		Build(container)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().
+		WithRoute("order.created").
+		WithTTL(-time.Second).
+		Build()
+	if _, err := gateway.Execute(context.Background(), msg); err != nil {
+		t.Errorf("Expected expired message to skip processing, got %v", err)
+	}
+	if len(routeDlq.sent) != 1 || routeDlq.sent[0].GetHeader().Get(message.HeaderExpired) != "true" {
+		t.Errorf("Expected expired message in the dead letter channel of its route, got %d", len(routeDlq.sent))
+	}
+}
+
+func TestMessageBuilder_WithFilter(t *testing.T) {
+	t.Parallel()
+	container := container.NewGenericContainer[any, any]()
+	discard := &recordingPublisher{}
+	container.Set("discard", discard)
+	gateway, err := endpoint.NewGatewayBuilder("ref", "").
+		WithFilter(func(msg message.Message) bool {
+			return msg.GetHeader().Get(message.HeaderOrigin) != "legacy"
+		}, "discard").
+		Build(container)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().
+		WithRoute("unknown.route").
+		WithOrigin("legacy").
+		Build()
+	result, err := gateway.Execute(context.Background(), msg)
+	if err != nil || result != nil {
+		t.Errorf("Expected filtered message to skip processing, got %v, %v", result, err)
+	}
+	if len(discard.sent) != 1 {
+		t.Errorf("Expected 1 discarded message, got %d", len(discard.sent))
+	}
+}
+
+func TestMessageBuilder_WithRouter(t *testing.T) {
+	t.Parallel()
+	container := container.NewGenericContainer[any, any]()
+	billing := &recordingPublisher{}
+	container.Set("billing", billing)
+	dynamicRouter := router.NewDynamicRouter()
+	dynamicRouter.AddRoute(func(msg message.Message) bool {
+		return msg.GetHeader().Get(message.HeaderRoute) == "order.paid"
+	}, "billing")
+	gateway, err := endpoint.NewGatewayBuilder("ref", "").
+		WithRouter(dynamicRouter).
+		WithReplyTimeout(time.Second).
+		Build(container)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().WithRoute("order.paid").WithPayload("paid").Build()
+	if _, err := gateway.Execute(context.Background(), msg); err != nil {
+		t.Fatalf("Expected the message routed without waiting for a reply, got %v", err)
+	}
+	if len(billing.sent) != 1 {
+		t.Errorf("Expected 1 message routed to billing, got %d", len(billing.sent))
+	}
+
+	unknown := message.NewMessageBuilder().WithRoute("order.created").Build()
+	if _, err := gateway.Execute(context.Background(), unknown); !errors.Is(err, router.ErrUnroutable) {
+		t.Errorf("Expected unroutable error, got: %v", err)
+	}
+}
+
 func TestMessageBuilder_WithReplyChannel(t *testing.T) {
 	t.Parallel()
 	t.Run("should add reply channel correctly", func(t *testing.T) {
@@ -192,3 +412,247 @@ func TestGateway_Execute(t *testing.T) {
 		}
 	})
 }
+
+// panickingGatewayHandler panics while processing every message.
+type panickingGatewayHandler struct{}
+
+func (p *panickingGatewayHandler) Handle(_ context.Context, _ *message.Message) (*message.Message, error) {
+	panic("interceptor exploded")
+}
+
+func TestGateway_ExecutePanic(t *testing.T) {
+	t.Parallel()
+	gw := endpoint.NewGateway(&panickingGatewayHandler{}, "ref", "channel")
+	msg := message.NewMessageBuilder().
+		WithChannelName("channel").
+		WithMessageType(message.Command).
+		WithPayload("payload").
+		Build()
+
+	_, err := gw.Execute(context.Background(), msg)
+	if !errors.Is(err, message.ErrPanic) {
+		t.Errorf("Execute should return the panic as an error, got: %v", err)
+	}
+}
+
+type lateReplyChannel struct {
+	delay time.Duration
+}
+
+func (c *lateReplyChannel) Name() string { return "late.reply" }
+
+func (c *lateReplyChannel) Send(ctx context.Context, msg *message.Message) error {
+	go func() {
+		time.Sleep(c.delay)
+		reply := message.NewMessageBuilder().
+			WithCorrelationId(msg.GetHeader().Get(message.HeaderCorrelationId)).
+			WithPayload("late").
+			Build()
+		msg.GetInternalReplyChannel().Send(context.Background(), reply)
+	}()
+	return nil
+}
+
+func TestGateway_ReplyTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should time out and report late replies as orphan", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("late.reply", &lateReplyChannel{delay: 30 * time.Millisecond})
+		lateReplies := make(chan *message.Message, 1)
+		gateway, err := endpoint.NewGatewayBuilder("ref", "late.reply").
+			WithReplyTimeout(5 * time.Millisecond).
+			WithLateReplyHandler(func(msg *message.Message) { lateReplies <- msg }).
+			Build(cont)
+		if err != nil {
+			t.Fatalf("Build should return nil error, got: %v", err)
+		}
+
+		msg := message.NewMessageBuilder().WithCorrelationId("corr-late").Build()
+		_, err = gateway.Execute(context.Background(), msg)
+		if !errors.Is(err, handler.ErrReplyTimeout) {
+			t.Fatalf("Expected reply timeout error, got: %v", err)
+		}
+
+		select {
+		case late := <-lateReplies:
+			if late.GetHeader().Get(message.HeaderCorrelationId) != "corr-late" {
+				t.Errorf("Expected late reply for corr-late, got %s",
+					late.GetHeader().Get(message.HeaderCorrelationId))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected late reply to be reported")
+		}
+		if gateway.OrphanReplies() != 1 {
+			t.Errorf("Expected 1 orphan reply, got %d", gateway.OrphanReplies())
+		}
+	})
+
+	t.Run("should use the request reply timeout header", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("late.reply", &lateReplyChannel{delay: 5 * time.Millisecond})
+		gateway, _ := endpoint.NewGatewayBuilder("ref", "late.reply").
+			WithReplyTimeout(time.Millisecond).
+			Build(cont)
+
+		msg := message.NewMessageBuilder().
+			WithCustomHeader(message.HeaderReplyTimeout, "1s").
+			Build()
+		result, err := gateway.Execute(context.Background(), msg)
+		if err != nil || result != "late" {
+			t.Errorf("Expected reply 'late', got %v (%v)", result, err)
+		}
+	})
+}
+
+func TestGateway_CorrelationStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should keep timed out correlations until the late reply", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("late.reply", &lateReplyChannel{delay: 30 * time.Millisecond})
+		store := handler.NewInMemoryCorrelationStore()
+		lateReplies := make(chan *message.Message, 1)
+		gateway, _ := endpoint.NewGatewayBuilder("ref", "late.reply").
+			WithReplyTimeout(5*time.Millisecond).
+			WithCorrelationStore(store, time.Minute).
+			WithLateReplyHandler(func(msg *message.Message) { lateReplies <- msg }).
+			Build(cont)
+
+		msg := message.NewMessageBuilder().
+			WithCorrelationId("corr-store").
+			WithRoute("order.create").
+			Build()
+		if _, err := gateway.Execute(context.Background(), msg); !errors.Is(err, handler.ErrReplyTimeout) {
+			t.Fatalf("Expected reply timeout error, got: %v", err)
+		}
+
+		requestId := msg.GetHeader().Get(message.HeaderMessageId)
+		correlation, found, _ := store.Find(context.Background(), requestId)
+		if !found || correlation.Route != "order.create" || correlation.RequestChannel != "late.reply" {
+			t.Fatalf("Expected pending correlation, got %+v (%v)", correlation, found)
+		}
+
+		select {
+		case <-lateReplies:
+		case <-time.After(time.Second):
+			t.Fatal("Expected late reply to be reported")
+		}
+		if _, found, _ := store.Find(context.Background(), requestId); found {
+			t.Error("Expected correlation to be removed by the late reply")
+		}
+	})
+
+	t.Run("should remove the correlation of replied requests", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("late.reply", &lateReplyChannel{})
+		store := handler.NewInMemoryCorrelationStore()
+		gateway, _ := endpoint.NewGatewayBuilder("ref", "late.reply").
+			WithCorrelationStore(store, 0).
+			Build(cont)
+
+		msg := message.NewMessageBuilder().WithCorrelationId("corr-replied").Build()
+		if _, err := gateway.Execute(context.Background(), msg); err != nil {
+			t.Fatalf("Expected reply, got: %v", err)
+		}
+		if _, found, _ := store.Find(context.Background(), msg.GetHeader().Get(message.HeaderMessageId)); found {
+			t.Error("Expected correlation to be removed")
+		}
+	})
+
+	t.Run("should match the reply of a request sent before a restart", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("silent", &silentChannel{})
+		path := filepath.Join(t.TempDir(), "correlations.json")
+		store, _ := handler.NewFileCorrelationStore(path)
+		gateway, _ := endpoint.NewGatewayBuilder("ref", "silent").
+			WithReplyTimeout(5*time.Millisecond).
+			WithCorrelationStore(store, time.Minute).
+			Build(cont)
+
+		msg := message.NewMessageBuilder().WithCorrelationId("workflow").Build()
+		if _, err := gateway.Execute(context.Background(), msg); !errors.Is(err, handler.ErrReplyTimeout) {
+			t.Fatalf("Expected reply timeout error, got: %v", err)
+		}
+
+		restartedStore, err := handler.NewFileCorrelationStore(path)
+		if err != nil {
+			t.Fatalf("Expected store reloaded, got: %v", err)
+		}
+		restarted, _ := endpoint.NewGatewayBuilder("ref", "silent").
+			WithCorrelationStore(restartedStore, time.Minute).
+			Build(cont)
+		reply := message.NewMessageBuilder().
+			WithCorrelationId("workflow").
+			WithCustomHeader(message.HeaderCausationId, msg.GetHeader().Get(message.HeaderMessageId)).
+			Build()
+		correlation, found, err := restarted.MatchReply(context.Background(), reply)
+		if err != nil || !found || correlation.RequestChannel != "silent" {
+			t.Fatalf("Expected the correlation matched after the restart, got %+v (%v, %v)", correlation, found, err)
+		}
+		if _, found, _ := restarted.MatchReply(context.Background(), reply); found {
+			t.Error("Expected the matched correlation to be removed")
+		}
+	})
+}
+
+// silentChannel never replies to the sent messages.
+type silentChannel struct{}
+
+func (c *silentChannel) Name() string { return "silent" }
+
+func (c *silentChannel) Send(context.Context, *message.Message) error { return nil }
+
+// tenantChannel replies to every sent message, recording the channel it
+// was sent to.
+type tenantChannel struct {
+	name     string
+	channels chan string
+}
+
+func (c *tenantChannel) Name() string { return c.name }
+
+func (c *tenantChannel) Send(ctx context.Context, msg *message.Message) error {
+	c.channels <- msg.GetHeader().Get(message.HeaderChannelName)
+	reply := message.NewMessageBuilder().
+		WithCorrelationId(msg.GetHeader().Get(message.HeaderCorrelationId)).
+		WithPayload(c.name).
+		Build()
+	return msg.GetInternalReplyChannel().Send(ctx, reply)
+}
+
+func TestGateway_TenantRouting(t *testing.T) {
+	t.Parallel()
+	channels := make(chan string, 2)
+	container := container.NewGenericContainer[any, any]()
+	container.Set("orders", &tenantChannel{name: "orders", channels: channels})
+	container.Set("orders.tenant-a", &tenantChannel{name: "orders.tenant-a", channels: channels})
+	gateway, err := endpoint.NewGatewayBuilder("ref", "orders").
+		WithTenantRouting(message.TenantChannelSuffix(".")).
+		Build(container)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	for tenantId, expected := range map[string]string{
+		"tenant-a": "orders.tenant-a",
+		"":         "orders",
+	} {
+		msg := message.NewMessageBuilder().
+			WithRoute("create.order").
+			WithCustomHeader(message.HeaderTenantId, tenantId).
+			Build()
+		result, err := gateway.Execute(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("Execute should return nil error, got: %v", err)
+		}
+		if channel := <-channels; channel != expected || result != expected {
+			t.Errorf("Expected message sent to %s, got %s (%v)", expected, channel, result)
+		}
+	}
+}
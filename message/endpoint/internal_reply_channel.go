@@ -0,0 +1,131 @@
+// Package endpoint implements the internal reply channel used by gateways to
+// wait for the reply of a request.
+//
+// The internalReplyChannel implementation supports:
+// - Non-blocking reply delivery, a single reply is buffered
+// - Safe delivery of replies arriving after the request finished
+// - Orphan reply detection for replies nobody will ever receive
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// LateReplyHandler is called with the replies arriving after their request
+// finished, e.g. after a reply timeout.
+type LateReplyHandler func(msg *message.Message)
+
+// errReplyChannelClosed is returned when a reply is sent to a closed channel.
+var errReplyChannelClosed = errors.New(
+	"[internal-reply-channel] request already finished, reply discarded",
+)
+
+// internalReplyChannel is the per-request channel receiving the reply of a
+// gateway request. Unlike a point-to-point channel, sending to it never blocks
+// nor panics once the request is finished: the reply is reported as orphan.
+type internalReplyChannel struct {
+	name     string
+	replies  chan *message.Message
+	mu       sync.Mutex
+	closed   bool
+	onOrphan LateReplyHandler
+}
+
+// newInternalReplyChannel creates a new internal reply channel.
+//
+// Parameters:
+//   - name: the channel name
+//   - onOrphan: called with the replies nobody will receive
+//
+// Returns:
+//   - *internalReplyChannel: the reply channel
+func newInternalReplyChannel(
+	name string,
+	onOrphan LateReplyHandler,
+) *internalReplyChannel {
+	return &internalReplyChannel{
+		name:     name,
+		replies:  make(chan *message.Message, 1),
+		onOrphan: onOrphan,
+	}
+}
+
+// Name returns the channel name.
+//
+// Returns:
+//   - string: the channel name
+func (c *internalReplyChannel) Name() string {
+	return c.name
+}
+
+// Send delivers a reply without blocking. Replies sent after the channel was
+// closed, or beyond the first one, are reported as orphan.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the reply message
+//
+// Returns:
+//   - error: error if the reply could not be delivered
+func (c *internalReplyChannel) Send(ctx context.Context, msg *message.Message) error {
+	c.mu.Lock()
+	if !c.closed {
+		select {
+		case c.replies <- msg:
+			c.mu.Unlock()
+			return nil
+		default:
+		}
+	}
+	c.mu.Unlock()
+
+	c.onOrphan(msg)
+	return errReplyChannelClosed
+}
+
+// Receive waits for the reply.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - *message.Message: the reply message
+//   - error: error if the context is done before a reply arrives
+func (c *internalReplyChannel) Receive(ctx context.Context) (*message.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case reply := <-c.replies:
+		return reply, nil
+	}
+}
+
+// Close closes the channel. A reply delivered but never received is reported
+// as orphan.
+//
+// Returns:
+//   - error: always nil
+func (c *internalReplyChannel) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+
+	var pending *message.Message
+	select {
+	case pending = <-c.replies:
+	default:
+	}
+	c.mu.Unlock()
+
+	if pending != nil {
+		c.onOrphan(pending)
+	}
+	return nil
+}
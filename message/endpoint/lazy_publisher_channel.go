@@ -0,0 +1,93 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// lazyPublisherChannel resolves a publisher channel from the container at its
+// first use, so the dead letter, unroutable, quarantine and parking channels
+// of a gateway may be registered after the gateway is built.
+type lazyPublisherChannel struct {
+	container container.Container[any, any]
+	name      string
+	mu        sync.Mutex
+	channel   message.PublisherChannel
+}
+
+// resolvePublisherChannel returns the publisher channel registered under name,
+// or a channel resolving it at its first use when it is not registered yet.
+//
+// Parameters:
+//   - container: the container the channel is registered in
+//   - name: the reference name of the channel
+//
+// Returns:
+//   - message.PublisherChannel: the resolved or lazily resolved channel
+//   - error: error if the registered channel is not a publisher channel
+func resolvePublisherChannel(
+	container container.Container[any, any],
+	name string,
+) (message.PublisherChannel, error) {
+	lazyChannel := &lazyPublisherChannel{container: container, name: name}
+	if !container.Has(name) {
+		return lazyChannel, nil
+	}
+	return lazyChannel.resolve()
+}
+
+// Name returns the reference name of the channel.
+//
+// Returns:
+//   - string: the channel name
+func (c *lazyPublisherChannel) Name() string {
+	return c.name
+}
+
+// Send resolves the channel, if not resolved yet, and sends the message to it.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to send
+//
+// Returns:
+//   - error: error if the channel is not registered or sending fails
+func (c *lazyPublisherChannel) Send(ctx context.Context, msg *message.Message) error {
+	channel, err := c.resolve()
+	if err != nil {
+		return err
+	}
+	return channel.Send(ctx, msg)
+}
+
+// resolve returns the publisher channel registered in the container, caching
+// it after the first successful resolution.
+func (c *lazyPublisherChannel) resolve() (message.PublisherChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.channel != nil {
+		return c.channel, nil
+	}
+
+	anyChannel, err := c.container.Get(c.name)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[gateway] %w: %s",
+			message.ErrChannelNotFound,
+			c.name,
+		)
+	}
+	channel, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[gateway] channel %s is not a publisher channel",
+			c.name,
+		)
+	}
+	c.channel = channel
+	return channel, nil
+}
@@ -14,10 +14,12 @@ package endpoint
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 	"github.com/jeffersonbrasilino/gomes/otel"
 )
 
@@ -26,6 +28,11 @@ import (
 type messageDispatcherBuilder struct {
 	referenceName      string
 	requestChannelName string
+	replyTimeout       time.Duration
+	lateReplyHandler   LateReplyHandler
+	correlationStore   handler.CorrelationStore
+	correlationTTL     time.Duration
+	tenantRouting      message.TenantChannelStrategy
 }
 
 // MessageDispatcher handles message dispatching operations through configured gateways.
@@ -53,6 +60,70 @@ func NewMessageDispatcherBuilder(
 	}
 }
 
+// WithReplyTimeout sets how long a sent message waits for its reply.
+//
+// Parameters:
+//   - timeout: the reply timeout, zero means no timeout
+//
+// Returns:
+//   - *messageDispatcherBuilder: builder instance for method chaining
+func (b *messageDispatcherBuilder) WithReplyTimeout(
+	timeout time.Duration,
+) *messageDispatcherBuilder {
+	b.replyTimeout = timeout
+	return b
+}
+
+// WithLateReplyHandler sets the handler called with the replies arriving
+// after their request finished. Without it, the late reply handler of the
+// request publisher channel is used, if any.
+//
+// Parameters:
+//   - lateReplyHandler: the late reply handler
+//
+// Returns:
+//   - *messageDispatcherBuilder: builder instance for method chaining
+func (b *messageDispatcherBuilder) WithLateReplyHandler(
+	lateReplyHandler LateReplyHandler,
+) *messageDispatcherBuilder {
+	b.lateReplyHandler = lateReplyHandler
+	return b
+}
+
+// WithCorrelationStore keeps the correlation of each sent message in the
+// given store while it waits for its reply. Without it, the correlation store
+// of the request publisher channel is used, if any.
+//
+// Parameters:
+//   - store: the pending correlation store
+//   - ttl: how long a correlation is kept, zero means it never expires
+//
+// Returns:
+//   - *messageDispatcherBuilder: builder instance for method chaining
+func (b *messageDispatcherBuilder) WithCorrelationStore(
+	store handler.CorrelationStore,
+	ttl time.Duration,
+) *messageDispatcherBuilder {
+	b.correlationStore = store
+	b.correlationTTL = ttl
+	return b
+}
+
+// WithTenantRouting dispatches the messages of each tenant to the channel of
+// the tenant given by the strategy.
+//
+// Parameters:
+//   - strategy: the tenant channel strategy
+//
+// Returns:
+//   - *messageDispatcherBuilder: builder instance for method chaining
+func (b *messageDispatcherBuilder) WithTenantRouting(
+	strategy message.TenantChannelStrategy,
+) *messageDispatcherBuilder {
+	b.tenantRouting = strategy
+	return b
+}
+
 // NewMessageDispatcher creates a new message dispatcher instance.
 //
 // Parameters:
@@ -79,14 +150,32 @@ func (b *messageDispatcherBuilder) Build(
 	container container.Container[any, any],
 ) (*MessageDispatcher, error) {
 
+	channel, _ := container.Get(b.requestChannelName)
+	correlationStore, correlationTTL := b.correlationStore, b.correlationTTL
+	if correlationStore == nil {
+		if provider, ok := channel.(correlationStoreProvider); ok {
+			correlationStore, correlationTTL = provider.CorrelationStore()
+		}
+	}
+	lateReplyHandler := b.lateReplyHandler
+	if lateReplyHandler == nil {
+		if provider, ok := channel.(lateReplyHandlerProvider); ok {
+			lateReplyHandler = provider.LateReplyHandler()
+		}
+	}
+
 	gateway, err := NewGatewayBuilder(
 		b.referenceName,
 		b.requestChannelName,
 	).
+		WithReplyTimeout(b.replyTimeout).
+		WithLateReplyHandler(lateReplyHandler).
+		WithCorrelationStore(correlationStore, correlationTTL).
+		WithTenantRouting(b.tenantRouting).
 		Build(container)
 
 	if err != nil {
-		return nil, fmt.Errorf("[message-dispatcher] %s", err)
+		return nil, fmt.Errorf("[message-dispatcher] %w", err)
 	}
 
 	dispatcher := NewMessageDispatcher(gateway)
@@ -94,6 +183,15 @@ func (b *messageDispatcherBuilder) Build(
 	return dispatcher, nil
 }
 
+// OrphanReplies returns the number of replies which arrived after their
+// request finished.
+//
+// Returns:
+//   - int64: number of orphan replies
+func (m *MessageDispatcher) OrphanReplies() int64 {
+	return m.gateway.OrphanReplies()
+}
+
 // SendMessage sends a message synchronously and waits for a response.
 //
 // Parameters:
@@ -112,18 +210,21 @@ func (m *MessageDispatcher) SendMessage(
 		ctx,
 		"",
 		otel.WithMessagingSystemType(otel.MessageSystemTypeInternal),
-		otel.WithSpanOperation(otel.SpanOperationCreate),
+		otel.WithSpanOperation(otel.SpanOperationSend),
 		otel.WithSpanKind(otel.SpanKindProducer),
+		otel.WithTraceContextToLink(msg.GetContext()),
 		otel.WithMessage(msg),
 	)
 	defer span.End()
 
-	result, err := m.gateway.Execute(ctx, msg)
+	result, err := m.gateway.Execute(message.ContextWithReplyExpected(ctx), msg)
 
 	if err != nil {
+		span.Error(err, err.Error())
 		return nil, err
 	}
 
+	span.Success("message sent")
 	return result, nil
 }
 
@@ -144,17 +245,20 @@ func (m *MessageDispatcher) PublishMessage(
 		ctx,
 		fmt.Sprintf("Create message %s", msg.GetHeader().Get(message.HeaderRoute)),
 		otel.WithMessagingSystemType(otel.MessageSystemTypeInternal),
-		otel.WithSpanOperation(otel.SpanOperationCreate),
+		otel.WithSpanOperation(otel.SpanOperationSend),
 		otel.WithSpanKind(otel.SpanKindProducer),
+		otel.WithTraceContextToLink(msg.GetContext()),
 		otel.WithMessage(msg),
 	)
 	defer span.End()
 
 	_, err := m.gateway.Execute(ctx, msg)
 	if err != nil {
+		span.Error(err, err.Error())
 		return err
 	}
 
+	span.Success("message published")
 	return nil
 }
 
@@ -2,12 +2,15 @@ package endpoint_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
 
 type dummyHandler struct{}
@@ -54,6 +57,84 @@ func TestNewMessageDispatcherBuilder_Build(t *testing.T) {
 	})
 }
 
+// correlatedChannel is a publisher channel with a correlation store which
+// never replies.
+type correlatedChannel struct {
+	store handler.CorrelationStore
+}
+
+func (c *correlatedChannel) Name() string { return "correlated" }
+
+func (c *correlatedChannel) Send(context.Context, *message.Message) error { return nil }
+
+func (c *correlatedChannel) CorrelationStore() (handler.CorrelationStore, time.Duration) {
+	return c.store, time.Minute
+}
+
+func TestNewMessageDispatcherBuilder_ChannelCorrelationStore(t *testing.T) {
+	t.Parallel()
+	store := handler.NewInMemoryCorrelationStore()
+	c := container.NewGenericContainer[any, any]()
+	c.Set("correlated", &correlatedChannel{store: store})
+	dispatcher, err := endpoint.NewMessageDispatcherBuilder("ref", "correlated").
+		WithReplyTimeout(5 * time.Millisecond).
+		Build(c)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().
+		WithMessageType(message.Command).
+		WithCorrelationId("workflow").
+		Build()
+	if _, err := dispatcher.SendMessage(context.Background(), msg); !errors.Is(err, handler.ErrReplyTimeout) {
+		t.Fatalf("expected reply timeout error, got: %v", err)
+	}
+	if _, found, _ := store.Find(context.Background(), msg.GetHeader().Get(message.HeaderMessageId)); !found {
+		t.Error("expected the correlation kept in the store of the channel")
+	}
+}
+
+// lateRepliedChannel is a publisher channel replying after a delay, with a
+// late reply handler.
+type lateRepliedChannel struct {
+	lateReplyChannel
+	lateReplies chan *message.Message
+}
+
+func (c *lateRepliedChannel) LateReplyHandler() func(msg *message.Message) {
+	return func(msg *message.Message) { c.lateReplies <- msg }
+}
+
+func TestNewMessageDispatcherBuilder_ChannelLateReplyHandler(t *testing.T) {
+	t.Parallel()
+	channel := &lateRepliedChannel{
+		lateReplyChannel: lateReplyChannel{delay: 30 * time.Millisecond},
+		lateReplies:      make(chan *message.Message, 1),
+	}
+	c := container.NewGenericContainer[any, any]()
+	c.Set("late.reply", channel)
+	dispatcher, err := endpoint.NewMessageDispatcherBuilder("ref", "late.reply").
+		WithReplyTimeout(5 * time.Millisecond).
+		Build(c)
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().WithMessageType(message.Command).Build()
+	if _, err := dispatcher.SendMessage(context.Background(), msg); !errors.Is(err, handler.ErrReplyTimeout) {
+		t.Fatalf("expected reply timeout error, got: %v", err)
+	}
+	select {
+	case <-channel.lateReplies:
+	case <-time.After(time.Second):
+		t.Fatal("expected the late reply handled by the handler of the channel")
+	}
+	if dispatcher.OrphanReplies() != 1 {
+		t.Errorf("expected 1 orphan reply, got %d", dispatcher.OrphanReplies())
+	}
+}
+
 func TestNewMessageDispatcher(t *testing.T) {
 	t.Parallel()
 	gw := endpoint.NewGateway(&dummyHandler{}, "", "channel")
@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"sync"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// MaxConsumerPriority is the highest priority of the consumer priority
+// queues; extracted priorities are clamped between zero and it.
+const MaxConsumerPriority = 9
+
+// priorityQueue is a bounded queue handing out the messages of the highest
+// priority first, in arrival order within a priority.
+type priorityQueue struct {
+	mu     sync.Mutex
+	levels [MaxConsumerPriority + 1][]*message.Message
+	// slots bounds the queued messages, a slot is taken by every push
+	slots chan struct{}
+	// items holds a token for every queued message
+	items chan struct{}
+}
+
+// newPriorityQueue creates a priority queue holding up to capacity messages.
+//
+// Parameters:
+//   - capacity: maximum queued messages
+//
+// Returns:
+//   - *priorityQueue: configured priority queue
+func newPriorityQueue(capacity int) *priorityQueue {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &priorityQueue{
+		slots: make(chan struct{}, capacity),
+		items: make(chan struct{}, capacity),
+	}
+}
+
+// push queues a message with the given priority. A slot must be taken from
+// slots before pushing.
+//
+// Parameters:
+//   - msg: the message to queue
+//   - priority: the message priority
+func (q *priorityQueue) push(msg *message.Message, priority int) {
+	priority = min(max(priority, 0), MaxConsumerPriority)
+	q.mu.Lock()
+	q.levels[priority] = append(q.levels[priority], msg)
+	q.mu.Unlock()
+	q.items <- struct{}{}
+}
+
+// pop takes the oldest message of the highest priority, releasing its slot.
+// A token must be taken from items before popping.
+//
+// Returns:
+//   - *message.Message: the message to process
+func (q *priorityQueue) pop() *message.Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer func() { <-q.slots }()
+	for priority := MaxConsumerPriority; priority >= 0; priority-- {
+		if len(q.levels[priority]) == 0 {
+			continue
+		}
+		msg := q.levels[priority][0]
+		q.levels[priority][0] = nil
+		q.levels[priority] = q.levels[priority][1:]
+		return msg
+	}
+	return nil
+}
+
+// close stops the queue; the messages already queued are still handed out.
+func (q *priorityQueue) close() {
+	close(q.items)
+}
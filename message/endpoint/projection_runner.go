@@ -0,0 +1,496 @@
+// Package endpoint implements the projection runner, which keeps CQRS read
+// models up to date from the events of a consumer channel.
+//
+// The ProjectionRunner implementation supports:
+// - A checkpoint per projection name, kept in a pluggable store
+// - Replay from the checkpoint on startup
+// - Rebuild of the read model from the beginning of the source
+// - Catch-up completion detection through an idle timeout
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+// CheckpointStore defines the contract for stores keeping the position of
+// the last event processed by each projection, i.e. its position in the
+// source of the consumer channel, such as the global position of an event
+// store. Implementations backed by the read model storage (e.g. a SQL table
+// updated in the same database) keep the checkpoint consistent with the
+// projected data.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the position of the last event processed by a
+	// projection.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - projectionName: The name of the projection
+	//
+	// Returns:
+	//   - int64: The checkpoint, zero when the projection has none
+	//   - error: Error if the store operation fails
+	LoadCheckpoint(ctx context.Context, projectionName string) (int64, error)
+	// SaveCheckpoint records the position of the last event processed by a
+	// projection.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - projectionName: The name of the projection
+	//   - position: The position of the last processed event
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	SaveCheckpoint(ctx context.Context, projectionName string, position int64) error
+}
+
+// inMemoryCheckpointStore keeps the checkpoints in memory.
+type inMemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]int64
+}
+
+// NewInMemoryCheckpointStore creates a new in-memory checkpoint store. The
+// checkpoints are lost when the process exits, so the projections are
+// rebuilt on every startup.
+//
+// Returns:
+//   - *inMemoryCheckpointStore: configured store instance
+func NewInMemoryCheckpointStore() *inMemoryCheckpointStore {
+	return &inMemoryCheckpointStore{checkpoints: map[string]int64{}}
+}
+
+// LoadCheckpoint returns the checkpoint of a projection.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - projectionName: the name of the projection
+//
+// Returns:
+//   - int64: the checkpoint, zero when the projection has none
+//   - error: always nil
+func (s *inMemoryCheckpointStore) LoadCheckpoint(
+	ctx context.Context,
+	projectionName string,
+) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoints[projectionName], nil
+}
+
+// SaveCheckpoint records the checkpoint of a projection.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - projectionName: the name of the projection
+//   - position: the position of the last processed event
+//
+// Returns:
+//   - error: always nil
+func (s *inMemoryCheckpointStore) SaveCheckpoint(
+	ctx context.Context,
+	projectionName string,
+	position int64,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[projectionName] = position
+	return nil
+}
+
+// ProjectionRunnerBuilder is responsible for building ProjectionRunner
+// instances. referenceName identifies the consumer channel of the events.
+type ProjectionRunnerBuilder struct {
+	projectionName     string
+	referenceName      string
+	checkpointStore    CheckpointStore
+	beforeInterceptors []message.MessageHandler
+	afterInterceptors  []message.MessageHandler
+}
+
+// ProjectionRunner feeds the events of a consumer channel to the handlers of
+// a read model, one at a time, saving the position of each processed event
+// as the projection checkpoint. The channel must be able to seek, e.g. an
+// event store consumer channel, whose positions are the global positions of
+// the events, so a projection may span every stream or a category of
+// streams. Events at or before the checkpoint, e.g. redelivered by the
+// channel, are skipped.
+type ProjectionRunner struct {
+	projectionName                string
+	gateway                       *Gateway
+	inboundChannelAdapter         InboundChannelAdapter
+	checkpointStore               CheckpointStore
+	reset                         func(ctx context.Context) error
+	idleTimeout                   time.Duration
+	processingTimeoutMilliseconds int
+}
+
+// NewProjectionRunnerBuilder creates a new ProjectionRunnerBuilder instance.
+//
+// Parameters:
+//   - projectionName: name of the projection, identifying its checkpoint
+//   - referenceName: reference name of the consumer channel of the events
+//   - checkpointStore: store of the projection checkpoint
+//
+// Returns:
+//   - *ProjectionRunnerBuilder: pointer to ProjectionRunnerBuilder
+func NewProjectionRunnerBuilder(
+	projectionName string,
+	referenceName string,
+	checkpointStore CheckpointStore,
+) *ProjectionRunnerBuilder {
+	return &ProjectionRunnerBuilder{
+		projectionName:  projectionName,
+		referenceName:   referenceName,
+		checkpointStore: checkpointStore,
+	}
+}
+
+// NewProjectionRunner creates a new ProjectionRunner instance.
+//
+// Parameters:
+//   - projectionName: name of the projection, identifying its checkpoint
+//   - gateway: pointer to the associated Gateway
+//   - inboundChannelAdapter: consumer channel of the events
+//   - checkpointStore: store of the projection checkpoint
+//
+// Returns:
+//   - *ProjectionRunner: pointer to ProjectionRunner
+func NewProjectionRunner(
+	projectionName string,
+	gateway *Gateway,
+	inboundChannelAdapter InboundChannelAdapter,
+	checkpointStore CheckpointStore,
+) *ProjectionRunner {
+	return &ProjectionRunner{
+		projectionName:                projectionName,
+		gateway:                       gateway,
+		inboundChannelAdapter:         inboundChannelAdapter,
+		checkpointStore:               checkpointStore,
+		processingTimeoutMilliseconds: 100000,
+	}
+}
+
+// WithBeforeInterceptors adds interceptors executed before the interceptors of
+// the consumer channel.
+//
+// Parameters:
+//   - interceptors: message handlers to execute before processing
+//
+// Returns:
+//   - *ProjectionRunnerBuilder: builder instance for method chaining
+func (b *ProjectionRunnerBuilder) WithBeforeInterceptors(
+	interceptors ...message.MessageHandler,
+) *ProjectionRunnerBuilder {
+	b.beforeInterceptors = append(b.beforeInterceptors, interceptors...)
+	return b
+}
+
+// WithAfterInterceptors adds interceptors executed after the interceptors of
+// the consumer channel.
+//
+// Parameters:
+//   - interceptors: message handlers to execute after processing
+//
+// Returns:
+//   - *ProjectionRunnerBuilder: builder instance for method chaining
+func (b *ProjectionRunnerBuilder) WithAfterInterceptors(
+	interceptors ...message.MessageHandler,
+) *ProjectionRunnerBuilder {
+	b.afterInterceptors = append(b.afterInterceptors, interceptors...)
+	return b
+}
+
+// Build constructs a ProjectionRunner from the dependency container.
+//
+// Parameters:
+//   - container: dependency container
+//
+// Returns:
+//   - *ProjectionRunner: pointer to ProjectionRunner
+//   - error: error if the consumer channel is not found or cannot seek
+func (b *ProjectionRunnerBuilder) Build(
+	container container.Container[any, any],
+) (*ProjectionRunner, error) {
+	if b.checkpointStore == nil {
+		return nil, fmt.Errorf(
+			"[projection-runner] projection %s has no checkpoint store",
+			b.projectionName,
+		)
+	}
+
+	anyChannel, err := container.Get(ConsumerChannelKey(b.referenceName))
+	if err != nil {
+		return nil,
+			fmt.Errorf(
+				"[projection-runner] consumer %w: %s",
+				message.ErrChannelNotFound,
+				b.referenceName,
+			)
+	}
+
+	inboundChannel, ok := anyChannel.(InboundChannelAdapter)
+	if !ok {
+		return nil,
+			fmt.Errorf(
+				"[projection-runner] consumer channel %s is not a consumer channel.",
+				b.referenceName,
+			)
+	}
+	if _, ok := inboundChannel.(seekableChannelProvider); !ok {
+		return nil,
+			fmt.Errorf(
+				"[projection-runner] consumer channel %s cannot seek.",
+				b.referenceName,
+			)
+	}
+
+	gateway, err := buildInboundGateway(
+		container,
+		inboundChannel,
+		b.beforeInterceptors,
+		b.afterInterceptors,
+		handler.AckAuto,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProjectionRunner(
+		b.projectionName,
+		gateway,
+		inboundChannel,
+		b.checkpointStore,
+	), nil
+}
+
+// WithResetHandler sets the function clearing the read model before a
+// rebuild, e.g. truncating its tables.
+//
+// Parameters:
+//   - reset: function clearing the read model
+//
+// Returns:
+//   - *ProjectionRunner: pointer to ProjectionRunner for method chaining
+func (r *ProjectionRunner) WithResetHandler(
+	reset func(ctx context.Context) error,
+) *ProjectionRunner {
+	r.reset = reset
+	return r
+}
+
+// WithIdleTimeout sets how long the runner waits for a new event before
+// considering the projection caught up and returning.
+//
+// default value: 0 (runs until the context is cancelled)
+//
+// Parameters:
+//   - value: idle duration
+//
+// Returns:
+//   - *ProjectionRunner: pointer to ProjectionRunner for method chaining
+func (r *ProjectionRunner) WithIdleTimeout(value time.Duration) *ProjectionRunner {
+	if value > 0 {
+		r.idleTimeout = value
+	}
+	return r
+}
+
+// WithMessageProcessingTimeout sets the message processing timeout in
+// milliseconds.
+//
+// Parameters:
+//   - milliseconds: timeout in milliseconds
+//
+// Returns:
+//   - *ProjectionRunner: pointer to ProjectionRunner for method chaining
+func (r *ProjectionRunner) WithMessageProcessingTimeout(
+	milliseconds int,
+) *ProjectionRunner {
+	if milliseconds > 0 {
+		r.processingTimeoutMilliseconds = milliseconds
+	}
+	return r
+}
+
+// ProjectionName returns the name of the projection.
+//
+// Returns:
+//   - string: the projection name
+func (r *ProjectionRunner) ProjectionName() string {
+	return r.projectionName
+}
+
+// Checkpoint returns the position of the last event processed by the
+// projection.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//
+// Returns:
+//   - int64: the checkpoint, zero when the projection has none
+//   - error: error if the checkpoint store fails
+func (r *ProjectionRunner) Checkpoint(ctx context.Context) (int64, error) {
+	return r.checkpointStore.LoadCheckpoint(ctx, r.projectionName)
+}
+
+// Run replays the events after the projection checkpoint, then keeps
+// projecting the new events until the idle timeout is reached, the context
+// is cancelled or an event fails. A failed event does not advance the
+// checkpoint, so it is replayed on the next run. The consumer channel stays
+// open, so the projection can be run again or rebuilt.
+//
+// Parameters:
+//   - ctx: context for cancellation and timeout control
+//
+// Returns:
+//   - error: the first receive, processing or checkpoint error, or the
+//     context error
+func (r *ProjectionRunner) Run(ctx context.Context) error {
+	checkpoint, err := r.Checkpoint(ctx)
+	if err != nil {
+		return fmt.Errorf(
+			"[projection-runner] failed to load checkpoint of %s: %w",
+			r.projectionName,
+			err,
+		)
+	}
+	seekableChannel, err := r.seekable()
+	if err != nil {
+		return err
+	}
+	if err := seekableChannel.SeekTo(checkpoint + 1); err != nil {
+		return fmt.Errorf("[projection-runner] %w", err)
+	}
+
+	slog.Info(
+		"[projection-runner] started.",
+		"projection", r.projectionName,
+		"checkpoint", checkpoint,
+	)
+	for {
+		receiveCtx, cancelReceive := ctx, context.CancelFunc(func() {})
+		if r.idleTimeout > 0 {
+			receiveCtx, cancelReceive = context.WithTimeout(ctx, r.idleTimeout)
+		}
+		msg, err := r.inboundChannelAdapter.ReceiveMessage(receiveCtx)
+		idle := receiveCtx.Err() != nil
+		cancelReceive()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if idle {
+			slog.Info(
+				"[projection-runner] caught up.",
+				"projection", r.projectionName,
+			)
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		checkpoint, err = r.project(ctx, seekableChannel, msg, checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Rebuild clears the read model through the reset handler, discards the
+// projection checkpoint and replays the events from the beginning.
+//
+// Parameters:
+//   - ctx: context for cancellation and timeout control
+//
+// Returns:
+//   - error: error if the reset fails, or the error of Run
+func (r *ProjectionRunner) Rebuild(ctx context.Context) error {
+	slog.Info("[projection-runner] rebuilding.", "projection", r.projectionName)
+	if r.reset != nil {
+		if err := r.reset(ctx); err != nil {
+			return fmt.Errorf(
+				"[projection-runner] failed to reset %s: %w",
+				r.projectionName,
+				err,
+			)
+		}
+	}
+	if err := r.checkpointStore.SaveCheckpoint(ctx, r.projectionName, 0); err != nil {
+		return fmt.Errorf(
+			"[projection-runner] failed to reset checkpoint of %s: %w",
+			r.projectionName,
+			err,
+		)
+	}
+	return r.Run(ctx)
+}
+
+// project sends the event to the gateway and saves its position as the
+// projection checkpoint, skipping the events already projected. It returns
+// the checkpoint after the event.
+func (r *ProjectionRunner) project(
+	ctx context.Context,
+	seekableChannel seekableChannelProvider,
+	msg *message.Message,
+	checkpoint int64,
+) (int64, error) {
+	position, err := seekableChannel.PositionOf(msg)
+	if err != nil {
+		return checkpoint, fmt.Errorf("[projection-runner] %w", err)
+	}
+	if position <= checkpoint {
+		return checkpoint, nil
+	}
+
+	opCtx, cancel := context.WithTimeout(
+		ctx,
+		time.Duration(r.processingTimeoutMilliseconds)*time.Millisecond,
+	)
+	defer cancel()
+
+	if _, err := r.gateway.Execute(opCtx, msg); err != nil {
+		slog.Error("[projection-runner] processing message error.",
+			"projection", r.projectionName,
+			"projection.position", position,
+			"consumer.messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"consumer.error", err.Error(),
+		)
+		return checkpoint, err
+	}
+
+	if err := r.checkpointStore.SaveCheckpoint(ctx, r.projectionName, position); err != nil {
+		return checkpoint, fmt.Errorf(
+			"[projection-runner] failed to save checkpoint of %s: %w",
+			r.projectionName,
+			err,
+		)
+	}
+	return position, nil
+}
+
+// seekable returns the consumer channel as a seekable channel.
+func (r *ProjectionRunner) seekable() (seekableChannelProvider, error) {
+	seekableChannel, ok := r.inboundChannelAdapter.(seekableChannelProvider)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[projection-runner] consumer channel %s cannot seek.",
+			r.inboundChannelAdapter.ReferenceName(),
+		)
+	}
+	return seekableChannel, nil
+}
@@ -0,0 +1,200 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// streamInboundAdapter replays a stream of messages from a position, the
+// position of each message being its index plus one.
+type streamInboundAdapter struct {
+	fakeInboundAdapter
+	mu       sync.Mutex
+	messages []*message.Message
+	next     int64
+}
+
+func newStreamInboundAdapter(payloads ...string) *streamInboundAdapter {
+	adapter := &streamInboundAdapter{next: 1}
+	for i, payload := range payloads {
+		adapter.messages = append(adapter.messages, message.NewMessageBuilder().
+			WithMessageType(message.Event).
+			WithCustomHeader("position", strconv.Itoa(i+1)).
+			WithPayload(payload).
+			Build())
+	}
+	return adapter
+}
+
+func (s *streamInboundAdapter) ReceiveMessage(
+	ctx context.Context,
+) (*message.Message, error) {
+	s.mu.Lock()
+	if s.next <= int64(len(s.messages)) {
+		msg := s.messages[s.next-1]
+		s.next++
+		s.mu.Unlock()
+		return msg, nil
+	}
+	s.mu.Unlock()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *streamInboundAdapter) SeekTo(position int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next = position
+	return nil
+}
+
+func (s *streamInboundAdapter) PositionOf(msg *message.Message) (int64, error) {
+	return strconv.ParseInt(msg.GetHeader().Get("position"), 10, 64)
+}
+
+type projectionRecorderHandler struct {
+	projected []string
+}
+
+func (p *projectionRecorderHandler) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetPayload() == "fail" {
+		return nil, errors.New("projection failed")
+	}
+	p.projected = append(p.projected, msg.GetPayload().(string))
+	return msg, nil
+}
+
+func TestProjectionRunnerBuilder_Build(t *testing.T) {
+	t.Run("fails when consumer channel is not found", func(t *testing.T) {
+		t.Parallel()
+		_, err := endpoint.NewProjectionRunnerBuilder(
+			"orders",
+			"ref",
+			endpoint.NewInMemoryCheckpointStore(),
+		).Build(container.NewGenericContainer[any, any]())
+		if !errors.Is(err, message.ErrChannelNotFound) {
+			t.Errorf("Expected not found error, got: %v", err)
+		}
+	})
+
+	t.Run("fails when consumer channel cannot seek", func(t *testing.T) {
+		t.Parallel()
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set(endpoint.ConsumerChannelKey("ref"), newHistoryInboundAdapter())
+		_, err := endpoint.NewProjectionRunnerBuilder(
+			"orders",
+			"ref",
+			endpoint.NewInMemoryCheckpointStore(),
+		).Build(cont)
+		if err == nil || err.Error() != "[projection-runner] consumer channel ref cannot seek." {
+			t.Errorf("Expected seek error, got: %v", err)
+		}
+	})
+}
+
+func TestProjectionRunner_Run(t *testing.T) {
+	t.Run("replays from the checkpoint and saves the position", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		store := endpoint.NewInMemoryCheckpointStore()
+		store.SaveCheckpoint(ctx, "orders", 1)
+		recorder := &projectionRecorderHandler{}
+		runner := endpoint.NewProjectionRunner(
+			"orders",
+			endpoint.NewGateway(recorder, "", ""),
+			newStreamInboundAdapter("1", "2", "3"),
+			store,
+		).WithIdleTimeout(50 * time.Millisecond)
+
+		if err := runner.Run(ctx); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(recorder.projected) != 2 || recorder.projected[0] != "2" {
+			t.Errorf("Expected events after the checkpoint, got %v", recorder.projected)
+		}
+		if checkpoint, _ := runner.Checkpoint(ctx); checkpoint != 3 {
+			t.Errorf("Expected checkpoint 3, got %d", checkpoint)
+		}
+	})
+
+	t.Run("skips events redelivered at or before the checkpoint", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		recorder := &projectionRecorderHandler{}
+		adapter := newStreamInboundAdapter("1", "2", "2")
+		adapter.messages[2].GetHeader().Set("position", "2")
+		runner := endpoint.NewProjectionRunner(
+			"orders",
+			endpoint.NewGateway(recorder, "", ""),
+			adapter,
+			endpoint.NewInMemoryCheckpointStore(),
+		).WithIdleTimeout(50 * time.Millisecond)
+
+		if err := runner.Run(ctx); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(recorder.projected) != 2 {
+			t.Errorf("Expected the redelivered event skipped, got %v", recorder.projected)
+		}
+		if checkpoint, _ := runner.Checkpoint(ctx); checkpoint != 2 {
+			t.Errorf("Expected checkpoint 2, got %d", checkpoint)
+		}
+	})
+
+	t.Run("stops without advancing the checkpoint when an event fails", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		runner := endpoint.NewProjectionRunner(
+			"orders",
+			endpoint.NewGateway(&projectionRecorderHandler{}, "", ""),
+			newStreamInboundAdapter("1", "fail", "3"),
+			endpoint.NewInMemoryCheckpointStore(),
+		).WithIdleTimeout(50 * time.Millisecond)
+
+		if err := runner.Run(ctx); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if checkpoint, _ := runner.Checkpoint(ctx); checkpoint != 1 {
+			t.Errorf("Expected checkpoint 1, got %d", checkpoint)
+		}
+	})
+}
+
+func TestProjectionRunner_Rebuild(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	recorder := &projectionRecorderHandler{}
+	resets := 0
+	runner := endpoint.NewProjectionRunner(
+		"orders",
+		endpoint.NewGateway(recorder, "", ""),
+		newStreamInboundAdapter("1", "2"),
+		endpoint.NewInMemoryCheckpointStore(),
+	).WithIdleTimeout(50 * time.Millisecond).
+		WithResetHandler(func(ctx context.Context) error {
+			resets++
+			recorder.projected = nil
+			return nil
+		})
+
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := runner.Rebuild(ctx); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if resets != 1 || len(recorder.projected) != 2 {
+		t.Errorf("Expected the read model rebuilt once, got %d resets and %v", resets, recorder.projected)
+	}
+}
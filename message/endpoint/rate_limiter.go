@@ -0,0 +1,81 @@
+package endpoint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket limits how many messages are dispatched per second, allowing
+// bursts up to its capacity.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// newTokenBucket creates a full token bucket.
+//
+// Parameters:
+//   - perSecond: tokens refilled per second
+//   - burst: maximum tokens kept in the bucket
+//
+// Returns:
+//   - *tokenBucket: configured token bucket
+func newTokenBucket(perSecond float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:     perSecond,
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and takes it.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//
+// Returns:
+//   - error: the context error if it is done before a token is available
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token when available, otherwise it returns how long until
+// the next token is refilled.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
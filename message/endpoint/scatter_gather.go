@@ -0,0 +1,301 @@
+// Package endpoint provides scatter-gather capabilities for the message system.
+//
+// This package implements the Scatter-Gather pattern from Enterprise
+// Integration Patterns, publishing one request to many channels and combining
+// their replies into a single result.
+//
+// The ScatterGather implementation supports:
+// - Simultaneous publishing of a request to multiple channels
+// - Waiting for the first successful reply or for all replies
+// - Gather timeout, replies not received in time are reported as failed
+// - User-supplied gather strategy aggregating the replies
+// - Usage as a bus dispatcher, e.g. from one QueryBus call
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// ScatterReply holds the reply of one channel of a scatter-gather request.
+type ScatterReply struct {
+	ChannelName string
+	Payload     any
+	Err         error
+}
+
+// GatherStrategy aggregates the replies of a scatter-gather request into its
+// result. Replies are ordered as the channels were configured.
+type GatherStrategy func(replies []ScatterReply) (any, error)
+
+// scatterGatherBuilder provides a builder pattern for creating ScatterGather
+// instances.
+type scatterGatherBuilder struct {
+	referenceName string
+	channelNames  []string
+	timeout       time.Duration
+	gatherFirst   bool
+	strategy      GatherStrategy
+}
+
+// ScatterGather publishes requests to multiple channels and gathers their
+// replies.
+type ScatterGather struct {
+	channelNames []string
+	dispatchers  []*MessageDispatcher
+	timeout      time.Duration
+	gatherFirst  bool
+	strategy     GatherStrategy
+}
+
+// NewScatterGatherBuilder creates a new scatter-gather builder instance.
+//
+// Parameters:
+//   - referenceName: unique identifier for the scatter-gather
+//   - channelNames: the channels receiving each request
+//
+// Returns:
+//   - *scatterGatherBuilder: configured builder instance
+func NewScatterGatherBuilder(
+	referenceName string,
+	channelNames ...string,
+) *scatterGatherBuilder {
+	return &scatterGatherBuilder{
+		referenceName: referenceName,
+		channelNames:  channelNames,
+		strategy:      GatherPayloads,
+	}
+}
+
+// ReferenceName returns the reference name of the scatter-gather.
+//
+// Returns:
+//   - string: the reference name
+func (b *scatterGatherBuilder) ReferenceName() string {
+	return b.referenceName
+}
+
+// WithTimeout sets how long the replies are awaited. Zero means the wait is
+// bound only by the request context.
+//
+// Parameters:
+//   - timeout: the gather timeout
+//
+// Returns:
+//   - *scatterGatherBuilder: builder instance for method chaining
+func (b *scatterGatherBuilder) WithTimeout(timeout time.Duration) *scatterGatherBuilder {
+	b.timeout = timeout
+	return b
+}
+
+// WithGatherFirst makes requests complete with the first successful reply,
+// cancelling the remaining ones. By default all replies are awaited.
+//
+// Returns:
+//   - *scatterGatherBuilder: builder instance for method chaining
+func (b *scatterGatherBuilder) WithGatherFirst() *scatterGatherBuilder {
+	b.gatherFirst = true
+	return b
+}
+
+// WithGatherStrategy sets how the replies are aggregated. Defaults to
+// GatherPayloads.
+//
+// Parameters:
+//   - strategy: the gather strategy
+//
+// Returns:
+//   - *scatterGatherBuilder: builder instance for method chaining
+func (b *scatterGatherBuilder) WithGatherStrategy(
+	strategy GatherStrategy,
+) *scatterGatherBuilder {
+	if strategy != nil {
+		b.strategy = strategy
+	}
+	return b
+}
+
+// Build constructs a ScatterGather from the dependency container, with one
+// message dispatcher per channel.
+//
+// Parameters:
+//   - container: dependency container containing required components
+//
+// Returns:
+//   - *ScatterGather: configured scatter-gather
+//   - error: error if there are no channels or a dispatcher cannot be built
+func (b *scatterGatherBuilder) Build(
+	container container.Container[any, any],
+) (*ScatterGather, error) {
+	if len(b.channelNames) == 0 {
+		return nil, fmt.Errorf("[scatter-gather] %s has no channels", b.referenceName)
+	}
+
+	dispatchers := make([]*MessageDispatcher, 0, len(b.channelNames))
+	for _, channelName := range b.channelNames {
+		dispatcher, err := NewMessageDispatcherBuilder(
+			fmt.Sprintf("%s:%s", b.referenceName, channelName),
+			channelName,
+		).Build(container)
+		if err != nil {
+			return nil, fmt.Errorf("[scatter-gather] %w", err)
+		}
+		dispatchers = append(dispatchers, dispatcher)
+	}
+
+	return &ScatterGather{
+		channelNames: b.channelNames,
+		dispatchers:  dispatchers,
+		timeout:      b.timeout,
+		gatherFirst:  b.gatherFirst,
+		strategy:     b.strategy,
+	}, nil
+}
+
+// GatherPayloads is the default gather strategy. It returns the payloads of
+// the successful replies as []any, failing only if every reply failed.
+//
+// Parameters:
+//   - replies: the scatter-gather replies
+//
+// Returns:
+//   - any: the successful reply payloads
+//   - error: the joined reply errors if no reply succeeded
+func GatherPayloads(replies []ScatterReply) (any, error) {
+	payloads := []any{}
+	errs := []error{}
+	for _, reply := range replies {
+		if reply.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", reply.ChannelName, reply.Err))
+			continue
+		}
+		payloads = append(payloads, reply.Payload)
+	}
+
+	if len(payloads) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("[scatter-gather] all replies failed: %w", errors.Join(errs...))
+	}
+	return payloads, nil
+}
+
+// SendMessage publishes the message to every channel and gathers the replies.
+// Each channel receives a copy of the message with its own message id and the
+// same correlation id.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be sent
+//
+// Returns:
+//   - any: the gathered result
+//   - error: error if gathering fails
+func (s *ScatterGather) SendMessage(
+	ctx context.Context,
+	msg *message.Message,
+) (any, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexedReply struct {
+		index int
+		reply ScatterReply
+	}
+	received := make(chan indexedReply, len(s.dispatchers))
+	for i, dispatcher := range s.dispatchers {
+		go func(index int, dispatcher *MessageDispatcher) {
+			payload, err := dispatcher.SendMessage(ctx, s.copyMessage(msg))
+			received <- indexedReply{index, ScatterReply{
+				ChannelName: s.channelNames[index],
+				Payload:     payload,
+				Err:         err,
+			}}
+		}(i, dispatcher)
+	}
+
+	replies := make([]ScatterReply, len(s.dispatchers))
+	replied := make([]bool, len(s.dispatchers))
+	for i, channelName := range s.channelNames {
+		replies[i] = ScatterReply{ChannelName: channelName}
+	}
+
+	pending := len(s.dispatchers)
+	for pending > 0 {
+		select {
+		case r := <-received:
+			pending--
+			replies[r.index] = r.reply
+			replied[r.index] = true
+			if s.gatherFirst && r.reply.Err == nil {
+				return s.strategy([]ScatterReply{r.reply})
+			}
+		case <-ctx.Done():
+			for i := range replies {
+				if !replied[i] {
+					replies[i].Err = ctx.Err()
+				}
+			}
+			pending = 0
+		}
+	}
+
+	return s.strategy(replies)
+}
+
+// PublishMessage publishes the message to every channel without gathering
+// replies.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be published
+//
+// Returns:
+//   - error: the joined errors of the channels which failed
+func (s *ScatterGather) PublishMessage(
+	ctx context.Context,
+	msg *message.Message,
+) error {
+	errs := []error{}
+	for i, dispatcher := range s.dispatchers {
+		if err := dispatcher.PublishMessage(ctx, s.copyMessage(msg)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.channelNames[i], err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("[scatter-gather] publish failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// MessageBuilder creates a message builder with automatic correlation ID
+// generation.
+//
+// Parameters:
+//   - messageType: the message type
+//   - payload: the message payload
+//   - headers: the message headers
+//
+// Returns:
+//   - *message.MessageBuilder: configured message builder
+func (s *ScatterGather) MessageBuilder(
+	messageType message.MessageType,
+	payload any,
+	headers map[string]string,
+) *message.MessageBuilder {
+	return s.dispatchers[0].MessageBuilder(messageType, payload, headers)
+}
+
+func (s *ScatterGather) copyMessage(msg *message.Message) *message.Message {
+	return message.NewMessageBuilderFromMessage(msg).
+		WithMessageId("").
+		Build()
+}
@@ -0,0 +1,105 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/bus"
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// replyingChannel replies to every request with its payload or error.
+type replyingChannel struct {
+	name    string
+	payload any
+	delay   time.Duration
+}
+
+func (c *replyingChannel) Name() string { return c.name }
+
+func (c *replyingChannel) Send(ctx context.Context, msg *message.Message) error {
+	go func() {
+		time.Sleep(c.delay)
+		reply := message.NewMessageBuilder().WithPayload(c.payload).Build()
+		msg.GetInternalReplyChannel().Send(context.Background(), reply)
+	}()
+	return nil
+}
+
+func newScatterGatherContainer() container.Container[any, any] {
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("orders", &replyingChannel{name: "orders", payload: "order"})
+	cont.Set("billing", &replyingChannel{name: "billing", payload: "invoice", delay: 20 * time.Millisecond})
+	cont.Set("failing", &replyingChannel{name: "failing", payload: errors.New("unavailable")})
+	cont.Set("slow", &replyingChannel{name: "slow", payload: "late", delay: time.Second})
+	return cont
+}
+
+func TestScatterGather_GatherAll(t *testing.T) {
+	t.Parallel()
+	scatterGather, err := endpoint.NewScatterGatherBuilder("customer", "orders", "billing", "failing").
+		Build(newScatterGatherContainer())
+	if err != nil {
+		t.Fatalf("Build should return nil error, got: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().WithCorrelationId("corr-sg").Build()
+	result, err := scatterGather.SendMessage(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("Expected gathered result, got: %v", err)
+	}
+	if !reflect.DeepEqual(result, []any{"order", "invoice"}) {
+		t.Errorf("Expected successful payloads in channel order, got %v", result)
+	}
+}
+
+func TestScatterGather_GatherFirstAndTimeout(t *testing.T) {
+	t.Parallel()
+	cont := newScatterGatherContainer()
+
+	first, _ := endpoint.NewScatterGatherBuilder("first", "slow", "failing", "billing").
+		WithGatherFirst().
+		Build(cont)
+	result, err := first.SendMessage(context.Background(), message.NewMessageBuilder().Build())
+	if err != nil || !reflect.DeepEqual(result, []any{"invoice"}) {
+		t.Errorf("Expected first successful reply, got %v (%v)", result, err)
+	}
+
+	var gathered []endpoint.ScatterReply
+	timed, _ := endpoint.NewScatterGatherBuilder("timed", "orders", "slow").
+		WithTimeout(50 * time.Millisecond).
+		WithGatherStrategy(func(replies []endpoint.ScatterReply) (any, error) {
+			gathered = replies
+			return len(replies), nil
+		}).
+		Build(cont)
+	if _, err := timed.SendMessage(context.Background(), message.NewMessageBuilder().Build()); err != nil {
+		t.Fatalf("Expected strategy result, got: %v", err)
+	}
+	if gathered[0].Err != nil || gathered[0].Payload != "order" {
+		t.Errorf("Expected orders reply, got %+v", gathered[0])
+	}
+	if !errors.Is(gathered[1].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected slow reply to time out, got %+v", gathered[1])
+	}
+}
+
+func TestScatterGather_QueryBus(t *testing.T) {
+	t.Parallel()
+	scatterGather, _ := endpoint.NewScatterGatherBuilder("query", "failing").
+		Build(newScatterGatherContainer())
+
+	queryBus := bus.NewQueryBus(scatterGather)
+	if _, err := queryBus.SendRaw(context.Background(), "customer.get", "1", nil); err == nil {
+		t.Error("Expected error when every reply failed")
+	}
+
+	if _, err := endpoint.NewScatterGatherBuilder("empty").Build(newScatterGatherContainer()); err == nil {
+		t.Error("Expected error for scatter-gather without channels")
+	}
+}
@@ -0,0 +1,111 @@
+// Package message provides the sentinel errors of the message system.
+package message
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors wrapped by the errors of the message system, so callers can
+// handle them with errors.Is.
+var (
+	// ErrChannelNotFound is wrapped when a channel is not registered.
+	ErrChannelNotFound = errors.New("channel not found")
+	// ErrDuplicateRegistration is wrapped when a component is registered
+	// twice under the same name.
+	ErrDuplicateRegistration = errors.New("already exists")
+	// ErrNotStarted is wrapped when the message system is used before it is
+	// started.
+	ErrNotStarted = errors.New("message system not started")
+	// ErrHandlerNotFound is wrapped when no handler is registered for the
+	// route of a message.
+	ErrHandlerNotFound = errors.New("handler not found")
+	// ErrTranslation is wrapped when a message or its payload cannot be
+	// translated.
+	ErrTranslation = errors.New("message translation failed")
+	// ErrTimeout is wrapped when an operation does not finish in time.
+	ErrTimeout = errors.New("timeout")
+	// ErrInvalidTenant is wrapped when a message has no tenant or a tenant
+	// not accepted by its consumer.
+	ErrInvalidTenant = errors.New("invalid tenant")
+	// ErrPanic is wrapped when a handler panics while processing a message.
+	ErrPanic = errors.New("handler panicked")
+	// ErrGuardViolation is wrapped when a received message violates a guard
+	// of its consumer channel.
+	ErrGuardViolation = errors.New("message guard violated")
+)
+
+// PanicError is the error of a handler which panicked, carrying the recovered
+// value and the stack trace of the panic. It wraps ErrPanic.
+type PanicError struct {
+	// Value is the value recovered from the panic.
+	Value any
+	// Stack is the stack trace of the goroutine which panicked.
+	Stack string
+}
+
+// NewPanicError creates the error of a recovered panic.
+//
+// Parameters:
+//   - value: the value recovered from the panic
+//   - stack: the stack trace of the panic, e.g. from debug.Stack
+//
+// Returns:
+//   - *PanicError: the panic error
+func NewPanicError(value any, stack []byte) *PanicError {
+	return &PanicError{Value: value, Stack: string(stack)}
+}
+
+// Error returns the recovered value of the panic.
+//
+// Returns:
+//   - string: the error message
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrPanic.Error(), e.Value)
+}
+
+// Unwrap returns ErrPanic, so panics can be handled with errors.Is.
+//
+// Returns:
+//   - error: ErrPanic
+func (e *PanicError) Unwrap() error {
+	return ErrPanic
+}
+
+// GuardError is the error of a received message rejected by a guard of its
+// consumer channel, carrying the violated guard. It wraps ErrGuardViolation.
+type GuardError struct {
+	// Guard is the violated guard, e.g. maxPayloadSize.
+	Guard string
+	// Reason describes the violation.
+	Reason string
+}
+
+// NewGuardError creates the error of a guard violation.
+//
+// Parameters:
+//   - guard: the violated guard
+//   - reason: the description of the violation
+//
+// Returns:
+//   - *GuardError: the guard error
+func NewGuardError(guard string, reason string) *GuardError {
+	return &GuardError{Guard: guard, Reason: reason}
+}
+
+// Error returns the violated guard and the reason of the violation.
+//
+// Returns:
+//   - string: the error message
+func (e *GuardError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrGuardViolation.Error(), e.Guard, e.Reason)
+}
+
+// Unwrap returns ErrGuardViolation, so guard violations can be handled with
+// errors.Is.
+//
+// Returns:
+//   - error: ErrGuardViolation
+func (e *GuardError) Unwrap() error {
+	return ErrGuardViolation
+}
@@ -6,7 +6,9 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 )
@@ -25,11 +27,56 @@ type ChannelMessageAcknowledgment interface {
 	CommitMessage(msg *message.Message) error
 }
 
+// ChannelMessageNegativeAcknowledgment is implemented by channels able to
+// settle a message as not processed.
+type ChannelMessageNegativeAcknowledgment interface {
+	// NackMessage marks a message as not processed.
+	//
+	// Parameters:
+	//   - msg: The message to settle
+	//   - requeue: true to redeliver the message, false to discard it
+	//
+	// Returns:
+	//   - error: Error if the settlement fails
+	NackMessage(msg *message.Message, requeue bool) error
+}
+
+// AckMode defines when the processed messages are acknowledged.
+type AckMode int
+
+// Acknowledgment modes.
+const (
+	// AckAuto acknowledges every message after processing, even failed ones.
+	AckAuto AckMode = iota
+	// AckOnSuccess acknowledges successfully processed messages only, failed
+	// messages are requeued for redelivery.
+	AckOnSuccess
+	// AckManual leaves the acknowledgment to the handlers, through the
+	// Acknowledger carried by the message context.
+	AckManual
+)
+
+// String returns the string representation of an AckMode.
+//
+// Returns:
+//   - string: the mode name
+func (m AckMode) String() string {
+	switch m {
+	case AckOnSuccess:
+		return "on-success"
+	case AckManual:
+		return "manual"
+	default:
+		return "auto"
+	}
+}
+
 // acknowledgeHandler wraps a message handler with automatic message acknowledgment
 // support, ensuring messages are committed after successful processing.
 type acknowledgeHandler struct {
 	channelAdapter ChannelMessageAcknowledgment
 	handler        message.MessageHandler
+	mode           AckMode
 }
 
 // NewAcknowledgeHandler creates a new acknowledge handler that wraps an existing
@@ -48,8 +95,23 @@ func NewAcknowledgeHandler(
 	return &acknowledgeHandler{channelAdapter: channel, handler: handler}
 }
 
-// Handle processes a message through the wrapped handler and automatically
-// acknowledges it after processing, regardless of success or failure.
+// WithAckMode sets when the processed messages are acknowledged.
+//
+// Parameters:
+//   - mode: The acknowledgment mode, AckAuto by default
+//
+// Returns:
+//   - *acknowledgeHandler: Handler instance for method chaining
+func (h *acknowledgeHandler) WithAckMode(mode AckMode) *acknowledgeHandler {
+	h.mode = mode
+	return h
+}
+
+// Handle processes a message through the wrapped handler and acknowledges it
+// according to the acknowledgment mode. The handlers and interceptors may
+// settle the message themselves through the Acknowledger carried by the
+// context, in which case it is not acknowledged again, or defer its
+// settlement through message.DeferrableAcknowledger.
 //
 // Parameters:
 //   - ctx: Context for timeout/cancellation control
@@ -62,8 +124,30 @@ func (h *acknowledgeHandler) Handle(
 	ctx context.Context,
 	msg *message.Message,
 ) (*message.Message, error) {
+	acknowledger := &messageAcknowledger{channel: h.channelAdapter, msg: msg}
+	ctx = message.ContextWithAcknowledger(ctx, acknowledger)
+	if msg != nil {
+		msg.SetContext(message.ContextWithAcknowledger(msg.GetContext(), acknowledger))
+	}
+
 	resultMessage, err := h.handler.Handle(ctx, msg)
-	errC := h.channelAdapter.CommitMessage(msg)
+
+	var errC error
+	switch {
+	case acknowledger.isSettled(), acknowledger.isDeferred():
+	case IsReplyNotPublished(err):
+		slog.Warn("[acknowledgeHandler-handler] message left unacknowledged, its reply was not published",
+			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	case h.mode == AckManual:
+		slog.Warn("[acknowledgeHandler-handler] message not acknowledged by its handler",
+			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	case h.mode == AckOnSuccess && err != nil:
+		errC = acknowledger.Nack(true)
+	default:
+		errC = acknowledger.Ack()
+	}
 	if errC != nil {
 		slog.Error("[acknowledgeHandler-handler] failed to acknowledge message:",
 			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
@@ -72,3 +156,87 @@ func (h *acknowledgeHandler) Handle(
 	}
 	return resultMessage, err
 }
+
+// messageAcknowledger settles a message on its channel, at most once.
+type messageAcknowledger struct {
+	channel  ChannelMessageAcknowledgment
+	msg      *message.Message
+	mu       sync.Mutex
+	settled  bool
+	deferred bool
+}
+
+// Ack commits the message on its channel.
+//
+// Returns:
+//   - error: Error if the message is already settled or the commit fails
+func (a *messageAcknowledger) Ack() error {
+	return a.settle(func() error {
+		return a.channel.CommitMessage(a.msg)
+	})
+}
+
+// Nack settles the message as not processed. Channels which cannot settle a
+// message negatively leave it uncommitted for redelivery when requeue is
+// true, and commit it otherwise.
+//
+// Parameters:
+//   - requeue: true to redeliver the message, false to discard it
+//
+// Returns:
+//   - error: Error if the message is already settled or the settlement fails
+func (a *messageAcknowledger) Nack(requeue bool) error {
+	return a.settle(func() error {
+		if nackChannel, ok := a.channel.(ChannelMessageNegativeAcknowledgment); ok {
+			return nackChannel.NackMessage(a.msg, requeue)
+		}
+		if requeue {
+			return nil
+		}
+		return a.channel.CommitMessage(a.msg)
+	})
+}
+
+// Reject discards the message without requeueing it.
+//
+// Returns:
+//   - error: Error if the message is already settled or the settlement fails
+func (a *messageAcknowledger) Reject() error {
+	return a.Nack(false)
+}
+
+// Defer keeps the message unsettled when its handling returns, leaving its
+// settlement to a later Ack, Nack or Reject.
+func (a *messageAcknowledger) Defer() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.deferred = true
+}
+
+// settle runs the settlement once.
+func (a *messageAcknowledger) settle(settlement func() error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.settled {
+		return fmt.Errorf(
+			"[acknowledgeHandler-handler] message %s already settled",
+			a.msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	}
+	a.settled = true
+	return settlement()
+}
+
+// isSettled reports whether the message was settled.
+func (a *messageAcknowledger) isSettled() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.settled
+}
+
+// isDeferred reports whether the settlement of the message was deferred.
+func (a *messageAcknowledger) isDeferred() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.deferred
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
@@ -255,3 +256,175 @@ func TestAcknowledgeHandler_Handle(t *testing.T) {
 		}
 	})
 }
+
+// mockNackChannel records the negative acknowledgments of the messages.
+type mockNackChannel struct {
+	mockChannelMessageAcknowledgment
+	nacked  bool
+	requeue bool
+}
+
+func (m *mockNackChannel) NackMessage(msg *message.Message, requeue bool) error {
+	m.nacked = true
+	m.requeue = requeue
+	return nil
+}
+
+// settlingMessageHandler settles the message through the context acknowledger.
+type settlingMessageHandler struct {
+	settle func(message.Acknowledger) error
+}
+
+func (m *settlingMessageHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	acknowledger, ok := message.AcknowledgerFromContext(ctx)
+	if !ok {
+		return nil, errors.New("acknowledger not found")
+	}
+	return msg, m.settle(acknowledger)
+}
+
+func TestAcknowledgeHandler_WithAckMode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should requeue failed messages on success mode", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockNackChannel{}
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			&mockAcknowledgeMessageHandler{handleError: errors.New("boom")},
+		).WithAckMode(handler.AckOnSuccess)
+
+		ackHandler.Handle(ctx, message.NewMessageBuilder().Build())
+		if channel.committed {
+			t.Error("expected failed message not committed")
+		}
+		if !channel.nacked || !channel.requeue {
+			t.Error("expected failed message requeued")
+		}
+	})
+
+	t.Run("should commit successful messages on success mode", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockNackChannel{}
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			&mockAcknowledgeMessageHandler{},
+		).WithAckMode(handler.AckOnSuccess)
+
+		ackHandler.Handle(ctx, message.NewMessageBuilder().Build())
+		if !channel.committed {
+			t.Error("expected successful message committed")
+		}
+	})
+
+	t.Run("should leave acknowledgment to the handler on manual mode", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockNackChannel{}
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			&mockAcknowledgeMessageHandler{},
+		).WithAckMode(handler.AckManual)
+
+		ackHandler.Handle(ctx, message.NewMessageBuilder().Build())
+		if channel.committed || channel.nacked {
+			t.Error("expected message not settled")
+		}
+	})
+
+	t.Run("should leave messages whose reply was not published unacknowledged", func(t *testing.T) {
+		t.Parallel()
+		replyContainer := container.NewGenericContainer[any, any]()
+		replyContainer.Set("replies", &replyPublisherMock{err: errors.New("broker unavailable")})
+		channel := &mockNackChannel{}
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			handler.NewSendReplyToHandler(&replyTohandlerMock{}, replyContainer).WithAtomicReply(),
+		)
+
+		_, err := ackHandler.Handle(ctx, message.NewMessageBuilder().WithReplyTo("replies").Build())
+		if !handler.IsReplyNotPublished(err) {
+			t.Fatalf("expected reply not published error, got %v", err)
+		}
+		if channel.committed || channel.nacked {
+			t.Error("expected message not settled")
+		}
+	})
+
+	t.Run("should settle once through the context acknowledger", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockNackChannel{}
+		msg := message.NewMessageBuilder().Build()
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			&settlingMessageHandler{settle: func(a message.Acknowledger) error {
+				if err := a.Reject(); err != nil {
+					return err
+				}
+				if a.Ack() == nil {
+					return errors.New("expected second settlement to fail")
+				}
+				return nil
+			}},
+		)
+
+		if _, err := ackHandler.Handle(ctx, msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !channel.nacked || channel.requeue {
+			t.Error("expected message rejected")
+		}
+		if channel.committed {
+			t.Error("expected rejected message not committed again")
+		}
+		if _, ok := message.AcknowledgerFromContext(msg.GetContext()); !ok {
+			t.Error("expected acknowledger carried by the message context")
+		}
+	})
+
+	t.Run("should commit rejected messages of channels without nack", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockChannelMessageAcknowledgment{}
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			&settlingMessageHandler{settle: func(a message.Acknowledger) error {
+				return a.Reject()
+			}},
+		).WithAckMode(handler.AckManual)
+
+		ackHandler.Handle(ctx, message.NewMessageBuilder().Build())
+		if !channel.committed {
+			t.Error("expected rejected message committed")
+		}
+	})
+
+	t.Run("should leave deferred messages unsettled until their acknowledgment", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockChannelMessageAcknowledgment{}
+		var deferred message.Acknowledger
+		ackHandler := handler.NewAcknowledgeHandler(
+			channel,
+			&settlingMessageHandler{settle: func(a message.Acknowledger) error {
+				deferrable, ok := a.(message.DeferrableAcknowledger)
+				if !ok {
+					return errors.New("expected a deferrable acknowledger")
+				}
+				deferrable.Defer()
+				deferred = deferrable
+				return nil
+			}},
+		)
+
+		if _, err := ackHandler.Handle(ctx, message.NewMessageBuilder().Build()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if channel.committed {
+			t.Fatal("expected deferred message not committed")
+		}
+		if err := deferred.Ack(); err != nil || !channel.committed {
+			t.Errorf("expected deferred message committed on its acknowledgment, got %v", err)
+		}
+	})
+}
@@ -10,12 +10,19 @@
 // - Action routing and processing
 // - Reply channel integration
 // - Error handling and response management
+// - Handled message and its header available in the handler context
+// - Optional access to the full handled message through MessageAware
+// - Plain functions as handlers through ActionHandlerFunc
+// - Handlers created at build time with dependencies from the container
+// - Handler panics recovered as errors carrying their stack trace
 package handler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime/debug"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
@@ -27,28 +34,114 @@ type Action interface {
 	Name() string
 }
 
+// ActionName resolves the name of the action type T. T may be a value type
+// (Name declared with a value receiver) or a pointer type (Name declared with
+// either receiver); for pointer types, Name is called on a zero value
+// instance instead of a nil pointer.
+//
+// Returns:
+//   - string: the action name
+//   - error: error if T is an interface type, Name panics or returns an empty
+//     name
+func ActionName[T Action]() (name string, err error) {
+	actionType := reflect.TypeFor[T]()
+	if actionType.Kind() == reflect.Interface {
+		return "", fmt.Errorf(
+			"[action-handler] action type %v must be a concrete type",
+			actionType,
+		)
+	}
+
+	var action T
+	if actionType.Kind() == reflect.Pointer {
+		action = reflect.New(actionType.Elem()).Interface().(T)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf(
+				"[action-handler] cannot resolve name of action %v: %v",
+				actionType,
+				r,
+			)
+		}
+	}()
+
+	name = action.Name()
+	if name == "" {
+		return "", fmt.Errorf(
+			"[action-handler] action %v returned an empty name",
+			actionType,
+		)
+	}
+	return name, nil
+}
+
 // ActionHandler defines the contract for handling specific action types with
 // generic input and output types.
-type ActionHandler[T Action, U any] interface {
+type ActionHandler[T any, U any] interface {
 	Handle(ctx context.Context, payload T) (U, error)
 }
 
+// ActionHandlerFunc adapts a function to the ActionHandler contract, so plain
+// functions can handle actions without declaring a handler type.
+type ActionHandlerFunc[T any, U any] func(ctx context.Context, payload T) (U, error)
+
+// Handle calls the function with the action payload.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - payload: the action payload
+//
+// Returns:
+//   - U: the result of the function
+//   - error: the error returned by the function
+func (f ActionHandlerFunc[T, U]) Handle(ctx context.Context, payload T) (U, error) {
+	return f(ctx, payload)
+}
+
 // ActionHandleActivatorBuilder provides a builder pattern for creating action
 // handler activators with specific configurations.
-type ActionHandleActivatorBuilder[TInput Action, TOutput any] struct {
+type ActionHandleActivatorBuilder[TInput any, TOutput any] struct {
 	referenceName string
 	handler       ActionHandler[TInput, TOutput]
+	factory       ActionHandlerFactory[TInput, TOutput]
 }
 
+// ActionHandlerFactory creates an action handler at build time, resolving its
+// dependencies from the dependency container.
+type ActionHandlerFactory[T any, U any] func(
+	container container.Container[any, any],
+) (ActionHandler[T, U], error)
+
+// MessageHeaderAccessor is optionally implemented by action handlers to
+// receive the header of the handled message before Handle is called.
+//
+// Deprecated: the handler instance is shared by the processors of a consumer,
+// so the header set may belong to another message; read it from the handler
+// context with gomes.HeadersFromContext instead.
 type MessageHeaderAccessor interface {
 	SetMessageHeader(header message.Header)
 }
 
+// MessageAware is optionally implemented by action handlers and event
+// subscribers to receive the full handled message (headers, raw message from
+// the external source and context) before Handle is called, keeping the
+// simple Handle(ctx, action) signature.
+//
+// Deprecated: the handler instance is shared by the processors of a consumer,
+// so the message set may belong to another message; read it from the handler
+// context with gomes.RawMessageFromContext and the other context accessors
+// instead.
+type MessageAware interface {
+	SetMessage(msg *message.Message)
+}
+
 // ActionHandleActivator processes actions by delegating to the appropriate
 // handler and managing the response through reply channels.
 type ActionHandleActivator[
 	THandler ActionHandler[TInput, TOutput],
-	TInput Action,
+	TInput any,
 	TOutput any,
 ] struct {
 	handler THandler
@@ -63,7 +156,7 @@ type ActionHandleActivator[
 //
 // Returns:
 //   - *ActionHandleActivatorBuilder[TInput, TOutput]: configured builder instance
-func NewActionHandleActivatorBuilder[TInput Action, TOutput any](
+func NewActionHandleActivatorBuilder[TInput any, TOutput any](
 	referenceName string,
 	handler ActionHandler[TInput, TOutput],
 ) *ActionHandleActivatorBuilder[TInput, TOutput] {
@@ -73,6 +166,26 @@ func NewActionHandleActivatorBuilder[TInput Action, TOutput any](
 	}
 }
 
+// NewActionHandleActivatorBuilderFromFactory creates a new action handler
+// activator builder whose handler is created by the factory when the
+// activator is built.
+//
+// Parameters:
+//   - referenceName: unique identifier for the activator
+//   - factory: creates the action handler from the dependency container
+//
+// Returns:
+//   - *ActionHandleActivatorBuilder[TInput, TOutput]: configured builder instance
+func NewActionHandleActivatorBuilderFromFactory[TInput any, TOutput any](
+	referenceName string,
+	factory ActionHandlerFactory[TInput, TOutput],
+) *ActionHandleActivatorBuilder[TInput, TOutput] {
+	return &ActionHandleActivatorBuilder[TInput, TOutput]{
+		referenceName: referenceName,
+		factory:       factory,
+	}
+}
+
 // NewActionHandlerActivator creates a new action handler activator instance.
 //
 // Parameters:
@@ -80,7 +193,7 @@ func NewActionHandleActivatorBuilder[TInput Action, TOutput any](
 //
 // Returns:
 //   - *ActionHandleActivator[THandler, TInput, TOutput]: configured activator
-func NewActionHandlerActivator[THandler ActionHandler[TInput, TOutput], TInput Action, TOutput any](
+func NewActionHandlerActivator[THandler ActionHandler[TInput, TOutput], TInput any, TOutput any](
 	handler THandler,
 ) *ActionHandleActivator[THandler, TInput, TOutput] {
 	return &ActionHandleActivator[THandler, TInput, TOutput]{
@@ -103,11 +216,31 @@ func (b *ActionHandleActivatorBuilder[TInput, TOutput]) ReferenceName() string {
 //
 // Returns:
 //   - message.PublisherChannel: configured publisher channel for the activator
-//   - error: error if construction fails
+//   - error: error if construction fails or the factory fails to create the
+//     handler
 func (b *ActionHandleActivatorBuilder[TInput, TOutput]) Build(
 	container container.Container[any, any],
 ) (message.PublisherChannel, error) {
-	handlerActivator := NewActionHandlerActivator(b.handler)
+	actionHandler := b.handler
+	if b.factory != nil {
+		var err error
+		actionHandler, err = b.factory(container)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[action-handler] factory of %s failed: %w",
+				b.referenceName,
+				err,
+			)
+		}
+		if actionHandler == nil {
+			return nil, fmt.Errorf(
+				"[action-handler] factory of %s returned a nil handler",
+				b.referenceName,
+			)
+		}
+	}
+
+	handlerActivator := NewActionHandlerActivator(actionHandler)
 	chn := channel.NewPointToPointChannel(b.referenceName)
 	chn.Subscribe(func(msg *message.Message) {
 		handlerActivator.Handle(msg.GetContext(), msg)
@@ -144,20 +277,22 @@ func (c *ActionHandleActivator[THandler, TInput, TOutput]) Handle(
 	}
 
 	if !ok {
-		payload, ok := msg.GetPayload().([]byte)
-		if !ok {
+		decoded, errUnmsl := decodePayload(msg.GetPayload(), &action)
+		if !decoded {
 			err := fmt.Errorf(
-				"[action-handler] cannot process action: incorrect contract data",
+				"[action-handler] cannot process action: %w, incorrect contract data",
+				message.ErrTranslation,
 			)
 			resultMessageBuilder.WithPayload(err)
 			c.sendResponseToReplyChannel(ctx, msg, resultMessageBuilder.Build())
 			return nil, err
 		}
 
-		errUnmsl := json.Unmarshal(payload, &action)
 		if errUnmsl != nil {
 			err := fmt.Errorf(
-				"[action-handler] cannot process action: %v", errUnmsl.Error(),
+				"[action-handler] cannot process action: %w: %w",
+				message.ErrTranslation,
+				errUnmsl,
 			)
 			resultMessageBuilder.WithPayload(err)
 			c.sendResponseToReplyChannel(ctx, msg, resultMessageBuilder.Build())
@@ -169,6 +304,11 @@ func (c *ActionHandleActivator[THandler, TInput, TOutput]) Handle(
 		accessor.SetMessageHeader(msg.GetHeader())
 	}
 
+	if aware, ok := any(c.handler).(MessageAware); ok {
+		aware.SetMessage(msg)
+	}
+
+	ctx = message.ContextWithMessage(ctx, msg)
 	output, err := c.executeAction(ctx, action)
 
 	if err != nil {
@@ -183,7 +323,9 @@ func (c *ActionHandleActivator[THandler, TInput, TOutput]) Handle(
 	return resultMessage, err
 }
 
-// executeAction executes the action using the configured handler.
+// executeAction executes the action using the configured handler. A panic of
+// the handler is recovered as a *message.PanicError, so the message follows
+// the retry and dead letter flow instead of crashing the process.
 //
 // Parameters:
 //   - ctx: context for timeout/cancellation control
@@ -191,13 +333,29 @@ func (c *ActionHandleActivator[THandler, TInput, TOutput]) Handle(
 //
 // Returns:
 //   - TOutput: the result of the action execution
-//   - error: error if execution fails
+//   - error: error if execution fails or the handler panics
 func (c *ActionHandleActivator[THandler, TInput, TOutput]) executeAction(
 	ctx context.Context,
 	args TInput,
-) (TOutput, error) {
-	result, err := c.handler.Handle(ctx, args)
-	return result, err
+) (result TOutput, err error) {
+	defer recoverPanic(ctx, fmt.Sprintf("%T", c.handler), &err)
+	return c.handler.Handle(ctx, args)
+}
+
+// recoverPanic recovers a panic of a handler, setting err to a
+// *message.PanicError with the stack trace of the panic. It must be deferred.
+func recoverPanic(ctx context.Context, handlerName string, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	panicErr := message.NewPanicError(recovered, debug.Stack())
+	slog.ErrorContext(ctx, "[handler] handler panicked",
+		"handler", handlerName,
+		"reason", panicErr.Error(),
+		"stack", panicErr.Stack,
+	)
+	*err = panicErr
 }
 
 func (c *ActionHandleActivator[THandler, TInput, TOutput]) sendResponseToReplyChannel(
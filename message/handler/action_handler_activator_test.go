@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -29,6 +30,13 @@ func (h *mockActionHandler) Handle(ctx context.Context, action *mockAction) (any
 	if h.result == "failure" {
 		return nil, fmt.Errorf("handler error")
 	}
+	if h.result == "header" {
+		header, ok := message.HeaderFromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("header not found in context")
+		}
+		return header.Get(message.HeaderCorrelationId), nil
+	}
 	return h.result, nil
 }
 
@@ -58,6 +66,54 @@ func TestActionHandleActivatorBuilder_Build(t *testing.T) {
 	}
 }
 
+func TestActionHandleActivatorBuilderFromFactory_Build(t *testing.T) {
+	t.Parallel()
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("result", "from-container")
+
+	t.Run("should create the handler from the container", func(t *testing.T) {
+		t.Parallel()
+		builder := handler.NewActionHandleActivatorBuilderFromFactory("ref",
+			func(c container.Container[any, any]) (handler.ActionHandler[*mockAction, any], error) {
+				result, err := c.Get("result")
+				if err != nil {
+					return nil, err
+				}
+				return &mockActionHandler{result: result.(string)}, nil
+			},
+		)
+		chn, err := builder.Build(cont)
+		if err != nil || chn == nil {
+			t.Errorf("Expected channel instance, got %v (%v)", chn, err)
+		}
+	})
+
+	t.Run("should fail when the factory fails", func(t *testing.T) {
+		t.Parallel()
+		builder := handler.NewActionHandleActivatorBuilderFromFactory("ref",
+			func(c container.Container[any, any]) (handler.ActionHandler[*mockAction, any], error) {
+				_, err := c.Get("missing")
+				return nil, err
+			},
+		)
+		if _, err := builder.Build(cont); err == nil {
+			t.Error("Expected factory error, got nil")
+		}
+	})
+
+	t.Run("should fail when the factory returns no handler", func(t *testing.T) {
+		t.Parallel()
+		builder := handler.NewActionHandleActivatorBuilderFromFactory("ref",
+			func(c container.Container[any, any]) (handler.ActionHandler[*mockAction, any], error) {
+				return nil, nil
+			},
+		)
+		if _, err := builder.Build(cont); err == nil {
+			t.Error("Expected nil handler error, got nil")
+		}
+	})
+}
+
 func TestActionHandleActivator_Handle(t *testing.T) {
 	cases := []struct {
 		description         string
@@ -113,3 +169,182 @@ func TestActionHandleActivator_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestActionHandleActivator_HandleHeaderInContext(t *testing.T) {
+	t.Parallel()
+	activator := handler.NewActionHandlerActivator(&mockActionHandler{result: "header"})
+	msg := message.NewMessageBuilder().
+		WithPayload(&mockAction{name: "test"}).
+		WithCorrelationId("corr-ctx").
+		WithInternalReplyChannel(channel.NewPointToPointChannel("reply-header")).
+		Build()
+	go msg.GetInternalReplyChannel().(*channel.PointToPointChannel).Receive(context.Background())
+
+	result, err := activator.Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if result.GetPayload() != "corr-ctx" {
+		t.Errorf("Expected correlation id from context, got %v", result.GetPayload())
+	}
+}
+
+type messageAwareActionHandler struct {
+	received *message.Message
+}
+
+func (h *messageAwareActionHandler) SetMessage(msg *message.Message) {
+	h.received = msg
+}
+
+func (h *messageAwareActionHandler) Handle(ctx context.Context, action *mockAction) (any, error) {
+	if h.received == nil {
+		return nil, fmt.Errorf("message not received")
+	}
+	return h.received.GetRawMessage(), nil
+}
+
+func TestActionHandleActivator_HandleMessageAware(t *testing.T) {
+	t.Parallel()
+	awareHandler := &messageAwareActionHandler{}
+	activator := handler.NewActionHandlerActivator(awareHandler)
+	msg := message.NewMessageBuilder().
+		WithPayload(&mockAction{name: "test"}).
+		WithRawMessage("raw-message").
+		WithInternalReplyChannel(channel.NewPointToPointChannel("reply-aware")).
+		Build()
+	go msg.GetInternalReplyChannel().(*channel.PointToPointChannel).Receive(context.Background())
+
+	result, err := activator.Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if awareHandler.received != msg || result.GetPayload() != "raw-message" {
+		t.Errorf("Expected handler to receive the handled message, got %v", result.GetPayload())
+	}
+}
+
+func TestActionHandleActivator_HandleMessageInContext(t *testing.T) {
+	t.Parallel()
+	activator := handler.NewActionHandlerActivator(handler.ActionHandlerFunc[*mockAction, any](
+		func(ctx context.Context, action *mockAction) (any, error) {
+			msg, ok := message.MessageFromContext(ctx)
+			if !ok {
+				return nil, fmt.Errorf("message not found in context")
+			}
+			return msg.GetRawMessage(), nil
+		},
+	))
+	msg := message.NewMessageBuilder().
+		WithPayload(&mockAction{name: "test"}).
+		WithRawMessage("raw-message").
+		WithInternalReplyChannel(channel.NewPointToPointChannel("reply-context")).
+		Build()
+	go msg.GetInternalReplyChannel().(*channel.PointToPointChannel).Receive(context.Background())
+
+	result, err := activator.Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if result.GetPayload() != "raw-message" {
+		t.Errorf("Expected raw message from context, got %v", result.GetPayload())
+	}
+}
+
+func TestActionHandleActivator_HandlePanic(t *testing.T) {
+	t.Parallel()
+	activator := handler.NewActionHandlerActivator(handler.ActionHandlerFunc[*mockAction, any](
+		func(ctx context.Context, action *mockAction) (any, error) {
+			panic("boom")
+		},
+	))
+	msg := message.NewMessageBuilder().
+		WithPayload(&mockAction{name: "test"}).
+		WithInternalReplyChannel(channel.NewPointToPointChannel("reply-panic")).
+		Build()
+	go msg.GetInternalReplyChannel().(*channel.PointToPointChannel).Receive(context.Background())
+
+	_, err := activator.Handle(context.Background(), msg)
+	if !errors.Is(err, message.ErrPanic) {
+		t.Fatalf("Expected panic error, got %v", err)
+	}
+	var panicErr *message.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" || panicErr.Stack == "" {
+		t.Errorf("Expected recovered value and stack trace, got %#v", panicErr)
+	}
+}
+
+type createUserCommand struct {
+	Email string `json:"email"`
+}
+
+func TestActionHandleActivator_HandleFunc(t *testing.T) {
+	t.Parallel()
+	activator := handler.NewActionHandlerActivator(handler.ActionHandlerFunc[createUserCommand, string](
+		func(ctx context.Context, cmd createUserCommand) (string, error) {
+			return "created " + cmd.Email, nil
+		},
+	))
+
+	for description, payload := range map[string]any{
+		"in-process payload": createUserCommand{Email: "ana@example.com"},
+		"external payload":   []byte(`{"email":"ana@example.com"}`),
+	} {
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			replyChannel := channel.NewPointToPointChannel("reply-func-" + description)
+			go replyChannel.Receive(context.Background())
+			msg := message.NewMessageBuilder().
+				WithPayload(payload).
+				WithInternalReplyChannel(replyChannel).
+				Build()
+
+			result, err := activator.Handle(context.Background(), msg)
+			if err != nil {
+				t.Fatalf("Expected success, got error: %v", err)
+			}
+			if result.GetPayload() != "created ana@example.com" {
+				t.Errorf("Expected function result, got %v", result.GetPayload())
+			}
+		})
+	}
+}
+
+type pointerNamedAction struct{ name string }
+
+func (a *pointerNamedAction) Name() string { return "pointer" + a.name }
+
+type valueNamedAction struct{}
+
+func (a valueNamedAction) Name() string { return "value" }
+
+type emptyNamedAction struct{}
+
+func (a emptyNamedAction) Name() string { return "" }
+
+type panicNamedAction struct{ inner *pointerNamedAction }
+
+func (a panicNamedAction) Name() string { return a.inner.name }
+
+func TestActionName(t *testing.T) {
+	t.Parallel()
+
+	if name, err := handler.ActionName[valueNamedAction](); err != nil || name != "value" {
+		t.Errorf("expected 'value', got '%s' (%v)", name, err)
+	}
+	if name, err := handler.ActionName[*valueNamedAction](); err != nil || name != "value" {
+		t.Errorf("expected 'value', got '%s' (%v)", name, err)
+	}
+	if name, err := handler.ActionName[*pointerNamedAction](); err != nil || name != "pointer" {
+		t.Errorf("expected 'pointer', got '%s' (%v)", name, err)
+	}
+	if _, err := handler.ActionName[handler.Action](); err == nil {
+		t.Error("expected error for interface action type")
+	}
+	if _, err := handler.ActionName[emptyNamedAction](); err == nil {
+		t.Error("expected error for empty action name")
+	}
+	if _, err := handler.ActionName[panicNamedAction](); err == nil {
+		t.Error("expected error for panicking action name")
+	}
+}
@@ -0,0 +1,353 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The CircuitBreaker implementation supports:
+// - Failure rate tracking over a window of the latest processed messages
+// - Open state pausing the processing for a cool-down period
+// - Optional parking channel receiving the messages while the circuit is open
+// - Half-open state processing trial messages before closing the circuit
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// ErrCircuitOpen is returned when a message is not processed because the
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("[circuit-breaker] circuit is open")
+
+// CircuitState represents the state of a circuit breaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	CircuitClosed   CircuitState = iota // Messages are processed
+	CircuitOpen                         // Messages are held for the cool-down
+	CircuitHalfOpen                     // Trial messages are processed
+)
+
+// String returns the string representation of a CircuitState.
+//
+// Returns:
+//   - string: the state name
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerConfig configures a circuit breaker.
+type CircuitBreakerConfig struct {
+	// FailureRateThreshold is the failure rate opening the circuit, greater
+	// than 0 and up to 1. Defaults to 0.5.
+	FailureRateThreshold float64
+	// WindowSize is the amount of latest processed messages the failure rate
+	// is calculated from. Defaults to 20.
+	WindowSize int
+	// MinimumRequests is the amount of processed messages required before the
+	// circuit can open. Defaults to WindowSize.
+	MinimumRequests int
+	// CoolDown is how long the circuit stays open before half-opening.
+	// Defaults to 30 seconds.
+	CoolDown time.Duration
+	// HalfOpenTrials is the amount of successful trial messages closing the
+	// circuit. Defaults to 1.
+	HalfOpenTrials int
+	// ParkingChannel, when set, receives the messages arriving while the
+	// circuit is open, instead of holding them until it half-opens.
+	ParkingChannel string
+}
+
+// CircuitBreaker tracks the processing failures of a consumer, opening the
+// circuit when the failure rate exceeds the threshold so a failing dependency
+// gets a cool-down period instead of a burst of retries.
+type CircuitBreaker struct {
+	config         CircuitBreakerConfig
+	mu             sync.Mutex
+	state          CircuitState
+	results        []bool
+	next           int
+	count          int
+	failures       int
+	openedAt       time.Time
+	trials         int
+	trialSuccesses int
+	stateChanged   chan struct{}
+}
+
+// NewCircuitBreaker creates a new closed circuit breaker.
+//
+// Parameters:
+//   - config: the circuit breaker configuration
+//
+// Returns:
+//   - *CircuitBreaker: configured circuit breaker
+//   - error: error if the failure rate threshold is not within (0, 1]
+func NewCircuitBreaker(config CircuitBreakerConfig) (*CircuitBreaker, error) {
+	if config.FailureRateThreshold == 0 {
+		config.FailureRateThreshold = 0.5
+	}
+	if !(config.FailureRateThreshold > 0 && config.FailureRateThreshold <= 1) {
+		return nil, fmt.Errorf(
+			"[circuit-breaker] failure rate threshold must be greater than 0 and up to 1, got %v",
+			config.FailureRateThreshold,
+		)
+	}
+	if config.WindowSize <= 0 {
+		config.WindowSize = 20
+	}
+	if config.MinimumRequests <= 0 || config.MinimumRequests > config.WindowSize {
+		config.MinimumRequests = config.WindowSize
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenTrials <= 0 {
+		config.HalfOpenTrials = 1
+	}
+	return &CircuitBreaker{
+		config:       config,
+		results:      make([]bool, config.WindowSize),
+		stateChanged: make(chan struct{}),
+	}, nil
+}
+
+// ParkingChannel returns the channel receiving the messages while the circuit
+// is open.
+//
+// Returns:
+//   - string: the parking channel name, empty when messages are held
+func (c *CircuitBreaker) ParkingChannel() string {
+	return c.config.ParkingChannel
+}
+
+// State returns the current circuit state.
+//
+// Returns:
+//   - CircuitState: the circuit state
+func (c *CircuitBreaker) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.halfOpenIfCooledDown()
+	return c.state
+}
+
+// WaitUntilClosed blocks while the circuit is open, returning once it
+// half-opens or closes.
+//
+// Parameters:
+//   - ctx: context for cancellation control
+//
+// Returns:
+//   - error: the context error if it is done while the circuit is open
+func (c *CircuitBreaker) WaitUntilClosed(ctx context.Context) error {
+	for {
+		remaining := c.coolDownRemaining()
+		if remaining == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// coolDownRemaining returns how long until the open circuit half-opens, or
+// zero when it is not open.
+func (c *CircuitBreaker) coolDownRemaining() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.halfOpenIfCooledDown()
+	if c.state != CircuitOpen {
+		return 0
+	}
+	return c.config.CoolDown - time.Since(c.openedAt)
+}
+
+// acquire reserves the processing of a message.
+//
+// Returns:
+//   - bool: true if the message can be processed
+//   - bool: true if the message is a half-open trial
+//   - <-chan struct{}: closed on the next state change when the message
+//     cannot be processed
+func (c *CircuitBreaker) acquire() (bool, bool, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.halfOpenIfCooledDown()
+
+	switch c.state {
+	case CircuitClosed:
+		return true, false, nil
+	case CircuitHalfOpen:
+		if c.trials < c.config.HalfOpenTrials {
+			c.trials++
+			return true, true, nil
+		}
+	}
+	return false, false, c.stateChanged
+}
+
+// record records the processing result of an acquired message.
+func (c *CircuitBreaker) record(trial bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CircuitHalfOpen && trial {
+		if err != nil {
+			c.transition(CircuitOpen)
+			return
+		}
+		c.trialSuccesses++
+		if c.trialSuccesses >= c.config.HalfOpenTrials {
+			c.transition(CircuitClosed)
+		}
+		return
+	}
+	if c.state != CircuitClosed {
+		return
+	}
+
+	if c.count == len(c.results) && c.results[c.next] {
+		c.failures--
+	}
+	c.results[c.next] = err != nil
+	if err != nil {
+		c.failures++
+	}
+	c.next = (c.next + 1) % len(c.results)
+	if c.count < len(c.results) {
+		c.count++
+	}
+
+	if c.count >= c.config.MinimumRequests &&
+		float64(c.failures)/float64(c.count) >= c.config.FailureRateThreshold {
+		c.transition(CircuitOpen)
+	}
+}
+
+// halfOpenIfCooledDown half-opens the circuit once the cool-down elapsed. The
+// caller holds the lock.
+func (c *CircuitBreaker) halfOpenIfCooledDown() {
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= c.config.CoolDown {
+		c.transition(CircuitHalfOpen)
+	}
+}
+
+// transition changes the circuit state, resetting its counters. The caller
+// holds the lock.
+func (c *CircuitBreaker) transition(state CircuitState) {
+	slog.Warn("[circuit-breaker] circuit state changed",
+		"from", c.state.String(),
+		"to", state.String(),
+	)
+	c.state = state
+	c.trials = 0
+	c.trialSuccesses = 0
+	if state == CircuitOpen {
+		c.openedAt = time.Now()
+	}
+	if state == CircuitClosed {
+		c.count, c.next, c.failures = 0, 0, 0
+	}
+	close(c.stateChanged)
+	c.stateChanged = make(chan struct{})
+}
+
+// circuitBreakerHandler processes messages through a circuit breaker.
+type circuitBreakerHandler struct {
+	breaker        *CircuitBreaker
+	handler        message.MessageHandler
+	parkingChannel message.PublisherChannel
+}
+
+// NewCircuitBreakerHandler creates a new circuit breaker handler that wraps
+// an existing message handler. While the circuit is open, messages are sent
+// to the parking channel, when given, or held until the circuit half-opens.
+//
+// Parameters:
+//   - breaker: The circuit breaker tracking the failures
+//   - handler: The underlying message handler to wrap
+//   - parkingChannel: Optional channel receiving the messages while the
+//     circuit is open
+//
+// Returns:
+//   - *circuitBreakerHandler: Configured circuit breaker handler instance
+func NewCircuitBreakerHandler(
+	breaker *CircuitBreaker,
+	handler message.MessageHandler,
+	parkingChannel message.PublisherChannel,
+) *circuitBreakerHandler {
+	return &circuitBreakerHandler{
+		breaker:        breaker,
+		handler:        handler,
+		parkingChannel: parkingChannel,
+	}
+}
+
+// Handle processes a message through the wrapped handler when the circuit
+// allows it, recording the processing result.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to process
+//
+// Returns:
+//   - *message.Message: The resulting message from processing
+//   - error: Error if processing fails, or ErrCircuitOpen if the context is
+//     done while the message is held
+func (h *circuitBreakerHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	for {
+		allowed, trial, stateChanged := h.breaker.acquire()
+		if allowed {
+			resultMessage, err := h.handler.Handle(ctx, msg)
+			h.breaker.record(trial, err)
+			return resultMessage, err
+		}
+
+		if h.parkingChannel != nil {
+			if err := h.parkingChannel.Send(ctx, msg); err != nil {
+				return nil, fmt.Errorf(
+					"[circuit-breaker] failed to park message: %w",
+					err,
+				)
+			}
+			return nil, nil
+		}
+
+		// While half-open with all trials in flight, only a state change
+		// releases the message.
+		var coolDown <-chan time.Time
+		if remaining := h.breaker.coolDownRemaining(); remaining > 0 {
+			timer := time.NewTimer(remaining)
+			defer timer.Stop()
+			coolDown = timer.C
+		}
+		select {
+		case <-stateChanged:
+		case <-coolDown:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrCircuitOpen, ctx.Err())
+		}
+	}
+}
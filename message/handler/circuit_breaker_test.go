@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func newCircuitBreaker(t *testing.T, config handler.CircuitBreakerConfig) *handler.CircuitBreaker {
+	t.Helper()
+	breaker, err := handler.NewCircuitBreaker(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return breaker
+}
+
+func TestNewCircuitBreaker_FailureRateThreshold(t *testing.T) {
+	t.Parallel()
+	breaker := newCircuitBreaker(t, handler.CircuitBreakerConfig{WindowSize: 2})
+	inner := &countingMessageHandler{err: errors.New("boom")}
+	cb := handler.NewCircuitBreakerHandler(breaker, inner, nil)
+	msg := message.NewMessageBuilder().Build()
+
+	cb.Handle(context.Background(), msg)
+	inner.err = nil
+	cb.Handle(context.Background(), msg)
+	if breaker.State() != handler.CircuitOpen {
+		t.Errorf("expected the default threshold of 0.5 to open the circuit, got %s", breaker.State())
+	}
+
+	for _, threshold := range []float64{-0.1, 1.5} {
+		_, err := handler.NewCircuitBreaker(handler.CircuitBreakerConfig{
+			FailureRateThreshold: threshold,
+		})
+		if err == nil {
+			t.Errorf("expected error for threshold %v", threshold)
+		}
+	}
+}
+
+func TestCircuitBreakerHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+	config := handler.CircuitBreakerConfig{
+		FailureRateThreshold: 0.5,
+		WindowSize:           4,
+		MinimumRequests:      2,
+		CoolDown:             50 * time.Millisecond,
+	}
+
+	t.Run("should open after the failure rate threshold", func(t *testing.T) {
+		t.Parallel()
+		breaker := newCircuitBreaker(t, config)
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		cb := handler.NewCircuitBreakerHandler(breaker, inner, nil)
+		msg := message.NewMessageBuilder().Build()
+
+		cb.Handle(ctx, msg)
+		if breaker.State() != handler.CircuitClosed {
+			t.Fatalf("expected closed circuit before minimum requests, got %s", breaker.State())
+		}
+		cb.Handle(ctx, msg)
+		if breaker.State() != handler.CircuitOpen {
+			t.Fatalf("expected open circuit, got %s", breaker.State())
+		}
+	})
+
+	t.Run("should park messages while open", func(t *testing.T) {
+		t.Parallel()
+		parking := &mockPublisherChannel{}
+		breaker := newCircuitBreaker(t, config)
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		cb := handler.NewCircuitBreakerHandler(breaker, inner, parking)
+		msg := message.NewMessageBuilder().Build()
+
+		cb.Handle(ctx, msg)
+		cb.Handle(ctx, msg)
+		result, err := cb.Handle(ctx, msg)
+		if err != nil || result != nil {
+			t.Fatalf("expected parked message, got %v, %v", result, err)
+		}
+		if parking.sentMsg != msg {
+			t.Error("expected message sent to the parking channel")
+		}
+		if inner.calls != 2 {
+			t.Errorf("expected 2 processed messages, got %d", inner.calls)
+		}
+	})
+
+	t.Run("should return ErrCircuitOpen when the held message context is done", func(t *testing.T) {
+		t.Parallel()
+		breaker := newCircuitBreaker(t, handler.CircuitBreakerConfig{
+			FailureRateThreshold: 0.5,
+			WindowSize:           1,
+			CoolDown:             time.Minute,
+		})
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		cb := handler.NewCircuitBreakerHandler(breaker, inner, nil)
+		msg := message.NewMessageBuilder().Build()
+		cb.Handle(ctx, msg)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err := cb.Handle(waitCtx, msg)
+		if !errors.Is(err, handler.ErrCircuitOpen) {
+			t.Errorf("expected ErrCircuitOpen, got %v", err)
+		}
+	})
+
+	t.Run("should close after successful half-open trials", func(t *testing.T) {
+		t.Parallel()
+		breaker := newCircuitBreaker(t, config)
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		cb := handler.NewCircuitBreakerHandler(breaker, inner, nil)
+		msg := message.NewMessageBuilder().Build()
+		cb.Handle(ctx, msg)
+		cb.Handle(ctx, msg)
+
+		inner.err = nil
+		start := time.Now()
+		if _, err := cb.Handle(ctx, msg); err != nil {
+			t.Fatalf("expected held message processed, got %v", err)
+		}
+		if time.Since(start) < 40*time.Millisecond {
+			t.Error("expected message held for the cool-down")
+		}
+		if breaker.State() != handler.CircuitClosed {
+			t.Errorf("expected closed circuit, got %s", breaker.State())
+		}
+	})
+
+	t.Run("should reopen after a failed half-open trial", func(t *testing.T) {
+		t.Parallel()
+		breaker := newCircuitBreaker(t, config)
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		cb := handler.NewCircuitBreakerHandler(breaker, inner, nil)
+		msg := message.NewMessageBuilder().Build()
+		cb.Handle(ctx, msg)
+		cb.Handle(ctx, msg)
+
+		if err := breaker.WaitUntilClosed(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if breaker.State() != handler.CircuitHalfOpen {
+			t.Fatalf("expected half-open circuit, got %s", breaker.State())
+		}
+		cb.Handle(ctx, msg)
+		if breaker.State() != handler.CircuitOpen {
+			t.Errorf("expected open circuit, got %s", breaker.State())
+		}
+	})
+}
@@ -0,0 +1,235 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The ClaimCheck implementation supports:
+// - Claim Check pattern for payloads bigger than a threshold
+// - Pluggable blob stores (S3, GCS, filesystem)
+// - Outbound interceptor replacing large payloads with a reference header
+// - Inbound interceptor rehydrating the payload before the handler runs
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// BlobStore defines the contract for stores keeping claim-checked payloads.
+type BlobStore interface {
+	// Put stores a payload under the given key.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - key: The payload key
+	//   - data: The encoded payload
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	Put(ctx context.Context, key string, data []byte) error
+	// Get loads the payload stored under the given key.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - key: The payload key
+	//
+	// Returns:
+	//   - []byte: The encoded payload
+	//   - error: Error if the payload does not exist or the operation fails
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// fileBlobStore is a BlobStore keeping each payload in a file.
+type fileBlobStore struct {
+	directory string
+}
+
+// NewFileBlobStore creates a blob store keeping each payload in a file of the
+// given directory, e.g. a volume shared by producers and consumers.
+//
+// Parameters:
+//   - directory: The directory of the payload files
+//
+// Returns:
+//   - *fileBlobStore: Configured store instance
+func NewFileBlobStore(directory string) *fileBlobStore {
+	return &fileBlobStore{directory: directory}
+}
+
+// Put writes a payload to the file of its key.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - key: The payload key
+//   - data: The encoded payload
+//
+// Returns:
+//   - error: Error if the key is invalid or the file cannot be written
+func (s *fileBlobStore) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.directory, 0o755); err != nil {
+		return fmt.Errorf("[claim-check] failed to create %s: %w", s.directory, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("[claim-check] failed to write payload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get reads the payload file of a key.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - key: The payload key
+//
+// Returns:
+//   - []byte: The encoded payload
+//   - error: Error if the key is invalid or the file cannot be read
+func (s *fileBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[claim-check] failed to read payload %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (s *fileBlobStore) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", fmt.Errorf("[claim-check] invalid payload key %q", key)
+	}
+	return filepath.Join(s.directory, key), nil
+}
+
+// claimCheckOutInterceptor stores large payloads in a blob store, replacing
+// them with a reference header.
+type claimCheckOutInterceptor struct {
+	store     BlobStore
+	threshold int
+}
+
+// NewClaimCheckOutInterceptor creates an outbound interceptor storing the
+// payloads whose JSON encoding is bigger than the threshold in the blob store.
+// The message is sent with an empty payload and the claimCheck header holding
+// the payload key.
+//
+// Parameters:
+//   - store: The blob store keeping the payloads
+//   - threshold: The encoded payload size, in bytes, above which it is stored
+//
+// Returns:
+//   - *claimCheckOutInterceptor: Configured interceptor instance
+func NewClaimCheckOutInterceptor(
+	store BlobStore,
+	threshold int,
+) *claimCheckOutInterceptor {
+	return &claimCheckOutInterceptor{store: store, threshold: threshold}
+}
+
+// Handle stores the message payload when it exceeds the threshold.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to be sent
+//
+// Returns:
+//   - *message.Message: The message to send, with the payload replaced by its
+//     reference when stored
+//   - error: Error if the payload cannot be encoded or stored
+func (h *claimCheckOutInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetHeader().Get(message.HeaderClaimCheck) != "" {
+		return msg, nil
+	}
+
+	data, err := json.Marshal(msg.GetPayload())
+	if err != nil {
+		return nil, fmt.Errorf("[claim-check] failed to encode payload: %w", err)
+	}
+	if len(data) <= h.threshold {
+		return msg, nil
+	}
+
+	key := uuid.New().String()
+	if err := h.store.Put(ctx, key, data); err != nil {
+		return nil, fmt.Errorf("[claim-check] failed to store payload: %w", err)
+	}
+
+	return copyMessage(msg).
+		WithPayload(nil).
+		WithCustomHeader(message.HeaderClaimCheck, key).
+		Build(), nil
+}
+
+// claimCheckInInterceptor rehydrates claim-checked payloads.
+type claimCheckInInterceptor struct {
+	store BlobStore
+}
+
+// NewClaimCheckInInterceptor creates an inbound interceptor loading the
+// payload of claim-checked messages from the blob store, before the handler
+// runs.
+//
+// Parameters:
+//   - store: The blob store keeping the payloads
+//
+// Returns:
+//   - *claimCheckInInterceptor: Configured interceptor instance
+func NewClaimCheckInInterceptor(store BlobStore) *claimCheckInInterceptor {
+	return &claimCheckInInterceptor{store: store}
+}
+
+// Handle replaces the payload of a claim-checked message with the stored one.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The received message
+//
+// Returns:
+//   - *message.Message: The message with its original payload
+//   - error: Error if the payload cannot be loaded
+func (h *claimCheckInInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	key := msg.GetHeader().Get(message.HeaderClaimCheck)
+	if key == "" {
+		return msg, nil
+	}
+
+	data, err := h.store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[claim-check] failed to load payload of message %s: %w",
+			msg.GetHeader().Get(message.HeaderMessageId),
+			err,
+		)
+	}
+
+	rehydrated := copyMessage(msg).WithPayload(data).Build()
+	delete(rehydrated.GetHeader(), message.HeaderClaimCheck)
+	return rehydrated, nil
+}
+
+// copyMessage returns a builder of a message copy, keeping its context, raw
+// message and internal reply channel.
+func copyMessage(msg *message.Message) *message.MessageBuilder {
+	builder := message.NewMessageBuilderFromMessage(msg).WithContext(msg.GetContext())
+	if replyChannel := msg.GetInternalReplyChannel(); replyChannel != nil {
+		builder.WithInternalReplyChannel(replyChannel)
+	}
+	return builder
+}
@@ -0,0 +1,71 @@
+package handler_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/channel"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestClaimCheck_RoundTrip(t *testing.T) {
+	t.Parallel()
+	store := handler.NewFileBlobStore(t.TempDir())
+	out := handler.NewClaimCheckOutInterceptor(store, 16)
+	in := handler.NewClaimCheckInInterceptor(store)
+
+	large := strings.Repeat("x", 32)
+	msg := message.NewMessageBuilder().WithPayload(large).Build()
+	sent, err := out.Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.GetPayload() != nil || sent.GetHeader().Get(message.HeaderClaimCheck) == "" {
+		t.Fatalf("expected payload replaced by a claim check, got %v", sent.GetPayload())
+	}
+	if sent.GetHeader().Get(message.HeaderMessageId) != msg.GetHeader().Get(message.HeaderMessageId) {
+		t.Error("expected message id to be kept")
+	}
+
+	replyChannel := channel.NewPointToPointChannel("claim-check-reply")
+	received := message.NewMessageBuilderFromMessage(sent).
+		WithPayload([]byte("null")).
+		WithInternalReplyChannel(replyChannel).
+		Build()
+	rehydrated, err := in.Handle(context.Background(), received)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(rehydrated.GetPayload().([]byte)) != `"`+large+`"` {
+		t.Errorf("expected original encoded payload, got %s", rehydrated.GetPayload())
+	}
+	if _, ok := rehydrated.GetHeader()[message.HeaderClaimCheck]; ok {
+		t.Error("expected claim check header to be removed")
+	}
+	if rehydrated.GetInternalReplyChannel() != replyChannel {
+		t.Error("expected internal reply channel to be kept")
+	}
+}
+
+func TestClaimCheck_SmallPayloadAndErrors(t *testing.T) {
+	t.Parallel()
+	store := handler.NewFileBlobStore(t.TempDir())
+
+	msg := message.NewMessageBuilder().WithPayload("small").Build()
+	sent, err := handler.NewClaimCheckOutInterceptor(store, 16).Handle(context.Background(), msg)
+	if err != nil || sent != msg {
+		t.Errorf("expected small payload to be sent as is, got %v (%v)", sent, err)
+	}
+
+	missing := message.NewMessageBuilder().
+		WithCustomHeader(message.HeaderClaimCheck, "missing").
+		Build()
+	if _, err := handler.NewClaimCheckInInterceptor(store).Handle(context.Background(), missing); err == nil {
+		t.Error("expected error for missing payload")
+	}
+	if err := store.Put(context.Background(), "../escape", []byte("x")); err == nil {
+		t.Error("expected error for invalid payload key")
+	}
+}
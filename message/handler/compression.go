@@ -0,0 +1,76 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The compression implementation supports:
+// - Outbound payload compression requested by the contentEncoding header
+// - Minimum payload size below which messages are sent uncompressed
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// compressionInterceptor requests the compression of outbound payloads.
+type compressionInterceptor struct {
+	encoding string
+	minSize  int
+}
+
+// NewCompressionInterceptor creates an outbound interceptor setting the
+// contentEncoding header, so the channel message translator compresses the
+// serialized payload with the given encoding.
+//
+// Parameters:
+//   - encoding: the content encoding, message.ContentEncodingGzip or
+//     message.ContentEncodingZstd
+//   - minSize: the serialized payload size, in bytes, below which the payload
+//     is sent uncompressed
+//
+// Returns:
+//   - *compressionInterceptor: Configured interceptor instance
+//   - error: Error if the encoding is not supported
+func NewCompressionInterceptor(
+	encoding string,
+	minSize int,
+) (*compressionInterceptor, error) {
+	if err := message.ValidateContentEncoding(encoding); err != nil {
+		return nil, err
+	}
+	return &compressionInterceptor{encoding: encoding, minSize: minSize}, nil
+}
+
+// Handle sets the contentEncoding header of messages whose payload is big
+// enough to be compressed.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to be sent
+//
+// Returns:
+//   - *message.Message: The message to send
+//   - error: Error if the payload cannot be encoded
+func (h *compressionInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetHeader().Get(message.HeaderContentEncoding) != "" {
+		return msg, nil
+	}
+	if h.minSize > 0 {
+		data, err := json.Marshal(msg.GetPayload())
+		if err != nil {
+			return nil, fmt.Errorf("[compression] failed to encode payload: %w", err)
+		}
+		if len(data) < h.minSize {
+			return msg, nil
+		}
+	}
+
+	return copyMessage(msg).
+		WithCustomHeader(message.HeaderContentEncoding, h.encoding).
+		Build(), nil
+}
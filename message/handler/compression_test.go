@@ -0,0 +1,39 @@
+package handler_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestCompressionInterceptor_Handle(t *testing.T) {
+	t.Parallel()
+	interceptor, err := handler.NewCompressionInterceptor(message.ContentEncodingGzip, 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	large := message.NewMessageBuilder().WithPayload(strings.Repeat("x", 32)).Build()
+	sent, err := interceptor.Handle(context.Background(), large)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.GetHeader().Get(message.HeaderContentEncoding) != message.ContentEncodingGzip {
+		t.Errorf("expected gzip content encoding, got %q", sent.GetHeader().Get(message.HeaderContentEncoding))
+	}
+	if large.GetHeader().Get(message.HeaderContentEncoding) != "" {
+		t.Error("expected original message to be kept")
+	}
+
+	small := message.NewMessageBuilder().WithPayload("x").Build()
+	if sent, _ := interceptor.Handle(context.Background(), small); sent.GetHeader().Get(message.HeaderContentEncoding) != "" {
+		t.Error("expected small payload not to be compressed")
+	}
+
+	if _, err := handler.NewCompressionInterceptor("br", 0); err == nil {
+		t.Error("expected error for unsupported encoding")
+	}
+}
@@ -0,0 +1,190 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The ContentEnricher implementation supports:
+// - EIP Content Enricher augmenting the payload and headers of messages
+// - Lookups through user-provided functions, e.g. cache or database loads
+// - Lookup timeouts, abandoning lookups not honoring their context
+// - Failure policies skipping the enrichment or failing the message
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// ErrEnrichmentTimeout is returned when a lookup does not finish within the
+// enricher timeout.
+var ErrEnrichmentTimeout = errors.New("enrichment lookup timed out")
+
+// Enrichment holds the data augmenting a message.
+type Enrichment struct {
+	// Payload replaces the message payload when not nil.
+	Payload any
+	// Headers are added to the message headers, replacing existing ones.
+	Headers map[string]string
+}
+
+// EnrichFunc loads the data augmenting a message.
+type EnrichFunc func(ctx context.Context, msg *message.Message) (*Enrichment, error)
+
+// EnrichmentFailurePolicy defines how a failed lookup is handled.
+type EnrichmentFailurePolicy int
+
+// Enrichment failure policies.
+const (
+	// EnrichmentFail fails the message processing with the lookup error.
+	EnrichmentFail EnrichmentFailurePolicy = iota
+	// EnrichmentSkip logs the lookup error and processes the message as
+	// received.
+	EnrichmentSkip
+)
+
+// String returns the string representation of an EnrichmentFailurePolicy.
+//
+// Returns:
+//   - string: the policy name
+func (p EnrichmentFailurePolicy) String() string {
+	if p == EnrichmentSkip {
+		return "skip"
+	}
+	return "fail"
+}
+
+// contentEnricher augments the intercepted messages with the data loaded by
+// its lookup function.
+type contentEnricher struct {
+	enrich        EnrichFunc
+	timeout       time.Duration
+	failurePolicy EnrichmentFailurePolicy
+}
+
+// NewContentEnricher creates an interceptor augmenting the messages with the
+// data loaded by the enrich function, to be added to the before interceptors
+// of a channel. By default lookups have no timeout and their failures fail the
+// message.
+//
+// Parameters:
+//   - enrich: The function loading the enrichment of a message
+//
+// Returns:
+//   - *contentEnricher: Configured content enricher instance
+func NewContentEnricher(enrich EnrichFunc) *contentEnricher {
+	return &contentEnricher{enrich: enrich}
+}
+
+// WithTimeout bounds the lookup of each message. Lookups exceeding it are
+// abandoned, even if they do not honor their context, and handled by the
+// failure policy.
+//
+// Parameters:
+//   - timeout: The lookup timeout, zero for none
+//
+// Returns:
+//   - *contentEnricher: enricher instance for chaining
+func (e *contentEnricher) WithTimeout(timeout time.Duration) *contentEnricher {
+	e.timeout = timeout
+	return e
+}
+
+// WithFailurePolicy sets how failed or timed out lookups are handled.
+//
+// Parameters:
+//   - policy: The failure policy, EnrichmentFail by default
+//
+// Returns:
+//   - *contentEnricher: enricher instance for chaining
+func (e *contentEnricher) WithFailurePolicy(
+	policy EnrichmentFailurePolicy,
+) *contentEnricher {
+	e.failurePolicy = policy
+	return e
+}
+
+// Handle loads the enrichment of the message and returns a copy of it with
+// the enriched payload and headers.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to enrich
+//
+// Returns:
+//   - *message.Message: The enriched message, or the received one when the
+//     lookup fails with the skip policy
+//   - error: Error if the lookup fails with the fail policy
+func (e *contentEnricher) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	enrichment, err := e.lookup(ctx, msg)
+	if err != nil {
+		if e.failurePolicy == EnrichmentSkip {
+			slog.Warn("[content-enricher] skipping enrichment",
+				"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+				"route", msg.GetHeader().Get(message.HeaderRoute),
+				"reason", err.Error(),
+			)
+			return msg, nil
+		}
+		return msg, fmt.Errorf("[content-enricher] %w", err)
+	}
+	if enrichment == nil {
+		return msg, nil
+	}
+
+	enriched := message.NewMessageBuilderFromMessage(msg)
+	if enrichment.Payload != nil {
+		enriched.WithPayload(enrichment.Payload)
+	}
+	for key, value := range enrichment.Headers {
+		enriched.WithCustomHeader(key, value)
+	}
+	return enriched.Build(), nil
+}
+
+// lookup runs the enrich function, bounded by the enricher timeout.
+func (e *contentEnricher) lookup(
+	ctx context.Context,
+	msg *message.Message,
+) (*Enrichment, error) {
+	if e.timeout <= 0 {
+		return e.enrich(ctx, msg)
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	type lookupResult struct {
+		enrichment *Enrichment
+		err        error
+	}
+	// buffered, so an abandoned lookup never blocks
+	result := make(chan lookupResult, 1)
+	go func() {
+		enrichment, err := e.enrich(lookupCtx, msg)
+		result <- lookupResult{enrichment: enrichment, err: err}
+	}()
+
+	select {
+	case r := <-result:
+		if r.err != nil && lookupCtx.Err() != nil && ctx.Err() == nil {
+			return nil, e.timeoutError()
+		}
+		return r.enrichment, r.err
+	case <-lookupCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, e.timeoutError()
+	}
+}
+
+// timeoutError returns the error of a lookup exceeding the timeout.
+func (e *contentEnricher) timeoutError() error {
+	return fmt.Errorf("%w after %s", ErrEnrichmentTimeout, e.timeout)
+}
@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type enrichedOrder struct {
+	UserId   string
+	UserName string
+}
+
+func loadUser(ctx context.Context, msg *message.Message) (*handler.Enrichment, error) {
+	userId := msg.GetHeader().Get("userId")
+	return &handler.Enrichment{
+		Payload: &enrichedOrder{UserId: userId, UserName: "user " + userId},
+		Headers: map[string]string{"userTier": "gold"},
+	}, nil
+}
+
+func TestContentEnricher_Handle(t *testing.T) {
+	t.Parallel()
+	t.Run("should enrich payload and headers", func(t *testing.T) {
+		t.Parallel()
+		enricher := handler.NewContentEnricher(loadUser)
+		msg := message.NewMessageBuilder().
+			WithCustomHeader("userId", "42").
+			WithPayload("order").
+			Build()
+
+		result, err := enricher.Handle(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		payload, ok := result.GetPayload().(*enrichedOrder)
+		if !ok || payload.UserName != "user 42" {
+			t.Errorf("expected enriched payload, got %v", result.GetPayload())
+		}
+		if result.GetHeader().Get("userTier") != "gold" ||
+			result.GetHeader().Get("userId") != "42" {
+			t.Errorf("expected enriched headers, got %v", result.GetHeader())
+		}
+		if msg.GetPayload() != "order" {
+			t.Error("expected the received message unchanged")
+		}
+	})
+
+	t.Run("should keep the message without enrichment", func(t *testing.T) {
+		t.Parallel()
+		enricher := handler.NewContentEnricher(
+			func(ctx context.Context, msg *message.Message) (*handler.Enrichment, error) {
+				return nil, nil
+			},
+		)
+		msg := message.NewMessageBuilder().Build()
+
+		result, err := enricher.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Errorf("expected message unchanged, got %v, %v", result, err)
+		}
+	})
+
+	t.Run("should fail the message when the lookup fails", func(t *testing.T) {
+		t.Parallel()
+		lookupErr := errors.New("user not found")
+		enricher := handler.NewContentEnricher(
+			func(ctx context.Context, msg *message.Message) (*handler.Enrichment, error) {
+				return nil, lookupErr
+			},
+		)
+
+		_, err := enricher.Handle(context.Background(), message.NewMessageBuilder().Build())
+		if !errors.Is(err, lookupErr) {
+			t.Errorf("expected lookup error, got %v", err)
+		}
+	})
+
+	t.Run("should skip the enrichment when the lookup times out", func(t *testing.T) {
+		t.Parallel()
+		release := make(chan struct{})
+		defer close(release)
+		enricher := handler.NewContentEnricher(
+			func(ctx context.Context, msg *message.Message) (*handler.Enrichment, error) {
+				<-release
+				return &handler.Enrichment{Payload: "late"}, nil
+			},
+		).
+			WithTimeout(20 * time.Millisecond).
+			WithFailurePolicy(handler.EnrichmentSkip)
+		msg := message.NewMessageBuilder().WithPayload("order").Build()
+
+		result, err := enricher.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Errorf("expected message unchanged, got %v, %v", result, err)
+		}
+	})
+
+	t.Run("should fail the message when the lookup times out", func(t *testing.T) {
+		t.Parallel()
+		enricher := handler.NewContentEnricher(
+			func(ctx context.Context, msg *message.Message) (*handler.Enrichment, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		).WithTimeout(20 * time.Millisecond)
+
+		_, err := enricher.Handle(context.Background(), message.NewMessageBuilder().Build())
+		if !errors.Is(err, handler.ErrEnrichmentTimeout) {
+			t.Errorf("expected timeout error, got %v", err)
+		}
+	})
+}
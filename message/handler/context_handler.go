@@ -14,6 +14,7 @@ package handler
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 )
@@ -54,7 +55,11 @@ func (h *contextHandler) Handle(
 	case <-ctx.Done():
 		switch ctx.Err() {
 		case context.DeadlineExceeded:
-			return nil, context.DeadlineExceeded
+			return nil, fmt.Errorf(
+				"[context-handler] processing %w: %w",
+				message.ErrTimeout,
+				context.DeadlineExceeded,
+			)
 		case context.Canceled:
 			return nil, context.Canceled
 		default:
@@ -0,0 +1,259 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The CorrelationStore implementation supports:
+// - Pending request correlations (request messageId to caller metadata)
+// - Pluggable stores, so correlations survive process restarts
+// - In-memory and JSON file stores
+// - Expiration of correlations whose reply never arrives
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/internal/jsonfile"
+)
+
+// PendingCorrelation holds the metadata of a request waiting for its reply,
+// identified by the message id of the request. Replies reference it through
+// their causation id, which stays unique even when a correlation id is
+// shared by every message of a workflow.
+type PendingCorrelation struct {
+	CorrelationId  string            `json:"correlationId"`
+	MessageId      string            `json:"messageId"`
+	Route          string            `json:"route,omitempty"`
+	RequestChannel string            `json:"requestChannel,omitempty"`
+	ReplyChannel   string            `json:"replyChannel,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	ExpiresAt      time.Time         `json:"expiresAt,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// Expired reports whether the correlation expired at the given time. A
+// correlation without expiration never expires.
+//
+// Parameters:
+//   - now: the reference time
+//
+// Returns:
+//   - bool: true if the correlation is expired
+func (c PendingCorrelation) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// CorrelationStore defines the contract for stores of pending request
+// correlations. Implementations backed by durable storage (e.g. Redis, a SQL
+// table) allow replies arriving after a process restart to be matched to
+// their request metadata.
+type CorrelationStore interface {
+	// Save records a pending correlation, replacing any correlation of the
+	// same request message id.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - correlation: The pending correlation
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	Save(ctx context.Context, correlation PendingCorrelation) error
+	// Find returns the pending correlation of a request.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - messageId: The message id of the request
+	//
+	// Returns:
+	//   - PendingCorrelation: The pending correlation
+	//   - bool: false if there is no pending, unexpired correlation
+	//   - error: Error if the store operation fails
+	Find(ctx context.Context, messageId string) (PendingCorrelation, bool, error)
+	// Delete removes the pending correlation of a request.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - messageId: The message id of the request
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	Delete(ctx context.Context, messageId string) error
+}
+
+// inMemoryCorrelationStore is a process local CorrelationStore.
+type inMemoryCorrelationStore struct {
+	mu           sync.Mutex
+	correlations map[string]PendingCorrelation
+}
+
+// NewInMemoryCorrelationStore creates a process local correlation store. Its
+// correlations do not survive restarts.
+//
+// Returns:
+//   - *inMemoryCorrelationStore: Configured store instance
+func NewInMemoryCorrelationStore() *inMemoryCorrelationStore {
+	return &inMemoryCorrelationStore{correlations: map[string]PendingCorrelation{}}
+}
+
+// Save records a pending correlation.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - correlation: The pending correlation
+//
+// Returns:
+//   - error: always nil
+func (s *inMemoryCorrelationStore) Save(
+	ctx context.Context,
+	correlation PendingCorrelation,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired(time.Now())
+	s.correlations[correlation.MessageId] = correlation
+	return nil
+}
+
+// Find returns the pending correlation of a request.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - messageId: The message id of the request
+//
+// Returns:
+//   - PendingCorrelation: The pending correlation
+//   - bool: false if there is no pending, unexpired correlation
+//   - error: always nil
+func (s *inMemoryCorrelationStore) Find(
+	ctx context.Context,
+	messageId string,
+) (PendingCorrelation, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	correlation, ok := s.correlations[messageId]
+	if !ok || correlation.Expired(time.Now()) {
+		return PendingCorrelation{}, false, nil
+	}
+	return correlation, true, nil
+}
+
+// Delete removes the pending correlation of a request.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - messageId: The message id of the request
+//
+// Returns:
+//   - error: always nil
+func (s *inMemoryCorrelationStore) Delete(
+	ctx context.Context,
+	messageId string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.correlations, messageId)
+	return nil
+}
+
+func (s *inMemoryCorrelationStore) purgeExpired(now time.Time) {
+	for id, correlation := range s.correlations {
+		if correlation.Expired(now) {
+			delete(s.correlations, id)
+		}
+	}
+}
+
+// fileCorrelationStore is a CorrelationStore persisting its correlations in a
+// JSON file, for single instance deployments without shared storage.
+type fileCorrelationStore struct {
+	path   string
+	memory *inMemoryCorrelationStore
+}
+
+// NewFileCorrelationStore creates a correlation store persisted in a JSON
+// file, loading the correlations saved by previous processes.
+//
+// Parameters:
+//   - path: The JSON file path, created on the first save
+//
+// Returns:
+//   - *fileCorrelationStore: Configured store instance
+//   - error: Error if the existing file cannot be read
+func NewFileCorrelationStore(path string) (*fileCorrelationStore, error) {
+	store := &fileCorrelationStore{
+		path:   path,
+		memory: NewInMemoryCorrelationStore(),
+	}
+
+	if err := jsonfile.Load(path, &store.memory.correlations); err != nil {
+		return nil, fmt.Errorf("[correlation-store] %w", err)
+	}
+	return store, nil
+}
+
+// Save records a pending correlation and persists the store.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - correlation: The pending correlation
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (s *fileCorrelationStore) Save(
+	ctx context.Context,
+	correlation PendingCorrelation,
+) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+	s.memory.purgeExpired(time.Now())
+	s.memory.correlations[correlation.MessageId] = correlation
+	return s.persist()
+}
+
+// Find returns the pending correlation of a request.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - messageId: The message id of the request
+//
+// Returns:
+//   - PendingCorrelation: The pending correlation
+//   - bool: false if there is no pending, unexpired correlation
+//   - error: always nil
+func (s *fileCorrelationStore) Find(
+	ctx context.Context,
+	messageId string,
+) (PendingCorrelation, bool, error) {
+	return s.memory.Find(ctx, messageId)
+}
+
+// Delete removes the pending correlation of a request and persists the
+// store.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - messageId: The message id of the request
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (s *fileCorrelationStore) Delete(
+	ctx context.Context,
+	messageId string,
+) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+	if _, ok := s.memory.correlations[messageId]; !ok {
+		return nil
+	}
+	delete(s.memory.correlations, messageId)
+	return s.persist()
+}
+
+// persist atomically rewrites the store file. The caller holds the lock.
+func (s *fileCorrelationStore) persist() error {
+	if err := jsonfile.Save(s.path, s.memory.correlations); err != nil {
+		return fmt.Errorf("[correlation-store] %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestInMemoryCorrelationStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := handler.NewInMemoryCorrelationStore()
+
+	store.Save(ctx, handler.PendingCorrelation{CorrelationId: "workflow", MessageId: "msg-a"})
+	store.Save(ctx, handler.PendingCorrelation{CorrelationId: "workflow", MessageId: "msg-b"})
+	store.Save(ctx, handler.PendingCorrelation{
+		MessageId: "expired",
+		ExpiresAt: time.Now().Add(-time.Second),
+	})
+
+	if correlation, found, err := store.Find(ctx, "msg-a"); err != nil || !found || correlation.MessageId != "msg-a" {
+		t.Errorf("expected correlation of msg-a, got %+v (%v, %v)", correlation, found, err)
+	}
+	if _, found, _ := store.Find(ctx, "msg-b"); !found {
+		t.Error("expected requests sharing a correlation id to keep their own correlation")
+	}
+	if _, found, _ := store.Find(ctx, "expired"); found {
+		t.Error("expected expired correlation not to be found")
+	}
+
+	store.Delete(ctx, "msg-a")
+	if _, found, _ := store.Find(ctx, "msg-a"); found {
+		t.Error("expected deleted correlation not to be found")
+	}
+}
+
+func TestFileCorrelationStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "correlations.json")
+
+	store, err := handler.NewFileCorrelationStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.Save(ctx, handler.PendingCorrelation{
+		MessageId: "a",
+		Route:     "order.create",
+		CreatedAt: time.Now(),
+	})
+	store.Save(ctx, handler.PendingCorrelation{MessageId: "b"})
+	store.Delete(ctx, "b")
+
+	restarted, err := handler.NewFileCorrelationStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error reloading store: %v", err)
+	}
+	if correlation, found, _ := restarted.Find(ctx, "a"); !found || correlation.Route != "order.create" {
+		t.Errorf("expected correlation to survive restart, got %+v (%v)", correlation, found)
+	}
+	if _, found, _ := restarted.Find(ctx, "b"); found {
+		t.Error("expected deleted correlation not to survive restart")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	os.WriteFile(invalid, []byte("{"), 0o600)
+	if _, err := handler.NewFileCorrelationStore(invalid); err == nil {
+		t.Error("expected error for invalid store file")
+	}
+}
@@ -9,13 +9,20 @@
 // - Failed message handling and routing
 // - Dead letter channel integration
 // - Error logging and monitoring
+// - Failure metadata headers for triage and reprocessing
+// - Stack trace header of messages failed by a handler panic
+// - Guard failure header of messages rejected by the consumer guards
+// - Dead letter channels per route
 // - Graceful error recovery patterns
 package handler
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/otel"
@@ -24,9 +31,11 @@ import (
 // deadLetter implements the Dead Letter Channel pattern, routing failed messages
 // to a designated dead letter channel for further processing or analysis.
 type deadLetter struct {
-	channel   message.PublisherChannel
-	handler   message.MessageHandler
-	otelTrace otel.OtelTrace
+	channel       message.PublisherChannel
+	routeChannels map[string]message.PublisherChannel
+	handler       message.MessageHandler
+	otelTrace     otel.OtelTrace
+	sourceChannel string
 }
 type deadLetterMessage struct {
 	ReasonError string
@@ -38,7 +47,8 @@ type deadLetterMessage struct {
 // messages to the specified dead letter channel.
 //
 // Parameters:
-//   - channel: the publisher channel for sending failed messages
+//   - channel: the publisher channel for sending failed messages, nil when
+//     only the routes set by WithRouteChannels are dead lettered
 //   - handler: the message handler to attempt processing with
 //
 // Returns:
@@ -54,9 +64,43 @@ func NewDeadLetter(
 	}
 }
 
+// WithSourceChannel sets the name of the channel the failed messages were
+// consumed from, reported in the dlqOriginalChannel header. When not set, the
+// channel name header of the failed message is used.
+//
+// Parameters:
+//   - channelName: the source channel name
+//
+// Returns:
+//   - *deadLetter: dead letter handler for method chaining
+func (s *deadLetter) WithSourceChannel(channelName string) *deadLetter {
+	s.sourceChannel = channelName
+	return s
+}
+
+// WithRouteChannels sets the dead letter channels of the failed messages of
+// some routes, used instead of the default dead letter channel.
+//
+// Parameters:
+//   - channels: the dead letter channels by route
+//
+// Returns:
+//   - *deadLetter: dead letter handler for method chaining
+func (s *deadLetter) WithRouteChannels(
+	channels map[string]message.PublisherChannel,
+) *deadLetter {
+	s.routeChannels = channels
+	return s
+}
+
 // Handle processes a message by attempting to process it with the wrapped handler.
 // If processing fails, the message is sent to the dead letter channel for further
-// analysis or processing.
+// analysis or processing, enriched with failure metadata headers (original
+// channel, error, handler, attempts and failure timestamp). The dead letter
+// channel of the message route is used when set, the default one otherwise.
+// Messages already sent to an unroutable channel, left unacknowledged for
+// redelivery (see IsReplyNotPublished) or without a dead letter channel for their route, are not
+// dead-lettered.
 //
 // Parameters:
 //   - ctx: context for timeout/cancellation control
@@ -64,7 +108,8 @@ func NewDeadLetter(
 //
 // Returns:
 //   - *message.Message: the original message (regardless of processing success)
-//   - error: error if processing fails (message is sent to dead letter channel)
+//   - error: error if processing fails (message is sent to dead letter channel),
+//     reported by IsDeadLettered once the message is dead lettered
 func (s *deadLetter) Handle(
 	ctx context.Context,
 	msg *message.Message,
@@ -74,6 +119,15 @@ func (s *deadLetter) Handle(
 		return resultMessage, nil
 	}
 
+	if isUnroutableError(err) || IsReplyNotPublished(err) {
+		return resultMessage, err
+	}
+
+	channel := s.channelOf(msg)
+	if channel == nil {
+		return resultMessage, err
+	}
+
 	ctx, span := s.otelTrace.Start(
 		ctx,
 		"Send message to dead letter",
@@ -89,7 +143,7 @@ func (s *deadLetter) Handle(
 		slog.Error("[dead-letter-handler] cannot convert original payload",
 			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
 			"reason", errP.Error(),
-			"dlqChannelName", s.channel.Name(),
+			"dlqChannelName", channel.Name(),
 		)
 
 		span.Error(errP, "[dead-letter-handler] cannot convert original payload")
@@ -97,17 +151,17 @@ func (s *deadLetter) Handle(
 		return resultMessage, errP
 	}
 
-	dlqMessage := s.makeDeadLetterMessage(ctx, msg, &deadLetterMessage{
+	dlqMessage := s.makeDeadLetterMessage(ctx, channel, msg, err, &deadLetterMessage{
 		ReasonError: err.Error(),
 		Payload:     originalPayload,
 	})
 
-	errDql := s.channel.Send(ctx, dlqMessage)
+	errDql := channel.Send(ctx, dlqMessage)
 	if errDql != nil {
 		slog.Error("[dead-letter-handler] failed to send message to dead letter",
 			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
 			"reason", errDql.Error(),
-			"dlqChannelName", s.channel.Name(),
+			"dlqChannelName", channel.Name(),
 		)
 		span.Error(errDql, "[dead-letter-handler] failed to send message to dead letter")
 		return resultMessage, errDql
@@ -116,11 +170,49 @@ func (s *deadLetter) Handle(
 	slog.Info("[dead-letter-handler] Sent message to dead letter",
 		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
 		"reason", err.Error(),
-		"dlqChannelName", s.channel.Name(),
+		"dlqChannelName", channel.Name(),
 	)
 	span.Success("[dead-letter-handler] sent message to dead letter")
 
-	return resultMessage, err
+	return resultMessage, &deadLetteredError{err: err}
+}
+
+// deadLetteredError is the processing error of a message sent to the dead
+// letter channel. It keeps the message of the processing error.
+type deadLetteredError struct {
+	err error
+}
+
+// Error returns the message of the processing error.
+func (e *deadLetteredError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the processing error.
+func (e *deadLetteredError) Unwrap() error {
+	return e.err
+}
+
+// IsDeadLettered reports whether a processing error belongs to a message
+// which was sent to the dead letter channel.
+//
+// Parameters:
+//   - err: the processing error
+//
+// Returns:
+//   - bool: true if the failed message was dead lettered
+func IsDeadLettered(err error) bool {
+	var deadLettered *deadLetteredError
+	return errors.As(err, &deadLettered)
+}
+
+// channelOf returns the dead letter channel of the message route, or the
+// default dead letter channel.
+func (s *deadLetter) channelOf(msg *message.Message) message.PublisherChannel {
+	if channel, ok := s.routeChannels[msg.GetHeader().Get(message.HeaderRoute)]; ok {
+		return channel
+	}
+	return s.channel
 }
 
 func (s *deadLetter) convertMessagePayload(msg *message.Message) (any, error) {
@@ -136,17 +228,45 @@ func (s *deadLetter) convertMessagePayload(msg *message.Message) (any, error) {
 
 func (s *deadLetter) makeDeadLetterMessage(
 	ctxDql context.Context,
+	channel message.PublisherChannel,
 	msg *message.Message,
+	reason error,
 	payload *deadLetterMessage,
 ) *message.Message {
 	headers := msg.GetHeader()
 	payload.Headers = headers
 	dlqMessage := message.NewMessageBuilder()
 	dlqMessage.WithContext(ctxDql)
-	dlqMessage.WithChannelName(s.channel.Name())
+	dlqMessage.WithChannelName(channel.Name())
 	dlqMessage.WithMessageType(message.Document)
 	dlqMessage.WithCorrelationId(msg.GetHeader().Get(message.HeaderCorrelationId))
 	dlqMessage.WithPayload(payload)
 
+	originalChannel := s.sourceChannel
+	if originalChannel == "" {
+		originalChannel = headers.Get(message.HeaderChannelName)
+	}
+	attempts := 1
+	if retries, err := strconv.Atoi(headers.Get(message.HeaderRetryAttempts)); err == nil {
+		attempts += retries
+	}
+
+	dlqMessage.WithCustomHeader(message.HeaderDeadLetterOriginalChannel, originalChannel)
+	dlqMessage.WithCustomHeader(message.HeaderDeadLetterError, reason.Error())
+	dlqMessage.WithCustomHeader(message.HeaderDeadLetterHandler, headers.Get(message.HeaderRoute))
+	dlqMessage.WithCustomHeader(message.HeaderDeadLetterAttempts, strconv.Itoa(attempts))
+	dlqMessage.WithCustomHeader(
+		message.HeaderDeadLetterFailedAt,
+		time.Now().UTC().Format(time.RFC3339Nano),
+	)
+	var panicErr *message.PanicError
+	if errors.As(reason, &panicErr) {
+		dlqMessage.WithCustomHeader(message.HeaderDeadLetterStackTrace, panicErr.Stack)
+	}
+	var guardErr *message.GuardError
+	if errors.As(reason, &guardErr) {
+		dlqMessage.WithCustomHeader(message.HeaderDeadLetterGuardFailure, guardErr.Guard)
+	}
+
 	return dlqMessage.Build()
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/message/handler"
 )
@@ -66,8 +67,147 @@ func TestDeadLetter_Handle(t *testing.T) {
 		handlerMock := &mockDeadMessageHandler{shouldFail: true, failErr: dlErr}
 		dl := handler.NewDeadLetter(channel, handlerMock)
 		_, err := dl.Handle(ctx, msg)
-		if err == nil {
-			t.Errorf("expected error, got nil")
+		if !errors.Is(err, dlErr) {
+			t.Errorf("expected handler error, got %v", err)
+		}
+		if !handler.IsDeadLettered(err) {
+			t.Error("expected error reported as dead lettered")
+		}
+		if handler.IsDeadLettered(dlErr) {
+			t.Error("expected handler error not reported as dead lettered")
+		}
+	})
+
+	t.Run("should enrich dead letter message with failure metadata", func(t *testing.T) {
+		t.Parallel()
+		dlErr := errors.New("handler failed")
+		msgF := message.NewMessageBuilder().
+			WithPayload("payload").
+			WithRoute("createUser").
+			WithCustomHeader(message.HeaderRetryAttempts, "2").
+			Build()
+		channel := &mockPublisherChannel{}
+		handlerMock := &mockDeadMessageHandler{shouldFail: true, failErr: dlErr}
+		dl := handler.NewDeadLetter(channel, handlerMock).WithSourceChannel("users.topic")
+		dl.Handle(ctx, msgF)
+
+		if channel.sentMsg == nil {
+			t.Fatal("expected message sent to dead letter channel")
+		}
+		header := channel.sentMsg.GetHeader()
+		expected := map[string]string{
+			message.HeaderDeadLetterOriginalChannel: "users.topic",
+			message.HeaderDeadLetterError:           "handler failed",
+			message.HeaderDeadLetterHandler:         "createUser",
+			message.HeaderDeadLetterAttempts:        "3",
+		}
+		for key, want := range expected {
+			if got := header.Get(key); got != want {
+				t.Errorf("expected header %s to be '%s', got '%s'", key, want, got)
+			}
+		}
+		if header.Get(message.HeaderDeadLetterFailedAt) == "" {
+			t.Error("expected failure timestamp header")
+		}
+	})
+
+	t.Run("should add the stack trace of handler panics", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockPublisherChannel{}
+		handlerMock := &mockDeadMessageHandler{
+			shouldFail: true,
+			failErr:    message.NewPanicError("boom", []byte("goroutine 1 [running]")),
+		}
+		dl := handler.NewDeadLetter(channel, handlerMock)
+		dl.Handle(ctx, msg)
+
+		if channel.sentMsg == nil {
+			t.Fatal("expected message sent to dead letter channel")
+		}
+		header := channel.sentMsg.GetHeader()
+		if got := header.Get(message.HeaderDeadLetterStackTrace); got != "goroutine 1 [running]" {
+			t.Errorf("expected stack trace header, got '%s'", got)
+		}
+		if got := header.Get(message.HeaderDeadLetterError); got != "handler panicked: boom" {
+			t.Errorf("expected panic error header, got '%s'", got)
+		}
+	})
+
+	t.Run("should add the guard failure header of rejected messages", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockPublisherChannel{}
+		handlerMock := &mockDeadMessageHandler{
+			shouldFail: true,
+			failErr: handler.NewPermanentError(
+				message.NewGuardError(handler.GuardAllowedRoutes, "route deleteUser is not allowed"),
+			),
+		}
+		dl := handler.NewDeadLetter(channel, handlerMock)
+		dl.Handle(ctx, msg)
+
+		if channel.sentMsg == nil {
+			t.Fatal("expected message sent to dead letter channel")
+		}
+		header := channel.sentMsg.GetHeader()
+		if got := header.Get(message.HeaderDeadLetterGuardFailure); got != handler.GuardAllowedRoutes {
+			t.Errorf("expected guard failure header, got '%s'", got)
+		}
+	})
+
+	t.Run("should send to the dead letter channel of the route", func(t *testing.T) {
+		t.Parallel()
+		dlErr := errors.New("handler failed")
+		channel := &mockPublisherChannel{}
+		userChannel := &mockPublisherChannel{}
+		handlerMock := &mockDeadMessageHandler{shouldFail: true, failErr: dlErr}
+		dl := handler.NewDeadLetter(channel, handlerMock).
+			WithRouteChannels(map[string]message.PublisherChannel{"createUser": userChannel})
+
+		dl.Handle(ctx, message.NewMessageBuilder().WithRoute("createUser").WithPayload("payload").Build())
+		if userChannel.sentMsg == nil || channel.sentMsg != nil {
+			t.Fatal("expected message sent to the dead letter channel of the route")
+		}
+
+		_, err := dl.Handle(ctx, message.NewMessageBuilder().WithRoute("deleteUser").WithPayload("payload").Build())
+		if channel.sentMsg == nil || !handler.IsDeadLettered(err) {
+			t.Fatal("expected message of unmapped route sent to the default dead letter channel")
+		}
+	})
+
+	t.Run("should not dead letter routes without dead letter channel", func(t *testing.T) {
+		t.Parallel()
+		dlErr := errors.New("handler failed")
+		userChannel := &mockPublisherChannel{}
+		handlerMock := &mockDeadMessageHandler{shouldFail: true, failErr: dlErr}
+		dl := handler.NewDeadLetter(nil, handlerMock).
+			WithRouteChannels(map[string]message.PublisherChannel{"createUser": userChannel})
+
+		_, err := dl.Handle(ctx, message.NewMessageBuilder().WithRoute("deleteUser").WithPayload("payload").Build())
+		if !errors.Is(err, dlErr) || handler.IsDeadLettered(err) {
+			t.Errorf("expected handler error not dead lettered, got %v", err)
+		}
+		if userChannel.sentMsg != nil {
+			t.Error("expected no message sent to the dead letter channel of the route")
+		}
+	})
+
+	t.Run("should not dead letter requests whose reply was not published", func(t *testing.T) {
+		t.Parallel()
+		publisher := &replyPublisherMock{err: errors.New("broker unavailable")}
+		replyContainer := container.NewGenericContainer[any, any]()
+		replyContainer.Set("replies", publisher)
+		replyHandler := handler.NewSendReplyToHandler(&replyTohandlerMock{}, replyContainer).
+			WithAtomicReply()
+		channel := &mockPublisherChannel{}
+		dl := handler.NewDeadLetter(channel, replyHandler)
+
+		reqCtx := message.ContextWithAcknowledger(ctx, &replyAcknowledgerMock{})
+		_, err := dl.Handle(reqCtx, message.NewMessageBuilder().WithReplyTo("replies").WithPayload("request").Build())
+		if !handler.IsReplyNotPublished(err) || handler.IsDeadLettered(err) {
+			t.Errorf("expected reply not published error not dead lettered, got %v", err)
+		}
+		if channel.sentMsg != nil {
+			t.Error("expected no message sent to dead letter channel")
 		}
 	})
 
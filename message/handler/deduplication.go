@@ -0,0 +1,219 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The Deduplication implementation supports:
+// - Idempotent Receiver pattern over at-least-once channels
+// - Pluggable stores for the processed message ids (in-memory LRU, Redis, SQL)
+// - Time-to-live for the processed message ids
+// - Release of the message id when processing fails, allowing redelivery
+package handler
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// DeduplicationStore defines the contract for stores tracking the ids of
+// processed messages. Implementations backed by shared storage (e.g. Redis
+// SET NX with expiration, or a SQL table with a unique key) make the
+// deduplication effective across consumer instances.
+type DeduplicationStore interface {
+	// Reserve atomically records the message id if it is not yet recorded.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - messageId: The message id to record
+	//   - ttl: How long the id is kept; zero means it never expires
+	//
+	// Returns:
+	//   - bool: true if the id was recorded, false if it already existed
+	//   - error: Error if the store operation fails
+	Reserve(ctx context.Context, messageId string, ttl time.Duration) (bool, error)
+	// Release removes a recorded message id.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - messageId: The message id to remove
+	//
+	// Returns:
+	//   - error: Error if the store operation fails
+	Release(ctx context.Context, messageId string) error
+}
+
+// deduplicationHandler implements the Idempotent Receiver pattern, skipping
+// messages whose id was already processed.
+type deduplicationHandler struct {
+	store   DeduplicationStore
+	ttl     time.Duration
+	handler message.MessageHandler
+}
+
+// NewDeduplicationHandler creates a new deduplication handler that wraps an
+// existing message handler, skipping duplicated messages.
+//
+// Parameters:
+//   - store: The store tracking the processed message ids
+//   - ttl: How long a processed message id is kept
+//   - handler: The underlying message handler to wrap
+//
+// Returns:
+//   - *deduplicationHandler: Configured deduplication handler instance
+func NewDeduplicationHandler(
+	store DeduplicationStore,
+	ttl time.Duration,
+	handler message.MessageHandler,
+) *deduplicationHandler {
+	return &deduplicationHandler{store: store, ttl: ttl, handler: handler}
+}
+
+// Handle processes a message through the wrapped handler only if its id was
+// not processed yet. Duplicated messages are short-circuited without error, so
+// they are acknowledged. When processing fails the id is released, allowing
+// the message to be processed again on redelivery.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to process
+//
+// Returns:
+//   - *message.Message: The resulting message from processing
+//   - error: Error if the store fails or processing fails
+func (h *deduplicationHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	messageId := msg.GetHeader().Get(message.HeaderMessageId)
+	if messageId == "" {
+		return h.handler.Handle(ctx, msg)
+	}
+
+	reserved, err := h.store.Reserve(ctx, messageId, h.ttl)
+	if err != nil {
+		return msg, fmt.Errorf("[deduplication-handler] store error: %w", err)
+	}
+
+	if !reserved {
+		slog.Info("[deduplication-handler] skipping duplicated message",
+			"messageId", messageId,
+			"route", msg.GetHeader().Get(message.HeaderRoute),
+		)
+		return msg, nil
+	}
+
+	resultMessage, err := h.handler.Handle(ctx, msg)
+	if err != nil {
+		if errR := h.store.Release(context.WithoutCancel(ctx), messageId); errR != nil {
+			slog.Error("[deduplication-handler] failed to release message id",
+				"messageId", messageId,
+				"reason", errR.Error(),
+			)
+		}
+		return resultMessage, err
+	}
+
+	return resultMessage, nil
+}
+
+// inMemoryDeduplicationStore is a least recently used, in-memory
+// DeduplicationStore, suitable for single instance consumers.
+type inMemoryDeduplicationStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// deduplicationEntry holds a recorded message id and its expiration.
+type deduplicationEntry struct {
+	messageId string
+	expiresAt time.Time
+}
+
+// NewInMemoryDeduplicationStore creates a new in-memory deduplication store
+// which evicts the least recently recorded ids when the capacity is reached.
+//
+// Parameters:
+//   - capacity: Maximum number of message ids kept; zero means unbounded
+//
+// Returns:
+//   - *inMemoryDeduplicationStore: Configured store instance
+func NewInMemoryDeduplicationStore(capacity int) *inMemoryDeduplicationStore {
+	return &inMemoryDeduplicationStore{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Reserve atomically records the message id if it is not yet recorded or if
+// its previous record has expired.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - messageId: The message id to record
+//   - ttl: How long the id is kept; zero means it never expires
+//
+// Returns:
+//   - bool: true if the id was recorded, false if it already existed
+//   - error: always nil
+func (s *inMemoryDeduplicationStore) Reserve(
+	ctx context.Context,
+	messageId string,
+	ttl time.Duration,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if element, ok := s.entries[messageId]; ok {
+		entry := element.Value.(*deduplicationEntry)
+		if entry.expiresAt.IsZero() || now.Before(entry.expiresAt) {
+			return false, nil
+		}
+		s.remove(element)
+	}
+
+	entry := &deduplicationEntry{messageId: messageId}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	s.entries[messageId] = s.order.PushFront(entry)
+
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+
+	return true, nil
+}
+
+// Release removes a recorded message id.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - messageId: The message id to remove
+//
+// Returns:
+//   - error: always nil
+func (s *inMemoryDeduplicationStore) Release(
+	ctx context.Context,
+	messageId string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[messageId]; ok {
+		s.remove(element)
+	}
+	return nil
+}
+
+func (s *inMemoryDeduplicationStore) remove(element *list.Element) {
+	entry := s.order.Remove(element).(*deduplicationEntry)
+	delete(s.entries, entry.messageId)
+}
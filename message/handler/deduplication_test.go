@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type countingMessageHandler struct {
+	calls int
+	err   error
+}
+
+func (m *countingMessageHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	m.calls++
+	return msg, m.err
+}
+
+func TestDeduplicationHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should skip duplicated messages", func(t *testing.T) {
+		t.Parallel()
+		inner := &countingMessageHandler{}
+		dedup := handler.NewDeduplicationHandler(
+			handler.NewInMemoryDeduplicationStore(10), time.Minute, inner,
+		)
+		msg := message.NewMessageBuilder().WithMessageId("msg-1").Build()
+
+		for range 3 {
+			if _, err := dedup.Handle(ctx, msg); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if inner.calls != 1 {
+			t.Errorf("expected 1 call, got %d", inner.calls)
+		}
+	})
+
+	t.Run("should process again after a failure", func(t *testing.T) {
+		t.Parallel()
+		inner := &countingMessageHandler{err: errors.New("fail")}
+		dedup := handler.NewDeduplicationHandler(
+			handler.NewInMemoryDeduplicationStore(10), time.Minute, inner,
+		)
+		msg := message.NewMessageBuilder().WithMessageId("msg-1").Build()
+
+		dedup.Handle(ctx, msg)
+		dedup.Handle(ctx, msg)
+		if inner.calls != 2 {
+			t.Errorf("expected 2 calls, got %d", inner.calls)
+		}
+	})
+}
+
+func TestInMemoryDeduplicationStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should expire ids after ttl", func(t *testing.T) {
+		t.Parallel()
+		store := handler.NewInMemoryDeduplicationStore(0)
+		store.Reserve(ctx, "a", 10*time.Millisecond)
+		if ok, _ := store.Reserve(ctx, "a", 10*time.Millisecond); ok {
+			t.Error("expected duplicated id before ttl")
+		}
+		time.Sleep(20 * time.Millisecond)
+		if ok, _ := store.Reserve(ctx, "a", 10*time.Millisecond); !ok {
+			t.Error("expected id to be reserved after ttl")
+		}
+	})
+
+	t.Run("should evict least recent ids over capacity", func(t *testing.T) {
+		t.Parallel()
+		store := handler.NewInMemoryDeduplicationStore(2)
+		for i := range 3 {
+			store.Reserve(ctx, fmt.Sprint(i), 0)
+		}
+		if ok, _ := store.Reserve(ctx, "0", 0); !ok {
+			t.Error("expected evicted id to be reserved again")
+		}
+		if ok, _ := store.Reserve(ctx, "2", 0); ok {
+			t.Error("expected recent id to be duplicated")
+		}
+	})
+}
@@ -0,0 +1,122 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The Downcaster implementation supports:
+// - Outbound conversion of payloads to the version expected by a channel
+// - Downcasters keyed by message route and version
+// - Chained downcasters, e.g. from version 3.0 to 2.0 and then to 1.0
+// - Messages of routes without downcasters sent unchanged
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// DowncastFunc converts a payload to the shape of an older version.
+type DowncastFunc func(ctx context.Context, payload any) (any, error)
+
+// Downcaster converts the payloads of a route from one version to an older
+// one.
+type Downcaster struct {
+	Route       string
+	FromVersion string
+	ToVersion   string
+	Downcast    DowncastFunc
+}
+
+// downcastInterceptor converts outbound payloads to the version expected by
+// the consumers of a channel.
+type downcastInterceptor struct {
+	targetVersion string
+	downcasters   map[string]map[string]Downcaster
+}
+
+// NewDowncastInterceptor creates an outbound interceptor converting the
+// message payloads to the target version, so legacy consumers keep receiving
+// the payload shape they understand while producers publish newer versions.
+// The version header of converted messages is set to the target version.
+//
+// Parameters:
+//   - targetVersion: the version expected by the channel consumers
+//   - downcasters: the downcasters of each route and version
+//
+// Returns:
+//   - *downcastInterceptor: Configured interceptor instance
+func NewDowncastInterceptor(
+	targetVersion string,
+	downcasters ...Downcaster,
+) *downcastInterceptor {
+	byRoute := map[string]map[string]Downcaster{}
+	for _, downcaster := range downcasters {
+		if byRoute[downcaster.Route] == nil {
+			byRoute[downcaster.Route] = map[string]Downcaster{}
+		}
+		byRoute[downcaster.Route][downcaster.FromVersion] = downcaster
+	}
+	return &downcastInterceptor{targetVersion: targetVersion, downcasters: byRoute}
+}
+
+// Handle converts the message payload down to the target version.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to be sent
+//
+// Returns:
+//   - *message.Message: The message in the target version
+//   - error: Error if there is no downcaster path to the target version or a
+//     downcaster fails
+func (h *downcastInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	route := msg.GetHeader().Get(message.HeaderRoute)
+	downcasters, ok := h.downcasters[route]
+	version := msg.GetHeader().Get(message.HeaderVersion)
+	if !ok || version == h.targetVersion {
+		return msg, nil
+	}
+
+	payload := msg.GetPayload()
+	visited := map[string]bool{}
+	for version != h.targetVersion {
+		downcaster, ok := downcasters[version]
+		if !ok || visited[version] {
+			return nil, fmt.Errorf(
+				"[downcaster] no downcaster of route %s from version %s to %s",
+				route,
+				version,
+				h.targetVersion,
+			)
+		}
+		visited[version] = true
+
+		var err error
+		if payload, err = downcaster.Downcast(ctx, payload); err != nil {
+			return nil, fmt.Errorf(
+				"[downcaster] failed to downcast route %s from version %s to %s: %w",
+				route,
+				downcaster.FromVersion,
+				downcaster.ToVersion,
+				err,
+			)
+		}
+		version = downcaster.ToVersion
+	}
+
+	slog.Debug("[downcaster] message downcast",
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"route", route,
+		"fromVersion", msg.GetHeader().Get(message.HeaderVersion),
+		"toVersion", version,
+	)
+
+	return copyMessage(msg).
+		WithPayload(payload).
+		WithVersion(version).
+		Build(), nil
+}
@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type orderCreatedV3 struct {
+	OrderId  string
+	Customer string
+}
+
+type orderCreatedV2 struct {
+	OrderId string
+}
+
+func TestDowncastInterceptor_Handle(t *testing.T) {
+	t.Parallel()
+	interceptor := handler.NewDowncastInterceptor("1.0",
+		handler.Downcaster{
+			Route:       "order.created",
+			FromVersion: "3.0",
+			ToVersion:   "2.0",
+			Downcast: func(ctx context.Context, payload any) (any, error) {
+				return orderCreatedV2{OrderId: payload.(orderCreatedV3).OrderId}, nil
+			},
+		},
+		handler.Downcaster{
+			Route:       "order.created",
+			FromVersion: "2.0",
+			ToVersion:   "1.0",
+			Downcast: func(ctx context.Context, payload any) (any, error) {
+				return payload.(orderCreatedV2).OrderId, nil
+			},
+		},
+		handler.Downcaster{
+			Route:       "order.failed",
+			FromVersion: "2.0",
+			ToVersion:   "1.0",
+			Downcast: func(ctx context.Context, payload any) (any, error) {
+				return nil, errors.New("invalid")
+			},
+		},
+	)
+
+	t.Run("should chain downcasters to the target version", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().
+			WithRoute("order.created").
+			WithVersion("3.0").
+			WithPayload(orderCreatedV3{OrderId: "1", Customer: "c"}).
+			Build()
+		sent, err := interceptor.Handle(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent.GetPayload() != "1" || sent.GetHeader().Get(message.HeaderVersion) != "1.0" {
+			t.Errorf("expected version 1.0 payload, got %v (%s)", sent.GetPayload(), sent.GetHeader().Get(message.HeaderVersion))
+		}
+		if msg.GetHeader().Get(message.HeaderVersion) != "3.0" {
+			t.Error("expected original message to be kept")
+		}
+	})
+
+	t.Run("should send routes without downcasters unchanged", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().WithRoute("order.paid").WithVersion("3.0").Build()
+		if sent, _ := interceptor.Handle(context.Background(), msg); sent != msg {
+			t.Error("expected unchanged message")
+		}
+	})
+
+	t.Run("should fail without a downcaster path", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().WithRoute("order.created").WithVersion("4.0").Build()
+		if _, err := interceptor.Handle(context.Background(), msg); err == nil {
+			t.Error("expected error for missing downcaster")
+		}
+	})
+
+	t.Run("should fail when a downcaster fails", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().WithRoute("order.failed").WithVersion("2.0").Build()
+		if _, err := interceptor.Handle(context.Background(), msg); err == nil {
+			t.Error("expected downcaster error")
+		}
+	})
+}
@@ -0,0 +1,321 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The Encryption implementation supports:
+// - Envelope encryption of payloads with AES-256-GCM data keys
+// - Pluggable key providers wrapping the data keys (KMS, Vault, static keys)
+// - Key id and wrapped data key carried in the message headers
+// - Outbound interceptor encrypting payloads before publishing
+// - Inbound interceptor decrypting payloads before the handler runs
+package handler
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// KeyProvider defines the contract for the key management service protecting
+// the data keys of encrypted payloads.
+type KeyProvider interface {
+	// WrapKey encrypts a data key with the current master key.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - dataKey: The plain data key
+	//
+	// Returns:
+	//   - string: The id of the master key
+	//   - []byte: The wrapped data key
+	//   - error: Error if the key cannot be wrapped
+	WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error)
+	// UnwrapKey decrypts a data key with the given master key.
+	//
+	// Parameters:
+	//   - ctx: Context for timeout/cancellation control
+	//   - keyId: The id of the master key
+	//   - wrappedKey: The wrapped data key
+	//
+	// Returns:
+	//   - []byte: The plain data key
+	//   - error: Error if the master key is unknown or the key cannot be
+	//     unwrapped
+	UnwrapKey(ctx context.Context, keyId string, wrappedKey []byte) ([]byte, error)
+}
+
+// staticKeyProvider is a KeyProvider wrapping data keys with in-process
+// AES-256 master keys.
+type staticKeyProvider struct {
+	currentKeyId string
+	keys         map[string][]byte
+}
+
+// NewStaticKeyProvider creates a key provider wrapping data keys with the
+// given 32 byte master keys. Older keys are kept to decrypt the messages
+// published before a key rotation.
+//
+// Parameters:
+//   - currentKeyId: The id of the key wrapping new data keys
+//   - keys: The master keys by id
+//
+// Returns:
+//   - *staticKeyProvider: Configured key provider instance
+//   - error: Error if the current key is missing or a key is not 32 bytes
+func NewStaticKeyProvider(
+	currentKeyId string,
+	keys map[string][]byte,
+) (*staticKeyProvider, error) {
+	if _, ok := keys[currentKeyId]; !ok {
+		return nil, fmt.Errorf("[encryption] current key %s not found", currentKeyId)
+	}
+	for keyId, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("[encryption] key %s must have 32 bytes", keyId)
+		}
+	}
+	return &staticKeyProvider{currentKeyId: currentKeyId, keys: keys}, nil
+}
+
+// WrapKey encrypts a data key with the current master key.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - dataKey: The plain data key
+//
+// Returns:
+//   - string: The id of the current master key
+//   - []byte: The wrapped data key
+//   - error: Error if the key cannot be wrapped
+func (p *staticKeyProvider) WrapKey(
+	ctx context.Context,
+	dataKey []byte,
+) (string, []byte, error) {
+	wrapped, err := sealAESGCM(p.keys[p.currentKeyId], dataKey, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return p.currentKeyId, wrapped, nil
+}
+
+// UnwrapKey decrypts a data key with the given master key.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - keyId: The id of the master key
+//   - wrappedKey: The wrapped data key
+//
+// Returns:
+//   - []byte: The plain data key
+//   - error: Error if the master key is unknown or the key cannot be unwrapped
+func (p *staticKeyProvider) UnwrapKey(
+	ctx context.Context,
+	keyId string,
+	wrappedKey []byte,
+) ([]byte, error) {
+	key, ok := p.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("[encryption] key %s not found", keyId)
+	}
+	return openAESGCM(key, wrappedKey, nil)
+}
+
+// encryptInterceptor encrypts outbound payloads.
+type encryptInterceptor struct {
+	keyProvider KeyProvider
+}
+
+// NewEncryptInterceptor creates an outbound interceptor encrypting the JSON
+// encoding of the message payload with a new AES-256-GCM data key. The data
+// key, wrapped by the key provider, and the master key id are sent in the
+// message headers. The message id is authenticated with the payload, so the
+// ciphertext cannot be moved to another message.
+//
+// Parameters:
+//   - keyProvider: The key provider wrapping the data keys
+//
+// Returns:
+//   - *encryptInterceptor: Configured interceptor instance
+func NewEncryptInterceptor(keyProvider KeyProvider) *encryptInterceptor {
+	return &encryptInterceptor{keyProvider: keyProvider}
+}
+
+// Handle replaces the message payload with its ciphertext.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to be sent
+//
+// Returns:
+//   - *message.Message: The message with the encrypted payload
+//   - error: Error if the payload cannot be encoded or encrypted
+func (h *encryptInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetHeader().Get(message.HeaderEncryptionKeyId) != "" {
+		return msg, nil
+	}
+
+	data, err := json.Marshal(msg.GetPayload())
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] failed to encode payload: %w", err)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("[encryption] failed to generate data key: %w", err)
+	}
+	ciphertext, err := sealAESGCM(dataKey, data, messageIdOf(msg))
+	if err != nil {
+		return nil, err
+	}
+	keyId, wrappedKey, err := h.keyProvider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] failed to wrap data key: %w", err)
+	}
+
+	return copyMessage(msg).
+		WithPayload(ciphertext).
+		WithCustomHeader(message.HeaderEncryptionKeyId, keyId).
+		WithCustomHeader(
+			message.HeaderEncryptedDataKey,
+			base64.StdEncoding.EncodeToString(wrappedKey),
+		).
+		Build(), nil
+}
+
+// decryptInterceptor decrypts inbound payloads.
+type decryptInterceptor struct {
+	keyProvider KeyProvider
+}
+
+// NewDecryptInterceptor creates an inbound interceptor decrypting the payload
+// of encrypted messages before the handler runs. Messages without the
+// encryption headers are passed unchanged, and a payload encrypted for
+// another message id is rejected.
+//
+// Parameters:
+//   - keyProvider: The key provider unwrapping the data keys
+//
+// Returns:
+//   - *decryptInterceptor: Configured interceptor instance
+func NewDecryptInterceptor(keyProvider KeyProvider) *decryptInterceptor {
+	return &decryptInterceptor{keyProvider: keyProvider}
+}
+
+// Handle replaces the ciphertext of an encrypted message with its plain
+// payload.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The received message
+//
+// Returns:
+//   - *message.Message: The message with the plain JSON payload
+//   - error: Error if the payload cannot be decrypted
+func (h *decryptInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	keyId := msg.GetHeader().Get(message.HeaderEncryptionKeyId)
+	if keyId == "" {
+		return msg, nil
+	}
+
+	ciphertext, err := encryptedPayload(msg.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := base64.StdEncoding.DecodeString(
+		msg.GetHeader().Get(message.HeaderEncryptedDataKey),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] invalid encrypted data key: %w", err)
+	}
+	dataKey, err := h.keyProvider.UnwrapKey(ctx, keyId, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] failed to unwrap data key: %w", err)
+	}
+	data, err := openAESGCM(dataKey, ciphertext, messageIdOf(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := copyMessage(msg).WithPayload(data).Build()
+	delete(decrypted.GetHeader(), message.HeaderEncryptionKeyId)
+	delete(decrypted.GetHeader(), message.HeaderEncryptedDataKey)
+	return decrypted, nil
+}
+
+// encryptedPayload returns the ciphertext of a payload, either as sent in
+// process or as its JSON encoding received from a channel.
+func encryptedPayload(payload any) ([]byte, error) {
+	switch p := payload.(type) {
+	case []byte:
+		var ciphertext []byte
+		if err := json.Unmarshal(p, &ciphertext); err == nil {
+			return ciphertext, nil
+		}
+		return p, nil
+	case message.JSONPayload:
+		var ciphertext []byte
+		if err := json.Unmarshal(p.Data, &ciphertext); err != nil {
+			return nil, fmt.Errorf("[encryption] invalid encrypted payload: %w", err)
+		}
+		return ciphertext, nil
+	default:
+		return nil, fmt.Errorf("[encryption] invalid encrypted payload type %T", payload)
+	}
+}
+
+// messageIdOf returns the message id authenticated as the additional data of
+// an encrypted payload.
+func messageIdOf(msg *message.Message) []byte {
+	return []byte(msg.GetHeader().Get(message.HeaderMessageId))
+}
+
+func sealAESGCM(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("[encryption] failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openAESGCM(key []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("[encryption] ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] invalid key: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] invalid key: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestEncryption_RoundTrip(t *testing.T) {
+	t.Parallel()
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	provider, err := handler.NewStaticKeyProvider("key-1", map[string][]byte{"key-1": oldKey})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := message.NewMessageBuilder().WithPayload(map[string]string{"document": "123"}).Build()
+	sent, err := handler.NewEncryptInterceptor(provider).Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.GetHeader().Get(message.HeaderEncryptionKeyId) != "key-1" {
+		t.Fatalf("expected key id header, got %q", sent.GetHeader().Get(message.HeaderEncryptionKeyId))
+	}
+	wire, _ := json.Marshal(sent.GetPayload())
+	if bytes.Contains(wire, []byte("123")) {
+		t.Fatalf("expected encrypted payload, got %s", wire)
+	}
+
+	rotated, _ := handler.NewStaticKeyProvider("key-2", map[string][]byte{
+		"key-1": oldKey,
+		"key-2": bytes.Repeat([]byte{2}, 32),
+	})
+	received := message.NewMessageBuilderFromMessage(sent).WithPayload(wire).Build()
+	decrypted, err := handler.NewDecryptInterceptor(rotated).Handle(context.Background(), received)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(decrypted.GetPayload().([]byte)) != `{"document":"123"}` {
+		t.Errorf("expected plain payload, got %s", decrypted.GetPayload())
+	}
+	if decrypted.GetHeader().Get(message.HeaderEncryptionKeyId) != "" {
+		t.Error("expected encryption headers to be removed")
+	}
+
+	unknown, _ := handler.NewStaticKeyProvider("key-3", map[string][]byte{"key-3": bytes.Repeat([]byte{3}, 32)})
+	if _, err := handler.NewDecryptInterceptor(unknown).Handle(context.Background(), received); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestNewStaticKeyProvider_InvalidKeys(t *testing.T) {
+	t.Parallel()
+	if _, err := handler.NewStaticKeyProvider("missing", map[string][]byte{}); err == nil {
+		t.Error("expected error for missing current key")
+	}
+	if _, err := handler.NewStaticKeyProvider("short", map[string][]byte{"short": []byte("x")}); err == nil {
+		t.Error("expected error for short key")
+	}
+}
+
+func TestEncryption_RejectsPayloadOfAnotherMessage(t *testing.T) {
+	t.Parallel()
+	provider, _ := handler.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": bytes.Repeat([]byte{1}, 32),
+	})
+	msg := message.NewMessageBuilder().WithPayload(map[string]string{"document": "123"}).Build()
+	sent, err := handler.NewEncryptInterceptor(provider).Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	swapped := message.NewMessageBuilderFromMessage(sent).WithMessageId("another-message").Build()
+	if _, err := handler.NewDecryptInterceptor(provider).Handle(context.Background(), swapped); err == nil {
+		t.Error("expected error for a payload encrypted for another message")
+	}
+}
@@ -0,0 +1,258 @@
+// Package handler provides message handling components for the message system.
+//
+// This package implements various message handlers that process and route messages
+// through the system. It provides specialized handlers for different message
+// processing scenarios including action handling, context management, and error
+// handling patterns.
+//
+// The EventSubscribersActivator implementation supports:
+// - In-process publish/subscribe delivery of events
+// - Multiple subscribers per event type
+// - Concurrent subscriber execution with aggregated errors
+// - Subscriber panics recovered as errors
+// - Reply channel integration with the gateway pipeline
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/channel"
+)
+
+// EventSubscriber defines the contract for in-process subscribers of a
+// specific event type.
+type EventSubscriber[T any] interface {
+	Handle(ctx context.Context, event T) error
+}
+
+// EventSubscriberFunc adapts a function to the EventSubscriber contract, so
+// plain functions can subscribe to events.
+type EventSubscriberFunc[T any] func(ctx context.Context, event T) error
+
+// EventSubscribersActivatorBuilder provides a builder pattern for creating
+// event subscribers activators that fan out an event to every registered
+// subscriber.
+type EventSubscribersActivatorBuilder struct {
+	referenceName string
+	subscribers   []message.MessageHandler
+	mu            sync.Mutex
+}
+
+// EventSubscribersActivator delivers an event message to every registered
+// subscriber and replies through the internal reply channel once all of them
+// have finished.
+type EventSubscribersActivator struct {
+	subscribers []message.MessageHandler
+}
+
+// eventSubscriberHandler adapts a typed EventSubscriber to the
+// message.MessageHandler contract.
+type eventSubscriberHandler[T any] struct {
+	subscriber EventSubscriber[T]
+}
+
+// NewEventSubscribersActivatorBuilder creates a new event subscribers activator
+// builder instance.
+//
+// Parameters:
+//   - referenceName: the event name the subscribers listen to
+//
+// Returns:
+//   - *EventSubscribersActivatorBuilder: configured builder instance
+func NewEventSubscribersActivatorBuilder(
+	referenceName string,
+) *EventSubscribersActivatorBuilder {
+	return &EventSubscribersActivatorBuilder{
+		referenceName: referenceName,
+		subscribers:   []message.MessageHandler{},
+	}
+}
+
+// NewEventSubscribersActivator creates a new event subscribers activator.
+//
+// Parameters:
+//   - subscribers: the message handlers that receive every event
+//
+// Returns:
+//   - *EventSubscribersActivator: configured activator
+func NewEventSubscribersActivator(
+	subscribers ...message.MessageHandler,
+) *EventSubscribersActivator {
+	return &EventSubscribersActivator{subscribers: subscribers}
+}
+
+// NewEventSubscriberHandler wraps a typed event subscriber into a message
+// handler which converts the message payload to the event type.
+//
+// Parameters:
+//   - subscriber: the typed event subscriber
+//
+// Returns:
+//   - message.MessageHandler: the adapted subscriber
+func NewEventSubscriberHandler[T any](
+	subscriber EventSubscriber[T],
+) message.MessageHandler {
+	return &eventSubscriberHandler[T]{subscriber: subscriber}
+}
+
+// Handle calls the function with the event.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - event: the received event
+//
+// Returns:
+//   - error: the error returned by the function
+func (f EventSubscriberFunc[T]) Handle(ctx context.Context, event T) error {
+	return f(ctx, event)
+}
+
+// AddSubscriber registers a new subscriber for the event.
+//
+// Parameters:
+//   - subscriber: the message handler to be notified
+//
+// Returns:
+//   - *EventSubscribersActivatorBuilder: builder instance for method chaining
+func (b *EventSubscribersActivatorBuilder) AddSubscriber(
+	subscriber message.MessageHandler,
+) *EventSubscribersActivatorBuilder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber)
+	return b
+}
+
+// ReferenceName returns the reference name of the activator builder.
+//
+// Returns:
+//   - string: the reference name
+func (b *EventSubscribersActivatorBuilder) ReferenceName() string {
+	return b.referenceName
+}
+
+// Build constructs an event subscribers activator exposed as a publisher
+// channel, so it can be resolved by the recipient list router.
+//
+// Parameters:
+//   - container: dependency container containing required components
+//
+// Returns:
+//   - message.PublisherChannel: configured publisher channel for the activator
+//   - error: error if construction fails
+func (b *EventSubscribersActivatorBuilder) Build(
+	container container.Container[any, any],
+) (message.PublisherChannel, error) {
+	b.mu.Lock()
+	activator := NewEventSubscribersActivator(b.subscribers...)
+	b.mu.Unlock()
+
+	chn := channel.NewPointToPointChannel(b.referenceName)
+	chn.Subscribe(func(msg *message.Message) {
+		activator.Handle(msg.GetContext(), msg)
+	})
+	return chn, nil
+}
+
+// Handle delivers the event to all subscribers concurrently and waits for
+// them to finish. Subscriber errors are joined and returned to the publisher
+// through the internal reply channel.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message containing the event
+//
+// Returns:
+//   - *message.Message: the result message
+//   - error: joined subscriber errors, if any
+func (a *EventSubscribersActivator) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	var wg sync.WaitGroup
+	errs := make([]error, len(a.subscribers))
+	for i, subscriber := range a.subscribers {
+		wg.Add(1)
+		go func(index int, subscriber message.MessageHandler) {
+			defer wg.Done()
+			defer recoverPanic(ctx, fmt.Sprintf("%T", subscriber), &errs[index])
+			_, errs[index] = subscriber.Handle(ctx, msg)
+		}(i, subscriber)
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+
+	resultMessageBuilder := message.NewMessageBuilder().
+		WithMessageType(message.Document).
+		WithCorrelationId(msg.GetHeader().Get(message.HeaderCorrelationId)).
+		WithPayload(msg.GetPayload())
+
+	if err != nil {
+		resultMessageBuilder.WithPayload(err)
+	}
+
+	replyChannel := msg.GetInternalReplyChannel()
+	if replyChannel != nil {
+		resultMessageBuilder.WithChannelName(replyChannel.Name())
+	}
+
+	resultMessage := resultMessageBuilder.Build()
+	if replyChannel != nil {
+		replyChannel.Send(ctx, resultMessage)
+	}
+
+	return resultMessage, err
+}
+
+// Handle converts the message payload into the event type and delivers it to
+// the typed subscriber. When it is an external message, the payload MUST be
+// of type []byte. Subscribers implementing MessageAware receive the message
+// before Handle is called.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message containing the event
+//
+// Returns:
+//   - *message.Message: the original message
+//   - error: error if conversion or subscriber execution fails
+func (h *eventSubscriberHandler[T]) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	event, ok := msg.GetPayload().(T)
+	if !ok {
+		decoded, err := decodePayload(msg.GetPayload(), &event)
+		if !decoded {
+			return nil, fmt.Errorf(
+				"[event-subscriber] cannot process event: %w, incorrect contract data",
+				message.ErrTranslation,
+			)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[event-subscriber] cannot process event: %w: %w",
+				message.ErrTranslation,
+				err,
+			)
+		}
+	}
+
+	if aware, ok := any(h.subscriber).(MessageAware); ok {
+		aware.SetMessage(msg)
+	}
+
+	ctx = message.ContextWithMessage(ctx, msg)
+	if err := h.subscriber.Handle(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,191 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/channel"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type mockEvent struct {
+	Id string `json:"id"`
+}
+
+func (e *mockEvent) Name() string {
+	return "mockEvent"
+}
+
+type mockEventSubscriber struct {
+	calls atomic.Int32
+	err   error
+	last  atomic.Value
+}
+
+func (s *mockEventSubscriber) Handle(ctx context.Context, event *mockEvent) error {
+	s.calls.Add(1)
+	s.last.Store(event.Id)
+	return s.err
+}
+
+func TestEventSubscribersActivatorBuilder_Build(t *testing.T) {
+	t.Parallel()
+	builder := handler.NewEventSubscribersActivatorBuilder("mockEvent").
+		AddSubscriber(handler.NewEventSubscriberHandler(&mockEventSubscriber{}))
+	if builder.ReferenceName() != "mockEvent" {
+		t.Errorf("Expected ReferenceName 'mockEvent', got '%s'", builder.ReferenceName())
+	}
+	chn, err := builder.Build(container.NewGenericContainer[any, any]())
+	if err != nil {
+		t.Errorf("Expected success, got error: %v", err)
+	}
+	if chn == nil || chn.Name() != "mockEvent" {
+		t.Error("Expected channel instance named mockEvent")
+	}
+}
+
+func TestEventSubscribersActivator_Handle(t *testing.T) {
+	t.Run("should deliver event to every subscriber", func(t *testing.T) {
+		t.Parallel()
+		first := &mockEventSubscriber{}
+		second := &mockEventSubscriber{}
+		activator := handler.NewEventSubscribersActivator(
+			handler.NewEventSubscriberHandler(first),
+			handler.NewEventSubscriberHandler(second),
+		)
+		replyChannel := channel.NewPointToPointChannel("reply-subscribers")
+		go replyChannel.Receive(context.Background())
+
+		msg := message.NewMessageBuilder().
+			WithMessageType(message.Event).
+			WithPayload(&mockEvent{Id: "1"}).
+			WithInternalReplyChannel(replyChannel).
+			Build()
+
+		_, err := activator.Handle(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("Expected success, got error: %v", err)
+		}
+		if first.calls.Load() != 1 || second.calls.Load() != 1 {
+			t.Error("Expected both subscribers to be called once")
+		}
+	})
+
+	t.Run("should decode external payload", func(t *testing.T) {
+		t.Parallel()
+		subscriber := &mockEventSubscriber{}
+		activator := handler.NewEventSubscribersActivator(
+			handler.NewEventSubscriberHandler(subscriber),
+		)
+		msg := message.NewMessageBuilder().
+			WithPayload([]byte(`{"id":"external"}`)).
+			Build()
+
+		_, err := activator.Handle(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("Expected success, got error: %v", err)
+		}
+		if subscriber.last.Load() != "external" {
+			t.Errorf("Expected event id 'external', got %v", subscriber.last.Load())
+		}
+	})
+
+	t.Run("should return joined subscriber errors", func(t *testing.T) {
+		t.Parallel()
+		subscriberErr := errors.New("subscriber failed")
+		failing := &mockEventSubscriber{err: subscriberErr}
+		succeeding := &mockEventSubscriber{}
+		activator := handler.NewEventSubscribersActivator(
+			handler.NewEventSubscriberHandler(failing),
+			handler.NewEventSubscriberHandler(succeeding),
+		)
+		replyChannel := channel.NewPointToPointChannel("reply-subscribers-error")
+		replies := make(chan *message.Message, 1)
+		go func() {
+			reply, _ := replyChannel.Receive(context.Background())
+			replies <- reply
+		}()
+
+		msg := message.NewMessageBuilder().
+			WithPayload(&mockEvent{Id: "2"}).
+			WithInternalReplyChannel(replyChannel).
+			Build()
+
+		_, err := activator.Handle(context.Background(), msg)
+		if !errors.Is(err, subscriberErr) {
+			t.Errorf("Expected subscriber error, got: %v", err)
+		}
+		if succeeding.calls.Load() != 1 {
+			t.Error("Expected succeeding subscriber to be called")
+		}
+		if _, ok := (<-replies).GetPayload().(error); !ok {
+			t.Error("Expected error payload on reply channel")
+		}
+	})
+
+	t.Run("should recover subscriber panics", func(t *testing.T) {
+		t.Parallel()
+		succeeding := &mockEventSubscriber{}
+		activator := handler.NewEventSubscribersActivator(
+			handler.NewEventSubscriberHandler(handler.EventSubscriberFunc[*mockEvent](
+				func(ctx context.Context, event *mockEvent) error {
+					panic("subscriber exploded")
+				},
+			)),
+			handler.NewEventSubscriberHandler(succeeding),
+		)
+		msg := message.NewMessageBuilder().WithPayload(&mockEvent{Id: "3"}).Build()
+
+		_, err := activator.Handle(context.Background(), msg)
+		if !errors.Is(err, message.ErrPanic) {
+			t.Errorf("Expected panic error, got: %v", err)
+		}
+		if succeeding.calls.Load() != 1 {
+			t.Error("Expected succeeding subscriber to be called")
+		}
+	})
+
+	t.Run("should return error for invalid payload", func(t *testing.T) {
+		t.Parallel()
+		activator := handler.NewEventSubscribersActivator(
+			handler.NewEventSubscriberHandler(&mockEventSubscriber{}),
+		)
+		msg := message.NewMessageBuilder().WithPayload("invalid").Build()
+
+		_, err := activator.Handle(context.Background(), msg)
+		if err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestEventSubscriberFunc(t *testing.T) {
+	t.Parallel()
+	received := make(chan string, 1)
+	activator := handler.NewEventSubscribersActivator(
+		handler.NewEventSubscriberHandler(handler.EventSubscriberFunc[mockEvent](
+			func(ctx context.Context, event mockEvent) error {
+				received <- event.Id
+				return nil
+			},
+		)),
+	)
+	replyChannel := channel.NewPointToPointChannel("reply-subscriber-func")
+	go replyChannel.Receive(context.Background())
+	msg := message.NewMessageBuilder().
+		WithMessageType(message.Event).
+		WithPayload([]byte(`{"id":"func-1"}`)).
+		WithInternalReplyChannel(replyChannel).
+		Build()
+
+	if _, err := activator.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if id := <-received; id != "func-1" {
+		t.Errorf("Expected event func-1, got %s", id)
+	}
+}
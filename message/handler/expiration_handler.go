@@ -0,0 +1,120 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The Expiration implementation supports:
+// - Message time to live enforcement through the expiresAt header
+// - Discarding of the expired messages before they reach the handlers
+// - Routing of the expired messages to a dead letter channel, per route
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// expirationHandler drops the messages whose expiration has passed.
+type expirationHandler struct {
+	expiredChannel message.PublisherChannel
+	routeChannels  map[string]message.PublisherChannel
+	counter        atomic.Int64
+}
+
+// NewExpirationHandler creates an interceptor dropping the expired messages,
+// optionally sending them to a channel marked with the expired header.
+//
+// Parameters:
+//   - expiredChannel: The channel receiving the expired messages, e.g. the
+//     dead letter channel, nil to discard them
+//
+// Returns:
+//   - *expirationHandler: Configured expiration handler instance
+func NewExpirationHandler(expiredChannel message.PublisherChannel) *expirationHandler {
+	return &expirationHandler{expiredChannel: expiredChannel}
+}
+
+// WithRouteChannels sets the channels receiving the expired messages of some
+// routes, used instead of the default expired channel.
+//
+// Parameters:
+//   - channels: the expired channels by route, e.g. their dead letter channels
+//
+// Returns:
+//   - *expirationHandler: Handler instance for method chaining
+func (h *expirationHandler) WithRouteChannels(
+	channels map[string]message.PublisherChannel,
+) *expirationHandler {
+	h.routeChannels = channels
+	return h
+}
+
+// Handle passes the message through unless it has expired. Expired messages
+// are sent to the expired channel of their route, or the default one, if any, and a nil message is returned,
+// ending the processing without error so they are acknowledged.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to check
+//
+// Returns:
+//   - *message.Message: The message if it has not expired, nil otherwise
+//   - error: Error if the expired message cannot be sent
+func (h *expirationHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if !msg.IsExpired() {
+		return msg, nil
+	}
+
+	total := h.counter.Add(1)
+	attributes := []any{
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"route", msg.GetHeader().Get(message.HeaderRoute),
+		"expiresAt", msg.GetHeader().Get(message.HeaderExpiresAt),
+		"expiredTotal", total,
+	}
+	expiredChannel := h.channelOf(msg)
+	if expiredChannel == nil {
+		slog.Info("[expiration-handler] discarded expired message", attributes...)
+		return nil, nil
+	}
+
+	expiredMessage := message.NewMessageBuilderFromMessage(msg).
+		WithChannelName(expiredChannel.Name()).
+		WithContext(ctx).
+		WithBoolHeader(message.HeaderExpired, true).
+		Build()
+	if err := expiredChannel.Send(ctx, expiredMessage); err != nil {
+		return msg, fmt.Errorf(
+			"[expiration-handler] failed to send expired message to %s: %w",
+			expiredChannel.Name(),
+			err,
+		)
+	}
+
+	slog.Info("[expiration-handler] sent expired message",
+		append(attributes, "expiredChannelName", expiredChannel.Name())...,
+	)
+	return nil, nil
+}
+
+// channelOf returns the expired channel of the message route, or the default
+// expired channel.
+func (h *expirationHandler) channelOf(msg *message.Message) message.PublisherChannel {
+	if channel, ok := h.routeChannels[msg.GetHeader().Get(message.HeaderRoute)]; ok {
+		return channel
+	}
+	return h.expiredChannel
+}
+
+// Expired returns the number of expired messages dropped.
+//
+// Returns:
+//   - int64: number of expired messages
+func (h *expirationHandler) Expired() int64 {
+	return h.counter.Load()
+}
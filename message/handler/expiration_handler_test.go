@@ -0,0 +1,57 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestExpirationHandler_Handle(t *testing.T) {
+	t.Parallel()
+	t.Run("should pass messages within their ttl", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockPublisherChannel{}
+		expiration := handler.NewExpirationHandler(channel)
+		msg := message.NewMessageBuilder().WithTTL(time.Minute).Build()
+
+		result, err := expiration.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Errorf("expected message unchanged, got %v, %v", result, err)
+		}
+		if channel.sentMsg != nil {
+			t.Error("expected no expired message")
+		}
+	})
+
+	t.Run("should send expired messages marked as expired", func(t *testing.T) {
+		t.Parallel()
+		channel := &mockPublisherChannel{}
+		expiration := handler.NewExpirationHandler(channel)
+		msg := message.NewMessageBuilder().WithTTL(-time.Second).Build()
+
+		result, err := expiration.Handle(context.Background(), msg)
+		if err != nil || result != nil {
+			t.Errorf("expected nil message without error, got %v, %v", result, err)
+		}
+		if channel.sentMsg == nil || channel.sentMsg.GetHeader().Get(message.HeaderExpired) != "true" {
+			t.Error("expected message sent with the expired header")
+		}
+		if expiration.Expired() != 1 {
+			t.Errorf("expected 1 expired message, got %d", expiration.Expired())
+		}
+	})
+
+	t.Run("should discard expired messages without channel", func(t *testing.T) {
+		t.Parallel()
+		expiration := handler.NewExpirationHandler(nil)
+		msg := message.NewMessageBuilder().WithTTL(-time.Second).Build()
+
+		result, err := expiration.Handle(context.Background(), msg)
+		if err != nil || result != nil {
+			t.Errorf("expected nil message without error, got %v, %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The JSON encoding implementation supports:
+// - Outbound payload encoding with a channel JSON encoder
+// - Inbound payload decoding with a channel JSON encoder
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// jsonEncodeInterceptor encodes outbound payloads with a JSON encoder.
+type jsonEncodeInterceptor struct {
+	encoder *message.JSONEncoder
+}
+
+// NewJSONEncodeInterceptor creates an outbound interceptor encoding the
+// message payload with the given encoder. The encoded payload is sent
+// unchanged by the JSON message translators.
+//
+// Parameters:
+//   - encoder: the JSON encoder of the channel
+//
+// Returns:
+//   - *jsonEncodeInterceptor: Configured interceptor instance
+func NewJSONEncodeInterceptor(encoder *message.JSONEncoder) *jsonEncodeInterceptor {
+	return &jsonEncodeInterceptor{encoder: encoder}
+}
+
+// Handle replaces the message payload with its JSON encoding.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to be sent
+//
+// Returns:
+//   - *message.Message: The message with the encoded payload
+//   - error: Error if the payload cannot be encoded
+func (h *jsonEncodeInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if _, ok := msg.GetPayload().(json.RawMessage); ok {
+		return msg, nil
+	}
+
+	data, err := h.encoder.Marshal(msg.GetPayload())
+	if err != nil {
+		return nil, fmt.Errorf("[json-encoder] failed to encode payload: %w", err)
+	}
+	return copyMessage(msg).WithPayload(json.RawMessage(data)).Build(), nil
+}
+
+// jsonDecodeInterceptor binds received payloads to a JSON encoder.
+type jsonDecodeInterceptor struct {
+	encoder *message.JSONEncoder
+}
+
+// NewJSONDecodeInterceptor creates an inbound interceptor binding the raw
+// payload of received messages to the given encoder, so handlers decode it
+// with the channel options.
+//
+// Parameters:
+//   - encoder: the JSON encoder of the channel
+//
+// Returns:
+//   - *jsonDecodeInterceptor: Configured interceptor instance
+func NewJSONDecodeInterceptor(encoder *message.JSONEncoder) *jsonDecodeInterceptor {
+	return &jsonDecodeInterceptor{encoder: encoder}
+}
+
+// Handle replaces a raw payload with a message.JSONPayload.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The received message
+//
+// Returns:
+//   - *message.Message: The message with the bound payload
+//   - error: Always nil
+func (h *jsonDecodeInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	data, ok := msg.GetPayload().([]byte)
+	if !ok {
+		return msg, nil
+	}
+	return copyMessage(msg).
+		WithPayload(message.JSONPayload{Data: data, Encoder: h.encoder}).
+		Build(), nil
+}
+
+// decodePayload decodes a received payload into the target, with the channel
+// encoder when the payload carries one.
+func decodePayload(payload any, target any) (bool, error) {
+	switch p := payload.(type) {
+	case message.PayloadDecoder:
+		return true, p.Decode(target)
+	case []byte:
+		return true, json.Unmarshal(p, target)
+	case json.RawMessage:
+		return true, json.Unmarshal(p, target)
+	default:
+		return false, nil
+	}
+}
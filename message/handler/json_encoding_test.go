@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type jsonEncodingOrder struct {
+	OrderId string
+}
+
+func (jsonEncodingOrder) Name() string { return "jsonEncodingOrder" }
+
+type jsonEncodingSubscriber struct {
+	received jsonEncodingOrder
+}
+
+func (s *jsonEncodingSubscriber) Handle(ctx context.Context, event jsonEncodingOrder) error {
+	s.received = event
+	return nil
+}
+
+func TestJSONEncoding_RoundTrip(t *testing.T) {
+	t.Parallel()
+	encoder := message.NewJSONEncoder().
+		WithFieldNaming(message.FieldNamingSnakeCase).
+		WithDisallowUnknownFields()
+
+	msg := message.NewMessageBuilder().
+		WithPayload(jsonEncodingOrder{OrderId: "1"}).
+		Build()
+	sent, err := handler.NewJSONEncodeInterceptor(encoder).Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := sent.GetPayload().(interface{ MarshalJSON() ([]byte, error) }).MarshalJSON()
+	if string(data) != `{"order_id":"1"}` {
+		t.Fatalf("expected snake_case payload, got %s", data)
+	}
+
+	received := message.NewMessageBuilderFromMessage(sent).WithPayload(data).Build()
+	bound, err := handler.NewJSONDecodeInterceptor(encoder).Handle(context.Background(), received)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	subscriber := &jsonEncodingSubscriber{}
+	activator := handler.NewEventSubscriberHandler[jsonEncodingOrder](subscriber)
+	if _, err := activator.Handle(context.Background(), bound); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subscriber.received.OrderId != "1" {
+		t.Errorf("expected decoded event, got %+v", subscriber.received)
+	}
+}
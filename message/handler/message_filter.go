@@ -0,0 +1,99 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The MessageFilter implementation supports:
+// - Dropping messages failing a predicate over their headers and payload
+// - Routing of the dropped messages to a discard channel
+// - Discarded message counting
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/router"
+)
+
+// messageFilterHandler drops the messages failing its filter before they
+// reach the action handlers.
+type messageFilterHandler struct {
+	filter         router.FilterFunc
+	discardChannel message.PublisherChannel
+	counter        atomic.Int64
+}
+
+// NewMessageFilterHandler creates an interceptor dropping the messages
+// rejected by the filter, optionally sending them to a discard channel.
+//
+// Parameters:
+//   - filter: The predicate returning true for the messages to process
+//   - discardChannel: The channel receiving the dropped messages, nil to
+//     drop them silently
+//
+// Returns:
+//   - *messageFilterHandler: Configured message filter handler instance
+func NewMessageFilterHandler(
+	filter router.FilterFunc,
+	discardChannel message.PublisherChannel,
+) *messageFilterHandler {
+	return &messageFilterHandler{filter: filter, discardChannel: discardChannel}
+}
+
+// Handle passes the message through when it satisfies the filter. Rejected
+// messages are sent to the discard channel, if any, and a nil message is
+// returned, ending the processing without error so they are acknowledged.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to filter
+//
+// Returns:
+//   - *message.Message: The message if it satisfies the filter, nil otherwise
+//   - error: Error if the discarded message cannot be sent
+func (h *messageFilterHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if h.filter(*msg) {
+		return msg, nil
+	}
+
+	total := h.counter.Add(1)
+	attributes := []any{
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"route", msg.GetHeader().Get(message.HeaderRoute),
+		"discardedTotal", total,
+	}
+	if h.discardChannel == nil {
+		slog.Info("[message-filter] discarded message", attributes...)
+		return nil, nil
+	}
+
+	discardedMessage := message.NewMessageBuilderFromMessage(msg).
+		WithChannelName(h.discardChannel.Name()).
+		WithContext(ctx).
+		Build()
+	if err := h.discardChannel.Send(ctx, discardedMessage); err != nil {
+		return msg, fmt.Errorf(
+			"[message-filter] failed to send message to discard channel %s: %w",
+			h.discardChannel.Name(),
+			err,
+		)
+	}
+
+	slog.Info("[message-filter] sent message to discard channel",
+		append(attributes, "discardChannelName", h.discardChannel.Name())...,
+	)
+	return nil, nil
+}
+
+// Discarded returns the number of messages rejected by the filter.
+//
+// Returns:
+//   - int64: number of discarded messages
+func (h *messageFilterHandler) Discarded() int64 {
+	return h.counter.Load()
+}
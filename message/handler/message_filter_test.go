@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/channel"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func fromOrigin(origin string) func(msg message.Message) bool {
+	return func(msg message.Message) bool {
+		return msg.GetHeader().Get(message.HeaderOrigin) != origin
+	}
+}
+
+func TestMessageFilterHandler_Handle(t *testing.T) {
+	t.Parallel()
+	t.Run("should pass messages satisfying the filter", func(t *testing.T) {
+		t.Parallel()
+		discard := &mockPublisherChannel{}
+		filter := handler.NewMessageFilterHandler(fromOrigin("legacy"), discard)
+		msg := message.NewMessageBuilder().WithOrigin("orders").Build()
+
+		result, err := filter.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Errorf("expected message unchanged, got %v, %v", result, err)
+		}
+		if discard.sentMsg != nil || filter.Discarded() != 0 {
+			t.Error("expected no discarded message")
+		}
+	})
+
+	t.Run("should send rejected messages to the discard channel", func(t *testing.T) {
+		t.Parallel()
+		discard := &mockPublisherChannel{}
+		filter := handler.NewMessageFilterHandler(fromOrigin("legacy"), discard)
+		msg := message.NewMessageBuilder().
+			WithMessageId("discarded").
+			WithOrigin("legacy").
+			Build()
+
+		result, err := filter.Handle(context.Background(), msg)
+		if err != nil || result != nil {
+			t.Errorf("expected nil message without error, got %v, %v", result, err)
+		}
+		if discard.sentMsg == nil ||
+			discard.sentMsg.GetHeader().Get(message.HeaderMessageId) != "discarded" {
+			t.Error("expected message sent to the discard channel")
+		}
+		if filter.Discarded() != 1 {
+			t.Errorf("expected 1 discarded message, got %d", filter.Discarded())
+		}
+	})
+
+	t.Run("should drop rejected messages without discard channel", func(t *testing.T) {
+		t.Parallel()
+		filter := handler.NewMessageFilterHandler(fromOrigin("legacy"), nil)
+		msg := message.NewMessageBuilder().WithOrigin("legacy").Build()
+
+		result, err := filter.Handle(context.Background(), msg)
+		if err != nil || result != nil {
+			t.Errorf("expected nil message without error, got %v, %v", result, err)
+		}
+	})
+
+	t.Run("should return error when the discard channel fails", func(t *testing.T) {
+		t.Parallel()
+		discard := channel.NewPointToPointChannel("discard")
+		discard.Close()
+		filter := handler.NewMessageFilterHandler(fromOrigin("legacy"), discard)
+		msg := message.NewMessageBuilder().WithOrigin("legacy").Build()
+
+		result, err := filter.Handle(context.Background(), msg)
+		if err == nil || result != msg {
+			t.Errorf("expected error with the message, got %v, %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The MessageGuard implementation supports:
+// - Rejection of messages whose payload exceeds a maximum size
+// - Rejection of messages missing required headers
+// - Route allowlists
+// - Permanent guard errors, dead lettered with the violated guard
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// Guards reported by the guard errors.
+const (
+	GuardMaxPayloadSize  = "maxPayloadSize"
+	GuardRequiredHeaders = "requiredHeaders"
+	GuardAllowedRoutes   = "allowedRoutes"
+)
+
+// MessageGuards configures the guards of a consumer channel. Zero values
+// disable the corresponding guard.
+type MessageGuards struct {
+	// MaxPayloadSize is the maximum size in bytes of the serialized payload.
+	MaxPayloadSize int
+	// RequiredHeaders are the headers every message must have.
+	RequiredHeaders []string
+	// AllowedRoutes are the only routes accepted by the consumer.
+	AllowedRoutes []string
+}
+
+// messageGuardInterceptor rejects the received messages violating the guards
+// of their consumer channel.
+type messageGuardInterceptor struct {
+	guards MessageGuards
+}
+
+// NewMessageGuardInterceptor creates an inbound interceptor rejecting the
+// messages violating the guards before they reach the action handlers.
+// Rejections are permanent errors wrapping a message.GuardError, so they are
+// not retried and are sent to the dead letter channel when configured.
+//
+// Parameters:
+//   - guards: the guards of the consumer channel
+//
+// Returns:
+//   - *messageGuardInterceptor: Configured interceptor instance
+func NewMessageGuardInterceptor(guards MessageGuards) *messageGuardInterceptor {
+	return &messageGuardInterceptor{guards: guards}
+}
+
+// Handle checks the message against the guards.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The received message
+//
+// Returns:
+//   - *message.Message: The message, unchanged
+//   - error: Permanent error wrapping a message.GuardError if a guard is
+//     violated
+func (h *messageGuardInterceptor) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if err := h.check(msg); err != nil {
+		return nil, NewPermanentError(fmt.Errorf(
+			"[message-guard] message %s rejected: %w",
+			msg.GetHeader().Get(message.HeaderMessageId),
+			err,
+		))
+	}
+	return msg, nil
+}
+
+// check returns the violation of the first guard failed by the message.
+func (h *messageGuardInterceptor) check(msg *message.Message) *message.GuardError {
+	if h.guards.MaxPayloadSize > 0 {
+		if size, ok := payloadSize(msg.GetPayload()); ok && size > h.guards.MaxPayloadSize {
+			return message.NewGuardError(
+				GuardMaxPayloadSize,
+				fmt.Sprintf(
+					"payload of %d bytes exceeds %d bytes",
+					size,
+					h.guards.MaxPayloadSize,
+				),
+			)
+		}
+	}
+
+	for _, header := range h.guards.RequiredHeaders {
+		if msg.GetHeader().Get(header) == "" {
+			return message.NewGuardError(
+				GuardRequiredHeaders,
+				fmt.Sprintf("header %s is missing", header),
+			)
+		}
+	}
+
+	route := msg.GetHeader().Get(message.HeaderRoute)
+	if len(h.guards.AllowedRoutes) > 0 && !slices.Contains(h.guards.AllowedRoutes, route) {
+		return message.NewGuardError(
+			GuardAllowedRoutes,
+			fmt.Sprintf("route %s is not allowed", route),
+		)
+	}
+	return nil
+}
+
+// payloadSize returns the size of a serialized payload, as received from a
+// broker.
+func payloadSize(payload any) (int, bool) {
+	switch value := payload.(type) {
+	case []byte:
+		return len(value), true
+	case string:
+		return len(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestMessageGuardInterceptor_Handle(t *testing.T) {
+	t.Parallel()
+	interceptor := handler.NewMessageGuardInterceptor(handler.MessageGuards{
+		MaxPayloadSize:  16,
+		RequiredHeaders: []string{message.HeaderCorrelationId},
+		AllowedRoutes:   []string{"createUser"},
+	})
+
+	cases := []struct {
+		description string
+		route       string
+		payload     any
+		headers     map[string]string
+		guard       string
+	}{
+		{"accepted message", "createUser", []byte(`{"id":1}`), map[string]string{message.HeaderCorrelationId: "c1"}, ""},
+		{"payload too large", "createUser", []byte(`{"name":"a very long name"}`), map[string]string{message.HeaderCorrelationId: "c1"}, handler.GuardMaxPayloadSize},
+		{"missing required header", "createUser", []byte(`{"id":1}`), nil, handler.GuardRequiredHeaders},
+		{"route not allowed", "deleteUser", []byte(`{"id":1}`), map[string]string{message.HeaderCorrelationId: "c1"}, handler.GuardAllowedRoutes},
+		{"decoded payload is not measured", "createUser", struct{ Name string }{"a very long name"}, map[string]string{message.HeaderCorrelationId: "c1"}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			t.Parallel()
+			builder := message.NewMessageBuilder().
+				WithRoute(c.route).
+				WithPayload(c.payload)
+			for key, value := range c.headers {
+				builder.WithCustomHeader(key, value)
+			}
+			msg := builder.Build()
+
+			result, err := interceptor.Handle(context.Background(), msg)
+			if c.guard == "" {
+				if err != nil || result != msg {
+					t.Errorf("expected message accepted, got %v, %v", result, err)
+				}
+				return
+			}
+
+			var guardErr *message.GuardError
+			if !errors.As(err, &guardErr) || guardErr.Guard != c.guard {
+				t.Fatalf("expected %s guard error, got %v", c.guard, err)
+			}
+			if !errors.Is(err, message.ErrGuardViolation) {
+				t.Errorf("expected error wrapping ErrGuardViolation, got %v", err)
+			}
+			if !handler.IsPermanentError(err) {
+				t.Errorf("expected permanent error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The MessageHistoryInterceptor implementation supports:
+// - EIP Message History recording in the history header
+// - Custom components added to the before/after interceptors of a pipeline
+package handler
+
+import (
+	"context"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// messageHistoryInterceptor appends a component to the history of the
+// intercepted messages.
+type messageHistoryInterceptor struct {
+	component string
+	name      string
+}
+
+// NewMessageHistoryInterceptor creates an interceptor appending the given
+// component to the history header of every intercepted message, regardless
+// of message.EnableHistory.
+//
+// Parameters:
+//   - component: the kind of the component, e.g. "enricher"
+//   - name: the reference name of the component
+//
+// Returns:
+//   - message.MessageHandler: the history interceptor
+func NewMessageHistoryInterceptor(component string, name string) message.MessageHandler {
+	return &messageHistoryInterceptor{component: component, name: name}
+}
+
+// Handle appends the component to the message history.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the intercepted message
+//
+// Returns:
+//   - *message.Message: the message with the component in its history
+//   - error: always nil
+func (i *messageHistoryInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	message.AppendHistory(msg, i.component, i.name)
+	return msg, nil
+}
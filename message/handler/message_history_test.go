@@ -0,0 +1,27 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestMessageHistoryInterceptor(t *testing.T) {
+	t.Parallel()
+	interceptor := handler.NewMessageHistoryInterceptor("enricher", "customer")
+	msg := message.NewMessageBuilder().Build()
+
+	result, err := interceptor.Handle(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	history, err := message.GetHistory(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].Component != "enricher" || history[0].Name != "customer" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
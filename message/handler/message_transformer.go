@@ -0,0 +1,85 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The MessageTransformer implementation supports:
+// - EIP Message Translator normalizing external payloads to canonical ones
+// - Transformers keyed by message route, or applied to every message
+// - Chained transformers, executed in registration order
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// TransformFunc converts a message, e.g. from a legacy payload shape to the
+// canonical one expected by the handlers.
+type TransformFunc func(msg *message.Message) (*message.Message, error)
+
+// Transformer converts the messages of a route. An empty route applies it to
+// every message.
+type Transformer struct {
+	Route     string
+	Transform TransformFunc
+}
+
+// messageTransformer applies the transformers matching the route of the
+// intercepted messages.
+type messageTransformer struct {
+	transformers []Transformer
+}
+
+// NewMessageTransformer creates an interceptor converting the messages
+// through the transformers of their route, so legacy payload shapes are
+// normalized without touching the handler code.
+//
+// Parameters:
+//   - transformers: the transformers, executed in the given order
+//
+// Returns:
+//   - *messageTransformer: Configured interceptor instance
+func NewMessageTransformer(transformers ...Transformer) *messageTransformer {
+	return &messageTransformer{transformers: transformers}
+}
+
+// Handle converts the message through the transformers matching its route.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to be transformed
+//
+// Returns:
+//   - *message.Message: The transformed message
+//   - error: Error if a transformer fails
+func (h *messageTransformer) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	transformed := msg
+	for _, transformer := range h.transformers {
+		route := transformed.GetHeader().Get(message.HeaderRoute)
+		if transformer.Route != "" && transformer.Route != route {
+			continue
+		}
+
+		result, err := transformer.Transform(transformed)
+		if err != nil {
+			return msg, fmt.Errorf(
+				"[message-transformer] failed to transform route %s: %w",
+				route,
+				err,
+			)
+		}
+		if result == nil {
+			return msg, fmt.Errorf(
+				"[message-transformer] transformer of route %s returned no message",
+				route,
+			)
+		}
+		transformed = result
+	}
+
+	return transformed, nil
+}
@@ -0,0 +1,103 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type legacyCustomer struct {
+	FullName string
+}
+
+type canonicalCustomer struct {
+	Name string
+}
+
+func normalizeCustomer(msg *message.Message) (*message.Message, error) {
+	legacy, ok := msg.GetPayload().(*legacyCustomer)
+	if !ok {
+		return msg, nil
+	}
+	return message.NewMessageBuilderFromMessage(msg).
+		WithPayload(&canonicalCustomer{Name: legacy.FullName}).
+		Build(), nil
+}
+
+func TestMessageTransformer_Handle(t *testing.T) {
+	t.Parallel()
+	t.Run("should transform messages of the route", func(t *testing.T) {
+		t.Parallel()
+		transformer := handler.NewMessageTransformer(
+			handler.Transformer{Route: "customer.created", Transform: normalizeCustomer},
+		)
+		msg := message.NewMessageBuilder().
+			WithRoute("customer.created").
+			WithPayload(&legacyCustomer{FullName: "Ada"}).
+			Build()
+
+		result, err := transformer.Handle(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		customer, ok := result.GetPayload().(*canonicalCustomer)
+		if !ok || customer.Name != "Ada" {
+			t.Errorf("expected canonical payload, got %v", result.GetPayload())
+		}
+	})
+
+	t.Run("should keep messages of other routes", func(t *testing.T) {
+		t.Parallel()
+		transformer := handler.NewMessageTransformer(
+			handler.Transformer{Route: "customer.created", Transform: normalizeCustomer},
+		)
+		msg := message.NewMessageBuilder().
+			WithRoute("customer.deleted").
+			WithPayload(&legacyCustomer{FullName: "Ada"}).
+			Build()
+
+		result, err := transformer.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Errorf("expected message unchanged, got %v, %v", result, err)
+		}
+	})
+
+	t.Run("should chain transformers in registration order", func(t *testing.T) {
+		t.Parallel()
+		appendHeader := func(value string) handler.TransformFunc {
+			return func(msg *message.Message) (*message.Message, error) {
+				return message.NewMessageBuilderFromMessage(msg).
+					WithCustomHeader("steps", msg.GetHeader().Get("steps")+value).
+					Build(), nil
+			}
+		}
+		transformer := handler.NewMessageTransformer(
+			handler.Transformer{Transform: appendHeader("a")},
+			handler.Transformer{Route: "customer.created", Transform: appendHeader("b")},
+		)
+		msg := message.NewMessageBuilder().WithRoute("customer.created").Build()
+
+		result, err := transformer.Handle(context.Background(), msg)
+		if err != nil || result.GetHeader().Get("steps") != "ab" {
+			t.Errorf("expected steps ab, got %v, %v", result.GetHeader().Get("steps"), err)
+		}
+	})
+
+	t.Run("should return error when a transformer fails", func(t *testing.T) {
+		t.Parallel()
+		transformErr := errors.New("invalid payload")
+		transformer := handler.NewMessageTransformer(handler.Transformer{
+			Transform: func(msg *message.Message) (*message.Message, error) {
+				return nil, transformErr
+			},
+		})
+
+		_, err := transformer.Handle(context.Background(), message.NewMessageBuilder().Build())
+		if !errors.Is(err, transformErr) {
+			t.Errorf("expected transform error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,277 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The PoisonMessage implementation supports:
+// - Consecutive failure counting per message across redeliveries
+// - Message identification by message id or payload hash
+// - Bounded failure tracking, forgetting messages not failing again within a TTL
+// - Quarantine of poison messages in a dedicated channel, apart from the
+// dead letter flow
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/otel"
+)
+
+// defaultPoisonFailureTTL is how long the failures of a message are kept
+// without a new failure by default.
+const defaultPoisonFailureTTL = time.Hour
+
+// maxTrackedPoisonMessages is the maximum number of failing messages tracked
+// at once. Once reached, the expired failures are discarded, then the oldest.
+const maxTrackedPoisonMessages = 10000
+
+// PoisonMessageKeyExtractor extracts the key identifying a message across
+// redeliveries.
+type PoisonMessageKeyExtractor func(msg *message.Message) string
+
+// poisonMessageHandler implements poison message detection, quarantining
+// messages that keep failing across redeliveries.
+type poisonMessageHandler struct {
+	channel      message.PublisherChannel
+	handler      message.MessageHandler
+	maxFailures  int
+	keyExtractor PoisonMessageKeyExtractor
+	failureTTL   time.Duration
+	maxTracked   int
+	otelTrace    otel.OtelTrace
+	mu           sync.Mutex
+	failures     map[string]*poisonFailures
+}
+
+// poisonFailures are the consecutive failures of a message.
+type poisonFailures struct {
+	count    int
+	failedAt time.Time
+}
+
+// PoisonMessageKeyByMessageId identifies messages by their message id.
+//
+// Parameters:
+//   - msg: the message to identify
+//
+// Returns:
+//   - string: the message id
+func PoisonMessageKeyByMessageId(msg *message.Message) string {
+	return msg.GetHeader().Get(message.HeaderMessageId)
+}
+
+// PoisonMessageKeyByPayloadHash identifies messages by the SHA-256 hash of
+// their payload, for producers which do not keep the message id on
+// redeliveries.
+//
+// Parameters:
+//   - msg: the message to identify
+//
+// Returns:
+//   - string: the hex encoded payload hash, or empty if it cannot be encoded
+func PoisonMessageKeyByPayloadHash(msg *message.Message) string {
+	payload, ok := msg.GetPayload().([]byte)
+	if !ok {
+		var err error
+		payload, err = json.Marshal(msg.GetPayload())
+		if err != nil {
+			return ""
+		}
+	}
+	hash := sha256.Sum256(payload)
+	return hex.EncodeToString(hash[:])
+}
+
+// NewPoisonMessageHandler creates a new poison message handler that wraps an
+// existing message handler, quarantining messages after a number of
+// consecutive failures.
+//
+// Parameters:
+//   - channel: The publisher channel receiving the quarantined messages
+//   - maxFailures: Consecutive failures after which a message is quarantined
+//   - handler: The underlying message handler to wrap
+//
+// Returns:
+//   - *poisonMessageHandler: Configured poison message handler instance
+//   - error: Error if maxFailures is not positive
+func NewPoisonMessageHandler(
+	channel message.PublisherChannel,
+	maxFailures int,
+	handler message.MessageHandler,
+) (*poisonMessageHandler, error) {
+	if maxFailures <= 0 {
+		return nil, fmt.Errorf(
+			"[poison-message-handler] max failures must be greater than zero, got %d",
+			maxFailures,
+		)
+	}
+	return &poisonMessageHandler{
+		channel:      channel,
+		handler:      handler,
+		maxFailures:  maxFailures,
+		keyExtractor: PoisonMessageKeyByMessageId,
+		failureTTL:   defaultPoisonFailureTTL,
+		maxTracked:   maxTrackedPoisonMessages,
+		otelTrace:    otel.InitTrace("poison-message-handler"),
+		failures:     map[string]*poisonFailures{},
+	}, nil
+}
+
+// WithKeyExtractor sets how messages are identified across redeliveries.
+// Defaults to PoisonMessageKeyByMessageId.
+//
+// Parameters:
+//   - extractor: the key extractor
+//
+// Returns:
+//   - *poisonMessageHandler: handler instance for method chaining
+func (h *poisonMessageHandler) WithKeyExtractor(
+	extractor PoisonMessageKeyExtractor,
+) *poisonMessageHandler {
+	if extractor != nil {
+		h.keyExtractor = extractor
+	}
+	return h
+}
+
+// WithFailureTTL sets how long the failures of a message are kept without a
+// new failure. A message failing again after the TTL starts counting from
+// one. Defaults to one hour.
+//
+// Parameters:
+//   - ttl: the failure retention
+//
+// Returns:
+//   - *poisonMessageHandler: handler instance for method chaining
+func (h *poisonMessageHandler) WithFailureTTL(ttl time.Duration) *poisonMessageHandler {
+	if ttl > 0 {
+		h.failureTTL = ttl
+	}
+	return h
+}
+
+// Handle processes a message through the wrapped handler, counting its
+// consecutive failures. When the failures reach the limit, the message is
+// sent to the quarantine channel and reported as handled, so it is neither
+// redelivered nor sent to the dead letter channel.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to process
+//
+// Returns:
+//   - *message.Message: The resulting message from processing
+//   - error: Error if processing fails before the failure limit, or if the
+//     quarantine fails
+func (h *poisonMessageHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	key := h.keyExtractor(msg)
+	resultMessage, err := h.handler.Handle(ctx, msg)
+	if key == "" {
+		return resultMessage, err
+	}
+
+	if err == nil {
+		h.forget(key)
+		return resultMessage, nil
+	}
+	failures := h.recordFailure(key)
+
+	if failures < h.maxFailures {
+		return resultMessage, err
+	}
+
+	ctx, span := h.otelTrace.Start(
+		ctx,
+		"Send message to quarantine",
+		otel.WithMessagingSystemType(otel.MessageSystemTypeInternal),
+		otel.WithSpanOperation(otel.SpanOperationProcess),
+		otel.WithSpanKind(otel.SpanKindInternal),
+		otel.WithMessage(msg),
+	)
+	defer span.End()
+
+	quarantineMessage := message.NewMessageBuilderFromMessage(msg).
+		WithChannelName(h.channel.Name()).
+		WithContext(ctx).
+		WithCustomHeader(message.HeaderDeadLetterError, err.Error()).
+		WithCustomHeader(message.HeaderQuarantineFailures, strconv.Itoa(failures)).
+		Build()
+
+	if errQ := h.channel.Send(ctx, quarantineMessage); errQ != nil {
+		slog.Error("[poison-message-handler] failed to send message to quarantine",
+			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"reason", errQ.Error(),
+			"quarantineChannelName", h.channel.Name(),
+		)
+		span.Error(errQ, "[poison-message-handler] failed to send message to quarantine")
+		return resultMessage, err
+	}
+
+	h.forget(key)
+
+	slog.Warn("[poison-message-handler] sent message to quarantine",
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"failures", failures,
+		"reason", err.Error(),
+		"quarantineChannelName", h.channel.Name(),
+	)
+	span.Success("[poison-message-handler] sent message to quarantine")
+
+	return msg, nil
+}
+
+// recordFailure counts a failure of a message, returning its consecutive
+// failures. Failures older than the TTL are not consecutive.
+func (h *poisonMessageHandler) recordFailure(key string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	entry, ok := h.failures[key]
+	if !ok {
+		if len(h.failures) >= h.maxTracked {
+			h.evict(now)
+		}
+		entry = &poisonFailures{}
+		h.failures[key] = entry
+	} else if now.Sub(entry.failedAt) > h.failureTTL {
+		entry.count = 0
+	}
+	entry.count++
+	entry.failedAt = now
+	return entry.count
+}
+
+// forget discards the failures of a message.
+func (h *poisonMessageHandler) forget(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.failures, key)
+}
+
+// evict discards the expired failures, or the oldest ones when none has
+// expired. The caller holds the lock.
+func (h *poisonMessageHandler) evict(now time.Time) {
+	oldestKey, oldest := "", now
+	for key, entry := range h.failures {
+		if now.Sub(entry.failedAt) > h.failureTTL {
+			delete(h.failures, key)
+			continue
+		}
+		if entry.failedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.failedAt
+		}
+	}
+	if len(h.failures) >= h.maxTracked {
+		delete(h.failures, oldestKey)
+	}
+}
@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func newPoisonMessageHandler(
+	t *testing.T,
+	quarantine message.PublisherChannel,
+	maxFailures int,
+	inner message.MessageHandler,
+) message.MessageHandler {
+	t.Helper()
+	poison, err := handler.NewPoisonMessageHandler(quarantine, maxFailures, inner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return poison
+}
+
+func TestNewPoisonMessageHandler_RejectsNonPositiveMaxFailures(t *testing.T) {
+	t.Parallel()
+	for _, maxFailures := range []int{0, -1} {
+		_, err := handler.NewPoisonMessageHandler(
+			&mockPublisherChannel{},
+			maxFailures,
+			&countingMessageHandler{},
+		)
+		if err == nil {
+			t.Errorf("expected error for max failures %d", maxFailures)
+		}
+	}
+}
+
+func TestPoisonMessageHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should quarantine after max consecutive failures", func(t *testing.T) {
+		t.Parallel()
+		quarantine := &mockPublisherChannel{}
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		poison := newPoisonMessageHandler(t, quarantine, 3, inner)
+		msg := message.NewMessageBuilder().WithMessageId("poison-1").Build()
+
+		for i := range 2 {
+			if _, err := poison.Handle(ctx, msg); err == nil {
+				t.Fatalf("expected error on attempt %d", i+1)
+			}
+		}
+		if quarantine.sentMsg != nil {
+			t.Fatal("expected no quarantined message before the limit")
+		}
+
+		if _, err := poison.Handle(ctx, msg); err != nil {
+			t.Fatalf("expected nil error on quarantine, got %v", err)
+		}
+		if quarantine.sentMsg == nil {
+			t.Fatal("expected quarantined message")
+		}
+		if quarantine.sentMsg.GetHeader().Get(message.HeaderQuarantineFailures) != "3" {
+			t.Errorf("expected 3 failures header, got %s",
+				quarantine.sentMsg.GetHeader().Get(message.HeaderQuarantineFailures))
+		}
+	})
+
+	t.Run("should reset failures after success", func(t *testing.T) {
+		t.Parallel()
+		quarantine := &mockPublisherChannel{}
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		poison := newPoisonMessageHandler(t, quarantine, 2, inner)
+		msg := message.NewMessageBuilder().WithMessageId("poison-2").Build()
+
+		poison.Handle(ctx, msg)
+		inner.err = nil
+		poison.Handle(ctx, msg)
+		inner.err = errors.New("boom")
+		if _, err := poison.Handle(ctx, msg); err == nil {
+			t.Error("expected error after failures reset")
+		}
+		if quarantine.sentMsg != nil {
+			t.Error("expected no quarantined message")
+		}
+	})
+
+	t.Run("should identify messages by payload hash", func(t *testing.T) {
+		t.Parallel()
+		quarantine := &mockPublisherChannel{}
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		poison, _ := handler.NewPoisonMessageHandler(quarantine, 2, inner)
+		poison.WithKeyExtractor(handler.PoisonMessageKeyByPayloadHash)
+
+		poison.Handle(ctx, message.NewMessageBuilder().WithPayload([]byte("a")).Build())
+		poison.Handle(ctx, message.NewMessageBuilder().WithPayload([]byte("a")).Build())
+		if quarantine.sentMsg == nil {
+			t.Error("expected quarantined message for the same payload")
+		}
+	})
+	t.Run("should forget failures older than the ttl", func(t *testing.T) {
+		t.Parallel()
+		quarantine := &mockPublisherChannel{}
+		inner := &countingMessageHandler{err: errors.New("boom")}
+		poison, _ := handler.NewPoisonMessageHandler(quarantine, 2, inner)
+		poison.WithFailureTTL(10 * time.Millisecond)
+		msg := message.NewMessageBuilder().WithMessageId("poison-3").Build()
+
+		poison.Handle(ctx, msg)
+		time.Sleep(20 * time.Millisecond)
+		if _, err := poison.Handle(ctx, msg); err == nil {
+			t.Error("expected error after the failures expired")
+		}
+		if quarantine.sentMsg != nil {
+			t.Error("expected no quarantined message")
+		}
+	})
+}
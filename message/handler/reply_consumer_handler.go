@@ -10,20 +10,27 @@
 // - Consumer channel integration
 // - Error handling and validation
 // - Context-aware message processing
+// - Reply timeouts, configured per handler or per request
 package handler
 
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 )
 
+// ErrReplyTimeout is returned, wrapped, when no reply is received within the
+// reply timeout.
+var ErrReplyTimeout = fmt.Errorf("[reply-consumer] reply %w", message.ErrTimeout)
+
 // replyConsumerHandler processes reply messages by receiving them from consumer
 // channels and handling the response appropriately.
 type replyConsumerHandler struct {
 	gomesContainer container.Container[any, any]
+	replyTimeout   time.Duration
 }
 
 // NewReplyConsumerHandler creates a new reply consumer handler instance.
@@ -36,6 +43,22 @@ func NewReplyConsumerHandler(container container.Container[any, any]) *replyCons
 	}
 }
 
+// WithReplyTimeout sets how long to wait for a reply. Zero means the wait is
+// bound only by the context. The replyTimeout header of a message, in Go
+// duration format (e.g. "1500ms"), overrides it for that request.
+//
+// Parameters:
+//   - timeout: the reply timeout
+//
+// Returns:
+//   - *replyConsumerHandler: handler instance for method chaining
+func (s *replyConsumerHandler) WithReplyTimeout(
+	timeout time.Duration,
+) *replyConsumerHandler {
+	s.replyTimeout = timeout
+	return s
+}
+
 // Handle processes reply messages by receiving them from the configured reply
 // channel and handling the response or error appropriately.
 //
@@ -45,7 +68,8 @@ func NewReplyConsumerHandler(container container.Container[any, any]) *replyCons
 //
 // Returns:
 //   - *message.Message: the reply message if successful
-//   - error: error if processing fails or reply channel is invalid
+//   - error: error if processing fails, reply channel is invalid or the reply
+//     timeout is reached (wrapping ErrReplyTimeout)
 func (s *replyConsumerHandler) Handle(
 	ctx context.Context,
 	msg *message.Message,
@@ -53,7 +77,7 @@ func (s *replyConsumerHandler) Handle(
 
 	channel := msg.GetInternalReplyChannel()
 	if channel == nil {
-		return nil, fmt.Errorf("reply channel not found")
+		return nil, fmt.Errorf("[reply-consumer] reply %w", message.ErrChannelNotFound)
 	}
 
 	replyChannel, ok := channel.(message.ConsumerChannel)
@@ -61,9 +85,37 @@ func (s *replyConsumerHandler) Handle(
 		return nil, fmt.Errorf("reply channel is not a consumer channel")
 	}
 
-	replyMessage, err := replyChannel.Receive(ctx)
+	timeout := s.replyTimeout
+	if value := msg.GetHeader().Get(message.HeaderReplyTimeout); value != "" {
+		requestTimeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[reply-consumer] invalid %s header: %w",
+				message.HeaderReplyTimeout,
+				err,
+			)
+		}
+		timeout = requestTimeout
+	}
+
+	receiveCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		receiveCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	replyMessage, err := replyChannel.Receive(receiveCtx)
 
 	if err != nil {
+		if ctx.Err() == nil && receiveCtx.Err() != nil {
+			return nil, fmt.Errorf(
+				"%w after %v, correlationId %s",
+				ErrReplyTimeout,
+				timeout,
+				msg.GetHeader().Get(message.HeaderCorrelationId),
+			)
+		}
 		return nil, err
 	}
 
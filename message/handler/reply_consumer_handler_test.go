@@ -83,7 +83,7 @@ func TestReplyConsumerHandler_Handle(t *testing.T) {
 		container := container.NewGenericContainer[any, any]()
 		h := handler.NewReplyConsumerHandler(container)
 		res, err := h.Handle(context.Background(), requestMessage)
-		if err.Error() != "reply channel not found" {
+		if !errors.Is(err, message.ErrChannelNotFound) {
 			t.Errorf("Expected error for nil channel, got: %v", err)
 		}
 		if res != nil {
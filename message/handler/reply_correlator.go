@@ -0,0 +1,140 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The ReplyCorrelator implementation supports:
+// - Request-reply over reply channels shared by many instances
+// - Replies filtered by the instance id header of their request
+// - Replies routed to the awaiting caller by the message id of their request
+// - Orphan reply detection for replies nobody awaits anymore
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// ReplyCorrelator routes the replies received from a shared reply channel to
+// the callers of this instance awaiting them. Outbound channels configured
+// with it stamp their requests with the instance id, and the consumer of the
+// shared reply channel uses it as its first before interceptor.
+type ReplyCorrelator struct {
+	instanceId    string
+	mu            sync.Mutex
+	waiting       map[string]chan *message.Message
+	orphanReplies atomic.Int64
+}
+
+// NewReplyCorrelator creates a new reply correlator.
+//
+// Parameters:
+//   - instanceId: the id of this instance, a random id is used when empty
+//
+// Returns:
+//   - *ReplyCorrelator: configured reply correlator
+func NewReplyCorrelator(instanceId string) *ReplyCorrelator {
+	if instanceId == "" {
+		instanceId = uuid.New().String()
+	}
+	return &ReplyCorrelator{
+		instanceId: instanceId,
+		waiting:    map[string]chan *message.Message{},
+	}
+}
+
+// InstanceId returns the id of this instance.
+//
+// Returns:
+//   - string: the instance id
+func (c *ReplyCorrelator) InstanceId() string {
+	return c.instanceId
+}
+
+// OrphanReplies returns the number of replies addressed to this instance
+// which nobody awaited.
+//
+// Returns:
+//   - int64: number of orphan replies
+func (c *ReplyCorrelator) OrphanReplies() int64 {
+	return c.orphanReplies.Load()
+}
+
+// Register starts awaiting the reply of a request. Replies reference their
+// request by their causation id, which stays unique when many concurrent
+// requests share the correlation id of a workflow.
+//
+// Parameters:
+//   - requestId: the message id of the request
+//
+// Returns:
+//   - <-chan *message.Message: receives the reply
+//   - func(): stops awaiting the reply, must be called once the caller is done
+func (c *ReplyCorrelator) Register(requestId string) (<-chan *message.Message, func()) {
+	replies := make(chan *message.Message, 1)
+	c.mu.Lock()
+	c.waiting[requestId] = replies
+	c.mu.Unlock()
+
+	return replies, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.waiting[requestId] == replies {
+			delete(c.waiting, requestId)
+		}
+	}
+}
+
+// Handle consumes the replies addressed to this instance, delivering them to
+// the caller awaiting the request given by their causation id, and discards
+// the replies of other instances.
+// Messages without the instance id header are passed on unchanged.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the received message
+//
+// Returns:
+//   - *message.Message: the message when it is not a correlated reply, nil
+//     otherwise
+//   - error: always nil
+func (c *ReplyCorrelator) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	instanceId := msg.GetHeader().Get(message.HeaderReplyInstanceId)
+	if instanceId == "" {
+		return msg, nil
+	}
+
+	requestId := msg.GetHeader().Get(message.HeaderCausationId)
+	if instanceId != c.instanceId {
+		slog.Debug("[reply-correlator] discarded reply of another instance",
+			"requestId", requestId,
+			"instanceId", instanceId,
+		)
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	replies, ok := c.waiting[requestId]
+	if ok {
+		delete(c.waiting, requestId)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		replies <- msg
+		return nil, nil
+	}
+
+	slog.Warn("[reply-correlator] orphan reply received, nobody awaits it",
+		"requestId", requestId,
+		"correlationId", msg.GetHeader().Get(message.HeaderCorrelationId),
+		"orphanRepliesTotal", c.orphanReplies.Add(1),
+	)
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func newCorrelatedReply(requestId, instanceId string) *message.Message {
+	return message.NewMessageBuilder().
+		WithMessageType(message.Document).
+		WithCorrelationId("workflow").
+		WithCustomHeader(message.HeaderCausationId, requestId).
+		WithCustomHeader(message.HeaderReplyInstanceId, instanceId).
+		WithPayload("reply").
+		Build()
+}
+
+func TestReplyCorrelator_Handle(t *testing.T) {
+	t.Run("delivers the reply to the awaiting caller", func(t *testing.T) {
+		t.Parallel()
+		correlator := handler.NewReplyCorrelator("instance-a")
+		replies, release := correlator.Register("request-1")
+		defer release()
+
+		reply := newCorrelatedReply("request-1", "instance-a")
+		result, err := correlator.Handle(context.Background(), reply)
+		if err != nil || result != nil {
+			t.Fatalf("expected the reply to be consumed, got %v, %v", result, err)
+		}
+		if got := <-replies; got != reply {
+			t.Error("expected the reply to be delivered")
+		}
+	})
+
+	t.Run("delivers the replies of requests sharing a correlation id", func(t *testing.T) {
+		t.Parallel()
+		correlator := handler.NewReplyCorrelator("instance-a")
+		first, releaseFirst := correlator.Register("request-1")
+		defer releaseFirst()
+		second, releaseSecond := correlator.Register("request-2")
+		defer releaseSecond()
+
+		secondReply := newCorrelatedReply("request-2", "instance-a")
+		firstReply := newCorrelatedReply("request-1", "instance-a")
+		correlator.Handle(context.Background(), secondReply)
+		correlator.Handle(context.Background(), firstReply)
+		if got := <-first; got != firstReply {
+			t.Error("expected the first request to receive its own reply")
+		}
+		if got := <-second; got != secondReply {
+			t.Error("expected the second request to receive its own reply")
+		}
+	})
+
+	t.Run("discards replies of other instances", func(t *testing.T) {
+		t.Parallel()
+		correlator := handler.NewReplyCorrelator("instance-a")
+		replies, release := correlator.Register("request-1")
+		defer release()
+
+		result, _ := correlator.Handle(
+			context.Background(),
+			newCorrelatedReply("request-1", "instance-b"),
+		)
+		if result != nil {
+			t.Error("expected the reply to be discarded")
+		}
+		select {
+		case <-replies:
+			t.Error("expected no reply to be delivered")
+		default:
+		}
+		if correlator.OrphanReplies() != 0 {
+			t.Errorf("expected no orphan replies, got %d", correlator.OrphanReplies())
+		}
+	})
+
+	t.Run("counts orphan replies", func(t *testing.T) {
+		t.Parallel()
+		correlator := handler.NewReplyCorrelator("instance-a")
+		_, release := correlator.Register("request-1")
+		release()
+
+		correlator.Handle(context.Background(), newCorrelatedReply("request-1", "instance-a"))
+		if correlator.OrphanReplies() != 1 {
+			t.Errorf("expected 1 orphan reply, got %d", correlator.OrphanReplies())
+		}
+	})
+
+	t.Run("passes uncorrelated messages on", func(t *testing.T) {
+		t.Parallel()
+		correlator := handler.NewReplyCorrelator("")
+		if correlator.InstanceId() == "" {
+			t.Fatal("expected a generated instance id")
+		}
+		msg := message.NewMessageBuilder().WithPayload("event").Build()
+		result, _ := correlator.Handle(context.Background(), msg)
+		if result != msg {
+			t.Error("expected the message to be passed on")
+		}
+	})
+}
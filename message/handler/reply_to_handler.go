@@ -6,26 +6,69 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"path"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
 	"github.com/jeffersonbrasilino/gomes/otel"
 )
 
+const (
+	// atomicReplyAttempts is the amount of attempts to publish an atomic reply.
+	atomicReplyAttempts = 3
+	// atomicReplyBackoff is the wait before the second attempt to publish an
+	// atomic reply, doubled on each further attempt.
+	atomicReplyBackoff = 50 * time.Millisecond
+)
+
 // ErrorResult represents an error response to be sent as a message payload.
 type ErrorResult struct {
 	// Result contains the error message string.
 	Result string `json:"error"`
 }
 
+// ReplyChannelFactory creates the publisher channel of a reply destination
+// which has no registered publisher channel.
+type ReplyChannelFactory func(
+	container container.Container[any, any],
+	channelName string,
+) (message.PublisherChannel, error)
+
 // SendReplyToHandler handles sending reply messages to the channel specified in
 // the original message's reply-to header, supporting asynchronous request-response
 // patterns.
 type SendReplyToHandler struct {
-	gomesContainer container.Container[any, any]
-	handler        message.MessageHandler
-	otelTrace      otel.OtelTrace
+	gomesContainer       container.Container[any, any]
+	handler              message.MessageHandler
+	otelTrace            otel.OtelTrace
+	responseChannelName  string
+	replyChannelFactory  ReplyChannelFactory
+	allowedReplyChannels []string
+	atomicReply          bool
+	mu                   sync.Mutex
+}
+
+// replyMessageIdNamespace is the namespace of the reply message ids derived
+// from the ids of their requests.
+var replyMessageIdNamespace = uuid.MustParse("3b0e9a4c-5f1d-4c8e-9a57-2d6f0b8e7c41")
+
+// ReplyMessageId returns the message id of the reply to a request, the same
+// for every delivery of the request, so the duplicated replies of redelivered
+// requests can be discarded by an idempotent receiver.
+//
+// Parameters:
+//   - requestMessageId: the message id of the request
+//
+// Returns:
+//   - string: the message id of the reply
+func ReplyMessageId(requestMessageId string) string {
+	return uuid.NewSHA1(replyMessageIdNamespace, []byte(requestMessageId)).String()
 }
 
 // NewSendReplyToHandler creates a new send reply-to handler that wraps an existing
@@ -48,8 +91,64 @@ func NewSendReplyToHandler(
 	}
 }
 
+// WithResponseChannelName sets the channel receiving the replies of messages
+// without a reply-to header, so the results of asynchronous commands are
+// reported even when their producers do not await them.
+//
+// Parameters:
+//   - channelName: the name of the response publisher channel
+//
+// Returns:
+//   - *SendReplyToHandler: handler instance for method chaining
+func (s *SendReplyToHandler) WithResponseChannelName(
+	channelName string,
+) *SendReplyToHandler {
+	s.responseChannelName = channelName
+	return s
+}
+
+// WithReplyChannelFactory creates the publisher channels of reply-to
+// destinations with no registered publisher channel on first use, registering
+// them in the container so later replies reuse them. Only destinations
+// matching one of the allowed patterns are created, so producers cannot make
+// the consumer publish to arbitrary channels.
+//
+// Parameters:
+//   - factory: creates the publisher channel of a reply destination
+//   - allowedPatterns: path.Match patterns of the destinations allowed to be
+//     created, e.g. "replies.*"; none allows no destination
+//
+// Returns:
+//   - *SendReplyToHandler: handler instance for method chaining
+func (s *SendReplyToHandler) WithReplyChannelFactory(
+	factory ReplyChannelFactory,
+	allowedPatterns ...string,
+) *SendReplyToHandler {
+	s.replyChannelFactory = factory
+	s.allowedReplyChannels = allowedPatterns
+	return s
+}
+
+// WithAtomicReply links the reply publishing to the acknowledgment of the
+// request: the reply is published before the request is acknowledged, a
+// failed publish is retried a few times with backoff and, when it keeps
+// failing, the request is left unacknowledged and its consumer stops, so the
+// broker redelivers the request and it is replied after its redelivery.
+// Replies carry an id
+// derived from the request id, see ReplyMessageId, so the replies duplicated
+// by a redelivery, e.g. after a crash between the reply and the
+// acknowledgment, can be discarded by the requester.
+//
+// Returns:
+//   - *SendReplyToHandler: handler instance for method chaining
+func (s *SendReplyToHandler) WithAtomicReply() *SendReplyToHandler {
+	s.atomicReply = true
+	return s
+}
+
 // Handle processes a message through the wrapped handler and sends the result to
-// the reply channel specified in the message's reply-to header. Errors during
+// the reply channel specified in the message's reply-to header, or to the
+// response channel when the header is not set. Errors during
 // processing are serialized and sent as ErrorResult payloads.
 //
 // Parameters:
@@ -65,6 +164,10 @@ func (s *SendReplyToHandler) Handle(
 ) (*message.Message, error) {
 
 	replyMessage, err := s.handler.Handle(ctx, msg)
+	if err == nil && replyMessage == nil {
+		// the message was dropped, e.g. by a message filter, so there is no reply
+		return nil, nil
+	}
 
 	ctx, span := s.otelTrace.Start(
 		ctx,
@@ -77,6 +180,9 @@ func (s *SendReplyToHandler) Handle(
 	defer span.End()
 
 	replyToChannelName := msg.GetHeader().Get(message.HeaderReplyTo)
+	if replyToChannelName == "" {
+		replyToChannelName = s.responseChannelName
+	}
 
 	if replyToChannelName == "" {
 		err := fmt.Errorf(
@@ -86,7 +192,7 @@ func (s *SendReplyToHandler) Handle(
 		return nil, err
 	}
 
-	replyChannel, errch := s.gomesContainer.Get(replyToChannelName)
+	replyChannel, errch := s.replyChannel(replyToChannelName)
 	if errch != nil {
 		span.Error(errch, "[send-reply-to-handler] failed to retrieve reply channel from container")
 		return nil, fmt.Errorf("[send-reply-to-handler] %v", errch.Error())
@@ -110,8 +216,12 @@ func (s *SendReplyToHandler) Handle(
 			WithChannelName(replyToChannelName).
 			WithPayload(&ErrorResult{err.Error()}).
 			Build()
+		addressReply(msg, rplMessage)
 
-		channel.Send(ctx, rplMessage)
+		if errS := s.sendReply(ctx, channel, msg, rplMessage); errS != nil {
+			span.Error(errS, "[send-reply-to-handler] failed to send error message to reply channel")
+			return nil, errS
+		}
 		span.Success("[send-reply-to-handler] sent error message to reply channel")
 
 		return nil, err
@@ -124,9 +234,17 @@ func (s *SendReplyToHandler) Handle(
 		).
 			WithPayload(&ErrorResult{payload.Error()}).
 			Build()
+	} else if rplMessage == msg {
+		// the request itself is the reply, so addressing it must not change
+		// the request headers
+		rplMessage = message.NewMessageBuilderFromMessage(msg).Build()
 	}
+	addressReply(msg, rplMessage)
 
-	channel.Send(ctx, rplMessage)
+	if errS := s.sendReply(ctx, channel, msg, rplMessage); errS != nil {
+		span.Error(errS, "[send-reply-to-handler] failed to send reply message to reply channel")
+		return nil, errS
+	}
 	span.Success("[send-reply-to-handler] sent reply message to reply channel")
 
 	if errorMessage, ok := replyMessage.GetPayload().(error); ok {
@@ -135,3 +253,139 @@ func (s *SendReplyToHandler) Handle(
 
 	return replyMessage, nil
 }
+
+// sendReply publishes the reply to a request. With atomic replies the reply
+// id is derived from the request id and a failed publish is retried with
+// backoff; when every attempt fails, the request is left unacknowledged and
+// the error is reported by IsReplyNotPublished.
+func (s *SendReplyToHandler) sendReply(
+	ctx context.Context,
+	channel message.PublisherChannel,
+	request *message.Message,
+	reply *message.Message,
+) error {
+	if !s.atomicReply {
+		channel.Send(ctx, reply)
+		return nil
+	}
+
+	if requestId := request.GetHeader().Get(message.HeaderMessageId); requestId != "" {
+		reply = message.NewMessageBuilderFromMessage(reply).
+			WithMessageId(ReplyMessageId(requestId)).
+			Build()
+	}
+	err := channel.Send(ctx, reply)
+retry:
+	for attempt := 1; err != nil && attempt < atomicReplyAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			break retry
+		case <-time.After(atomicReplyBackoff << (attempt - 1)):
+			err = channel.Send(ctx, reply)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	return NewPermanentError(&replyNotPublishedError{
+		err: fmt.Errorf("[send-reply-to-handler] failed to publish reply: %w", err),
+	})
+}
+
+// replyNotPublishedError is the error of a request whose atomic reply failed
+// to be published. The request is left unacknowledged to be redelivered once
+// its consumer stops, so it is neither retried nor dead-lettered.
+type replyNotPublishedError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error.
+func (e *replyNotPublishedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *replyNotPublishedError) Unwrap() error {
+	return e.err
+}
+
+// IsReplyNotPublished reports whether a processing error belongs to a request
+// whose atomic reply failed to be published. The request is left
+// unacknowledged and its consumer stops, so the broker redelivers it.
+//
+// Parameters:
+//   - err: the processing error
+//
+// Returns:
+//   - bool: true if the reply of the failed request was not published
+func IsReplyNotPublished(err error) bool {
+	var notPublished *replyNotPublishedError
+	return errors.As(err, &notPublished)
+}
+
+// replyChannel returns the registered channel of a reply destination,
+// creating it through the reply channel factory when not registered and
+// allowed.
+func (s *SendReplyToHandler) replyChannel(channelName string) (any, error) {
+	replyChannel, err := s.gomesContainer.Get(channelName)
+	if err == nil || s.replyChannelFactory == nil {
+		return replyChannel, err
+	}
+	if !s.allowedReplyChannel(channelName) {
+		return nil, fmt.Errorf(
+			"reply channel %s is not registered nor allowed to be created",
+			channelName,
+		)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if replyChannel, err := s.gomesContainer.Get(channelName); err == nil {
+		return replyChannel, nil
+	}
+	created, err := s.replyChannelFactory(s.gomesContainer, channelName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reply channel %s: %w", channelName, err)
+	}
+	if err := s.gomesContainer.Set(channelName, created); err != nil {
+		// registered meanwhile by another consumer
+		if closable, ok := created.(interface{ Close() error }); ok {
+			closable.Close()
+		}
+		return s.gomesContainer.Get(channelName)
+	}
+	if closable, ok := created.(interface{ Close() error }); ok {
+		s.gomesContainer.OnStop(channelName, func(ctx context.Context) error {
+			return closable.Close()
+		})
+	}
+
+	slog.Info("[send-reply-to-handler] reply channel created",
+		"channel", channelName,
+	)
+	return created, nil
+}
+
+// allowedReplyChannel reports whether a reply destination matches one of the
+// allowed patterns.
+func (s *SendReplyToHandler) allowedReplyChannel(channelName string) bool {
+	for _, pattern := range s.allowedReplyChannels {
+		if matched, _ := path.Match(pattern, channelName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// addressReply relates the reply to its request through the causation id and
+// addresses it to the instance awaiting it, so it can be correlated when the
+// reply channel is shared by many instances.
+func addressReply(request *message.Message, reply *message.Message) {
+	if requestId := request.GetHeader().Get(message.HeaderMessageId); requestId != "" {
+		reply.GetHeader().Set(message.HeaderCausationId, requestId)
+	}
+	instanceId := request.GetHeader().Get(message.HeaderReplyInstanceId)
+	if instanceId != "" {
+		reply.GetHeader().Set(message.HeaderReplyInstanceId, instanceId)
+	}
+}
@@ -34,6 +34,42 @@ func (m *replyTohandlerMock) Handle(
 	return responseMessage, nil
 }
 
+type replyPublisherMock struct {
+	err      error
+	failures int
+	sent     []*message.Message
+}
+
+func (m *replyPublisherMock) Name() string { return "replies" }
+
+func (m *replyPublisherMock) Send(_ context.Context, msg *message.Message) error {
+	m.sent = append(m.sent, msg)
+	if m.failures > 0 {
+		m.failures--
+		return fmt.Errorf("broker unavailable")
+	}
+	return m.err
+}
+
+type replyAcknowledgerMock struct {
+	settled bool
+}
+
+func (m *replyAcknowledgerMock) Ack() error {
+	m.settled = true
+	return nil
+}
+
+func (m *replyAcknowledgerMock) Nack(bool) error {
+	m.settled = true
+	return nil
+}
+
+func (m *replyAcknowledgerMock) Reject() error {
+	m.settled = true
+	return nil
+}
+
 func TestReplyToHandler_Handle(t *testing.T) {
 	t.Run("should be reply to success", func(t *testing.T) {
 		t.Parallel()
@@ -69,6 +105,37 @@ func TestReplyToHandler_Handle(t *testing.T) {
 
 	})
 
+	t.Run("should reply to the response channel without reply-to", func(t *testing.T) {
+		t.Parallel()
+
+		responseChannel := channel.NewPointToPointChannel("gomes.response")
+		defer responseChannel.Close()
+
+		container := container.NewGenericContainer[any, any]()
+		container.Set("gomes.response", responseChannel)
+
+		reqMessage := message.NewMessageBuilder().
+			WithPayload("request").
+			Build()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		replies := make(chan *message.Message, 1)
+		go func() {
+			reply, _ := responseChannel.Receive(ctx)
+			replies <- reply
+		}()
+		got := handler.NewSendReplyToHandler(&replyTohandlerMock{}, container).
+			WithResponseChannelName("gomes.response")
+		if _, err := got.Handle(ctx, reqMessage); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if reply := <-replies; reply == nil || reply.GetPayload() != "response" {
+			t.Fatalf("expected reply on the response channel, got %v", reply)
+		}
+	})
+
 	t.Run("should be channel not specified", func(t *testing.T) {
 		t.Parallel()
 
@@ -205,4 +272,148 @@ func TestReplyToHandler_Handle(t *testing.T) {
 		}
 	})
 
+	t.Run("should create allowed reply channels on first use", func(t *testing.T) {
+		t.Parallel()
+
+		created := channel.NewPointToPointChannel("replies.client-1")
+		defer created.Close()
+		factoryCalls := 0
+		factory := func(
+			_ container.Container[any, any],
+			channelName string,
+		) (message.PublisherChannel, error) {
+			factoryCalls++
+			return created, nil
+		}
+
+		replyContainer := container.NewGenericContainer[any, any]()
+		got := handler.NewSendReplyToHandler(&replyTohandlerMock{}, replyContainer).
+			WithReplyChannelFactory(factory, "replies.*")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		for range 2 {
+			go created.Receive(ctx)
+			reqMessage := message.NewMessageBuilder().
+				WithReplyTo("replies.client-1").
+				WithPayload("request").
+				Build()
+			if _, err := got.Handle(ctx, reqMessage); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		if factoryCalls != 1 {
+			t.Errorf("expected the reply channel created once, got %d", factoryCalls)
+		}
+		if !replyContainer.Has("replies.client-1") {
+			t.Error("expected the reply channel registered in the container")
+		}
+	})
+
+	t.Run("should not create reply channels out of the allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		factory := func(
+			_ container.Container[any, any],
+			channelName string,
+		) (message.PublisherChannel, error) {
+			t.Errorf("unexpected reply channel %s created", channelName)
+			return channel.NewPointToPointChannel(channelName), nil
+		}
+		got := handler.NewSendReplyToHandler(
+			&replyTohandlerMock{},
+			container.NewGenericContainer[any, any](),
+		).WithReplyChannelFactory(factory, "replies.*")
+
+		reqMessage := message.NewMessageBuilder().
+			WithReplyTo("payments.commands").
+			WithPayload("request").
+			Build()
+		if _, err := got.Handle(context.Background(), reqMessage); err == nil {
+			t.Fatal("expected error replying to a channel out of the allowlist")
+		}
+	})
+
+	t.Run("should derive atomic reply ids from the request id", func(t *testing.T) {
+		t.Parallel()
+
+		publisher := &replyPublisherMock{}
+		replyContainer := container.NewGenericContainer[any, any]()
+		replyContainer.Set("replies", publisher)
+		got := handler.NewSendReplyToHandler(&replyTohandlerMock{}, replyContainer).
+			WithAtomicReply()
+
+		reqMessage := message.NewMessageBuilder().
+			WithReplyTo("replies").
+			WithPayload("request").
+			Build()
+		for range 2 {
+			if _, err := got.Handle(context.Background(), reqMessage); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		requestId := reqMessage.GetHeader().Get(message.HeaderMessageId)
+		for _, reply := range publisher.sent {
+			replyId := reply.GetHeader().Get(message.HeaderMessageId)
+			if replyId != handler.ReplyMessageId(requestId) {
+				t.Errorf("expected reply id derived from the request id, got %s", replyId)
+			}
+			if causationId := reply.GetHeader().Get(message.HeaderCausationId); causationId != requestId {
+				t.Errorf("expected reply caused by the request, got %s", causationId)
+			}
+		}
+	})
+
+	t.Run("should retry the atomic reply when its publish fails", func(t *testing.T) {
+		t.Parallel()
+
+		publisher := &replyPublisherMock{failures: 1}
+		replyContainer := container.NewGenericContainer[any, any]()
+		replyContainer.Set("replies", publisher)
+		got := handler.NewSendReplyToHandler(&replyTohandlerMock{}, replyContainer).
+			WithAtomicReply()
+
+		reqMessage := message.NewMessageBuilder().
+			WithReplyTo("replies").
+			WithPayload("request").
+			Build()
+		if _, err := got.Handle(context.Background(), reqMessage); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(publisher.sent) != 2 {
+			t.Errorf("expected the reply published on the second attempt, got %d attempts", len(publisher.sent))
+		}
+	})
+
+	t.Run("should leave the request unacknowledged when the atomic reply fails", func(t *testing.T) {
+		t.Parallel()
+
+		publisher := &replyPublisherMock{err: fmt.Errorf("broker unavailable")}
+		replyContainer := container.NewGenericContainer[any, any]()
+		replyContainer.Set("replies", publisher)
+		got := handler.NewSendReplyToHandler(&replyTohandlerMock{}, replyContainer).
+			WithAtomicReply()
+
+		acknowledger := &replyAcknowledgerMock{}
+		ctx := message.ContextWithAcknowledger(context.Background(), acknowledger)
+		reqMessage := message.NewMessageBuilder().
+			WithReplyTo("replies").
+			WithPayload("request").
+			Build()
+		_, err := got.Handle(ctx, reqMessage)
+		if err == nil {
+			t.Fatal("expected error when the reply is not published")
+		}
+		if len(publisher.sent) != 3 {
+			t.Errorf("expected 3 publish attempts, got %d", len(publisher.sent))
+		}
+		if acknowledger.settled {
+			t.Error("expected the request left unacknowledged")
+		}
+		if !handler.IsReplyNotPublished(err) || !handler.IsPermanentError(err) {
+			t.Errorf("expected permanent reply not published error, got %v", err)
+		}
+	})
 }
@@ -8,16 +8,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/jeffersonbrasilino/gomes/message"
 )
 
 // retryHandler implements retry logic for failed message processing attempts,
-// allowing configurable delays between retry attempts.
+// delegating the retry decision and delays to a retry policy.
 type retryHandler struct {
-	handler      message.MessageHandler
-	attemptsTime []int
+	handler message.MessageHandler
+	policy  RetryPolicy
 }
 
 // NewRetryHandler creates a new retry handler that wraps an existing message
@@ -33,12 +34,29 @@ func NewRetryHandler(
 	attemptsTime []int,
 	handler message.MessageHandler,
 ) *retryHandler {
-	return &retryHandler{handler: handler, attemptsTime: attemptsTime}
+	return NewRetryHandlerWithPolicy(NewFixedRetryPolicy(attemptsTime...), handler)
+}
+
+// NewRetryHandlerWithPolicy creates a new retry handler that wraps an existing
+// message handler, retrying failures according to the given retry policy.
+//
+// Parameters:
+//   - policy: The retry policy deciding retries and delays
+//   - handler: The underlying message handler to wrap
+//
+// Returns:
+//   - *retryHandler: Configured retry handler instance
+func NewRetryHandlerWithPolicy(
+	policy RetryPolicy,
+	handler message.MessageHandler,
+) *retryHandler {
+	return &retryHandler{handler: handler, policy: policy}
 }
 
 // Handle processes a message through the wrapped handler with automatic retry on
-// failure. If processing fails, it retries with configured delay intervals until
-// success or all retries are exhausted.
+// failure. If processing fails, it retries while the retry policy allows it,
+// until success, a permanent error or all retries are exhausted. The number of
+// retries performed is recorded in the retryAttempts header.
 //
 // Parameters:
 //   - ctx: Context for timeout/cancellation control
@@ -56,26 +74,33 @@ func (h *retryHandler) Handle(
 		return resultMessage, nil
 	}
 
-	for k, attempt := range h.attemptsTime {
-		select {
-		case <-ctx.Done():
-			return msg, ctx.Err()
-		default:
+	for attempt := 1; ; attempt++ {
+		delay, retry := h.policy.NextDelay(attempt, err)
+		if !retry {
+			return resultMessage, err
 		}
 
 		slog.Info(
 			"[retry-handler] retrying process message after error",
 			"message.id",
 			msg.GetHeader().Get(message.HeaderMessageId),
-			"attempt", k+1,
+			"attempt", attempt,
 			"start.in",
-			fmt.Sprintf("%v milliseconds", attempt),
+			fmt.Sprintf("%v milliseconds", delay.Milliseconds()),
 		)
-		time.Sleep(time.Millisecond * time.Duration(attempt))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return msg, ctx.Err()
+		case <-timer.C:
+		}
+
+		msg.GetHeader().Set(message.HeaderRetryAttempts, strconv.Itoa(attempt))
 		resultMessage, err = h.handler.Handle(ctx, msg)
 		if err == nil {
 			return resultMessage, nil
 		}
 	}
-	return resultMessage, err
 }
@@ -0,0 +1,202 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline. It includes various handler implementations for
+// acknowledgment, retry logic, dead letter handling, and other message processing
+// concerns following the Enterprise Integration Patterns.
+package handler
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy decides whether a failed message processing attempt must be
+// retried and how long to wait before retrying.
+type RetryPolicy interface {
+	// NextDelay returns the delay before the given retry attempt (starting at
+	// 1) and whether the error is retried at all.
+	NextDelay(attempt int, err error) (time.Duration, bool)
+}
+
+// permanentError marks an error which must never be retried.
+type permanentError struct {
+	err error
+}
+
+// fixedRetryPolicy retries using a fixed list of delays, one per attempt.
+type fixedRetryPolicy struct {
+	delays []time.Duration
+}
+
+// ExponentialBackoffRetryPolicy retries with exponentially growing delays,
+// optionally randomized by jitter and capped by a maximum delay and a maximum
+// number of attempts.
+type ExponentialBackoffRetryPolicy struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	maxAttempts  int
+	multiplier   float64
+	jitter       float64
+	retryable    func(err error) bool
+}
+
+// NewPermanentError wraps an error so that no retry policy retries it.
+//
+// Parameters:
+//   - err: the error to be wrapped
+//
+// Returns:
+//   - error: the permanent error, or nil if err is nil
+func NewPermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanentError reports whether the error, or any error it wraps, was
+// marked as permanent.
+//
+// Parameters:
+//   - err: the error to check
+//
+// Returns:
+//   - bool: true if the error must not be retried
+func IsPermanentError(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Error returns the message of the wrapped error.
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// NewFixedRetryPolicy creates a retry policy with a fixed delay per attempt.
+// The number of attempts is the number of delays.
+//
+// Parameters:
+//   - delaysMilliseconds: retry delay intervals in milliseconds
+//
+// Returns:
+//   - RetryPolicy: the fixed retry policy
+func NewFixedRetryPolicy(delaysMilliseconds ...int) RetryPolicy {
+	delays := make([]time.Duration, len(delaysMilliseconds))
+	for i, delay := range delaysMilliseconds {
+		delays[i] = time.Duration(delay) * time.Millisecond
+	}
+	return &fixedRetryPolicy{delays: delays}
+}
+
+// NextDelay returns the configured delay for the attempt.
+func (p *fixedRetryPolicy) NextDelay(attempt int, err error) (time.Duration, bool) {
+	if IsPermanentError(err) || attempt < 1 || attempt > len(p.delays) {
+		return 0, false
+	}
+	return p.delays[attempt-1], true
+}
+
+// NewExponentialBackoffRetryPolicy creates a retry policy whose delay doubles
+// at every attempt, starting at initialDelay and never exceeding maxDelay.
+//
+// Parameters:
+//   - initialDelay: delay before the first retry
+//   - maxDelay: maximum delay between retries
+//   - maxAttempts: maximum number of retries
+//
+// Returns:
+//   - *ExponentialBackoffRetryPolicy: the exponential backoff retry policy
+func NewExponentialBackoffRetryPolicy(
+	initialDelay time.Duration,
+	maxDelay time.Duration,
+	maxAttempts int,
+) *ExponentialBackoffRetryPolicy {
+	return &ExponentialBackoffRetryPolicy{
+		initialDelay: initialDelay,
+		maxDelay:     maxDelay,
+		maxAttempts:  maxAttempts,
+		multiplier:   2,
+	}
+}
+
+// WithMultiplier sets the factor applied to the delay at every attempt.
+//
+// default value: 2
+//
+// Parameters:
+//   - multiplier: the delay growth factor
+//
+// Returns:
+//   - *ExponentialBackoffRetryPolicy: policy instance for method chaining
+func (p *ExponentialBackoffRetryPolicy) WithMultiplier(
+	multiplier float64,
+) *ExponentialBackoffRetryPolicy {
+	if multiplier >= 1 {
+		p.multiplier = multiplier
+	}
+	return p
+}
+
+// WithJitter randomizes the delays by the given fraction (0 to 1), spreading
+// retries of concurrent consumers. A jitter of 0.5 returns delays between 50%
+// and 100% of the computed backoff.
+//
+// default value: 0 (no jitter)
+//
+// Parameters:
+//   - jitter: the randomization fraction
+//
+// Returns:
+//   - *ExponentialBackoffRetryPolicy: policy instance for method chaining
+func (p *ExponentialBackoffRetryPolicy) WithJitter(
+	jitter float64,
+) *ExponentialBackoffRetryPolicy {
+	p.jitter = math.Min(math.Max(jitter, 0), 1)
+	return p
+}
+
+// WithRetryableErrors sets the function which classifies errors as retryable.
+// Errors for which it returns false are handled as permanent errors.
+//
+// Parameters:
+//   - retryable: the error classifier
+//
+// Returns:
+//   - *ExponentialBackoffRetryPolicy: policy instance for method chaining
+func (p *ExponentialBackoffRetryPolicy) WithRetryableErrors(
+	retryable func(err error) bool,
+) *ExponentialBackoffRetryPolicy {
+	p.retryable = retryable
+	return p
+}
+
+// NextDelay returns the exponential backoff delay for the attempt.
+func (p *ExponentialBackoffRetryPolicy) NextDelay(
+	attempt int,
+	err error,
+) (time.Duration, bool) {
+	if IsPermanentError(err) || attempt < 1 || attempt > p.maxAttempts {
+		return 0, false
+	}
+
+	if p.retryable != nil && !p.retryable(err) {
+		return 0, false
+	}
+
+	delay := float64(p.initialDelay) * math.Pow(p.multiplier, float64(attempt-1))
+	if p.maxDelay > 0 && delay > float64(p.maxDelay) {
+		delay = float64(p.maxDelay)
+	}
+
+	if p.jitter > 0 {
+		delay = delay*(1-p.jitter) + rand.Float64()*delay*p.jitter
+	}
+
+	return time.Duration(delay), true
+}
@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestPermanentError(t *testing.T) {
+	t.Parallel()
+	baseErr := errors.New("invalid payload")
+	err := fmt.Errorf("wrapped: %w", handler.NewPermanentError(baseErr))
+
+	if !handler.IsPermanentError(err) {
+		t.Error("expected error to be permanent")
+	}
+	if !errors.Is(err, baseErr) {
+		t.Error("expected permanent error to unwrap the original error")
+	}
+	if handler.IsPermanentError(baseErr) {
+		t.Error("expected plain error not to be permanent")
+	}
+	if handler.NewPermanentError(nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+}
+
+func TestFixedRetryPolicy_NextDelay(t *testing.T) {
+	t.Parallel()
+	policy := handler.NewFixedRetryPolicy(100, 200)
+	err := errors.New("failed")
+
+	if delay, ok := policy.NextDelay(2, err); !ok || delay != 200*time.Millisecond {
+		t.Errorf("expected 200ms retry, got %v %v", delay, ok)
+	}
+	if _, ok := policy.NextDelay(3, err); ok {
+		t.Error("expected no retry after attempts are exhausted")
+	}
+	if _, ok := policy.NextDelay(1, handler.NewPermanentError(err)); ok {
+		t.Error("expected no retry for permanent error")
+	}
+}
+
+func TestExponentialBackoffRetryPolicy_NextDelay(t *testing.T) {
+	err := errors.New("failed")
+
+	t.Run("should grow delays up to the max delay", func(t *testing.T) {
+		t.Parallel()
+		policy := handler.NewExponentialBackoffRetryPolicy(
+			100*time.Millisecond,
+			300*time.Millisecond,
+			4,
+		)
+		expected := []time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+			300 * time.Millisecond,
+			300 * time.Millisecond,
+		}
+		for i, want := range expected {
+			got, ok := policy.NextDelay(i+1, err)
+			if !ok || got != want {
+				t.Errorf("attempt %d: expected %v, got %v %v", i+1, want, got, ok)
+			}
+		}
+		if _, ok := policy.NextDelay(5, err); ok {
+			t.Error("expected no retry after max attempts")
+		}
+	})
+
+	t.Run("should apply jitter within bounds", func(t *testing.T) {
+		t.Parallel()
+		policy := handler.NewExponentialBackoffRetryPolicy(
+			100*time.Millisecond,
+			time.Second,
+			3,
+		).WithMultiplier(3).WithJitter(0.5)
+		for i := 0; i < 20; i++ {
+			got, ok := policy.NextDelay(2, err)
+			if !ok || got < 150*time.Millisecond || got > 300*time.Millisecond {
+				t.Fatalf("expected delay between 150ms and 300ms, got %v", got)
+			}
+		}
+	})
+
+	t.Run("should not retry non retryable errors", func(t *testing.T) {
+		t.Parallel()
+		policy := handler.NewExponentialBackoffRetryPolicy(
+			time.Millisecond,
+			time.Second,
+			3,
+		).WithRetryableErrors(func(err error) bool {
+			return err.Error() != "validation"
+		})
+		if _, ok := policy.NextDelay(1, errors.New("validation")); ok {
+			t.Error("expected no retry for non retryable error")
+		}
+		if _, ok := policy.NextDelay(1, err); !ok {
+			t.Error("expected retry for retryable error")
+		}
+	})
+}
+
+func TestRetryHandler_HandleWithPolicy(t *testing.T) {
+	msg := message.NewMessageBuilder().WithPayload("payload").Build()
+
+	t.Run("should stop retrying on permanent error", func(t *testing.T) {
+		t.Parallel()
+		handlerMock := &mockRetryMessageHandler{
+			shouldFail: true,
+			failErr:    handler.NewPermanentError(errors.New("invalid")),
+		}
+		rh := handler.NewRetryHandlerWithPolicy(
+			handler.NewExponentialBackoffRetryPolicy(time.Millisecond, time.Millisecond, 5),
+			handlerMock,
+		)
+		_, err := rh.Handle(context.Background(), msg)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+		if handlerMock.attempts != 1 {
+			t.Errorf("expected a single attempt, got %d", handlerMock.attempts)
+		}
+	})
+
+	t.Run("should retry until max attempts", func(t *testing.T) {
+		t.Parallel()
+		handlerMock := &mockRetryMessageHandler{
+			shouldFail: true,
+			failErr:    errors.New("temporary"),
+		}
+		rh := handler.NewRetryHandlerWithPolicy(
+			handler.NewExponentialBackoffRetryPolicy(time.Millisecond, time.Millisecond, 3),
+			handlerMock,
+		)
+		_, err := rh.Handle(context.Background(), msg)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+		if handlerMock.attempts != 4 {
+			t.Errorf("expected 4 attempts, got %d", handlerMock.attempts)
+		}
+	})
+
+	t.Run("should stop waiting when context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		rh := handler.NewRetryHandlerWithPolicy(
+			handler.NewFixedRetryPolicy(5000),
+			&mockRetryMessageHandler{shouldFail: true, failErr: errors.New("temporary")},
+		)
+		_, err := rh.Handle(ctx, msg)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The SourceTopicInterceptor implementation supports:
+// - Interceptors of multi-topic consumers applied to a single topic
+// - Topic matching through the sourceTopic header set by the adapters
+package handler
+
+import (
+	"context"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// sourceTopicInterceptor runs an interceptor only for the messages received
+// from a topic.
+type sourceTopicInterceptor struct {
+	topic       string
+	interceptor message.MessageHandler
+}
+
+// NewSourceTopicInterceptor creates an interceptor running the given one only
+// for the messages whose sourceTopic header matches the topic, passing the
+// other messages through unchanged.
+//
+// Parameters:
+//   - topic: The source topic of the intercepted messages
+//   - interceptor: The interceptor to run
+//
+// Returns:
+//   - *sourceTopicInterceptor: Configured interceptor instance
+func NewSourceTopicInterceptor(
+	topic string,
+	interceptor message.MessageHandler,
+) *sourceTopicInterceptor {
+	return &sourceTopicInterceptor{topic: topic, interceptor: interceptor}
+}
+
+// Handle runs the interceptor when the message comes from the topic.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The intercepted message
+//
+// Returns:
+//   - *message.Message: The resulting message
+//   - error: Error if the interceptor fails
+func (h *sourceTopicInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetHeader().Get(message.HeaderSourceTopic) != h.topic {
+		return msg, nil
+	}
+	return h.interceptor.Handle(ctx, msg)
+}
@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type countingInterceptor struct {
+	calls int
+}
+
+func (c *countingInterceptor) Handle(
+	_ context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	c.calls++
+	return msg, nil
+}
+
+func TestSourceTopicInterceptor_Handle(t *testing.T) {
+	t.Parallel()
+	t.Run("should intercept messages of the topic", func(t *testing.T) {
+		t.Parallel()
+		counter := &countingInterceptor{}
+		interceptor := handler.NewSourceTopicInterceptor("orders", counter)
+		msg := message.NewMessageBuilder().
+			WithCustomHeader(message.HeaderSourceTopic, "orders").
+			Build()
+
+		result, err := interceptor.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Fatalf("expected message unchanged, got %v, %v", result, err)
+		}
+		if counter.calls != 1 {
+			t.Errorf("expected interceptor to run once, got %d", counter.calls)
+		}
+	})
+
+	t.Run("should skip messages of other topics", func(t *testing.T) {
+		t.Parallel()
+		counter := &countingInterceptor{}
+		interceptor := handler.NewSourceTopicInterceptor("orders", counter)
+		msg := message.NewMessageBuilder().
+			WithCustomHeader(message.HeaderSourceTopic, "payments").
+			Build()
+
+		result, err := interceptor.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Fatalf("expected message unchanged, got %v, %v", result, err)
+		}
+		if counter.calls != 0 {
+			t.Errorf("expected interceptor not to run, got %d calls", counter.calls)
+		}
+	})
+}
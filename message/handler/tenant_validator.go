@@ -0,0 +1,94 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The TenantValidator implementation supports:
+// - Inbound validation of the tenant of the received messages
+// - Rejection of messages without tenant
+// - Tenant allowlists through AllowedTenants
+// - Custom validation, e.g. against the tenants registry
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// TenantValidator reports whether a consumer accepts the messages of a
+// tenant, returning an error for tenants it does not accept.
+type TenantValidator func(ctx context.Context, tenantId string) error
+
+// tenantValidationInterceptor rejects the messages of tenants not accepted by
+// the consumer.
+type tenantValidationInterceptor struct {
+	validate TenantValidator
+}
+
+// AllowedTenants creates a validator accepting only the given tenants.
+//
+// Parameters:
+//   - tenantIds: the accepted tenants
+//
+// Returns:
+//   - TenantValidator: the allowlist validator
+func AllowedTenants(tenantIds ...string) TenantValidator {
+	return func(ctx context.Context, tenantId string) error {
+		if !slices.Contains(tenantIds, tenantId) {
+			return fmt.Errorf("tenant %s is not allowed", tenantId)
+		}
+		return nil
+	}
+}
+
+// NewTenantValidationInterceptor creates an inbound interceptor rejecting
+// the messages without tenant and those whose tenant fails the validator, so
+// a consumer never processes messages of another tenant. Rejections wrap
+// message.ErrInvalidTenant.
+//
+// Parameters:
+//   - validate: the tenant validator, nil to only require a tenant
+//
+// Returns:
+//   - *tenantValidationInterceptor: Configured interceptor instance
+func NewTenantValidationInterceptor(
+	validate TenantValidator,
+) *tenantValidationInterceptor {
+	return &tenantValidationInterceptor{validate: validate}
+}
+
+// Handle validates the tenant of the message.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The received message
+//
+// Returns:
+//   - *message.Message: The message, unchanged
+//   - error: Error wrapping message.ErrInvalidTenant if the tenant is missing
+//     or not accepted
+func (h *tenantValidationInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	tenantId := msg.GetHeader().Get(message.HeaderTenantId)
+	if tenantId == "" {
+		return nil, fmt.Errorf(
+			"[tenant-validator] message %s has no tenant: %w",
+			msg.GetHeader().Get(message.HeaderMessageId),
+			message.ErrInvalidTenant,
+		)
+	}
+	if h.validate == nil {
+		return msg, nil
+	}
+	if err := h.validate(ctx, tenantId); err != nil {
+		return nil, fmt.Errorf(
+			"[tenant-validator] %w: %w",
+			message.ErrInvalidTenant,
+			err,
+		)
+	}
+	return msg, nil
+}
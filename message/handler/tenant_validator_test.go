@@ -0,0 +1,43 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestTenantValidationInterceptor_Handle(t *testing.T) {
+	t.Parallel()
+	interceptor := handler.NewTenantValidationInterceptor(handler.AllowedTenants("acme"))
+
+	cases := []struct {
+		description string
+		tenantId    string
+		expectError bool
+	}{
+		{"allowed tenant", "acme", false},
+		{"tenant not allowed", "globex", true},
+		{"missing tenant", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			t.Parallel()
+			msg := message.NewMessageBuilder().
+				WithCustomHeader(message.HeaderTenantId, c.tenantId).
+				Build()
+			result, err := interceptor.Handle(context.Background(), msg)
+			if c.expectError {
+				if !errors.Is(err, message.ErrInvalidTenant) {
+					t.Errorf("expected invalid tenant error, got %v", err)
+				}
+				return
+			}
+			if err != nil || result != msg {
+				t.Errorf("expected message accepted, got %v, %v", result, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The Unroutable implementation supports:
+// - Strict routing, treating messages without a registered handler as errors
+// - Routing of unroutable messages to a dedicated channel
+// - Unroutable message counting and tracing
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/router"
+	"github.com/jeffersonbrasilino/gomes/otel"
+)
+
+// unroutableHandler sends the messages whose route has no registered handler
+// to an unroutable channel.
+type unroutableHandler struct {
+	channel   message.PublisherChannel
+	handler   message.MessageHandler
+	otelTrace otel.OtelTrace
+	counter   atomic.Int64
+}
+
+// unroutableError marks an error of a message already sent to the unroutable
+// channel, so it is neither retried nor sent to the dead letter channel.
+type unroutableError struct {
+	err error
+}
+
+// NewUnroutableHandler creates a new unroutable handler that wraps an existing
+// message handler, sending unroutable messages to the given channel.
+//
+// Parameters:
+//   - channel: The publisher channel receiving the unroutable messages
+//   - handler: The underlying message handler to wrap
+//
+// Returns:
+//   - *unroutableHandler: Configured unroutable handler instance
+func NewUnroutableHandler(
+	channel message.PublisherChannel,
+	handler message.MessageHandler,
+) *unroutableHandler {
+	return &unroutableHandler{
+		channel:   channel,
+		handler:   handler,
+		otelTrace: otel.InitTrace("unroutable-handler"),
+	}
+}
+
+// Handle processes a message through the wrapped handler. When the message
+// has no registered handler for its route, it is sent to the unroutable
+// channel and a permanent error is returned, so the failure is surfaced
+// without being retried or dead-lettered.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The message to process
+//
+// Returns:
+//   - *message.Message: The resulting message from processing
+//   - error: Error if processing fails or the message is unroutable
+func (h *unroutableHandler) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	resultMessage, err := h.handler.Handle(ctx, msg)
+	if err == nil || !errors.Is(err, router.ErrUnroutable) {
+		return resultMessage, err
+	}
+
+	total := h.counter.Add(1)
+	route := msg.GetHeader().Get(message.HeaderRoute)
+
+	ctx, span := h.otelTrace.Start(
+		ctx,
+		"Send message to unroutable channel",
+		otel.WithMessagingSystemType(otel.MessageSystemTypeInternal),
+		otel.WithSpanOperation(otel.SpanOperationProcess),
+		otel.WithSpanKind(otel.SpanKindInternal),
+		otel.WithMessage(msg),
+	)
+	defer span.End()
+
+	unroutableMessage := message.NewMessageBuilderFromMessage(msg).
+		WithChannelName(h.channel.Name()).
+		WithContext(ctx).
+		WithCustomHeader(message.HeaderDeadLetterError, err.Error()).
+		Build()
+
+	if errS := h.channel.Send(ctx, unroutableMessage); errS != nil {
+		slog.Error("[unroutable-handler] failed to send message to unroutable channel",
+			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"route", route,
+			"reason", errS.Error(),
+			"unroutableChannelName", h.channel.Name(),
+		)
+		span.Error(errS, "[unroutable-handler] failed to send message to unroutable channel")
+		return resultMessage, fmt.Errorf("%w: %w", err, errS)
+	}
+
+	slog.Warn("[unroutable-handler] sent message to unroutable channel",
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"route", route,
+		"unroutableChannelName", h.channel.Name(),
+		"unroutableTotal", total,
+	)
+	span.AddEvent(
+		"unroutable message",
+		otel.NewOtelAttr("gomes.unroutable.total", fmt.Sprint(total)),
+	)
+	span.Success("[unroutable-handler] sent message to unroutable channel")
+
+	return resultMessage, NewPermanentError(&unroutableError{err: err})
+}
+
+// Unroutable returns the number of messages sent to the unroutable channel.
+//
+// Returns:
+//   - int64: number of unroutable messages
+func (h *unroutableHandler) Unroutable() int64 {
+	return h.counter.Load()
+}
+
+// Error returns the message of the wrapped error.
+func (e *unroutableError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *unroutableError) Unwrap() error {
+	return e.err
+}
+
+// isUnroutableError reports whether the error, or any error it wraps, belongs
+// to a message already sent to the unroutable channel.
+func isUnroutableError(err error) bool {
+	var unroutable *unroutableError
+	return errors.As(err, &unroutable)
+}
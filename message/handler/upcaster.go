@@ -0,0 +1,161 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The Upcaster implementation supports:
+// - Inbound conversion of payloads to the latest version of their schema
+// - Upcasters keyed by message route and version
+// - Chained upcasters, e.g. from version 1.0 to 2.0 and then to 3.0
+// - Typed upcasters decoding the raw payload of the older version
+// - Messages of routes without upcasters received unchanged
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// UpcastFunc converts a payload to the shape of a newer version.
+type UpcastFunc func(ctx context.Context, payload any) (any, error)
+
+// Upcaster converts the payloads of a route from one version to a newer one.
+type Upcaster struct {
+	Route       string
+	FromVersion string
+	ToVersion   string
+	Upcast      UpcastFunc
+}
+
+// upcastInterceptor converts inbound payloads to the latest version of their
+// route.
+type upcastInterceptor struct {
+	upcasters map[string]map[string]Upcaster
+}
+
+// NewUpcaster creates an upcaster from a typed conversion. The payload is
+// decoded as TFrom when it is still raw, e.g. received from a broker, and the
+// result is handed as TTo to the next upcaster or to the handler, so TTo
+// should be the type the handlers receive, e.g. a pointer to the action.
+//
+// Parameters:
+//   - route: the message route
+//   - fromVersion: the version converted from
+//   - toVersion: the version converted to
+//   - upcast: the conversion of the payload
+//
+// Returns:
+//   - Upcaster: the upcaster of the route and version
+func NewUpcaster[TFrom any, TTo any](
+	route string,
+	fromVersion string,
+	toVersion string,
+	upcast func(ctx context.Context, payload TFrom) (TTo, error),
+) Upcaster {
+	return Upcaster{
+		Route:       route,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Upcast: func(ctx context.Context, payload any) (any, error) {
+			from, ok := payload.(TFrom)
+			if !ok {
+				decoded, err := decodePayload(payload, &from)
+				if !decoded {
+					return nil, fmt.Errorf(
+						"unexpected payload %T, %w",
+						payload,
+						message.ErrTranslation,
+					)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", message.ErrTranslation, err)
+				}
+			}
+			return upcast(ctx, from)
+		},
+	}
+}
+
+// NewUpcastInterceptor creates an inbound interceptor converting the message
+// payloads to the latest version of their route, so handlers always receive
+// the latest schema while producers still publish older versions. The version
+// header of converted messages is set to the version reached.
+//
+// Parameters:
+//   - upcasters: the upcasters of each route and version
+//
+// Returns:
+//   - *upcastInterceptor: Configured interceptor instance
+func NewUpcastInterceptor(upcasters ...Upcaster) *upcastInterceptor {
+	byRoute := map[string]map[string]Upcaster{}
+	for _, upcaster := range upcasters {
+		if byRoute[upcaster.Route] == nil {
+			byRoute[upcaster.Route] = map[string]Upcaster{}
+		}
+		byRoute[upcaster.Route][upcaster.FromVersion] = upcaster
+	}
+	return &upcastInterceptor{upcasters: byRoute}
+}
+
+// Handle converts the message payload up to the latest version of its route.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - msg: The received message
+//
+// Returns:
+//   - *message.Message: The message in the latest version
+//   - error: Error if the upcasters of the route loop or an upcaster fails
+func (h *upcastInterceptor) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	route := msg.GetHeader().Get(message.HeaderRoute)
+	version := msg.GetHeader().Get(message.HeaderVersion)
+	upcasters := h.upcasters[route]
+	if _, ok := upcasters[version]; !ok {
+		return msg, nil
+	}
+
+	payload := msg.GetPayload()
+	visited := map[string]bool{}
+	for {
+		upcaster, ok := upcasters[version]
+		if !ok {
+			break
+		}
+		if visited[version] {
+			return nil, fmt.Errorf(
+				"[upcaster] upcasters of route %s loop at version %s",
+				route,
+				version,
+			)
+		}
+		visited[version] = true
+
+		var err error
+		if payload, err = upcaster.Upcast(ctx, payload); err != nil {
+			return nil, fmt.Errorf(
+				"[upcaster] failed to upcast route %s from version %s to %s: %w",
+				route,
+				upcaster.FromVersion,
+				upcaster.ToVersion,
+				err,
+			)
+		}
+		version = upcaster.ToVersion
+	}
+
+	slog.Debug("[upcaster] message upcast",
+		"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+		"route", route,
+		"fromVersion", msg.GetHeader().Get(message.HeaderVersion),
+		"toVersion", version,
+	)
+
+	return copyMessage(msg).
+		WithPayload(payload).
+		WithVersion(version).
+		Build(), nil
+}
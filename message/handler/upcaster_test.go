@@ -0,0 +1,109 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+type customerRegisteredV1 struct {
+	Name string `json:"name"`
+}
+
+type customerRegisteredV2 struct {
+	FirstName string `json:"firstName"`
+}
+
+type customerRegisteredV3 struct {
+	FirstName string `json:"firstName"`
+	Country   string `json:"country"`
+}
+
+func TestUpcastInterceptor_Handle(t *testing.T) {
+	t.Parallel()
+	interceptor := handler.NewUpcastInterceptor(
+		handler.NewUpcaster("customer.registered", "1.0", "2.0",
+			func(ctx context.Context, payload customerRegisteredV1) (*customerRegisteredV2, error) {
+				return &customerRegisteredV2{FirstName: payload.Name}, nil
+			},
+		),
+		handler.NewUpcaster("customer.registered", "2.0", "3.0",
+			func(ctx context.Context, payload *customerRegisteredV2) (*customerRegisteredV3, error) {
+				return &customerRegisteredV3{FirstName: payload.FirstName, Country: "BR"}, nil
+			},
+		),
+		handler.Upcaster{
+			Route:       "customer.failed",
+			FromVersion: "1.0",
+			ToVersion:   "2.0",
+			Upcast: func(ctx context.Context, payload any) (any, error) {
+				return nil, errors.New("invalid")
+			},
+		},
+		handler.Upcaster{
+			Route:       "customer.loop",
+			FromVersion: "1.0",
+			ToVersion:   "1.0",
+			Upcast: func(ctx context.Context, payload any) (any, error) {
+				return payload, nil
+			},
+		},
+	)
+
+	t.Run("should chain upcasters from the raw payload to the latest version", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().
+			WithRoute("customer.registered").
+			WithVersion("1.0").
+			WithPayload([]byte(`{"name":"Ana"}`)).
+			Build()
+		received, err := interceptor.Handle(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		payload, ok := received.GetPayload().(*customerRegisteredV3)
+		if !ok || payload.FirstName != "Ana" || payload.Country != "BR" {
+			t.Errorf("unexpected payload: %#v", received.GetPayload())
+		}
+		if version := received.GetHeader().Get(message.HeaderVersion); version != "3.0" {
+			t.Errorf("expected version 3.0, got %s", version)
+		}
+	})
+
+	t.Run("should keep messages in the latest version", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().
+			WithRoute("customer.registered").
+			WithVersion("3.0").
+			Build()
+		if received, _ := interceptor.Handle(context.Background(), msg); received != msg {
+			t.Error("expected message unchanged")
+		}
+	})
+
+	t.Run("should fail when the raw payload cannot be decoded", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().
+			WithRoute("customer.registered").
+			WithVersion("1.0").
+			WithPayload([]byte(`{"name":`)).
+			Build()
+		_, err := interceptor.Handle(context.Background(), msg)
+		if !errors.Is(err, message.ErrTranslation) {
+			t.Errorf("expected translation error, got %v", err)
+		}
+	})
+
+	t.Run("should fail when an upcaster fails or loops", func(t *testing.T) {
+		t.Parallel()
+		for _, route := range []string{"customer.failed", "customer.loop"} {
+			msg := message.NewMessageBuilder().WithRoute(route).WithVersion("1.0").Build()
+			if _, err := interceptor.Handle(context.Background(), msg); err == nil {
+				t.Errorf("expected error on route %s", route)
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+// Package handler provides message handlers for processing and intercepting messages
+// in the system's message pipeline.
+//
+// The WireTap implementation supports:
+// - EIP Wire Tap mirroring messages to an audit/inspection channel
+// - Asynchronous copies not affecting the main flow
+// - Sampling of a percentage of the messages
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// wireTap asynchronously copies the intercepted messages to a channel.
+type wireTap struct {
+	channel            message.PublisherChannel
+	samplingPercentage float64
+}
+
+// NewWireTap creates an interceptor copying the intercepted messages to the
+// given channel. Copies are sent in background and their failures are only
+// logged, so the main flow is never affected.
+//
+// Parameters:
+//   - channel: The publisher channel receiving the copies
+//   - samplingPercentage: The percentage of messages copied, from 0 to 100
+//
+// Returns:
+//   - message.MessageHandler: The wire tap interceptor
+func NewWireTap(
+	channel message.PublisherChannel,
+	samplingPercentage float64,
+) message.MessageHandler {
+	return &wireTap{channel: channel, samplingPercentage: samplingPercentage}
+}
+
+// Handle copies the message to the wire tap channel when sampled and returns
+// the message unchanged.
+//
+// Parameters:
+//   - ctx: Context of the main flow
+//   - msg: The intercepted message
+//
+// Returns:
+//   - *message.Message: The intercepted message
+//   - error: Always nil
+func (w *wireTap) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if !w.sampled() {
+		return msg, nil
+	}
+
+	tapCtx := context.WithoutCancel(ctx)
+	tapped := message.NewMessageBuilderFromMessage(msg).
+		WithContext(tapCtx).
+		Build()
+	go func() {
+		if err := w.channel.Send(tapCtx, tapped); err != nil {
+			slog.Error("[wire-tap] failed to copy message",
+				"channel", w.channel.Name(),
+				"messageId", tapped.GetHeader().Get(message.HeaderMessageId),
+				"reason", err.Error(),
+			)
+		}
+	}()
+	return msg, nil
+}
+
+// sampled reports whether the current message is copied.
+func (w *wireTap) sampled() bool {
+	if w.samplingPercentage >= 100 {
+		return true
+	}
+	return rand.Float64()*100 < w.samplingPercentage
+}
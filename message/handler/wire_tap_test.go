@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/channel"
+	"github.com/jeffersonbrasilino/gomes/message/handler"
+)
+
+func TestWireTap_Handle(t *testing.T) {
+	t.Parallel()
+	t.Run("should copy the message to the wire tap channel", func(t *testing.T) {
+		t.Parallel()
+		audit := channel.NewPointToPointChannel("audit")
+		wireTap := handler.NewWireTap(audit, 100)
+		msg := message.NewMessageBuilder().
+			WithMessageId("tapped").
+			WithPayload("payload").
+			Build()
+
+		result, err := wireTap.Handle(context.Background(), msg)
+		if err != nil || result != msg {
+			t.Fatalf("expected the message unchanged, got %v, %v", result, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		tapped, err := audit.Receive(ctx)
+		if err != nil {
+			t.Fatalf("expected copy, got %v", err)
+		}
+		if tapped == msg || tapped.GetHeader().Get(message.HeaderMessageId) != "tapped" {
+			t.Errorf("expected a copy of the message, got %v", tapped.GetHeader())
+		}
+	})
+
+	t.Run("should not copy messages out of the sample", func(t *testing.T) {
+		t.Parallel()
+		audit := channel.NewPointToPointChannel("audit")
+		wireTap := handler.NewWireTap(audit, 0)
+
+		wireTap.Handle(context.Background(), message.NewMessageBuilder().Build())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := audit.Receive(ctx); err == nil {
+			t.Error("expected no copy")
+		}
+	})
+
+	t.Run("should not affect the main flow when the copy fails", func(t *testing.T) {
+		t.Parallel()
+		audit := channel.NewPointToPointChannel("audit")
+		audit.Close()
+		wireTap := handler.NewWireTap(audit, 100)
+
+		if _, err := wireTap.Handle(context.Background(), message.NewMessageBuilder().Build()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
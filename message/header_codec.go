@@ -0,0 +1,331 @@
+// Package message provides typed custom header support for the message system.
+//
+// Header values travel as strings through every channel. The HeaderCodec
+// defines the stable string representation of typed values, so producers and
+// consumers in different services agree on how a typed header is encoded.
+//
+// The HeaderCodec implementation supports:
+// - Integer values encoded in base 10
+// - Float values encoded in the shortest exact decimal form
+// - Boolean values encoded as "true" or "false"
+// - Time values encoded as RFC3339 with nanoseconds in UTC
+// - String slices encoded as JSON arrays
+// - Decoded value caching per message
+// - Typed header accessors and setters
+// - Merge of typed custom headers in the message builder
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HeaderCodec defines the contract for encoding typed header values to their
+// string representation and decoding them back.
+type HeaderCodec interface {
+	Encode(value any) (string, error)
+	Decode(raw string, target any) error
+}
+
+// headerCodec is the default stable header codec.
+type headerCodec struct{}
+
+// decodedHeader holds a decoded header value along with the raw value it was
+// decoded from, so changes to the header invalidate the cached value.
+type decodedHeader struct {
+	raw   string
+	value any
+}
+
+// decodedHeaderCache holds the decoded header values of a message.
+type decodedHeaderCache struct {
+	mu     sync.Mutex
+	values map[string]decodedHeader
+}
+
+var defaultHeaderCodec HeaderCodec = &headerCodec{}
+
+// DefaultHeaderCodec returns the default stable header codec.
+//
+// Returns:
+//   - HeaderCodec: the default header codec
+func DefaultHeaderCodec() HeaderCodec {
+	return defaultHeaderCodec
+}
+
+// Encode converts a typed value to its stable string representation.
+//
+// Parameters:
+//   - value: the value to encode (string, integer, float, bool, time.Time,
+//     []string or []any)
+//
+// Returns:
+//   - string: the encoded value
+//   - error: error if the value type is not supported
+func (c *headerCodec) Encode(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano), nil
+	case []string:
+		if v == nil {
+			v = []string{}
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("[header-codec] cannot encode value: %w", err)
+		}
+		return string(data), nil
+	case []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("[header-codec] cannot encode value: %w", err)
+		}
+		return string(data), nil
+	}
+
+	return "", fmt.Errorf("[header-codec] unsupported header value type %T", value)
+}
+
+// Decode converts a string representation into the typed value pointed to by
+// target.
+//
+// Parameters:
+//   - raw: the encoded value
+//   - target: pointer to a string, int, int64, uint64, float64, bool,
+//     time.Time or []string
+//
+// Returns:
+//   - error: error if the value cannot be decoded into the target
+func (c *headerCodec) Decode(raw string, target any) error {
+	var err error
+	switch t := target.(type) {
+	case *string:
+		*t = raw
+	case *int:
+		var v int64
+		v, err = strconv.ParseInt(raw, 10, 0)
+		*t = int(v)
+	case *int64:
+		*t, err = strconv.ParseInt(raw, 10, 64)
+	case *uint64:
+		*t, err = strconv.ParseUint(raw, 10, 64)
+	case *float64:
+		*t, err = strconv.ParseFloat(raw, 64)
+	case *bool:
+		*t, err = strconv.ParseBool(raw)
+	case *time.Time:
+		*t, err = time.Parse(time.RFC3339Nano, raw)
+	case *[]string:
+		err = json.Unmarshal([]byte(raw), t)
+	default:
+		return fmt.Errorf("[header-codec] unsupported header target type %T", target)
+	}
+
+	if err != nil {
+		return fmt.Errorf("[header-codec] cannot decode value %q: %w", raw, err)
+	}
+	return nil
+}
+
+// HeaderValue returns the value of a custom header decoded as T using the
+// default header codec. Decoded values are cached in the message, so repeated
+// reads of an unchanged header do not decode it again.
+//
+// Parameters:
+//   - msg: the message holding the header
+//   - key: the header key
+//
+// Returns:
+//   - T: the decoded value
+//   - error: error if the header is missing or cannot be decoded as T
+func HeaderValue[T any](msg *Message, key string) (T, error) {
+	var value T
+	raw, ok := msg.GetHeader()[key]
+	if !ok {
+		return value, fmt.Errorf("[header-codec] header %s not found", key)
+	}
+
+	cache := msg.decodedHeaders
+	if cache == nil {
+		err := defaultHeaderCodec.Decode(raw, &value)
+		if err != nil {
+			return value, fmt.Errorf("[header-codec] header %s: %w", key, err)
+		}
+		return value, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cached, ok := cache.values[key]; ok && cached.raw == raw {
+		if typed, ok := cached.value.(T); ok {
+			return typed, nil
+		}
+	}
+
+	if err := defaultHeaderCodec.Decode(raw, &value); err != nil {
+		return value, fmt.Errorf("[header-codec] header %s: %w", key, err)
+	}
+
+	if cache.values == nil {
+		cache.values = map[string]decodedHeader{}
+	}
+	cache.values[key] = decodedHeader{raw: raw, value: value}
+	return value, nil
+}
+
+// CustomHeaders holds typed custom header values, encoded with the default
+// header codec when merged into a message builder.
+type CustomHeaders map[string]any
+
+// GetInt returns the value of a header decoded as an integer.
+//
+// Parameters:
+//   - key: the header key
+//
+// Returns:
+//   - int64: the decoded value
+//   - error: error if the header is missing or is not an integer
+func (h Header) GetInt(key string) (int64, error) {
+	var value int64
+	return value, h.decode(key, &value)
+}
+
+// GetFloat returns the value of a header decoded as a float.
+//
+// Parameters:
+//   - key: the header key
+//
+// Returns:
+//   - float64: the decoded value
+//   - error: error if the header is missing or is not a float
+func (h Header) GetFloat(key string) (float64, error) {
+	var value float64
+	return value, h.decode(key, &value)
+}
+
+// GetBool returns the value of a header decoded as a boolean.
+//
+// Parameters:
+//   - key: the header key
+//
+// Returns:
+//   - bool: the decoded value
+//   - error: error if the header is missing or is not a boolean
+func (h Header) GetBool(key string) (bool, error) {
+	var value bool
+	return value, h.decode(key, &value)
+}
+
+// GetTime returns the value of a header decoded as a time.
+//
+// Parameters:
+//   - key: the header key
+//
+// Returns:
+//   - time.Time: the decoded value
+//   - error: error if the header is missing or is not a RFC3339 time
+func (h Header) GetTime(key string) (time.Time, error) {
+	var value time.Time
+	return value, h.decode(key, &value)
+}
+
+// SetInt sets a custom integer header. Restricted headers cannot be set
+// manually.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the integer value
+//
+// Returns:
+//   - error: error if the header key is restricted
+func (h Header) SetInt(key string, value int64) error {
+	return h.encode(key, value)
+}
+
+// SetFloat sets a custom float header. Restricted headers cannot be set
+// manually.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the float value
+//
+// Returns:
+//   - error: error if the header key is restricted
+func (h Header) SetFloat(key string, value float64) error {
+	return h.encode(key, value)
+}
+
+// SetBool sets a custom boolean header. Restricted headers cannot be set
+// manually.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the boolean value
+//
+// Returns:
+//   - error: error if the header key is restricted
+func (h Header) SetBool(key string, value bool) error {
+	return h.encode(key, value)
+}
+
+// SetTime sets a custom time header. Restricted headers cannot be set
+// manually.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the time value
+//
+// Returns:
+//   - error: error if the header key is restricted
+func (h Header) SetTime(key string, value time.Time) error {
+	return h.encode(key, value)
+}
+
+// decode decodes a header value into target with the default header codec.
+func (h Header) decode(key string, target any) error {
+	raw, ok := h[key]
+	if !ok {
+		return fmt.Errorf("[header-codec] header %s not found", key)
+	}
+	if err := defaultHeaderCodec.Decode(raw, target); err != nil {
+		return fmt.Errorf("[header-codec] header %s: %w", key, err)
+	}
+	return nil
+}
+
+// encode sets a header value encoded with the default header codec.
+func (h Header) encode(key string, value any) error {
+	raw, err := defaultHeaderCodec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("[header-codec] header %s: %w", key, err)
+	}
+	return h.Set(key, raw)
+}
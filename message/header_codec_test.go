@@ -0,0 +1,173 @@
+package message_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestHeaderCodec_EncodeDecode(t *testing.T) {
+	codec := message.DefaultHeaderCodec()
+	now := time.Date(2025, 3, 1, 10, 30, 0, 123, time.FixedZone("BRT", -3*3600))
+
+	t.Run("should encode typed values", func(t *testing.T) {
+		t.Parallel()
+		cases := []struct {
+			value any
+			want  string
+		}{
+			{42, "42"},
+			{int64(-7), "-7"},
+			{true, "true"},
+			{1.5, "1.5"},
+			{now, "2025-03-01T13:30:00.000000123Z"},
+			{[]string{"a", "b"}, `["a","b"]`},
+			{[]string(nil), `[]`},
+		}
+		for _, c := range cases {
+			got, err := codec.Encode(c.value)
+			if err != nil {
+				t.Fatalf("Expected nil error for %v, got: %v", c.value, err)
+			}
+			if got != c.want {
+				t.Errorf("Expected '%s', got '%s'", c.want, got)
+			}
+		}
+	})
+
+	t.Run("should fail on unsupported type", func(t *testing.T) {
+		t.Parallel()
+		if _, err := codec.Encode(struct{}{}); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		var target struct{}
+		if err := codec.Decode("x", &target); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+
+	t.Run("should decode typed values", func(t *testing.T) {
+		t.Parallel()
+		var number int
+		var flag bool
+		var when time.Time
+		var list []string
+		if err := codec.Decode("42", &number); err != nil || number != 42 {
+			t.Errorf("Expected 42, got %d (%v)", number, err)
+		}
+		if err := codec.Decode("true", &flag); err != nil || !flag {
+			t.Errorf("Expected true, got %v (%v)", flag, err)
+		}
+		if err := codec.Decode("2025-03-01T13:30:00.000000123Z", &when); err != nil || !when.Equal(now) {
+			t.Errorf("Expected %v, got %v (%v)", now, when, err)
+		}
+		if err := codec.Decode(`["a","b"]`, &list); err != nil || len(list) != 2 {
+			t.Errorf("Expected 2 items, got %v (%v)", list, err)
+		}
+		if err := codec.Decode("abc", &number); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestHeaderValue(t *testing.T) {
+	now := time.Now().UTC()
+	msg := message.NewMessageBuilder().
+		WithIntHeader("attempt", 3).
+		WithBoolHeader("replay", true).
+		WithTimeHeader("deadline", now).
+		WithStringsHeader("tags", []string{"vip", "br"}).
+		Build()
+
+	t.Run("should read typed headers", func(t *testing.T) {
+		attempt, err := message.HeaderValue[int64](msg, "attempt")
+		if err != nil || attempt != 3 {
+			t.Errorf("Expected 3, got %d (%v)", attempt, err)
+		}
+		replay, err := message.HeaderValue[bool](msg, "replay")
+		if err != nil || !replay {
+			t.Errorf("Expected true, got %v (%v)", replay, err)
+		}
+		deadline, err := message.HeaderValue[time.Time](msg, "deadline")
+		if err != nil || !deadline.Equal(now) {
+			t.Errorf("Expected %v, got %v (%v)", now, deadline, err)
+		}
+		tags, err := message.HeaderValue[[]string](msg, "tags")
+		if err != nil || len(tags) != 2 || tags[0] != "vip" {
+			t.Errorf("Expected [vip br], got %v (%v)", tags, err)
+		}
+	})
+
+	t.Run("should refresh cached value when header changes", func(t *testing.T) {
+		msg.GetHeader().Set("attempt", "4")
+		attempt, err := message.HeaderValue[int64](msg, "attempt")
+		if err != nil || attempt != 4 {
+			t.Errorf("Expected 4, got %d (%v)", attempt, err)
+		}
+	})
+
+	t.Run("should fail when header is missing", func(t *testing.T) {
+		if _, err := message.HeaderValue[int](msg, "missing"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestHeader_TypedAccessors(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	header := message.NewMessageBuilder().
+		WithCustomHeaders(message.CustomHeaders{
+			"attempt":  3,
+			"ratio":    0.25,
+			"replay":   true,
+			"deadline": now,
+			"tenant":   "acme",
+		}).
+		Build().
+		GetHeader()
+
+	t.Run("should read the typed values", func(t *testing.T) {
+		if attempt, err := header.GetInt("attempt"); err != nil || attempt != 3 {
+			t.Errorf("Expected 3, got %d (%v)", attempt, err)
+		}
+		if ratio, err := header.GetFloat("ratio"); err != nil || ratio != 0.25 {
+			t.Errorf("Expected 0.25, got %v (%v)", ratio, err)
+		}
+		if replay, err := header.GetBool("replay"); err != nil || !replay {
+			t.Errorf("Expected true, got %v (%v)", replay, err)
+		}
+		if deadline, err := header.GetTime("deadline"); err != nil || !deadline.Equal(now) {
+			t.Errorf("Expected %v, got %v (%v)", now, deadline, err)
+		}
+		if header.Get("tenant") != "acme" {
+			t.Errorf("Expected acme, got %s", header.Get("tenant"))
+		}
+	})
+
+	t.Run("should set the typed values", func(t *testing.T) {
+		header := message.NewHeader(nil)
+		if err := header.SetInt("attempt", 4); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := header.SetBool("replay", false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if header.Get("attempt") != "4" || header.Get("replay") != "false" {
+			t.Errorf("Unexpected encoded values: %v", header)
+		}
+		if err := header.SetTime(message.HeaderMessageId, now); err == nil {
+			t.Error("Expected error setting a restricted header")
+		}
+	})
+
+	t.Run("should fail on missing or invalid values", func(t *testing.T) {
+		if _, err := header.GetInt("missing"); err == nil {
+			t.Error("Expected error for a missing header")
+		}
+		if _, err := header.GetBool("tenant"); err == nil {
+			t.Error("Expected error for an invalid boolean")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// History components recorded by the message system.
+const (
+	HistoryComponentConsumer        = "consumer"
+	HistoryComponentRouter          = "router"
+	HistoryComponentHandler         = "handler"
+	HistoryComponentOutboundChannel = "outbound-channel"
+)
+
+// historyEnabled enables the history recording of the message system
+// components.
+var historyEnabled atomic.Bool
+
+// HistoryEntry is a component traversed by a message.
+type HistoryEntry struct {
+	// Component is the kind of the component, e.g. "consumer".
+	Component string `json:"component"`
+	// Name is the reference name of the component.
+	Name string `json:"name"`
+	// Timestamp is when the message reached the component.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EnableHistory enables the Message History recording: consumers, routers,
+// handlers and outbound channels append themselves to the history header of
+// the messages they process.
+func EnableHistory() {
+	historyEnabled.Store(true)
+}
+
+// DisableHistory disables the Message History recording.
+func DisableHistory() {
+	historyEnabled.Store(false)
+}
+
+// IsHistoryEnabled reports whether the Message History recording is enabled.
+//
+// Returns:
+//   - bool: true if the history is recorded
+func IsHistoryEnabled() bool {
+	return historyEnabled.Load()
+}
+
+// RecordHistory appends a component to the history header of the message
+// when the Message History recording is enabled.
+//
+// Parameters:
+//   - msg: the message traversing the component
+//   - component: the kind of the component
+//   - name: the reference name of the component
+func RecordHistory(msg *Message, component string, name string) {
+	if msg == nil || !IsHistoryEnabled() {
+		return
+	}
+	AppendHistory(msg, component, name)
+}
+
+// AppendHistory appends a component, timestamped now, to the history header
+// of the message. A malformed history header is replaced.
+//
+// Parameters:
+//   - msg: the message traversing the component
+//   - component: the kind of the component
+//   - name: the reference name of the component
+func AppendHistory(msg *Message, component string, name string) {
+	history, _ := GetHistory(msg)
+	history = append(history, HistoryEntry{
+		Component: component,
+		Name:      name,
+		Timestamp: time.Now().UTC(),
+	})
+	encoded, _ := json.Marshal(history)
+	msg.GetHeader().Set(HeaderHistory, string(encoded))
+}
+
+// GetHistory returns the components traversed by the message, in traversal
+// order.
+//
+// Parameters:
+//   - msg: the message
+//
+// Returns:
+//   - []HistoryEntry: the traversed components
+//   - error: error if the history header is malformed
+func GetHistory(msg *Message) ([]HistoryEntry, error) {
+	value := msg.GetHeader().Get(HeaderHistory)
+	if value == "" {
+		return nil, nil
+	}
+
+	history := []HistoryEntry{}
+	if err := json.Unmarshal([]byte(value), &history); err != nil {
+		return nil, fmt.Errorf("[message-history] malformed history header: %w", err)
+	}
+	return history, nil
+}
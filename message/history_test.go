@@ -0,0 +1,52 @@
+package message_test
+
+import (
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestRecordHistory(t *testing.T) {
+	msg := message.NewMessageBuilder().Build()
+
+	message.RecordHistory(msg, message.HistoryComponentConsumer, "orders")
+	if history, _ := message.GetHistory(msg); len(history) != 0 {
+		t.Fatalf("expected no history while disabled, got %v", history)
+	}
+
+	message.EnableHistory()
+	defer message.DisableHistory()
+	message.RecordHistory(msg, message.HistoryComponentConsumer, "orders")
+	message.RecordHistory(msg, message.HistoryComponentRouter, "createOrder")
+
+	history, err := message.GetHistory(msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+	if history[0].Component != message.HistoryComponentConsumer || history[1].Name != "createOrder" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+	if history[1].Timestamp.Before(history[0].Timestamp) {
+		t.Error("expected entries in traversal order")
+	}
+}
+
+func TestGetHistory_Malformed(t *testing.T) {
+	t.Parallel()
+	msg := message.NewMessageBuilder().
+		WithCustomHeader(message.HeaderHistory, "not-json").
+		Build()
+
+	if _, err := message.GetHistory(msg); err == nil {
+		t.Error("expected malformed history error")
+	}
+
+	message.AppendHistory(msg, "enricher", "customer")
+	history, err := message.GetHistory(msg)
+	if err != nil || len(history) != 1 {
+		t.Errorf("expected malformed history to be replaced, got %v, %v", history, err)
+	}
+}
@@ -0,0 +1,301 @@
+// Package message provides configurable JSON payload encoding for the message
+// system.
+//
+// Payloads are exchanged with external systems as JSON. The JSONEncoder lets
+// each channel tune that encoding, so interop partners with strict contracts
+// receive and send payloads in the shape they expect.
+//
+// The JSONEncoder implementation supports:
+// - Numbers decoded as json.Number, keeping large integers exact
+// - Rejection of unknown fields when decoding into structs
+// - Omission of empty values when encoding
+// - snake_case field naming for untagged struct fields
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FieldNaming defines how the field names of encoded payloads are written.
+type FieldNaming int8
+
+// Field naming constants.
+const (
+	FieldNamingDefault FieldNaming = iota
+	FieldNamingSnakeCase
+)
+
+// PayloadDecoder is implemented by payloads received from a channel that know
+// how to decode themselves, e.g. with the channel JSON encoder.
+type PayloadDecoder interface {
+	Decode(target any) error
+}
+
+// JSONEncoder encodes and decodes message payloads as JSON with configurable
+// options. The zero options behave like encoding/json.
+type JSONEncoder struct {
+	useNumber             bool
+	disallowUnknownFields bool
+	omitEmpty             bool
+	fieldNaming           FieldNaming
+}
+
+// JSONPayload is a received JSON payload decoded with the encoder of the
+// channel it was received from.
+type JSONPayload struct {
+	Data    []byte
+	Encoder *JSONEncoder
+}
+
+// NewJSONEncoder creates a new JSON encoder with the encoding/json defaults.
+//
+// Returns:
+//   - *JSONEncoder: configured encoder instance
+func NewJSONEncoder() *JSONEncoder {
+	return &JSONEncoder{}
+}
+
+// WithUseNumber decodes numbers into interface values as json.Number instead
+// of float64, so large integers are not rounded.
+//
+// Returns:
+//   - *JSONEncoder: encoder instance for method chaining
+func (e *JSONEncoder) WithUseNumber() *JSONEncoder {
+	e.useNumber = true
+	return e
+}
+
+// WithDisallowUnknownFields makes decoding into a struct fail when the payload
+// has fields the struct does not declare.
+//
+// Returns:
+//   - *JSONEncoder: encoder instance for method chaining
+func (e *JSONEncoder) WithDisallowUnknownFields() *JSONEncoder {
+	e.disallowUnknownFields = true
+	return e
+}
+
+// WithOmitEmpty drops the object fields holding empty values (null, false, 0,
+// empty strings, arrays and objects) when encoding, as the omitempty tag does.
+//
+// Returns:
+//   - *JSONEncoder: encoder instance for method chaining
+func (e *JSONEncoder) WithOmitEmpty() *JSONEncoder {
+	e.omitEmpty = true
+	return e
+}
+
+// WithFieldNaming sets how field names are written when encoding and read
+// when decoding. Names set by json struct tags are written as declared, so
+// payload types with tags should not be combined with a field naming.
+//
+// Parameters:
+//   - naming: the field naming
+//
+// Returns:
+//   - *JSONEncoder: encoder instance for method chaining
+func (e *JSONEncoder) WithFieldNaming(naming FieldNaming) *JSONEncoder {
+	e.fieldNaming = naming
+	return e
+}
+
+// Marshal encodes a value as JSON. When empty values are omitted or a field
+// naming is set, object fields are written in alphabetical order.
+//
+// Parameters:
+//   - value: the value to encode
+//
+// Returns:
+//   - []byte: the JSON encoding
+//   - error: error if the value cannot be encoded
+func (e *JSONEncoder) Marshal(value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil || (!e.omitEmpty && e.fieldNaming == FieldNamingDefault) {
+		return data, err
+	}
+
+	generic, err := decodeGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(e.rewrite(generic, snakeCase))
+}
+
+// Unmarshal decodes JSON data into the target.
+//
+// Parameters:
+//   - data: the JSON encoding
+//   - target: pointer to the value receiving the decoded data
+//
+// Returns:
+//   - error: error if the data is invalid or does not fit the target
+func (e *JSONEncoder) Unmarshal(data []byte, target any) error {
+	if e.fieldNaming == FieldNamingSnakeCase {
+		generic, err := decodeGeneric(data)
+		if err != nil {
+			return err
+		}
+		if data, err = json.Marshal(e.renameKeys(generic, lowerCamelCase)); err != nil {
+			return err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if e.useNumber {
+		decoder.UseNumber()
+	}
+	if e.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(target)
+}
+
+// Decode decodes the payload into the target with its encoder, or with the
+// encoding/json defaults when it has none.
+//
+// Parameters:
+//   - target: pointer to the value receiving the decoded payload
+//
+// Returns:
+//   - error: error if the payload is invalid or does not fit the target
+func (p JSONPayload) Decode(target any) error {
+	if p.Encoder == nil {
+		return json.Unmarshal(p.Data, target)
+	}
+	return p.Encoder.Unmarshal(p.Data, target)
+}
+
+// MarshalJSON writes the raw payload, so a received payload is forwarded
+// unchanged.
+//
+// Returns:
+//   - []byte: the JSON encoding
+//   - error: always nil
+func (p JSONPayload) MarshalJSON() ([]byte, error) {
+	if len(p.Data) == 0 {
+		return []byte("null"), nil
+	}
+	return p.Data, nil
+}
+
+func decodeGeneric(data []byte) (any, error) {
+	var generic any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// rewrite applies the field naming and empty value omission to a generic
+// JSON value.
+func (e *JSONEncoder) rewrite(value any, rename func(string) string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			item = e.rewrite(item, rename)
+			if e.omitEmpty && isEmptyJSONValue(item) {
+				continue
+			}
+			if e.fieldNaming == FieldNamingSnakeCase {
+				key = rename(key)
+			}
+			result[key] = item
+		}
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = e.rewrite(item, rename)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// renameKeys applies the rename function to every object key of a generic
+// JSON value.
+func (e *JSONEncoder) renameKeys(value any, rename func(string) string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[rename(key)] = e.renameKeys(item, rename)
+		}
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = e.renameKeys(item, rename)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func isEmptyJSONValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case json.Number:
+		number, err := strconv.ParseFloat(v.String(), 64)
+		return err == nil && number == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// snakeCase converts a field name such as OrderID or orderId to order_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) ||
+				(unicode.IsUpper(previous) && nextIsLower) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+// lowerCamelCase converts a snake_case field name such as order_id to orderId,
+// which encoding/json matches case-insensitively to the OrderId and OrderID
+// struct fields.
+func lowerCamelCase(name string) string {
+	parts := strings.Split(name, "_")
+	var builder strings.Builder
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i > 0 && builder.Len() > 0 {
+			runes := []rune(part)
+			runes[0] = unicode.ToUpper(runes[0])
+			part = string(runes)
+		}
+		builder.WriteString(part)
+	}
+	if builder.Len() == 0 {
+		return name
+	}
+	return builder.String()
+}
@@ -0,0 +1,85 @@
+package message_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+type jsonEncoderOrder struct {
+	OrderID    string
+	TotalItems int
+	Notes      string
+	Tags       []string
+}
+
+func TestJSONEncoder_Marshal(t *testing.T) {
+	t.Parallel()
+	order := jsonEncoderOrder{OrderID: "1", TotalItems: 2}
+
+	cases := []struct {
+		name    string
+		encoder *message.JSONEncoder
+		want    string
+	}{
+		{"default", message.NewJSONEncoder(), `{"OrderID":"1","TotalItems":2,"Notes":"","Tags":null}`},
+		{"omit empty", message.NewJSONEncoder().WithOmitEmpty(), `{"OrderID":"1","TotalItems":2}`},
+		{
+			"snake case",
+			message.NewJSONEncoder().WithOmitEmpty().WithFieldNaming(message.FieldNamingSnakeCase),
+			`{"order_id":"1","total_items":2}`,
+		},
+	}
+	for _, c := range cases {
+		data, err := c.encoder.Marshal(order)
+		if err != nil || string(data) != c.want {
+			t.Errorf("%s: expected %s, got %s (%v)", c.name, c.want, data, err)
+		}
+	}
+}
+
+func TestJSONEncoder_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode snake case fields", func(t *testing.T) {
+		t.Parallel()
+		encoder := message.NewJSONEncoder().WithFieldNaming(message.FieldNamingSnakeCase)
+		var order jsonEncoderOrder
+		err := encoder.Unmarshal([]byte(`{"order_id":"1","total_items":2}`), &order)
+		if err != nil || order.OrderID != "1" || order.TotalItems != 2 {
+			t.Errorf("expected decoded order, got %+v (%v)", order, err)
+		}
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		t.Parallel()
+		encoder := message.NewJSONEncoder().WithDisallowUnknownFields()
+		var order jsonEncoderOrder
+		if err := encoder.Unmarshal([]byte(`{"OrderID":"1","Unknown":true}`), &order); err == nil {
+			t.Error("expected error for unknown field")
+		}
+	})
+
+	t.Run("should keep large numbers exact", func(t *testing.T) {
+		t.Parallel()
+		encoder := message.NewJSONEncoder().WithUseNumber()
+		var payload map[string]any
+		encoder.Unmarshal([]byte(`{"id":9007199254740993}`), &payload)
+		if number, ok := payload["id"].(json.Number); !ok || number.String() != "9007199254740993" {
+			t.Errorf("expected exact json.Number, got %v", payload["id"])
+		}
+	})
+
+	t.Run("should decode bound payload", func(t *testing.T) {
+		t.Parallel()
+		payload := message.JSONPayload{
+			Data:    []byte(`{"order_id":"1"}`),
+			Encoder: message.NewJSONEncoder().WithFieldNaming(message.FieldNamingSnakeCase),
+		}
+		var order jsonEncoderOrder
+		if err := payload.Decode(&order); err != nil || order.OrderID != "1" {
+			t.Errorf("expected decoded order, got %+v (%v)", order, err)
+		}
+	})
+}
@@ -39,6 +39,48 @@ const (
 	HeaderMessageId     = "messageId"
 	HeaderReplyTo       = "replyTo"
 	HeaderVersion       = "version"
+	HeaderCausationId   = "causationId"
+	HeaderTenantId      = "tenantId"
+	HeaderRetryAttempts = "retryAttempts"
+	HeaderReplyTimeout  = "replyTimeout"
+	// Processing timeout of a message, overriding the consumer timeouts.
+	HeaderProcessingTimeout = "processingTimeout"
+	// Instance awaiting the reply of a request sent over a shared reply channel.
+	HeaderReplyInstanceId = "replyInstanceId"
+	// Splitter/aggregator sequence headers.
+	HeaderSequenceNumber = "sequenceNumber"
+	HeaderSequenceSize   = "sequenceSize"
+	// Reference of a payload kept in a claim-check blob store.
+	HeaderClaimCheck = "claimCheck"
+	// Time a delayed message is due, in RFC3339 format.
+	HeaderScheduledAt = "scheduledAt"
+	// Time a message expires, in RFC3339 format, and the mark of expired
+	// messages sent to a dead letter channel.
+	HeaderExpiresAt = "expiresAt"
+	HeaderExpired   = "expired"
+	// Processing priority of a message, higher values first.
+	HeaderPriority = "priority"
+	// Broker topic a message was received from, set by the inbound adapters.
+	HeaderSourceTopic = "sourceTopic"
+	// Compression of the serialized payload.
+	HeaderContentEncoding = "contentEncoding"
+	// Envelope encryption master key id and wrapped data key.
+	HeaderEncryptionKeyId  = "encryptionKeyId"
+	HeaderEncryptedDataKey = "encryptedDataKey"
+	// Dead letter failure metadata headers.
+	HeaderDeadLetterOriginalChannel = "dlqOriginalChannel"
+	HeaderDeadLetterError           = "dlqError"
+	HeaderDeadLetterHandler         = "dlqHandler"
+	HeaderDeadLetterAttempts        = "dlqAttempts"
+	HeaderDeadLetterFailedAt        = "dlqFailedAt"
+	// Stack trace of the panic which failed a dead lettered message.
+	HeaderDeadLetterStackTrace = "dlqStackTrace"
+	// Guard violated by a dead lettered message rejected by its consumer.
+	HeaderDeadLetterGuardFailure = "dlqGuardFailure"
+	// Consecutive failures of a quarantined poison message.
+	HeaderQuarantineFailures = "quarantineFailures"
+	// Components traversed by the message, see AppendHistory.
+	HeaderHistory = "history"
 )
 
 var restrictedHeaders = []string{
@@ -51,6 +93,14 @@ var restrictedHeaders = []string{
 // MessageType represents the type of a message in the system.
 type MessageType int8
 
+// Timestamp layouts of the timestamp header. RFC3339 with nanoseconds in UTC
+// is the canonical layout; the legacy layout, without timezone, is still
+// parsed from messages produced by older versions.
+const (
+	TimestampLayout       = time.RFC3339Nano
+	LegacyTimestampLayout = "2006-01-02 15:04:05"
+)
+
 // Header represents a map of header key-value pairs for message metadata.
 type Header map[string]string
 
@@ -87,6 +137,7 @@ type Message struct {
 	context              context.Context
 	rawMessage           any
 	internalreplyChannel PublisherChannel
+	decodedHeaders       *decodedHeaderCache
 }
 
 // NewHeader creates a new header with default values and custom attributes.
@@ -102,10 +153,12 @@ func NewHeader(attributes map[string]string) Header {
 		attributes = make(map[string]string)
 	}
 
-	attributes[HeaderMessageId] = uuid.New().String()
+	if val, ok := attributes[HeaderMessageId]; !ok || val == "" {
+		attributes[HeaderMessageId] = uuid.New().String()
+	}
 
 	if val, ok := attributes[HeaderTimestamp]; !ok || val == "" {
-		attributes[HeaderTimestamp] = time.Now().Format("2006-01-02 15:04:05")
+		attributes[HeaderTimestamp] = FormatTimestamp(time.Now())
 	}
 
 	if val, ok := attributes[HeaderOrigin]; !ok || val == "" {
@@ -164,6 +217,54 @@ func (h Header) All() map[string]string {
 	return headerCopy
 }
 
+// Timestamp returns the timestamp header as a time, accepting both the
+// canonical and the legacy layouts.
+//
+// Returns:
+//   - time.Time: the message timestamp
+//   - error: error if the header is missing or cannot be parsed
+func (h Header) Timestamp() (time.Time, error) {
+	value, ok := h[HeaderTimestamp]
+	if !ok {
+		return time.Time{}, fmt.Errorf("header %s not found", HeaderTimestamp)
+	}
+	return ParseTimestamp(value)
+}
+
+// FormatTimestamp formats a time in the canonical timestamp layout, RFC3339
+// with nanoseconds in UTC.
+//
+// Parameters:
+//   - value: the time to be formatted
+//
+// Returns:
+//   - string: the formatted timestamp
+func FormatTimestamp(value time.Time) string {
+	return value.UTC().Format(TimestampLayout)
+}
+
+// ParseTimestamp parses a timestamp in the canonical layout or, for messages
+// produced by older versions, in the legacy layout, which is read in the
+// local timezone it was written with.
+//
+// Parameters:
+//   - value: the timestamp to be parsed
+//
+// Returns:
+//   - time.Time: the parsed time
+//   - error: error if the value matches no timestamp layout
+func ParseTimestamp(value string) (time.Time, error) {
+	parsed, err := time.Parse(TimestampLayout, value)
+	if err == nil {
+		return parsed, nil
+	}
+	parsed, legacyErr := time.ParseInLocation(LegacyTimestampLayout, value, time.Local)
+	if legacyErr != nil {
+		return time.Time{}, err
+	}
+	return parsed, nil
+}
+
 // String returns the string representation of a MessageType.
 //
 // Returns:
@@ -196,9 +297,10 @@ func NewMessage(
 	header Header,
 ) *Message {
 	return &Message{
-		payload: payload,
-		header:  header,
-		context: context,
+		payload:        payload,
+		header:         header,
+		context:        context,
+		decodedHeaders: &decodedHeaderCache{},
 	}
 }
 
@@ -245,6 +347,46 @@ func (m *Message) ReplyRequired() bool {
 		m.header[HeaderMessageType] == Query.String()
 }
 
+// ExpiresAt returns the time the message expires.
+//
+// Returns:
+//   - time.Time: the expiration time
+//   - bool: false if the message has no valid expiration
+func (m *Message) ExpiresAt() (time.Time, bool) {
+	if m.header[HeaderExpiresAt] == "" {
+		return time.Time{}, false
+	}
+	expiresAt, err := HeaderValue[time.Time](m, HeaderExpiresAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
+
+// IsExpired reports whether the message expiration has passed.
+//
+// Returns:
+//   - bool: true if the message has expired, false if it has no expiration
+func (m *Message) IsExpired() bool {
+	expiresAt, ok := m.ExpiresAt()
+	return ok && !time.Now().Before(expiresAt)
+}
+
+// Priority returns the priority of the message.
+//
+// Returns:
+//   - int: the priority header value, zero when missing or invalid
+func (m *Message) Priority() int {
+	if m.header[HeaderPriority] == "" {
+		return 0
+	}
+	priority, err := HeaderValue[int](m, HeaderPriority)
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
 // SetRawMessage sets the raw message from the external source.
 //
 // Parameters:
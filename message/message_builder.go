@@ -12,6 +12,7 @@
 // - Context-aware message creation
 // - Reply channel and routing setup
 // - Custom header management
+// - Typed custom headers encoded with the default header codec
 package message
 
 import (
@@ -78,7 +79,7 @@ func NewMessageBuilderFromHeaders(headers map[string]string) (*MessageBuilder, e
 			return nil
 		},
 		"timestamp": func(value string) error {
-			dt, err := time.Parse("2006-01-02 15:04:05", value)
+			dt, err := ParseTimestamp(value)
 			if err != nil {
 				return err
 			}
@@ -249,7 +250,8 @@ func (b *MessageBuilder) WithMessageId(value string) *MessageBuilder {
 	return b
 }
 
-// WithTimestamp sets the timestamp for the message being built.
+// WithTimestamp sets the timestamp for the message being built, formatted as
+// RFC3339 with nanoseconds in UTC.
 //
 // Parameters:
 //   - value: the timestamp to be set for the message
@@ -257,7 +259,7 @@ func (b *MessageBuilder) WithMessageId(value string) *MessageBuilder {
 // Returns:
 //   - *MessageBuilder: builder instance for method chaining
 func (b *MessageBuilder) WithTimestamp(value time.Time) *MessageBuilder {
-	b.header[HeaderTimestamp] = value.Format("2006-01-02 15:04:05")
+	b.header[HeaderTimestamp] = FormatTimestamp(value)
 	return b
 }
 
@@ -285,6 +287,42 @@ func (b *MessageBuilder) WithVersion(value string) *MessageBuilder {
 	return b
 }
 
+// WithTTL sets the message to expire after the given time to live.
+// Consumers discard expired messages before invoking the handlers.
+//
+// Parameters:
+//   - ttl: the time to live of the message
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithTTL(ttl time.Duration) *MessageBuilder {
+	return b.WithExpiresAt(time.Now().Add(ttl))
+}
+
+// WithExpiresAt sets the time the message expires.
+//
+// Parameters:
+//   - value: the expiration time
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithExpiresAt(value time.Time) *MessageBuilder {
+	return b.WithTimeHeader(HeaderExpiresAt, value)
+}
+
+// WithPriority sets the processing priority of the message, higher values
+// first. Consumers using it as priority extractor and brokers with priority
+// queues process higher priority messages first.
+//
+// Parameters:
+//   - value: the message priority
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithPriority(value int) *MessageBuilder {
+	return b.WithIntHeader(HeaderPriority, int64(value))
+}
+
 // WithRawMessage sets raw message for the message being built.
 //
 // Parameters:
@@ -302,6 +340,83 @@ func (b *MessageBuilder) WithCustomHeader(key string, value string) *MessageBuil
 	return b
 }
 
+// WithIntHeader sets a custom integer header, encoded with the default
+// header codec.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the integer value
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithIntHeader(key string, value int64) *MessageBuilder {
+	b.header[key], _ = defaultHeaderCodec.Encode(value)
+	return b
+}
+
+// WithBoolHeader sets a custom boolean header, encoded with the default
+// header codec.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the boolean value
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithBoolHeader(key string, value bool) *MessageBuilder {
+	b.header[key], _ = defaultHeaderCodec.Encode(value)
+	return b
+}
+
+// WithTimeHeader sets a custom time header, encoded with the default header
+// codec.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the time value
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithTimeHeader(key string, value time.Time) *MessageBuilder {
+	b.header[key], _ = defaultHeaderCodec.Encode(value)
+	return b
+}
+
+// WithStringsHeader sets a custom string list header, encoded with the
+// default header codec.
+//
+// Parameters:
+//   - key: the header key
+//   - value: the string list value
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithStringsHeader(key string, value []string) *MessageBuilder {
+	b.header[key], _ = defaultHeaderCodec.Encode(value)
+	return b
+}
+
+// WithCustomHeaders merges typed custom headers into the message, encoding
+// every value with the default header codec, so consumers read them back
+// with the typed accessors whatever channel translates the message. Values
+// of types unsupported by the codec are encoded in their default format.
+//
+// Parameters:
+//   - headers: the typed custom headers
+//
+// Returns:
+//   - *MessageBuilder: builder instance for method chaining
+func (b *MessageBuilder) WithCustomHeaders(headers CustomHeaders) *MessageBuilder {
+	for key, value := range headers {
+		raw, err := defaultHeaderCodec.Encode(value)
+		if err != nil {
+			raw = fmt.Sprint(value)
+		}
+		b.header[key] = raw
+	}
+	return b
+}
+
 // Build constructs a new message instance with all configured properties.
 //
 // Returns:
@@ -84,7 +84,7 @@ func TestNewMessageBuilderFromHeaders(t *testing.T) {
 		if got != nil {
 			t.Error("NewMessageBuilderFromHeaders() should return nil for invalid timestamp")
 		}
-		if err.Error() != "[message-builder] header converter error: timestamp - parsing time \"invalid-timestamp\" as \"2006-01-02 15:04:05\": cannot parse \"invalid-timestamp\" as \"2006\"" {
+		if err.Error() != "[message-builder] header converter error: timestamp - parsing time \"invalid-timestamp\" as \"2006-01-02T15:04:05.999999999Z07:00\": cannot parse \"invalid-timestamp\" as \"2006\"" {
 			t.Errorf("NewMessageBuilderFromHeaders() returned unexpected error: %v", err)
 		}
 	})
@@ -228,7 +228,7 @@ func TestWithTimestamp(t *testing.T) {
 	t.Parallel()
 	timestamp := time.Now()
 	b := message.NewMessageBuilder().WithTimestamp(timestamp).Build()
-	if b.GetHeader().Get(message.HeaderTimestamp) != timestamp.Format("2006-01-02 15:04:05") {
+	if b.GetHeader().Get(message.HeaderTimestamp) != timestamp.UTC().Format(time.RFC3339Nano) {
 		t.Error("WithTimestamp did not set timestamp correctly")
 	}
 }
@@ -168,3 +168,87 @@ func TestMessage_InternalReplyChannel(t *testing.T) {
 		t.Error("Expected internal reply channel to be set, got nil")
 	}
 }
+
+func TestMessage_Expiration(t *testing.T) {
+	t.Parallel()
+	t.Run("should not expire messages without ttl", func(t *testing.T) {
+		t.Parallel()
+		msg := message.NewMessageBuilder().Build()
+		if _, ok := msg.ExpiresAt(); ok || msg.IsExpired() {
+			t.Error("expected message without expiration")
+		}
+	})
+
+	t.Run("should expire messages after their ttl", func(t *testing.T) {
+		t.Parallel()
+		alive := message.NewMessageBuilder().WithTTL(time.Minute).Build()
+		expired := message.NewMessageBuilder().WithTTL(-time.Second).Build()
+		if alive.IsExpired() {
+			t.Error("expected message within its ttl")
+		}
+		if !expired.IsExpired() {
+			t.Error("expected expired message")
+		}
+	})
+
+	t.Run("should keep the expiration time", func(t *testing.T) {
+		t.Parallel()
+		expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 6, time.UTC)
+		msg := message.NewMessageBuilder().WithExpiresAt(expiresAt).Build()
+		got, ok := msg.ExpiresAt()
+		if !ok || !got.Equal(expiresAt) {
+			t.Errorf("expected expiration %v, got %v", expiresAt, got)
+		}
+	})
+}
+
+func TestMessage_Priority(t *testing.T) {
+	t.Parallel()
+	if priority := message.NewMessageBuilder().Build().Priority(); priority != 0 {
+		t.Errorf("expected default priority 0, got %d", priority)
+	}
+	if priority := message.NewMessageBuilder().WithPriority(7).Build().Priority(); priority != 7 {
+		t.Errorf("expected priority 7, got %d", priority)
+	}
+}
+
+func TestHeader_Timestamp(t *testing.T) {
+	t.Parallel()
+	t.Run("should keep the timezone of the timestamp", func(t *testing.T) {
+		t.Parallel()
+		sentAt := time.Date(2030, 1, 2, 3, 4, 5, 6, time.FixedZone("BRT", -3*60*60))
+		msg := message.NewMessageBuilder().WithTimestamp(sentAt).Build()
+		if raw := msg.GetHeader().Get(message.HeaderTimestamp); raw != "2030-01-02T06:04:05.000000006Z" {
+			t.Errorf("expected RFC3339 timestamp in UTC, got %s", raw)
+		}
+		got, err := msg.GetHeader().Timestamp()
+		if err != nil || !got.Equal(sentAt) {
+			t.Errorf("expected timestamp %v, got %v (%v)", sentAt, got, err)
+		}
+	})
+
+	t.Run("should parse the legacy timestamp", func(t *testing.T) {
+		t.Parallel()
+		builder, err := message.NewMessageBuilderFromHeaders(map[string]string{
+			message.HeaderTimestamp: "2030-01-02 03:04:05",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := builder.Build().GetHeader().Timestamp()
+		expected := time.Date(2030, 1, 2, 3, 4, 5, 0, time.Local)
+		if err != nil || !got.Equal(expected) {
+			t.Errorf("expected timestamp %v, got %v (%v)", expected, got, err)
+		}
+	})
+
+	t.Run("should fail without a valid timestamp", func(t *testing.T) {
+		t.Parallel()
+		if _, err := (message.Header{}).Timestamp(); err == nil {
+			t.Error("expected error for a missing timestamp")
+		}
+		if _, err := message.ParseTimestamp("yesterday"); err == nil {
+			t.Error("expected error for an invalid timestamp")
+		}
+	})
+}
@@ -0,0 +1,267 @@
+// Package router provides message routing components for the message system.
+//
+// The Aggregator implementation supports:
+// - Collection of related messages grouped by split (causation) id
+// - Redelivered messages ignored by message id
+// - Completion by a fixed size or by the sequence size header
+// - Completion timeout, releasing incomplete groups
+// - Custom merge function building the aggregated payload
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// AggregateFunc defines the contract for message merge functions. It takes the
+// messages of a complete group, ordered by sequence number, and returns the
+// aggregated payload.
+type AggregateFunc func(msgs []*message.Message) (any, error)
+
+// aggregationGroup holds the messages collected for a group.
+type aggregationGroup struct {
+	messages   []*message.Message
+	messageIds map[string]struct{}
+	timer      *time.Timer
+}
+
+// aggregator implements the Aggregator pattern, collecting related messages
+// and sending a single aggregated message to an output channel.
+type aggregator struct {
+	gomesContainer    container.Container[any, any]
+	aggregateFunc     AggregateFunc
+	outputChannel     string
+	completionSize    int
+	completionTimeout time.Duration
+	mu                sync.Mutex
+	groups            map[string]*aggregationGroup
+}
+
+// NewAggregator creates a new aggregator instance. By default a group is
+// complete when it holds the number of messages in their sequence size header,
+// as set by the Splitter.
+//
+// Parameters:
+//   - gomesContainer: container for resolving the output channel
+//   - aggregateFunc: the function merging the messages of a complete group
+//   - outputChannel: the channel receiving the aggregated messages
+//
+// Returns:
+//   - *aggregator: configured aggregator
+func NewAggregator(
+	gomesContainer container.Container[any, any],
+	aggregateFunc AggregateFunc,
+	outputChannel string,
+) *aggregator {
+	return &aggregator{
+		gomesContainer: gomesContainer,
+		aggregateFunc:  aggregateFunc,
+		outputChannel:  outputChannel,
+		groups:         map[string]*aggregationGroup{},
+	}
+}
+
+// WithCompletionSize sets a fixed number of messages completing a group,
+// overriding the sequence size header.
+//
+// Parameters:
+//   - size: the number of messages of a complete group
+//
+// Returns:
+//   - *aggregator: aggregator instance for method chaining
+func (a *aggregator) WithCompletionSize(size int) *aggregator {
+	a.completionSize = size
+	return a
+}
+
+// WithCompletionTimeout sets the maximum time a group waits for its messages,
+// counted from its first message. On timeout the group is aggregated with the
+// messages received so far.
+//
+// Parameters:
+//   - timeout: the completion timeout
+//
+// Returns:
+//   - *aggregator: aggregator instance for method chaining
+func (a *aggregator) WithCompletionTimeout(timeout time.Duration) *aggregator {
+	a.completionTimeout = timeout
+	return a
+}
+
+// Handle adds a message to its group. The group is the split of the message,
+// given by the causation id the Splitter sets to the id of the split message,
+// so the splits of a workflow sharing a correlation id are aggregated apart.
+// Messages without a causation id are grouped by correlation id. A message
+// already in its group, e.g. a redelivery, is ignored. When the group is
+// complete, its messages are merged and the aggregated message is sent to the
+// output channel.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be aggregated
+//
+// Returns:
+//   - *message.Message: the aggregated message if the group is complete, nil
+//     otherwise
+//   - error: error if the message has neither causation nor correlation id,
+//     or if the group cannot be merged or sent
+func (a *aggregator) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	messageId := msg.GetHeader().Get(message.HeaderMessageId)
+	groupId := groupIdOf(msg)
+	if groupId == "" {
+		return nil, fmt.Errorf(
+			"[aggregator] message %s has no causation or correlation id",
+			messageId,
+		)
+	}
+
+	a.mu.Lock()
+	group, ok := a.groups[groupId]
+	if !ok {
+		group = &aggregationGroup{messageIds: map[string]struct{}{}}
+		a.groups[groupId] = group
+		if a.completionTimeout > 0 {
+			group.timer = time.AfterFunc(a.completionTimeout, func() {
+				a.expire(groupId, group)
+			})
+		}
+	}
+	if _, duplicated := group.messageIds[messageId]; duplicated && messageId != "" {
+		a.mu.Unlock()
+		slog.Warn("[aggregator] ignoring duplicated message",
+			"groupId", groupId,
+			"messageId", messageId,
+		)
+		return nil, nil
+	}
+	group.messageIds[messageId] = struct{}{}
+	group.messages = append(group.messages, msg)
+
+	if !a.isComplete(group) {
+		a.mu.Unlock()
+		return nil, nil
+	}
+
+	delete(a.groups, groupId)
+	if group.timer != nil {
+		group.timer.Stop()
+	}
+	a.mu.Unlock()
+
+	return a.release(ctx, group.messages)
+}
+
+// PendingGroups returns the number of incomplete groups.
+//
+// Returns:
+//   - int: the number of incomplete groups
+func (a *aggregator) PendingGroups() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.groups)
+}
+
+// groupIdOf returns the group of a message: its causation id, set by the
+// Splitter to the id of the split message, or its correlation id.
+func groupIdOf(msg *message.Message) string {
+	if causationId := msg.GetHeader().Get(message.HeaderCausationId); causationId != "" {
+		return causationId
+	}
+	return msg.GetHeader().Get(message.HeaderCorrelationId)
+}
+
+func (a *aggregator) isComplete(group *aggregationGroup) bool {
+	size := a.completionSize
+	if size <= 0 {
+		size, _ = strconv.Atoi(
+			group.messages[0].GetHeader().Get(message.HeaderSequenceSize),
+		)
+	}
+	return size > 0 && len(group.messages) >= size
+}
+
+// expire releases an incomplete group when its completion timeout elapses.
+func (a *aggregator) expire(groupId string, group *aggregationGroup) {
+	a.mu.Lock()
+	if a.groups[groupId] != group {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.groups, groupId)
+	a.mu.Unlock()
+
+	if _, err := a.release(context.Background(), group.messages); err != nil {
+		slog.Error("[aggregator] failed to release expired group",
+			"groupId", groupId,
+			"messages", len(group.messages),
+			"reason", err.Error(),
+		)
+	}
+}
+
+// release merges the messages of a group and sends the aggregated message to
+// the output channel.
+func (a *aggregator) release(
+	ctx context.Context,
+	messages []*message.Message,
+) (*message.Message, error) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return sequenceNumber(messages[i]) < sequenceNumber(messages[j])
+	})
+
+	payload, err := a.aggregateFunc(messages)
+	if err != nil {
+		return nil, fmt.Errorf("[aggregator] failed to aggregate messages: %w", err)
+	}
+
+	aggregated := message.NewMessageBuilderFromMessage(messages[0]).
+		WithMessageId("").
+		WithPayload(payload).
+		WithContext(ctx).
+		Build()
+	delete(aggregated.GetHeader(), message.HeaderSequenceNumber)
+	delete(aggregated.GetHeader(), message.HeaderSequenceSize)
+
+	anyChannel, err := a.gomesContainer.Get(a.outputChannel)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[aggregator] %w: %s",
+			message.ErrChannelNotFound,
+			a.outputChannel,
+		)
+	}
+
+	channel, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[aggregator] channel %s does not implement PublisherChannel",
+			a.outputChannel,
+		)
+	}
+
+	if err := channel.Send(ctx, aggregated); err != nil {
+		return nil, fmt.Errorf(
+			"[aggregator] failed to send message to %s: %w",
+			a.outputChannel,
+			err,
+		)
+	}
+
+	return aggregated, nil
+}
+
+func sequenceNumber(msg *message.Message) int {
+	number, _ := strconv.Atoi(msg.GetHeader().Get(message.HeaderSequenceNumber))
+	return number
+}
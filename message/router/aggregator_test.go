@@ -0,0 +1,144 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func joinWords(msgs []*message.Message) (any, error) {
+	words := []string{}
+	for _, msg := range msgs {
+		words = append(words, msg.GetPayload().(string))
+	}
+	return strings.Join(words, " "), nil
+}
+
+func TestAggregator_SplitAndAggregate(t *testing.T) {
+	t.Parallel()
+	words := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	sentences := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("words", words)
+	cont.Set("sentences", sentences)
+
+	source := message.NewMessageBuilder().WithPayload("one two three").Build()
+	if _, err := NewSplitter(cont, splitWords, "words").Handle(context.Background(), source); err != nil {
+		t.Fatalf("unexpected split error: %v", err)
+	}
+
+	children := []*message.Message{<-words.msgReceived, <-words.msgReceived, <-words.msgReceived}
+	agg := NewAggregator(cont, joinWords, "sentences")
+	for _, i := range []int{2, 0} {
+		result, err := agg.Handle(context.Background(), children[i])
+		if err != nil || result != nil {
+			t.Fatalf("expected incomplete group, got %v (%v)", result, err)
+		}
+	}
+	if agg.PendingGroups() != 1 {
+		t.Errorf("expected 1 pending group, got %d", agg.PendingGroups())
+	}
+
+	result, err := agg.Handle(context.Background(), children[1])
+	if err != nil || result == nil {
+		t.Fatalf("expected aggregated message, got %v (%v)", result, err)
+	}
+	if result.GetPayload() != "one two three" {
+		t.Errorf("expected payload in sequence order, got %v", result.GetPayload())
+	}
+	if result.GetHeader().Get(message.HeaderCorrelationId) != source.GetHeader().Get(message.HeaderMessageId) {
+		t.Error("expected aggregated message to keep the correlation id")
+	}
+	if _, ok := result.GetHeader()[message.HeaderSequenceSize]; ok {
+		t.Error("expected sequence headers to be removed")
+	}
+	if <-sentences.msgReceived != result {
+		t.Error("expected aggregated message sent to the output channel")
+	}
+	if agg.PendingGroups() != 0 {
+		t.Errorf("expected no pending group, got %d", agg.PendingGroups())
+	}
+}
+
+func TestAggregator_CompletionSizeAndTimeout(t *testing.T) {
+	t.Parallel()
+	output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("output", output)
+	agg := NewAggregator(cont, joinWords, "output").
+		WithCompletionSize(2).
+		WithCompletionTimeout(20 * time.Millisecond)
+
+	newMsg := func(correlationId, payload string) *message.Message {
+		return message.NewMessageBuilder().
+			WithCorrelationId(correlationId).
+			WithPayload(payload).
+			Build()
+	}
+
+	agg.Handle(context.Background(), newMsg("a", "first"))
+	result, err := agg.Handle(context.Background(), newMsg("a", "second"))
+	if err != nil || result == nil || result.GetPayload() != "first second" {
+		t.Fatalf("expected group complete by size, got %v (%v)", result, err)
+	}
+	<-output.msgReceived
+
+	agg.Handle(context.Background(), newMsg("b", "alone"))
+	select {
+	case expired := <-output.msgReceived:
+		if expired.GetPayload() != "alone" {
+			t.Errorf("expected partial group on timeout, got %v", expired.GetPayload())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected partial group released on timeout")
+	}
+
+	if _, err := agg.Handle(context.Background(), message.NewMessageBuilder().Build()); err == nil {
+		t.Error("expected error for message without causation or correlation id")
+	}
+}
+
+func TestAggregator_GroupsBySplitAndIgnoresDuplicates(t *testing.T) {
+	t.Parallel()
+	words := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	sentences := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("words", words)
+	cont.Set("sentences", sentences)
+	splitter := NewSplitter(cont, splitWords, "words")
+
+	first := message.NewMessageBuilder().WithCorrelationId("workflow").WithPayload("a b").Build()
+	second := message.NewMessageBuilder().WithCorrelationId("workflow").WithPayload("c d").Build()
+	for _, source := range []*message.Message{first, second} {
+		if _, err := splitter.Handle(context.Background(), source); err != nil {
+			t.Fatalf("unexpected split error: %v", err)
+		}
+	}
+	children := []*message.Message{
+		<-words.msgReceived, <-words.msgReceived, <-words.msgReceived, <-words.msgReceived,
+	}
+
+	agg := NewAggregator(cont, joinWords, "sentences")
+	for _, child := range []*message.Message{children[0], children[0], children[2]} {
+		result, err := agg.Handle(context.Background(), child)
+		if err != nil || result != nil {
+			t.Fatalf("expected incomplete groups, got %v (%v)", result, err)
+		}
+	}
+	if agg.PendingGroups() != 2 {
+		t.Errorf("expected a group per split, got %d", agg.PendingGroups())
+	}
+
+	result, err := agg.Handle(context.Background(), children[1])
+	if err != nil || result == nil || result.GetPayload() != "a b" {
+		t.Fatalf("expected the first split aggregated, got %v (%v)", result, err)
+	}
+	result, err = agg.Handle(context.Background(), children[3])
+	if err != nil || result == nil || result.GetPayload() != "c d" {
+		t.Fatalf("expected the second split aggregated, got %v (%v)", result, err)
+	}
+}
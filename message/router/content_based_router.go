@@ -0,0 +1,433 @@
+// Package router provides message routing components for the message system.
+//
+// This package implements various routing patterns from Enterprise Integration
+// Patterns, enabling flexible message routing and processing through different
+// channels and handlers. It provides composite routing, recipient list routing,
+// content-based routing and message filtering capabilities.
+//
+// The ContentBasedRouter implementation supports:
+// - Routing based on user-supplied predicates over the whole message
+// - Routing based on expressions over headers and payload fields
+// - Ordered route evaluation, the first matching route wins
+// - Default channel for messages matching no route
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// RoutePredicate defines the contract for content-based routing predicates.
+// It returns true if the message must be routed to the associated channel.
+type RoutePredicate func(message.Message) bool
+
+// contentRoute associates a predicate to its destination channel.
+type contentRoute struct {
+	predicate   RoutePredicate
+	channelName string
+}
+
+// contentBasedRouter implements the Content-Based Router pattern, routing
+// messages to the channel of the first route whose predicate matches.
+type contentBasedRouter struct {
+	gomesContainer container.Container[any, any]
+	routes         []contentRoute
+	defaultChannel string
+}
+
+// expressionCondition is a single comparison of a routing expression.
+type expressionCondition struct {
+	source   string
+	path     []string
+	operator string
+	literal  any
+}
+
+var expressionOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// NewContentBasedRouter creates a new content-based router instance.
+//
+// Parameters:
+//   - gomesContainer: container for resolving the destination channels
+//
+// Returns:
+//   - *contentBasedRouter: configured content-based router
+func NewContentBasedRouter(
+	gomesContainer container.Container[any, any],
+) *contentBasedRouter {
+	return &contentBasedRouter{gomesContainer: gomesContainer}
+}
+
+// When adds a route sending the messages matching the predicate to the
+// given channel. Routes are evaluated in the order they were added.
+//
+// Parameters:
+//   - predicate: the routing predicate
+//   - channelName: the destination channel name
+//
+// Returns:
+//   - *contentBasedRouter: router instance for method chaining
+func (r *contentBasedRouter) When(
+	predicate RoutePredicate,
+	channelName string,
+) *contentBasedRouter {
+	r.routes = append(r.routes, contentRoute{
+		predicate:   predicate,
+		channelName: channelName,
+	})
+	return r
+}
+
+// WhenExpression adds a route sending the messages matching the expression
+// to the given channel. An expression is one or more comparisons joined by
+// "&&", each one in the form "<source>.<field> <operator> <value>", where:
+//   - source is "header" or "payload" (nested payload fields use dots)
+//   - operator is one of ==, !=, >, <, >=, <=
+//   - value is a quoted string, which may contain "&&", a number, true,
+//     false or null
+//
+// Example: `header.route == "order.created" && payload.total.amount > 100`
+//
+// Parameters:
+//   - expression: the routing expression
+//   - channelName: the destination channel name
+//
+// Returns:
+//   - *contentBasedRouter: router instance for method chaining
+//   - error: error if the expression is invalid
+func (r *contentBasedRouter) WhenExpression(
+	expression string,
+	channelName string,
+) (*contentBasedRouter, error) {
+	predicate, err := ParseRouteExpression(expression)
+	if err != nil {
+		return r, err
+	}
+	return r.When(predicate, channelName), nil
+}
+
+// Otherwise sets the channel receiving the messages matching no route.
+//
+// Parameters:
+//   - channelName: the default channel name
+//
+// Returns:
+//   - *contentBasedRouter: router instance for method chaining
+func (r *contentBasedRouter) Otherwise(channelName string) *contentBasedRouter {
+	r.defaultChannel = channelName
+	return r
+}
+
+// Handle routes a message to the channel of the first matching route, or to
+// the default channel when no route matches.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be routed
+//
+// Returns:
+//   - *message.Message: the original message if routing succeeds
+//   - error: error wrapping ErrUnroutable if no route matches and there is no
+//     default channel, or error if the destination channel is invalid
+func (r *contentBasedRouter) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	channelName := r.defaultChannel
+	for _, route := range r.routes {
+		if route.predicate(*msg) {
+			channelName = route.channelName
+			break
+		}
+	}
+
+	if channelName == "" {
+		return nil, fmt.Errorf(
+			"%w, no content route matches message %s",
+			ErrUnroutable,
+			msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	}
+
+	anyChannel, err := r.gomesContainer.Get(channelName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[content-based-router] %w: %s",
+			message.ErrChannelNotFound,
+			channelName,
+		)
+	}
+
+	channel, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[content-based-router] channel %s does not implement PublisherChannel",
+			channelName,
+		)
+	}
+
+	if err := channel.Send(ctx, msg); err != nil {
+		return nil, fmt.Errorf(
+			"[content-based-router] failed to send message to %s: %w",
+			channelName,
+			err,
+		)
+	}
+
+	return msg, nil
+}
+
+// ParseRouteExpression compiles a routing expression into a route predicate.
+// See WhenExpression for the expression syntax.
+//
+// Parameters:
+//   - expression: the routing expression
+//
+// Returns:
+//   - RoutePredicate: the compiled predicate
+//   - error: error if the expression is invalid
+func ParseRouteExpression(expression string) (RoutePredicate, error) {
+	conditions := []expressionCondition{}
+	for _, part := range splitExpressionConditions(expression) {
+		condition, err := parseExpressionCondition(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[content-based-router] invalid expression %q: %w",
+				expression,
+				err,
+			)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return func(msg message.Message) bool {
+		var payload any
+		payloadDecoded := false
+		for _, condition := range conditions {
+			var value any
+			var found bool
+			if condition.source == "header" {
+				value, found = msg.GetHeader()[condition.path[0]]
+			} else {
+				if !payloadDecoded {
+					payload = decodePayload(msg.GetPayload())
+					payloadDecoded = true
+				}
+				value, found = lookupField(payload, condition.path)
+			}
+
+			if !condition.evaluate(value, found) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// splitExpressionConditions splits an expression on its && operators, keeping
+// the ones within quoted string literals.
+func splitExpressionConditions(expression string) []string {
+	conditions := []string{}
+	start := 0
+	var quote byte
+	for i := 0; i < len(expression); i++ {
+		switch {
+		case quote != 0:
+			if expression[i] == quote {
+				quote = 0
+			}
+		case expression[i] == '"' || expression[i] == '\'':
+			quote = expression[i]
+		case strings.HasPrefix(expression[i:], "&&"):
+			conditions = append(conditions, expression[start:i])
+			start = i + 2
+			i++
+		}
+	}
+	return append(conditions, expression[start:])
+}
+
+func parseExpressionCondition(condition string) (expressionCondition, error) {
+	index, operator := -1, ""
+	for i := 0; i < len(condition) && index < 0; i++ {
+		for _, candidate := range expressionOperators {
+			if strings.HasPrefix(condition[i:], candidate) {
+				index, operator = i, candidate
+				break
+			}
+		}
+	}
+
+	if index < 0 {
+		return expressionCondition{}, fmt.Errorf(
+			"condition %q has no valid operator",
+			condition,
+		)
+	}
+
+	field := strings.TrimSpace(condition[:index])
+	rawLiteral := strings.TrimSpace(condition[index+len(operator):])
+	source, path, ok := strings.Cut(field, ".")
+	if !ok || path == "" || (source != "header" && source != "payload") {
+		return expressionCondition{}, fmt.Errorf(
+			"field %q must start with header. or payload.",
+			field,
+		)
+	}
+
+	literal, err := parseExpressionLiteral(rawLiteral)
+	if err != nil {
+		return expressionCondition{}, err
+	}
+
+	fieldPath := strings.Split(path, ".")
+	if source == "header" {
+		fieldPath = []string{path}
+	}
+
+	return expressionCondition{
+		source:   source,
+		path:     fieldPath,
+		operator: operator,
+		literal:  literal,
+	}, nil
+}
+
+func parseExpressionLiteral(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, fmt.Errorf("missing value")
+	case raw == "null":
+		return nil, nil
+	case raw == "true" || raw == "false":
+		return raw == "true", nil
+	case strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, `'`):
+		if len(raw) < 2 || raw[len(raw)-1] != raw[0] {
+			return nil, fmt.Errorf("unterminated string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	}
+
+	number, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %s", raw)
+	}
+	return number, nil
+}
+
+// evaluate compares a field value to the condition literal.
+func (c expressionCondition) evaluate(value any, found bool) bool {
+	if c.literal == nil {
+		isNull := !found || value == nil
+		if c.operator == "!=" {
+			return !isNull
+		}
+		return c.operator == "==" && isNull
+	}
+
+	if !found {
+		return c.operator == "!="
+	}
+
+	switch literal := c.literal.(type) {
+	case float64:
+		number, ok := toNumber(value)
+		if !ok {
+			return c.operator == "!="
+		}
+		switch c.operator {
+		case "==":
+			return number == literal
+		case "!=":
+			return number != literal
+		case ">":
+			return number > literal
+		case "<":
+			return number < literal
+		case ">=":
+			return number >= literal
+		case "<=":
+			return number <= literal
+		}
+	case string:
+		text := fmt.Sprint(value)
+		switch c.operator {
+		case "==":
+			return text == literal
+		case "!=":
+			return text != literal
+		case ">":
+			return text > literal
+		case "<":
+			return text < literal
+		case ">=":
+			return text >= literal
+		case "<=":
+			return text <= literal
+		}
+	case bool:
+		flag, ok := value.(bool)
+		if text, isText := value.(string); isText {
+			parsed, err := strconv.ParseBool(text)
+			flag, ok = parsed, err == nil
+		}
+		switch c.operator {
+		case "==":
+			return ok && flag == literal
+		case "!=":
+			return !ok || flag != literal
+		}
+	}
+
+	return false
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// decodePayload converts the message payload to its generic JSON form, so its
+// fields can be looked up by name.
+func decodePayload(payload any) any {
+	data, ok := payload.([]byte)
+	if !ok {
+		var err error
+		data, err = json.Marshal(payload)
+		if err != nil {
+			return nil
+		}
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
+
+func lookupField(value any, path []string) (any, bool) {
+	for _, key := range path {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		value, ok = fields[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestContentBasedRouter_Handle(t *testing.T) {
+	orders := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	vip := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	fallback := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("orders", orders)
+	cont.Set("vip", vip)
+	cont.Set("fallback", fallback)
+
+	newRouter := func(t *testing.T) *contentBasedRouter {
+		r, err := NewContentBasedRouter(cont).
+			WhenExpression(`header.route == "order.created" && payload.total.amount >= 100`, "vip")
+		if err != nil {
+			t.Fatalf("unexpected expression error: %v", err)
+		}
+		return r.When(func(m message.Message) bool {
+			return m.GetHeader().Get(message.HeaderRoute) == "order.created"
+		}, "orders")
+	}
+
+	t.Run("should route by expression over headers and payload", func(t *testing.T) {
+		msg := message.NewMessageBuilder().
+			WithRoute("order.created").
+			WithPayload([]byte(`{"total":{"amount":150}}`)).
+			Build()
+		if _, err := newRouter(t).Handle(context.Background(), msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := <-vip.msgReceived; got != msg {
+			t.Error("expected message routed to vip channel")
+		}
+	})
+
+	t.Run("should route by predicate in order", func(t *testing.T) {
+		msg := message.NewMessageBuilder().
+			WithRoute("order.created").
+			WithPayload(map[string]any{"total": map[string]any{"amount": 10}}).
+			Build()
+		if _, err := newRouter(t).Handle(context.Background(), msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := <-orders.msgReceived; got != msg {
+			t.Error("expected message routed to orders channel")
+		}
+	})
+
+	t.Run("should route to default channel", func(t *testing.T) {
+		msg := message.NewMessageBuilder().WithRoute("order.cancelled").Build()
+		if _, err := newRouter(t).Otherwise("fallback").Handle(context.Background(), msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := <-fallback.msgReceived; got != msg {
+			t.Error("expected message routed to fallback channel")
+		}
+	})
+
+	t.Run("should return unroutable error when nothing matches", func(t *testing.T) {
+		msg := message.NewMessageBuilder().WithRoute("order.cancelled").Build()
+		_, err := newRouter(t).Handle(context.Background(), msg)
+		if !errors.Is(err, ErrUnroutable) {
+			t.Errorf("expected unroutable error, got %v", err)
+		}
+	})
+}
+
+func TestParseRouteExpression(t *testing.T) {
+	t.Parallel()
+	msg := message.NewMessageBuilder().
+		WithCustomHeader("region", "br").
+		WithPayload([]byte(`{"vip":true,"name":"a!=b","terms":"x && y","count":3,"missing":null}`)).
+		Build()
+
+	cases := []struct {
+		expression string
+		want       bool
+	}{
+		{`header.region == "br"`, true},
+		{`header.region != 'br'`, false},
+		{`payload.vip == true`, true},
+		{`payload.name == "a!=b"`, true},
+		{`payload.terms == "x && y" && header.region == 'br'`, true},
+		{`payload.terms == 'x && y' && payload.count > 3`, false},
+		{`payload.count < 3`, false},
+		{`payload.count <= 3`, true},
+		{`payload.missing == null`, true},
+		{`payload.unknown != null`, false},
+		{`payload.unknown == "x"`, false},
+	}
+	for _, c := range cases {
+		predicate, err := ParseRouteExpression(c.expression)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", c.expression, err)
+		}
+		if got := predicate(*msg); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.expression, c.want, got)
+		}
+	}
+
+	for _, invalid := range []string{`route == "x"`, `header.route`, `payload.a == "x`, `payload.a > abc`} {
+		if _, err := ParseRouteExpression(invalid); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jeffersonbrasilino/gomes/internal/jsonfile"
+)
+
+// DynamicRouteStore persists the routes added to a dynamic router by control
+// messages, so they survive restarts. A store shared by the instances of a
+// service, e.g. backed by a database, gives every instance the same routes
+// when it starts.
+type DynamicRouteStore interface {
+	// Save records a route, replacing the route with the same id.
+	Save(ctx context.Context, route DynamicRoute) error
+	// Delete removes a route.
+	Delete(ctx context.Context, routeId string) error
+	// Routes returns the recorded routes, in the order they were added.
+	Routes(ctx context.Context) ([]DynamicRoute, error)
+}
+
+// fileDynamicRouteStore is a DynamicRouteStore persisting its routes in a
+// JSON file, for single instance deployments without shared storage.
+type fileDynamicRouteStore struct {
+	path   string
+	mu     sync.Mutex
+	routes []DynamicRoute
+}
+
+// NewFileDynamicRouteStore creates a route store persisted in a JSON file,
+// loading the routes saved by previous processes.
+//
+// Parameters:
+//   - path: The JSON file path, created on the first save
+//
+// Returns:
+//   - *fileDynamicRouteStore: Configured store instance
+//   - error: Error if the existing file cannot be read
+func NewFileDynamicRouteStore(path string) (*fileDynamicRouteStore, error) {
+	store := &fileDynamicRouteStore{path: path}
+	if err := jsonfile.Load(path, &store.routes); err != nil {
+		return nil, fmt.Errorf("[dynamic-route-store] %w", err)
+	}
+	return store, nil
+}
+
+// Save records a route, replacing the route with the same id, and persists
+// the store.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - route: The route
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (s *fileDynamicRouteStore) Save(ctx context.Context, route DynamicRoute) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	routes := slices.Clone(s.routes)
+	index := slices.IndexFunc(routes, func(existing DynamicRoute) bool {
+		return existing.Id == route.Id
+	})
+	if index >= 0 {
+		routes[index] = route
+	} else {
+		routes = append(routes, route)
+	}
+	return s.persist(routes)
+}
+
+// Delete removes a route and persists the store.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//   - routeId: The id of the route
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (s *fileDynamicRouteStore) Delete(ctx context.Context, routeId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	routes := slices.DeleteFunc(slices.Clone(s.routes), func(route DynamicRoute) bool {
+		return route.Id == routeId
+	})
+	if len(routes) == len(s.routes) {
+		return nil
+	}
+	return s.persist(routes)
+}
+
+// Routes returns the recorded routes, in the order they were added.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation control
+//
+// Returns:
+//   - []DynamicRoute: The routes
+//   - error: always nil
+func (s *fileDynamicRouteStore) Routes(ctx context.Context) ([]DynamicRoute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.routes), nil
+}
+
+// persist atomically rewrites the store file with the routes, keeping them
+// once written. The caller holds the lock.
+func (s *fileDynamicRouteStore) persist(routes []DynamicRoute) error {
+	if err := jsonfile.Save(s.path, routes); err != nil {
+		return fmt.Errorf("[dynamic-route-store] %w", err)
+	}
+	s.routes = routes
+	return nil
+}
@@ -0,0 +1,525 @@
+// Package router provides message routing components for the message system.
+//
+// The DynamicRouter implementation supports:
+// - Routes added and removed at runtime, evaluated in the order they were added
+// - Routes by predicate or by routing expression
+// - Opt-in route changes through control messages, per the Dynamic Router pattern
+// - Control messages restricted to allowed channels and to their own routes
+// - Persistence of the routes added by control messages in a route store
+// - Default channel for messages matching no route
+// - Consumer pipelines routing their messages through the router
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// DynamicRouterControlRoute is the default route of the control messages of
+// the dynamic routers.
+const DynamicRouterControlRoute = "dynamicRouter.control"
+
+// Operations of the dynamic router control commands.
+const (
+	DynamicRouteAdd    = "add"
+	DynamicRouteRemove = "remove"
+)
+
+// ContainerRouter is a router resolving its destination channels from the
+// container of the consumer pipeline it is attached to.
+type ContainerRouter interface {
+	// Bind returns the handler routing the messages to the channels of the
+	// container.
+	Bind(gomesContainer container.Container[any, any]) message.MessageHandler
+}
+
+// DynamicRouteCommand is the payload of a control message changing the routes
+// of a dynamic router. Routes added by control messages use routing
+// expressions, see ParseRouteExpression.
+type DynamicRouteCommand struct {
+	// Operation is DynamicRouteAdd or DynamicRouteRemove.
+	Operation string `json:"operation"`
+	// RouteId identifies the route. An added route replaces the route with
+	// the same id added by a control message, a generated id is used when
+	// empty.
+	RouteId string `json:"routeId"`
+	// Expression is the routing expression of an added route.
+	Expression string `json:"expression"`
+	// Channel is the destination channel of an added route.
+	Channel string `json:"channel"`
+}
+
+// DynamicRoute describes a route of a dynamic router.
+type DynamicRoute struct {
+	// Id identifies the route.
+	Id string
+	// Channel is the destination channel name.
+	Channel string
+	// Expression is the routing expression, empty for predicate routes.
+	Expression string
+	// Controlled reports whether the route was added by a control message.
+	Controlled bool
+}
+
+// dynamicRoute associates a predicate to its destination channel.
+type dynamicRoute struct {
+	DynamicRoute
+	predicate RoutePredicate
+}
+
+// dynamicRouter implements the Dynamic Router pattern, routing messages to
+// the channel of the first matching route of a route table changed at
+// runtime, either through its API or through control messages.
+type dynamicRouter struct {
+	mu              sync.RWMutex
+	routes          []dynamicRoute
+	defaultChannel  string
+	controlRoute    string
+	controlEnabled  bool
+	allowedChannels []string
+	store           DynamicRouteStore
+}
+
+// boundDynamicRouter routes the messages through the route table of a
+// dynamic router to the channels of a container.
+type boundDynamicRouter struct {
+	router         *dynamicRouter
+	gomesContainer container.Container[any, any]
+}
+
+// NewDynamicRouter creates a new dynamic router instance, without routes.
+// Control messages are disabled until WithControlMessages is called.
+//
+// Returns:
+//   - *dynamicRouter: configured dynamic router
+func NewDynamicRouter() *dynamicRouter {
+	return &dynamicRouter{controlRoute: DynamicRouterControlRoute}
+}
+
+// WithControlRoute sets the route of the control messages of the router,
+// DynamicRouterControlRoute by default.
+//
+// Parameters:
+//   - route: the control messages route
+//
+// Returns:
+//   - *dynamicRouter: router instance for method chaining
+func (r *dynamicRouter) WithControlRoute(route string) *dynamicRouter {
+	r.controlRoute = route
+	return r
+}
+
+// WithControlMessages enables the route changes through control messages,
+// restricting the destinations of their routes to the channels matching one
+// of the patterns, so the producers of control messages cannot route the
+// messages to arbitrary channels. Without patterns, no channel is allowed.
+// Control messages only replace or remove the routes added by control
+// messages, never the routes added through the API.
+//
+// Parameters:
+//   - allowedPatterns: path.Match patterns of the allowed channels, e.g.
+//     "orders.*"
+//
+// Returns:
+//   - *dynamicRouter: router instance for method chaining
+func (r *dynamicRouter) WithControlMessages(allowedPatterns ...string) *dynamicRouter {
+	r.controlEnabled = true
+	r.allowedChannels = allowedPatterns
+	return r
+}
+
+// WithRouteStore persists the routes added by control messages in the store
+// and restores the routes it holds, so they survive restarts and instances
+// sharing the store start with the same routes.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - store: the route store
+//
+// Returns:
+//   - *dynamicRouter: router instance for method chaining
+//   - error: error if the routes cannot be restored
+func (r *dynamicRouter) WithRouteStore(
+	ctx context.Context,
+	store DynamicRouteStore,
+) (*dynamicRouter, error) {
+	routes, err := store.Routes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[dynamic-router] routes could not be restored: %w", err)
+	}
+	for _, route := range routes {
+		predicate, err := ParseRouteExpression(route.Expression)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"[dynamic-router] route %s could not be restored: %w",
+				route.Id,
+				err,
+			)
+		}
+		route.Controlled = true
+		r.addRoute(dynamicRoute{DynamicRoute: route, predicate: predicate})
+	}
+	r.store = store
+	return r, nil
+}
+
+// Bind returns the handler routing the messages to the channels of the
+// container, sharing the route table of the router.
+//
+// Parameters:
+//   - gomesContainer: container for resolving the destination channels
+//
+// Returns:
+//   - message.MessageHandler: the routing handler
+func (r *dynamicRouter) Bind(
+	gomesContainer container.Container[any, any],
+) message.MessageHandler {
+	return &boundDynamicRouter{router: r, gomesContainer: gomesContainer}
+}
+
+// Otherwise sets the channel receiving the messages matching no route.
+//
+// Parameters:
+//   - channelName: the default channel name
+//
+// Returns:
+//   - *dynamicRouter: router instance for method chaining
+func (r *dynamicRouter) Otherwise(channelName string) *dynamicRouter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultChannel = channelName
+	return r
+}
+
+// AddRoute adds a route sending the messages matching the predicate to the
+// given channel, after the existing routes.
+//
+// Parameters:
+//   - predicate: the routing predicate
+//   - channelName: the destination channel name
+//
+// Returns:
+//   - string: the id of the route, used to remove it
+func (r *dynamicRouter) AddRoute(
+	predicate RoutePredicate,
+	channelName string,
+) string {
+	return r.addRoute(dynamicRoute{
+		DynamicRoute: DynamicRoute{Id: uuid.NewString(), Channel: channelName},
+		predicate:    predicate,
+	})
+}
+
+// AddRouteExpression adds a route sending the messages matching the routing
+// expression to the given channel, after the existing routes. See
+// ParseRouteExpression for the expression syntax.
+//
+// Parameters:
+//   - expression: the routing expression
+//   - channelName: the destination channel name
+//
+// Returns:
+//   - string: the id of the route, used to remove it
+//   - error: error if the expression is invalid
+func (r *dynamicRouter) AddRouteExpression(
+	expression string,
+	channelName string,
+) (string, error) {
+	predicate, err := ParseRouteExpression(expression)
+	if err != nil {
+		return "", err
+	}
+	return r.addRoute(dynamicRoute{
+		DynamicRoute: DynamicRoute{
+			Id:         uuid.NewString(),
+			Channel:    channelName,
+			Expression: expression,
+		},
+		predicate: predicate,
+	}), nil
+}
+
+// RemoveRoute removes a route, including the routes added by control
+// messages, which are deleted from the route store too.
+//
+// Parameters:
+//   - routeId: the id of the route
+//
+// Returns:
+//   - bool: true if the route existed
+func (r *dynamicRouter) RemoveRoute(routeId string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(routeId)
+	if index < 0 {
+		return false
+	}
+	if r.routes[index].Controlled && r.store != nil {
+		if err := r.store.Delete(context.Background(), routeId); err != nil {
+			slog.Error("[dynamic-router] route could not be deleted from the store.",
+				"routeId", routeId,
+				"reason", err.Error(),
+			)
+		}
+	}
+	r.routes = slices.Delete(r.routes, index, index+1)
+	return true
+}
+
+// Routes returns the routes of the router, in evaluation order.
+//
+// Returns:
+//   - []DynamicRoute: the routes
+func (r *dynamicRouter) Routes() []DynamicRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	routes := make([]DynamicRoute, len(r.routes))
+	for i, route := range r.routes {
+		routes[i] = route.DynamicRoute
+	}
+	return routes
+}
+
+// Handle applies the control messages to the route table, when enabled, and
+// routes the other messages to the channel of the first matching route, or to
+// the default channel when no route matches.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be routed or the control message
+//
+// Returns:
+//   - *message.Message: the original message if routing succeeds, nil for
+//     control messages
+//   - error: error wrapping ErrUnroutable if no route matches and there is no
+//     default channel, or error if the destination channel is invalid or the
+//     control message cannot be applied
+func (b *boundDynamicRouter) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	if msg.GetHeader().Get(message.HeaderRoute) == b.router.controlRoute {
+		return nil, b.router.applyControl(ctx, msg)
+	}
+
+	channelName := b.router.match(msg)
+	if channelName == "" {
+		return nil, fmt.Errorf(
+			"%w, no dynamic route matches message %s",
+			ErrUnroutable,
+			msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	}
+
+	anyChannel, err := b.gomesContainer.Get(channelName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[dynamic-router] %w: %s",
+			message.ErrChannelNotFound,
+			channelName,
+		)
+	}
+
+	channel, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[dynamic-router] channel %s does not implement PublisherChannel",
+			channelName,
+		)
+	}
+
+	if err := channel.Send(ctx, msg); err != nil {
+		return nil, fmt.Errorf(
+			"[dynamic-router] failed to send message to %s: %w",
+			channelName,
+			err,
+		)
+	}
+
+	return msg, nil
+}
+
+// match returns the channel of the first route matching the message, or the
+// default channel.
+func (r *dynamicRouter) match(msg *message.Message) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, route := range r.routes {
+		if route.predicate(*msg) {
+			return route.Channel
+		}
+	}
+	return r.defaultChannel
+}
+
+// addRoute adds a route, replacing the route with the same id.
+func (r *dynamicRouter) addRoute(route dynamicRoute) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(route.Id)
+	if index >= 0 {
+		r.routes[index] = route
+		return route.Id
+	}
+	r.routes = append(r.routes, route)
+	return route.Id
+}
+
+// applyControl decodes a control message and applies its command.
+func (r *dynamicRouter) applyControl(ctx context.Context, msg *message.Message) error {
+	if !r.controlEnabled {
+		return fmt.Errorf(
+			"[dynamic-router] control messages are disabled, message %s rejected",
+			msg.GetHeader().Get(message.HeaderMessageId),
+		)
+	}
+
+	data, ok := msg.GetPayload().([]byte)
+	if !ok {
+		var err error
+		data, err = json.Marshal(msg.GetPayload())
+		if err != nil {
+			return fmt.Errorf(
+				"[dynamic-router] cannot decode control message: %w: %w",
+				message.ErrTranslation,
+				err,
+			)
+		}
+	}
+
+	command := DynamicRouteCommand{}
+	if err := json.Unmarshal(data, &command); err != nil {
+		return fmt.Errorf(
+			"[dynamic-router] cannot decode control message: %w: %w",
+			message.ErrTranslation,
+			err,
+		)
+	}
+
+	switch command.Operation {
+	case DynamicRouteAdd:
+		if err := r.addControlRoute(ctx, command); err != nil {
+			return err
+		}
+	case DynamicRouteRemove:
+		if err := r.removeControlRoute(ctx, command.RouteId); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf(
+			"[dynamic-router] unknown control operation %q",
+			command.Operation,
+		)
+	}
+
+	slog.Info("[dynamic-router] route table changed.",
+		"operation", command.Operation,
+		"routeId", command.RouteId,
+		"channel", command.Channel,
+	)
+	return nil
+}
+
+// addControlRoute adds or replaces a route of a control message, persisting
+// it in the route store.
+func (r *dynamicRouter) addControlRoute(
+	ctx context.Context,
+	command DynamicRouteCommand,
+) error {
+	if command.Channel == "" {
+		return fmt.Errorf("[dynamic-router] control message has no channel")
+	}
+	if !r.allowedChannel(command.Channel) {
+		return fmt.Errorf(
+			"[dynamic-router] channel %s is not allowed for control messages",
+			command.Channel,
+		)
+	}
+	predicate, err := ParseRouteExpression(command.Expression)
+	if err != nil {
+		return err
+	}
+	if command.RouteId == "" {
+		command.RouteId = uuid.NewString()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(command.RouteId)
+	if index >= 0 && !r.routes[index].Controlled {
+		return fmt.Errorf(
+			"[dynamic-router] route %s was not added by a control message",
+			command.RouteId,
+		)
+	}
+	route := dynamicRoute{
+		DynamicRoute: DynamicRoute{
+			Id:         command.RouteId,
+			Channel:    command.Channel,
+			Expression: command.Expression,
+			Controlled: true,
+		},
+		predicate: predicate,
+	}
+	if r.store != nil {
+		if err := r.store.Save(ctx, route.DynamicRoute); err != nil {
+			return fmt.Errorf("[dynamic-router] route %s could not be saved: %w", route.Id, err)
+		}
+	}
+	if index >= 0 {
+		r.routes[index] = route
+		return nil
+	}
+	r.routes = append(r.routes, route)
+	return nil
+}
+
+// removeControlRoute removes a route added by a control message, deleting it
+// from the route store. Removing an unknown route has no effect.
+func (r *dynamicRouter) removeControlRoute(ctx context.Context, routeId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(routeId)
+	if index < 0 {
+		return nil
+	}
+	if !r.routes[index].Controlled {
+		return fmt.Errorf(
+			"[dynamic-router] route %s was not added by a control message",
+			routeId,
+		)
+	}
+	if r.store != nil {
+		if err := r.store.Delete(ctx, routeId); err != nil {
+			return fmt.Errorf("[dynamic-router] route %s could not be deleted: %w", routeId, err)
+		}
+	}
+	r.routes = slices.Delete(r.routes, index, index+1)
+	return nil
+}
+
+// indexOf returns the index of a route, or -1. The caller holds the lock.
+func (r *dynamicRouter) indexOf(routeId string) int {
+	return slices.IndexFunc(r.routes, func(route dynamicRoute) bool {
+		return route.Id == routeId
+	})
+}
+
+// allowedChannel reports whether a control message may route to the channel.
+func (r *dynamicRouter) allowedChannel(channelName string) bool {
+	for _, pattern := range r.allowedChannels {
+		if matched, _ := path.Match(pattern, channelName); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestDynamicRouter_Handle(t *testing.T) {
+	t.Parallel()
+	orders := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	vip := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("orders", orders)
+	cont.Set("vip", vip)
+
+	r := NewDynamicRouter()
+	h := r.Bind(cont)
+	newOrder := func() *message.Message {
+		return message.NewMessageBuilder().
+			WithRoute("order.created").
+			WithPayload([]byte(`{"total":150}`)).
+			Build()
+	}
+
+	if _, err := h.Handle(context.Background(), newOrder()); !errors.Is(err, ErrUnroutable) {
+		t.Fatalf("expected ErrUnroutable without routes, got %v", err)
+	}
+
+	ordersRoute := r.AddRoute(func(m message.Message) bool {
+		return m.GetHeader().Get(message.HeaderRoute) == "order.created"
+	}, "orders")
+	msg := newOrder()
+	if _, err := h.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-orders.msgReceived; got != msg {
+		t.Error("expected message routed to orders channel")
+	}
+
+	if !r.RemoveRoute(ordersRoute) {
+		t.Fatal("expected route to be removed")
+	}
+	if r.RemoveRoute(ordersRoute) {
+		t.Error("expected removed route to be unknown")
+	}
+	vipRoute, err := r.AddRouteExpression(`payload.total >= 100`, "vip")
+	if err != nil {
+		t.Fatalf("unexpected expression error: %v", err)
+	}
+	msg = newOrder()
+	if _, err := h.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-vip.msgReceived; got != msg {
+		t.Error("expected message routed to vip channel")
+	}
+	if routes := r.Routes(); len(routes) != 1 || routes[0].Id != vipRoute {
+		t.Errorf("expected only the vip route, got %v", routes)
+	}
+
+	if _, err := r.AddRouteExpression(`total >= 100`, "vip"); err == nil {
+		t.Error("expected invalid expression error")
+	}
+}
+
+func TestDynamicRouter_ControlMessages(t *testing.T) {
+	t.Parallel()
+	orders := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("orders", orders)
+	r := NewDynamicRouter().WithControlMessages("orders")
+	h := r.Bind(cont)
+
+	t.Run("should add a route", func(t *testing.T) {
+		result, err := h.Handle(context.Background(), controlMessage([]byte(
+			`{"operation":"add","routeId":"orders","expression":"header.route == \"order.created\"","channel":"orders"}`,
+		)))
+		if err != nil || result != nil {
+			t.Fatalf("expected control message consumed, got %v (%v)", result, err)
+		}
+		msg := message.NewMessageBuilder().WithRoute("order.created").Build()
+		if _, err := h.Handle(context.Background(), msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := <-orders.msgReceived; got != msg {
+			t.Error("expected message routed to orders channel")
+		}
+	})
+
+	t.Run("should replace a route with the same id", func(t *testing.T) {
+		_, err := h.Handle(context.Background(), controlMessage(DynamicRouteCommand{
+			Operation:  DynamicRouteAdd,
+			RouteId:    "orders",
+			Expression: `header.route == "order.paid"`,
+			Channel:    "orders",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if routes := r.Routes(); len(routes) != 1 || routes[0].Expression != `header.route == "order.paid"` {
+			t.Errorf("expected the replaced route, got %v", routes)
+		}
+	})
+
+	t.Run("should remove a route", func(t *testing.T) {
+		_, err := h.Handle(context.Background(), controlMessage(DynamicRouteCommand{
+			Operation: DynamicRouteRemove,
+			RouteId:   "orders",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if routes := r.Routes(); len(routes) != 0 {
+			t.Errorf("expected no route, got %v", routes)
+		}
+	})
+
+	t.Run("should reject invalid commands", func(t *testing.T) {
+		for _, payload := range []any{
+			[]byte(`{"operation":`),
+			DynamicRouteCommand{Operation: "rename"},
+			DynamicRouteCommand{Operation: DynamicRouteAdd, Expression: `header.route == "x"`},
+		} {
+			if _, err := h.Handle(context.Background(), controlMessage(payload)); err == nil {
+				t.Errorf("expected error for %v", payload)
+			}
+		}
+	})
+}
+
+func TestDynamicRouter_AllowedChannels(t *testing.T) {
+	t.Parallel()
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("orders.created", &dummyChannel{msgReceived: make(chan *message.Message, 10)})
+	cont.Set("payments", &dummyChannel{msgReceived: make(chan *message.Message, 10)})
+
+	add := func(h message.MessageHandler, channelName string) error {
+		_, err := h.Handle(context.Background(), controlMessage(DynamicRouteCommand{
+			Operation:  DynamicRouteAdd,
+			Expression: `header.route == "order.created"`,
+			Channel:    channelName,
+		}))
+		return err
+	}
+
+	r := NewDynamicRouter().WithControlMessages("orders.*")
+	h := r.Bind(cont)
+	if err := add(h, "payments"); err == nil {
+		t.Error("expected control message to a channel not allowed to be rejected")
+	}
+	if err := add(h, "orders.created"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if routes := r.Routes(); len(routes) != 1 || routes[0].Channel != "orders.created" {
+		t.Errorf("expected only the allowed route, got %v", routes)
+	}
+
+	disabled := NewDynamicRouter()
+	if err := add(disabled.Bind(cont), "orders.created"); err == nil {
+		t.Error("expected control messages rejected when not enabled")
+	}
+	closed := NewDynamicRouter().WithControlMessages()
+	if err := add(closed.Bind(cont), "orders.created"); err == nil {
+		t.Error("expected no channel allowed without patterns")
+	}
+	if len(disabled.Routes())+len(closed.Routes()) != 0 {
+		t.Error("expected rejected control messages not to add routes")
+	}
+}
+
+func TestDynamicRouter_ControlMessagesKeepAPIRoutes(t *testing.T) {
+	t.Parallel()
+	r := NewDynamicRouter().WithControlMessages("*")
+	h := r.Bind(container.NewGenericContainer[any, any]())
+	apiRoute := r.AddRoute(func(m message.Message) bool { return true }, "orders")
+
+	for _, command := range []DynamicRouteCommand{
+		{
+			Operation:  DynamicRouteAdd,
+			RouteId:    apiRoute,
+			Expression: `header.route == "order.created"`,
+			Channel:    "payments",
+		},
+		{Operation: DynamicRouteRemove, RouteId: apiRoute},
+	} {
+		if _, err := h.Handle(context.Background(), controlMessage(command)); err == nil {
+			t.Errorf("expected %s of an API route rejected", command.Operation)
+		}
+	}
+	if routes := r.Routes(); len(routes) != 1 || routes[0].Channel != "orders" {
+		t.Errorf("expected the API route unchanged, got %v", routes)
+	}
+}
+
+func TestDynamicRouter_RouteStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "routes.json")
+	store, err := NewFileDynamicRouteStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := NewDynamicRouter().WithControlMessages("*").WithRouteStore(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h := r.Bind(container.NewGenericContainer[any, any]())
+	for _, command := range []DynamicRouteCommand{
+		{Operation: DynamicRouteAdd, RouteId: "paid", Expression: `header.route == "order.paid"`, Channel: "billing"},
+		{Operation: DynamicRouteAdd, RouteId: "created", Expression: `header.route == "order.created"`, Channel: "orders"},
+		{Operation: DynamicRouteRemove, RouteId: "paid"},
+	} {
+		if _, err := h.Handle(ctx, controlMessage(command)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// a restarted process restores the routes of the control messages
+	reopened, err := NewFileDynamicRouteStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restarted, err := NewDynamicRouter().WithRouteStore(ctx, reopened)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	routes := restarted.Routes()
+	if len(routes) != 1 || routes[0].Id != "created" || !routes[0].Controlled {
+		t.Fatalf("expected the created route restored, got %v", routes)
+	}
+
+	restarted.RemoveRoute("created")
+	if saved, _ := reopened.Routes(ctx); len(saved) != 0 {
+		t.Errorf("expected the removed route deleted from the store, got %v", saved)
+	}
+}
+
+// controlMessage builds a control message of the dynamic routers.
+func controlMessage(payload any) *message.Message {
+	return message.NewMessageBuilder().
+		WithRoute(DynamicRouterControlRoute).
+		WithPayload(payload).
+		Build()
+}
@@ -10,16 +10,24 @@
 // - Container-based channel resolution
 // - Flexible routing strategies
 // - Error handling for missing channels
+// - Version specific handlers of a route
+// - Router and handler entries in the Message History
 package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jeffersonbrasilino/gomes/container"
 	"github.com/jeffersonbrasilino/gomes/message"
+	messagechannel "github.com/jeffersonbrasilino/gomes/message/channel"
 )
 
+// ErrUnroutable is returned, wrapped, when no handler is registered for the
+// route of a message.
+var ErrUnroutable = errors.New("[recipient-list-router] unprocessable message")
+
 // recipientListRouter implements the Recipient List pattern, routing messages
 // to specific channels based on message headers and container configuration.
 type recipientListRouter struct {
@@ -48,7 +56,7 @@ func NewRecipientListRouter(
 //
 // Returns:
 //   - *message.Message: the original message if routing succeeds
-//   - error: error if the target channel is not found
+//   - error: error wrapping ErrUnroutable if the target channel is not found
 func (r *recipientListRouter) Handle(
 	ctx context.Context,
 	msg *message.Message,
@@ -58,7 +66,9 @@ func (r *recipientListRouter) Handle(
 
 	if err != nil {
 		return nil, fmt.Errorf(
-			"[recipient-list-router] unprocessable message, handler for action %v not exists",
+			"%w, %w for action %v",
+			ErrUnroutable,
+			message.ErrHandlerNotFound,
 			route,
 		)
 	}
@@ -71,13 +81,18 @@ func (r *recipientListRouter) Handle(
 		)
 	}
 
+	message.RecordHistory(msg, message.HistoryComponentRouter, route)
+	if _, isHandler := channel.(*messagechannel.PointToPointChannel); isHandler {
+		message.RecordHistory(msg, message.HistoryComponentHandler, channel.Name())
+	}
 	channel.Send(ctx, msg)
 
 	return msg, nil
 }
 
 // chooseRoute determines the appropriate route for a message based on its headers.
-// It prioritizes ChannelName over Route if both are present.
+// It prioritizes ChannelName over Route if both are present, and the handler
+// of the message version over the handler of the route.
 //
 // Parameters:
 //   - msg: the message to determine routing for
@@ -92,6 +107,25 @@ func (r *recipientListRouter) chooseRoute(msg *message.Message) string {
 
 	if msg.GetHeader().Get(message.HeaderRoute) != "" && route == "" {
 		route = msg.GetHeader().Get(message.HeaderRoute)
+		version := msg.GetHeader().Get(message.HeaderVersion)
+		if versioned := VersionedRoute(route, version); version != "" &&
+			r.gomesContainer.Has(versioned) {
+			route = versioned
+		}
 	}
 	return route
 }
+
+// VersionedRoute returns the name handlers of a single version of a route
+// are registered with. Messages of that version are routed to them instead
+// of the handler of the route.
+//
+// Parameters:
+//   - route: the message route
+//   - version: the message version
+//
+// Returns:
+//   - string: the versioned route name
+func VersionedRoute(route string, version string) string {
+	return route + "@" + version
+}
@@ -128,3 +128,33 @@ func TestHandle(t *testing.T) {
 		}
 	})
 }
+
+func TestHandle_VersionedRoute(t *testing.T) {
+	t.Parallel()
+	container := container.NewGenericContainer[any, any]()
+	routeChannel := &dummyChannel{msgReceived: make(chan *message.Message, 1)}
+	versionChannel := &dummyChannel{msgReceived: make(chan *message.Message, 1)}
+	container.Set("order.create", routeChannel)
+	container.Set(VersionedRoute("order.create", "2.0"), versionChannel)
+	r := NewRecipientListRouter(container)
+
+	t.Run("should route to the handler of the message version", func(t *testing.T) {
+		msg := message.NewMessageBuilder().WithRoute("order.create").WithVersion("2.0").Build()
+		if _, err := r.Handle(context.Background(), msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if received := <-versionChannel.msgReceived; received != msg {
+			t.Error("expected message on the version channel")
+		}
+	})
+
+	t.Run("should fall back to the handler of the route", func(t *testing.T) {
+		msg := message.NewMessageBuilder().WithRoute("order.create").WithVersion("1.0").Build()
+		if _, err := r.Handle(context.Background(), msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if received := <-routeChannel.msgReceived; received != msg {
+			t.Error("expected message on the route channel")
+		}
+	})
+}
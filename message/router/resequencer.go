@@ -0,0 +1,475 @@
+// Package router provides message routing components for the message system.
+//
+// The Resequencer implementation supports:
+// - Ordered delivery of out-of-order messages by their sequence number
+// - Independent sequences per sequence (causation) id
+// - Acknowledgment of the buffered messages deferred until their release
+// - Maximum buffer size, skipping the missing messages when exceeded
+// - Gap timeout, skipping the missing messages not received in time
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// defaultGroupTimeout is how long an idle sequence is kept by default.
+const defaultGroupTimeout = time.Hour
+
+// resequenceGroup holds the state of a sequence.
+type resequenceGroup struct {
+	next     int
+	size     int
+	buffer   map[int]*bufferedMessage
+	timer    *time.Timer
+	idle     *time.Timer
+	lastSeen time.Time
+}
+
+// bufferedMessage is a message held by a sequence, with the acknowledger
+// settling it once released.
+type bufferedMessage struct {
+	sequence     int
+	msg          *message.Message
+	acknowledger message.Acknowledger
+}
+
+// resequencer implements the Resequencer pattern, buffering out-of-order
+// messages and releasing them to an output channel in sequence order.
+type resequencer struct {
+	gomesContainer   container.Container[any, any]
+	outputChannel    string
+	sequenceHeader   string
+	sequenceIdHeader string
+	maxBufferSize    int
+	gapTimeout       time.Duration
+	groupTimeout     time.Duration
+	mu               sync.Mutex
+	groups           map[string]*resequenceGroup
+}
+
+// NewResequencer creates a new resequencer instance. Sequences start at 1 and
+// are read from the sequence number header, as set by the Splitter. The
+// sequence of a message is given by its causation id, which the Splitter sets
+// to the id of the split message, or by its correlation id.
+//
+// Parameters:
+//   - gomesContainer: container for resolving the output channel
+//   - outputChannel: the channel receiving the messages in order
+//
+// Returns:
+//   - *resequencer: configured resequencer
+func NewResequencer(
+	gomesContainer container.Container[any, any],
+	outputChannel string,
+) *resequencer {
+	return &resequencer{
+		gomesContainer: gomesContainer,
+		outputChannel:  outputChannel,
+		sequenceHeader: message.HeaderSequenceNumber,
+		groupTimeout:   defaultGroupTimeout,
+		groups:         map[string]*resequenceGroup{},
+	}
+}
+
+// WithSequenceHeader sets the header holding the sequence number, for
+// producers using their own header.
+//
+// Parameters:
+//   - header: the sequence number header
+//
+// Returns:
+//   - *resequencer: resequencer instance for method chaining
+func (r *resequencer) WithSequenceHeader(header string) *resequencer {
+	r.sequenceHeader = header
+	return r
+}
+
+// WithSequenceIdHeader sets the header identifying the sequence of a message,
+// for producers using their own header.
+//
+// Parameters:
+//   - header: the sequence id header
+//
+// Returns:
+//   - *resequencer: resequencer instance for method chaining
+func (r *resequencer) WithSequenceIdHeader(header string) *resequencer {
+	r.sequenceIdHeader = header
+	return r
+}
+
+// WithMaxBufferSize sets the maximum number of buffered messages of a
+// sequence. When exceeded, the missing messages are skipped and the buffered
+// ones released. Zero means unbounded.
+//
+// Parameters:
+//   - size: the maximum buffer size
+//
+// Returns:
+//   - *resequencer: resequencer instance for method chaining
+func (r *resequencer) WithMaxBufferSize(size int) *resequencer {
+	r.maxBufferSize = size
+	return r
+}
+
+// WithGapTimeout sets how long a sequence waits for a missing message before
+// skipping it. Zero means it waits forever.
+//
+// Parameters:
+//   - timeout: the gap timeout
+//
+// Returns:
+//   - *resequencer: resequencer instance for method chaining
+func (r *resequencer) WithGapTimeout(timeout time.Duration) *resequencer {
+	r.gapTimeout = timeout
+	return r
+}
+
+// WithGroupTimeout sets how long a sequence is kept without receiving
+// messages, one hour by default. When it elapses, the buffered messages are
+// released skipping the missing ones, and the sequence is forgotten, so
+// sequences without a size header do not accumulate. Zero means sequences
+// without a size header are kept forever.
+//
+// Parameters:
+//   - timeout: the group timeout
+//
+// Returns:
+//   - *resequencer: resequencer instance for method chaining
+func (r *resequencer) WithGroupTimeout(timeout time.Duration) *resequencer {
+	r.groupTimeout = timeout
+	return r
+}
+
+// Handle buffers a message and releases to the output channel every message
+// now in sequence. Messages older than the next expected one, e.g. arriving
+// after their gap was skipped, and redelivered messages already buffered are
+// discarded.
+//
+// The acknowledgment of a buffered message is deferred through the
+// message.DeferrableAcknowledger of its context, and the message is
+// acknowledged once sent to the output channel, so a crash does not lose the
+// buffered messages. The messages which cannot be sent stay buffered, and
+// their sending is retried with the next message of their sequence or on its
+// timeouts. On Kafka, the offsets
+// of the deferred messages are kept uncommitted only with the tracked commits,
+// e.g. with kafka.WithCommitEvery or handler.AckOnSuccess.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be resequenced
+//
+// Returns:
+//   - *message.Message: the last released message, nil if none was released
+//   - error: error if the sequence header is invalid or a message cannot be
+//     sent
+func (r *resequencer) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	sequence, err := strconv.Atoi(msg.GetHeader().Get(r.sequenceHeader))
+	if err != nil || sequence < 1 {
+		return nil, fmt.Errorf(
+			"[resequencer] message %s has invalid %s header",
+			msg.GetHeader().Get(message.HeaderMessageId),
+			r.sequenceHeader,
+		)
+	}
+
+	sequenceId := r.sequenceIdOf(msg)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	group, ok := r.groups[sequenceId]
+	if !ok {
+		group = &resequenceGroup{next: 1, buffer: map[int]*bufferedMessage{}}
+		r.groups[sequenceId] = group
+	}
+	group.lastSeen = time.Now()
+
+	if sequence < group.next {
+		r.settle(sequenceId, group)
+		slog.Warn("[resequencer] discarded message behind its sequence",
+			"messageId", msg.GetHeader().Get(message.HeaderMessageId),
+			"sequenceId", sequenceId,
+			"sequence", sequence,
+			"expected", group.next,
+		)
+		return nil, nil
+	}
+
+	if size, err := strconv.Atoi(msg.GetHeader().Get(message.HeaderSequenceSize)); err == nil {
+		group.size = size
+	}
+	// a redelivered message already buffered keeps the buffered one, whose
+	// acknowledgment is deferred, and retries the release of the sequence
+	buffered, duplicated := group.buffer[sequence]
+	if !duplicated {
+		buffered = &bufferedMessage{sequence: sequence, msg: msg}
+		group.buffer[sequence] = buffered
+	}
+
+	ready := group.releaseInOrder()
+	if r.maxBufferSize > 0 && len(group.buffer) > r.maxBufferSize {
+		ready = append(ready, group.skipGap()...)
+	}
+	if !duplicated && group.buffer[sequence] == buffered {
+		buffered.acknowledger = deferAcknowledgment(ctx, msg)
+	}
+
+	released, err := r.send(ctx, group, ready)
+	if err != nil && !duplicated && group.buffer[sequence] == buffered &&
+		buffered.acknowledger == nil {
+		buffered.acknowledger = deferAcknowledgment(ctx, msg)
+	}
+	r.settle(sequenceId, group)
+	return released, err
+}
+
+// sequenceIdOf returns the sequence of a message: its sequence id header, if
+// configured, or its causation or correlation id.
+func (r *resequencer) sequenceIdOf(msg *message.Message) string {
+	if r.sequenceIdHeader != "" {
+		return msg.GetHeader().Get(r.sequenceIdHeader)
+	}
+	return groupIdOf(msg)
+}
+
+// deferAcknowledgment defers the settlement of a buffered message, returning
+// its acknowledger, or nil if the message is not settled by an acknowledger.
+func deferAcknowledgment(
+	ctx context.Context,
+	msg *message.Message,
+) message.Acknowledger {
+	acknowledger, ok := message.AcknowledgerFromContext(ctx)
+	if !ok {
+		acknowledger, ok = message.AcknowledgerFromContext(msg.GetContext())
+	}
+	deferrable, deferrableOk := acknowledger.(message.DeferrableAcknowledger)
+	if !ok || !deferrableOk {
+		return nil
+	}
+	deferrable.Defer()
+	return deferrable
+}
+
+// releaseInOrder removes from the buffer the messages in sequence.
+func (g *resequenceGroup) releaseInOrder() []*bufferedMessage {
+	ready := []*bufferedMessage{}
+	for {
+		buffered, ok := g.buffer[g.next]
+		if !ok {
+			return ready
+		}
+		delete(g.buffer, g.next)
+		ready = append(ready, buffered)
+		g.next++
+	}
+}
+
+// skipGap moves the sequence to the lowest buffered message and releases the
+// messages in sequence.
+func (g *resequenceGroup) skipGap() []*bufferedMessage {
+	lowest := 0
+	for sequence := range g.buffer {
+		if lowest == 0 || sequence < lowest {
+			lowest = sequence
+		}
+	}
+	if lowest == 0 {
+		return nil
+	}
+	g.next = lowest
+	return g.releaseInOrder()
+}
+
+// releaseAll removes every buffered message, in sequence, skipping the gaps.
+func (g *resequenceGroup) releaseAll() []*bufferedMessage {
+	ready := []*bufferedMessage{}
+	for len(g.buffer) > 0 {
+		ready = append(ready, g.skipGap()...)
+	}
+	return ready
+}
+
+// settle updates the timers of a group and forgets completed sequences. The
+// caller holds the lock.
+func (r *resequencer) settle(sequenceId string, group *resequenceGroup) {
+	if len(group.buffer) == 0 {
+		if group.timer != nil {
+			group.timer.Stop()
+			group.timer = nil
+		}
+		if group.size > 0 && group.next > group.size {
+			r.forget(sequenceId, group)
+			return
+		}
+	}
+
+	if group.timer == nil && len(group.buffer) > 0 && r.gapTimeout > 0 {
+		group.timer = time.AfterFunc(r.gapTimeout, func() {
+			r.expire(sequenceId, group)
+		})
+	}
+	if group.idle == nil && r.groupTimeout > 0 {
+		group.idle = time.AfterFunc(r.groupTimeout, func() {
+			r.expireIdle(sequenceId, group)
+		})
+	}
+}
+
+// forget removes a group and stops its timers. The caller holds the lock.
+func (r *resequencer) forget(sequenceId string, group *resequenceGroup) {
+	delete(r.groups, sequenceId)
+	if group.timer != nil {
+		group.timer.Stop()
+	}
+	if group.idle != nil {
+		group.idle.Stop()
+	}
+}
+
+// expire skips the gap of a group when its gap timeout elapses.
+func (r *resequencer) expire(sequenceId string, group *resequenceGroup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.groups[sequenceId] != group {
+		return
+	}
+	group.timer = nil
+	slog.Warn("[resequencer] gap timeout, skipping missing messages",
+		"sequenceId", sequenceId,
+		"expected", group.next,
+	)
+	if _, err := r.send(context.Background(), group, group.skipGap()); err != nil {
+		slog.Error("[resequencer] failed to release messages after gap timeout",
+			"sequenceId", sequenceId,
+			"reason", err.Error(),
+		)
+	}
+	r.settle(sequenceId, group)
+}
+
+// expireIdle releases the buffered messages of a group and forgets it when no
+// message was received within the group timeout. The messages which cannot be
+// sent are requeued.
+func (r *resequencer) expireIdle(sequenceId string, group *resequenceGroup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.groups[sequenceId] != group {
+		return
+	}
+	if idle := time.Since(group.lastSeen); idle < r.groupTimeout {
+		group.idle.Reset(r.groupTimeout - idle)
+		return
+	}
+	if len(group.buffer) > 0 {
+		slog.Warn("[resequencer] group timeout, releasing buffered messages",
+			"sequenceId", sequenceId,
+			"expected", group.next,
+			"buffered", len(group.buffer),
+		)
+	}
+	if _, err := r.send(context.Background(), group, group.releaseAll()); err != nil {
+		slog.Error("[resequencer] failed to release messages after group timeout",
+			"sequenceId", sequenceId,
+			"reason", err.Error(),
+		)
+		requeue(group.releaseAll())
+	}
+	r.forget(sequenceId, group)
+}
+
+// send delivers the released messages of a group to the output channel, in
+// order, acknowledging the deferred ones once sent. When a message cannot be
+// sent, it and the following ones are buffered back into the group. The
+// caller holds the lock.
+func (r *resequencer) send(
+	ctx context.Context,
+	group *resequenceGroup,
+	messages []*bufferedMessage,
+) (*message.Message, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	channel, err := r.outputPublisher()
+	if err != nil {
+		group.restore(messages)
+		return nil, err
+	}
+
+	for index, buffered := range messages {
+		if err := channel.Send(ctx, buffered.msg); err != nil {
+			group.restore(messages[index:])
+			return nil, fmt.Errorf(
+				"[resequencer] failed to send message to %s: %w",
+				r.outputChannel,
+				err,
+			)
+		}
+		if buffered.acknowledger != nil {
+			if err := buffered.acknowledger.Ack(); err != nil {
+				slog.Error("[resequencer] failed to acknowledge released message",
+					"messageId", buffered.msg.GetHeader().Get(message.HeaderMessageId),
+					"reason", err.Error(),
+				)
+			}
+		}
+	}
+
+	return messages[len(messages)-1].msg, nil
+}
+
+// restore buffers back the messages which could not be sent, moving the
+// sequence back to the first of them.
+func (g *resequenceGroup) restore(messages []*bufferedMessage) {
+	for _, buffered := range messages {
+		g.buffer[buffered.sequence] = buffered
+	}
+	g.next = messages[0].sequence
+}
+
+// outputPublisher resolves the output channel.
+func (r *resequencer) outputPublisher() (message.PublisherChannel, error) {
+	anyChannel, err := r.gomesContainer.Get(r.outputChannel)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[resequencer] %w: %s",
+			message.ErrChannelNotFound,
+			r.outputChannel,
+		)
+	}
+
+	channel, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[resequencer] channel %s does not implement PublisherChannel",
+			r.outputChannel,
+		)
+	}
+	return channel, nil
+}
+
+// requeue settles the deferred messages which could not be released, for
+// their redelivery.
+func requeue(messages []*bufferedMessage) {
+	for _, buffered := range messages {
+		if buffered.acknowledger == nil {
+			continue
+		}
+		if err := buffered.acknowledger.Nack(true); err != nil {
+			slog.Error("[resequencer] failed to requeue message",
+				"messageId", buffered.msg.GetHeader().Get(message.HeaderMessageId),
+				"reason", err.Error(),
+			)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package router
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func newSequencedMessage(correlationId string, sequence int, size int) *message.Message {
+	builder := message.NewMessageBuilder().
+		WithCorrelationId(correlationId).
+		WithPayload(sequence).
+		WithCustomHeader(message.HeaderSequenceNumber, strconv.Itoa(sequence))
+	if size > 0 {
+		builder.WithCustomHeader(message.HeaderSequenceSize, strconv.Itoa(size))
+	}
+	return builder.Build()
+}
+
+func receivePayloads(output *dummyChannel, count int) []any {
+	payloads := []any{}
+	for range count {
+		select {
+		case msg := <-output.msgReceived:
+			payloads = append(payloads, msg.GetPayload())
+		case <-time.After(time.Second):
+			return payloads
+		}
+	}
+	return payloads
+}
+
+func TestResequencer_Handle(t *testing.T) {
+	t.Parallel()
+	output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("ordered", output)
+	r := NewResequencer(cont, "ordered")
+
+	for _, sequence := range []int{3, 2} {
+		result, err := r.Handle(context.Background(), newSequencedMessage("a", sequence, 3))
+		if err != nil || result != nil {
+			t.Fatalf("expected message to be buffered, got %v (%v)", result, err)
+		}
+	}
+
+	result, err := r.Handle(context.Background(), newSequencedMessage("a", 1, 3))
+	if err != nil || result == nil || result.GetPayload() != 3 {
+		t.Fatalf("expected last released message, got %v (%v)", result, err)
+	}
+	if payloads := receivePayloads(output, 3); len(payloads) != 3 ||
+		payloads[0] != 1 || payloads[1] != 2 || payloads[2] != 3 {
+		t.Errorf("expected messages in order, got %v", payloads)
+	}
+	if len(r.groups) != 0 {
+		t.Error("expected completed sequence to be forgotten")
+	}
+
+	if _, err := r.Handle(context.Background(), message.NewMessageBuilder().Build()); err == nil {
+		t.Error("expected error for message without sequence header")
+	}
+}
+
+func TestResequencer_Safeguards(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should skip the gap when the buffer is full", func(t *testing.T) {
+		t.Parallel()
+		output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("ordered", output)
+		r := NewResequencer(cont, "ordered").WithMaxBufferSize(2)
+
+		r.Handle(context.Background(), newSequencedMessage("b", 3, 0))
+		r.Handle(context.Background(), newSequencedMessage("b", 4, 0))
+		r.Handle(context.Background(), newSequencedMessage("b", 6, 0))
+		if payloads := receivePayloads(output, 2); len(payloads) != 2 ||
+			payloads[0] != 3 || payloads[1] != 4 {
+			t.Errorf("expected buffered messages after the gap, got %v", payloads)
+		}
+
+		result, _ := r.Handle(context.Background(), newSequencedMessage("b", 1, 0))
+		if result != nil {
+			t.Error("expected message behind the sequence to be discarded")
+		}
+	})
+
+	t.Run("should skip the gap on timeout", func(t *testing.T) {
+		t.Parallel()
+		output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("ordered", output)
+		r := NewResequencer(cont, "ordered").
+			WithSequenceHeader("position").
+			WithGapTimeout(20 * time.Millisecond)
+
+		msg := message.NewMessageBuilder().
+			WithCorrelationId("c").
+			WithPayload("second").
+			WithCustomHeader("position", "2").
+			Build()
+		r.Handle(context.Background(), msg)
+		if payloads := receivePayloads(output, 1); len(payloads) != 1 || payloads[0] != "second" {
+			t.Errorf("expected message released after gap timeout, got %v", payloads)
+		}
+	})
+}
+
+// recordingAcknowledger records the settlement of a message.
+type recordingAcknowledger struct {
+	deferred bool
+	acked    bool
+	nacked   bool
+}
+
+func (a *recordingAcknowledger) Defer() { a.deferred = true }
+
+func (a *recordingAcknowledger) Ack() error {
+	a.acked = true
+	return nil
+}
+
+func (a *recordingAcknowledger) Nack(requeue bool) error {
+	a.nacked = true
+	return nil
+}
+
+func (a *recordingAcknowledger) Reject() error { return a.Nack(false) }
+
+func TestResequencer_DefersTheAcknowledgment(t *testing.T) {
+	t.Parallel()
+	output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("ordered", output)
+	r := NewResequencer(cont, "ordered")
+
+	second := &recordingAcknowledger{}
+	ctx := message.ContextWithAcknowledger(context.Background(), second)
+	r.Handle(ctx, newSequencedMessage("a", 2, 2))
+	if !second.deferred || second.acked {
+		t.Fatal("expected the acknowledgment of the buffered message deferred")
+	}
+
+	output.shouldError = true
+	first := &recordingAcknowledger{}
+	ctx = message.ContextWithAcknowledger(context.Background(), first)
+	if _, err := r.Handle(ctx, newSequencedMessage("a", 1, 2)); err == nil {
+		t.Fatal("expected send error")
+	}
+	if !first.deferred || second.acked {
+		t.Fatal("expected the messages not sent kept buffered and unacknowledged")
+	}
+
+	output.shouldError = false
+	redelivered := &recordingAcknowledger{}
+	ctx = message.ContextWithAcknowledger(context.Background(), redelivered)
+	r.Handle(ctx, newSequencedMessage("a", 2, 2))
+	if redelivered.deferred {
+		t.Error("expected the redelivered message not buffered")
+	}
+	if payloads := receivePayloads(output, 2); len(payloads) != 2 ||
+		payloads[0] != 1 || payloads[1] != 2 {
+		t.Errorf("expected the buffered messages released in order, got %v", payloads)
+	}
+	if !first.acked || !second.acked {
+		t.Error("expected the released messages acknowledged")
+	}
+	if len(r.groups) != 0 {
+		t.Error("expected completed sequence to be forgotten")
+	}
+}
+
+func TestResequencer_SequencesAndGroupTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should keep the sequences of a workflow apart", func(t *testing.T) {
+		t.Parallel()
+		output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("ordered", output)
+		r := NewResequencer(cont, "ordered")
+
+		newMsg := func(sequenceId string, sequence int) *message.Message {
+			msg := newSequencedMessage("workflow", sequence, 2)
+			msg.GetHeader()[message.HeaderCausationId] = sequenceId
+			return msg
+		}
+		r.Handle(context.Background(), newMsg("split-1", 2))
+		result, _ := r.Handle(context.Background(), newMsg("split-2", 1))
+		if result == nil || result.GetPayload() != 1 {
+			t.Fatalf("expected the first message of the second split, got %v", result)
+		}
+		if payloads := receivePayloads(output, 1); len(payloads) != 1 {
+			t.Errorf("expected only the second split released, got %v", payloads)
+		}
+		if len(r.groups) != 2 {
+			t.Errorf("expected a group per sequence, got %d", len(r.groups))
+		}
+	})
+
+	t.Run("should release and forget idle sequences", func(t *testing.T) {
+		t.Parallel()
+		output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+		cont := container.NewGenericContainer[any, any]()
+		cont.Set("ordered", output)
+		r := NewResequencer(cont, "ordered").WithGroupTimeout(20 * time.Millisecond)
+
+		r.Handle(context.Background(), newSequencedMessage("d", 1, 0))
+		r.Handle(context.Background(), newSequencedMessage("d", 3, 0))
+		if payloads := receivePayloads(output, 2); len(payloads) != 2 || payloads[1] != 3 {
+			t.Errorf("expected the buffered message released on group timeout, got %v", payloads)
+		}
+		deadline := time.Now().Add(time.Second)
+		for {
+			r.mu.Lock()
+			groups := len(r.groups)
+			r.mu.Unlock()
+			if groups == 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("expected the idle sequence forgotten")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Package router provides message routing components for the message system.
+//
+// The Splitter implementation supports:
+// - Decomposition of one message into N child messages
+// - Correlation of the child messages to the source message
+// - Sequence number and size headers, enabling re-aggregation
+// - Delivery of the child messages to an output channel
+package router
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+// SplitFunc defines the contract for message splitting functions. It takes a
+// message and returns the payloads of its child messages.
+type SplitFunc func(msg *message.Message) ([]any, error)
+
+// splitter implements the Splitter pattern, breaking one message into many
+// child messages sent to an output channel.
+type splitter struct {
+	gomesContainer container.Container[any, any]
+	splitFunc      SplitFunc
+	outputChannel  string
+}
+
+// NewSplitter creates a new splitter instance.
+//
+// Parameters:
+//   - gomesContainer: container for resolving the output channel
+//   - splitFunc: the function producing the child payloads
+//   - outputChannel: the channel receiving the child messages
+//
+// Returns:
+//   - *splitter: configured splitter
+func NewSplitter(
+	gomesContainer container.Container[any, any],
+	splitFunc SplitFunc,
+	outputChannel string,
+) *splitter {
+	return &splitter{
+		gomesContainer: gomesContainer,
+		splitFunc:      splitFunc,
+		outputChannel:  outputChannel,
+	}
+}
+
+// Handle splits a message and sends each child message to the output channel.
+// Child messages keep the source headers, get a new message id, the source
+// correlation id (or the source message id when it has none), the source
+// message id as causation id and the sequence number/size headers.
+//
+// Parameters:
+//   - ctx: context for timeout/cancellation control
+//   - msg: the message to be split
+//
+// Returns:
+//   - *message.Message: the original message if all children are sent
+//   - error: error if splitting fails or a child message cannot be sent
+func (s *splitter) Handle(
+	ctx context.Context,
+	msg *message.Message,
+) (*message.Message, error) {
+	payloads, err := s.splitFunc(msg)
+	if err != nil {
+		return nil, fmt.Errorf("[splitter] failed to split message: %w", err)
+	}
+
+	anyChannel, err := s.gomesContainer.Get(s.outputChannel)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[splitter] %w: %s",
+			message.ErrChannelNotFound,
+			s.outputChannel,
+		)
+	}
+
+	channel, ok := anyChannel.(message.PublisherChannel)
+	if !ok {
+		return nil, fmt.Errorf(
+			"[splitter] channel %s does not implement PublisherChannel",
+			s.outputChannel,
+		)
+	}
+
+	messageId := msg.GetHeader().Get(message.HeaderMessageId)
+	correlationId := msg.GetHeader().Get(message.HeaderCorrelationId)
+	if correlationId == "" {
+		correlationId = messageId
+	}
+
+	for index, payload := range payloads {
+		child := message.NewMessageBuilderFromMessage(msg).
+			WithMessageId("").
+			WithPayload(payload).
+			WithContext(ctx).
+			WithCorrelationId(correlationId).
+			WithCustomHeader(message.HeaderCausationId, messageId).
+			WithCustomHeader(message.HeaderSequenceNumber, strconv.Itoa(index+1)).
+			WithCustomHeader(message.HeaderSequenceSize, strconv.Itoa(len(payloads))).
+			Build()
+
+		if err := channel.Send(ctx, child); err != nil {
+			return nil, fmt.Errorf(
+				"[splitter] failed to send message %d of %d to %s: %w",
+				index+1,
+				len(payloads),
+				s.outputChannel,
+				err,
+			)
+		}
+	}
+
+	return msg, nil
+}
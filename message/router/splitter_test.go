@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func splitWords(msg *message.Message) ([]any, error) {
+	words := []any{}
+	for _, word := range strings.Fields(msg.GetPayload().(string)) {
+		words = append(words, word)
+	}
+	return words, nil
+}
+
+func TestSplitter_Handle(t *testing.T) {
+	t.Parallel()
+	output := &dummyChannel{msgReceived: make(chan *message.Message, 10)}
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("words", output)
+
+	source := message.NewMessageBuilder().
+		WithPayload("split this message").
+		WithRoute("sentence").
+		Build()
+	result, err := NewSplitter(cont, splitWords, "words").Handle(context.Background(), source)
+	if err != nil || result != source {
+		t.Fatalf("expected source message, got %v (%v)", result, err)
+	}
+
+	sourceId := source.GetHeader().Get(message.HeaderMessageId)
+	expected := []string{"split", "this", "message"}
+	for i, word := range expected {
+		child := <-output.msgReceived
+		header := child.GetHeader()
+		if child.GetPayload() != word {
+			t.Errorf("expected payload %s, got %v", word, child.GetPayload())
+		}
+		if header.Get(message.HeaderCorrelationId) != sourceId ||
+			header.Get(message.HeaderCausationId) != sourceId {
+			t.Errorf("expected child correlated to source %s, got %v", sourceId, header)
+		}
+		if header.Get(message.HeaderMessageId) == sourceId {
+			t.Error("expected child to have its own message id")
+		}
+		if header.Get(message.HeaderSequenceNumber) != string(rune('1'+i)) ||
+			header.Get(message.HeaderSequenceSize) != "3" {
+			t.Errorf("unexpected sequence headers %v", header)
+		}
+		if header.Get(message.HeaderRoute) != "sentence" {
+			t.Errorf("expected route to be kept, got %s", header.Get(message.HeaderRoute))
+		}
+	}
+}
+
+func TestSplitter_HandleErrors(t *testing.T) {
+	t.Parallel()
+	cont := container.NewGenericContainer[any, any]()
+	cont.Set("failing", &dummyChannel{shouldError: true})
+	msg := message.NewMessageBuilder().WithPayload("a b").Build()
+
+	failingSplit := func(*message.Message) ([]any, error) {
+		return nil, errors.New("invalid payload")
+	}
+	if _, err := NewSplitter(cont, failingSplit, "failing").Handle(context.Background(), msg); err == nil {
+		t.Error("expected split error")
+	}
+	if _, err := NewSplitter(cont, splitWords, "missing").Handle(context.Background(), msg); err == nil {
+		t.Error("expected channel not found error")
+	}
+	if _, err := NewSplitter(cont, splitWords, "failing").Handle(context.Background(), msg); err == nil {
+		t.Error("expected send error")
+	}
+}
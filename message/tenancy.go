@@ -0,0 +1,21 @@
+// Package message provides the multi-tenancy contracts of the message system.
+package message
+
+// TenantChannelStrategy maps the channel of a message to the channel of its
+// tenant, e.g. "orders" to "orders.tenant-a", so the messages of each tenant
+// are published to their own channel or topic.
+type TenantChannelStrategy func(channelName string, tenantId string) string
+
+// TenantChannelSuffix creates a strategy appending the tenant to the channel
+// name, joined by the separator.
+//
+// Parameters:
+//   - separator: the separator between the channel name and the tenant
+//
+// Returns:
+//   - TenantChannelStrategy: the suffix strategy
+func TenantChannelSuffix(separator string) TenantChannelStrategy {
+	return func(channelName string, tenantId string) string {
+		return channelName + separator + tenantId
+	}
+}
@@ -0,0 +1,294 @@
+// Package otel provides an implementation for OpenTelemetry metrics
+// functionality. Intent: record the messaging instruments defined by the
+// OpenTelemetry semantic conventions. Objective: let operators chart
+// throughput and processing latency of every channel without custom code.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Instrument names, as defined by the messaging semantic conventions.
+const (
+	MetricProcessDuration   = "messaging.process.duration"
+	MetricConsumedMessages  = "messaging.client.consumed.messages"
+	MetricPublishedMessages = "messaging.client.published.messages"
+	// Messages waiting to be consumed: the consumer lag of Kafka consumer
+	// groups or the depth of RabbitMQ queues.
+	MetricConsumerBacklog = "messaging.consumer.backlog"
+)
+
+var metricsEnabled bool = false
+
+// EnableMetrics enables metrics for the message system.
+func EnableMetrics() {
+	mu.Lock()
+	defer mu.Unlock()
+	metricsEnabled = true
+}
+
+// isMetricsEnabled reports whether metrics are enabled.
+func isMetricsEnabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return metricsEnabled
+}
+
+// OtelMetrics is an interface to record the messaging instruments.
+type OtelMetrics interface {
+	// RecordProcessDuration records how long a message took to be processed.
+	// Parameters:
+	//   ctx: context of the processing.
+	//   systemType: messaging system of the message.
+	//   msg: processed message.
+	//   duration: processing duration.
+	//   err: processing error, if any.
+	RecordProcessDuration(
+		ctx context.Context,
+		systemType MessageSystemType,
+		msg *message.Message,
+		duration time.Duration,
+		err error,
+	)
+	// AddConsumed counts a message consumed from a channel.
+	// Parameters:
+	//   ctx: context of the consumption.
+	//   systemType: messaging system of the channel.
+	//   destination: channel the message was consumed from.
+	//   msg: consumed message.
+	AddConsumed(
+		ctx context.Context,
+		systemType MessageSystemType,
+		destination string,
+		msg *message.Message,
+	)
+	// AddPublished counts a message published to a channel.
+	// Parameters:
+	//   ctx: context of the publishing.
+	//   systemType: messaging system of the channel.
+	//   destination: channel the message was published to.
+	//   msg: published message.
+	//   err: publishing error, if any.
+	AddPublished(
+		ctx context.Context,
+		systemType MessageSystemType,
+		destination string,
+		msg *message.Message,
+		err error,
+	)
+	// RecordBacklog records how many messages wait to be consumed from a
+	// channel.
+	// Parameters:
+	//   ctx: context of the collection.
+	//   systemType: messaging system of the channel.
+	//   destination: channel the backlog belongs to.
+	//   backlog: messages waiting to be consumed.
+	RecordBacklog(
+		ctx context.Context,
+		systemType MessageSystemType,
+		destination string,
+		backlog int64,
+	)
+}
+
+// otelMetrics implements the OtelMetrics interface, creating its instruments
+// on the first record after metrics are enabled.
+type otelMetrics struct {
+	scopeName         string
+	once              sync.Once
+	processDuration   metric.Float64Histogram
+	consumedMessages  metric.Int64Counter
+	publishedMessages metric.Int64Counter
+	consumerBacklog   metric.Int64Gauge
+}
+
+// InitMetrics creates a new metrics instance for the given instrumentation
+// scope. The instruments are created with the global MeterProvider.
+//
+// Parameters:
+//   - scopeName string - name of the instrumentation scope
+//
+// Returns:
+//   - *otelMetrics - configured metrics instance
+//
+// Example usage:
+//
+//	metrics := otel.InitMetrics("kafka-inbound-channel-adapter")
+func InitMetrics(scopeName string) *otelMetrics {
+	return &otelMetrics{scopeName: scopeName}
+}
+
+// RecordProcessDuration records the messaging.process.duration histogram.
+//
+// Parameters:
+//   - ctx context.Context - context of the processing
+//   - systemType MessageSystemType - messaging system of the message
+//   - msg *message.Message - processed message
+//   - duration time.Duration - processing duration
+//   - err error - processing error, if any
+func (m *otelMetrics) RecordProcessDuration(
+	ctx context.Context,
+	systemType MessageSystemType,
+	msg *message.Message,
+	duration time.Duration,
+	err error,
+) {
+	if !m.init() {
+		return
+	}
+	destination := msg.GetHeader().Get(message.HeaderChannelName)
+	if destination == "" {
+		destination = msg.GetHeader().Get(message.HeaderRoute)
+	}
+	m.processDuration.Record(
+		ctx,
+		duration.Seconds(),
+		metric.WithAttributes(
+			metricAttributes(systemType, SpanOperationProcess, destination, msg, err)...,
+		),
+	)
+}
+
+// AddConsumed increments the messaging.client.consumed.messages counter.
+//
+// Parameters:
+//   - ctx context.Context - context of the consumption
+//   - systemType MessageSystemType - messaging system of the channel
+//   - destination string - channel the message was consumed from
+//   - msg *message.Message - consumed message
+func (m *otelMetrics) AddConsumed(
+	ctx context.Context,
+	systemType MessageSystemType,
+	destination string,
+	msg *message.Message,
+) {
+	if !m.init() {
+		return
+	}
+	m.consumedMessages.Add(
+		ctx,
+		1,
+		metric.WithAttributes(
+			metricAttributes(systemType, SpanOperationReceive, destination, msg, nil)...,
+		),
+	)
+}
+
+// AddPublished increments the messaging.client.published.messages counter.
+//
+// Parameters:
+//   - ctx context.Context - context of the publishing
+//   - systemType MessageSystemType - messaging system of the channel
+//   - destination string - channel the message was published to
+//   - msg *message.Message - published message
+//   - err error - publishing error, if any
+func (m *otelMetrics) AddPublished(
+	ctx context.Context,
+	systemType MessageSystemType,
+	destination string,
+	msg *message.Message,
+	err error,
+) {
+	if !m.init() {
+		return
+	}
+	m.publishedMessages.Add(
+		ctx,
+		1,
+		metric.WithAttributes(
+			metricAttributes(systemType, SpanOperationSend, destination, msg, err)...,
+		),
+	)
+}
+
+// RecordBacklog records the messaging.consumer.backlog gauge.
+//
+// Parameters:
+//   - ctx context.Context - context of the collection
+//   - systemType MessageSystemType - messaging system of the channel
+//   - destination string - channel the backlog belongs to
+//   - backlog int64 - messages waiting to be consumed
+func (m *otelMetrics) RecordBacklog(
+	ctx context.Context,
+	systemType MessageSystemType,
+	destination string,
+	backlog int64,
+) {
+	if !m.init() {
+		return
+	}
+	m.consumerBacklog.Record(
+		ctx,
+		backlog,
+		metric.WithAttributes(
+			metricAttributes(systemType, SpanOperationReceive, destination, nil, nil)...,
+		),
+	)
+}
+
+// init creates the instruments once metrics are enabled.
+//
+// Returns:
+//   - bool - true if the instruments are ready to record
+func (m *otelMetrics) init() bool {
+	if !isMetricsEnabled() {
+		return false
+	}
+	m.once.Do(func() {
+		meter := otel.Meter(m.scopeName)
+		m.processDuration, _ = meter.Float64Histogram(
+			MetricProcessDuration,
+			metric.WithUnit("s"),
+			metric.WithDescription("Duration of processing operation."),
+		)
+		m.consumedMessages, _ = meter.Int64Counter(
+			MetricConsumedMessages,
+			metric.WithUnit("{message}"),
+			metric.WithDescription("Number of messages that were delivered to the application."),
+		)
+		m.publishedMessages, _ = meter.Int64Counter(
+			MetricPublishedMessages,
+			metric.WithUnit("{message}"),
+			metric.WithDescription("Number of messages producer attempted to publish to the broker."),
+		)
+		m.consumerBacklog, _ = meter.Int64Gauge(
+			MetricConsumerBacklog,
+			metric.WithUnit("{message}"),
+			metric.WithDescription("Number of messages waiting to be consumed."),
+		)
+	})
+	return true
+}
+
+// metricAttributes builds the semantic convention attributes of a messaging
+// instrument.
+func metricAttributes(
+	systemType MessageSystemType,
+	operation SpanOperation,
+	destination string,
+	msg *message.Message,
+	err error,
+) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attribute.String("messaging.system", systemType.String()),
+		attribute.String("messaging.operation.name", operation.String()),
+		attribute.String("messaging.destination.name", destination),
+	}
+	if msg != nil {
+		attributes = append(attributes,
+			attribute.String("messaging.message.type", msg.GetHeader().Get(message.HeaderMessageType)),
+		)
+	}
+	if err != nil {
+		attributes = append(attributes, attribute.String("error.type", fmt.Sprintf("%T", err)))
+	}
+	return attributes
+}
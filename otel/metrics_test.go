@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeffersonbrasilino/gomes/message"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("metricAttributes follows the semantic conventions", func(t *testing.T) {
+		t.Parallel()
+		hdrs := message.NewHeader(map[string]string{message.HeaderMessageType: "Command"})
+		msg := message.NewMessage(context.Background(), nil, hdrs)
+
+		attrs := metricAttributes(
+			MessageSystemTypeKafka,
+			SpanOperationReceive,
+			"orders",
+			msg,
+			errors.New("boom"),
+		)
+		values := map[string]string{}
+		for _, attr := range attrs {
+			values[string(attr.Key)] = attr.Value.AsString()
+		}
+		expected := map[string]string{
+			"messaging.system":           "kafka",
+			"messaging.operation.name":   "receive",
+			"messaging.destination.name": "orders",
+			"messaging.message.type":     "Command",
+			"error.type":                 "*errors.errorString",
+		}
+		for key, value := range expected {
+			if values[key] != value {
+				t.Errorf("expected %s=%s, got %s", key, value, values[key])
+			}
+		}
+	})
+
+	t.Run("EnableMetrics records with the global meter provider", func(t *testing.T) {
+		t.Parallel()
+		metrics := InitMetrics("svc-metrics-test")
+		hdrs := message.NewHeader(map[string]string{message.HeaderRoute: "route-x"})
+		msg := message.NewMessage(context.Background(), nil, hdrs)
+
+		EnableMetrics()
+		if !isMetricsEnabled() {
+			t.Fatal("expected metrics enabled")
+		}
+		metrics.RecordProcessDuration(
+			context.Background(),
+			MessageSystemTypeInternal,
+			msg,
+			time.Millisecond,
+			nil,
+		)
+		metrics.AddConsumed(context.Background(), MessageSystemTypeKafka, "topic", msg)
+		metrics.AddPublished(context.Background(), MessageSystemTypeKafka, "topic", msg, nil)
+		if metrics.processDuration == nil || metrics.publishedMessages == nil {
+			t.Error("expected the instruments to be created")
+		}
+	})
+}
@@ -119,6 +119,9 @@ func makeAttributesFromMessage(msg *message.Message) []OtelAttribute {
 // ctx using the global text map propagator and returns it as a map of header
 // keys to values. Useful for attaching trace headers to outgoing messages.
 func GetTraceContextPropagatorByContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return map[string]string{}
+	}
 	carrier := propagation.HeaderCarrier{}
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, &carrier)
@@ -149,3 +152,26 @@ func GetTraceContextPropagatorByTraceParent(
 	propagator := otel.GetTextMapPropagator()
 	return propagator.Extract(ctx, &carrier)
 }
+
+// GetTraceContextPropagatorByHeaders extracts the trace context carried by
+// the headers of a received message, e.g. traceparent, tracestate and
+// baggage, using the global text map propagator. Header keys are matched
+// case-insensitively.
+//
+// Parameters:
+//   - ctx: the base context
+//   - headers: the received message headers
+//
+// Returns:
+//   - context.Context: context with extracted trace information
+func GetTraceContextPropagatorByHeaders(
+	ctx context.Context,
+	headers map[string]string,
+) context.Context {
+	carrier := propagation.HeaderCarrier{}
+	for key, value := range headers {
+		carrier.Set(key, value)
+	}
+	propagator := otel.GetTextMapPropagator()
+	return propagator.Extract(ctx, &carrier)
+}
@@ -2,9 +2,13 @@ package otel
 
 import (
     "context"
+    "strings"
     "testing"
 
     "github.com/jeffersonbrasilino/gomes/message"
+    otelGlobal "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/propagation"
+    traceTypes "go.opentelemetry.io/otel/trace"
 )
 
 func TestOtelHelpers(t *testing.T) {
@@ -76,3 +80,38 @@ func TestOtelHelpers(t *testing.T) {
         }
     })
 }
+
+func TestGetTraceContextPropagatorByHeaders(t *testing.T) {
+	otelGlobal.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otelGlobal.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	traceId, _ := traceTypes.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanId, _ := traceTypes.SpanIDFromHex("00f067aa0ba902b7")
+	spanContext := traceTypes.NewSpanContext(traceTypes.SpanContextConfig{
+		TraceID:    traceId,
+		SpanID:     spanId,
+		TraceFlags: traceTypes.FlagsSampled,
+	})
+	ctx := traceTypes.ContextWithSpanContext(context.Background(), spanContext)
+
+	headers := GetTraceContextPropagatorByContext(ctx)
+	headers["route"] = "orders.created"
+
+	// brokers may lowercase the header keys
+	received := map[string]string{}
+	for key, value := range headers {
+		received[strings.ToLower(key)] = value
+	}
+
+	extracted := traceTypes.SpanContextFromContext(
+		GetTraceContextPropagatorByHeaders(context.Background(), received),
+	)
+	if extracted.TraceID() != traceId || extracted.SpanID() != spanId {
+		t.Fatalf("expected the propagated span context, got %v", extracted)
+	}
+	if GetTraceContextPropagatorByContext(nil) == nil {
+		t.Fatal("expected an empty map for a nil context")
+	}
+}
@@ -99,6 +99,13 @@ func EnableTrace() {
 	traceEnabled = true
 }
 
+// IsTraceEnabled reports whether tracing is enabled for the message system.
+func IsTraceEnabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return traceEnabled
+}
+
 // otelTrace implements the OtelTrace interface for creating and managing traces
 type otelTrace struct {
 	tracer traceTypes.Tracer
@@ -0,0 +1,143 @@
+package gomes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jeffersonbrasilino/gomes/container"
+	"github.com/jeffersonbrasilino/gomes/message"
+	"github.com/jeffersonbrasilino/gomes/message/adapter"
+	"github.com/jeffersonbrasilino/gomes/message/endpoint"
+)
+
+// runtimeConsumer is a consumer channel added after Start.
+type runtimeConsumer struct {
+	builderName string
+	cancel      context.CancelFunc
+}
+
+// runtimeConsumers holds the consumer channels added after Start, by consumer
+// name.
+var runtimeConsumers = container.NewGenericContainer[string, runtimeConsumer]()
+
+// AddConsumerChannelRuntime registers, builds and starts a consumer channel
+// after Start has run, enabling dynamic subscriptions without restarting the
+// process. The channel is consumed by an event-driven consumer running until
+// RemoveConsumerChannel or Shutdown is called.
+//
+// Parameters:
+//   - inboundChannel: the consumer channel builder
+//
+// Returns:
+//   - error: error if the message system is not started, the channel already
+//     exists or cannot be built
+func AddConsumerChannelRuntime(
+	inboundChannel BuildableComponent[*adapter.InboundChannelAdapter],
+) error {
+	if !activeEndpoints.Has(defaultCommandChannelName) {
+		return fmt.Errorf("[consumer-channel] %w", message.ErrNotStarted)
+	}
+	if err := AddConsumerChannel(inboundChannel); err != nil {
+		return err
+	}
+
+	builderName := inboundChannel.ReferenceName()
+	adapterChannel, err := inboundChannel.Build(gomesContainer)
+	if err != nil {
+		inboundChannelBuilders.Remove(builderName)
+		return fmt.Errorf("[consumer-channel] %w", err)
+	}
+	consumerName := adapterChannel.ReferenceName()
+	if err := setComponent(gomesContainer, endpoint.ConsumerChannelKey(consumerName), adapterChannel); err != nil {
+		inboundChannelBuilders.Remove(builderName)
+		adapterChannel.Close()
+		return fmt.Errorf(
+			"[consumer-channel] consumer for channel %s %w",
+			consumerName,
+			message.ErrDuplicateRegistration,
+		)
+	}
+
+	consumer, err := EventDrivenConsumer(consumerName)
+	if err != nil {
+		gomesContainer.Remove(endpoint.ConsumerChannelKey(consumerName))
+		inboundChannelBuilders.Remove(builderName)
+		adapterChannel.Close()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runtimeConsumers.Set(consumerName, runtimeConsumer{
+		builderName: builderName,
+		cancel:      cancel,
+	})
+	go runRuntimeConsumer(ctx, consumerName, consumer)
+	slog.Info("[message-system] consumer channel added", "name", consumerName)
+	return nil
+}
+
+// RemoveConsumerChannel stops the consumer of a consumer channel and disposes
+// the channel after Start has run, closing its broker subscription.
+//
+// Parameters:
+//   - consumerName: the reference name of the consumer channel
+//
+// Returns:
+//   - error: error if the channel is not found
+func RemoveConsumerChannel(consumerName string) error {
+	anyChannel, err := gomesContainer.Get(endpoint.ConsumerChannelKey(consumerName))
+	if err != nil {
+		return fmt.Errorf(
+			"[consumer-channel] consumer %w: %s",
+			message.ErrChannelNotFound,
+			consumerName,
+		)
+	}
+	consumerChannel, ok := anyChannel.(endpoint.InboundChannelAdapter)
+	if !ok {
+		return fmt.Errorf(
+			"[consumer-channel] %s is not a consumer channel",
+			consumerName,
+		)
+	}
+
+	if activeEndpoint, err := activeEndpoints.Get(consumerName); err == nil {
+		if consumer, ok := activeEndpoint.(*endpoint.EventDrivenConsumer); ok {
+			consumer.Stop()
+		}
+		activeEndpoints.Remove(consumerName)
+	}
+
+	builderName := consumerName
+	if runtime, err := runtimeConsumers.Get(consumerName); err == nil {
+		runtime.cancel()
+		builderName = runtime.builderName
+		runtimeConsumers.Remove(consumerName)
+	}
+
+	gomesContainer.Remove(endpoint.ConsumerChannelKey(consumerName))
+	inboundChannelBuilders.Remove(builderName)
+	if err := consumerChannel.Close(); err != nil {
+		return fmt.Errorf("[consumer-channel] failed to close %s: %w", consumerName, err)
+	}
+	slog.Info("[message-system] consumer channel removed", "name", consumerName)
+	return nil
+}
+
+// runRuntimeConsumer runs a consumer added after Start until its context is
+// cancelled.
+func runRuntimeConsumer(
+	ctx context.Context,
+	consumerName string,
+	consumer *endpoint.EventDrivenConsumer,
+) {
+	err := consumer.Run(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		slog.Error("[message-system] consumer stopped with error",
+			"name", consumerName,
+			"error", err,
+		)
+	}
+}
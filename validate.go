@@ -0,0 +1,248 @@
+package gomes
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ValidationIssue describes a problem found in the registered topology.
+type ValidationIssue struct {
+	// Component is the kind of the component, e.g. "consumer-channel".
+	Component string
+	// Name is the reference name of the component.
+	Name string
+	// Problem describes the issue.
+	Problem string
+}
+
+// String returns the string representation of the issue.
+//
+// Returns:
+//   - string: the issue description
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", i.Component, i.Name, i.Problem)
+}
+
+// ValidationReport is the result of Validate.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+// Valid reports whether no issue was found.
+//
+// Returns:
+//   - bool: true if the topology is valid
+func (r *ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns the issues joined in a single error.
+//
+// Returns:
+//   - error: the issues, nil if the topology is valid
+func (r *ValidationReport) Err() error {
+	errs := make([]error, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		errs = append(errs, errors.New(issue.String()))
+	}
+	return errors.Join(errs...)
+}
+
+// add records an issue.
+func (r *ValidationReport) add(component, name, problem string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Component: component,
+		Name:      name,
+		Problem:   fmt.Sprintf(problem, args...),
+	})
+}
+
+// connectionReferencer is implemented by channel builders using a broker
+// connection.
+type connectionReferencer interface {
+	ConnectionReferenceName() string
+}
+
+// replyChannelReferencer is implemented by publisher channel builders
+// configured with a reply channel.
+type replyChannelReferencer interface {
+	ReplyChannelName(value string) string
+}
+
+// failureChannelsReferencer is implemented by consumer channel builders
+// routing failed messages to publisher channels.
+type failureChannelsReferencer interface {
+	DeadLetterChannelName() string
+	UnroutableChannelName() string
+	QuarantineChannelName() string
+}
+
+// deadLetterRoutesReferencer is implemented by consumer channel builders
+// sending the failed messages of some routes to their own dead letter
+// channels.
+type deadLetterRoutesReferencer interface {
+	DeadLetterRoutes() map[string]string
+}
+
+// discardChannelReferencer is implemented by consumer channel builders
+// sending the messages dropped by their filter to a publisher channel.
+type discardChannelReferencer interface {
+	DiscardChannelName() string
+}
+
+// responseChannelReferencer is implemented by consumer channel builders
+// publishing the handler results to a response channel.
+type responseChannelReferencer interface {
+	ResponseChannelName() string
+}
+
+// wireTapReferencer is implemented by channel builders copying their
+// messages to a wire tap channel.
+type wireTapReferencer interface {
+	WireTapChannelName() string
+}
+
+// publishFallbackReferencer is implemented by publisher channel builders
+// sending the messages they fail to publish to a fallback channel.
+type publishFallbackReferencer interface {
+	PublishFallbackChannelName() string
+}
+
+// Validate checks the registered components without connecting to any broker,
+// reporting channels referencing missing connections, dead letter (including
+// the ones per route), unroutable, quarantine, discard, response, wire tap and
+// publish fallback channels without a registered publisher, reply channels
+// which are not registered and handlers whose action or event names collide
+// with channel names. It should be called before Start.
+//
+// Returns:
+//   - *ValidationReport: the issues found in the topology
+func Validate() *ValidationReport {
+	report := &ValidationReport{}
+	publishers := outboundChannelBuilders.GetAll()
+	consumers := inboundChannelBuilders.GetAll()
+
+	for _, name := range slices.Sorted(maps.Keys(publishers)) {
+		publisher := publishers[name]
+		validateConnection(report, "publisher-channel", name, publisher)
+		validateWireTap(report, "publisher-channel", name, publisher, publishers)
+		if referencer, ok := publisher.(publishFallbackReferencer); ok {
+			channelName := referencer.PublishFallbackChannelName()
+			if _, ok := publishers[channelName]; channelName != "" && !ok {
+				report.add("publisher-channel", name,
+					"publish fallback channel %s has no publisher channel registered", channelName)
+			}
+		}
+		referencer, ok := publisher.(replyChannelReferencer)
+		if !ok {
+			continue
+		}
+		replyChannel := referencer.ReplyChannelName("")
+		_, isConsumer := consumers[replyChannel]
+		_, isPublisher := publishers[replyChannel]
+		if replyChannel != "" && !isConsumer && !isPublisher {
+			report.add("publisher-channel", name,
+				"reply channel %s is not registered", replyChannel)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(consumers)) {
+		consumer := consumers[name]
+		validateConnection(report, "consumer-channel", name, consumer)
+		validateWireTap(report, "consumer-channel", name, consumer, publishers)
+		if referencer, ok := consumer.(discardChannelReferencer); ok {
+			channelName := referencer.DiscardChannelName()
+			if _, ok := publishers[channelName]; channelName != "" && !ok {
+				report.add("consumer-channel", name,
+					"discard channel %s has no publisher channel registered", channelName)
+			}
+		}
+		if referencer, ok := consumer.(deadLetterRoutesReferencer); ok {
+			routes := referencer.DeadLetterRoutes()
+			for _, route := range slices.Sorted(maps.Keys(routes)) {
+				if _, ok := publishers[routes[route]]; !ok {
+					report.add("consumer-channel", name,
+						"dead letter channel %s of route %s has no publisher channel registered",
+						routes[route], route)
+				}
+			}
+		}
+		if referencer, ok := consumer.(responseChannelReferencer); ok {
+			channelName := referencer.ResponseChannelName()
+			if _, ok := publishers[channelName]; channelName != "" && !ok {
+				report.add("consumer-channel", name,
+					"response channel %s has no publisher channel registered", channelName)
+			}
+		}
+		referencer, ok := consumer.(failureChannelsReferencer)
+		if !ok {
+			continue
+		}
+		for kind, channelName := range map[string]string{
+			"dead letter": referencer.DeadLetterChannelName(),
+			"unroutable":  referencer.UnroutableChannelName(),
+			"quarantine":  referencer.QuarantineChannelName(),
+		} {
+			if _, ok := publishers[channelName]; channelName != "" && !ok {
+				report.add("consumer-channel", name,
+					"%s channel %s has no publisher channel registered", kind, channelName)
+			}
+		}
+	}
+
+	handlerNames := append(
+		slices.Sorted(maps.Keys(actionHandlers.GetAll())),
+		slices.Sorted(maps.Keys(eventSubscribers.GetAll()))...,
+	)
+	for _, name := range handlerNames {
+		_, isConsumer := consumers[name]
+		_, isPublisher := publishers[name]
+		if isConsumer || isPublisher {
+			report.add("handler", name, "name collides with a channel name")
+		}
+	}
+
+	slices.SortStableFunc(report.Issues, func(a, b ValidationIssue) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return report
+}
+
+// validateConnection reports a channel referencing a missing connection.
+func validateConnection(
+	report *ValidationReport,
+	component string,
+	name string,
+	builder any,
+) {
+	referencer, ok := builder.(connectionReferencer)
+	if !ok {
+		return
+	}
+	if connection := referencer.ConnectionReferenceName(); !channelConnections.Has(connection) {
+		report.add(component, name, "connection %s is not registered", connection)
+	}
+}
+
+// validateWireTap reports a channel whose wire tap channel has no registered
+// publisher.
+func validateWireTap[T any](
+	report *ValidationReport,
+	component string,
+	name string,
+	builder any,
+	publishers map[string]T,
+) {
+	referencer, ok := builder.(wireTapReferencer)
+	if !ok {
+		return
+	}
+	channelName := referencer.WireTapChannelName()
+	if _, ok := publishers[channelName]; channelName != "" && !ok {
+		report.add(component, name,
+			"wire tap channel %s has no publisher channel registered", channelName)
+	}
+}